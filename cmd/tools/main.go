@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/fgeck/tools/internal/app"
 	"github.com/fgeck/tools/internal/cli"
 	"github.com/fgeck/tools/internal/config"
-	"github.com/fgeck/tools/internal/repository/yaml"
-	"github.com/fgeck/tools/internal/service"
+	"github.com/fgeck/tools/internal/i18n"
 )
 
+// Set at build time via -ldflags
+var version = "dev"
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -19,19 +23,38 @@ func main() {
 
 func run() error {
 	// Load configuration
-	cfg := config.DefaultConfig()
-
-	// Initialize repository
-	repo, err := yaml.NewYAMLBookmarkRepository(cfg.StorageFilePath)
+	cfg, err := config.Load()
 	if err != nil {
-		return fmt.Errorf("failed to initialize repository: %w", err)
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	i18n.SetLanguage(i18n.Detect(cfg.Language))
 
-	// Initialize service
-	svc := service.NewBookmarkService(repo)
+	// Open the store and service, logging as configured by the log flags
+	logger := cli.Logger()
+	a, err := app.Open(cfg, app.Options{Logger: logger, OnChange: cli.StoreChanged})
+	if err != nil {
+		return err
+	}
+	for _, recovery := range a.Recoveries {
+		fmt.Fprintln(os.Stderr, i18n.T("Warning: %s was damaged (%v). Restored %d bookmarks from the backup of %s; the damaged file was kept as %s.",
+			recovery.Path, recovery.Cause, recovery.Bookmarks, recovery.BackupTime.Local().Format("2006-01-02 15:04"), recovery.DamagedPath))
+	}
+	if a.Metrics != nil {
+		cli.SetStoreMetrics(a.Metrics)
+		cli.OnShutdown(func() error {
+			a.Metrics.Log(context.Background(), logger)
+			return nil
+		})
+	}
+	// Pending auto-export writes are finished before exiting
+	if a.AutoExport != nil {
+		cli.OnShutdown(a.AutoExport.Flush)
+		cli.SetAutoExportStatus(a.AutoExport.Status)
+	}
 
 	// Initialize and execute CLI
-	cli.Initialize(svc)
+	cli.Version = version
+	cli.Initialize(a.Service, cfg)
 	cli.Execute()
 
 	return nil
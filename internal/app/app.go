@@ -0,0 +1,176 @@
+// Package app wires the bookmark service from the configuration, the same way for the tools
+// binary and for programs embedding the store through pkg/bookmarks
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/export"
+	"github.com/fgeck/tools/internal/hooks"
+	"github.com/fgeck/tools/internal/logging"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/deadline"
+	"github.com/fgeck/tools/internal/repository/layered"
+	"github.com/fgeck/tools/internal/repository/logged"
+	"github.com/fgeck/tools/internal/repository/metered"
+	"github.com/fgeck/tools/internal/repository/registry"
+	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/fgeck/tools/internal/secrets"
+	"github.com/fgeck/tools/internal/service"
+)
+
+// Options are what the caller adds to the configured wiring
+type Options struct {
+	Logger     *slog.Logger // Logs the store operations and the changes of the service, nil for none
+	OnChange   func()       // Optional, called after every change to the store
+	SyncExport bool         // Write the auto-export on every change instead of after its delay, for callers that cannot flush it before exiting
+}
+
+// App is the service opened by Open and what its caller runs around it
+type App struct {
+	Service    service.BookmarkService
+	Recoveries []*yaml.Recovery // Store files found damaged and restored from their backup
+	Metrics    *metered.Metrics // Operation counts of the store, nil unless the metrics decorator is configured
+	AutoExport *export.Mirror   // The configured auto-export, nil if none; flush it before exiting
+}
+
+// Open opens the store, decorators, layers and service described by cfg
+// Like every start of tools, it restores the backup of a store file left damaged by a crash.
+func Open(cfg *config.Config, opts Options) (*App, error) {
+	app := &App{}
+	logger := opts.Logger
+	if logger == nil {
+		logger = logging.Discard()
+	}
+
+	// Restore the backup of a store left damaged, e.g. by a crash while it was written
+	recoveries, err := yaml.RecoverStore(cfg.StorageFilePath)
+	if err != nil {
+		return nil, err
+	}
+	app.Recoveries = recoveries
+
+	// Open the store, logging its operations
+	repo, err := yaml.OpenStore(cfg.StorageFilePath)
+	if errors.Is(err, fs.ErrPermission) {
+		// Typical for a container volume owned by another user than the one tools runs as
+		return nil, fmt.Errorf("failed to initialize repository: %w; the directory of %s must be writable by uid %d, chown it or set TOOLS_CONFIG_DIR",
+			err, cfg.StorageFilePath, os.Getuid())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	repo = logged.NewLoggedBookmarkRepository(repo, logger, cfg.StorageFilePath)
+
+	// Wrap the store in the configured decorators, e.g. a cache or a read-only guard
+	metrics := metered.NewMetrics()
+	decorators, err := registry.Decorators(cfg.Storage.Decorators, registry.DecoratorOptions{
+		CacheTTL: cfg.Storage.CacheTTL,
+		Metrics:  metrics,
+		AuditLog: audit.NewFileLog(cfg.Storage.AuditFile),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage configuration: %w", err)
+	}
+	repo = repository.Decorate(repo, decorators...)
+	if slices.Contains(cfg.Storage.Decorators, "metrics") {
+		app.Metrics = metrics
+	}
+
+	// Merge the stores the user's store includes and the system layer, read-only; writes
+	// keep going to the user's store. Later includes override earlier ones, so come first.
+	includes, err := yaml.Includes(cfg.StorageFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read includes: %w", err)
+	}
+	var layers []layered.Layer
+	for _, path := range slices.Backward(includes) {
+		layers = append(layers, layered.Layer{
+			Name: filepath.Base(path),
+			Repo: logged.NewLoggedBookmarkRepository(yaml.NewReadOnlyYAMLBookmarkRepository(path), logger, path),
+		})
+	}
+	if cfg.SystemFilePath != "" {
+		layers = append(layers, layered.Layer{
+			Name: "system",
+			Repo: logged.NewLoggedBookmarkRepository(yaml.NewReadOnlyYAMLBookmarkRepository(cfg.SystemFilePath), logger, cfg.SystemFilePath),
+		})
+	}
+	if len(layers) > 0 {
+		repo = layered.NewLayeredBookmarkRepository(repo, layers...)
+	}
+
+	// Abort store operations that hang, e.g. on an unresponsive network mount, so the TUI
+	// reports an error instead of freezing
+	if cfg.Storage.Timeout > 0 {
+		repo = deadline.NewDeadlineBookmarkRepository(repo, cfg.Storage.Timeout)
+	}
+
+	// Initialize service with history and revision recording
+	svcOpts := []service.Option{
+		service.WithAuditLog(audit.NewFileLog(cfg.HistoryFilePath)),
+		service.WithMaxRevisions(cfg.MaxRevisions),
+		service.WithLogger(logger),
+	}
+	if opts.OnChange != nil {
+		svcOpts = append(svcOpts, service.WithOnChange(opts.OnChange))
+	}
+	if cfg.Hooks.Enabled() {
+		svcOpts = append(svcOpts, service.WithHooks(hooks.FromConfig(cfg.Hooks)))
+	}
+	if cfg.Review.Enabled {
+		queue, err := yaml.NewYAMLBookmarkRepository(cfg.Review.PendingFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize review queue: %w", err)
+		}
+		svcOpts = append(svcOpts, service.WithReviewQueue(logged.NewLoggedBookmarkRepository(queue, logger, cfg.Review.PendingFile)))
+	}
+	if author := cfg.Team.AuthorName(); author != "" {
+		svcOpts = append(svcOpts, service.WithAuthor(author))
+	}
+
+	// Keep the configured export in sync
+	if cfg.AutoExport.Enabled() {
+		format := export.Format(cfg.AutoExport.Format)
+		if !export.Supported(format) {
+			return nil, fmt.Errorf("invalid auto_export format '%s'", cfg.AutoExport.Format)
+		}
+		redactor, err := secrets.NewRedactor(cfg.Redaction.Secrets, cfg.Redaction.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		mirror := export.NewMirror(cfg.AutoExport.Path, format, cfg.AutoExport.Delay, func(ctx context.Context) ([]dto.BookmarkResponse, error) {
+			resp, err := app.Service.ListBookmarks(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// The auto-export is often synced or shared, keep private bookmarks and redacted
+			// values out of it
+			return redactor.Bookmarks(dto.Shared(resp.Examples)), nil
+		})
+		changed := mirror.Changed
+		if opts.SyncExport {
+			changed = func() {
+				mirror.Changed()
+				if err := mirror.Flush(); err != nil {
+					logger.Error("auto-export failed", "path", cfg.AutoExport.Path, "error", err)
+				}
+			}
+		}
+		svcOpts = append(svcOpts, service.WithOnChange(changed))
+		app.AutoExport = mirror
+	}
+
+	app.Service = service.NewBookmarkService(repo, svcOpts...)
+	return app, nil
+}
@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// Action identifies the kind of change recorded in the audit log
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionEdit   Action = "edit"
+	ActionDelete Action = "delete"
+	ActionRun    Action = "run"
+	ActionSearch Action = "search"
+	ActionResult Action = "result"
+)
+
+// Event is a single entry of the audit log
+// Before holds the bookmark prior to the change (edit, delete), After the result (create, edit).
+// Searches record their query instead of a command, executed commands their Result.
+type Event struct {
+	Time    time.Time        `json:"time"`
+	Action  Action           `json:"action"`
+	Command string           `json:"command"`
+	Query   string           `json:"query,omitempty"`
+	Before  *models.Bookmark `json:"before,omitempty"`
+	After   *models.Bookmark `json:"after,omitempty"`
+	Result  *Result          `json:"result,omitempty"`
+}
+
+// Result is the outcome of an executed command
+type Result struct {
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Log defines an append-only event log
+type Log interface {
+	// Append adds an event to the end of the log
+	Append(event Event) error
+
+	// Events returns all recorded events in chronological order
+	Events() ([]Event, error)
+}
+
+// FileLog implements Log as a JSON-lines file
+type FileLog struct {
+	filePath string
+	mu       sync.Mutex
+}
+
+// NewFileLog creates a file-backed audit log; the file is created on first append
+func NewFileLog(filePath string) *FileLog {
+	return &FileLog{filePath: filePath}
+}
+
+// Append writes the event as a single JSON line
+func (l *FileLog) Append(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
+
+// Events reads all events from the log file
+// A missing file is treated as an empty log
+func (l *FileLog) Events() ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.filePath)
+	if os.IsNotExist(err) {
+		return []Event{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to parse history file at line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return events, nil
+}
+
+// Follow calls fn with each event appended to the log file, checking it for new lines every
+// interval until ctx is done. With fromStart the events already recorded are passed first.
+// A log that shrank was replaced, it is then read again from the beginning.
+func (l *FileLog) Follow(ctx context.Context, interval time.Duration, fromStart bool, fn func(Event) error) error {
+	var offset int64
+	if !fromStart {
+		info, err := os.Stat(l.filePath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to open history file: %w", err)
+		}
+		if err == nil {
+			offset = info.Size()
+		}
+	}
+
+	for {
+		var err error
+		if offset, err = l.readFrom(offset, fn); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// readFrom passes the events of the complete lines after offset to fn and returns the offset
+// after the last of them; a line still being written is left for the next read
+func (l *FileLog) readFrom(offset int64, fn func(Event) error) (int64, error) {
+	f, err := os.Open(l.filePath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return offset, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return offset, fmt.Errorf("failed to read history file: %w", err)
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, fmt.Errorf("failed to read history file: %w", err)
+		}
+		offset += int64(len(data))
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return offset, fmt.Errorf("failed to parse history file: %w", err)
+		}
+		if err := fn(event); err != nil {
+			return offset, err
+		}
+	}
+}
@@ -0,0 +1,170 @@
+//go:build unit
+// +build unit
+
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestFileLogAppendAndEvents(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	log := NewFileLog(filePath)
+
+	before := &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "old"}
+	after := &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "new"}
+
+	events := []Event{
+		{Time: time.Now(), Action: ActionCreate, Command: before.Command, After: before},
+		{Time: time.Now(), Action: ActionEdit, Command: after.Command, Before: before, After: after},
+		{Time: time.Now(), Action: ActionRun, Command: after.Command},
+	}
+	for _, event := range events {
+		if err := log.Append(event); err != nil {
+			t.Fatalf("Failed to append event: %v", err)
+		}
+	}
+
+	read, err := log.Events()
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+
+	if len(read) != len(events) {
+		t.Fatalf("Expected %d events, got %d", len(events), len(read))
+	}
+
+	if read[1].Action != ActionEdit || read[1].Before.Description != "old" || read[1].After.Description != "new" {
+		t.Errorf("Edit event not preserved: %+v", read[1])
+	}
+
+	if read[2].Before != nil || read[2].After != nil {
+		t.Error("Run event should not carry bookmark snapshots")
+	}
+}
+
+func TestFileLogMissingFile(t *testing.T) {
+	log := NewFileLog(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	events, err := log.Events()
+	if err != nil {
+		t.Fatalf("Missing file should not be an error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected no events, got %d", len(events))
+	}
+}
+
+func TestFileLogIsAppendOnly(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	first := NewFileLog(filePath)
+	_ = first.Append(Event{Time: time.Now(), Action: ActionCreate, Command: "ls"})
+
+	// A second log instance on the same file must not truncate existing entries
+	second := NewFileLog(filePath)
+	_ = second.Append(Event{Time: time.Now(), Action: ActionDelete, Command: "ls"})
+
+	events, err := second.Events()
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected 2 events, got %d", len(events))
+	}
+}
+
+func TestFileLogCorruptLine(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(filePath, []byte("not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileLog(filePath).Events(); err == nil {
+		t.Error("Expected parse error for corrupt history file")
+	}
+}
+
+func TestFileLogFollow(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	log := NewFileLog(filePath)
+	_ = log.Append(Event{Time: time.Now(), Action: ActionCreate, Command: "old"})
+
+	for _, fromStart := range []bool{true, false} {
+		ctx, cancel := context.WithCancel(context.Background())
+		received := make(chan Event, 10)
+		done := make(chan error)
+		go func() {
+			done <- log.Follow(ctx, 10*time.Millisecond, fromStart, func(event Event) error {
+				received <- event
+				return nil
+			})
+		}()
+
+		if fromStart {
+			if event := <-received; event.Command != "old" {
+				t.Errorf("Expected the recorded event first, got %+v", event)
+			}
+		}
+
+		// Give Follow the time to reach the end before appending
+		time.Sleep(50 * time.Millisecond)
+		_ = log.Append(Event{Time: time.Now(), Action: ActionEdit, Command: "new"})
+
+		select {
+		case event := <-received:
+			if event.Command != "new" || event.Action != ActionEdit {
+				t.Errorf("Expected the appended event, got %+v", event)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Follow did not pass the appended event")
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Follow failed: %v", err)
+		}
+		if len(received) != 0 {
+			t.Errorf("Expected no further events, got %+v", <-received)
+		}
+	}
+}
+
+func TestFileLogFollowPartialLine(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	log := NewFileLog(filePath)
+
+	var events []Event
+	collect := func(event Event) error {
+		events = append(events, event)
+		return nil
+	}
+
+	if err := os.WriteFile(filePath, []byte(`{"action":"run","command":"ls"}`+"\n"+`{"action":"run",`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	offset, err := log.readFrom(0, collect)
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected the line being written to wait, got %d events", len(events))
+	}
+
+	f, _ := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`"command":"pwd"}` + "\n")
+	_ = f.Close()
+
+	if _, err := log.readFrom(offset, collect); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	if len(events) != 2 || events[1].Command != "pwd" {
+		t.Errorf("Expected the completed line, got %+v", events)
+	}
+}
@@ -0,0 +1,118 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/dto"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatVersion is the version of the bundle file layout
+const FormatVersion = 1
+
+// maxDownloadSize caps the size of bundles fetched over HTTP
+const maxDownloadSize = 10 << 20
+
+// Metadata describes a bundle
+type Metadata struct {
+	Name        string    `yaml:"name"`
+	Description string    `yaml:"description,omitempty"`
+	Author      string    `yaml:"author,omitempty"`
+	Version     string    `yaml:"version"`
+	CreatedAt   time.Time `yaml:"created_at"`
+}
+
+// Bundle is a shareable, self-describing set of bookmarks
+type Bundle struct {
+	Format    int                         `yaml:"format"`
+	Metadata  Metadata                    `yaml:"bundle"`
+	Bookmarks []dto.CreateBookmarkRequest `yaml:"bookmarks"`
+}
+
+// Marshal encodes a bundle as YAML
+func Marshal(b *Bundle) ([]byte, error) {
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	return data, nil
+}
+
+// Unmarshal decodes and validates a YAML bundle
+func Unmarshal(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	if b.Format > FormatVersion {
+		return nil, fmt.Errorf("bundle format %d is newer than supported format %d, please upgrade tools", b.Format, FormatVersion)
+	}
+	if strings.TrimSpace(b.Metadata.Name) == "" {
+		return nil, fmt.Errorf("invalid bundle: missing name")
+	}
+
+	return &b, nil
+}
+
+// WriteFile writes a bundle to the given path
+func WriteFile(path string, b *Bundle) error {
+	data, err := Marshal(b)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// Load reads a bundle from a local file or an http(s) URL
+func Load(ctx context.Context, source string) (*Bundle, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return fetch(ctx, source)
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	return Unmarshal(data)
+}
+
+// fetch downloads a bundle over HTTP
+func fetch(ctx context.Context, url string) (*Bundle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle URL: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download bundle: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download bundle: %w", err)
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("bundle exceeds maximum size of %d bytes", maxDownloadSize)
+	}
+
+	return Unmarshal(data)
+}
@@ -0,0 +1,124 @@
+//go:build unit
+// +build unit
+
+package bundle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+func testBundle() *Bundle {
+	return &Bundle{
+		Format: FormatVersion,
+		Metadata: Metadata{
+			Name:        "k8s",
+			Description: "kubernetes essentials",
+			Author:      "alice",
+			Version:     "1.2.0",
+			CreatedAt:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Bookmarks: []dto.CreateBookmarkRequest{
+			{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "list all pods", Tags: []string{"k8s"}},
+		},
+	}
+}
+
+func TestWriteAndLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "k8s.bundle.yaml")
+	if err := WriteFile(path, testBundle()); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+
+	b, err := Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Failed to load bundle: %v", err)
+	}
+
+	if b.Metadata.Name != "k8s" || b.Metadata.Author != "alice" || b.Metadata.Version != "1.2.0" {
+		t.Errorf("Metadata not preserved: %+v", b.Metadata)
+	}
+	if len(b.Bookmarks) != 1 || b.Bookmarks[0].Tags[0] != "k8s" {
+		t.Errorf("Bookmarks not preserved: %+v", b.Bookmarks)
+	}
+}
+
+func TestLoadFromURL(t *testing.T) {
+	data, _ := Marshal(testBundle())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/k8s.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	b, err := Load(context.Background(), server.URL+"/k8s.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load bundle from URL: %v", err)
+	}
+	if b.Metadata.Name != "k8s" {
+		t.Errorf("Unexpected bundle name %q", b.Metadata.Name)
+	}
+
+	if _, err := Load(context.Background(), server.URL+"/missing.yaml"); err == nil {
+		t.Error("Expected error for HTTP 404")
+	}
+}
+
+func TestUnmarshalValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"invalid yaml", "bundle: [unclosed"},
+		{"missing name", "format: 1\nbundle:\n  version: 1.0.0\nbookmarks: []\n"},
+		{"newer format", "format: 99\nbundle:\n  name: x\nbookmarks: []\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal([]byte(tt.data)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestStarters(t *testing.T) {
+	packs, err := Starters()
+	if err != nil {
+		t.Fatalf("Failed to read starter packs: %v", err)
+	}
+
+	var names []string
+	for _, b := range packs {
+		names = append(names, b.Metadata.Name)
+		if b.Metadata.Description == "" || len(b.Bookmarks) == 0 {
+			t.Errorf("Starter pack %q needs a description and bookmarks", b.Metadata.Name)
+		}
+		seen := map[string]bool{}
+		for _, bookmark := range b.Bookmarks {
+			if bookmark.Command == "" || bookmark.ToolName == "" || bookmark.Description == "" {
+				t.Errorf("Incomplete bookmark in starter pack %q: %+v", b.Metadata.Name, bookmark)
+			}
+			if seen[bookmark.Command] {
+				t.Errorf("Duplicate command %q in starter pack %q", bookmark.Command, b.Metadata.Name)
+			}
+			seen[bookmark.Command] = true
+		}
+	}
+
+	want := []string{"docker", "git", "kubectl", "networking"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected starter packs %v, got %v", want, names)
+	}
+}
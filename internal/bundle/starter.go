@@ -0,0 +1,34 @@
+package bundle
+
+import (
+	"embed"
+	"fmt"
+	"path"
+)
+
+// starterFS holds the starter packs offered to new users
+//
+//go:embed starter/*.yaml
+var starterFS embed.FS
+
+// Starters returns the built-in starter packs, ordered by name
+func Starters() ([]*Bundle, error) {
+	entries, err := starterFS.ReadDir("starter")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read starter packs: %w", err)
+	}
+
+	packs := make([]*Bundle, 0, len(entries))
+	for _, entry := range entries {
+		data, err := starterFS.ReadFile(path.Join("starter", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read starter pack: %w", err)
+		}
+		b, err := Unmarshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("starter pack %s: %w", entry.Name(), err)
+		}
+		packs = append(packs, b)
+	}
+	return packs, nil
+}
@@ -0,0 +1,324 @@
+// Package canonical rewrites commands of known tools into one spelling, so the same command
+// typed differently is stored, deduplicated and found as one bookmark
+package canonical
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fgeck/tools/internal/utils"
+)
+
+// safeWord matches words that need no quoting in a shell
+var safeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// assignment matches a leading VAR=value environment assignment
+var assignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// placeholder matches the <name> and {name} placeholders of bookmarks, which stay as written
+var placeholder = regexp.MustCompile(`^(<[A-Za-z0-9_.:-]+>|\{[A-Za-z0-9_.:-]+\})$`)
+
+// Command returns the canonical form of a single invocation of a known tool:
+//   - options are spelled out long, -n kube-system becomes --namespace=kube-system
+//   - grouped short options are split, -it becomes --stdin --tty
+//   - options are sorted by name, after the operands or, for commands like docker run whose
+//     operands start a command of their own, before them
+//   - quotes are removed where not needed and are single quotes elsewhere
+//
+// Other commands are returned as they are, as are commands whose meaning could change:
+// pipelines, redirections, substitutions, scripts and options missing from the known tools.
+func Command(command string) string {
+	if canonical, ok := rewrite(command); ok {
+		return canonical
+	}
+	return command
+}
+
+// Known reports whether commands of the tool are canonicalized
+func Known(toolName string) bool {
+	_, ok := tools[toolName]
+	return ok
+}
+
+// word is a shell word of a command
+type word struct {
+	raw   string // As written
+	value string // After removing quotes and escapes
+
+	// literal is false when the word expands, e.g. $HOME, *.go or "$NS": then only its raw
+	// form keeps the meaning
+	literal bool
+}
+
+// render returns the word in canonical quoting, or as written if it expands
+func (w word) render() string {
+	if !w.literal {
+		return w.raw
+	}
+	return utils.ShellQuote(w.value)
+}
+
+// renderFrom renders the word from a byte offset of its value, e.g. the value of --name=value;
+// ok is false if the offset cannot be mapped to the raw form of an expanding word
+func (w word) renderFrom(offset int) (string, bool) {
+	if w.literal {
+		return utils.ShellQuote(w.value[offset:]), true
+	}
+	if w.raw == w.value {
+		return w.raw[offset:], true
+	}
+	return "", false
+}
+
+// split splits a single-line command into words; ok is false for anything but a simple
+// command: operators, redirections, substitutions, comments or unterminated quotes
+func split(command string) (words []word, ok bool) {
+	var raw, value strings.Builder
+	var quoteChar rune
+	escaped, inWord, literal := false, false, true
+
+	flush := func() bool {
+		if inWord {
+			w := word{raw: raw.String(), value: value.String(), literal: literal}
+			if !w.literal && !placeholder.MatchString(w.raw) && strings.ContainsAny(w.raw, "<>{}") {
+				return false
+			}
+			words = append(words, w)
+		}
+		raw.Reset()
+		value.Reset()
+		inWord, literal = false, true
+		return true
+	}
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			escaped = false
+			value.WriteRune(r)
+		case quoteChar == '\'':
+			if r == '\'' {
+				quoteChar = 0
+			} else {
+				value.WriteRune(r)
+			}
+		case quoteChar == '"':
+			switch r {
+			case '"':
+				quoteChar = 0
+			case '$', '`', '\\':
+				return nil, false
+			default:
+				value.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case r == '\'' || r == '"':
+			quoteChar = r
+		case r == ' ' || r == '\t':
+			if !flush() {
+				return nil, false
+			}
+			continue
+		case strings.ContainsRune("|&;()`\n\r", r), r == '#' && !inWord:
+			return nil, false
+		default:
+			if !safeWord.MatchString(string(r)) {
+				literal = false
+			}
+			value.WriteRune(r)
+		}
+		raw.WriteRune(r)
+		inWord = true
+	}
+	if quoteChar != 0 || escaped || !flush() {
+		return nil, false
+	}
+	return words, true
+}
+
+// option is a parsed option of a command
+type option struct {
+	name string // Long name, the sort key
+	text string // Canonical spelling with its value
+}
+
+// rewrite returns the canonical form of a command; ok is false if it has none
+func rewrite(command string) (string, bool) {
+	words, ok := split(command)
+	if !ok {
+		return "", false
+	}
+
+	i := 0
+	for i < len(words) && assignment.MatchString(words[i].raw) {
+		i++
+	}
+	if i == len(words) {
+		return "", false
+	}
+	prefix, executable, args := words[:i], words[i], words[i+1:]
+	spec, known := tools[filepath.Base(executable.value)]
+	if !known || !executable.literal {
+		return "", false
+	}
+
+	subName, ok := findSubcommand(spec.flags, args)
+	if !ok {
+		return "", false
+	}
+	sub, knownSub := spec.subcommands[subName]
+	if !knownSub {
+		// Unknown subcommands take the shared options only and operands anywhere
+		sub = subcommand{interspersed: true}
+	}
+	flags := append(append([]flag{}, spec.flags...), sub.flags...)
+
+	var operands, tail []word
+	var options []option
+	dashdash := false
+	for j := 0; j < len(args); j++ {
+		arg := args[j]
+		v := arg.value
+		switch {
+		case arg.literal && v == "--":
+			dashdash, tail = true, args[j+1:]
+			j = len(args)
+		case arg.literal && strings.HasPrefix(v, "--"):
+			name, _, hasValue := strings.Cut(v[2:], "=")
+			f, found := lookupLong(flags, name)
+			if !found {
+				return "", false
+			}
+			text := "--" + f.long
+			switch {
+			case hasValue:
+				rendered, ok := arg.renderFrom(len(name) + 3)
+				if !ok {
+					return "", false
+				}
+				text += "=" + rendered
+			case f.value:
+				if j+1 == len(args) {
+					return "", false
+				}
+				j++
+				text += "=" + args[j].render()
+			}
+			options = append(options, option{f.long, text})
+		case arg.literal && len(v) > 1 && v[0] == '-':
+			for k := 1; k < len(v); k++ {
+				f, found := lookupShort(flags, v[k])
+				if !found {
+					return "", false
+				}
+				if !f.value {
+					options = append(options, option{f.long, "--" + f.long})
+					continue
+				}
+				var rendered string
+				if k+1 < len(v) {
+					if rendered, ok = arg.renderFrom(k + 1); !ok {
+						return "", false
+					}
+				} else if j+1 < len(args) {
+					j++
+					rendered = args[j].render()
+				} else {
+					return "", false
+				}
+				options = append(options, option{f.long, "--" + f.long + "=" + rendered})
+				break
+			}
+		case strings.HasPrefix(arg.raw, "-") && !arg.literal:
+			return "", false // An option that expands, like -n$NS
+		case !sub.interspersed && len(operands) > 0:
+			// The operand after the subcommand starts a command of its own, e.g. the image
+			tail = args[j:]
+			j = len(args)
+		default:
+			operands = append(operands, arg)
+		}
+	}
+
+	sort.SliceStable(options, func(a, b int) bool {
+		return options[a].name < options[b].name
+	})
+
+	parts := make([]string, 0, len(words)+1)
+	for _, w := range prefix {
+		parts = append(parts, w.render())
+	}
+	parts = append(parts, executable.raw)
+	renderAll := func(ws []word) {
+		for _, w := range ws {
+			parts = append(parts, w.render())
+		}
+	}
+	renderAll(operands)
+	for _, o := range options {
+		parts = append(parts, o.text)
+	}
+	if dashdash {
+		parts = append(parts, "--")
+	}
+	renderAll(tail)
+	return strings.Join(parts, " "), true
+}
+
+// findSubcommand returns the first operand, skipping the shared options before it and their
+// values; ok is false for an option that is not shared
+func findSubcommand(shared []flag, args []word) (string, bool) {
+	for j := 0; j < len(args); j++ {
+		v := args[j].value
+		switch {
+		case v == "--":
+			return "", true
+		case strings.HasPrefix(v, "--"):
+			name, _, hasValue := strings.Cut(v[2:], "=")
+			f, found := lookupLong(shared, name)
+			if !found {
+				return "", false
+			}
+			if f.value && !hasValue {
+				j++
+			}
+		case len(v) > 1 && v[0] == '-':
+			for k := 1; k < len(v); k++ {
+				f, found := lookupShort(shared, v[k])
+				if !found {
+					return "", false
+				}
+				if f.value {
+					if k+1 == len(v) {
+						j++
+					}
+					break
+				}
+			}
+		default:
+			return v, true
+		}
+	}
+	return "", true
+}
+
+func lookupLong(flags []flag, name string) (flag, bool) {
+	for _, f := range flags {
+		if f.long == name {
+			return f, true
+		}
+	}
+	return flag{}, false
+}
+
+func lookupShort(flags []flag, short byte) (flag, bool) {
+	for _, f := range flags {
+		if f.short != 0 && f.short == short {
+			return f, true
+		}
+	}
+	return flag{}, false
+}
@@ -0,0 +1,73 @@
+//go:build unit
+// +build unit
+
+package canonical
+
+import "testing"
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		// Options spelled out long, with their values, sorted after the operands
+		{"kubectl get pods -n kube-system -o wide", "kubectl get pods --namespace=kube-system --output=wide"},
+		{"kubectl -o wide get pods --namespace kube-system", "kubectl get pods --namespace=kube-system --output=wide"},
+		{"kubectl get pods -A -owide", "kubectl get pods --all-namespaces --output=wide"},
+		{"kubectl logs -f deploy/api --tail=100", "kubectl logs deploy/api --follow --tail=100"},
+		{"kubectl apply -f deploy.yaml --dry-run=client", "kubectl apply --dry-run=client --filename=deploy.yaml"},
+		{"kubectl exec -it api-0 -c app -- sh -c 'echo hi'", "kubectl exec api-0 --container=app --stdin --tty -- sh -c 'echo hi'"},
+		{"KUBECONFIG=~/.kube/dev kubectl get pods -n dev", "KUBECONFIG=~/.kube/dev kubectl get pods --namespace=dev"},
+		{"kubectl config get-contexts", "kubectl config get-contexts"},
+
+		// Quotes only where needed, single quotes for the rest
+		{`kubectl get pods -l "app=api"`, "kubectl get pods --selector=app=api"},
+		{`kubectl get pods -l "app in (api, web)"`, "kubectl get pods --selector='app in (api, web)'"},
+		{`docker ps --format "{{.Names}}"`, "docker ps --format='{{.Names}}'"},
+		{`docker ps --format='{{.Names}}\t{{.Status}}' -a`, `docker ps --all --format='{{.Names}}\t{{.Status}}'`},
+
+		// Options after the image belong to the command run in the container
+		{"docker run -it --rm -p 8080:80 nginx nginx -g 'daemon off;'", "docker run --interactive --publish=8080:80 --rm --tty nginx nginx -g 'daemon off;'"},
+		{"docker run --rm -e A=1 -e B=2 alpine env", "docker run --env=A=1 --env=B=2 --rm alpine env"},
+
+		// Expanding words keep their spelling and stay where their option goes
+		{"kubectl get pods -n $NS", "kubectl get pods --namespace=$NS"},
+		{"kubectl logs <pod> -n <namespace>", "kubectl logs <pod> --namespace=<namespace>"},
+		{"kubectl get pods -n {namespace}", "kubectl get pods --namespace={namespace}"},
+
+		// Left as written
+		{"kubectl get pods -n dev | grep api", "kubectl get pods -n dev | grep api"},
+		{"kubectl get pods > pods.txt", "kubectl get pods > pods.txt"},
+		{"kubectl delete pod $(kubectl get pods -o name)", "kubectl delete pod $(kubectl get pods -o name)"},
+		{`kubectl get pods -n "$NS"`, `kubectl get pods -n "$NS"`},
+		{"kubectl get pods --unknown-flag", "kubectl get pods --unknown-flag"},
+		{"kubectl get pods -n", "kubectl get pods -n"},
+		{"kubectl logs {pod:kubectl get pods -o name}", "kubectl logs {pod:kubectl get pods -o name}"},
+		{"docker -H tcp://host ps", "docker -H tcp://host ps"},
+		{"sudo kubectl get pods -n dev", "sudo kubectl get pods -n dev"},
+		{"git log --oneline -n 5", "git log --oneline -n 5"},
+		{"kubectl get pods 'unterminated", "kubectl get pods 'unterminated"},
+		{"kubectl apply -f -\nkubectl get pods", "kubectl apply -f -\nkubectl get pods"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := Command(tt.command); got != tt.expected {
+				t.Errorf("Command(%q) = %q, want %q", tt.command, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommandIsStable(t *testing.T) {
+	for _, command := range []string{
+		"kubectl -n dev logs -f api-0 -c app --since 1h",
+		"docker run -it --rm -v $PWD:/src -w /src golang:1.25 go test ./...",
+		`docker ps -a --format "{{.ID}} {{.Image}}"`,
+	} {
+		once := Command(command)
+		if twice := Command(once); twice != once {
+			t.Errorf("Expected the canonical form to stay, got %q then %q", once, twice)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package canonical
+
+// flag is an option of a known tool
+type flag struct {
+	long  string // Name without the dashes, the canonical spelling
+	short byte   // Single-letter alias, 0 for none
+	value bool   // Takes a value, as --long=value, --long value, -s value or -svalue
+}
+
+// subcommand lists the options of one subcommand of a known tool
+type subcommand struct {
+	flags []flag
+
+	// interspersed is false for subcommands whose first operand starts a command of its own,
+	// like the image of docker run: options after it belong to that command
+	interspersed bool
+}
+
+// tool describes the options of a known tool, shared and per subcommand
+// Commands using an option missing here are left as written, so an entry only needs
+// the options worth canonicalizing.
+type tool struct {
+	flags       []flag // Options of every subcommand, also accepted before the subcommand
+	subcommands map[string]subcommand
+}
+
+var kubectlResourceFlags = []flag{
+	{long: "filename", short: 'f', value: true},
+	{long: "recursive", short: 'R'},
+	{long: "kustomize", short: 'k', value: true},
+	{long: "dry-run"}, // Its value is only taken as --dry-run=client, like a boolean
+}
+
+// tools are the known tools by executable name
+var tools = map[string]tool{
+	"kubectl": {
+		flags: []flag{
+			{long: "namespace", short: 'n', value: true},
+			{long: "all-namespaces", short: 'A'},
+			{long: "context", value: true},
+			{long: "kubeconfig", value: true},
+			{long: "cluster", value: true},
+			{long: "output", short: 'o', value: true},
+			{long: "selector", short: 'l', value: true},
+			{long: "field-selector", value: true},
+			{long: "sort-by", value: true},
+			{long: "label-columns", short: 'L', value: true},
+			{long: "show-labels"},
+			{long: "no-headers"},
+			{long: "watch", short: 'w'},
+			{long: "request-timeout", value: true},
+		},
+		subcommands: map[string]subcommand{
+			"get":      {interspersed: true, flags: kubectlResourceFlags},
+			"describe": {interspersed: true, flags: kubectlResourceFlags},
+			"apply":    {interspersed: true, flags: append([]flag{{long: "server-side"}, {long: "prune"}}, kubectlResourceFlags...)},
+			"create":   {interspersed: true, flags: kubectlResourceFlags},
+			"replace":  {interspersed: true, flags: append([]flag{{long: "force"}}, kubectlResourceFlags...)},
+			"diff":     {interspersed: true, flags: kubectlResourceFlags},
+			"delete":   {interspersed: true, flags: append([]flag{{long: "force"}, {long: "all"}, {long: "grace-period", value: true}}, kubectlResourceFlags...)},
+			"logs": {interspersed: true, flags: []flag{
+				{long: "follow", short: 'f'},
+				{long: "previous", short: 'p'},
+				{long: "container", short: 'c', value: true},
+				{long: "all-containers"},
+				{long: "tail", value: true},
+				{long: "since", value: true},
+				{long: "timestamps"},
+				{long: "prefix"},
+			}},
+			"exec": {interspersed: true, flags: []flag{
+				{long: "stdin", short: 'i'},
+				{long: "tty", short: 't'},
+				{long: "container", short: 'c', value: true},
+			}},
+			"port-forward": {interspersed: true, flags: []flag{{long: "address", value: true}}},
+			"scale":        {interspersed: true, flags: []flag{{long: "replicas", value: true}}},
+			"rollout":      {interspersed: true},
+			"top":          {interspersed: true},
+		},
+	},
+	"docker": {
+		subcommands: map[string]subcommand{
+			"run": {flags: []flag{
+				{long: "interactive", short: 'i'},
+				{long: "tty", short: 't'},
+				{long: "detach", short: 'd'},
+				{long: "rm"},
+				{long: "name", value: true},
+				{long: "publish", short: 'p', value: true},
+				{long: "publish-all", short: 'P'},
+				{long: "volume", short: 'v', value: true},
+				{long: "mount", value: true},
+				{long: "env", short: 'e', value: true},
+				{long: "env-file", value: true},
+				{long: "workdir", short: 'w', value: true},
+				{long: "user", short: 'u', value: true},
+				{long: "network", value: true},
+				{long: "entrypoint", value: true},
+				{long: "platform", value: true},
+				{long: "privileged"},
+				{long: "hostname", short: 'h', value: true},
+				{long: "label", short: 'l', value: true},
+				{long: "restart", value: true},
+				{long: "memory", short: 'm', value: true},
+				{long: "cpus", value: true},
+				{long: "gpus", value: true},
+				{long: "init"},
+				{long: "read-only"},
+				{long: "pull", value: true},
+			}},
+			"exec": {flags: []flag{
+				{long: "interactive", short: 'i'},
+				{long: "tty", short: 't'},
+				{long: "detach", short: 'd'},
+				{long: "env", short: 'e', value: true},
+				{long: "user", short: 'u', value: true},
+				{long: "workdir", short: 'w', value: true},
+				{long: "privileged"},
+			}},
+			"ps": {interspersed: true, flags: []flag{
+				{long: "all", short: 'a'},
+				{long: "quiet", short: 'q'},
+				{long: "filter", short: 'f', value: true},
+				{long: "format", value: true},
+				{long: "no-trunc"},
+				{long: "latest", short: 'l'},
+				{long: "size", short: 's'},
+				{long: "last", short: 'n', value: true},
+			}},
+			"images": {interspersed: true, flags: []flag{
+				{long: "all", short: 'a'},
+				{long: "quiet", short: 'q'},
+				{long: "filter", short: 'f', value: true},
+				{long: "format", value: true},
+				{long: "digests"},
+				{long: "no-trunc"},
+			}},
+			"logs": {interspersed: true, flags: []flag{
+				{long: "follow", short: 'f'},
+				{long: "tail", short: 'n', value: true},
+				{long: "since", value: true},
+				{long: "until", value: true},
+				{long: "timestamps", short: 't'},
+				{long: "details"},
+			}},
+			"build": {interspersed: true, flags: []flag{
+				{long: "tag", short: 't', value: true},
+				{long: "file", short: 'f', value: true},
+				{long: "build-arg", value: true},
+				{long: "target", value: true},
+				{long: "platform", value: true},
+				{long: "progress", value: true},
+				{long: "no-cache"},
+				{long: "pull"},
+				{long: "quiet", short: 'q'},
+			}},
+		},
+	},
+}
@@ -0,0 +1,215 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a single command captured by the shell hook
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Dir     string    `json:"dir,omitempty"` // Working directory the command ran in
+}
+
+// Candidate is a distinct captured command, aggregated over all its entries
+type Candidate struct {
+	Command  string
+	Count    int
+	LastUsed time.Time
+	Dir      string // Working directory of the latest run
+}
+
+// Staging is the JSON-lines file captured commands are collected in until they are triaged
+type Staging struct {
+	filePath string
+	mu       sync.Mutex
+}
+
+// NewStaging creates a file-backed staging area; the file is created on first append
+func NewStaging(filePath string) *Staging {
+	return &Staging{filePath: filePath}
+}
+
+// Append adds a captured command
+func (s *Staging) Append(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal captured command: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create capture directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write capture file: %w", err)
+	}
+
+	return nil
+}
+
+// Entries reads all captured commands in the order they ran
+// A missing file is treated as an empty staging area; malformed lines are skipped,
+// as concurrent shells may have interleaved their writes
+func (s *Staging) Entries() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// Candidates returns the distinct captured commands, most recently used first
+func (s *Staging) Candidates() ([]Candidate, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	byCommand := map[string]*Candidate{}
+	for _, entry := range entries {
+		c, ok := byCommand[entry.Command]
+		if !ok {
+			c = &Candidate{Command: entry.Command}
+			byCommand[entry.Command] = c
+		}
+		c.Count++
+		if !entry.Time.Before(c.LastUsed) {
+			c.LastUsed = entry.Time
+			c.Dir = entry.Dir
+		}
+	}
+
+	candidates := make([]Candidate, 0, len(byCommand))
+	for _, c := range byCommand {
+		candidates = append(candidates, *c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].LastUsed.After(candidates[j].LastUsed)
+	})
+	return candidates, nil
+}
+
+// Remove drops all entries of the given commands, e.g. after they have been triaged
+func (s *Staging) Remove(commands []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	drop := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		drop[command] = true
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		if drop[entry.Command] {
+			continue
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal captured command: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(s.filePath, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write capture file: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all captured commands
+func (s *Staging) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear capture file: %w", err)
+	}
+	return nil
+}
+
+func (s *Staging) load() ([]Entry, error) {
+	f, err := os.Open(s.filePath)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Command == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read capture file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Hook returns the shell snippet that records every executed command with "tools capture record"
+// Supported shells are bash and zsh.
+func Hook(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashHook, nil
+	case "zsh":
+		return zshHook, nil
+	default:
+		return "", fmt.Errorf("unsupported shell '%s' (use bash or zsh)", shell)
+	}
+}
+
+const bashHook = `# tools capture hook for bash
+__tools_capture() {
+  local cmd
+  cmd=$(HISTTIMEFORMAT= builtin history 1 | sed -e 's/^ *[0-9]*[* ] *//')
+  if [ -n "$cmd" ] && [ "$cmd" != "$__tools_capture_last" ]; then
+    __tools_capture_last=$cmd
+    (command tools capture record -- "$cmd" >/dev/null 2>&1 &)
+  fi
+}
+case ";${PROMPT_COMMAND:-};" in
+  *";__tools_capture;"*) ;;
+  *) PROMPT_COMMAND="__tools_capture${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`
+
+const zshHook = `# tools capture hook for zsh
+__tools_capture() {
+  (command tools capture record -- "$1" >/dev/null 2>&1 &)
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook preexec __tools_capture
+`
@@ -0,0 +1,109 @@
+//go:build unit
+// +build unit
+
+package capture
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStagingCandidates(t *testing.T) {
+	staging := NewStaging(filepath.Join(t.TempDir(), "captured.jsonl"))
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Time: base, Command: "git status", Dir: "/a"},
+		{Time: base.Add(time.Minute), Command: "kubectl get pods", Dir: "/b"},
+		{Time: base.Add(2 * time.Minute), Command: "git status", Dir: "/c"},
+	}
+	for _, e := range entries {
+		if err := staging.Append(e); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+
+	candidates, err := staging.Candidates()
+	if err != nil {
+		t.Fatalf("Failed to read candidates: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 distinct commands, got %d", len(candidates))
+	}
+
+	first := candidates[0]
+	if first.Command != "git status" || first.Count != 2 || first.Dir != "/c" || !first.LastUsed.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("Unexpected most recent candidate: %+v", first)
+	}
+}
+
+func TestStagingRemoveAndClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "captured.jsonl")
+	staging := NewStaging(path)
+
+	for _, command := range []string{"ls", "pwd", "ls"} {
+		_ = staging.Append(Entry{Time: time.Now(), Command: command})
+	}
+
+	if err := staging.Remove([]string{"ls"}); err != nil {
+		t.Fatalf("Failed to remove: %v", err)
+	}
+	entries, _ := staging.Entries()
+	if len(entries) != 1 || entries[0].Command != "pwd" {
+		t.Errorf("Expected only pwd to remain, got %+v", entries)
+	}
+
+	if err := staging.Clear(); err != nil {
+		t.Fatalf("Failed to clear: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected capture file to be removed")
+	}
+	if err := staging.Clear(); err != nil {
+		t.Errorf("Clearing an empty staging area should not fail: %v", err)
+	}
+}
+
+func TestStagingSkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "captured.jsonl")
+	content := `{"time":"2025-01-01T00:00:00Z","command":"ls"}
+{"time":"2025-01-01T00:00:01Z","comm
+{"time":"2025-01-01T00:00:02Z","command":"pwd"}
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := NewStaging(path).Entries()
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 valid entries, got %d", len(entries))
+	}
+}
+
+func TestHook(t *testing.T) {
+	if _, err := Hook("fish"); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+
+	for _, shell := range []string{"bash", "zsh"} {
+		hook, err := Hook(shell)
+		if err != nil {
+			t.Fatalf("Failed to get %s hook: %v", shell, err)
+		}
+
+		// Check the snippet parses when the shell is available
+		path, err := exec.LookPath(shell)
+		if err != nil {
+			continue
+		}
+		if out, err := exec.Command(path, "-n", "-c", hook).CombinedOutput(); err != nil {
+			t.Errorf("%s hook has syntax errors: %v\n%s", shell, err, out)
+		}
+	}
+}
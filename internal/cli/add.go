@@ -3,8 +3,17 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/fgeck/tools/internal/canonical"
+	"github.com/fgeck/tools/internal/domain/models"
 	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/parse"
+	"github.com/fgeck/tools/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -12,43 +21,301 @@ var (
 	addToolName   string
 	addDesc       string
 	addExampleCmd string
+	addTags       []string
+	addFromFile   string
+	addLink       string
+	addRemind     string
+	addHost       string
+	addVariants   []string
+	addRequires   []string
+	addPrivate    bool
+	addStdin      bool
+	addNoDefaults bool
+	addCanonical  bool
+	addSecrets    bool
 )
 
 func newAddCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "add",
+		Use:     "add [description -- command]",
 		Aliases: []string{"a"},
 		Short:   "Add a new example bookmark",
 		Long: `Add a new example to the bookmark manager.
 
 Each example requires:
-- Tool name: For grouping (e.g., "lsof")
+- Tool name: For grouping (e.g., "lsof"); inferred from the command when -n is not set
 - Description: What it does (e.g., "list all ports at port 54321")
-- Command: The actual command (e.g., "lsof -i :54321")`,
+- Command: The actual command (e.g., "lsof -i :54321")
+
+Optionally label it with tags (e.g., --tags k8s,prod) and link documentation,
+a runbook or a ticket with --link. Personal one-offs marked --private are left
+out of exports, bundles and the auto-export.
+
+Defaults configured for the tool under tool_defaults apply unless
+--no-defaults is set: their tags are added to --tags and their description
+template is used when -d is not set.
+
+Commands of known tools like kubectl and docker can be stored in their
+canonical form, with long options sorted by name and quotes only where needed.
+With canonical_commands in the configuration the canonical form is shown for
+approval, --canonical stores it without asking.
+
+Commands containing likely credentials, such as AWS access keys, tokens or
+passwords in URLs, are refused so they don't leak into synced or shared
+stores. Read them from an environment variable instead, e.g. $GITHUB_TOKEN,
+or pass --allow-secrets to store the command anyway.
+
+Multi-line commands such as small scripts or heredocs can be read from a file
+with --from-file instead of -c. With --stdin the command is read from standard
+input, e.g. piped from another program:
+
+  echo 'kubectl get pods -A' | tools add -n kubectl -d "list pods" --stdin
+
+Quick add: pass the description as argument and the command after --. The tool
+name is taken from the command's executable unless -n is set:
+
+  tools add "list pods" -- kubectl get pods -A
+
+A single argument after -- is used verbatim, so quote commands with pipes or
+redirections: tools add "biggest files" -- 'du -sh * | sort -h'`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			req := dto.CreateBookmarkRequest{
-				Command:     addExampleCmd,
-				ToolName:    addToolName,
-				Description: addDesc,
+			var req dto.CreateBookmarkRequest
+			var err error
+			if len(args) > 0 {
+				req, err = quickAddRequest(cmd, args)
+			} else {
+				req, err = flagAddRequest(cmd)
 			}
+			if err != nil {
+				return err
+			}
+			if req.Command, err = canonicalCommand(cmd, req.Command); err != nil {
+				return err
+			}
+			req.AllowSecrets = addSecrets
 
 			resp, err := svc.CreateBookmark(context.Background(), req)
 			if err != nil {
 				return fmt.Errorf("failed to add example: %w", err)
 			}
 
-			fmt.Printf("Successfully added command: %s for tool: %s\n", resp.Command, resp.ToolName)
+			printAdded(resp)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&addToolName, "name", "n", "", "Tool name for grouping (required)")
+	cmd.Flags().StringVarP(&addToolName, "name", "n", "", "Tool name for grouping (inferred from the command if not set)")
 	cmd.Flags().StringVarP(&addDesc, "description", "d", "", "Description - what it does (required)")
-	cmd.Flags().StringVarP(&addExampleCmd, "command", "c", "", "The actual command to execute (required)")
+	cmd.Flags().StringVarP(&addExampleCmd, "command", "c", "", "The actual command to execute (required unless --from-file is set)")
+	cmd.Flags().StringSliceVar(&addTags, "tags", nil, "Comma-separated tags (e.g., k8s,prod)")
+	cmd.Flags().StringVarP(&addFromFile, "from-file", "f", "", "Read a multi-line command from a file (- for stdin)")
+	cmd.Flags().StringVar(&addLink, "link", "", "URL of documentation, a runbook or a ticket")
+	cmd.Flags().StringVar(&addRemind, "remind-every", "", "Remind to run the command after this period, e.g. 30d, 2w or 12h (see 'tools due')")
+	cmd.Flags().StringVar(&addHost, "host", "", "SSH host the command runs on with 'tools run', e.g. prod-db")
+	cmd.Flags().StringArrayVar(&addVariants, "variant", nil, "Command for another OS as os=command, e.g. darwin=pbcopy (repeatable)")
+	cmd.Flags().StringSliceVar(&addRequires, "requires", nil, "Comma-separated environment variables and executables the command needs, e.g. AWS_PROFILE,aws")
+	cmd.Flags().BoolVar(&addPrivate, "private", false, "Keep the bookmark out of exports, bundles and the auto-export")
+
+	cmd.Flags().BoolVar(&addStdin, "stdin", false, "Read the command from standard input (same as --from-file -)")
+	cmd.Flags().BoolVar(&addNoDefaults, "no-defaults", false, "Ignore the tags and description template configured for the tool")
+	cmd.Flags().BoolVar(&addCanonical, "canonical", false, "Store the command of a known tool in its canonical form without asking")
+	cmd.Flags().BoolVar(&addSecrets, "allow-secrets", false, "Store the command even if it contains likely credentials")
 
-	_ = cmd.MarkFlagRequired("name")
-	_ = cmd.MarkFlagRequired("description")
-	_ = cmd.MarkFlagRequired("command")
+	cmd.MarkFlagsMutuallyExclusive("command", "from-file", "stdin")
 
 	return cmd
 }
+
+// flagAddRequest builds the bookmark of the flag form: add -n <tool> -d <description> -c <command>
+func flagAddRequest(cmd *cobra.Command) (dto.CreateBookmarkRequest, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("command") && !flags.Changed("from-file") && !addStdin {
+		return dto.CreateBookmarkRequest{}, fmt.Errorf("%w: one of the flags --command, --from-file or --stdin is required, or use: tools add <description> -- <command>", models.ErrValidation)
+	}
+
+	command := addExampleCmd
+	if addStdin {
+		addFromFile = "-"
+	}
+	if flags.Changed("from-file") || addStdin {
+		script, err := readScript(cmd.InOrStdin(), addFromFile)
+		if err != nil {
+			return dto.CreateBookmarkRequest{}, err
+		}
+		command = script
+	}
+
+	toolName, err := addRequestToolName(flags.Changed("name"), command)
+	if err != nil {
+		return dto.CreateBookmarkRequest{}, err
+	}
+	variants, err := parseVariants(addVariants)
+	if err != nil {
+		return dto.CreateBookmarkRequest{}, err
+	}
+
+	req := withToolDefaults(dto.CreateBookmarkRequest{
+		Command:     command,
+		ToolName:    toolName,
+		Description: addDesc,
+		Tags:        addTags,
+		Link:        addLink,
+		RemindEvery: addRemind,
+		Host:        addHost,
+		Variants:    variants,
+		Requires:    addRequires,
+		Private:     addPrivate,
+	})
+	if req.Description == "" && !flags.Changed("description") {
+		return dto.CreateBookmarkRequest{}, fmt.Errorf("%w: required flag(s) \"description\" not set", models.ErrValidation)
+	}
+	return req, nil
+}
+
+// quickAddRequest builds the bookmark of the quick form: add <description> -- <command>
+func quickAddRequest(cmd *cobra.Command, args []string) (dto.CreateBookmarkRequest, error) {
+	flags := cmd.Flags()
+	if cmd.ArgsLenAtDash() != 1 || len(args) < 2 {
+		return dto.CreateBookmarkRequest{}, fmt.Errorf("%w: quick add takes one description and the command after --, e.g. tools add \"list pods\" -- kubectl get pods", models.ErrValidation)
+	}
+	if flags.Changed("description") || flags.Changed("command") || flags.Changed("from-file") || addStdin {
+		return dto.CreateBookmarkRequest{}, fmt.Errorf("%w: quick add cannot be combined with --description, --command, --from-file or --stdin", models.ErrValidation)
+	}
+
+	command := shellJoin(args[1:])
+	toolName, err := addRequestToolName(flags.Changed("name"), command)
+	if err != nil {
+		return dto.CreateBookmarkRequest{}, err
+	}
+	variants, err := parseVariants(addVariants)
+	if err != nil {
+		return dto.CreateBookmarkRequest{}, err
+	}
+
+	return withToolDefaults(dto.CreateBookmarkRequest{
+		Command:     command,
+		ToolName:    toolName,
+		Description: args[0],
+		Tags:        addTags,
+		Link:        addLink,
+		RemindEvery: addRemind,
+		Host:        addHost,
+		Variants:    variants,
+		Requires:    addRequires,
+		Private:     addPrivate,
+	}), nil
+}
+
+// addRequestToolName returns the tool name given with -n, or infers it from the command
+func addRequestToolName(nameSet bool, command string) (string, error) {
+	if nameSet {
+		return addToolName, nil
+	}
+	toolName := parse.ToolName(command)
+	if toolName == "" {
+		return "", fmt.Errorf("%w: cannot infer the tool name from the command, set it with --name (-n)", models.ErrValidation)
+	}
+	info("Using tool name '%s' inferred from the command (set --name to override)\n", toolName)
+	return toolName, nil
+}
+
+// canonicalCommand returns the command to store: its canonical form with --canonical, or with
+// canonical_commands if the user approves it, else the command as given
+func canonicalCommand(cmd *cobra.Command, command string) (string, error) {
+	if !addCanonical && !cfg.CanonicalCommands {
+		return command, nil
+	}
+	canonicalForm := canonical.Command(command)
+	if strings.Join(strings.Fields(canonicalForm), " ") == strings.Join(strings.Fields(command), " ") {
+		return command, nil // Whitespace is normalized when storing anyway
+	}
+	if addCanonical {
+		info("Using the canonical form: %s\n", canonicalForm)
+		return canonicalForm, nil
+	}
+
+	ok, err := confirm(cmd.InOrStdin(), i18n.T("Canonical form: %s\nStore it instead?", canonicalForm))
+	if err != nil {
+		return "", fmt.Errorf("failed to add example: %w", err)
+	}
+	if ok {
+		return canonicalForm, nil
+	}
+	return command, nil
+}
+
+// withToolDefaults adds the default tags of the bookmark's tool and fills an empty description
+// with its template, unless --no-defaults is set
+func withToolDefaults(req dto.CreateBookmarkRequest) dto.CreateBookmarkRequest {
+	if addNoDefaults {
+		return req
+	}
+	defaults := cfg.DefaultsFor(req.ToolName)
+	if req.Description == "" {
+		req.Description = defaults.RenderDescription(req.ToolName, req.Command)
+	}
+	req.Tags = mergeTags(req.Tags, defaults.Tags)
+	return req
+}
+
+// mergeTags appends the extra tags that are not in tags yet, ignoring case
+func mergeTags(tags, extra []string) []string {
+	for _, tag := range extra {
+		if !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseVariants parses os=command pairs of --variant flags
+// An empty command is kept, so that editing with it removes the variant.
+func parseVariants(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	variants := map[string]string{}
+	for _, value := range values {
+		goos, command, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(goos) == "" {
+			return nil, fmt.Errorf("%w: invalid variant '%s', use os=command, e.g. darwin=pbcopy", models.ErrValidation, value)
+		}
+		variants[strings.ToLower(strings.TrimSpace(goos))] = command
+	}
+	return variants, nil
+}
+
+// shellJoin turns arguments back into a command line
+// A single argument is the whole command and is kept verbatim; otherwise arguments the
+// shell would split or expand are single-quoted, so the command runs as typed.
+func shellJoin(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	return utils.ShellJoin(args)
+}
+
+// readScript reads a command from a file, or from in when path is "-"
+func readScript(in io.Reader, path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read command: %w", err)
+	}
+	return string(data), nil
+}
+
+// printAdded confirms a new bookmark, or its submission when review is enabled
+func printAdded(resp *dto.BookmarkResponse) {
+	if resp.Pending {
+		info("Submitted command: %s for tool: %s, it is listed once approved with 'tools review approve'\n", resp.Command, resp.ToolName)
+		return
+	}
+	info("Successfully added command: %s for tool: %s\n", resp.Command, resp.ToolName)
+}
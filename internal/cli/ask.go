@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var askLimit int
+
+func newAskCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ask <question>",
+		Short: "Find bookmarks by asking in plain language",
+		Long: `Rank stored bookmarks by relevance to a natural-language question and show
+the best matches, so you don't need to remember exact keywords.
+
+Matching uses local keyword scoring (BM25) with stemming and common synonyms
+(e.g. "kill" also finds "stop", "folder" also finds "directory"). Nothing
+leaves your machine.
+
+Example:
+  tools ask "how do I kill the process on port 8080"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			question := strings.Join(args, " ")
+
+			resp, err := svc.RankBookmarks(context.Background(), question, askLimit)
+			if err != nil {
+				return fmt.Errorf("failed to search bookmarks: %w", err)
+			}
+
+			if resp.Count == 0 {
+				info("No matching bookmarks found.\n")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "SCORE\tTOOL\tDESCRIPTION\tCOMMAND")
+			_, _ = fmt.Fprintln(w, "-----\t----\t-----------\t-------")
+			for _, result := range resp.Results {
+				_, _ = fmt.Fprintf(w, "%.2f\t%s\t%s\t%s\n", result.Score, result.ToolName, result.Description, result.Command)
+			}
+			_ = w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&askLimit, "limit", "l", 5, "Maximum number of matches to show (0 for all)")
+
+	return cmd
+}
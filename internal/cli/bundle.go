@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/bundle"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleTags        []string
+	bundleTool        string
+	bundleDescription string
+	bundleAuthor      string
+	bundleVersion     string
+	bundleOutput      string
+	bundleOnConflict  string
+	bundlePrivate     bool
+	bundleSecrets     bool
+)
+
+func newBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Create and install shareable bookmark bundles",
+		Long: `Bundles are self-describing YAML files with a curated set of bookmarks and
+metadata (name, author, description, version). Share them with your team via
+git, chat or any web server - no registry required.`,
+	}
+
+	cmd.AddCommand(newBundleCreateCmd())
+	cmd.AddCommand(newBundleInstallCmd())
+
+	return cmd
+}
+
+func newBundleCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a bundle from your bookmarks",
+		Long: `Create a bundle file from bookmarks selected by tag and/or tool name.
+
+Bookmarks carrying any of the given --tag values are included; --tool further
+restricts the selection to one tool. Without filters, all bookmarks are bundled.
+Private bookmarks are left out unless --include-private is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			resp, err := svc.ListBookmarks(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to list examples: %w", err)
+			}
+
+			b := &bundle.Bundle{
+				Format: bundle.FormatVersion,
+				Metadata: bundle.Metadata{
+					Name:        name,
+					Description: bundleDescription,
+					Author:      bundleAuthor,
+					Version:     bundleVersion,
+					CreatedAt:   time.Now().UTC(),
+				},
+				Bookmarks: []dto.CreateBookmarkRequest{},
+			}
+			examples := resp.Examples
+			if !bundlePrivate {
+				examples = dto.Shared(examples)
+			}
+			for _, example := range examples {
+				if !matchesBundleFilter(example) {
+					continue
+				}
+				b.Bookmarks = append(b.Bookmarks, dto.CreateBookmarkRequest{
+					Command:     example.Command,
+					ToolName:    example.ToolName,
+					Description: example.Description,
+					Tags:        example.Tags,
+					Link:        example.Link,
+					Variants:    example.Variants,
+					Requires:    example.Requires,
+				})
+			}
+
+			if len(b.Bookmarks) == 0 {
+				return fmt.Errorf("%w: no bookmarks match the given filters", models.ErrNotFound)
+			}
+
+			output := bundleOutput
+			if output == "" {
+				output = name + ".bundle.yaml"
+			}
+			if err := bundle.WriteFile(output, b); err != nil {
+				return err
+			}
+
+			info("Successfully created bundle '%s' with %d bookmarks: %s\n", name, len(b.Bookmarks), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&bundleTags, "tag", nil, "Include bookmarks with this tag (repeatable)")
+	cmd.Flags().StringVar(&bundleTool, "tool", "", "Only include bookmarks of this tool")
+	cmd.Flags().BoolVar(&bundlePrivate, "include-private", false, "Also include private bookmarks")
+	cmd.Flags().StringVarP(&bundleDescription, "description", "d", "", "Bundle description")
+	cmd.Flags().StringVar(&bundleAuthor, "author", os.Getenv("USER"), "Bundle author")
+	cmd.Flags().StringVar(&bundleVersion, "version", "1.0.0", "Bundle version")
+	cmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "Output file (default <name>.bundle.yaml)")
+
+	return cmd
+}
+
+func newBundleInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install <file|url>",
+		Short: "Install bookmarks from a bundle",
+		Long: `Import the bookmarks of a bundle file or http(s) URL into your store.
+
+Bookmarks that already exist unchanged are skipped. For bookmarks that exist
+with different content, --on-conflict decides:
+  skip       keep your version (default)
+  overwrite  replace your version with the bundle's
+  fail       abort without installing anything
+
+A bundle with bookmarks containing likely credentials is refused like with
+'tools import', unless --allow-secrets is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			b, err := bundle.Load(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			meta := b.Metadata
+			info("Installing bundle '%s' v%s", meta.Name, meta.Version)
+			if meta.Author != "" {
+				info(" by %s", meta.Author)
+			}
+			info(" (%d bookmarks)\n", len(b.Bookmarks))
+			if meta.Description != "" {
+				info("  %s\n", meta.Description)
+			}
+
+			for i := range b.Bookmarks {
+				b.Bookmarks[i].AllowSecrets = bundleSecrets
+			}
+			result, err := svc.ImportBookmarks(ctx, b.Bookmarks, dto.ConflictStrategy(bundleOnConflict))
+			if result != nil && len(result.Conflicts) > 0 {
+				fmt.Printf("Conflicting bookmarks (%s):\n", bundleOnConflict)
+				for _, command := range result.Conflicts {
+					fmt.Printf("  %s\n", command)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to install bundle: %w", err)
+			}
+
+			info("Successfully installed bundle: %d added, %d updated, %d skipped\n", result.Added, result.Updated, result.Skipped)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&bundleOnConflict, "on-conflict", string(dto.ConflictSkip), "Conflict handling: skip, overwrite or fail")
+	cmd.Flags().BoolVar(&bundleSecrets, "allow-secrets", false, "Install bookmarks even if they contain likely credentials")
+
+	return cmd
+}
+
+// matchesBundleFilter reports whether a bookmark is selected by the bundle filters
+func matchesBundleFilter(example dto.BookmarkResponse) bool {
+	if bundleTool != "" && example.ToolName != bundleTool {
+		return false
+	}
+	if len(bundleTags) == 0 {
+		return true
+	}
+	for _, tag := range bundleTags {
+		if slices.Contains(example.Tags, strings.ToLower(strings.TrimSpace(tag))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/capture"
+	"github.com/spf13/cobra"
+)
+
+func newCaptureCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capture",
+		Short: "Capture executed shell commands for later triage",
+		Long: `Opt-in capture of every command you run, so interesting ones can be turned
+into bookmarks later with 'tools triage'.
+
+Enable it by adding the hook to your shell startup file:
+
+  # ~/.bashrc
+  eval "$(tools capture init bash)"
+
+  # ~/.zshrc
+  eval "$(tools capture init zsh)"
+
+Commands starting with a space and invocations of tools itself are not captured.`,
+	}
+
+	cmd.AddCommand(newCaptureInitCmd())
+	cmd.AddCommand(newCaptureRecordCmd())
+	cmd.AddCommand(newCaptureClearCmd())
+
+	return cmd
+}
+
+func newCaptureInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       "init <bash|zsh>",
+		Short:     "Print the shell hook that captures executed commands",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"bash", "zsh"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hook, err := capture.Hook(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Print(hook)
+			return nil
+		},
+	}
+}
+
+func newCaptureRecordCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "record -- <command>",
+		Short:  "Record an executed command (called by the shell hook)",
+		Hidden: true,
+		Args:   cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command := strings.Join(args, " ")
+			if !shouldCapture(command) {
+				return nil
+			}
+
+			dir, _ := os.Getwd()
+			return capture.NewStaging(cfg.CaptureFilePath).Append(capture.Entry{
+				Time:    time.Now(),
+				Command: strings.TrimSpace(command),
+				Dir:     dir,
+			})
+		},
+	}
+}
+
+func newCaptureClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Discard all captured commands",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := capture.NewStaging(cfg.CaptureFilePath).Clear(); err != nil {
+				return err
+			}
+			info("Successfully cleared captured commands\n")
+			return nil
+		},
+	}
+}
+
+// shouldCapture filters out empty commands, commands hidden with a leading space
+// and invocations of tools itself
+func shouldCapture(command string) bool {
+	trimmed := strings.TrimSpace(command)
+	if trimmed == "" || strings.HasPrefix(command, " ") {
+		return false
+	}
+	return trimmed != "tools" && !strings.HasPrefix(trimmed, "tools ")
+}
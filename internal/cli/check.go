@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fgeck/tools/internal/toolcheck"
+	"github.com/spf13/cobra"
+)
+
+var checkHints bool
+
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check that the tools used by your bookmarks are installed",
+		Long: `Resolve the executable of every bookmarked command in your PATH and list the
+ones that are missing, together with the bookmarks using them.
+
+The executable is the first word of the command, skipping variable assignments
+and wrappers like sudo or env. Shell builtins are ignored.
+
+Use --hints to print install commands for brew, apt, dnf and pacman.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := svc.ListBookmarks(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to list examples: %w", err)
+			}
+
+			commands := make([]string, len(resp.Examples))
+			for i, example := range resp.Examples {
+				commands[i] = example.Command
+			}
+
+			statuses := toolcheck.Check(commands, exec.LookPath)
+			var missing []toolcheck.Status
+			for _, status := range statuses {
+				if status.Missing() {
+					missing = append(missing, status)
+				}
+			}
+
+			if len(missing) == 0 {
+				info("All %d tools used by your bookmarks are installed.\n", len(statuses))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "MISSING\tBOOKMARKS\tCOMMANDS")
+			_, _ = fmt.Fprintln(w, "-------\t---------\t--------")
+			for _, status := range missing {
+				_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", status.Binary, len(status.Commands), strings.Join(status.Commands, "; "))
+			}
+			_ = w.Flush()
+
+			if checkHints {
+				for _, status := range missing {
+					if strings.Contains(status.Binary, "/") {
+						continue
+					}
+					fmt.Printf("\nInstall %s:\n", status.Binary)
+					hw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					for _, hint := range toolcheck.InstallHints(status.Binary) {
+						_, _ = fmt.Fprintf(hw, "  %s:\t%s\n", hint.Manager, hint.Command)
+					}
+					_ = hw.Flush()
+				}
+			}
+
+			info("\nTotal: %d of %d tools missing\n", len(missing), len(statuses))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&checkHints, "hints", false, "Show install commands per package manager")
+
+	return cmd
+}
@@ -6,14 +6,30 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/fgeck/tools/internal/audit"
 	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/domain/models"
 	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository/layered"
+	"github.com/fgeck/tools/internal/repository/logged"
 	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/fgeck/tools/internal/selfupdate"
 	"github.com/fgeck/tools/internal/service"
 )
 
@@ -29,7 +45,7 @@ func setupTestCLI(t *testing.T) (string, func()) {
 	}
 
 	testSvc := service.NewBookmarkService(repo)
-	Initialize(testSvc)
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath})
 
 	// Return cleanup function
 	cleanup := func() {
@@ -54,6 +70,21 @@ func captureOutput(f func()) string {
 	return buf.String()
 }
 
+func captureStderr(f func()) string {
+	old := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	f()
+
+	w.Close()
+	os.Stderr = old
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	return buf.String()
+}
+
 func TestCLIAddCommand(t *testing.T) {
 	_, cleanup := setupTestCLI(t)
 	defer cleanup()
@@ -225,6 +256,350 @@ func TestCLIRemoveCommand(t *testing.T) {
 	}
 }
 
+func TestCLIQuickAdd(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	// Checked first: the flag set remembers the position of -- from earlier runs
+	rootCmd.SetArgs([]string{"add", "no dash", "kubectl"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d without --, got %d", ExitValidation, code)
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		args        []string
+		wantCommand string
+		wantTool    string
+	}{
+		{"inferred tool", []string{"add", "list pods", "--", "kubectl", "get", "pods", "-A"}, "kubectl get pods -A", "kubectl"},
+		{"wrapper skipped", []string{"add", "free port", "--", "sudo", "lsof", "-i", ":8080"}, "sudo lsof -i :8080", "lsof"},
+		{"quoted arguments", []string{"add", "find todos", "--", "grep", "-rn", "TODO: fix", "."}, "grep -rn 'TODO: fix' .", "grep"},
+		{"verbatim command", []string{"add", "biggest files", "--", "du -sh * | sort -h"}, "du -sh * | sort -h", "du"},
+		{"name override", []string{"add", "-n", "k8s", "list nodes", "--", "kubectl", "get", "nodes"}, "kubectl get nodes", "k8s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd.SetArgs(tt.args)
+			captureOutput(func() {
+				if err := rootCmd.Execute(); err != nil {
+					t.Fatalf("Add command failed: %v", err)
+				}
+			})
+
+			bookmark, err := svc.GetBookmark(ctx, tt.wantCommand)
+			if err != nil {
+				t.Fatalf("Expected bookmark %q: %v", tt.wantCommand, err)
+			}
+			if bookmark.ToolName != tt.wantTool {
+				t.Errorf("Expected tool %q, got %q", tt.wantTool, bookmark.ToolName)
+			}
+		})
+	}
+}
+
+func TestCLIAddInfersToolName(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	rootCmd.SetArgs([]string{"add", "-c", "KUBECONFIG=~/.kube/dev kubectl get pods", "-d", "list dev pods"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Add command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Using tool name 'kubectl' inferred from the command") {
+		t.Errorf("Expected inferred tool name to be reported, got: %s", output)
+	}
+	bookmark, err := svc.GetBookmark(context.Background(), "KUBECONFIG=~/.kube/dev kubectl get pods")
+	if err != nil || bookmark.ToolName != "kubectl" {
+		t.Errorf("Expected bookmark with tool kubectl, got %+v (%v)", bookmark, err)
+	}
+
+	rootCmd.SetArgs([]string{"add", "-c", "$EDITOR ~/.bashrc", "-d", "edit bashrc"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d when no tool can be inferred, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLIAddToolDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		command     string
+		description string
+		tags        string
+	}{
+		// Without -d the template is the description, the default tags join --tags
+		{"template", []string{"add", "-c", "kubectl get pods", "--tags", "prod"}, "kubectl get pods", "kubectl: kubectl get pods", "k8s,prod"},
+		{"quick add", []string{"add", "list nodes", "--", "kubectl", "get", "nodes"}, "kubectl get nodes", "list nodes", "k8s"},
+		{"no defaults", []string{"add", "-c", "kubectl get svc", "-d", "list services", "--no-defaults"}, "kubectl get svc", "list services", ""},
+		{"other tool", []string{"add", "-c", "helm list", "-d", "list releases"}, "helm list", "list releases", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cleanup := setupTestCLI(t)
+			defer cleanup()
+			cfg.ToolDefaults = map[string]config.ToolDefaults{
+				"kubectl": {Tags: []string{"k8s"}, Description: "{tool}: {command}"},
+			}
+
+			rootCmd.SetArgs(tt.args)
+			captureOutput(func() {
+				if err := rootCmd.Execute(); err != nil {
+					t.Fatalf("Add command failed: %v", err)
+				}
+			})
+			bookmark, err := svc.GetBookmark(context.Background(), tt.command)
+			if err != nil || bookmark.Description != tt.description || strings.Join(bookmark.Tags, ",") != tt.tags {
+				t.Errorf("Expected %q with tags %q, got %+v (%v)", tt.description, tt.tags, bookmark, err)
+			}
+		})
+	}
+
+	// Tools without a template still need a description
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	rootCmd.SetArgs([]string{"add", "-c", "helm list"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d without a description, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLIAddCanonical(t *testing.T) {
+	const typed, canonicalForm = "kubectl get pods -n dev -o wide", "kubectl get pods --namespace=dev --output=wide"
+	tests := []struct {
+		name     string
+		config   bool
+		flag     bool
+		answer   string
+		expected string
+	}{
+		{"approved", true, false, "y\n", canonicalForm},
+		{"declined", true, false, "n\n", typed},
+		{"flag", false, true, "", canonicalForm},
+		{"off", false, false, "", typed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cleanup := setupTestCLI(t)
+			defer cleanup()
+			cfg.CanonicalCommands = tt.config
+			rootCmd.SetIn(strings.NewReader(tt.answer))
+			defer rootCmd.SetIn(nil)
+
+			args := []string{"add", "-c", typed, "-d", "list dev pods"}
+			if tt.flag {
+				args = append(args, "--canonical")
+			}
+			rootCmd.SetArgs(args)
+			output := captureOutput(func() {
+				if err := rootCmd.Execute(); err != nil {
+					t.Fatalf("Add command failed: %v", err)
+				}
+			})
+			if prompted := strings.Contains(output, "Canonical form: "+canonicalForm); prompted != tt.config {
+				t.Errorf("Expected prompt %v, got: %s", tt.config, output)
+			}
+			if _, err := svc.GetBookmark(context.Background(), tt.expected); err != nil {
+				t.Errorf("Expected %q to be stored: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestCLIBookmarkLink(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	rootCmd.SetArgs([]string{"add", "-n", "helm", "-c", "helm list -A", "-d", "list releases", "--link", "https://helm.sh/docs/helm/helm_list/"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Add command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"get", "-c", "helm list -A"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Get command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "link:        https://helm.sh/docs/helm/helm_list/") {
+		t.Errorf("Expected link in output, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"edit", "-c", "helm list -A", "--new-link", ""})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Edit command failed: %v", err)
+		}
+	})
+	if bookmark, _ := svc.GetBookmark(context.Background(), "helm list -A"); bookmark.Link != "" {
+		t.Errorf("Expected link to be removed, got %q", bookmark.Link)
+	}
+
+	rootCmd.SetArgs([]string{"add", "-n", "ls", "-c", "ls -la", "-d", "list", "--link", "docs"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an invalid link, got %d", ExitValidation, code)
+	}
+	addLink = ""
+}
+
+func TestCLIRemovePartialCommand(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps -a", ToolName: "docker", Description: "list containers"})
+
+	rootCmd.SetArgs([]string{"remove", "-c", "KUBECTL get"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Remove command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully removed example: kubectl get pods") {
+		t.Errorf("Expected resolved command in output, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"remove", "-c", "helm"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for no match, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIRemoveByToolNameConfirmation(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get nodes", ToolName: "kubectl", Description: "list nodes"})
+
+	// Declining keeps the bookmarks
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"remove", "-c", "", "-n", "kubectl", "--yes=false"})
+	var code int
+	output := captureOutput(func() {
+		code = run()
+	})
+	if code != ExitError {
+		t.Errorf("Expected exit code %d when declined, got %d", ExitError, code)
+	}
+	if !strings.Contains(output, "This will delete 2 example(s) for tool 'kubectl'. Continue? [y/N]") {
+		t.Errorf("Expected confirmation prompt with count, got: %s", output)
+	}
+	if resp, _ := svc.ListBookmarks(ctx); resp.Count != 2 {
+		t.Errorf("Expected 2 bookmarks after declining, got %d", resp.Count)
+	}
+
+	// Confirming removes them
+	rootCmd.SetIn(strings.NewReader("y\n"))
+	rootCmd.SetArgs([]string{"remove", "-c", "", "-n", "kubectl", "--yes=false"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Remove command failed: %v", err)
+		}
+	})
+	if resp, _ := svc.ListBookmarks(ctx); resp.Count != 0 {
+		t.Errorf("Expected no bookmarks after confirming, got %d", resp.Count)
+	}
+
+	// --yes skips the prompt
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "helm list", ToolName: "helm", Description: "list releases"})
+	rootCmd.SetIn(strings.NewReader(""))
+	rootCmd.SetArgs([]string{"remove", "-c", "", "-n", "helm", "--yes"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Remove command failed: %v", err)
+		}
+	})
+	if strings.Contains(output, "Continue?") {
+		t.Errorf("Expected no prompt with --yes, got: %s", output)
+	}
+	if resp, _ := svc.ListBookmarks(ctx); resp.Count != 0 {
+		t.Errorf("Expected helm bookmark to be removed, got %d bookmarks", resp.Count)
+	}
+}
+
+func TestCLINotFoundSuggestions(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "list all pods"})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"get", []string{"get", "-c", "kubectl get pod -A"}, "did you mean 'kubectl get pods -A'?"},
+		{"remove command", []string{"remove", "-c", "kubectl get pod -A", "-n", ""}, "did you mean 'kubectl get pods -A'?"},
+		{"remove tool", []string{"remove", "-c", "", "-n", "kubetcl", "--yes"}, "did you mean 'kubectl'?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+			if err != nil {
+				if parts := strings.Split(err.Error(), ": "); len(parts) > 1 && parts[0] == parts[1] {
+					t.Errorf("Expected the failure stated once, got %v", err)
+				}
+			}
+			if ExitCode(err) != ExitNotFound {
+				t.Errorf("Expected not-found exit code, got %d", ExitCode(err))
+			}
+		})
+	}
+}
+
+func TestCLIEditPartialCommandDisambiguation(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get nodes", ToolName: "kubectl", Description: "list nodes"})
+
+	rootCmd.SetIn(strings.NewReader("2\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"edit", "-c", "kubectl get", "-d", "all nodes"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Edit command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "2 examples match 'kubectl get'") {
+		t.Errorf("Expected disambiguation list, got: %s", output)
+	}
+
+	example, err := svc.GetBookmark(ctx, "kubectl get nodes")
+	if err != nil {
+		t.Fatalf("Failed to get example: %v", err)
+	}
+	if example.Description != "all nodes" {
+		t.Errorf("Expected selected example to be edited, got %q", example.Description)
+	}
+
+	rootCmd.SetIn(strings.NewReader("\n"))
+	rootCmd.SetArgs([]string{"edit", "-c", "kubectl get", "-d", "none"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d without a selection, got %d", ExitValidation, code)
+	}
+}
+
 func TestCLIEndToEndWorkflow(t *testing.T) {
 	filePath, cleanup := setupTestCLI(t)
 	defer cleanup()
@@ -602,3 +977,2884 @@ func TestCLIListCommandEdgeCases(t *testing.T) {
 		t.Error("Output should contain 'cat' tool")
 	}
 }
+
+func TestCLIMigrateLegacy(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	legacy := `bookmarks:
+  - command: ls -la
+    toolname: ls
+    description: list
+examples:
+  - command: ls -la
+    tool_name: ls
+    description: list again
+  - command: df -h
+    tool_name: df
+    description: disk usage
+tools:
+  - name: lsof
+    examples:
+      - command: lsof -i :8080
+        description: check port
+`
+	if err := os.WriteFile(filePath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"migrate-legacy", "--dry-run"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Migrate-legacy command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "1 tools with 1 examples") || !strings.Contains(output, "1 duplicate commands") {
+		t.Errorf("Expected a report of the legacy sections, got: %s", output)
+	}
+	if data, _ := os.ReadFile(filePath); string(data) != legacy {
+		t.Errorf("Expected a dry run to leave the store alone, got:\n%s", data)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"migrate-legacy"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Migrate-legacy command failed: %v", err)
+		}
+	})
+	data, _ := os.ReadFile(filePath)
+	if strings.Contains(string(data), "examples:") || strings.Contains(string(data), "tools:") {
+		t.Errorf("Expected the legacy sections to be converted, got:\n%s", data)
+	}
+	if backup, _ := os.ReadFile(yaml.BackupPath(filePath)); string(backup) != legacy {
+		t.Errorf("Expected the legacy file as backup, got:\n%s", backup)
+	}
+	bookmark, err := svc.GetBookmark(ctx, "ls -la")
+	if err != nil || bookmark.Description != "list" {
+		t.Errorf("Expected the current bookmark to win over its legacy duplicate, got %+v: %v", bookmark, err)
+	}
+	bookmark, err = svc.GetBookmark(ctx, "lsof -i :8080")
+	if err != nil || bookmark.ToolName != "lsof" {
+		t.Errorf("Expected the tool group's name as tool name, got %+v: %v", bookmark, err)
+	}
+
+	// Other legacy files are added to the store, keeping the bookmarked commands
+	other := filepath.Join(t.TempDir(), "examples.yaml")
+	_ = os.WriteFile(other, []byte("tools:\n  - name: git\n    examples:\n      - command: git status\n        description: status\n      - command: df -h\n        description: other\n"), 0644)
+	rootCmd.SetArgs([]string{"migrate-legacy", other})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Migrate-legacy command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "1 added, 1 skipped") {
+		t.Errorf("Expected one bookmark to be added, got: %s", output)
+	}
+	if bookmark, _ := svc.GetBookmark(ctx, "df -h"); bookmark == nil || bookmark.Description != "disk usage" {
+		t.Errorf("Expected the bookmarked command to be kept, got %+v", bookmark)
+	}
+
+	rootCmd.SetArgs([]string{"migrate-legacy", filepath.Join(t.TempDir(), "missing.yaml")})
+	if err := rootCmd.Execute(); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found error for a missing file, got %v", err)
+	}
+}
+
+func TestCLIMigrateCommand(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "kubectl get pods",
+		ToolName:    "kubectl",
+		Description: "list pods",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+
+	destPath := filepath.Join(filepath.Dir(filePath), "migrated.yaml")
+	rootCmd.SetArgs([]string{"migrate", "--from", "yaml", "--to", "yaml", "--to-path", destPath})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Migrate command failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "1 migrated") {
+		t.Errorf("Expected migration summary, got: %s", output)
+	}
+
+	dest, err := yaml.NewYAMLBookmarkRepository(destPath)
+	if err != nil {
+		t.Fatalf("Failed to open destination: %v", err)
+	}
+	if exists, _ := dest.Exists(ctx, "kubectl get pods"); !exists {
+		t.Error("Expected bookmark in destination store")
+	}
+
+	// The configured store through a symlink or a relative path is the same store
+	link := filepath.Join(t.TempDir(), "link.yaml")
+	if err := os.Symlink(filePath, link); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relative, err := filepath.Rel(wd, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{link, relative} {
+		Initialize(svc, cfg)
+		rootCmd.SetArgs([]string{"migrate", "--to-path", path})
+		if err := rootCmd.Execute(); !errors.Is(err, models.ErrValidation) {
+			t.Errorf("Expected a validation error migrating into %s, got %v", path, err)
+		}
+	}
+}
+
+func TestCLIHistoryCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	historyPath := filepath.Join(tmpDir, "history.jsonl")
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	testSvc := service.NewBookmarkService(repo, service.WithAuditLog(audit.NewFileLog(historyPath)))
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "kubectl get pods",
+		ToolName:    "kubectl",
+		Description: "old description",
+	})
+	_, _ = svc.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{
+		Command:        "kubectl get pods",
+		NewDescription: "new description",
+	})
+
+	rootCmd.SetArgs([]string{"history", "-c", "kubectl get pods"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("History command failed: %v", err)
+		}
+	})
+
+	for _, want := range []string{"create", "edit", "'old description' -> 'new description'", "Total: 2 events"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestCLILogFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	logPath := filepath.Join(tmpDir, "tools.log")
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	logger := Logger()
+	testSvc := service.NewBookmarkService(logged.NewLoggedBookmarkRepository(repo, logger, filePath), service.WithLogger(logger))
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath})
+	defer logHandler.Set(slog.DiscardHandler)
+
+	rootCmd.SetArgs([]string{"add", "-n", "ls", "-c", "ls -la", "-d", "list", "--log-file", logPath, "--log-level", "debug"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+	if code := shutdown(ExitOK); code != ExitOK {
+		t.Errorf("Expected closing the log to succeed, got exit code %d", code)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if !strings.Contains(string(data), `op=create`) || !strings.Contains(string(data), `msg="bookmark create" command="ls -la"`) {
+		t.Errorf("Expected the store operation and the change in the log, got: %s", data)
+	}
+
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath})
+	rootCmd.SetArgs([]string{"list", "--log-level", "loud"})
+	if err := rootCmd.Execute(); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error for an invalid log level, got %v", err)
+	}
+}
+
+func TestCLIEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	historyPath := filepath.Join(tmpDir, "history.jsonl")
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	testSvc := service.NewBookmarkService(repo, service.WithAuditLog(audit.NewFileLog(historyPath)))
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "ls -la", ToolName: "ls", Description: "list"})
+	_ = svc.RecordRun(ctx, "ls -la")
+	_, _ = svc.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{Command: "ls -la", NewDescription: "list all"})
+
+	rootCmd.SetArgs([]string{"events", "-a", "create", "-a", "edit"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Events command failed: %v", err)
+		}
+	})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one JSON line per create and edit event, got: %s", output)
+	}
+	var event audit.Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("Expected a JSON event, got %q: %v", lines[1], err)
+	}
+	if event.Action != audit.ActionEdit || event.Before.Description != "list" || event.After.Description != "list all" {
+		t.Errorf("Unexpected edit event: %+v", event)
+	}
+
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+	rootCmd.SetArgs([]string{"events", "-a", "rename"})
+	if err := rootCmd.Execute(); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error for unknown action, got %v", err)
+	}
+
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+	rootCmd.SetArgs([]string{"events", "--new"})
+	if err := rootCmd.Execute(); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error for --new without --follow, got %v", err)
+	}
+}
+
+func TestCLIRevisionsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	Initialize(service.NewBookmarkService(repo, service.WithMaxRevisions(5)), &config.Config{StorageFilePath: filePath})
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "kubectl get pods",
+		ToolName:    "kubectl",
+		Description: "old description",
+	})
+	_, _ = svc.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{
+		Command:        "kubectl get pods",
+		NewDescription: "new description",
+	})
+
+	rootCmd.SetArgs([]string{"revisions", "-c", "kubectl get pods"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Revisions command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "#1") || !strings.Contains(output, "'old description' -> 'new description'") {
+		t.Errorf("Expected revision listing with changes, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"revisions", "-c", "kubectl get pods", "--restore", "1"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Restore failed: %v", err)
+		}
+	})
+
+	resp, _ := svc.GetBookmark(ctx, "kubectl get pods")
+	if resp.Description != "old description" {
+		t.Errorf("Expected restored description, got %s", resp.Description)
+	}
+}
+
+func TestCLIAskCommand(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "lsof -ti :8080 | xargs kill",
+		ToolName:    "lsof",
+		Description: "kill whatever listens on port 8080",
+	})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "docker ps",
+		ToolName:    "docker",
+		Description: "list running containers",
+	})
+
+	rootCmd.SetArgs([]string{"ask", "how do I kill the process on port 8080"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Ask command failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "xargs kill") {
+		t.Errorf("Expected lsof bookmark in output, got: %s", output)
+	}
+	if docker := strings.Index(output, "docker ps"); docker != -1 && docker < strings.Index(output, "xargs kill") {
+		t.Errorf("Best match should be listed first, got: %s", output)
+	}
+}
+
+func TestCLIBundleCreateAndInstall(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "kubectl get pods",
+		ToolName:    "kubectl",
+		Description: "list pods",
+		Tags:        []string{"k8s"},
+	})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "docker ps",
+		ToolName:    "docker",
+		Description: "list containers",
+	})
+
+	bundlePath := filepath.Join(filepath.Dir(filePath), "k8s.bundle.yaml")
+	rootCmd.SetArgs([]string{"bundle", "create", "k8s", "--tag", "k8s", "--author", "alice", "-o", bundlePath})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Bundle create failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "1 bookmarks") {
+		t.Errorf("Expected one bundled bookmark, got: %s", output)
+	}
+
+	// Install into a fresh store
+	otherPath := filepath.Join(filepath.Dir(filePath), "other.yaml")
+	repo, err := yaml.NewYAMLBookmarkRepository(otherPath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	Initialize(service.NewBookmarkService(repo), &config.Config{StorageFilePath: otherPath})
+
+	rootCmd.SetArgs([]string{"bundle", "install", bundlePath})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Bundle install failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "by alice") || !strings.Contains(output, "1 added") {
+		t.Errorf("Unexpected install output: %s", output)
+	}
+
+	installed, err := svc.GetBookmark(ctx, "kubectl get pods")
+	if err != nil {
+		t.Fatalf("Bundled bookmark not installed: %v", err)
+	}
+	if len(installed.Tags) != 1 || installed.Tags[0] != "k8s" {
+		t.Errorf("Expected tags to be installed, got %v", installed.Tags)
+	}
+	if _, err := svc.GetBookmark(ctx, "docker ps"); err == nil {
+		t.Error("Untagged bookmark should not have been bundled")
+	}
+}
+
+func TestDemoService(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	demoSvc, err := newDemoService(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create demo service: %v", err)
+	}
+	if _, err := demoSvc.GetBookmark(ctx, "kubectl get pods -A -o wide"); err != nil {
+		t.Errorf("Expected the sample bookmarks in the demo: %v", err)
+	}
+	if _, err := demoSvc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "ls -la", ToolName: "ls", Description: "list"}); err != nil {
+		t.Fatalf("Failed to add a demo bookmark: %v", err)
+	}
+
+	resp, err := svc.ListBookmarks(ctx)
+	if err != nil || len(resp.Examples) != 0 {
+		t.Errorf("Expected the store to be untouched by the demo, got %d bookmarks: %v", len(resp.Examples), err)
+	}
+}
+
+func TestCLIStarter(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rootCmd.SetArgs([]string{"starter", "--list"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Starter command failed: %v", err)
+		}
+	})
+	for _, name := range []string{"git", "docker", "kubectl", "networking"} {
+		if !strings.Contains(output, name) {
+			t.Errorf("Expected starter pack %q in the list, got: %s", name, output)
+		}
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"starter", "git", "KUBECTL"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Starter command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Installed starter pack 'git'") || !strings.Contains(output, "Installed starter pack 'kubectl'") {
+		t.Errorf("Expected both packs to be installed, got: %s", output)
+	}
+	bookmark, err := svc.GetBookmark(ctx, "git status -sb")
+	if err != nil || bookmark.ToolName != "git" {
+		t.Errorf("Expected a git bookmark, got %+v: %v", bookmark, err)
+	}
+
+	// Installing again keeps the existing bookmarks
+	_, _ = svc.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{Command: "git status -sb", NewDescription: "my status"})
+	rootCmd.SetArgs([]string{"starter", "git"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Starter command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "0 added") {
+		t.Errorf("Expected nothing to be added again, got: %s", output)
+	}
+	if bookmark, _ := svc.GetBookmark(ctx, "git status -sb"); bookmark == nil || bookmark.Description != "my status" {
+		t.Errorf("Expected the edited bookmark to be kept, got %+v", bookmark)
+	}
+
+	rootCmd.SetArgs([]string{"starter", "emacs"})
+	if err := rootCmd.Execute(); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error for an unknown pack, got %v", err)
+	}
+}
+
+func TestCLIListShowsOrigin(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	systemPath := filepath.Join(filepath.Dir(filePath), "system.yaml")
+	content := "bookmarks:\n  - command: kubectl get pods -A\n    toolname: kubectl\n    description: list all pods\n"
+	if err := os.WriteFile(systemPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	userRepo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	repo := layered.NewLayeredBookmarkRepository(userRepo, layered.Layer{
+		Name: "system",
+		Repo: yaml.NewReadOnlyYAMLBookmarkRepository(systemPath),
+	})
+	Initialize(service.NewBookmarkService(repo), &config.Config{StorageFilePath: filePath})
+
+	_, _ = svc.CreateBookmark(context.Background(), dto.CreateBookmarkRequest{
+		Command:     "docker ps",
+		ToolName:    "docker",
+		Description: "list containers",
+	})
+
+	rootCmd.SetArgs([]string{"list"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("List command failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "ORIGIN") {
+		t.Errorf("Expected ORIGIN column, got: %s", output)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "kubectl get pods -A") && !strings.HasSuffix(strings.TrimSpace(line), "system") {
+			t.Errorf("Expected system origin for layer bookmark, got: %s", line)
+		}
+		if strings.Contains(line, "docker ps") && !strings.HasSuffix(strings.TrimSpace(line), "user") {
+			t.Errorf("Expected user origin for own bookmark, got: %s", line)
+		}
+	}
+
+	// Highlighting pads the command column itself; without a terminal there are no colors
+	// and the table must look the same
+	rootCmd.SetArgs([]string{"list", "--color"})
+	colored := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("List command failed: %v", err)
+		}
+	})
+	listColor = false
+	if colored != output {
+		t.Errorf("Expected the same layout with --color, got:\n%s\nwant:\n%s", colored, output)
+	}
+
+	// Writes never touch the system file
+	rootCmd.SetArgs([]string{"remove", "-c", "kubectl get pods -A"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err == nil {
+			t.Error("Expected error when removing a system bookmark")
+		}
+	})
+}
+
+func TestCLILearn(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	// A fake tool that documents two examples in its help output
+	binDir := filepath.Join(filepath.Dir(filePath), "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := `#!/bin/sh
+cat <<'HELP'
+Usage: fakectl [command]
+
+Examples:
+  # Show the status
+  fakectl status
+
+  # Restart everything
+  fakectl restart --all
+HELP
+`
+	if err := os.WriteFile(filepath.Join(binDir, "fakectl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// Accept the first example, decline the second
+	rootCmd.SetIn(strings.NewReader("y\nn\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"learn", "fakectl"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Learn command failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Found 2 examples") || !strings.Contains(output, "Successfully added 1 examples") {
+		t.Errorf("Unexpected learn output: %s", output)
+	}
+
+	ctx := context.Background()
+	added, err := svc.GetBookmark(ctx, "fakectl status")
+	if err != nil {
+		t.Fatalf("Accepted example not added: %v", err)
+	}
+	if added.ToolName != "fakectl" || added.Description != "Show the status" {
+		t.Errorf("Unexpected bookmark: %+v", added)
+	}
+	if _, err := svc.GetBookmark(ctx, "fakectl restart --all"); err == nil {
+		t.Error("Declined example should not be added")
+	}
+}
+
+func TestCLICheck(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "sh -c 'echo hi'",
+		ToolName:    "sh",
+		Description: "installed everywhere",
+	})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "definitely-not-installed-tool --version",
+		ToolName:    "missing",
+		Description: "not installed",
+	})
+
+	rootCmd.SetArgs([]string{"check", "--hints"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Check command failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "definitely-not-installed-tool") {
+		t.Errorf("Expected missing tool to be reported, got: %s", output)
+	}
+	if strings.Contains(output, "sh -c") {
+		t.Errorf("Installed tool should not be reported, got: %s", output)
+	}
+	if !strings.Contains(output, "brew install definitely-not-installed-tool") {
+		t.Errorf("Expected install hints, got: %s", output)
+	}
+	if !strings.Contains(output, "Total: 1 of 2 tools missing") {
+		t.Errorf("Expected total line, got: %s", output)
+	}
+}
+
+func TestCLILint(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "kubectl get pods --show-all",
+		ToolName:    "kubectl",
+		Description: "list all pods",
+	})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:      "mysql -u root -phunter22 shop",
+		ToolName:     "mysql",
+		Description:  "shop database",
+		AllowSecrets: true,
+	})
+
+	rootCmd.SetArgs([]string{"lint"})
+	var code int
+	output := captureOutput(func() {
+		captureStderr(func() { code = run() })
+	})
+	if code != ExitError {
+		t.Errorf("Expected exit code %d for an error finding, got %d", ExitError, code)
+	}
+	for _, expected := range []string{"credentials", "deprecated", "(fixable)", "Total: 2 findings in 2 bookmarks (1 errors, 1 warnings, 0 infos)"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the output, got: %s", expected, output)
+		}
+	}
+	if !strings.Contains(output, "MySQL password hu******") {
+		t.Errorf("Expected the password to be masked, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"lint", "--fix", "--rule", "deprecated"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Lint --fix failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Fixed 1 bookmarks") || !strings.Contains(output, "No findings") {
+		t.Errorf("Expected the deprecated flag to be fixed, got: %s", output)
+	}
+	if _, err := svc.GetBookmark(ctx, "kubectl get pods"); err != nil {
+		t.Errorf("Expected the fixed command to be stored: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"lint", "--rule", "unknown"})
+	captureStderr(func() { code = run() })
+	if code != ExitValidation {
+		t.Errorf("Expected exit code %d for an unknown rule, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLISecretScanning(t *testing.T) {
+	tmpDir := t.TempDir()
+	const secret = "psql postgres://admin:s3cret@db/shop"
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"add refused", []string{"add", "query shop", "--", secret}, ExitValidation},
+		{"add allowed", []string{"add", "query shop", "--allow-secrets", "--", secret}, ExitOK},
+		{"add reference", []string{"add", "query shop", "--", "psql postgres://admin:$PGPASSWORD@db/shop"}, ExitOK},
+		{"import refused", []string{"import", filepath.Join(tmpDir, "import.yaml")}, ExitValidation},
+		{"import allowed", []string{"import", "--allow-secrets", filepath.Join(tmpDir, "import.yaml")}, ExitOK},
+		{"bundle install refused", []string{"bundle", "install", filepath.Join(tmpDir, "shop.bundle.yaml")}, ExitValidation},
+		{"bundle install allowed", []string{"bundle", "install", "--allow-secrets", filepath.Join(tmpDir, "shop.bundle.yaml")}, ExitOK},
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "import.yaml"), []byte(`examples:
+  - command: `+secret+`
+    tool_name: psql
+    description: query shop
+  - command: psql --version
+    tool_name: psql
+    description: show the version
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "shop.bundle.yaml"), []byte(`format: 1
+bundle:
+  name: shop
+bookmarks:
+  - command: `+secret+`
+    tool_name: psql
+    description: query shop
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cleanup := setupTestCLI(t)
+			defer cleanup()
+
+			rootCmd.SetArgs(tt.args)
+			var code int
+			var stderr string
+			captureOutput(func() {
+				stderr = captureStderr(func() { code = run() })
+			})
+			if code != tt.code {
+				t.Fatalf("Expected exit code %d, got %d: %s", tt.code, code, stderr)
+			}
+			if strings.Contains(stderr, "s3cret") {
+				t.Errorf("Expected the secret to be masked, got: %s", stderr)
+			}
+
+			resp, _ := svc.ListBookmarks(context.Background())
+			if tt.code != ExitOK && resp.Count != 0 {
+				t.Errorf("Expected nothing to be stored, got %+v", resp.Examples)
+			}
+			if tt.code == ExitOK && resp.Count == 0 {
+				t.Error("Expected the bookmarks to be stored")
+			}
+		})
+	}
+}
+
+func TestCLIEditSecrets(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	if _, err := svc.CreateBookmark(context.Background(), dto.CreateBookmarkRequest{Command: "psql shop", ToolName: "psql", Description: "query shop"}); err != nil {
+		t.Fatal(err)
+	}
+	const secret = "psql postgres://admin:s3cret@db/shop"
+
+	rootCmd.SetArgs([]string{"edit", "-c", "psql shop", "-n", secret})
+	var code int
+	captureOutput(func() {
+		captureStderr(func() { code = run() })
+	})
+	if code != ExitValidation {
+		t.Errorf("Expected a new command with a password refused with exit code %d, got %d", ExitValidation, code)
+	}
+
+	rootCmd.SetArgs([]string{"edit", "-c", "psql shop", "-n", secret, "--allow-secrets"})
+	captureOutput(func() {
+		captureStderr(func() { code = run() })
+	})
+	if code != ExitOK {
+		t.Errorf("Expected the edit allowed with --allow-secrets, got exit code %d", code)
+	}
+}
+
+func TestCLIRedaction(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	cfg.Redaction = config.RedactionConfig{Patterns: []string{`--host=(\S+)`}}
+	const command = "psql --host=prod-db-1 shop"
+
+	rootCmd.SetArgs([]string{"add", "-c", command, "-d", "query shop"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Add command failed: %v", err)
+		}
+	})
+
+	for _, args := range [][]string{{"list"}, {"get", "-c", command}, {"export", "-f", "markdown"}} {
+		rootCmd.SetArgs(args)
+		output := captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("%s failed: %v", args[0], err)
+			}
+		})
+		if strings.Contains(output, "prod-db-1") || !strings.Contains(output, "psql --host=**** shop") {
+			t.Errorf("Expected %s to redact the host, got: %s", args[0], output)
+		}
+	}
+
+	// The stored command is only revealed after confirmation
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"run", "-c", command, "--print"})
+	var runErr error
+	output := captureOutput(func() { runErr = rootCmd.Execute() })
+	if !errors.Is(runErr, errAborted) || strings.Contains(output, "prod-db-1") {
+		t.Errorf("Expected the run to be aborted, got %v: %s", runErr, output)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", command, "--print", "--yes"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Run command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, command) {
+		t.Errorf("Expected the stored command with --yes, got: %s", output)
+	}
+}
+
+func TestCLIAddAndRunScript(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	scriptPath := filepath.Join(filepath.Dir(filePath), "script.sh")
+	outPath := filepath.Join(filepath.Dir(filePath), "out.txt")
+	script := "for i in 1 2; do\n\techo \"line $i\"\ndone > " + outPath + "\n"
+	if err := os.WriteFile(scriptPath, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"add", "-n", "sh", "-d", "write two lines", "-f", scriptPath})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+
+	stored := strings.TrimSuffix(script, "\n")
+	if _, err := svc.GetBookmark(context.Background(), stored); err != nil {
+		t.Fatalf("Script bookmark not stored: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", stored})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Run command failed: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Script did not run: %v", err)
+	}
+	if string(data) != "line 1\nline 2\n" {
+		t.Errorf("Unexpected script output: %q", data)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", stored, "--print"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Run --print failed: %v", err)
+		}
+	})
+
+	path := strings.TrimSpace(output)
+	defer func() { _ = os.Remove(path) }()
+	content, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(content), stored) {
+		t.Errorf("Expected printed path of the script, got %q (%v)", output, err)
+	}
+}
+
+func TestCLIRunRecordsResult(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	historyPath := filepath.Join(tmpDir, "history.jsonl")
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	testSvc := service.NewBookmarkService(repo, service.WithAuditLog(audit.NewFileLog(historyPath)))
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     "exit 3",
+		ToolName:    "sh",
+		Description: "fail on purpose",
+	})
+
+	rootCmd.SetArgs([]string{"get", "-c", "exit 3"})
+	output := captureOutput(func() { _ = rootCmd.Execute() })
+	if strings.Contains(output, "last run:") {
+		t.Errorf("Expected no last run before the first execution, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", "exit 3"})
+	var runErr error
+	_ = captureOutput(func() { runErr = rootCmd.Execute() })
+	if runErr == nil || !strings.Contains(runErr.Error(), "exit code 3") {
+		t.Errorf("Expected the run to fail with exit code 3, got %v", runErr)
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "exit 3"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Get command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "last run:    exit code 3 after") {
+		t.Errorf("Expected the last run, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"history", "-c", "exit 3"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("History command failed: %v", err)
+		}
+	})
+	for _, want := range []string{"run", "result", "exit code 3 after", "Total: 3 events"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestCLIRunCapture(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	Initialize(service.NewBookmarkService(repo), &config.Config{
+		StorageFilePath: filePath,
+		Runs:            config.RunsConfig{Dir: filepath.Join(tmpDir, "runs"), Keep: config.DefaultKeptRuns},
+	})
+
+	ctx := context.Background()
+	for _, cmd := range []string{"echo captured; echo oops >&2", "echo not captured"} {
+		_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: cmd, ToolName: "sh", Description: "print"})
+	}
+
+	rootCmd.SetArgs([]string{"runs", "show"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Runs show failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "No captured runs found") {
+		t.Errorf("Expected no runs yet, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", "echo captured; echo oops >&2", "--capture"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Run command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "captured") {
+		t.Errorf("Expected the output to be shown while capturing, got: %s", output)
+	}
+	runCapture = false
+	rootCmd.SetArgs([]string{"run", "-c", "echo not captured"})
+	_ = captureOutput(func() { _ = rootCmd.Execute() })
+
+	rootCmd.SetArgs([]string{"runs", "list"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Runs list failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "echo captured") || strings.Contains(output, "not captured") {
+		t.Errorf("Expected only the captured run, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"runs", "show"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Runs show failed: %v", err)
+		}
+	})
+	for _, want := range []string{"command:     echo captured", "exit code:   0", "captured\noops\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"runs", "show", "20000101-000000"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing run, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIRunbook(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	testCfg := &config.Config{StorageFilePath: filePath, RunbooksFile: filepath.Join(tmpDir, "runbooks.yaml")}
+	Initialize(service.NewBookmarkService(repo), testCfg)
+
+	outPath := filepath.Join(tmpDir, "out.txt")
+	steps := []string{"echo one >> " + outPath, "echo two >> " + outPath, "exit 5", "echo four >> " + outPath}
+	ctx := context.Background()
+	for i, cmd := range steps {
+		_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: cmd, ToolName: "sh", Description: "step " + strconv.Itoa(i+1)})
+	}
+	createArgs := []string{"runbook", "create", "deploy", "-d", "ship it"}
+	for _, cmd := range steps {
+		createArgs = append(createArgs, "-c", cmd)
+	}
+
+	rootCmd.SetArgs([]string{"runbook", "create", "deploy", "-c", "missing"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing bookmark, got %d", ExitNotFound, code)
+	}
+	Initialize(svc, testCfg)
+	rootCmd.SetArgs(createArgs)
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Runbook create failed: %v", err)
+		}
+	})
+	rootCmd.SetArgs(createArgs)
+	if code := run(); code != ExitConflict {
+		t.Errorf("Expected exit code %d for an existing runbook, got %d", ExitConflict, code)
+	}
+
+	// Run the first step, skip the second, the third fails
+	Initialize(svc, testCfg)
+	rootCmd.SetIn(strings.NewReader("y\ns\ny\n"))
+	rootCmd.SetArgs([]string{"runbook", "run", "deploy"})
+	var runErr error
+	output := captureOutput(func() { runErr = rootCmd.Execute() })
+	if runErr == nil || !strings.Contains(runErr.Error(), "step 3 failed with exit code 5") || !strings.Contains(runErr.Error(), "--from 3") {
+		t.Errorf("Expected the runbook to stop at step 3, got %v", runErr)
+	}
+	if !strings.Contains(output, "3/4  step 3") {
+		t.Errorf("Expected the steps to be shown, got: %s", output)
+	}
+	data, _ := os.ReadFile(outPath)
+	if string(data) != "one\n" {
+		t.Errorf("Expected only the first step to run, got %q", data)
+	}
+
+	Initialize(svc, testCfg)
+	rootCmd.SetArgs([]string{"runbook", "run", "deploy", "--from", "4", "--yes"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Runbook run failed: %v", err)
+		}
+	})
+	data, _ = os.ReadFile(outPath)
+	if string(data) != "one\nfour\n" {
+		t.Errorf("Expected the last step to run, got %q", data)
+	}
+
+	// Declining stops before anything runs
+	Initialize(svc, testCfg)
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	rootCmd.SetArgs([]string{"runbook", "run", "deploy"})
+	_ = captureOutput(func() { runErr = rootCmd.Execute() })
+	if runErr == nil || !strings.Contains(runErr.Error(), "stopped runbook 'deploy' before step 1") {
+		t.Errorf("Expected the runbook to stop, got %v", runErr)
+	}
+	rootCmd.SetIn(nil)
+
+	rootCmd.SetArgs([]string{"runbook", "list"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Runbook list failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "deploy") || !strings.Contains(output, "ship it") {
+		t.Errorf("Expected the runbook in the list, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"runbook", "delete", "deploy"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Runbook delete failed: %v", err)
+		}
+	})
+	rootCmd.SetArgs([]string{"runbook", "show", "deploy"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a deleted runbook, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"success", []string{"list"}, ExitOK},
+		{"edit missing", []string{"edit", "-c", "missing", "-d", "x"}, ExitNotFound},
+		{"remove missing", []string{"remove", "-c", "missing"}, ExitNotFound},
+		{"add duplicate", []string{"add", "-n", "ls", "-c", "ls -la", "-d", "again"}, ExitConflict},
+		{"add invalid tag", []string{"add", "-n", "ls", "-c", "ls", "-d", "list", "--tags", "two words"}, ExitValidation},
+		{"get existing", []string{"get", "-c", "ls -la"}, ExitOK},
+		{"get missing", []string{"get", "-c", "missing", "--quiet"}, ExitNotFound},
+		{"edit without fields", []string{"edit", "-c", "ls -la"}, ExitValidation},
+		{"edit changed meanwhile", []string{"edit", "-c", "ls -la", "-d", "x", "--if-updated-at", "2000-01-01T00:00:00Z"}, ExitConflict},
+		{"edit invalid update time", []string{"edit", "-c", "ls -la", "-d", "x", "--if-updated-at", "yesterday"}, ExitValidation},
+		{"remove changed meanwhile", []string{"remove", "-c", "ls -la", "--if-updated-at", "2000-01-01T00:00:00Z"}, ExitConflict},
+		{"missing required flag", []string{"add", "-n", "ls"}, ExitValidation},
+		{"unknown flag", []string{"list", "--frobnicate"}, ExitValidation},
+		{"unknown command", []string{"frobnicate"}, ExitValidation},
+		{"run failure", []string{"run", "-c", "false"}, ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cleanup := setupTestCLI(t)
+			defer cleanup()
+
+			ctx := context.Background()
+			_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+				Command:     "ls -la",
+				ToolName:    "ls",
+				Description: "list all",
+			})
+			_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+				Command:     "false",
+				ToolName:    "false",
+				Description: "always fails",
+			})
+
+			rootCmd.SetArgs(tt.args)
+			var code int
+			_ = captureOutput(func() {
+				code = run()
+			})
+
+			if code != tt.expected {
+				t.Errorf("Expected exit code %d, got %d", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestCLIQuiet(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	commands := [][]string{
+		{"add", "-n", "ls", "-c", "ls -la", "-d", "list all", "--quiet"},
+		{"get", "-c", "ls -la", "-q"},
+		{"edit", "-c", "ls -la", "-d", "list everything", "-q"},
+		{"remove", "-c", "ls -la", "-q"},
+		{"list", "-q"},
+	}
+
+	for _, args := range commands {
+		rootCmd.SetArgs(args)
+		output := captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Errorf("%v failed: %v", args, err)
+			}
+		})
+		if output != "" {
+			t.Errorf("Expected no output for %v, got: %q", args, output)
+		}
+	}
+
+	// Without --quiet the bookmark is printed
+	quiet = false
+	_, _ = svc.CreateBookmark(context.Background(), dto.CreateBookmarkRequest{Command: "pwd", ToolName: "pwd", Description: "cwd"})
+	rootCmd.SetArgs([]string{"get", "-c", "pwd"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Get failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "description: cwd") {
+		t.Errorf("Expected bookmark fields, got: %q", output)
+	}
+}
+
+func TestCLIListPages(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, command := range []string{"echo one", "echo two", "echo three"} {
+		if _, err := svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: command, ToolName: "echo", Description: "print"}); err != nil {
+			t.Fatalf("Failed to create bookmark: %v", err)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"list", "--limit", "2"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("List failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "echo two") || strings.Contains(output, "echo three") {
+		t.Errorf("Expected first page of two bookmarks, got: %q", output)
+	}
+	if !strings.Contains(output, "--cursor 2") {
+		t.Errorf("Expected next page hint, got: %q", output)
+	}
+
+	rootCmd.SetArgs([]string{"list", "--limit", "2", "--cursor", "2"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("List failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "echo three") || strings.Contains(output, "echo one") {
+		t.Errorf("Expected last page with the third bookmark, got: %q", output)
+	}
+
+	rootCmd.SetArgs([]string{"list", "--cursor", "bogus"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an invalid cursor, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLIListForLauncher(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if _, err := svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "list containers"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+
+	for _, format := range []string{"alfred", "raycast"} {
+		rootCmd.SetArgs([]string{"list", "--format", format})
+		output := captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Errorf("List failed: %v", err)
+			}
+		})
+
+		var out struct {
+			Items []struct {
+				Title    string `json:"title"`
+				Subtitle string `json:"subtitle"`
+				Arg      string `json:"arg"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(output), &out); err != nil {
+			t.Fatalf("Expected JSON for %s, got %q: %v", format, output, err)
+		}
+		if len(out.Items) != 1 || out.Items[0].Title != "list containers" || out.Items[0].Subtitle != "docker ps" || out.Items[0].Arg != "docker ps" {
+			t.Errorf("Unexpected %s items: %+v", format, out.Items)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"list", "--format", "spotlight"})
+	captureOutput(func() {
+		if code := run(); code != ExitValidation {
+			t.Errorf("Expected exit code %d for an unknown format, got %d", ExitValidation, code)
+		}
+	})
+}
+
+func TestCLIGetRelated(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, req := range []dto.CreateBookmarkRequest{
+		{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "list all pods"},
+		{Command: "kubectl get svc", ToolName: "kubectl", Description: "list services"},
+		{Command: "du -sh *", ToolName: "du", Description: "disk usage"},
+	} {
+		if _, err := svc.CreateBookmark(ctx, req); err != nil {
+			t.Fatalf("Failed to create bookmark: %v", err)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "kubectl get pods -A"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Get failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Related:") || !strings.Contains(output, "kubectl get svc") {
+		t.Errorf("Expected related bookmark, got: %q", output)
+	}
+	if strings.Contains(output, "du -sh") {
+		t.Errorf("Expected unrelated bookmark to be omitted, got: %q", output)
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "kubectl get pods -A", "--related", "0"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Get failed: %v", err)
+		}
+	})
+	if strings.Contains(output, "Related:") {
+		t.Errorf("Expected --related 0 to hide related bookmarks, got: %q", output)
+	}
+}
+
+func TestCLICaptureAndTriage(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+	cfg.CaptureFilePath = filepath.Join(filepath.Dir(filePath), "captured.jsonl")
+
+	for _, args := range [][]string{
+		{"capture", "record", "--", "kubectl get pods -A"},
+		{"capture", "record", "--", "ls -la"},
+		{"capture", "record", "--", " secret-command --token abc"},
+		{"capture", "record", "--", "tools list"},
+		{"capture", "record", "--", "git log --oneline"},
+	} {
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+
+	// Already bookmarked commands are not offered again
+	_, _ = svc.CreateBookmark(context.Background(), dto.CreateBookmarkRequest{Command: "ls -la", ToolName: "ls", Description: "list all"})
+
+	// Most recent first: bookmark git log with the suggested tool name, dismiss kubectl
+	rootCmd.SetIn(strings.NewReader("y\n\nshort git log\nn\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"triage"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Triage failed: %v", err)
+		}
+	})
+
+	if strings.Contains(output, "secret-command") || strings.Contains(output, "tools list") {
+		t.Errorf("Hidden commands and tools invocations must not be captured: %s", output)
+	}
+	if !strings.Contains(output, "[1/2] git log --oneline") || !strings.Contains(output, "Tool name [git]") {
+		t.Errorf("Unexpected triage output: %s", output)
+	}
+
+	bookmark, err := svc.GetBookmark(context.Background(), "git log --oneline")
+	if err != nil {
+		t.Fatalf("Promoted command not bookmarked: %v", err)
+	}
+	if bookmark.ToolName != "git" || bookmark.Description != "short git log" {
+		t.Errorf("Unexpected bookmark: %+v", bookmark)
+	}
+
+	// Everything was reviewed, so nothing is left
+	rootCmd.SetArgs([]string{"triage"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Triage failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "No captured commands to triage") {
+		t.Errorf("Expected empty staging area, got: %s", output)
+	}
+}
+
+func TestCLIDedupeCanonical(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	ctx := context.Background()
+	_ = repo.Create(ctx, &models.Bookmark{Command: "docker ps -a", ToolName: "docker", Description: "all containers"})
+	_ = repo.Create(ctx, &models.Bookmark{Command: "docker ps --all", ToolName: "docker", Description: "list all containers"})
+
+	rootCmd.SetArgs([]string{"dedupe", "--canonical"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Dedupe failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, `"docker ps -a", "docker ps --all" -> "docker ps --all"`) {
+		t.Errorf("Expected the spellings to be merged, got: %s", output)
+	}
+	if bookmarks, _ := repo.List(ctx); len(bookmarks) != 1 || bookmarks[0].Command != "docker ps --all" {
+		t.Errorf("Expected one canonical bookmark, got %+v", bookmarks)
+	}
+}
+
+func TestCLIDedupeCommand(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	// Duplicates stored before commands were normalized
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	ctx := context.Background()
+	_ = repo.Create(ctx, &models.Bookmark{Command: "kubectl  get pods", ToolName: "kubectl", Description: "pods"})
+	_ = repo.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+
+	rootCmd.SetArgs([]string{"dedupe", "--dry-run"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Dedupe dry run failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, `"kubectl  get pods", "kubectl get pods" -> "kubectl get pods"`) {
+		t.Errorf("Expected planned change, got: %s", output)
+	}
+	if bookmarks, _ := repo.List(ctx); len(bookmarks) != 2 {
+		t.Errorf("Dry run must not change the store, got %d bookmarks", len(bookmarks))
+	}
+
+	rootCmd.SetArgs([]string{"dedupe", "--dry-run=false"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Dedupe failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "0 renamed, 1 merged") {
+		t.Errorf("Expected summary, got: %s", output)
+	}
+	if bookmarks, _ := repo.List(ctx); len(bookmarks) != 1 {
+		t.Errorf("Expected duplicates to be merged, got %d bookmarks", len(bookmarks))
+	}
+}
+
+func TestCLIExportHTML(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+
+	rootCmd.SetArgs([]string{"export", "--format", "html"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Export command failed: %v", err)
+		}
+	})
+	for _, want := range []string{"<!DOCTYPE html>", "<h2>docker</h2>", "<code>kubectl get pods</code>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+
+	outPath := filepath.Join(filepath.Dir(filePath), "cheatsheet.html")
+	rootCmd.SetArgs([]string{"export", "-o", outPath, "--title", "Team tools"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Export command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully exported 2 bookmarks") {
+		t.Errorf("Expected confirmation, got: %s", output)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Expected export file: %v", err)
+	}
+	if !strings.Contains(string(data), "<title>Team tools</title>") {
+		t.Errorf("Expected title in export file, got: %s", data)
+	}
+
+	rootCmd.SetArgs([]string{"export", "-f", "markdown", "-o", "", "--title", "Team tools"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Export command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "# Team tools\n\n## docker\n") {
+		t.Errorf("Expected Markdown sheet, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"export", "--format", "docx", "-o", ""})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an unknown format, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLIReview(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	repo, _ := yaml.NewYAMLBookmarkRepository(filePath)
+	queue, err := yaml.NewYAMLBookmarkRepository(filepath.Join(filepath.Dir(filePath), "pending.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to create review queue: %v", err)
+	}
+	Initialize(service.NewBookmarkService(repo, service.WithReviewQueue(queue)), &config.Config{StorageFilePath: filePath})
+
+	for _, args := range [][]string{
+		{"add", "-n", "kubectl", "-c", "kubectl get pods -A", "-d", "list all pods"},
+		{"add", "-n", "rm", "-c", "rm -rf /tmp/cache", "-d", "clear cache"},
+	} {
+		rootCmd.SetArgs(args)
+		output := captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("Add command failed: %v", err)
+			}
+		})
+		if !strings.Contains(output, "Submitted command: "+args[4]) {
+			t.Errorf("Expected submission message, got: %s", output)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"list"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("List command failed: %v", err)
+		}
+	})
+	if strings.Contains(output, "kubectl get pods -A") {
+		t.Errorf("Expected pending bookmarks to be hidden from the list, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"review", "list"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Review list failed: %v", err)
+		}
+	})
+	for _, want := range []string{"kubectl get pods -A", "rm -rf /tmp/cache"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in pending list, got: %s", want, output)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"review", "approve"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d without commands, got %d", ExitValidation, code)
+	}
+
+	rootCmd.SetArgs([]string{"review", "reject", "rm -rf /tmp/cache"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Review reject failed: %v", err)
+		}
+	})
+	rootCmd.SetArgs([]string{"review", "approve", "--all"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Review approve failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Approved command: kubectl get pods -A") {
+		t.Errorf("Expected approval message, got: %s", output)
+	}
+
+	ctx := context.Background()
+	if _, err := svc.GetBookmark(ctx, "kubectl get pods -A"); err != nil {
+		t.Errorf("Expected approved bookmark in the store: %v", err)
+	}
+	if _, err := svc.GetBookmark(ctx, "rm -rf /tmp/cache"); err == nil {
+		t.Error("Expected rejected bookmark to stay out of the store")
+	}
+
+	rootCmd.SetArgs([]string{"review", "reject", "rm -rf /tmp/cache"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a command that is not pending, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIPick(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "list all pods"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl logs -f <pod>", ToolName: "kubectl", Description: "follow pod logs"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"unique match", []string{"pick", "--query", "containers"}, "docker ps\n"},
+		{"best match", []string{"pick", "--query", "list pods", "--first"}, "kubectl get pods -A\n"},
+		{"match by index", []string{"pick", "--query", "pod", "--index", "2"}, "kubectl logs -f <pod>\n"},
+		{"list index", []string{"pick", "--index", "3"}, "docker ps\n"},
+		{"alias", []string{"pick", "--query", "follow", "--alias", "logs", "--shell", "zsh"}, "alias logs='kubectl logs -f <pod>'\n"},
+		{"fish alias", []string{"pick", "--index", "3", "--alias", "dps", "--shell", "/usr/bin/fish"}, "alias dps 'docker ps'\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Initialize(svc, cfg)
+			rootCmd.SetArgs(tt.args)
+			output := captureOutput(func() {
+				if err := rootCmd.Execute(); err != nil {
+					t.Errorf("Pick command failed: %v", err)
+				}
+			})
+			if output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, output)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"pick", "--query", "pod", "--index", "0"}, ExitConflict},
+		{[]string{"pick", "--query", "terraform"}, ExitNotFound},
+		{[]string{"pick", "--query", "pod", "--index", "9"}, ExitNotFound},
+		{[]string{"pick", "--query", ""}, ExitValidation},
+		{[]string{"pick", "--index", "3", "--alias", "d ps"}, ExitValidation},
+		{[]string{"pick", "--index", "3", "--alias", "dps", "--shell", "tcsh"}, ExitValidation},
+	} {
+		Initialize(svc, cfg)
+		rootCmd.SetArgs(tt.args)
+		captureOutput(func() {
+			if code := run(); code != tt.code {
+				t.Errorf("Expected exit code %d for %v, got %d", tt.code, tt.args, code)
+			}
+		})
+	}
+}
+
+func TestCLIImportRecipes(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	projectDir := filepath.Join(filepath.Dir(filePath), "webapp")
+	if err := os.Mkdir(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	justfile := filepath.Join(projectDir, "justfile")
+	if err := os.WriteFile(justfile, []byte("# Start the dev server\ndev port:\n    npm run dev -- --port {{port}}\n\nlint:\n    npm run lint\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	makefile := filepath.Join(projectDir, "Makefile")
+	if err := os.WriteFile(makefile, []byte("build: ## Build the image\n\tdocker build .\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"import", "--from", "justfile", justfile})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Import command failed: %v", err)
+		}
+	})
+	rootCmd.SetArgs([]string{"import", "--from", "makefile", "--tags", "docker", makefile})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Import command failed: %v", err)
+		}
+	})
+
+	tests := []struct {
+		command     string
+		tool        string
+		description string
+		tags        []string
+	}{
+		{"just --justfile " + justfile + " dev <port>", "just", "Start the dev server", []string{"webapp"}},
+		{"just --justfile " + justfile + " lint", "just", "Run the lint recipe of webapp", []string{"webapp"}},
+		{"make -C " + projectDir + " build", "make", "Build the image", []string{"docker", "webapp"}},
+	}
+	for _, tt := range tests {
+		resp, err := svc.GetBookmark(context.Background(), tt.command)
+		if err != nil {
+			t.Errorf("Expected bookmark %q: %v", tt.command, err)
+			continue
+		}
+		if resp.ToolName != tt.tool || resp.Description != tt.description || strings.Join(resp.Tags, ",") != strings.Join(tt.tags, ",") {
+			t.Errorf("Unexpected bookmark for %q: %+v", tt.command, resp)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"import", "--from", "gradle", makefile},
+		{"import", "--tags", "docker", filePath},
+	} {
+		Initialize(svc, cfg)
+		rootCmd.SetArgs(args)
+		captureOutput(func() {
+			if code := run(); code != ExitValidation {
+				t.Errorf("Expected exit code %d for %v, got %d", ExitValidation, args, code)
+			}
+		})
+	}
+}
+
+func TestCLIScan(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	projectDir := filepath.Join(filepath.Dir(filePath), "shop")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "compose.yaml"), []byte("services:\n  api:\n    image: shop/api\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	compose := "docker compose -f " + filepath.Join(projectDir, "compose.yaml")
+
+	// Accept the first proposal, decline the second, stop at the third
+	rootCmd.SetIn(strings.NewReader("y\nn\nq\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"scan", projectDir})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Scan command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Found 8 new commands") || !strings.Contains(output, "(compose.yaml)") {
+		t.Errorf("Expected proposals with their source, got: %s", output)
+	}
+
+	resp, err := svc.GetBookmark(context.Background(), compose+" up -d")
+	if err != nil {
+		t.Fatalf("Expected the accepted proposal to be bookmarked: %v", err)
+	}
+	if resp.ToolName != "docker" || strings.Join(resp.Tags, ",") != "shop" {
+		t.Errorf("Unexpected bookmark: %+v", resp)
+	}
+	if _, err := svc.GetBookmark(context.Background(), compose+" down"); err == nil {
+		t.Error("Expected the declined proposal not to be bookmarked")
+	}
+
+	// Already bookmarked commands are not proposed again
+	rootCmd.SetArgs([]string{"scan", projectDir, "--all"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Scan command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Found 7 new commands") || !strings.Contains(output, "Successfully added 7 commands for project: shop") {
+		t.Errorf("Expected the remaining proposals to be added, got: %s", output)
+	}
+}
+
+func TestCLIImportDryRun(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+
+	importPath := filepath.Join(filepath.Dir(filePath), "backup.yaml")
+	content := `bookmarks:
+  - command: docker ps
+    toolname: docker
+    description: list containers
+  - command: kubectl get pods
+    toolname: kubectl
+    description: list all pods
+  - command: helm list
+    toolname: helm
+    description: list releases
+`
+	if err := os.WriteFile(importPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"import", importPath, "--dry-run", "--on-conflict", "overwrite"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Import command failed: %v", err)
+		}
+	})
+	for _, want := range []string{
+		"+ helm list",
+		"~ kubectl get pods",
+		"    - description: list pods",
+		"    + description: list all pods",
+		"= docker ps (unchanged)",
+		"Summary: 1 added, 1 changed, 1 skipped (1 conflicts)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if _, err := svc.GetBookmark(ctx, "helm list"); err == nil {
+		t.Error("Dry run must not import bookmarks")
+	}
+
+	rootCmd.SetArgs([]string{"import", importPath, "--dry-run=false", "--on-conflict", "overwrite"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Import command failed: %v", err)
+		}
+	})
+	if pods, _ := svc.GetBookmark(ctx, "kubectl get pods"); pods == nil || pods.Description != "list all pods" {
+		t.Errorf("Expected conflict to be overwritten, got %+v", pods)
+	}
+	if _, err := svc.GetBookmark(ctx, "helm list"); err != nil {
+		t.Errorf("Expected helm list to be imported: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"import", filepath.Join(filepath.Dir(filePath), "missing.yaml")})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing file, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLISnapshotCommands(t *testing.T) {
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+	cfg.SnapshotDir = filepath.Join(filepath.Dir(filePath), "snapshots")
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+
+	rootCmd.SetArgs([]string{"snapshot", "create", "before"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Snapshot create failed: %v", err)
+		}
+	})
+
+	svc.DeleteBookmark(ctx, "kubectl get pods")
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "helm list", ToolName: "helm", Description: "list releases"})
+
+	rootCmd.SetArgs([]string{"snapshot", "diff", "before"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Snapshot diff failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "+ helm list") || !strings.Contains(output, "- kubectl get pods") {
+		t.Errorf("Expected added and removed bookmarks, got: %s", output)
+	}
+
+	rootCmd.SetIn(strings.NewReader("y\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"snapshot", "restore", "before", "--yes=false"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Snapshot restore failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Replace your store with snapshot 'before'? [y/N]") {
+		t.Errorf("Expected confirmation prompt, got: %s", output)
+	}
+	if _, err := svc.GetBookmark(ctx, "kubectl get pods"); err != nil {
+		t.Errorf("Expected restored bookmark: %v", err)
+	}
+	if _, err := svc.GetBookmark(ctx, "helm list"); err == nil {
+		t.Error("Expected bookmark added after the snapshot to be gone")
+	}
+
+	rootCmd.SetArgs([]string{"snapshot", "restore", "missing", "--yes"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing snapshot, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIDirectoryStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tools")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := yaml.OpenStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open directory store: %v", err)
+	}
+	Initialize(service.NewBookmarkService(repo), &config.Config{StorageFilePath: dir, SnapshotDir: filepath.Join(dir, "snapshots")})
+
+	rootCmd.SetArgs([]string{"add", "-n", "kubectl", "-c", "kubectl get pods", "-d", "list pods"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Add command failed: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(dir, "kubectl.yaml")); err != nil {
+		t.Errorf("Expected the bookmark in kubectl.yaml: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"snapshot", "create"})
+	var code int
+	stderr := captureStderr(func() { code = run() })
+	if code != ExitValidation || !strings.Contains(stderr, "directory store") {
+		t.Errorf("Expected snapshots of a directory store to be refused, got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestCLIMergeToolCommand(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "k8s", Description: "list pods"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get nodes", ToolName: "kubectl", Description: "list nodes"})
+
+	rootCmd.SetArgs([]string{"merge-tool", "k8s", "kubectl"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Merge-tool command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "reassigned 1 examples from k8s to kubectl") {
+		t.Errorf("Expected summary, got: %s", output)
+	}
+
+	example, _ := svc.GetBookmark(ctx, "kubectl get pods")
+	if example.ToolName != "kubectl" {
+		t.Errorf("Expected tool kubectl, got %s", example.ToolName)
+	}
+
+	rootCmd.SetArgs([]string{"merge-tool", "k8s", "kubectl"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for unknown tool, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIToolsCommand(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "containers"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "git status", ToolName: "git", Description: "status"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "git log", ToolName: "git", Description: "log"})
+
+	for _, args := range [][]string{{"tools"}, {"list", "--tools"}} {
+		rootCmd.SetArgs(args)
+		output := captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Errorf("%v failed: %v", args, err)
+			}
+		})
+
+		gitIdx, dockerIdx := strings.Index(output, "git"), strings.Index(output, "docker")
+		if gitIdx < 0 || dockerIdx < 0 || gitIdx > dockerIdx {
+			t.Errorf("%v: expected tools sorted by count, got: %s", args, output)
+		}
+		if !strings.Contains(output, "Total: 2 tools") {
+			t.Errorf("%v: expected total, got: %s", args, output)
+		}
+	}
+
+	// Reset the persistent flag for later tests
+	listToolCounts = false
+}
+
+func TestCLIDuplicateCommand(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods -n dev", ToolName: "kubectl", Description: "list dev pods", Tags: []string{"k8s"}})
+
+	rootCmd.SetArgs([]string{"duplicate", "-c", "pods -n dev", "-n", "kubectl get pods -n prod", "-d", "list prod pods"})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Duplicate command failed: %v", err)
+		}
+	})
+
+	variant, err := svc.GetBookmark(ctx, "kubectl get pods -n prod")
+	if err != nil {
+		t.Fatalf("Expected duplicate to be created: %v", err)
+	}
+	if variant.ToolName != "kubectl" || variant.Description != "list prod pods" || len(variant.Tags) != 1 {
+		t.Errorf("Expected copied fields with new description, got %+v", variant)
+	}
+
+	// Without --new-command the fields are asked for, keeping the original's values on enter
+	rootCmd.SetIn(strings.NewReader("kubectl get pods -n test\n\n\n"))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"duplicate", "-c", "kubectl get pods -n dev", "-n", "", "-d", ""})
+	captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Interactive duplicate failed: %v", err)
+		}
+	})
+
+	variant, err = svc.GetBookmark(ctx, "kubectl get pods -n test")
+	if err != nil {
+		t.Fatalf("Expected interactive duplicate to be created: %v", err)
+	}
+	if variant.Description != "list dev pods" {
+		t.Errorf("Expected original description, got %q", variant.Description)
+	}
+}
+
+func TestCLISelfUpdateCheck(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.0", "assets": []}`))
+	}))
+	defer server.Close()
+
+	newUpdater = func() *selfupdate.Updater {
+		u := selfupdate.New()
+		u.ReleaseURL = server.URL
+		return u
+	}
+	defer func() { newUpdater = selfupdate.New }()
+
+	oldVersion := Version
+	defer func() { Version = oldVersion }()
+
+	Version = "1.1.0"
+	rootCmd.SetArgs([]string{"self-update", "--check"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Self-update check failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Update available: 1.1.0 -> 1.2.0") {
+		t.Errorf("Expected available update, got: %s", output)
+	}
+
+	Version = "1.2.0"
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Self-update check failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "up to date") {
+		t.Errorf("Expected up to date, got: %s", output)
+	}
+	selfUpdateCheck = false
+}
+
+func TestCLIMetricsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	historyPath := filepath.Join(tmpDir, "history.jsonl")
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	testSvc := service.NewBookmarkService(repo, service.WithAuditLog(audit.NewFileLog(historyPath)))
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "containers"})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "git status", ToolName: "git", Description: "status"})
+	_ = svc.RecordRun(ctx, "docker ps")
+	_, _ = svc.SearchBookmarks(ctx, "containers")
+
+	rootCmd.SetArgs([]string{"metrics", "--since", "7d"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Metrics command failed: %v", err)
+		}
+	})
+
+	for _, expected := range []string{"Added:    2", "Runs:     1", "Searches: 1", "1  docker ps", "1  containers", "Not run: 1 of 2 bookmarks", "  git status"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"metrics", "--since", "soon"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for invalid period, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLIDue(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	historyPath := filepath.Join(tmpDir, "history.jsonl")
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	testSvc := service.NewBookmarkService(repo, service.WithAuditLog(audit.NewFileLog(historyPath)))
+	Initialize(testSvc, &config.Config{StorageFilePath: filePath, HistoryFilePath: historyPath})
+
+	rootCmd.SetArgs([]string{"due"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Due command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "No bookmarks are due.") {
+		t.Errorf("Expected nothing due, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"add", "-n", "certbot", "-d", "renew certificates", "-c", "true", "--remind-every", "30d"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"due"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "certbot") || !strings.Contains(output, "30d") || !strings.Contains(output, "never") {
+		t.Errorf("Expected the never run bookmark to be due, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", "true"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Run command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"due"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "No bookmarks are due.") {
+		t.Errorf("Expected the reminder to reset after a run, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"edit", "-c", "true", "--new-remind-every", "sometimes"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an invalid period, got %d", ExitValidation, code)
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "true"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "reminder:    every 30d") {
+		t.Errorf("Expected the reminder in the bookmark, got: %s", output)
+	}
+}
+
+func TestCLICron(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	tmpDir := t.TempDir()
+
+	_, _ = svc.CreateBookmark(context.Background(), dto.CreateBookmarkRequest{
+		Command:     "certbot renew",
+		ToolName:    "certbot",
+		Description: "renew certificates",
+	})
+
+	logFile := filepath.Join(tmpDir, "logs", "certbot.log")
+	rootCmd.SetArgs([]string{"cron", "-c", "certbot renew", "-s", "0 3 * * *", "--log", logFile})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Cron command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "# tools: certbot renew\n0 3 * * * ") ||
+		!strings.Contains(output, " run --quiet --yes -c 'certbot renew' >> '"+logFile+"' 2>&1") {
+		t.Errorf("Expected the cron entry, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"cron", "-c", "certbot renew", "-s", "every night"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an invalid schedule, got %d", ExitValidation, code)
+	}
+
+	// A fake crontab keeps the installed table in a file
+	binDir := t.TempDir()
+	crontabFile := filepath.Join(tmpDir, "crontab")
+	script := "#!/bin/sh\nif [ \"$1\" = -l ]; then cat " + crontabFile + " 2>/dev/null || exit 1; else cat > " + crontabFile + "; fi\n"
+	if err := os.WriteFile(filepath.Join(binDir, "crontab"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake crontab: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, schedule := range []string{"@daily", "@weekly"} {
+		rootCmd.SetArgs([]string{"cron", "-c", "certbot renew", "-s", schedule, "--log", logFile, "--install"})
+		_ = captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Errorf("Cron install failed: %v", err)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(crontabFile)
+	if err != nil {
+		t.Fatalf("Expected the crontab to be installed: %v", err)
+	}
+	if strings.Count(string(data), "# tools: certbot renew") != 1 || !strings.Contains(string(data), "@weekly ") {
+		t.Errorf("Expected the entry to be replaced, got: %s", data)
+	}
+	if _, err := os.Stat(filepath.Dir(logFile)); err != nil {
+		t.Errorf("Expected the log directory to be created: %v", err)
+	}
+}
+
+func TestCLIHost(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	rootCmd.SetArgs([]string{"add", "-n", "ls", "-d", "list files", "-c", "ls -la"})
+	_ = captureOutput(func() { _ = rootCmd.Execute() })
+	rootCmd.SetArgs([]string{"add", "-n", "systemctl", "-d", "restart the database", "-c", "systemctl restart postgresql", "--host", "prod-db"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"list", "--host", "prod-db"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("List command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "systemctl restart postgresql") || strings.Contains(output, "ls -la") {
+		t.Errorf("Expected only the bookmark of the host, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"run", "-c", "systemctl restart postgresql", "--print"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if strings.TrimSpace(output) != "ssh prod-db 'systemctl restart postgresql'" {
+		t.Errorf("Expected the ssh command, got: %s", output)
+	}
+
+	// The variant for this operating system is not the one to run on the host
+	rootCmd.SetArgs([]string{"edit", "-c", "systemctl restart postgresql", "--new-variant", runtime.GOOS + "=brew services restart postgresql"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Edit command failed: %v", err)
+		}
+	})
+	rootCmd.SetArgs([]string{"run", "-c", "systemctl restart postgresql", "--print"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if strings.TrimSpace(output) != "ssh prod-db 'systemctl restart postgresql'" {
+		t.Errorf("Expected the stored command on the host, got: %s", output)
+	}
+
+	// Declining the confirmation runs nothing
+	Initialize(svc, cfg)
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	rootCmd.SetArgs([]string{"run", "-c", "systemctl restart postgresql"})
+	var runErr error
+	_ = captureOutput(func() { runErr = rootCmd.Execute() })
+	rootCmd.SetIn(nil)
+	if !errors.Is(runErr, errAborted) {
+		t.Errorf("Expected the run to be aborted, got %v", runErr)
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "systemctl restart postgresql"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "host:        prod-db") {
+		t.Errorf("Expected the host in the bookmark, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"edit", "-c", "systemctl restart postgresql", "--new-host", "-oProxyCommand=sh"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an invalid host, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLIVariants(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	rootCmd.SetArgs([]string{"add", "-n", "clipboard", "-d", "copy to the clipboard", "-c", "xclip -selection clipboard",
+		"--variant", runtime.GOOS + "=cat", "--variant", "plan9=snarf"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an unknown OS, got %d", ExitValidation, code)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"add", "-n", "clipboard", "-d", "copy to the clipboard", "-c", "xclip -selection clipboard",
+		"--variant", runtime.GOOS + "=cat"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"run", "-c", "xclip -selection clipboard", "--print"})
+	output := captureOutput(func() { _ = rootCmd.Execute() })
+	if strings.TrimSpace(output) != "cat" {
+		t.Errorf("Expected the variant of this OS, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "xclip -selection clipboard"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "on "+runtime.GOOS+":") {
+		t.Errorf("Expected the variant in the bookmark, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"edit", "-c", "xclip", "--new-variant", runtime.GOOS + "="})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Edit command failed: %v", err)
+		}
+	})
+	bookmark, _ := svc.GetBookmark(context.Background(), "xclip -selection clipboard")
+	if len(bookmark.Variants) != 0 {
+		t.Errorf("Expected the variant to be removed, got %v", bookmark.Variants)
+	}
+}
+
+func TestCLIRequires(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	t.Setenv("TOOLS_TEST_PROFILE", "")
+
+	rootCmd.SetArgs([]string{"add", "-n", "aws", "-d", "list buckets", "-c", "aws s3 ls",
+		"--requires", "TOOLS_TEST_PROFILE,tools-test-missing-binary"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"get", "-c", "aws s3 ls"})
+	output := captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "requires:    TOOLS_TEST_PROFILE, tools-test-missing-binary") {
+		t.Errorf("Expected the requirements in the bookmark, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"pick", "--query", "buckets", "--first"})
+	var stdout string
+	stderr := captureStderr(func() {
+		stdout = captureOutput(func() { _ = rootCmd.Execute() })
+	})
+	if strings.TrimSpace(stdout) != "aws s3 ls" {
+		t.Errorf("Expected the command to be picked anyway, got: %s", stdout)
+	}
+	if !strings.Contains(stderr, "Warning: missing requirements: TOOLS_TEST_PROFILE, tools-test-missing-binary") {
+		t.Errorf("Expected a warning about the requirements, got: %s", stderr)
+	}
+
+	t.Setenv("TOOLS_TEST_PROFILE", "dev")
+	rootCmd.SetArgs([]string{"edit", "-c", "aws s3 ls", "--new-requires", "TOOLS_TEST_PROFILE"})
+	_ = captureOutput(func() { _ = rootCmd.Execute() })
+	rootCmd.SetArgs([]string{"pick", "--query", "buckets", "--first"})
+	stderr = captureStderr(func() {
+		_ = captureOutput(func() { _ = rootCmd.Execute() })
+	})
+	if strings.Contains(stderr, "Warning") {
+		t.Errorf("Expected no warning once the requirements are met, got: %s", stderr)
+	}
+}
+
+func TestCLIPrivate(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "pods", Tags: []string{"k8s"}})
+	rootCmd.SetArgs([]string{"add", "-n", "kubectl", "-d", "my cluster", "-c", "kubectl config use-context home", "--tags", "k8s", "--private"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+
+	rootCmd.SetArgs([]string{"export", "-f", "json"})
+	output := captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "kubectl get pods") || strings.Contains(output, "use-context home") {
+		t.Errorf("Expected the private bookmark to be left out of the export, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"export", "-f", "json", "--include-private"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "use-context home") {
+		t.Errorf("Expected --include-private to export the private bookmark, got: %s", output)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "k8s.bundle.yaml")
+	rootCmd.SetArgs([]string{"bundle", "create", "k8s", "--tag", "k8s", "-o", bundlePath})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Bundle create failed: %v", err)
+		}
+	})
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %v", err)
+	}
+	if !strings.Contains(string(data), "kubectl get pods") || strings.Contains(string(data), "use-context home") {
+		t.Errorf("Expected the private bookmark to be left out of the bundle, got: %s", data)
+	}
+
+	rootCmd.SetArgs([]string{"edit", "-c", "use-context home", "--new-private=false"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Edit command failed: %v", err)
+		}
+	})
+	if bookmark, _ := svc.GetBookmark(ctx, "kubectl config use-context home"); bookmark == nil || bookmark.Private {
+		t.Errorf("Expected the bookmark to be shared again, got %+v", bookmark)
+	}
+}
+
+func TestCLIListAuthor(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rootCmd.SetArgs([]string{"list"})
+	output := captureOutput(func() { _ = rootCmd.Execute() })
+	if strings.Contains(output, "AUTHOR") {
+		t.Errorf("Expected no author column without recorded authors, got: %s", output)
+	}
+
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "df -h", ToolName: "df", Description: "disk usage", Author: "alice"})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "free -m", ToolName: "free", Description: "memory", Author: "bob"})
+
+	rootCmd.SetArgs([]string{"list", "--author", "alice"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("List command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "AUTHOR") || !strings.Contains(output, "alice") || strings.Contains(output, "free -m") {
+		t.Errorf("Expected only the bookmarks of alice with an author column, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"list", "--author", "carol"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "No examples found by author 'carol'") {
+		t.Errorf("Expected no bookmarks of carol, got: %s", output)
+	}
+
+	rootCmd.SetArgs([]string{"get", "-c", "free -m"})
+	output = captureOutput(func() { _ = rootCmd.Execute() })
+	if !strings.Contains(output, "author:      bob") {
+		t.Errorf("Expected the author in the bookmark, got: %s", output)
+	}
+}
+
+func TestCLIPrune(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	archivePath := filepath.Join(tmpDir, "archive.yaml")
+	ctx := context.Background()
+
+	repo, err := yaml.NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	// Stored before the history was started, so never used as far as it knows
+	_ = repo.Create(ctx, &models.Bookmark{Command: "netstat -tulpn", ToolName: "netstat", Description: "ports"})
+
+	testCfg := &config.Config{StorageFilePath: filePath, StoreLimit: config.StoreLimitConfig{MaxEntries: 2}}
+	testSvc := service.NewBookmarkService(repo,
+		service.WithAuditLog(audit.NewFileLog(filepath.Join(tmpDir, "history.jsonl"))),
+		service.WithOnChange(StoreChanged))
+	Initialize(testSvc, testCfg)
+
+	// Growing the store beyond the limit warns about it
+	rootCmd.SetArgs([]string{"add", "-n", "ss", "-d", "ports", "-c", "ss -tulpn"})
+	stderr := captureStderr(func() {
+		_ = captureOutput(func() { run() })
+	})
+	if strings.Contains(stderr, "Warning") {
+		t.Errorf("Expected no warning within the limit, got: %s", stderr)
+	}
+	rootCmd.SetArgs([]string{"add", "-n", "make", "-d", "tests", "-c", "make test"})
+	stderr = captureStderr(func() {
+		_ = captureOutput(func() { run() })
+	})
+	if !strings.Contains(stderr, "the store has grown to 3 bookmarks") || !strings.Contains(stderr, "tools prune --unused-for 180d") {
+		t.Errorf("Expected a warning about the store size, got: %s", stderr)
+	}
+
+	rootCmd.SetArgs([]string{"prune", "--unused-for", "30d", "--dry-run"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Prune command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "netstat -tulpn") || !strings.Contains(output, "never used") || strings.Contains(output, "ss -tulpn") {
+		t.Errorf("Expected only the unused bookmark to be listed, got: %s", output)
+	}
+	if !strings.Contains(output, "Dry run: 1 bookmarks would be removed") {
+		t.Errorf("Expected a dry run summary, got: %s", output)
+	}
+
+	Initialize(testSvc, testCfg)
+	rootCmd.SetArgs([]string{"prune", "--unused-for", "30d", "--archive", archivePath})
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); !errors.Is(err, errAborted) {
+			t.Errorf("Expected declining to abort, got %v", err)
+		}
+	})
+	rootCmd.SetIn(nil)
+	if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+		t.Error("Expected no archive after aborting")
+	}
+
+	rootCmd.SetArgs([]string{"prune", "--unused-for", "30d", "--archive", archivePath, "-y"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Prune command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully removed 1 stale bookmarks, archived in "+archivePath) {
+		t.Errorf("Expected a summary, got: %s", output)
+	}
+	if _, err := svc.GetBookmark(ctx, "netstat -tulpn"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected the unused bookmark to be removed, got %v", err)
+	}
+	archived, err := yaml.NewReadOnlyYAMLBookmarkRepository(archivePath).GetByCommand(ctx, "netstat -tulpn")
+	if err != nil || archived.Description != "ports" {
+		t.Errorf("Expected the removed bookmark in the archive, got %+v, %v", archived, err)
+	}
+
+	rootCmd.SetArgs([]string{"prune", "--unused-for", "soon"})
+	if err := rootCmd.Execute(); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error for an invalid period, got %v", err)
+	}
+}
+
+func TestCLIPruneMissingToolInteractive(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+	archivePath := filepath.Join(t.TempDir(), "archive.yaml")
+
+	for _, req := range []dto.CreateBookmarkRequest{
+		{Command: "tools-test-missing-a --flag", ToolName: "a", Description: "first"},
+		{Command: "tools-test-missing-b", ToolName: "b", Description: "second"},
+		{Command: "tools-test-missing-c", ToolName: "c", Description: "third"},
+		{Command: "tools-test-missing-d", ToolName: "d", Description: "on a host", Host: "prod"},
+		{Command: "ls -la", ToolName: "ls", Description: "installed"},
+	} {
+		_, _ = svc.CreateBookmark(ctx, req)
+	}
+
+	rootCmd.SetArgs([]string{"prune", "--missing-tool", "--dry-run"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Prune command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "missing tools-test-missing-a") || strings.Contains(output, "ls -la") || strings.Contains(output, "tools-test-missing-d") {
+		t.Errorf("Expected the bookmarks of missing local tools, got: %s", output)
+	}
+	if !strings.Contains(output, "Dry run: 3 bookmarks would be removed") {
+		t.Errorf("Expected a dry run summary, got: %s", output)
+	}
+
+	// Archive the first, remove the second, keep the third
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"prune", "--missing-tool", "-i", "--archive", archivePath})
+	rootCmd.SetIn(strings.NewReader("a\ny\nn\n"))
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Prune command failed: %v", err)
+		}
+	})
+	rootCmd.SetIn(nil)
+	if !strings.Contains(output, "Successfully removed 2 stale bookmarks, 1 archived in "+archivePath) {
+		t.Errorf("Expected an interactive summary, got: %s", output)
+	}
+	for command, kept := range map[string]bool{"tools-test-missing-a --flag": false, "tools-test-missing-b": false, "tools-test-missing-c": true} {
+		if _, err := svc.GetBookmark(ctx, command); (err == nil) != kept {
+			t.Errorf("Expected %q kept=%t, got %v", command, kept, err)
+		}
+	}
+	if _, err := yaml.NewReadOnlyYAMLBookmarkRepository(archivePath).GetByCommand(ctx, "tools-test-missing-a --flag"); err != nil {
+		t.Errorf("Expected the archived bookmark in the archive: %v", err)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"prune"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("Expected an error without --unused-for or --missing-tool")
+	}
+}
+
+func TestCLIFilter(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "k", Description: "list pods"})
+	svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get nodes", ToolName: "k", Description: "list nodes"})
+
+	// The bookmarks pass through the command, changed ones are updated
+	rootCmd.SetArgs([]string{"filter", "--", "sed", `s/"tool_name":"k"/"tool_name":"kubectl"/`})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Filter command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "~ kubectl get pods") || !strings.Contains(output, "0 added, 2 updated, 0 removed") {
+		t.Errorf("Expected both bookmarks to be updated, got: %s", output)
+	}
+	if b, _ := svc.GetBookmark(ctx, "kubectl get nodes"); b == nil || b.ToolName != "kubectl" {
+		t.Errorf("Expected the tool name to be changed, got %+v", b)
+	}
+
+	// Bookmarks read from stdin; missing ones are removed
+	rootCmd.SetIn(strings.NewReader(`{"command":"kubectl get pods","tool_name":"kubectl","description":"list pods"}
+{"command":"kubectl get svc","tool_name":"kubectl","description":"list services"}
+`))
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetArgs([]string{"filter", "--delete-missing"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Filter command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "+ kubectl get svc") || !strings.Contains(output, "- kubectl get nodes") {
+		t.Errorf("Expected an added and a removed bookmark, got: %s", output)
+	}
+	if _, err := svc.GetBookmark(ctx, "kubectl get nodes"); err == nil {
+		t.Error("Expected the missing bookmark to be removed")
+	}
+
+	// An empty result doesn't remove everything, unknown fields are refused
+	for _, input := range []string{"", `{"command":"ls","tool":"ls"}`} {
+		rootCmd.SetIn(strings.NewReader(input))
+		rootCmd.SetArgs([]string{"filter", "--delete-missing"})
+		var code int
+		captureOutput(func() {
+			captureStderr(func() { code = run() })
+		})
+		if code != ExitValidation {
+			t.Errorf("Expected exit code %d for %q, got %d", ExitValidation, input, code)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"filter", "--dump"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Filter command failed: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || lines[0] != `{"command":"kubectl get pods","tool_name":"kubectl","description":"list pods"}` {
+		t.Errorf("Expected a JSON line per bookmark, got: %s", output)
+	}
+}
+
+func TestCLIReplace(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "pods"})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "containers"})
+
+	rootCmd.SetArgs([]string{"replace", "--find", "kubectl", "--replace", "k", "--dry-run"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Replace command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "- kubectl get pods\n+ k get pods") || !strings.Contains(output, "Dry run: 1 commands would change") {
+		t.Errorf("Expected a preview diff, got: %s", output)
+	}
+	if _, err := svc.GetBookmark(ctx, "k get pods"); err == nil {
+		t.Error("Expected the dry run not to change the command")
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"replace", "--find", "kubectl", "--replace", "k"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Replace command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully changed 1 commands") {
+		t.Errorf("Expected a summary, got: %s", output)
+	}
+	if _, err := svc.GetBookmark(ctx, "k get pods"); err != nil {
+		t.Errorf("Expected the replaced command: %v", err)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"replace", "--find", "(", "--replace", "x", "--regex"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for an invalid expression, got %d", ExitValidation, code)
+	}
+}
+
+func TestCLITag(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "kubectl get svc", ToolName: "kubectl", Description: "list services", Tags: []string{"k8s"}})
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+
+	rootCmd.SetArgs([]string{"tag", "add", "K8s", "--tool", "kubectl"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Tag command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully tagged 1 examples with 'k8s', 1 already had it") {
+		t.Errorf("Expected a summary, got: %s", output)
+	}
+	if bookmark, _ := svc.GetBookmark(ctx, "kubectl get pods"); !slices.Equal(bookmark.Tags, []string{"k8s"}) {
+		t.Errorf("Expected the tag to be added, got %v", bookmark.Tags)
+	}
+	if bookmark, _ := svc.GetBookmark(ctx, "docker ps"); len(bookmark.Tags) != 0 {
+		t.Errorf("Expected other tools to be untouched, got %v", bookmark.Tags)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"tag", "remove", "k8s", "--query", "list services"})
+	output = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Tag command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully removed 'k8s' from 1 examples") {
+		t.Errorf("Expected a summary, got: %s", output)
+	}
+	if bookmark, _ := svc.GetBookmark(ctx, "kubectl get svc"); len(bookmark.Tags) != 0 {
+		t.Errorf("Expected the tag to be removed, got %v", bookmark.Tags)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetArgs([]string{"tag", "add", "k8s", "--tool", "helm"})
+	if code := run(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for an unknown tool, got %d", ExitNotFound, code)
+	}
+}
+
+func TestCLIStdin(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rootCmd.SetIn(strings.NewReader("kubectl get pods -A\n"))
+	rootCmd.SetArgs([]string{"add", "-n", "kubectl", "-d", "list pods", "--stdin"})
+	_ = captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Add command failed: %v", err)
+		}
+	})
+	if _, err := svc.GetBookmark(ctx, "kubectl get pods -A"); err != nil {
+		t.Errorf("Expected the piped command to be bookmarked: %v", err)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetIn(strings.NewReader(`[{"command": "docker ps", "tool_name": "docker", "description": "list containers", "tags": ["containers"]}]`))
+	rootCmd.SetArgs([]string{"import", "--stdin", "--format", "json"})
+	output := captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Import command failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Successfully imported stdin: 1 added") {
+		t.Errorf("Expected an import summary, got: %s", output)
+	}
+	if bookmark, err := svc.GetBookmark(ctx, "docker ps"); err != nil || !slices.Equal(bookmark.Tags, []string{"containers"}) {
+		t.Errorf("Expected the piped bookmark with its tags, got %+v (%v)", bookmark, err)
+	}
+
+	Initialize(svc, cfg)
+	rootCmd.SetIn(strings.NewReader("bookmarks: [}"))
+	rootCmd.SetArgs([]string{"import", "--stdin"})
+	if code := run(); code != ExitValidation {
+		t.Errorf("Expected exit code %d for invalid YAML, got %d", ExitValidation, code)
+	}
+	rootCmd.SetIn(nil)
+}
+
+func TestCLIServe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM cannot be sent on windows")
+	}
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+	t.Setenv("TOOLS_TOKEN", "team-token")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		rootCmd.SetArgs([]string{"serve", "--addr", addr, "--quiet"})
+		done <- rootCmd.Execute()
+	}()
+
+	base := "http://" + addr
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get(base + "/healthz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected /healthz 200, got %d", resp.StatusCode)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Server did not come up: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if resp, err := http.Get(base + "/readyz"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected /readyz 200, got %v, %v", resp, err)
+	}
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"docker ps","tool_name":"docker","description":"list containers"}}}`
+	if resp, err := http.Post(base+"/mcp", "application/json", strings.NewReader(body)); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 from /mcp without the token, got %v, %v", resp, err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, base+"/mcp", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer team-token")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /mcp failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 from /mcp, got %d", resp.StatusCode)
+	}
+	req, _ = http.NewRequest(http.MethodGet, base+"/mcp", nil)
+	req.Header.Set("Authorization", "Bearer team-token")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET /mcp, got %v, %v", resp, err)
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected serve to stop cleanly on SIGTERM, got %v", err)
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("serve did not stop on SIGTERM")
+	}
+
+	if _, err := http.Get(base + "/healthz"); err == nil {
+		t.Error("Expected the server to be closed after SIGTERM")
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil || !strings.Contains(string(data), "docker ps") {
+		t.Errorf("Expected the bookmark to be persisted, got %q, %v", data, err)
+	}
+}
+
+func TestCLIServeRequiresTokenOffLoopback(t *testing.T) {
+	_, cleanup := setupTestCLI(t)
+	defer cleanup()
+	t.Setenv("TOOLS_TOKEN", "")
+
+	rootCmd.SetArgs([]string{"serve", "--addr", ":0"})
+	var code int
+	stderr := captureStderr(func() { code = run() })
+	if code != ExitValidation {
+		t.Errorf("Expected exit code %d without TOOLS_TOKEN on all interfaces, got %d", ExitValidation, code)
+	}
+	if !strings.Contains(stderr, "TOOLS_TOKEN") {
+		t.Errorf("Expected the error to name TOOLS_TOKEN, got %s", stderr)
+	}
+}
+
+func TestCLIServeUnwritableStore(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+	filePath, cleanup := setupTestCLI(t)
+	defer cleanup()
+
+	dir := filepath.Dir(filePath)
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0755)
+
+	rootCmd.SetArgs([]string{"serve", "--addr", "127.0.0.1:0"})
+	err := rootCmd.Execute()
+	if err == nil {
+		t.Fatal("Expected serve to fail on an unwritable store")
+	}
+	if !strings.Contains(err.Error(), filePath) || !strings.Contains(err.Error(), "uid "+strconv.Itoa(os.Getuid())) {
+		t.Errorf("Expected the error to name the store and uid, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// errAborted is returned when the user declines a confirmation prompt
+var errAborted = errors.New("aborted, nothing was changed")
+
+// confirm asks a yes/no question and reports whether the user answered yes
+// Anything but y or yes, including end of input, counts as no.
+func confirm(in io.Reader, question string) (bool, error) {
+	answer, _, err := prompt(bufio.NewReader(in), question+i18n.T(" [y/N]: "))
+	if err != nil {
+		return false, err
+	}
+	return isYes(answer), nil
+}
+
+// isYes reports whether an answer means yes, in English or the selected language
+func isYes(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "y", "yes", i18n.T("y"), i18n.T("yes"):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/cron"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cronCommand  string
+	cronSchedule string
+	cronLog      string
+	cronInstall  bool
+)
+
+func newCronCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cron",
+		Short: "Generate a cron entry that runs a bookmark",
+		Long: `Generate a crontab entry that runs a bookmark on a schedule through
+'tools run', so its runs are recorded in the history like any other. The output
+of each run is appended to a log file, ~/.config/tools/cron.log by default.
+
+The entry is printed; with --install it is added to your crontab instead,
+replacing an earlier entry for the same bookmark.`,
+		Example: `  tools cron -c "certbot renew" --schedule "0 3 * * *"
+  tools cron -c "docker system prune -f" --schedule @weekly --install`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if err := cron.ValidateSchedule(cronSchedule); err != nil {
+				return err
+			}
+			bookmark, err := svc.GetBookmark(ctx, cronCommand)
+			if err != nil {
+				return fmt.Errorf("failed to generate cron entry: %w", commandNotFound(ctx, err, cronCommand))
+			}
+			if runner.IsScript(bookmark.Command) {
+				return fmt.Errorf("%w: multi-line commands cannot be scheduled, save the script to a file and bookmark running it", models.ErrValidation)
+			}
+
+			job, err := cronJob(bookmark.Command)
+			if err != nil {
+				return fmt.Errorf("failed to generate cron entry: %w", err)
+			}
+
+			if !cronInstall {
+				fmt.Print(job.Lines())
+				return nil
+			}
+
+			if err := os.MkdirAll(filepath.Dir(job.LogFile), 0755); err != nil {
+				return fmt.Errorf("failed to create log directory: %w", err)
+			}
+			if err := cron.Install(job); err != nil {
+				return err
+			}
+			info("Successfully installed cron entry for: %s, logging to %s\n", bookmark.Command, job.LogFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&cronCommand, "command", "c", "", "Command of the bookmark to schedule (required)")
+	cmd.Flags().StringVarP(&cronSchedule, "schedule", "s", "", "Cron schedule, e.g. \"0 3 * * *\" or @daily (required)")
+	cmd.Flags().StringVar(&cronLog, "log", "", "File the output is appended to (default ~/.config/tools/cron.log)")
+	cmd.Flags().BoolVar(&cronInstall, "install", false, "Add the entry to your crontab instead of printing it")
+
+	_ = cmd.MarkFlagRequired("command")
+	_ = cmd.MarkFlagRequired("schedule")
+
+	return cmd
+}
+
+// cronJob builds the cron job running a bookmark with this binary
+func cronJob(command string) (cron.Job, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return cron.Job{}, fmt.Errorf("failed to locate the tools binary: %w", err)
+	}
+
+	// Nobody is there to confirm running on the host of a bookmark
+	args := []string{exe, "run", "--quiet", "--yes", "-c", command}
+	// cron starts with a minimal environment; keep pointing at the same config directory
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		args = append([]string{"env", "XDG_CONFIG_HOME=" + dir}, args...)
+	}
+
+	logFile := cronLog
+	if logFile == "" {
+		logFile = filepath.Join(config.GetConfigDir(), "cron.log")
+	}
+
+	return cron.Job{
+		Schedule: cronSchedule,
+		Command:  utils.JoinFor(utils.ShellPOSIX, args), // Control characters must not break the line of the entry
+		LogFile:  logFile,
+		Name:     command,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fgeck/tools/internal/repository/registry"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dedupeDryRun    bool
+	dedupeCanonical bool
+)
+
+func newDedupeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Normalize stored commands and merge whitespace duplicates",
+		Long: `Rewrite stored commands to their normalized form.
+
+Commands are trimmed and runs of unquoted spaces and tabs collapse to a
+single space, so 'kubectl  get pods' and 'kubectl get pods' are the same
+bookmark. New bookmarks are normalized automatically; this command
+reconciles bookmarks stored before. Duplicates are merged into one
+bookmark that keeps the tags of all of them.
+
+With --canonical, commands of known tools like kubectl and docker are also
+rewritten into their canonical form: long options sorted by name and quotes
+only where needed, so 'kubectl get pods -n dev' and
+'kubectl get pods --namespace dev' are merged too. Check the result with
+--dry-run first.`,
+		Example: `  tools dedupe --dry-run
+  tools dedupe
+  tools dedupe --canonical --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := registry.Open("yaml", cfg.StorageFilePath)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+
+			normalize := service.NormalizeStoredCommands
+			if dedupeCanonical {
+				normalize = service.CanonicalizeStoredCommands
+			}
+			result, err := normalize(context.Background(), repo, dedupeDryRun)
+			if err != nil {
+				return fmt.Errorf("failed to normalize commands: %w", err)
+			}
+
+			for _, change := range result.Changes {
+				fmt.Printf("%s -> %q\n", strings.Join(quoteAll(change.From), ", "), change.To)
+			}
+
+			if dedupeDryRun {
+				info("Dry run: %d would be renamed, %d would be merged\n", result.Renamed, result.Merged)
+				return nil
+			}
+			info("Normalized commands: %d renamed, %d merged\n", result.Renamed, result.Merged)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dedupeDryRun, "dry-run", false, "Only show what would change")
+	cmd.Flags().BoolVar(&dedupeCanonical, "canonical", false, "Also rewrite commands of known tools into their canonical form")
+
+	return cmd
+}
+
+// quoteAll quotes commands so their whitespace is visible
+func quoteAll(commands []string) []string {
+	quoted := make([]string, len(commands))
+	for i, command := range commands {
+		quoted[i] = fmt.Sprintf("%q", command)
+	}
+	return quoted
+}
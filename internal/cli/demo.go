@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/bundle"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/fgeck/tools/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func newDemoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "demo",
+		Short: "Try the TUI with sample bookmarks, without touching your store",
+		Long: `Start the TUI with the bookmarks of the starter packs, kept in memory only.
+Add, edit and delete as you like: nothing is written to your store or history,
+and everything is gone when you quit. Useful to try tools or record a screencast.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			demoSvc, err := newDemoService(context.Background())
+			if err != nil {
+				return err
+			}
+			return tui.Run(demoSvc, tui.Options{
+				Icons:        cfg.TUI.Icons,
+				Columns:      tuiColumns(cfg.TUI.Columns),
+				Sidebar:      cfg.TUI.Sidebar,
+				ToolDefaults: tuiToolDefaults,
+				Canonical:    cfg.CanonicalCommands,
+			})
+		},
+	}
+}
+
+// newDemoService returns a service of an in-memory store holding the starter packs
+func newDemoService(ctx context.Context) (service.BookmarkService, error) {
+	packs, err := bundle.Starters()
+	if err != nil {
+		return nil, err
+	}
+
+	demoSvc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository())
+	for _, b := range packs {
+		if _, err := demoSvc.ImportBookmarks(ctx, b.Bookmarks, dto.ConflictSkip); err != nil {
+			return nil, fmt.Errorf("failed to load sample bookmarks: %w", err)
+		}
+	}
+	return demoSvc, nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newDueCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "due",
+		Short: "List bookmarks whose reminder is due",
+		Long: `List the bookmarks with a reminder (set with 'tools add --remind-every 30d' or
+'remind_every' in the store) whose period has elapsed since they were last run,
+e.g. certificate renewals or cleanup jobs. Bookmarks that never ran are due
+right away and are listed first, then the longest overdue ones.
+
+Running a bookmark with 'tools run', 'tools pick' or from the TUI resets its
+reminder.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := svc.ListDue(context.Background(), time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to list due bookmarks: %w", err)
+			}
+
+			if resp.Count == 0 {
+				info("No bookmarks are due.\n")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "TOOL\tCOMMAND\tEVERY\tLAST RUN")
+			for _, b := range resp.Bookmarks {
+				lastRun := "never"
+				if b.LastRun != nil {
+					lastRun = b.LastRun.Local().Format("2006-01-02 15:04")
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.ToolName, summaryLine(b.Command), b.RemindEvery, lastRun)
+			}
+			return w.Flush()
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var (
+	duplicateCommand     string
+	duplicateNewCommand  string
+	duplicateNewToolName string
+	duplicateNewDesc     string
+)
+
+func newDuplicateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "duplicate",
+		Aliases: []string{"dup", "cp"},
+		Short:   "Create a variant of an existing bookmark",
+		Long: `Copy an existing bookmark, including its tags, under a new command.
+
+Without --new-command you are asked for the command, tool name and
+description; press enter to keep the value of the original. The command to
+duplicate may be abbreviated like for 'tools edit'.`,
+		Example: `  tools duplicate -c "kubectl get pods -n dev" -n "kubectl get pods -n prod"
+  tools duplicate -c "get pods -n dev"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			command, err := resolveCommand(ctx, cmd.InOrStdin(), duplicateCommand)
+			if err != nil {
+				return fmt.Errorf("failed to duplicate example: %w", err)
+			}
+			original, err := svc.GetBookmark(ctx, command)
+			if err != nil {
+				return fmt.Errorf("failed to duplicate example: %w", err)
+			}
+
+			req := dto.CreateBookmarkRequest{
+				Command:     duplicateNewCommand,
+				ToolName:    original.ToolName,
+				Description: original.Description,
+				Tags:        original.Tags,
+				Link:        original.Link,
+			}
+			if duplicateNewToolName != "" {
+				req.ToolName = duplicateNewToolName
+			}
+			if duplicateNewDesc != "" {
+				req.Description = duplicateNewDesc
+			}
+
+			if req.Command == "" {
+				// Like the add form: every field starts with the original's value
+				req.Command = original.Command
+				reader := bufio.NewReader(cmd.InOrStdin())
+				for _, field := range []struct {
+					label string
+					value *string
+				}{
+					{i18n.T("Command"), &req.Command},
+					{i18n.T("Tool name"), &req.ToolName},
+					{i18n.T("Description"), &req.Description},
+				} {
+					answer, _, err := prompt(reader, fmt.Sprintf("%s [%s]: ", field.label, *field.value))
+					if err != nil {
+						return err
+					}
+					if answer != "" {
+						*field.value = answer
+					}
+				}
+			}
+
+			resp, err := svc.CreateBookmark(ctx, req)
+			if err != nil {
+				return fmt.Errorf("failed to duplicate example: %w", err)
+			}
+
+			printAdded(resp)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&duplicateCommand, "command", "c", "", "Command of the bookmark to duplicate, full or partial (required)")
+	cmd.Flags().StringVarP(&duplicateNewCommand, "new-command", "n", "", "Command of the copy (asked for if not set)")
+	cmd.Flags().StringVarP(&duplicateNewToolName, "new-tool", "t", "", "Tool name of the copy (defaults to the original's)")
+	cmd.Flags().StringVarP(&duplicateNewDesc, "new-description", "d", "", "Description of the copy (defaults to the original's)")
+
+	_ = cmd.MarkFlagRequired("command")
+
+	return cmd
+}
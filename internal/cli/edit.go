@@ -3,7 +3,11 @@ package cli
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
+	"time"
 
+	"github.com/fgeck/tools/internal/domain/models"
 	"github.com/fgeck/tools/internal/dto"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +17,15 @@ var (
 	editNewToolName string
 	editNewDesc     string
 	editNewCommand  string
+	editNewTags     []string
+	editNewLink     string
+	editNewRemind   string
+	editNewHost     string
+	editNewVariants []string
+	editNewRequires []string
+	editNewPrivate  bool
+	editIfUpdatedAt string
+	editSecrets     bool
 )
 
 func newEditCmd() *cobra.Command {
@@ -21,37 +34,135 @@ func newEditCmd() *cobra.Command {
 		Aliases: []string{"e", "update"},
 		Short:   "Edit an existing example bookmark",
 		Long: `Edit an existing example by specifying its current command.
-You can update the tool name, description, and/or command.
-Only the fields you provide will be updated.`,
+You can update the tool name, description, command, tags, link, reminder, host,
+OS variants, requirements and/or private flag. Only the fields you provide will
+be updated; --new-tags "" and --new-requires "" remove all tags and
+requirements, and --new-link "", --new-remind-every "" and --new-host "" remove
+the link, reminder and host. --new-variant os=command adds or replaces the
+variant for one OS and --new-variant os= removes it. --new-private=false shares
+a private bookmark again.
+
+--if-updated-at takes the update time shown by 'tools get' and refuses the edit
+with exit code 3 if the bookmark was changed since, e.g. in the TUI.
+
+A new command or variant containing likely credentials is refused like with
+'tools add', unless --allow-secrets is set.
+
+The current command may be abbreviated: a case-insensitive prefix or substring
+that matches a single example selects it, several matches are listed to choose from.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// At least one field must be provided for update
-			if editNewToolName == "" && editNewDesc == "" && editNewCommand == "" {
-				return fmt.Errorf("at least one field must be provided for update (--new-tool, --new-description, or --new-command)")
+			tagsChanged := cmd.Flags().Changed("new-tags")
+			linkChanged := cmd.Flags().Changed("new-link")
+			remindChanged := cmd.Flags().Changed("new-remind-every")
+			hostChanged := cmd.Flags().Changed("new-host")
+			variantsChanged := cmd.Flags().Changed("new-variant")
+			requiresChanged := cmd.Flags().Changed("new-requires")
+			privateChanged := cmd.Flags().Changed("new-private")
+			if editNewToolName == "" && editNewDesc == "" && editNewCommand == "" && !tagsChanged && !linkChanged && !remindChanged && !hostChanged && !variantsChanged && !requiresChanged && !privateChanged {
+				return fmt.Errorf("%w: at least one field must be provided for update (--new-tool, --new-description, --new-command, --new-tags, --new-link, --new-remind-every, --new-host, --new-variant, --new-requires or --new-private)", models.ErrValidation)
+			}
+
+			ctx := context.Background()
+			command, err := resolveCommand(ctx, cmd.InOrStdin(), editCommand)
+			if err != nil {
+				return fmt.Errorf("failed to edit example: %w", err)
 			}
 
 			req := dto.UpdateBookmarkRequest{
-				Command:        editCommand,
+				Command:        command,
 				NewToolName:    editNewToolName,
 				NewDescription: editNewDesc,
 				NewCommand:     editNewCommand,
+				AllowSecrets:   editSecrets,
+			}
+			if tagsChanged {
+				req.NewTags = append([]string{}, editNewTags...)
+			}
+			if linkChanged {
+				req.NewLink = &editNewLink
+			}
+			if remindChanged {
+				req.NewRemindEvery = &editNewRemind
+			}
+			if hostChanged {
+				req.NewHost = &editNewHost
+			}
+			if variantsChanged {
+				if req.NewVariants, err = editedVariants(ctx, command); err != nil {
+					return fmt.Errorf("failed to edit example: %w", err)
+				}
+			}
+			if requiresChanged {
+				req.NewRequires = append([]string{}, editNewRequires...)
+			}
+			if privateChanged {
+				req.NewPrivate = &editNewPrivate
+			}
+			if req.ExpectedUpdatedAt, err = parseUpdatedAt(editIfUpdatedAt); err != nil {
+				return fmt.Errorf("failed to edit example: %w", err)
 			}
 
-			resp, err := svc.UpdateBookmark(context.Background(), req)
+			resp, err := svc.UpdateBookmark(ctx, req)
 			if err != nil {
 				return fmt.Errorf("failed to edit example: %w", err)
 			}
 
-			fmt.Printf("Successfully updated example: %s\n", resp.Command)
+			info("Successfully updated example: %s\n", resp.Command)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&editCommand, "command", "c", "", "Current command to edit (required)")
+	cmd.Flags().StringVarP(&editCommand, "command", "c", "", "Current command to edit, full or partial (required)")
 	cmd.Flags().StringVarP(&editNewToolName, "new-tool", "t", "", "New tool name")
 	cmd.Flags().StringVarP(&editNewDesc, "new-description", "d", "", "New description")
 	cmd.Flags().StringVarP(&editNewCommand, "new-command", "n", "", "New command")
+	cmd.Flags().StringSliceVar(&editNewTags, "new-tags", nil, "New comma-separated tags (replaces existing tags)")
+	cmd.Flags().StringVar(&editNewLink, "new-link", "", "New documentation URL (empty removes the link)")
+	cmd.Flags().StringVar(&editNewRemind, "new-remind-every", "", "New reminder period, e.g. 30d (empty removes the reminder)")
+	cmd.Flags().StringVar(&editNewHost, "new-host", "", "New SSH host (empty runs the command locally again)")
+	cmd.Flags().StringArrayVar(&editNewVariants, "new-variant", nil, "Command for an OS as os=command, os= removes it (repeatable)")
+	cmd.Flags().StringSliceVar(&editNewRequires, "new-requires", nil, "New comma-separated requirements (replaces existing requirements)")
+	cmd.Flags().BoolVar(&editNewPrivate, "new-private", false, "Make the bookmark private, --new-private=false shares it again")
+	cmd.Flags().StringVar(&editIfUpdatedAt, "if-updated-at", "", "Only edit if the bookmark's update time, as shown by 'tools get', is unchanged")
+	cmd.Flags().BoolVar(&editSecrets, "allow-secrets", false, "Store a new command or variant even if it contains likely credentials")
 
 	_ = cmd.MarkFlagRequired("command")
 
 	return cmd
 }
+
+// editedVariants applies the --new-variant flags to the current variants of a bookmark
+func editedVariants(ctx context.Context, command string) (map[string]string, error) {
+	changes, err := parseVariants(editNewVariants)
+	if err != nil {
+		return nil, err
+	}
+	bookmark, err := svc.GetBookmark(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := map[string]string{}
+	maps.Copy(variants, bookmark.Variants)
+	for goos, variant := range changes {
+		if strings.TrimSpace(variant) == "" {
+			delete(variants, goos)
+			continue
+		}
+		variants[goos] = variant
+	}
+	return variants, nil
+}
+
+// parseUpdatedAt parses the update time of an --if-updated-at flag, nil if it is empty
+func parseUpdatedAt(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid update time '%s', use the one shown by 'tools get'", models.ErrValidation, value)
+	}
+	return &updatedAt, nil
+}
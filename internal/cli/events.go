@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/spf13/cobra"
+)
+
+// eventsPollInterval is how often --follow checks the history file for new events
+const eventsPollInterval = 500 * time.Millisecond
+
+var (
+	eventsFollow  bool
+	eventsNew     bool
+	eventsActions []string
+)
+
+func newEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Stream the history of bookmark changes as JSON lines",
+		Long: `Print the events of the history as JSON lines, one event per line, for scripts
+and tools reacting to bookmark changes. The fields are those of the history
+file: time, action, command, query, before, after and result.
+
+With --follow the command keeps running and prints each new event as soon as it
+is recorded, until interrupted. Add --new to skip the events recorded before.`,
+		Example: `  tools events --follow --new --action create --action edit --action delete
+  tools events --action run | jq -r .command`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, action := range eventsActions {
+				if !slices.Contains(eventActions, audit.Action(action)) {
+					return fmt.Errorf("%w: invalid action '%s', must be one of create, edit, delete, run, search or result", models.ErrValidation, action)
+				}
+			}
+			if eventsNew && !eventsFollow {
+				return fmt.Errorf("%w: --new can only be used with --follow", models.ErrValidation)
+			}
+
+			out := json.NewEncoder(os.Stdout)
+			emit := func(event audit.Event) error {
+				if len(eventsActions) > 0 && !slices.Contains(eventsActions, string(event.Action)) {
+					return nil
+				}
+				return out.Encode(event)
+			}
+
+			log := audit.NewFileLog(cfg.HistoryFilePath)
+			if !eventsFollow {
+				events, err := log.Events()
+				if err != nil {
+					return fmt.Errorf("failed to read history: %w", err)
+				}
+				for _, event := range events {
+					if err := emit(event); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if err := log.Follow(ctx, eventsPollInterval, !eventsNew, emit); err != nil {
+				return fmt.Errorf("failed to follow history: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep printing new events as they are recorded")
+	cmd.Flags().BoolVar(&eventsNew, "new", false, "With --follow, only print events recorded from now on")
+	cmd.Flags().StringArrayVarP(&eventsActions, "action", "a", nil, "Only print events of this action, repeat for several")
+
+	return cmd
+}
+
+// eventActions are the actions recorded in the history
+var eventActions = []audit.Action{
+	audit.ActionCreate, audit.ActionEdit, audit.ActionDelete,
+	audit.ActionRun, audit.ActionSearch, audit.ActionResult,
+}
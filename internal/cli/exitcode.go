@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// Exit codes returned by the tools binary
+const (
+	ExitOK         = 0 // Success
+	ExitError      = 1 // Any other failure
+	ExitNotFound   = 2 // Bookmark or revision does not exist
+	ExitConflict   = 3 // Command already exists or conflicts with existing or read-only data
+	ExitValidation = 4 // Invalid input
+)
+
+// ExitCode maps an error returned by a command to the process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, models.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, models.ErrAlreadyExists), errors.Is(err, models.ErrConflict):
+		return ExitConflict
+	case errors.Is(err, models.ErrValidation):
+		return ExitValidation
+	default:
+		return ExitError
+	}
+}
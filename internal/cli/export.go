@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/export"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat  string
+	exportOutput  string
+	exportTitle   string
+	exportPrivate bool
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export bookmarks as a cheat sheet",
+		Long: `Render all bookmarks as a cheat sheet, grouped by tool.
+
+Formats:
+  html      a standalone page with per-tool sections and a search box, ready
+            to publish as a cheat-sheet site
+  markdown  one section per tool, for a README or wiki page
+  latex     a compact two-column sheet to print; run 'pdflatex' on it to get
+            a PDF
+  json      the bookmarks grouped by tool, for other programs
+
+The sheet is written to stdout unless --output is set. Private bookmarks are
+left out unless --include-private is set. Parts of commands hidden by the
+redaction settings of the config are exported as ****.`,
+		Example: `  tools export --format html -o cheatsheet.html
+  tools export --title "Team cheat sheet" > index.html
+  tools export -f latex -o cheatsheet.tex && pdflatex cheatsheet.tex`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := svc.ListBookmarks(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to export bookmarks: %w", err)
+			}
+
+			examples := resp.Examples
+			if !exportPrivate {
+				examples = dto.Shared(examples)
+			}
+			examples = redactor().Bookmarks(examples)
+
+			var buf bytes.Buffer
+			sheet := export.NewSheet(exportTitle, examples)
+			if err := export.Write(&buf, export.Format(exportFormat), sheet); err != nil {
+				return fmt.Errorf("failed to export bookmarks: %w", err)
+			}
+
+			if exportOutput == "" {
+				_, err := os.Stdout.Write(buf.Bytes())
+				return err
+			}
+			if err := os.WriteFile(exportOutput, buf.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to export bookmarks: %w", err)
+			}
+			info("Successfully exported %d bookmarks to %s\n", len(examples), exportOutput)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&exportFormat, "format", "f", string(export.FormatHTML), "Output format: html, markdown, latex or json")
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to this file instead of stdout")
+	cmd.Flags().StringVar(&exportTitle, "title", export.DefaultTitle, "Title of the cheat sheet")
+	cmd.Flags().BoolVar(&exportPrivate, "include-private", false, "Also export private bookmarks")
+
+	return cmd
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filterDump          bool
+	filterDryRun        bool
+	filterDeleteMissing bool
+)
+
+func newFilterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter [-- <command> [args...]]",
+		Short: "Transform bookmarks with other programs through JSON lines",
+		Long: `Hand your bookmarks to other programs as JSON lines, one bookmark per line
+with the fields of 'tools import', and store what comes back. Use it for bulk
+changes the other commands don't cover.
+
+With a command after --, your bookmarks are written to its stdin and the
+bookmarks it prints are stored. Without one, the bookmarks are read from
+stdin; --dump prints them to stdout to start such a pipeline.
+
+Bookmarks are matched by command: changed ones are updated and new commands
+are added. A bookmark whose command was changed is added as a new bookmark;
+the old one is only removed with --delete-missing, which removes every
+bookmark of your store that is missing from the result. Bookmarks of
+read-only layers and the review queue are left out. The changes are shown as a
+diff; use --dry-run to only show them.`,
+		Example: `  tools filter -- jq -c 'if .tool_name == "k" then .tool_name = "kubectl" else . end'
+  tools filter --delete-missing -- jq -c 'select(.tool_name != "scratch")'
+  tools filter --dump > bookmarks.jsonl
+  tools filter --dry-run < bookmarks.jsonl`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			stored, err := filterBookmarks(ctx)
+			if err != nil {
+				return err
+			}
+			if filterDump {
+				if len(args) > 0 {
+					return fmt.Errorf("%w: --dump cannot be combined with a command", models.ErrValidation)
+				}
+				return writeJSONLines(os.Stdout, stored)
+			}
+
+			input := cmd.InOrStdin()
+			if len(args) > 0 {
+				output, err := runFilter(args, stored)
+				if err != nil {
+					return err
+				}
+				input = output
+			}
+			reqs, err := readJSONLines(input)
+			if err != nil {
+				return err
+			}
+
+			return applyFilter(ctx, stored, reqs)
+		},
+	}
+
+	cmd.Flags().BoolVar(&filterDump, "dump", false, "Print your bookmarks as JSON lines instead of storing any")
+	cmd.Flags().BoolVar(&filterDryRun, "dry-run", false, "Only show what would change")
+	cmd.Flags().BoolVar(&filterDeleteMissing, "delete-missing", false, "Remove the bookmarks of your store that are missing from the result")
+
+	return cmd
+}
+
+// filterBookmarks returns the bookmarks of the user's store as they are imported
+func filterBookmarks(ctx context.Context) ([]dto.CreateBookmarkRequest, error) {
+	resp, err := svc.ListBookmarks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+	reqs := []dto.CreateBookmarkRequest{}
+	for _, b := range resp.Examples {
+		if b.Origin != "" || b.Pending {
+			continue
+		}
+		reqs = append(reqs, dto.CreateBookmarkRequest{
+			Command:     b.Command,
+			ToolName:    b.ToolName,
+			Description: b.Description,
+			Tags:        b.Tags,
+			Link:        b.Link,
+			RemindEvery: b.RemindEvery,
+			Host:        b.Host,
+			Variants:    b.Variants,
+			Requires:    b.Requires,
+			Private:     b.Private,
+			Author:      b.Author,
+		})
+	}
+	return reqs, nil
+}
+
+// writeJSONLines writes one bookmark per line
+func writeJSONLines(w io.Writer, reqs []dto.CreateBookmarkRequest) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, req := range reqs {
+		if err := encoder.Encode(req); err != nil {
+			return fmt.Errorf("failed to write bookmarks: %w", err)
+		}
+	}
+	return nil
+}
+
+// readJSONLines reads bookmarks written by writeJSONLines or a program transforming them
+// Values spanning several lines, like the output of jq without -c, are read too. Unknown
+// fields are refused, so a misspelled field doesn't go unnoticed.
+func readJSONLines(r io.Reader) ([]dto.CreateBookmarkRequest, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var reqs []dto.CreateBookmarkRequest
+	for {
+		var req dto.CreateBookmarkRequest
+		err := decoder.Decode(&req)
+		if errors.Is(err, io.EOF) {
+			return reqs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid bookmark %d: %v", models.ErrValidation, len(reqs)+1, err)
+		}
+		reqs = append(reqs, req)
+	}
+}
+
+// runFilter runs a program with the bookmarks on its stdin and returns what it printed
+// Its stderr goes to the terminal.
+func runFilter(args []string, reqs []dto.CreateBookmarkRequest) (io.Reader, error) {
+	var stdin, stdout bytes.Buffer
+	if err := writeJSONLines(&stdin, reqs); err != nil {
+		return nil, err
+	}
+
+	filter := exec.Command(args[0], args[1:]...)
+	filter.Stdin = &stdin
+	filter.Stdout = &stdout
+	filter.Stderr = os.Stderr
+	if err := filter.Run(); err != nil {
+		return nil, fmt.Errorf("filter %s failed, nothing was changed: %w", args[0], err)
+	}
+	return &stdout, nil
+}
+
+// applyFilter stores the bookmarks returned by a filter, after showing how they differ from
+// the stored ones
+func applyFilter(ctx context.Context, stored, reqs []dto.CreateBookmarkRequest) error {
+	preview, err := svc.PreviewImport(ctx, reqs, dto.ConflictOverwrite)
+	if err != nil {
+		return fmt.Errorf("failed to filter bookmarks: %w", err)
+	}
+
+	var missing []string
+	if filterDeleteMissing {
+		returned := make(map[string]bool, len(reqs))
+		for _, entry := range preview.Entries {
+			returned[entry.Bookmark.Command] = true
+		}
+		for _, req := range stored {
+			if !returned[req.Command] {
+				missing = append(missing, req.Command)
+			}
+		}
+		// An empty result is more likely a broken filter than the wish to remove everything
+		if len(reqs) == 0 && len(stored) > 0 {
+			return fmt.Errorf("%w: the filter returned no bookmarks, refusing to remove all %d; use 'tools rm' instead", models.ErrValidation, len(stored))
+		}
+	}
+
+	for _, entry := range preview.Entries {
+		switch entry.Status {
+		case dto.ImportAdded:
+			fmt.Println(addedStyle.Render("+ " + summaryLine(entry.Bookmark.Command)))
+		case dto.ImportChanged:
+			fmt.Println(changedStyle.Render("~ " + summaryLine(entry.Bookmark.Command)))
+			printFieldDiff(entry.Existing, entry.Bookmark)
+		}
+	}
+	for _, command := range missing {
+		fmt.Println(removedStyle.Render("- " + summaryLine(command)))
+	}
+
+	if filterDryRun {
+		info("Dry run: %d would be added, %d updated, %d removed\n", preview.Added, preview.Changed, len(missing))
+		return nil
+	}
+
+	// The filter output replaces the store as a whole or not at all
+	var result *dto.ImportResult
+	err = svc.Transaction(ctx, func(tx service.BookmarkService) error {
+		if result, err = tx.ImportBookmarks(ctx, reqs, dto.ConflictOverwrite); err != nil {
+			return fmt.Errorf("failed to filter bookmarks: %w", err)
+		}
+		for _, command := range missing {
+			if err := tx.DeleteBookmark(ctx, command); err != nil {
+				return fmt.Errorf("failed to remove '%s': %w", command, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	info("Successfully filtered bookmarks: %d added, %d updated, %d removed\n", result.Added, result.Updated, len(missing))
+	return nil
+}
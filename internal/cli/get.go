@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	getCommand string
+	getRelated int
+)
+
+func newGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show a single bookmark",
+		Long: `Show a bookmark by its command (primary key).
+
+Bookmarks of the same tool or with overlapping tags and words are listed as
+related; use --related to change how many are shown (0 to hide them).
+
+Exits with code 2 if the command is not bookmarked. Combined with --quiet it
+prints nothing, so it can be used as a check in scripts:
+
+  tools get -c "kubectl get pods -A" --quiet || tools add ...`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			resp, err := svc.GetBookmark(ctx, getCommand)
+			if err != nil {
+				return commandNotFound(ctx, err, getCommand)
+			}
+
+			if quiet {
+				return nil
+			}
+			printBookmarkFields(redactor().Bookmark(*resp))
+			if !resp.UpdatedAt.IsZero() {
+				// In full precision, for edit and remove --if-updated-at
+				fmt.Printf("    updated:     %s\n", resp.UpdatedAt.Format(time.RFC3339Nano))
+			}
+
+			last, err := svc.LastRun(ctx, resp.Command)
+			if err != nil {
+				return fmt.Errorf("failed to read run history: %w", err)
+			}
+			if last != nil {
+				fmt.Printf("    last run:    %s, %s\n", describeRunResult(*last), last.Time.Local().Format("2006-01-02 15:04:05"))
+			}
+
+			if getRelated <= 0 {
+				return nil
+			}
+			related, err := svc.RelatedBookmarks(ctx, getCommand, getRelated)
+			if err != nil {
+				return fmt.Errorf("failed to find related examples: %w", err)
+			}
+			if related.Count > 0 {
+				fmt.Println("\nRelated:")
+				for _, r := range related.Results {
+					command, _, script := strings.Cut(redactor().Redact(r.Command), "\n")
+					if script {
+						command += " ..."
+					}
+					fmt.Printf("    %-15s %s\n", r.ToolName, command)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&getCommand, "command", "c", "", "Command to show (required)")
+	cmd.Flags().IntVar(&getRelated, "related", 5, "Maximum number of related bookmarks to show (0 to hide)")
+
+	_ = cmd.MarkFlagRequired("command")
+
+	return cmd
+}
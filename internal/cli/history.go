@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyCommand string
+	historyLimit   int
+)
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the history of bookmark changes and runs",
+		Long: `Display the audit log of every create, edit, delete and run event with timestamps.
+Commands executed with 'tools run' also record their exit code and duration.
+
+Use -c to show only the history of a single command. Edits that changed the
+command itself are included for both the old and the new command.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := svc.GetHistory(context.Background(), historyCommand)
+			if err != nil {
+				return fmt.Errorf("failed to read history: %w", err)
+			}
+
+			if resp.Count == 0 {
+				info("No history recorded yet.\n")
+				return nil
+			}
+
+			entries := resp.Entries
+			if historyLimit > 0 && len(entries) > historyLimit {
+				entries = entries[len(entries)-historyLimit:]
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "TIME\tACTION\tCOMMAND\tDETAILS")
+			_, _ = fmt.Fprintln(w, "----\t------\t-------\t-------")
+			for _, entry := range entries {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					entry.Time.Local().Format("2006-01-02 15:04:05"),
+					entry.Action,
+					entry.Command,
+					describeHistoryChange(entry),
+				)
+			}
+			_ = w.Flush()
+
+			info("\nTotal: %d events\n", resp.Count)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&historyCommand, "command", "c", "", "Only show history for this command")
+	cmd.Flags().IntVarP(&historyLimit, "limit", "l", 0, "Only show the most recent N events")
+
+	return cmd
+}
+
+// describeHistoryChange summarizes what an audit log entry changed
+func describeHistoryChange(entry dto.HistoryEntry) string {
+	switch {
+	case entry.Result != nil:
+		return describeRunResult(*entry.Result)
+	case entry.Before != nil && entry.After != nil:
+		changes := describeBookmarkChanges(*entry.Before, *entry.After)
+		if len(changes) == 0 {
+			return "no changes"
+		}
+		return strings.Join(changes, "; ")
+	case entry.After != nil:
+		return fmt.Sprintf("tool: '%s', description: '%s'", entry.After.ToolName, entry.After.Description)
+	case entry.Before != nil:
+		return fmt.Sprintf("was tool: '%s', description: '%s'", entry.Before.ToolName, entry.Before.Description)
+	}
+	return ""
+}
+
+// describeRunResult summarizes the exit code and duration of an executed command
+func describeRunResult(result dto.RunResult) string {
+	return fmt.Sprintf("exit code %d after %s", result.ExitCode, result.Duration.Round(time.Millisecond))
+}
+
+// describeBookmarkChanges lists the fields that differ between two versions of a bookmark
+func describeBookmarkChanges(before, after dto.BookmarkResponse) []string {
+	var changes []string
+	if before.Command != after.Command {
+		changes = append(changes, fmt.Sprintf("command: '%s' -> '%s'", before.Command, after.Command))
+	}
+	if before.ToolName != after.ToolName {
+		changes = append(changes, fmt.Sprintf("tool: '%s' -> '%s'", before.ToolName, after.ToolName))
+	}
+	if before.Description != after.Description {
+		changes = append(changes, fmt.Sprintf("description: '%s' -> '%s'", before.Description, after.Description))
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		changes = append(changes, fmt.Sprintf("tags: [%s] -> [%s]", strings.Join(before.Tags, ", "), strings.Join(after.Tags, ", ")))
+	}
+	if before.Link != after.Link {
+		changes = append(changes, fmt.Sprintf("link: '%s' -> '%s'", before.Link, after.Link))
+	}
+	if !maps.Equal(before.Variants, after.Variants) {
+		changes = append(changes, fmt.Sprintf("variants: [%s] -> [%s]", formatVariants(before.Variants), formatVariants(after.Variants)))
+	}
+	if !slices.Equal(before.Requires, after.Requires) {
+		changes = append(changes, fmt.Sprintf("requires: [%s] -> [%s]", strings.Join(before.Requires, ", "), strings.Join(after.Requires, ", ")))
+	}
+	if before.Private != after.Private {
+		changes = append(changes, fmt.Sprintf("private: %t -> %t", before.Private, after.Private))
+	}
+	if before.Host != after.Host {
+		changes = append(changes, fmt.Sprintf("host: '%s' -> '%s'", before.Host, after.Host))
+	}
+	if before.RemindEvery != after.RemindEvery {
+		changes = append(changes, fmt.Sprintf("remind every: '%s' -> '%s'", before.RemindEvery, after.RemindEvery))
+	}
+	return changes
+}
+
+// formatVariants lists OS variants as os=command pairs, sorted by OS
+func formatVariants(variants map[string]string) string {
+	pairs := make([]string, 0, len(variants))
+	for _, goos := range slices.Sorted(maps.Keys(variants)) {
+		pairs = append(pairs, goos+"="+variants[goos])
+	}
+	return strings.Join(pairs, ", ")
+}
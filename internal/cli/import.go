@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/recipes"
+	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDryRun     bool
+	importOnConflict string
+	importFrom       string
+	importTags       []string
+	importStdin      bool
+	importFormat     string
+	importSecrets    bool
+)
+
+// Styles of diff-style output; colors are dropped when stdout is not a terminal
+var (
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("167"))
+	changedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	skippedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>|--stdin",
+		Short: "Import bookmarks from another store file, a Justfile or a Makefile",
+		Long: `Import the bookmarks of a tools YAML store, e.g. a backup or the store of
+another machine, into your store.
+
+With --from justfile or --from makefile, the recipes of a Justfile or the
+targets of a Makefile are imported instead. Each becomes a bookmark that runs
+it from any directory (just --justfile <path> <recipe>, make -C <dir> <target>),
+tagged with the name of the project directory and described by the comment
+above the recipe. Required recipe parameters become <placeholders>.
+
+Bookmarks that already exist unchanged are skipped. For bookmarks that exist
+with different content, --on-conflict decides:
+  skip       keep your version (default)
+  overwrite  replace your version with the imported one
+  fail       abort without importing anything
+
+Bookmarks containing likely credentials, such as AWS access keys, tokens or
+passwords in URLs, are listed and the import is refused, so they don't leak
+into synced or shared stores. Pass --allow-secrets to import them anyway.
+
+Use --dry-run to preview the added, changed and skipped bookmarks as a diff
+without changing your store.
+
+With --stdin, bookmarks are read from standard input instead of a file, so
+scripts can feed them without temporary files. --format yaml (default) expects
+the store format, --format json a JSON array of bookmarks:
+
+  echo '[{"command": "kubectl get pods -A", "tool_name": "kubectl",
+    "description": "list pods"}]' | tools import --stdin --format json`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if importStdin {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			strategy := dto.ConflictStrategy(importOnConflict)
+
+			if importFrom != "tools" && importStdin {
+				return fmt.Errorf("%w: --stdin only applies to --from tools", models.ErrValidation)
+			}
+			if cmd.Flags().Changed("format") && !importStdin {
+				return fmt.Errorf("%w: --format only applies to --stdin", models.ErrValidation)
+			}
+			if importFormat != "yaml" && importFormat != "json" {
+				return fmt.Errorf("%w: unknown import format '%s' (supported: yaml, json)", models.ErrValidation, importFormat)
+			}
+
+			var reqs []dto.CreateBookmarkRequest
+			var err error
+			switch {
+			case importStdin:
+				args = []string{"stdin"}
+				reqs, err = readImportData(cmd.InOrStdin(), importFormat)
+			case importFrom == "tools":
+				if len(importTags) > 0 {
+					return fmt.Errorf("%w: --tags only applies to --from justfile or makefile", models.ErrValidation)
+				}
+				reqs, err = readImportFile(ctx, args[0])
+			default:
+				reqs, err = readRecipeFile(recipes.Source(importFrom), args[0], importTags)
+			}
+			if err != nil {
+				return err
+			}
+			for i := range reqs {
+				reqs[i].AllowSecrets = importSecrets
+			}
+
+			if importDryRun {
+				preview, err := svc.PreviewImport(ctx, reqs, strategy)
+				if err != nil {
+					return fmt.Errorf("failed to preview import: %w", err)
+				}
+				printImportPreview(args[0], strategy, preview)
+				return nil
+			}
+
+			result, err := svc.ImportBookmarks(ctx, reqs, strategy)
+			if result != nil && len(result.Conflicts) > 0 {
+				fmt.Printf("Conflicting bookmarks (%s):\n", importOnConflict)
+				for _, command := range result.Conflicts {
+					fmt.Printf("  %s\n", command)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to import bookmarks: %w", err)
+			}
+
+			info("Successfully imported %s: %d added, %d updated, %d skipped\n", args[0], result.Added, result.Updated, result.Skipped)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without changing anything")
+	cmd.Flags().StringVar(&importOnConflict, "on-conflict", string(dto.ConflictSkip), "Conflict handling: skip, overwrite or fail")
+	cmd.Flags().StringVar(&importFrom, "from", "tools", "Kind of file: tools (a YAML store), justfile or makefile")
+	cmd.Flags().StringSliceVar(&importTags, "tags", nil, "Comma-separated additional tags for imported recipes")
+	cmd.Flags().BoolVar(&importStdin, "stdin", false, "Read the bookmarks from standard input instead of a file")
+	cmd.Flags().StringVar(&importFormat, "format", "yaml", "Format of the bookmarks read with --stdin: yaml or json")
+	cmd.Flags().BoolVar(&importSecrets, "allow-secrets", false, "Import bookmarks even if they contain likely credentials")
+
+	return cmd
+}
+
+// readImportFile reads the bookmarks of a YAML store file
+func readImportFile(ctx context.Context, path string) ([]dto.CreateBookmarkRequest, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: import file '%s' does not exist", models.ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	bookmarks, err := yaml.NewReadOnlyYAMLBookmarkRepository(path).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	return importRequests(bookmarks), nil
+}
+
+// readImportData reads bookmarks in the store format (yaml) or as a JSON array (json)
+func readImportData(in io.Reader, format string) ([]dto.CreateBookmarkRequest, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	var bookmarks []*models.Bookmark
+	if format == "json" {
+		err = json.Unmarshal(data, &bookmarks)
+	} else {
+		bookmarks, err = yaml.ParseStore(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse bookmarks as %s: %v", models.ErrValidation, format, err)
+	}
+	return importRequests(bookmarks), nil
+}
+
+// importRequests converts bookmarks read for an import to create requests
+func importRequests(bookmarks []*models.Bookmark) []dto.CreateBookmarkRequest {
+	reqs := make([]dto.CreateBookmarkRequest, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		reqs = append(reqs, dto.CreateBookmarkRequest{
+			Command:     b.Command,
+			ToolName:    b.ToolName,
+			Description: b.Description,
+			Tags:        b.Tags,
+			Link:        b.Link,
+			RemindEvery: b.RemindEvery,
+			Host:        b.Host,
+			Variants:    b.Variants,
+			Requires:    b.Requires,
+			Private:     b.Private,
+			Author:      b.Author,
+		})
+	}
+	return reqs
+}
+
+// readRecipeFile reads the recipes of a Justfile or Makefile as bookmarks
+// The bookmarks are tagged with the project directory's name and the given tags.
+func readRecipeFile(source recipes.Source, path string, tags []string) ([]dto.CreateBookmarkRequest, error) {
+	if !slices.Contains(recipes.Sources(), source) {
+		return nil, fmt.Errorf("%w: unknown import source '%s' (supported: tools, justfile, makefile)", models.ErrValidation, source)
+	}
+
+	found, err := recipes.Read(source, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: import file '%s' does not exist", models.ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	abs, _ := filepath.Abs(path)
+	project := projectTag(filepath.Base(filepath.Dir(abs)))
+	tool, kind := "just", "recipe"
+	if source == recipes.SourceMakefile {
+		tool, kind = "make", "target"
+	}
+
+	reqs := make([]dto.CreateBookmarkRequest, 0, len(found))
+	for _, r := range found {
+		description := r.Description
+		if description == "" {
+			description = fmt.Sprintf("Run the %s %s of %s", r.Name, kind, project)
+		}
+		reqs = append(reqs, dto.CreateBookmarkRequest{
+			Command:     r.Command,
+			ToolName:    tool,
+			Description: description,
+			Tags:        append([]string{project}, tags...),
+		})
+	}
+	return reqs, nil
+}
+
+// projectTag turns a directory name into a tag, which cannot contain whitespace or commas
+func projectTag(dir string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == ',' {
+			return '-'
+		}
+		return r
+	}, dir)
+}
+
+// printImportPreview prints the entries of an import preview as a diff against the store
+// Added entries are marked with +, changed ones with ~ followed by the differing fields,
+// skipped ones with =.
+func printImportPreview(path string, strategy dto.ConflictStrategy, preview *dto.ImportPreview) {
+	fmt.Printf("Import preview of %s (on conflict: %s):\n", path, strategy)
+	for _, entry := range preview.Entries {
+		b := entry.Bookmark
+		switch entry.Status {
+		case dto.ImportAdded:
+			fmt.Println(addedStyle.Render(fmt.Sprintf("+ %s", summaryLine(b.Command))))
+			fmt.Println(addedStyle.Render(fmt.Sprintf("    tool: %s, description: %s", b.ToolName, b.Description)))
+		case dto.ImportChanged:
+			fmt.Println(changedStyle.Render(fmt.Sprintf("~ %s", summaryLine(b.Command))))
+			printFieldDiff(entry.Existing, b)
+		case dto.ImportSkipped:
+			if entry.Existing == nil {
+				fmt.Println(skippedStyle.Render(fmt.Sprintf("= %s (unchanged)", summaryLine(b.Command))))
+				continue
+			}
+			fmt.Println(skippedStyle.Render(fmt.Sprintf("= %s (conflict, keeping your version)", summaryLine(b.Command))))
+			printFieldDiff(entry.Existing, b)
+		}
+	}
+
+	fmt.Printf("Summary: %d added, %d changed, %d skipped", preview.Added, preview.Changed, preview.Skipped)
+	if len(preview.Conflicts) > 0 {
+		fmt.Printf(" (%d conflicts)", len(preview.Conflicts))
+	}
+	fmt.Println()
+	if strategy == dto.ConflictFail && len(preview.Conflicts) > 0 {
+		fmt.Println("The import would abort because of the conflicts.")
+	}
+	info("Dry run, nothing was imported.\n")
+}
+
+// printFieldDiff prints the fields in which an incoming bookmark differs from the stored one
+func printFieldDiff(existing *dto.BookmarkResponse, incoming dto.CreateBookmarkRequest) {
+	if existing == nil {
+		return
+	}
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"tool", existing.ToolName, incoming.ToolName},
+		{"description", existing.Description, incoming.Description},
+		{"tags", strings.Join(existing.Tags, ", "), strings.Join(incoming.Tags, ", ")},
+		{"link", existing.Link, incoming.Link},
+		{"remind every", existing.RemindEvery, incoming.RemindEvery},
+		{"host", existing.Host, incoming.Host},
+		{"variants", formatVariants(existing.Variants), formatVariants(incoming.Variants)},
+		{"requires", strings.Join(existing.Requires, ", "), strings.Join(incoming.Requires, ", ")},
+		{"private", strconv.FormatBool(existing.Private), strconv.FormatBool(incoming.Private)},
+	}
+	for _, f := range fields {
+		if f.old == f.new {
+			continue
+		}
+		fmt.Println(removedStyle.Render(fmt.Sprintf("    - %s: %s", f.name, f.old)))
+		fmt.Println(addedStyle.Render(fmt.Sprintf("    + %s: %s", f.name, f.new)))
+	}
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/learn"
+	"github.com/spf13/cobra"
+)
+
+var (
+	learnAll  bool
+	learnTags []string
+)
+
+func newLearnCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "learn <tool>",
+		Short: "Discover example invocations from a tool's help and man page",
+		Long: `Run "<tool> --help" and "man <tool>", collect the invocations listed in their
+EXAMPLES sections and offer each one for import as a bookmark.
+
+Answer y to add an example, n to skip it, a to add it and all remaining ones,
+or q to stop. Use --all to add every discovered example without asking.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tool := args[0]
+			ctx := context.Background()
+
+			examples, err := learn.Discover(ctx, tool)
+			if err != nil {
+				return fmt.Errorf("failed to learn examples: %w", err)
+			}
+			if len(examples) == 0 {
+				info("No examples found in the documentation of %s.\n", tool)
+				return nil
+			}
+
+			info("Found %d examples for %s.\n", len(examples), tool)
+			selected, err := selectExamples(cmd.InOrStdin(), examples, learnAll)
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				info("No examples added.\n")
+				return nil
+			}
+
+			reqs := make([]dto.CreateBookmarkRequest, len(selected))
+			for i, ex := range selected {
+				description := ex.Description
+				if description == "" {
+					description = fmt.Sprintf("Example from %s %s", tool, ex.Source)
+				}
+				reqs[i] = dto.CreateBookmarkRequest{
+					Command:     ex.Command,
+					ToolName:    tool,
+					Description: description,
+					Tags:        learnTags,
+				}
+			}
+
+			result, err := svc.ImportBookmarks(ctx, reqs, dto.ConflictSkip)
+			if err != nil {
+				return fmt.Errorf("failed to add examples: %w", err)
+			}
+
+			info("Successfully added %d examples for tool: %s (%d already bookmarked)\n", result.Added, tool, result.Skipped)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&learnAll, "all", "a", false, "Add all discovered examples without asking")
+	cmd.Flags().StringSliceVar(&learnTags, "tags", nil, "Comma-separated tags for the added bookmarks")
+
+	return cmd
+}
+
+// selectExamples asks for each example whether it should be added
+func selectExamples(in io.Reader, examples []learn.Example, all bool) ([]learn.Example, error) {
+	return selectEach(in, examples, all, func(ex learn.Example) (string, string) {
+		return ex.Command, ex.Description
+	})
+}
+
+// selectEach asks for each candidate whether it should be added
+// describe returns the command and the (optional) description shown for a candidate.
+func selectEach[T any](in io.Reader, candidates []T, all bool, describe func(T) (string, string)) ([]T, error) {
+	if all {
+		return candidates, nil
+	}
+
+	reader := bufio.NewReader(in)
+	var selected []T
+	for i, c := range candidates {
+		command, description := describe(c)
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(candidates), command)
+		if description != "" {
+			fmt.Printf("      %s\n", description)
+		}
+		fmt.Print("Add? [y/N/a/q] ")
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			selected = append(selected, c)
+		case "a", "all":
+			return append(selected, candidates[i:]...), nil
+		case "q", "quit":
+			return selected, nil
+		}
+
+		if err == io.EOF {
+			fmt.Println()
+			return selected, nil
+		}
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFix      bool
+	lintDryRun   bool
+	lintRules    []string
+	lintSeverity string
+	lintList     bool
+)
+
+func newLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check bookmarks for vague descriptions, credentials, unportable paths and deprecated flags",
+		Long: `Check every bookmark with the lint rules and report the findings per bookmark:
+
+  description  descriptions that are missing, a placeholder, a single word or repeat the command
+  credentials  passwords, tokens and keys written into the command
+  paths        absolute paths into home directories, which only exist on one machine
+  deprecated   deprecated tools and flags, e.g. egrep or kubectl --show-all
+
+Findings are errors, warnings or infos. The command fails when any error is found,
+so it can guard a shared bookmark file in CI. Use --rule to run some rules only and
+--severity to hide findings below a severity.
+
+With --fix, findings that can be fixed without changing what a command does are
+fixed in the store: deprecated tools and flags are replaced or removed and paths
+into your own home directory use $HOME. Check the result with --dry-run first.`,
+		Example: `  tools lint
+  tools lint --rule credentials --rule paths
+  tools lint --severity error
+  tools lint --fix --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if lintList {
+				return listLintRules()
+			}
+			if lintDryRun && !lintFix {
+				return fmt.Errorf("%w: --dry-run only applies to --fix", models.ErrValidation)
+			}
+
+			minSeverity, err := lint.ParseSeverity(lintSeverity)
+			if err != nil {
+				return fmt.Errorf("%w: %v", models.ErrValidation, err)
+			}
+			rules, err := selectedLintRules(lintRules)
+			if err != nil {
+				return err
+			}
+
+			resp, err := svc.ListBookmarks(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list examples: %w", err)
+			}
+
+			if lintFix {
+				fixed, err := fixBookmarks(ctx, resp.Examples, rules, lintDryRun)
+				if err != nil {
+					return err
+				}
+				if lintDryRun {
+					info("Dry run: %d bookmarks would be fixed\n", fixed)
+					return nil
+				}
+				info("Fixed %d bookmarks\n", fixed)
+				if resp, err = svc.ListBookmarks(ctx); err != nil {
+					return fmt.Errorf("failed to list examples: %w", err)
+				}
+			}
+
+			var findings []lint.Finding
+			for _, finding := range lint.Run(resp.Examples, rules) {
+				if finding.Severity >= minSeverity {
+					findings = append(findings, finding)
+				}
+			}
+			if len(findings) == 0 {
+				info("No findings in %d bookmarks.\n", len(resp.Examples))
+				return nil
+			}
+
+			printFindings(findings)
+
+			counts := map[lint.Severity]int{}
+			bookmarks, fixable := 0, 0
+			for i, finding := range findings {
+				counts[finding.Severity]++
+				if i == 0 || findings[i-1].Command != finding.Command {
+					bookmarks++
+				}
+				if finding.Fixable() {
+					fixable++
+				}
+			}
+			info("\nTotal: %d findings in %d bookmarks (%d errors, %d warnings, %d infos)\n",
+				len(findings), bookmarks, counts[lint.Error], counts[lint.Warning], counts[lint.Info])
+			if fixable > 0 && !lintFix {
+				info("%d can be fixed with 'tools lint --fix'\n", fixable)
+			}
+
+			if counts[lint.Error] > 0 {
+				return errors.New("lint found errors")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&lintFix, "fix", false, "Fix the findings that can be fixed without changing what the command does")
+	cmd.Flags().BoolVar(&lintDryRun, "dry-run", false, "With --fix, only show what would change")
+	cmd.Flags().StringArrayVar(&lintRules, "rule", nil, "Only run this rule (repeatable), see --list-rules")
+	cmd.Flags().StringVar(&lintSeverity, "severity", "info", "Only report findings of at least this severity: info, warning or error")
+	cmd.Flags().BoolVar(&lintList, "list-rules", false, "List the rules and exit")
+
+	return cmd
+}
+
+// selectedLintRules returns the rules named by --rule, or all rules when none is named
+func selectedLintRules(names []string) ([]lint.Rule, error) {
+	if len(names) == 0 {
+		return lint.Rules(), nil
+	}
+	rules := make([]lint.Rule, 0, len(names))
+	for _, name := range names {
+		rule, ok := lint.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown lint rule '%s', see 'tools lint --list-rules'", models.ErrValidation, name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// listLintRules prints the registered rules with their severity
+func listLintRules() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RULE\tSEVERITY\tCHECKS")
+	_, _ = fmt.Fprintln(w, "----\t--------\t------")
+	for _, rule := range lint.Rules() {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", rule.Name, rule.Severity, rule.Description)
+	}
+	return w.Flush()
+}
+
+// printFindings prints the findings grouped by bookmark, each bookmark's command followed by
+// its findings, aligned across bookmarks
+func printFindings(findings []lint.Finding) {
+	severityWidth, ruleWidth := 0, 0
+	for _, finding := range findings {
+		severityWidth = max(severityWidth, len(finding.Severity.String()))
+		ruleWidth = max(ruleWidth, len(finding.Rule))
+	}
+
+	for i, finding := range findings {
+		if i == 0 || findings[i-1].Command != finding.Command {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(finding.Command)
+		}
+		message := finding.Message
+		if finding.Fixable() {
+			message += " (fixable)"
+		}
+		fmt.Printf("  %-*s  %-*s  %s\n", severityWidth, finding.Severity, ruleWidth, finding.Rule, message)
+	}
+}
+
+// fixBookmarks applies the safe fixes of the rules to the bookmarks of the user's store and
+// returns how many bookmarks were, or with dryRun would be, changed
+// A fix whose command is bookmarked already is skipped, as merging is up to 'tools dedupe'.
+func fixBookmarks(ctx context.Context, bookmarks []dto.BookmarkResponse, rules []lint.Rule, dryRun bool) (int, error) {
+	fixed := 0
+	for _, bookmark := range bookmarks {
+		// Read-only layers and the review queue can't be changed here
+		if bookmark.Origin != "" || bookmark.Pending {
+			continue
+		}
+		command, applied := lint.Fix(bookmark, rules)
+		if len(applied) == 0 {
+			continue
+		}
+
+		fmt.Printf("%q -> %q\n", bookmark.Command, command)
+		if dryRun {
+			fixed++
+			continue
+		}
+		_, err := svc.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{Command: bookmark.Command, NewCommand: command})
+		if errors.Is(err, models.ErrAlreadyExists) {
+			fmt.Fprintf(os.Stderr, "Skipped %q: %q is bookmarked already\n", bookmark.Command, command)
+			continue
+		}
+		if err != nil {
+			return fixed, fmt.Errorf("failed to fix '%s': %w", bookmark.Command, err)
+		}
+		fixed++
+	}
+	return fixed, nil
+}
@@ -1,19 +1,102 @@
 package cli
 
 import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/export"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listLimit      int
+	listCursor     string
+	listToolCounts bool
+	listColor      bool
+	listFormat     string
+	listHost       string
+	listAuthor     string
+)
+
 func newListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"l", "ls"},
 		Short:   "List all tool bookmarks",
-		Long:    "Display all CLI tool bookmarks in a formatted table",
+		Long: `Display all CLI tool bookmarks in a formatted table.
+
+Use --limit to page through large stores; the output ends with the command
+that shows the next page. Use --tools to list tool names with their number of
+bookmarks instead.
+
+Use --format alfred or --format raycast to print the JSON those launchers
+expect, with the description as title and the command as subtitle and
+argument, e.g. as an Alfred script filter running 'tools list --format alfred'.
+
+Use --host to list only the bookmarks that run on that SSH host, and --author to
+list only those added by a user of a shared store (see 'team.record_author' in
+the config).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if listToolCounts {
+				return listTools()
+			}
+			switch export.Launcher(listFormat) {
+			case export.LauncherAlfred, export.LauncherRaycast:
+				return listForLauncher(export.Launcher(listFormat))
+			}
+			if listFormat != "table" {
+				return fmt.Errorf("%w: unknown list format '%s' (supported: table, alfred, raycast)", models.ErrValidation, listFormat)
+			}
 			return listExamples()
 		},
 	}
 
+	cmd.Flags().IntVarP(&listLimit, "limit", "l", 0, "Maximum number of bookmarks to show (0 for all)")
+	cmd.Flags().StringVar(&listCursor, "cursor", "", "Continue listing at a cursor printed by a previous page")
+	cmd.Flags().BoolVar(&listToolCounts, "tools", false, "List tool names with their number of bookmarks")
+	cmd.Flags().BoolVar(&listColor, "color", false, "Highlight command syntax (when the terminal supports colors)")
+	cmd.Flags().StringVarP(&listFormat, "format", "f", "table", "Output format: table, alfred or raycast")
+	cmd.Flags().StringVar(&listHost, "host", "", "Only list bookmarks that run on this SSH host")
+	cmd.Flags().StringVar(&listAuthor, "author", "", "Only list bookmarks added by this user")
+	cmd.MarkFlagsMutuallyExclusive("tools", "format")
+	cmd.MarkFlagsMutuallyExclusive("tools", "host")
+	cmd.MarkFlagsMutuallyExclusive("host", "limit")
+	cmd.MarkFlagsMutuallyExclusive("host", "cursor")
+	cmd.MarkFlagsMutuallyExclusive("tools", "author")
+	cmd.MarkFlagsMutuallyExclusive("author", "limit")
+	cmd.MarkFlagsMutuallyExclusive("author", "cursor")
+
 	return cmd
 }
+
+// listForLauncher prints the bookmarks as JSON items for a system launcher
+func listForLauncher(launcher export.Launcher) error {
+	resp, err := listedBookmarks(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list examples: %w", err)
+	}
+	return export.WriteLauncher(os.Stdout, launcher, resp.Examples)
+}
+
+// listedBookmarks returns the page of bookmarks to list, only those of the --host host and
+// the --author author if set
+func listedBookmarks(ctx context.Context) (*dto.ListBookmarksResponse, error) {
+	if listHost == "" && listAuthor == "" {
+		return svc.ListBookmarksPage(ctx, listCursor, listLimit)
+	}
+
+	resp, err := svc.ListBookmarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	examples := []dto.BookmarkResponse{}
+	for _, example := range resp.Examples {
+		if (listHost == "" || example.Host == listHost) && (listAuthor == "" || example.Author == listAuthor) {
+			examples = append(examples, example)
+		}
+	}
+	return &dto.ListBookmarksResponse{Examples: examples, Count: len(examples)}, nil
+}
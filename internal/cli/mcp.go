@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/mcp"
+	"github.com/fgeck/tools/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var mcpMetricsAddr string
+
+func newMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Run as an MCP server over stdio",
+		Long: `Run a Model Context Protocol (MCP) server on stdin/stdout so AI coding
+assistants can list, search and add your bookmarked commands.
+
+Exposed tools: list_bookmarks, search_bookmarks, create_bookmark
+
+With --metrics-addr, Prometheus metrics are served on http://<addr>/metrics while
+the server runs: requests and their latency, the store size, store operation
+latency (with the metrics storage decorator) and the auto-export status.
+/healthz answers 200 while the store is readable, /readyz while it is also
+writable, and 503 otherwise, for liveness and readiness probes.
+
+Example client configuration:
+  {"mcpServers": {"tools": {"command": "tools", "args": ["mcp"]}}}`,
+		Example: `  tools mcp
+  tools mcp --metrics-addr 127.0.0.1:9464`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server := mcp.NewServer(svc, Version)
+			server.Redact(redactor())
+			if mcpMetricsAddr != "" {
+				collector := newCollector()
+				server.Observe(collector.ObserveRequest)
+
+				stop, err := serveMonitoring(mcpMetricsAddr, collector)
+				if err != nil {
+					return err
+				}
+				defer stop()
+			}
+			return server.Serve(cmd.Context(), os.Stdin, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&mcpMetricsAddr, "metrics-addr", "", "Serve Prometheus metrics and health probes on this address, e.g. 127.0.0.1:9464")
+
+	return cmd
+}
+
+// countBookmarks returns the size of the store for the metrics
+func countBookmarks(ctx context.Context) (int, error) {
+	resp, err := svc.ListBookmarks(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// newCollector returns the metrics collector of the store and the auto-export
+func newCollector() *telemetry.Collector {
+	return telemetry.NewCollector(telemetry.Sources{
+		Version:    Version,
+		Bookmarks:  countBookmarks,
+		Store:      storeMetrics,
+		AutoExport: autoExportStatus,
+	})
+}
+
+// monitoringMux serves the collector on /metrics and the store probes on /healthz and /readyz
+func monitoringMux(collector *telemetry.Collector) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", collector)
+	mux.Handle("/healthz", telemetry.Probe(func(ctx context.Context) error {
+		return svc.CheckStore(ctx, false)
+	}))
+	mux.Handle("/readyz", telemetry.Probe(func(ctx context.Context) error {
+		return svc.CheckStore(ctx, true)
+	}))
+	return mux
+}
+
+// listen opens the TCP listener of an address given on the command line
+func listen(addr string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to listen on %s: %v", models.ErrValidation, addr, err)
+	}
+	return listener, nil
+}
+
+// serveMonitoring serves monitoringMux on addr in the background until stop is called
+// The address is reported on stderr, stdout carries the MCP stream.
+func serveMonitoring(addr string, collector *telemetry.Collector) (stop func(), err error) {
+	listener, err := listen(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Handler: monitoringMux(collector)}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			Logger().Error("monitoring server failed", "error", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving Prometheus metrics on http://%s/metrics, probes on /healthz and /readyz\n", listener.Addr())
+	return func() { server.Close() }, nil
+}
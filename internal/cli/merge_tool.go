@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeToolDedupe bool
+
+func newMergeToolCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge-tool <source> <target>",
+		Short: "Reassign all bookmarks of one tool name to another",
+		Long: `Move every bookmark of the source tool to the target tool, e.g. after
+bookmarks were added under inconsistent names such as k8s and kubectl.
+
+Each reassigned bookmark keeps its previous tool name as a revision.
+With --dedupe, bookmarks of the target tool whose commands only differ by
+whitespace are merged afterwards. Bookmarks of read-only layers are skipped.`,
+		Example: `  tools merge-tool k8s kubectl
+  tools merge-tool k8s kubectl --dedupe`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := svc.MergeTool(context.Background(), args[0], args[1], mergeToolDedupe)
+			if err != nil {
+				return fmt.Errorf("failed to merge tool '%s' into '%s': %w", args[0], args[1], err)
+			}
+
+			info("Successfully reassigned %d examples from %s to %s", result.Reassigned, args[0], args[1])
+			if mergeToolDedupe {
+				info(", merged %d duplicates", result.Merged)
+			}
+			if result.Skipped > 0 {
+				info(", skipped %d read-only examples", result.Skipped)
+			}
+			info("\n")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&mergeToolDedupe, "dedupe", false, "Merge bookmarks whose commands only differ by whitespace")
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsSince string
+	metricsTop   int
+)
+
+func newMetricsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Summarize your local bookmark activity",
+		Long: `Summarize adds, edits, deletes, runs and searches recorded in the local
+history file, list the most run bookmarks and most frequent searches, and show
+which bookmarks were not run at all in the period.
+
+Everything is computed from the history file on this machine; nothing is sent
+anywhere. --since accepts days (30d), weeks (4w) or durations such as 12h.`,
+		Example: `  tools metrics
+  tools metrics --since 7d --top 10`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			period, err := parsePeriod(metricsSince)
+			if err != nil {
+				return err
+			}
+
+			resp, err := svc.GetMetrics(context.Background(), time.Now().Add(-period), metricsTop)
+			if err != nil {
+				return fmt.Errorf("failed to compute metrics: %w", err)
+			}
+
+			fmt.Printf("Activity since %s\n\n", resp.Since.Local().Format("2006-01-02"))
+			fmt.Printf("  Added:    %d\n", resp.Adds)
+			fmt.Printf("  Edited:   %d\n", resp.Edits)
+			fmt.Printf("  Deleted:  %d\n", resp.Deletes)
+			fmt.Printf("  Runs:     %d\n", resp.Runs)
+			fmt.Printf("  Searches: %d\n", resp.Searches)
+
+			printActivity("Most run bookmarks", resp.TopRuns)
+			printActivity("Most frequent searches", resp.TopSearches)
+
+			fmt.Printf("\nNot run: %d of %d bookmarks\n", len(resp.Unused), resp.Bookmarks)
+			for i, command := range resp.Unused {
+				if metricsTop > 0 && i == metricsTop {
+					fmt.Printf("  ... and %d more\n", len(resp.Unused)-metricsTop)
+					break
+				}
+				fmt.Printf("  %s\n", summaryLine(command))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&metricsSince, "since", "30d", "Period to summarize, e.g. 30d, 4w or 12h")
+	cmd.Flags().IntVar(&metricsTop, "top", 5, "Number of entries in each list (0 for all)")
+
+	return cmd
+}
+
+// printActivity prints a ranked list of commands or queries with their counts
+func printActivity(title string, counts []dto.ActivityCount) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, c := range counts {
+		fmt.Printf("  %4d  %s\n", c.Count, summaryLine(c.Value))
+	}
+}
+
+// summaryLine returns the first line of a command, marking scripts that continue
+func summaryLine(command string) string {
+	if first, _, ok := strings.Cut(command, "\n"); ok {
+		return first + " …"
+	}
+	return command
+}
+
+// parsePeriod parses the period of a flag, e.g. 30d, 4w or 12h
+func parsePeriod(s string) (time.Duration, error) {
+	d, err := utils.ParsePeriod(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", models.ErrValidation, err)
+	}
+	return d, nil
+}
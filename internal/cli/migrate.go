@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/registry"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom     string
+	migrateTo       string
+	migrateFromPath string
+	migrateToPath   string
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate bookmarks between storage backends",
+		Long: fmt.Sprintf(`Copy all bookmarks from one storage backend into another.
+
+Every bookmark is copied and afterwards verified in the destination.
+Bookmarks that already exist unchanged in the destination are skipped;
+differing bookmarks with the same command abort the migration.
+
+Available backends: %s`, strings.Join(registry.Names(), ", ")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromPath := migrateFromPath
+			if fromPath == "" {
+				fromPath = cfg.StorageFilePath
+			}
+			if migrateFrom == migrateTo && sameFile(fromPath, migrateToPath) {
+				return fmt.Errorf("%w: source and destination are the same store", models.ErrValidation)
+			}
+
+			src, err := registry.Open(migrateFrom, fromPath)
+			if err != nil {
+				return fmt.Errorf("failed to open source store: %w", err)
+			}
+			dst, err := registry.Open(migrateTo, migrateToPath)
+			if err != nil {
+				return fmt.Errorf("failed to open destination store: %w", err)
+			}
+
+			info("Migrating bookmarks from %s (%s) to %s (%s)\n", migrateFrom, fromPath, migrateTo, migrateToPath)
+			progress := func(done, total int, bookmark *models.Bookmark) {
+				info("[%d/%d] %s\n", done, total, bookmark.Command)
+			}
+
+			result, err := service.MigrateBookmarks(context.Background(), src, dst, progress)
+			if err != nil {
+				return fmt.Errorf("migration failed: %w", err)
+			}
+
+			info("Migration complete: %d migrated, %d skipped, %d total (verified)\n", result.Migrated, result.Skipped, result.Total)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&migrateFrom, "from", "yaml", "Source storage backend")
+	cmd.Flags().StringVar(&migrateTo, "to", "yaml", "Destination storage backend")
+	cmd.Flags().StringVar(&migrateFromPath, "from-path", "", "Source storage location (defaults to the configured store)")
+	cmd.Flags().StringVar(&migrateToPath, "to-path", "", "Destination storage location (required)")
+
+	_ = cmd.MarkFlagRequired("to-path")
+
+	return cmd
+}
+
+// sameFile reports whether two paths name the same file, also through a relative path or a
+// symlink; paths of files that do not exist yet are compared as absolute paths
+func sameFile(a, b string) bool {
+	if statA, err := os.Stat(a); err == nil {
+		if statB, err := os.Stat(b); err == nil {
+			return os.SameFile(statA, statB)
+		}
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/spf13/cobra"
+)
+
+var migrateLegacyDryRun bool
+
+func newMigrateLegacyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-legacy [file...]",
+		Short: "Convert stores of the old tools:/examples: layouts",
+		Long: `Convert the legacy layouts of the Tool and ToolExample stores, an 'examples:'
+list and 'tools:' groups with their examples, into the current bookmark format.
+
+Without arguments, the legacy sections of your own store are converted in place;
+the previous file is kept as backup (tools.yaml.bak). With files, their bookmarks
+are converted and added to your store in one pass; commands you already
+bookmarked are kept as they are. Use --dry-run to only report what was found.`,
+		Example: `  tools migrate-legacy --dry-run
+  tools migrate-legacy ~/.config/tool/tools.yaml ~/.config/tool-example/examples.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if len(args) == 0 {
+				files, err := yaml.StoreFiles(cfg.StorageFilePath)
+				if err != nil {
+					return err
+				}
+				for _, file := range files {
+					if err := migrateLegacyStore(file); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			var reqs []dto.CreateBookmarkRequest
+			seen := map[string]bool{}
+			for _, path := range args {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("%w: failed to read legacy store: %v", models.ErrNotFound, err)
+				}
+				sections, err := yaml.DetectLegacy(data)
+				if err != nil {
+					return fmt.Errorf("%w: %s: %v", models.ErrValidation, path, err)
+				}
+				bookmarks, err := yaml.ParseStore(data)
+				if err != nil {
+					return fmt.Errorf("%w: %s: %v", models.ErrValidation, path, err)
+				}
+				printLegacySections(path, sections, len(bookmarks))
+				// The first file of a command wins, like the first entry within a file
+				for _, req := range importRequests(bookmarks) {
+					if !seen[req.Command] {
+						seen[req.Command] = true
+						req.AllowSecrets = true // Bookmarks already stored move as they are
+						reqs = append(reqs, req)
+					}
+				}
+			}
+
+			if migrateLegacyDryRun {
+				preview, err := svc.PreviewImport(ctx, reqs, dto.ConflictSkip)
+				if err != nil {
+					return fmt.Errorf("failed to migrate legacy stores: %w", err)
+				}
+				added := 0
+				for _, entry := range preview.Entries {
+					if entry.Status == dto.ImportAdded {
+						added++
+					}
+				}
+				info("\nDry run: %d bookmarks would be added, %d are bookmarked already\n", added, len(preview.Entries)-added)
+				return nil
+			}
+
+			result, err := svc.ImportBookmarks(ctx, reqs, dto.ConflictSkip)
+			if err != nil {
+				return fmt.Errorf("failed to migrate legacy stores: %w", err)
+			}
+			info("\nSuccessfully migrated legacy stores: %d added, %d skipped\n", result.Added, result.Skipped)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&migrateLegacyDryRun, "dry-run", false, "Only report the legacy sections and what would change")
+
+	return cmd
+}
+
+// migrateLegacyStore converts the legacy sections of the user's store file in place
+func migrateLegacyStore(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+	sections, err := yaml.DetectLegacy(data)
+	if err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+	if !sections.Found() {
+		info("No legacy sections found in %s\n", path)
+		return nil
+	}
+
+	bookmarks, err := yaml.ParseStore(data)
+	if err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+	printLegacySections(path, sections, len(bookmarks))
+	if migrateLegacyDryRun {
+		info("\nDry run: nothing was changed\n")
+		return nil
+	}
+
+	if err := yaml.Upgrade(path); err != nil {
+		return fmt.Errorf("failed to migrate legacy store: %w", err)
+	}
+	StoreChanged()
+	info("\nSuccessfully converted %s to the current format, the previous file is kept in %s\n", path, yaml.BackupPath(path))
+	return nil
+}
+
+// printLegacySections reports the legacy sections found in a file
+func printLegacySections(path string, sections yaml.LegacySections, bookmarks int) {
+	fmt.Printf("%s:\n", path)
+	fmt.Printf("  examples:  %d entries\n", sections.Examples)
+	fmt.Printf("  tools:     %d tools with %d examples\n", sections.Tools, sections.ToolExamples)
+	if sections.Duplicates > 0 {
+		fmt.Printf("  %d duplicate commands are merged\n", sections.Duplicates)
+	}
+	fmt.Printf("  %d bookmarks in the current format\n", bookmarks)
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pickQuery string
+	pickFirst bool
+	pickIndex int
+	pickAlias string
+	pickShell string
+)
+
+func newPickCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pick",
+		Short: "Print one bookmarked command without any UI",
+		Long: `Print a single bookmarked command to stdout, for scripts, keyboard launchers
+(rofi, Raycast) and editor plugins.
+
+With --query, bookmarks are ranked by relevance like 'tools ask'. A unique
+match is printed; when several bookmarks match, pass --first for the best one
+or --index to choose by position. Without --query, --index picks from the list
+shown by 'tools list'.
+
+With --alias, an alias definition running the command is printed instead,
+quoted for --shell (sh, bash, zsh or fish; default: $SHELL) on a single line,
+so multi-line scripts and commands with quotes survive in your shell's rc file.
+
+Exit codes: 2 when nothing matches, 3 when the query is ambiguous.`,
+		Example: `  tools pick --query "pods" --first
+  tools pick --query "restart deployment" --index 2
+  eval "$(tools pick --query 'port forward' --first)"
+  tools pick --query "port forward" --first --alias pf >> ~/.zshrc`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			command, err := pickCommand(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to pick example: %w", err)
+			}
+
+			// Print the variant for this operating system, the bookmark is recorded by its command
+			output := command
+			if bookmark, err := svc.GetBookmark(ctx, command); err == nil {
+				output = bookmark.CommandFor(runtime.GOOS)
+				warnUnmetRequirements(bookmark)
+			}
+			if pickAlias != "" {
+				alias, err := pickAliasLine(output)
+				if err != nil {
+					return err
+				}
+				// Defining an alias is not a run of the bookmark
+				fmt.Println(alias)
+				return nil
+			}
+			fmt.Println(output)
+			return svc.RecordRun(ctx, command)
+		},
+	}
+
+	cmd.Flags().StringVar(&pickQuery, "query", "", "Rank bookmarks by relevance to this text")
+	cmd.Flags().BoolVar(&pickFirst, "first", false, "Print the best match when several bookmarks match")
+	cmd.Flags().IntVarP(&pickIndex, "index", "i", 0, "Print the match at this position, starting at 1")
+	cmd.Flags().StringVar(&pickAlias, "alias", "", "Print an alias of this name running the command")
+	cmd.Flags().StringVar(&pickShell, "shell", "", "Shell the alias is for: sh, bash, zsh or fish (default: $SHELL)")
+	cmd.MarkFlagsMutuallyExclusive("first", "index")
+
+	return cmd
+}
+
+// pickAliasLine returns the definition of the alias --alias running command, for --shell
+// An unsupported $SHELL falls back to sh, whose quoting the other shells understand too.
+func pickAliasLine(command string) (string, error) {
+	shell := utils.ShellPOSIX
+	if pickShell != "" {
+		parsed, err := utils.ParseShell(pickShell)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", models.ErrValidation, err)
+		}
+		shell = parsed
+	} else if parsed, err := utils.ParseShell(os.Getenv("SHELL")); err == nil {
+		shell = parsed
+	}
+
+	alias, err := utils.Alias(shell, pickAlias, command)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", models.ErrValidation, err)
+	}
+	return alias, nil
+}
+
+// pickCommand selects the command chosen by --query, --first and --index
+func pickCommand(ctx context.Context) (string, error) {
+	var commands []string
+	if pickQuery != "" {
+		resp, err := svc.RankBookmarks(ctx, pickQuery, 0)
+		if err != nil {
+			return "", err
+		}
+		for _, result := range resp.Results {
+			commands = append(commands, result.Command)
+		}
+	} else {
+		if pickIndex == 0 {
+			return "", fmt.Errorf("%w: pass --query or --index", models.ErrValidation)
+		}
+		resp, err := svc.ListBookmarks(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, example := range resp.Examples {
+			commands = append(commands, example.Command)
+		}
+	}
+
+	switch {
+	case len(commands) == 0:
+		return "", fmt.Errorf("no example matches '%s': %w", pickQuery, models.ErrNotFound)
+	case pickIndex < 0:
+		return "", fmt.Errorf("%w: --index starts at 1", models.ErrValidation)
+	case pickIndex > len(commands):
+		return "", fmt.Errorf("%w: --index %d is out of range, %d examples match", models.ErrNotFound, pickIndex, len(commands))
+	case pickIndex > 0:
+		return commands[pickIndex-1], nil
+	case len(commands) == 1 || pickFirst:
+		return commands[0], nil
+	}
+
+	// Show the candidates on stderr so stdout stays empty for scripts
+	fmt.Fprintf(os.Stderr, "%d examples match '%s':\n", len(commands), pickQuery)
+	for i, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, summaryLine(command))
+	}
+	return "", fmt.Errorf("%w: %d examples match, pass --first or --index", models.ErrConflict, len(commands))
+}
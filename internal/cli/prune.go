@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/fgeck/tools/internal/toolcheck"
+	"github.com/fgeck/tools/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneUnusedFor   string
+	pruneMissingTool bool
+	pruneArchive     string
+	pruneDryRun      bool
+	pruneYes         bool
+	pruneInteractive bool
+)
+
+// staleBookmark is a bookmark prune offers to remove, with the reason it is considered stale
+type staleBookmark struct {
+	dto.BookmarkResponse
+	reason string
+}
+
+func newPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove bookmarks you no longer use",
+		Long: `Remove stale bookmarks: with --unused-for those that were neither run nor added
+within a period, according to the history, and with --missing-tool those whose
+executable is no longer installed. Bookmarks that never ran since the history
+was started count as unused unless they were changed within the period;
+bookmarks with a reminder are only removed for a missing tool, and the tools of
+bookmarks run on an SSH host are not checked.
+Bookmarks of read-only layers are kept.
+
+The stale bookmarks are listed and you are asked for confirmation first; pass
+--yes to skip the prompt. With --archive they are also saved to a file in the
+store format, so they can be brought back with 'tools import <file>'.
+
+With --interactive you decide for each bookmark: y removes it, a archives and
+removes it, q stops and anything else keeps it. Archived bookmarks go to the
+--archive file, ~/.config/tools/archive.yaml by default.`,
+		Example: `  tools prune --unused-for 180d --dry-run
+  tools prune --unused-for 52w --archive ~/tools-archive.yaml
+  tools prune --unused-for 365d --missing-tool --interactive`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			stale, err := staleBookmarks(ctx)
+			if err != nil {
+				return err
+			}
+			if len(stale) == 0 {
+				info("No stale bookmarks found.\n")
+				return nil
+			}
+
+			if pruneInteractive {
+				return pruneInteractively(ctx, cmd.InOrStdin(), stale)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "TOOL\tCOMMAND\tREASON")
+			for _, b := range stale {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", b.ToolName, summaryLine(b.Command), b.reason)
+			}
+			_ = w.Flush()
+
+			if pruneDryRun {
+				info("\nDry run: %d bookmarks would be removed\n", len(stale))
+				return nil
+			}
+			if !pruneYes {
+				ok, err := confirm(cmd.InOrStdin(), i18n.T("Remove these %d bookmarks?", len(stale)))
+				if err != nil {
+					return fmt.Errorf("failed to prune bookmarks: %w", err)
+				}
+				if !ok {
+					return errAborted
+				}
+			}
+
+			if pruneArchive != "" {
+				if err := archiveBookmarks(ctx, pruneArchive, stale); err != nil {
+					return fmt.Errorf("failed to archive bookmarks: %w", err)
+				}
+			}
+			if err := removeStale(ctx, stale); err != nil {
+				return fmt.Errorf("failed to prune bookmarks: %w", err)
+			}
+
+			if pruneArchive != "" {
+				info("Successfully removed %d stale bookmarks, archived in %s\n", len(stale), pruneArchive)
+			} else {
+				info("Successfully removed %d stale bookmarks\n", len(stale))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pruneUnusedFor, "unused-for", "", "Remove bookmarks not used for this period, e.g. 180d or 26w")
+	cmd.Flags().BoolVar(&pruneMissingTool, "missing-tool", false, "Remove bookmarks whose executable is not installed")
+	cmd.Flags().StringVar(&pruneArchive, "archive", "", "Also save the removed bookmarks to this file, in the store format")
+	cmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Only list the bookmarks that would be removed")
+	cmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().BoolVarP(&pruneInteractive, "interactive", "i", false, "Ask for each bookmark whether to remove, archive or keep it")
+
+	cmd.MarkFlagsOneRequired("unused-for", "missing-tool")
+	cmd.MarkFlagsMutuallyExclusive("interactive", "dry-run")
+	cmd.MarkFlagsMutuallyExclusive("interactive", "yes")
+
+	return cmd
+}
+
+// staleBookmarks returns the bookmarks selected by --unused-for, least recently used first,
+// followed by those selected by --missing-tool
+func staleBookmarks(ctx context.Context) ([]staleBookmark, error) {
+	var stale []staleBookmark
+	seen := map[string]bool{}
+
+	if pruneUnusedFor != "" {
+		period, err := utils.ParsePeriod(pruneUnusedFor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid --unused-for: %v", models.ErrValidation, err)
+		}
+		if period <= 0 {
+			return nil, fmt.Errorf("%w: --unused-for must be a period like 180d", models.ErrValidation)
+		}
+
+		resp, err := svc.ListUnused(ctx, time.Now().Add(-period))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find unused bookmarks: %w", err)
+		}
+		for _, b := range resp.Bookmarks {
+			reason := "never used"
+			if b.LastUsed != nil {
+				reason = "last used " + b.LastUsed.Local().Format("2006-01-02")
+			}
+			stale = append(stale, staleBookmark{BookmarkResponse: b.BookmarkResponse, reason: reason})
+			seen[b.Command] = true
+		}
+	}
+
+	if pruneMissingTool {
+		resp, err := svc.ListBookmarks(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list examples: %w", err)
+		}
+		for _, b := range resp.Examples {
+			if b.Origin != "" || b.Host != "" || seen[b.Command] {
+				continue
+			}
+			binary := toolcheck.Binary(b.CommandFor(runtime.GOOS))
+			if binary == "" {
+				continue
+			}
+			if _, err := exec.LookPath(binary); err != nil {
+				stale = append(stale, staleBookmark{BookmarkResponse: b, reason: "missing " + binary})
+			}
+		}
+	}
+
+	return stale, nil
+}
+
+// pruneInteractively asks for each stale bookmark whether to remove, archive or keep it
+func pruneInteractively(ctx context.Context, in io.Reader, stale []staleBookmark) error {
+	archivePath := pruneArchive
+	if archivePath == "" {
+		archivePath = filepath.Join(config.GetConfigDir(), "archive.yaml")
+	}
+
+	reader := bufio.NewReader(in)
+	var removed []staleBookmark
+	archived := 0
+loop:
+	for i, b := range stale {
+		fmt.Printf("\n[%d/%d] %s (%s)\n", i+1, len(stale), b.Description, b.reason)
+		for _, line := range strings.Split(b.Command, "\n") {
+			fmt.Printf("      $ %s\n", line)
+		}
+
+		answer, eof, err := prompt(reader, i18n.T("Remove? [y/N, a: archive, q: quit] "))
+		if err != nil {
+			return err
+		}
+		switch {
+		case isYes(answer):
+		case isArchive(answer):
+			if err := archiveBookmarks(ctx, archivePath, []staleBookmark{b}); err != nil {
+				return fmt.Errorf("failed to archive bookmarks: %w", err)
+			}
+			archived++
+		case isQuit(answer) || eof:
+			break loop
+		default:
+			continue
+		}
+
+		removed = append(removed, b)
+	}
+
+	// The answers are applied together, also when quitting early
+	if err := removeStale(ctx, removed); err != nil {
+		return fmt.Errorf("failed to prune bookmarks: %w", err)
+	}
+	if archived > 0 {
+		info("\nSuccessfully removed %d stale bookmarks, %d archived in %s\n", len(removed), archived, archivePath)
+	} else {
+		info("\nSuccessfully removed %d stale bookmarks\n", len(removed))
+	}
+	return nil
+}
+
+// removeStale deletes stale bookmarks in one transaction, so a failure removes none of them
+func removeStale(ctx context.Context, stale []staleBookmark) error {
+	return svc.Transaction(ctx, func(tx service.BookmarkService) error {
+		for _, b := range stale {
+			if err := tx.DeleteBookmark(ctx, b.Command); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// isArchive reports whether an answer means archive, in English or the selected language
+func isArchive(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "a", "archive", i18n.T("a"), i18n.T("archive"):
+		return true
+	default:
+		return false
+	}
+}
+
+// archiveBookmarks adds bookmarks to a store file, replacing archived versions of the same command
+func archiveBookmarks(ctx context.Context, path string, bookmarks []staleBookmark) error {
+	archive, err := yaml.NewYAMLBookmarkRepository(path)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bookmarks {
+		bookmark := &models.Bookmark{
+			Command:     b.Command,
+			ToolName:    b.ToolName,
+			Description: b.Description,
+			Tags:        b.Tags,
+			Link:        b.Link,
+			RemindEvery: b.RemindEvery,
+			Host:        b.Host,
+			Variants:    b.Variants,
+			Requires:    b.Requires,
+			Private:     b.Private,
+			Author:      b.Author,
+		}
+		err := archive.Create(ctx, bookmark)
+		if errors.Is(err, models.ErrAlreadyExists) {
+			err = archive.Update(ctx, bookmark)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
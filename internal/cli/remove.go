@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/i18n"
 	"github.com/spf13/cobra"
 )
 
 var (
-	removeCommand  string
-	removeToolName string
+	removeCommand     string
+	removeToolName    string
+	removeYes         bool
+	removeIfUpdatedAt string
 )
 
 func newRemoveCmd() *cobra.Command {
@@ -19,34 +23,71 @@ func newRemoveCmd() *cobra.Command {
 		Short:   "Remove example bookmark(s)",
 		Long: `Remove examples by command or tool name.
 
-Use -c to remove a specific example by its command (primary key).
-Use -n to remove all examples for a tool name.`,
+Use -c to remove a specific example by its command (primary key). The command
+may be abbreviated: a case-insensitive prefix or substring that matches a single
+example selects it, several matches are listed to choose from.
+Use -n to remove all examples for a tool name. It shows how many examples will
+be deleted and asks for confirmation first; pass --yes to skip the prompt.
+
+With -c, --if-updated-at takes the update time shown by 'tools get' and keeps
+the example, exiting with code 3, if it was changed since.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
 			// Must specify either command or tool name, but not both
 			if removeCommand == "" && removeToolName == "" {
-				return fmt.Errorf("must specify either --command (-c) or --name (-n)")
+				return fmt.Errorf("%w: must specify either --command (-c) or --name (-n)", models.ErrValidation)
 			}
 			if removeCommand != "" && removeToolName != "" {
-				return fmt.Errorf("cannot specify both --command and --name, choose one")
+				return fmt.Errorf("%w: cannot specify both --command and --name, choose one", models.ErrValidation)
+			}
+			if removeIfUpdatedAt != "" && removeCommand == "" {
+				return fmt.Errorf("%w: --if-updated-at only works with --command", models.ErrValidation)
 			}
 
 			// Remove by command (single example)
 			if removeCommand != "" {
-				if err := svc.DeleteBookmark(ctx, removeCommand); err != nil {
+				command, err := resolveCommand(ctx, cmd.InOrStdin(), removeCommand)
+				if err != nil {
+					return fmt.Errorf("failed to remove example: %w", err)
+				}
+				updatedAt, err := parseUpdatedAt(removeIfUpdatedAt)
+				if err != nil {
+					return fmt.Errorf("failed to remove example: %w", err)
+				}
+				if updatedAt != nil {
+					err = svc.DeleteBookmarkIfUnchanged(ctx, command, *updatedAt)
+				} else {
+					err = svc.DeleteBookmark(ctx, command)
+				}
+				if err != nil {
 					return fmt.Errorf("failed to remove example: %w", err)
 				}
-				fmt.Printf("Successfully removed example: %s\n", removeCommand)
+				info("Successfully removed example: %s\n", command)
 				return nil
 			}
 
 			// Remove by tool name (all examples for that tool)
 			if removeToolName != "" {
+				if !removeYes {
+					count, err := toolBookmarkCount(ctx, removeToolName)
+					if err != nil {
+						return fmt.Errorf("failed to remove examples for tool '%s': %w", removeToolName, err)
+					}
+					if count > 0 {
+						ok, err := confirm(cmd.InOrStdin(), i18n.T("This will delete %d example(s) for tool '%s'. Continue?", count, removeToolName))
+						if err != nil {
+							return fmt.Errorf("failed to remove examples for tool '%s': %w", removeToolName, err)
+						}
+						if !ok {
+							return errAborted
+						}
+					}
+				}
 				if err := svc.DeleteToolBookmarks(ctx, removeToolName); err != nil {
-					return fmt.Errorf("failed to remove examples for tool '%s': %w", removeToolName, err)
+					return fmt.Errorf("failed to remove examples for tool '%s': %w", removeToolName, toolNotFound(ctx, err, removeToolName))
 				}
-				fmt.Printf("Successfully removed all examples for tool: %s\n", removeToolName)
+				info("Successfully removed all examples for tool: %s\n", removeToolName)
 				return nil
 			}
 
@@ -54,8 +95,24 @@ Use -n to remove all examples for a tool name.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&removeCommand, "command", "c", "", "Remove specific example by command")
+	cmd.Flags().StringVarP(&removeCommand, "command", "c", "", "Remove specific example by full or partial command")
 	cmd.Flags().StringVarP(&removeToolName, "name", "n", "", "Remove all examples for tool name")
+	cmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Do not ask for confirmation when removing by tool name")
+	cmd.Flags().StringVar(&removeIfUpdatedAt, "if-updated-at", "", "Only remove if the example's update time, as shown by 'tools get', is unchanged")
 
 	return cmd
 }
+
+// toolBookmarkCount returns the number of examples stored for a tool name
+func toolBookmarkCount(ctx context.Context, toolName string) (int, error) {
+	resp, err := svc.ListTools(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, tool := range resp.Tools {
+		if tool.ToolName == toolName {
+			return tool.Count, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replaceFind   string
+	replaceWith   string
+	replaceTool   string
+	replaceRegex  bool
+	replaceDryRun bool
+)
+
+func newReplaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replace",
+		Short: "Replace text in bookmarked commands",
+		Long: `Replace text in all bookmarked commands, or in those of one tool with --tool,
+e.g. after a binary was renamed or a flag changed. Each change is shown as a
+diff; use --dry-run to only preview them.
+
+--find is literal text by default. With --regex it is a regular expression
+(Go syntax) and --replace may refer to submatches as $1 or ${name}.
+
+Nothing is changed when a command would become empty or the same as another
+bookmark. The changes are recorded in the history ('tools history').
+Bookmarks of read-only layers are skipped.`,
+		Example: `  tools replace --find kubectl --replace k --tool kubectl --dry-run
+  tools replace --find "--namespace[= ](\S+)" --replace "-n $1" --regex`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := svc.ReplaceInCommands(context.Background(), dto.ReplaceRequest{
+				Find:     replaceFind,
+				Replace:  replaceWith,
+				Regex:    replaceRegex,
+				ToolName: replaceTool,
+				DryRun:   replaceDryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to replace in commands: %w", err)
+			}
+
+			for _, change := range result.Changes {
+				printCommandDiff(change)
+			}
+
+			switch {
+			case len(result.Changes) == 0 && result.Skipped == 0:
+				info("No commands contain '%s'.\n", replaceFind)
+			case replaceDryRun:
+				info("Dry run: %d commands would change", len(result.Changes))
+			default:
+				info("Successfully changed %d commands", len(result.Changes))
+			}
+			if result.Skipped > 0 {
+				info(", skipped %d read-only examples", result.Skipped)
+			}
+			if len(result.Changes) > 0 || result.Skipped > 0 {
+				info("\n")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&replaceFind, "find", "", "Text to find, a regular expression with --regex (required)")
+	cmd.Flags().StringVar(&replaceWith, "replace", "", "Replacement text, may be empty (required)")
+	cmd.Flags().StringVarP(&replaceTool, "tool", "t", "", "Only change commands of this tool")
+	cmd.Flags().BoolVar(&replaceRegex, "regex", false, "Treat --find as a regular expression")
+	cmd.Flags().BoolVar(&replaceDryRun, "dry-run", false, "Only show what would change")
+
+	_ = cmd.MarkFlagRequired("find")
+	_ = cmd.MarkFlagRequired("replace")
+
+	return cmd
+}
+
+// printCommandDiff prints a replaced command as removed and added lines
+func printCommandDiff(change dto.CommandChange) {
+	for _, line := range strings.Split(change.From, "\n") {
+		fmt.Printf("- %s\n", line)
+	}
+	for _, line := range strings.Split(change.To, "\n") {
+		fmt.Printf("+ %s\n", line)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// resolveCommand resolves a full or partial command to a stored command
+// A unique match is used directly; when several bookmarks match, they are listed and
+// the user picks one by number.
+func resolveCommand(ctx context.Context, in io.Reader, query string) (string, error) {
+	matches, err := svc.MatchCommand(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	switch matches.Count {
+	case 0:
+		return "", commandNotFound(ctx, fmt.Errorf("example with command '%s' %w", query, models.ErrNotFound), query)
+	case 1:
+		return matches.Examples[0].Command, nil
+	}
+
+	fmt.Println(i18n.T("%d examples match '%s':", matches.Count, query))
+	for i, example := range matches.Examples {
+		fmt.Printf("  %d) %-15s %s\n", i+1, example.ToolName, summaryLine(example.Command))
+	}
+
+	answer, _, err := prompt(bufio.NewReader(in), i18n.T("Select [1-%d]: ", matches.Count))
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > matches.Count {
+		return "", fmt.Errorf("%w: no example selected", models.ErrValidation)
+	}
+	return matches.Examples[n-1].Command, nil
+}
+
+// suggestLimit is the number of "did you mean" suggestions added to not-found errors
+const suggestLimit = 3
+
+// commandNotFound adds the stored commands closest to command to a not-found error
+func commandNotFound(ctx context.Context, err error, command string) error {
+	if !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+	suggestions, suggestErr := svc.SuggestCommands(ctx, command, suggestLimit)
+	if suggestErr != nil {
+		return err
+	}
+	return didYouMean(err, suggestions)
+}
+
+// toolNotFound adds the stored tool names closest to toolName to a not-found error
+func toolNotFound(ctx context.Context, err error, toolName string) error {
+	if !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+	suggestions, suggestErr := svc.SuggestToolNames(ctx, toolName, suggestLimit)
+	if suggestErr != nil {
+		return err
+	}
+	return didYouMean(err, suggestions)
+}
+
+// didYouMean appends suggestions to an error, keeping it matchable with errors.Is
+func didYouMean(err error, suggestions []string) error {
+	if len(suggestions) == 0 {
+		return err
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = "'" + s + "'"
+	}
+	return fmt.Errorf("%w (did you mean %s?)", err, strings.Join(quoted, " or "))
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/spf13/cobra"
+)
+
+var reviewApproveAll bool
+
+func newReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Approve or reject bookmarks waiting for review",
+		Long: `With review enabled ('review.enabled' in the config), new bookmarks are
+submitted to a review queue instead of the store. They show up in listings,
+searches and the TUI only once they are approved.`,
+	}
+
+	cmd.AddCommand(newReviewListCmd())
+	cmd.AddCommand(newReviewApproveCmd())
+	cmd.AddCommand(newReviewRejectCmd())
+
+	return cmd
+}
+
+func newReviewListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List bookmarks waiting for review",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := svc.ListPending(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to list pending examples: %w", err)
+			}
+
+			if resp.Count == 0 {
+				info("No bookmarks are waiting for review.\n")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TOOL\tDESCRIPTION\tCOMMAND")
+			for _, example := range resp.Examples {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", example.ToolName, example.Description, summaryLine(example.Command))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newReviewApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve <command>...",
+		Short: "Move pending bookmarks into the store",
+		Example: `  tools review approve "kubectl get pods -A"
+  tools review approve --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if reviewApproveAll == (len(args) > 0) {
+				return fmt.Errorf("%w: pass the commands to approve or --all", models.ErrValidation)
+			}
+
+			if reviewApproveAll {
+				resp, err := svc.ListPending(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to list pending examples: %w", err)
+				}
+				for _, example := range resp.Examples {
+					args = append(args, example.Command)
+				}
+			}
+
+			for _, command := range args {
+				resp, err := svc.ApproveBookmark(ctx, command)
+				if err != nil {
+					return fmt.Errorf("failed to approve example: %w", err)
+				}
+				info("Approved command: %s for tool: %s\n", resp.Command, resp.ToolName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&reviewApproveAll, "all", false, "Approve every pending bookmark")
+
+	return cmd
+}
+
+func newReviewRejectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reject <command>...",
+		Short: "Discard pending bookmarks",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, command := range args {
+				if err := svc.RejectBookmark(context.Background(), command); err != nil {
+					return fmt.Errorf("failed to reject example: %w", err)
+				}
+				info("Rejected command: %s\n", command)
+			}
+			return nil
+		},
+	}
+}
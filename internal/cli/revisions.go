@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	revisionsCommand string
+	revisionsRestore int
+)
+
+func newRevisionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "revisions",
+		Aliases: []string{"rev"},
+		Short:   "Show or restore previous versions of a bookmark",
+		Long: `List the previous revisions of a bookmark, including what changed between
+consecutive versions, or restore one of them with --restore.
+
+Revisions are recorded on every edit; the number kept per bookmark is set by
+max_revisions in the config file. Restoring keeps the replaced version as a new
+revision, so a restore can itself be undone.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if revisionsRestore > 0 {
+				resp, err := svc.RestoreRevision(ctx, revisionsCommand, revisionsRestore)
+				if err != nil {
+					return fmt.Errorf("failed to restore revision: %w", err)
+				}
+				info("Successfully restored revision %d: %s\n", revisionsRestore, resp.Command)
+				return nil
+			}
+
+			resp, err := svc.ListRevisions(ctx, revisionsCommand)
+			if err != nil {
+				return fmt.Errorf("failed to list revisions: %w", err)
+			}
+
+			if resp.Count == 0 {
+				info("No previous revisions of '%s'.\n", resp.Current.Command)
+				return nil
+			}
+
+			for i, rev := range resp.Revisions {
+				version := revisionToBookmark(rev)
+				next := resp.Current
+				if i+1 < len(resp.Revisions) {
+					next = revisionToBookmark(resp.Revisions[i+1])
+				}
+
+				fmt.Printf("#%d  replaced %s\n", rev.Number, rev.ChangedAt.Local().Format("2006-01-02 15:04:05"))
+				printBookmarkFields(version)
+				fmt.Printf("    changed:     %s\n\n", strings.Join(describeBookmarkChanges(version, next), "; "))
+			}
+
+			fmt.Println("Current")
+			printBookmarkFields(resp.Current)
+			info("\nTotal: %d revisions (restore with --restore <number>)\n", resp.Count)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&revisionsCommand, "command", "c", "", "Command of the bookmark (required)")
+	cmd.Flags().IntVarP(&revisionsRestore, "restore", "r", 0, "Restore the revision with this number")
+
+	_ = cmd.MarkFlagRequired("command")
+
+	return cmd
+}
+
+// revisionToBookmark converts a revision to a bookmark DTO for comparison
+func revisionToBookmark(rev dto.RevisionResponse) dto.BookmarkResponse {
+	return dto.BookmarkResponse{
+		Command:     rev.Command,
+		ToolName:    rev.ToolName,
+		Description: rev.Description,
+		Tags:        rev.Tags,
+		Link:        rev.Link,
+	}
+}
+
+// printBookmarkFields prints the fields of a bookmark as an indented block
+func printBookmarkFields(bookmark dto.BookmarkResponse) {
+	fmt.Printf("    command:     %s\n", bookmark.Command)
+	fmt.Printf("    tool:        %s\n", bookmark.ToolName)
+	fmt.Printf("    description: %s\n", bookmark.Description)
+	if len(bookmark.Tags) > 0 {
+		fmt.Printf("    tags:        %s\n", strings.Join(bookmark.Tags, ", "))
+	}
+	if bookmark.Link != "" {
+		fmt.Printf("    link:        %s\n", bookmark.Link)
+	}
+	for _, goos := range slices.Sorted(maps.Keys(bookmark.Variants)) {
+		fmt.Printf("    %-13s%s\n", "on "+goos+":", bookmark.Variants[goos])
+	}
+	if len(bookmark.Requires) > 0 {
+		fmt.Printf("    requires:    %s\n", strings.Join(bookmark.Requires, ", "))
+	}
+	if bookmark.Host != "" {
+		fmt.Printf("    host:        %s\n", bookmark.Host)
+	}
+	if bookmark.Private {
+		fmt.Println("    private:     yes")
+	}
+	if bookmark.Author != "" {
+		fmt.Printf("    author:      %s\n", bookmark.Author)
+	}
+	if bookmark.RemindEvery != "" {
+		fmt.Printf("    reminder:    every %s\n", bookmark.RemindEvery)
+	}
+	if bookmark.Origin != "" {
+		fmt.Printf("    origin:      %s\n", bookmark.Origin)
+	}
+}
@@ -3,28 +3,71 @@ package cli
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"slices"
+	"strings"
 	"text/tabwriter"
 
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/export"
+	"github.com/fgeck/tools/internal/highlight"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/logging"
+	"github.com/fgeck/tools/internal/placeholder"
+	"github.com/fgeck/tools/internal/repository/metered"
+	"github.com/fgeck/tools/internal/repository/yaml"
 	"github.com/fgeck/tools/internal/service"
 	"github.com/fgeck/tools/internal/tui"
 	"github.com/fgeck/tools/internal/utils"
+	"github.com/mattn/go-runewidth"
 	"github.com/spf13/cobra"
 )
 
+// Version is the application version, set at build time
+var Version = "dev"
+
 var (
-	svc     service.BookmarkService
-	rootCmd *cobra.Command
-	useCLI  bool
+	svc          service.BookmarkService
+	cfg          *config.Config
+	rootCmd      *cobra.Command
+	useCLI       bool
+	expandEnv    bool
+	placeholders bool
+	plain        bool
+	quiet        bool
+	logLevel     string
+	logFile      string
+
+	// commandStarted is set once cobra has parsed flags and arguments and runs a command
+	// Errors returned before that are usage errors
+	commandStarted bool
+
+	// shutdownFuncs run after the command, before the process exits
+	shutdownFuncs []func() error
+
+	// storeChanged is set when the command changed the store, see StoreChanged
+	storeChanged bool
+
+	// logHandler receives the log of services and repositories, see Logger
+	logHandler = logging.NewSwitch()
+
+	// storeMetrics and autoExportStatus are exported by 'tools mcp --metrics-addr' and 'tools serve'
+	storeMetrics     *metered.Metrics
+	autoExportStatus func() export.MirrorStatus
 )
 
-// Initialize sets up the CLI with the provided service
-func Initialize(exampleService service.BookmarkService) {
+// Initialize sets up the CLI with the provided service and configuration
+func Initialize(exampleService service.BookmarkService, appConfig *config.Config) {
 	svc = exampleService
+	cfg = appConfig
 
 	rootCmd = &cobra.Command{
-		Use:   "tools",
-		Short: "A bookmark manager for your terminal",
+		Use:     "tools",
+		Short:   "A bookmark manager for your terminal",
+		Version: Version,
 		Long: `The single CLI tool to view, add or remove CLI tools.
 Consider it as a bookmark manager for your terminal.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -32,46 +75,261 @@ Consider it as a bookmark manager for your terminal.`,
 			if useCLI {
 				return listExamples()
 			}
-			return tui.Run(svc)
+			opts := tui.Options{
+				ExpandEnv:    expandEnv,
+				Icons:        cfg.TUI.Icons,
+				Columns:      tuiColumns(cfg.TUI.Columns),
+				StateFile:    cfg.TUI.StateFile,
+				Sidebar:      cfg.TUI.Sidebar,
+				ToolDefaults: tuiToolDefaults,
+				Canonical:    cfg.CanonicalCommands,
+				Redactor:     redactor(),
+				// Dumb terminals can't draw the table, fall back to plain mode
+				Plain: plain || cfg.TUI.Plain || os.Getenv("TERM") == "dumb",
+			}
+			if placeholders || cfg.Placeholders.Enabled {
+				opts.Placeholders = &placeholder.Runner{Timeout: cfg.Placeholders.Timeout, Trusted: cfg.Placeholders.Trusted}
+			}
+			if !opts.Plain {
+				if err := offerStarterPacks(context.Background()); err != nil {
+					return err
+				}
+			}
+			return tui.Run(svc, opts)
 		},
 	}
 
-	// Add global flag
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// Cobra checks flag constraints after this hook; check them first so they count as usage errors
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err
+		}
+
+		if err := configureLogging(); err != nil {
+			return err
+		}
+
+		commandStarted = true
+		// Scripts only need the error message, not the usage text
+		cmd.SilenceUsage = quiet
+		return nil
+	}
+
+	// Add global flags
 	rootCmd.PersistentFlags().BoolVar(&useCLI, "cli", false, "Use classic CLI mode instead of TUI")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-essential output (for scripts, rely on the exit code)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn or error (default: no log, or info with --log-file)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append the log to this file instead of stderr")
+	rootCmd.Flags().BoolVar(&expandEnv, "expand-env", false, "Expand $VAR/${VAR} references from the environment when selecting a command")
+	rootCmd.Flags().BoolVar(&placeholders, "placeholders", false, "Fill dynamic placeholders like {pod:kubectl get pods -o name} from their command's output when selecting")
+	rootCmd.Flags().BoolVar(&plain, "plain", false, "Accessible mode: numbered, line-oriented selection without colors or full-screen drawing")
 
 	// Add subcommands
 	rootCmd.AddCommand(newAddCmd())
 	rootCmd.AddCommand(newListCmd())
+	rootCmd.AddCommand(newToolsCmd())
+	rootCmd.AddCommand(newGetCmd())
 	rootCmd.AddCommand(newEditCmd())
+	rootCmd.AddCommand(newDuplicateCmd())
 	rootCmd.AddCommand(newRemoveCmd())
+	rootCmd.AddCommand(newMergeToolCmd())
+	rootCmd.AddCommand(newReplaceCmd())
+	rootCmd.AddCommand(newTagCmd())
+	rootCmd.AddCommand(newFilterCmd())
+	rootCmd.AddCommand(newMigrateCmd())
+	rootCmd.AddCommand(newMigrateLegacyCmd())
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newSnapshotCmd())
+	rootCmd.AddCommand(newDedupeCmd())
+	rootCmd.AddCommand(newPruneCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newEventsCmd())
+	rootCmd.AddCommand(newRunsCmd())
+	rootCmd.AddCommand(newRunbookCmd())
+	rootCmd.AddCommand(newDueCmd())
+	rootCmd.AddCommand(newCronCmd())
+	rootCmd.AddCommand(newMetricsCmd())
+	rootCmd.AddCommand(newRevisionsCmd())
+	rootCmd.AddCommand(newMCPCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newAskCmd())
+	rootCmd.AddCommand(newBundleCmd())
+	rootCmd.AddCommand(newStarterCmd())
+	rootCmd.AddCommand(newDemoCmd())
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.AddCommand(newScanCmd())
+	rootCmd.AddCommand(newCheckCmd())
+	rootCmd.AddCommand(newLintCmd())
+	rootCmd.AddCommand(newRunCmd())
+	rootCmd.AddCommand(newPickCmd())
+	rootCmd.AddCommand(newCaptureCmd())
+	rootCmd.AddCommand(newTriageCmd())
+	rootCmd.AddCommand(newReviewCmd())
+	rootCmd.AddCommand(newSelfUpdateCmd())
+}
+
+// OnShutdown registers fn to run after the command, e.g. to finish pending writes
+// An error of fn is printed and makes the process exit with an error code.
+func OnShutdown(fn func() error) {
+	shutdownFuncs = append(shutdownFuncs, fn)
+}
+
+// Logger returns the logger to pass to services and repositories
+// It writes nothing unless --log-level or --log-file is given.
+func Logger() *slog.Logger {
+	return slog.New(logHandler)
+}
+
+// configureLogging directs the log to the destination and level given by the log flags
+func configureLogging() error {
+	if logLevel == "" && logFile == "" {
+		logHandler.Set(slog.DiscardHandler)
+		return nil
+	}
+
+	handler, closer, err := logging.Open(logLevel, config.ExpandHome(logFile))
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrValidation, err)
+	}
+	logHandler.Set(handler)
+	OnShutdown(closer.Close)
+	return nil
+}
+
+// SetStoreMetrics passes the collector of the metrics decorator, whose store operations
+// 'tools mcp --metrics-addr' and 'tools serve' export
+func SetStoreMetrics(m *metered.Metrics) {
+	storeMetrics = m
+}
+
+// SetAutoExportStatus passes the status of the auto-export, exported by
+// 'tools mcp --metrics-addr' and 'tools serve'
+func SetAutoExportStatus(fn func() export.MirrorStatus) {
+	autoExportStatus = fn
+}
+
+// StoreChanged tells the CLI that the running command changed the store, so it checks the
+// store size afterwards; pass it to service.WithOnChange
+func StoreChanged() {
+	storeChanged = true
 }
 
-// Execute runs the root command
+// Execute runs the root command and exits with the code matching its result
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	os.Exit(shutdown(run()))
+}
+
+// shutdown runs the registered shutdown functions and returns the final exit code
+func shutdown(code int) int {
+	for _, fn := range shutdownFuncs {
+		if err := fn(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			if code == ExitOK {
+				code = ExitError
+			}
+		}
+	}
+	return code
+}
+
+// run executes the root command and returns the process exit code
+// Usage errors (unknown commands, invalid flags or arguments) count as validation errors.
+func run() int {
+	commandStarted = false
+	storeChanged = false
+	err := rootCmd.Execute()
+	if err == nil {
+		if storeChanged {
+			warnLargeStore()
+		}
+		return ExitOK
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	Logger().Error("command failed", "error", err)
+	if !commandStarted {
+		return ExitValidation
+	}
+	return ExitCode(err)
+}
+
+// warnLargeStore suggests cleaning up once the user's store grew beyond the configured limits
+func warnLargeStore() {
+	if quiet {
+		return
+	}
+	resp, err := svc.ListBookmarks(context.Background())
+	if err != nil {
+		return
+	}
+	entries := 0
+	for _, example := range resp.Examples {
+		if example.Origin == "" {
+			entries++
+		}
+	}
+	var size int64
+	files, _ := yaml.StoreFiles(cfg.StorageFilePath)
+	for _, file := range files {
+		if stat, err := os.Stat(file); err == nil {
+			size += stat.Size()
+		}
+	}
+
+	if cfg.StoreLimit.Exceeded(entries, size) {
+		fmt.Fprintln(os.Stderr, i18n.T("Warning: the store has grown to %d bookmarks (%d KB), beyond 'store_limit' in the config. Remove bookmarks you no longer use with 'tools prune --unused-for 180d'.",
+			entries, size/1024))
+	}
+}
+
+// info prints non-essential output such as confirmations and totals, unless --quiet is set
+func info(format string, a ...any) {
+	if !quiet {
+		fmt.Printf(format, a...)
 	}
 }
 
 // listExamples is a shared function for displaying examples in table format
+// It shows the page selected by --limit and --cursor, the examples of --host, or all
+// examples when they are unset
 func listExamples() error {
-	resp, err := svc.ListBookmarks(context.Background())
+	resp, err := listedBookmarks(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to list examples: %w", err)
 	}
+	resp.Examples = redactor().Bookmarks(resp.Examples)
 
+	if resp.Count == 0 && listHost != "" {
+		info("No examples found for host '%s'.\n", listHost)
+		return nil
+	}
+	if resp.Count == 0 && listAuthor != "" {
+		info("No examples found by author '%s'.\n", listAuthor)
+		return nil
+	}
 	if resp.Count == 0 {
-		fmt.Println("No examples found. Use 'tools add' to add your first example.")
+		info("No examples found. Use 'tools add' to add your first example.\n")
 		return nil
 	}
 
 	// Create tabwriter for aligned output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-	// Print header
-	_, _ = fmt.Fprintln(w, "TOOL\tDESCRIPTION\tCOMMAND")
-	_, _ = fmt.Fprintln(w, "----\t-----------\t-------")
+	// Only show where bookmarks come from when read-only layers contribute any, and who
+	// added them when authors are recorded
+	showOrigin := slices.ContainsFunc(resp.Examples, func(e dto.BookmarkResponse) bool { return e.Origin != "" })
+	showAuthor := slices.ContainsFunc(resp.Examples, func(e dto.BookmarkResponse) bool { return e.Author != "" })
+	var trailing []string
+	if showOrigin {
+		trailing = append(trailing, "ORIGIN")
+	}
+	if showAuthor {
+		trailing = append(trailing, "AUTHOR")
+	}
 
 	// Define column widths for wrapping
 	const (
@@ -79,6 +337,36 @@ func listExamples() error {
 		commandWidth     = 50
 	)
 
+	// With --color commands are highlighted. The tabwriter would count their escape
+	// sequences as text, so when columns follow, the command column is padded here.
+	columnWidth := 0
+	if listColor && len(trailing) > 0 {
+		columnWidth = len("COMMAND")
+		for _, example := range resp.Examples {
+			for _, line := range utils.WrapToLines(example.Command, commandWidth) {
+				columnWidth = max(columnWidth, runewidth.StringWidth(line))
+			}
+		}
+	}
+	commandCell := func(plain, rendered string) string {
+		switch {
+		case len(trailing) == 0:
+			return rendered
+		case columnWidth == 0:
+			return rendered + "\t"
+		default:
+			return rendered + strings.Repeat(" ", columnWidth-runewidth.StringWidth(plain)+2)
+		}
+	}
+
+	// Print header
+	underlines := make([]string, len(trailing))
+	for i, title := range trailing {
+		underlines[i] = strings.Repeat("-", len(title))
+	}
+	_, _ = fmt.Fprintln(w, "TOOL\tDESCRIPTION\t"+commandCell("COMMAND", "COMMAND")+strings.Join(trailing, "\t"))
+	_, _ = fmt.Fprintln(w, "----\t-----------\t"+commandCell("-------", "-------")+strings.Join(underlines, "\t"))
+
 	// Print rows with wrapping support
 	for _, example := range resp.Examples {
 		rows := utils.SplitWrappedRows(
@@ -89,13 +377,58 @@ func listExamples() error {
 			commandWidth,
 		)
 
-		for _, row := range rows {
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", row[0], row[1], row[2])
+		for i, row := range rows {
+			command := row[2]
+			if listColor {
+				command = highlight.Command(command)
+			}
+			origin, author := "", ""
+			if i == 0 {
+				origin, author = originLabel(example.Origin), example.Author
+			}
+			var cells []string
+			if showOrigin {
+				cells = append(cells, origin)
+			}
+			if showAuthor {
+				cells = append(cells, author)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s%s\n", row[0], row[1], commandCell(row[2], command), strings.Join(cells, "\t"))
 		}
 	}
 
 	_ = w.Flush()
-	fmt.Printf("\nTotal: %d examples\n", resp.Count)
+	switch {
+	case resp.NextCursor != "":
+		info("\nShowing %d examples. Next page: tools list --limit %d --cursor %s\n", resp.Count, listLimit, resp.NextCursor)
+	case listCursor != "":
+		info("\nShowing %d examples (last page)\n", resp.Count)
+	default:
+		info("\nTotal: %d examples\n", resp.Count)
+	}
 
 	return nil
 }
+
+// originLabel names the store a bookmark comes from
+func originLabel(origin string) string {
+	if origin == "" {
+		return "user"
+	}
+	return origin
+}
+
+// tuiToolDefaults returns the configured description and tags of new bookmarks of a tool
+func tuiToolDefaults(tool, command string) (string, []string) {
+	defaults := cfg.DefaultsFor(tool)
+	return defaults.RenderDescription(tool, command), defaults.Tags
+}
+
+// tuiColumns converts the configured table layout to TUI columns
+func tuiColumns(columns []config.ColumnConfig) []tui.Column {
+	result := make([]tui.Column, len(columns))
+	for i, c := range columns {
+		result[i] = tui.Column{Name: c.Name, Weight: c.Width}
+	}
+	return result
+}
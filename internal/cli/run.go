@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/runlog"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/toolcheck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runCommand string
+	runPrint   bool
+	runCapture bool
+	runYes     bool
+)
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a bookmarked command",
+		Long: `Run a bookmarked command in your shell, identified by its command (primary key).
+
+Multi-line commands are written to a temporary script and executed. Use --print
+to write the script and print its path instead of running it.
+
+The exit code and duration of each run are recorded in the history; the last
+result is shown by 'tools get' and in the details of the interactive mode.
+
+Use --capture (or 'runs.capture' in the config) to also save the output of the
+command, which is then still shown while it runs. Review it later with
+'tools runs show <id>'. While capturing, stderr is combined into stdout.
+
+Bookmarks with a variant for this operating system run that variant. Bookmarks
+with a host run their command there through 'ssh <host>', without a variant for
+the local operating system, after asking for confirmation unless --yes is set. So do commands with parts hidden by the redaction settings of the
+config, before they are revealed by running or printing them.
+
+A warning is printed when environment variables or executables the bookmark
+requires (see 'tools add --requires') are missing.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			bookmark, err := svc.GetBookmark(ctx, runCommand)
+			if err != nil {
+				return fmt.Errorf("failed to run example: %w", commandNotFound(ctx, err, runCommand))
+			}
+
+			warnUnmetRequirements(bookmark)
+			// The variants are for this operating system, a bookmark with a host runs its
+			// command as stored on that host
+			command, remote := bookmark.CommandFor(runtime.GOOS), ""
+			if bookmark.Host != "" {
+				remote = bookmark.Command
+				command = runner.SSHCommand(bookmark.Host, remote)
+			}
+
+			if err := confirmReveal(cmd.InOrStdin(), command, runYes); err != nil {
+				return err
+			}
+
+			if runPrint {
+				if !runner.IsScript(command) {
+					fmt.Println(command)
+					return nil
+				}
+				path, err := runner.WriteScript(command)
+				if err != nil {
+					return err
+				}
+				fmt.Println(path)
+				return nil
+			}
+
+			if bookmark.Host != "" && !runYes {
+				ok, err := confirm(cmd.InOrStdin(), i18n.T("Run '%s' on %s via ssh?", summaryLine(redactor().Redact(remote)), bookmark.Host))
+				if err != nil {
+					return fmt.Errorf("failed to run example: %w", err)
+				}
+				if !ok {
+					return errAborted
+				}
+			}
+
+			if err := svc.RecordRun(ctx, bookmark.Command); err != nil {
+				return err
+			}
+
+			stdout, stderr := io.Writer(os.Stdout), io.Writer(os.Stderr)
+			var runs *runlog.Manager
+			var run *runlog.Run
+			if runCapture || cfg.Runs.Capture {
+				var output *os.File
+				runs = runlog.NewManager(cfg.Runs.Dir, cfg.Runs.Keep)
+				run, output, err = runs.Start(bookmark.Command)
+				if err != nil {
+					return fmt.Errorf("failed to capture output: %w", err)
+				}
+				defer func() { _ = output.Close() }()
+				// One writer for both streams keeps their order in the log
+				stdout = io.MultiWriter(os.Stdout, output)
+				stderr = stdout
+			}
+
+			result, err := runner.RunTimed(ctx, command, os.Stdin, stdout, stderr)
+			if err != nil {
+				return fmt.Errorf("command failed: %w", err)
+			}
+			if err := svc.RecordRunResult(ctx, bookmark.Command, result.ExitCode, result.Duration); err != nil {
+				return err
+			}
+			if run != nil {
+				if err := runs.Finish(run, result.ExitCode, result.Duration); err != nil {
+					return fmt.Errorf("failed to capture output: %w", err)
+				}
+				// On stderr, so that piping the command's output stays clean
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "Output saved as run %s, show it with 'tools runs show %s'\n", run.ID, run.ID)
+				}
+			}
+			if result.ExitCode != 0 {
+				return fmt.Errorf("command failed with exit code %d", result.ExitCode)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&runCommand, "command", "c", "", "Command to run (required)")
+	cmd.Flags().BoolVar(&runPrint, "print", false, "Print the command, or the path of the script for multi-line commands, instead of running it")
+	cmd.Flags().BoolVar(&runCapture, "capture", false, "Save the output of the command for 'tools runs show'")
+	cmd.Flags().BoolVarP(&runYes, "yes", "y", false, "Do not ask for confirmation before running on a bookmark's host or revealing redacted values")
+
+	_ = cmd.MarkFlagRequired("command")
+
+	return cmd
+}
+
+// warnUnmetRequirements prints a warning to stderr when environment variables or executables
+// the bookmark requires are missing. The requirements of a bookmark with a host are not
+// checked, they concern that host.
+func warnUnmetRequirements(bookmark *dto.BookmarkResponse) {
+	if bookmark.Host != "" {
+		return
+	}
+	if unmet := toolcheck.Unmet(bookmark.Requires, exec.LookPath, os.Getenv); len(unmet) > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("Warning: missing requirements: %s", strings.Join(unmet, ", ")))
+	}
+}
@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/runbook"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runbookSteps       []string
+	runbookDescription string
+	runbookForce       bool
+	runbookYes         bool
+	runbookFrom        int
+)
+
+func newRunbookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runbook",
+		Short: "Run sequences of bookmarks step by step",
+		Long: `A runbook is a named, ordered list of bookmarks, e.g. the steps of a deploy.
+Running it shows each step and asks before running it; a failing step stops the
+runbook. Runbooks are kept in ~/.config/tools/runbooks.yaml ('runbooks_file' in
+the config).`,
+	}
+
+	cmd.AddCommand(newRunbookCreateCmd())
+	cmd.AddCommand(newRunbookListCmd())
+	cmd.AddCommand(newRunbookShowCmd())
+	cmd.AddCommand(newRunbookRunCmd())
+	cmd.AddCommand(newRunbookDeleteCmd())
+
+	return cmd
+}
+
+func newRunbookCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a runbook from bookmarks",
+		Long: `Create a runbook whose steps are the given bookmarks, in order:
+
+  tools runbook create deploy -d "Deploy the app" \
+    -c "make test" -c "make build" -c "kubectl apply -f deploy.yaml"
+
+Use --force to replace an existing runbook of the same name.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			steps := make([]string, 0, len(runbookSteps))
+			for _, command := range runbookSteps {
+				bookmark, err := svc.GetBookmark(ctx, command)
+				if err != nil {
+					return fmt.Errorf("failed to create runbook: %w", commandNotFound(ctx, err, command))
+				}
+				steps = append(steps, bookmark.Command)
+			}
+
+			rb := runbook.Runbook{Name: args[0], Description: runbookDescription, Steps: steps}
+			if err := runbook.NewStore(cfg.RunbooksFile).Save(rb, runbookForce); err != nil {
+				return fmt.Errorf("failed to create runbook: %w", err)
+			}
+
+			info("Successfully created runbook '%s' with %d steps\n", rb.Name, len(rb.Steps))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&runbookSteps, "command", "c", nil, "Bookmarked command of a step, repeat for each step in order (required)")
+	cmd.Flags().StringVarP(&runbookDescription, "description", "d", "", "Description of the runbook")
+	cmd.Flags().BoolVarP(&runbookForce, "force", "f", false, "Replace an existing runbook of the same name")
+
+	_ = cmd.MarkFlagRequired("command")
+
+	return cmd
+}
+
+func newRunbookListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List runbooks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runbooks, err := runbook.NewStore(cfg.RunbooksFile).List()
+			if err != nil {
+				return fmt.Errorf("failed to list runbooks: %w", err)
+			}
+
+			if len(runbooks) == 0 {
+				info("No runbooks found. Use 'tools runbook create' to make one.\n")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tSTEPS\tDESCRIPTION")
+			for _, rb := range runbooks {
+				_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", rb.Name, len(rb.Steps), rb.Description)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newRunbookShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the steps of a runbook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rb, err := runbook.NewStore(cfg.RunbooksFile).Get(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to show runbook: %w", err)
+			}
+
+			fmt.Printf("Runbook %s", rb.Name)
+			if rb.Description != "" {
+				fmt.Printf(": %s", rb.Description)
+			}
+			fmt.Println()
+			for i, step := range runbookStepBookmarks(context.Background(), rb) {
+				printRunbookStep(i+1, len(rb.Steps), step)
+			}
+			return nil
+		},
+	}
+}
+
+func newRunbookRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a runbook step by step",
+		Long: `Run the steps of a runbook in order. Before each step, its description and
+command are shown and you are asked whether to run it: y runs the step, s skips
+it and anything else stops the runbook. Use --yes to run all steps without
+asking.
+
+A step that exits with a non-zero code stops the runbook. Continue after fixing
+the problem with --from, e.g. 'tools runbook run deploy --from 3'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			rb, err := runbook.NewStore(cfg.RunbooksFile).Get(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to run runbook: %w", err)
+			}
+			if runbookFrom < 1 || runbookFrom > len(rb.Steps) {
+				return fmt.Errorf("%w: --from must be between 1 and %d", models.ErrValidation, len(rb.Steps))
+			}
+
+			// Check all steps first, so a removed bookmark does not stop the runbook halfway
+			steps := runbookStepBookmarks(ctx, rb)
+			for i, step := range steps {
+				if step == nil {
+					return fmt.Errorf("failed to run runbook: step %d '%s' is no longer bookmarked; update the runbook with 'tools runbook create %s --force'",
+						i+1, rb.Steps[i], rb.Name)
+				}
+			}
+
+			reader := bufio.NewReader(cmd.InOrStdin())
+			for i := runbookFrom - 1; i < len(steps); i++ {
+				step := steps[i]
+				fmt.Println()
+				printRunbookStep(i+1, len(steps), step)
+				warnUnmetRequirements(step)
+
+				if !runbookYes {
+					answer, _, err := prompt(reader, i18n.T("Run step %d? [y/N, s: skip] ", i+1))
+					if err != nil {
+						return err
+					}
+					if isSkip(answer) {
+						continue
+					}
+					if !isYes(answer) {
+						return fmt.Errorf("stopped runbook '%s' before step %d; continue with 'tools runbook run %s --from %d'", rb.Name, i+1, rb.Name, i+1)
+					}
+				}
+
+				if err := svc.RecordRun(ctx, step.Command); err != nil {
+					return err
+				}
+				command := step.CommandFor(runtime.GOOS)
+				if step.Host != "" {
+					command = runner.SSHCommand(step.Host, command)
+				}
+				result, err := runner.RunTimed(ctx, command, os.Stdin, os.Stdout, os.Stderr)
+				if err != nil {
+					return fmt.Errorf("step %d failed: %w", i+1, err)
+				}
+				if err := svc.RecordRunResult(ctx, step.Command, result.ExitCode, result.Duration); err != nil {
+					return err
+				}
+				if result.ExitCode != 0 {
+					return fmt.Errorf("step %d failed with exit code %d; continue with 'tools runbook run %s --from %d'", i+1, result.ExitCode, rb.Name, i+1)
+				}
+			}
+
+			info("\nSuccessfully ran runbook '%s'\n", rb.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&runbookYes, "yes", "y", false, "Run all steps without asking")
+	cmd.Flags().IntVar(&runbookFrom, "from", 1, "Start at this step")
+
+	return cmd
+}
+
+func newRunbookDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a runbook",
+		Long:    "Delete a runbook. The bookmarks of its steps are kept.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := runbook.NewStore(cfg.RunbooksFile).Delete(args[0]); err != nil {
+				return fmt.Errorf("failed to delete runbook: %w", err)
+			}
+			info("Successfully deleted runbook '%s'\n", args[0])
+			return nil
+		},
+	}
+}
+
+// runbookStepBookmarks looks up the bookmark of each step, nil for steps no longer bookmarked
+func runbookStepBookmarks(ctx context.Context, rb *runbook.Runbook) []*dto.BookmarkResponse {
+	steps := make([]*dto.BookmarkResponse, len(rb.Steps))
+	for i, command := range rb.Steps {
+		if bookmark, err := svc.GetBookmark(ctx, command); err == nil {
+			steps[i] = bookmark
+		}
+	}
+	return steps
+}
+
+// printRunbookStep prints the number, description and command of a step
+func printRunbookStep(number, total int, step *dto.BookmarkResponse) {
+	if step == nil {
+		fmt.Printf("  %d/%d  (no longer bookmarked)\n", number, total)
+		return
+	}
+	if step.Host != "" {
+		fmt.Printf("  %d/%d  %s (on %s)\n", number, total, step.Description, step.Host)
+	} else {
+		fmt.Printf("  %d/%d  %s\n", number, total, step.Description)
+	}
+	for _, line := range strings.Split(step.CommandFor(runtime.GOOS), "\n") {
+		fmt.Printf("        $ %s\n", line)
+	}
+}
+
+// isSkip reports whether an answer means skip, in English or the selected language
+func isSkip(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "s", "skip", i18n.T("s"), i18n.T("skip"):
+		return true
+	default:
+		return false
+	}
+}
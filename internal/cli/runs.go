@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fgeck/tools/internal/runlog"
+	"github.com/spf13/cobra"
+)
+
+var runsListCommand string
+
+func newRunsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Review the captured output of previous runs",
+		Long: `Runs started with 'tools run --capture' (or with 'runs.capture' in the config)
+save the output of the command in the runs directory (~/.config/tools/runs by
+default, 'runs.dir' in the config). The latest 50 runs are kept ('runs.keep').`,
+	}
+
+	cmd.AddCommand(newRunsListCmd())
+	cmd.AddCommand(newRunsShowCmd())
+
+	return cmd
+}
+
+func newRunsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List captured runs, oldest first",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runs, err := runlog.NewManager(cfg.Runs.Dir, cfg.Runs.Keep).List()
+			if err != nil {
+				return fmt.Errorf("failed to list runs: %w", err)
+			}
+
+			filter := strings.TrimSpace(runsListCommand)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tSTARTED\tEXIT\tDURATION\tCOMMAND")
+			count := 0
+			for _, run := range runs {
+				if filter != "" && run.Command != filter {
+					continue
+				}
+				command, _, script := strings.Cut(run.Command, "\n")
+				if script {
+					command += " ..."
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", run.ID, run.Started.Local().Format("2006-01-02 15:04:05"),
+					runExitCode(run), run.Duration.Round(time.Millisecond), command)
+				count++
+			}
+
+			if count == 0 {
+				info("No captured runs found. Use 'tools run --capture' to save the output of a run.\n")
+				return nil
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVarP(&runsListCommand, "command", "c", "", "Only list runs of this command")
+
+	return cmd
+}
+
+func newRunsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show [id]",
+		Short: "Show the output of a captured run",
+		Long: `Show the command, result and captured output of a run, the latest run
+without an ID. With --quiet only the output is printed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager := runlog.NewManager(cfg.Runs.Dir, cfg.Runs.Keep)
+
+			var run *runlog.Run
+			if len(args) == 1 {
+				var err error
+				if run, err = manager.Get(args[0]); err != nil {
+					return fmt.Errorf("failed to show run: %w", err)
+				}
+			} else {
+				runs, err := manager.List()
+				if err != nil {
+					return fmt.Errorf("failed to show run: %w", err)
+				}
+				if len(runs) == 0 {
+					info("No captured runs found. Use 'tools run --capture' to save the output of a run.\n")
+					return nil
+				}
+				run = &runs[len(runs)-1]
+			}
+
+			info("Run %s\n", run.ID)
+			info("    command:     %s\n", run.Command)
+			info("    started:     %s\n", run.Started.Local().Format("2006-01-02 15:04:05"))
+			info("    exit code:   %s\n", runExitCode(*run))
+			info("    duration:    %s\n\n", run.Duration.Round(time.Millisecond))
+
+			output, err := os.Open(run.OutputPath())
+			if err != nil {
+				return fmt.Errorf("failed to show run: %w", err)
+			}
+			defer func() { _ = output.Close() }()
+			if _, err := io.Copy(os.Stdout, output); err != nil {
+				return fmt.Errorf("failed to show run: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// runExitCode formats the exit code of a run, "-" if the run did not finish
+func runExitCode(run runlog.Run) string {
+	if run.ExitCode == nil {
+		return "-"
+	}
+	return strconv.Itoa(*run.ExitCode)
+}
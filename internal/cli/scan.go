@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanAll  bool
+	scanTags []string
+)
+
+func newScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan [dir]",
+		Short: "Propose bookmarks from the compose files and Makefiles of a project",
+		Long: `Scan the repository containing dir (default: the current directory) for
+compose files, Makefiles and Justfiles and offer their common invocations as
+bookmarks: docker compose up, down, ps and logs for the project, a shell, the
+logs and a restart per service, and each Makefile target and Justfile recipe.
+
+Commands work from any directory and are tagged with the project's name.
+Commands that are already bookmarked are not offered again.
+
+Answer y to add a command, n to skip it, a to add it and all remaining ones,
+or q to stop. Use --all to add every proposed command without asking.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			root, err := scan.Root(dir)
+			if err != nil {
+				return fmt.Errorf("failed to scan project: %w", err)
+			}
+			proposals, err := scan.Scan(root)
+			if err != nil {
+				return fmt.Errorf("failed to scan project: %w", err)
+			}
+
+			project := projectTag(filepath.Base(root))
+			reqs := make([]dto.CreateBookmarkRequest, len(proposals))
+			sources := map[string]string{}
+			for i, p := range proposals {
+				reqs[i] = dto.CreateBookmarkRequest{
+					Command:     p.Command,
+					ToolName:    p.ToolName,
+					Description: p.Description,
+					Tags:        append([]string{project}, scanTags...),
+				}
+				sources[p.Command] = p.Source
+			}
+
+			// Only offer commands that are not bookmarked yet
+			preview, err := svc.PreviewImport(ctx, reqs, dto.ConflictSkip)
+			if err != nil {
+				return fmt.Errorf("failed to scan project: %w", err)
+			}
+			var candidates []dto.CreateBookmarkRequest
+			for _, entry := range preview.Entries {
+				if entry.Status == dto.ImportAdded {
+					candidates = append(candidates, entry.Bookmark)
+				}
+			}
+			if len(candidates) == 0 {
+				info("No new commands found in %s.\n", root)
+				return nil
+			}
+
+			info("Found %d new commands in %s.\n", len(candidates), root)
+			selected, err := selectEach(cmd.InOrStdin(), candidates, scanAll, func(req dto.CreateBookmarkRequest) (string, string) {
+				return req.Command, fmt.Sprintf("%s (%s)", req.Description, sources[req.Command])
+			})
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				info("No commands added.\n")
+				return nil
+			}
+
+			result, err := svc.ImportBookmarks(ctx, selected, dto.ConflictSkip)
+			if err != nil {
+				return fmt.Errorf("failed to add commands: %w", err)
+			}
+
+			info("Successfully added %d commands for project: %s\n", result.Added, project)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&scanAll, "all", "a", false, "Add all proposed commands without asking")
+	cmd.Flags().StringSliceVar(&scanTags, "tags", nil, "Comma-separated additional tags for the added bookmarks")
+
+	return cmd
+}
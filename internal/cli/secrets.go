@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/secrets"
+)
+
+// redactor returns the redactor configured under redaction, nil when nothing is hidden
+// Its patterns were validated when the configuration was loaded.
+func redactor() *secrets.Redactor {
+	r, _ := secrets.NewRedactor(cfg.Redaction.Secrets, cfg.Redaction.Patterns)
+	return r
+}
+
+// confirmReveal asks before a command with redacted parts is printed or run, unless yes is set
+func confirmReveal(in io.Reader, command string, yes bool) error {
+	r := redactor()
+	if yes || !r.Hides(command) {
+		return nil
+	}
+	ok, err := confirm(in, i18n.T("'%s' contains redacted values. Reveal them?", summaryLine(r.Redact(command))))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errAborted
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fgeck/tools/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+var selfUpdateCheck bool
+
+// newUpdater creates the updater used by self-update; tests point it at a fake release server
+var newUpdater = selfupdate.New
+
+func newSelfUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update tools to the latest release",
+		Long: `Check the latest GitHub release and, if it is newer, download the build for
+this platform, verify its checksum and replace the running binary.
+
+Use --check to only report whether an update exists. Installations managed by
+Homebrew or Scoop are not replaced; update them with their package manager.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			updater := newUpdater()
+
+			release, err := updater.Latest(ctx)
+			if err != nil {
+				return err
+			}
+			if !selfupdate.IsNewer(release.Version, Version) {
+				info("tools %s is up to date\n", Version)
+				return nil
+			}
+
+			if selfUpdateCheck {
+				fmt.Printf("Update available: %s -> %s\n", Version, release.Version)
+				return nil
+			}
+
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate executable: %w", err)
+			}
+			if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+				exe = resolved
+			}
+			if pm := selfupdate.PackageManager(exe); pm != "" {
+				return fmt.Errorf("tools was installed with %s, run '%s upgrade tools' instead", pm, pm)
+			}
+
+			info("Downloading tools %s...\n", release.Version)
+			binary, err := updater.Download(ctx, release)
+			if err != nil {
+				return err
+			}
+			if err := selfupdate.Replace(exe, binary); err != nil {
+				return err
+			}
+
+			info("Successfully updated tools %s -> %s\n", Version, release.Version)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Only report whether an update is available")
+
+	return cmd
+}
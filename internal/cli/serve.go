@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultServeAddr is listened on without --addr or TOOLS_ADDR; only this host can connect
+	defaultServeAddr = "127.0.0.1:8080"
+
+	// serveShutdownTimeout bounds the wait for requests in flight on SIGTERM
+	serveShutdownTimeout = 10 * time.Second
+
+	// defaultServeRateLimit is how many /mcp requests per second a client may send by default
+	defaultServeRateLimit = 10
+)
+
+var (
+	serveAddr      string
+	serveRateLimit float64
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the MCP server over HTTP, e.g. in a container",
+		Long: `Serve the bookmarks over HTTP until SIGINT or SIGTERM:
+
+  /mcp      MCP JSON-RPC messages, one per POST request
+  /metrics  Prometheus metrics, as with 'tools mcp --metrics-addr'
+  /healthz  200 while the store is readable, 503 otherwise
+  /readyz   200 while the store is also writable, 503 otherwise
+
+The store must be writable on start, otherwise serve fails with the path and
+the user it runs as. On SIGTERM, requests in flight get up to 10s to finish and
+pending auto-exports are written before it exits.
+
+The address defaults to TOOLS_ADDR, or 127.0.0.1:8080. Paths follow
+TOOLS_CONFIG_DIR, TOOLS_STORAGE_FILE and TOOLS_HISTORY_FILE, see the README.
+
+Anyone reaching /mcp can read and change the bookmarks. With TOOLS_TOKEN set,
+/mcp requires it as bearer token ("Authorization: Bearer <token>"); without it,
+serve only listens on loopback addresses like 127.0.0.1 and /mcp refuses
+requests whose Host or Origin header names another one, e.g. from web pages.
+Messages must be sent with "Content-Type: application/json".
+
+Each client address may send 10 requests per second to /mcp, in bursts of up to
+20; more are answered with 429. Change the limit with --rate-limit, 0 disables
+it. Every request is logged with its status and duration at level info (see
+--log-level), and a request whose handling panics is answered with 500 and
+logged with the stack. Rate-limited and panicked requests are counted in
+tools_http_rejected_total on /metrics.`,
+		Example: `  tools serve
+  TOOLS_TOKEN=$(openssl rand -hex 32) tools serve --addr :8080
+  tools serve --rate-limit 50 --log-level info
+  docker run -p 8080:8080 -e TOOLS_TOKEN -v tools-data:/config ghcr.io/fgeck/tools serve`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := svc.CheckStore(ctx, true); err != nil {
+				return fmt.Errorf("failed to serve: store %s cannot be used by uid %d; mount the volume writable or chown it to that user: %w",
+					cfg.StorageFilePath, os.Getuid(), err)
+			}
+
+			listener, err := listen(serveAddr)
+			if err != nil {
+				return err
+			}
+			token := os.Getenv("TOOLS_TOKEN")
+			if token == "" && !loopback(listener.Addr()) {
+				listener.Close()
+				return fmt.Errorf("%w: refusing to serve /mcp on %s without authentication, anyone reaching it could read and change the bookmarks; set TOOLS_TOKEN to require a bearer token, or listen on 127.0.0.1",
+					models.ErrValidation, listener.Addr())
+			}
+
+			collector := newCollector()
+			server := mcp.NewServer(svc, Version)
+			server.Redact(redactor())
+			server.Observe(collector.ObserveRequest)
+			var endpoint http.Handler = server
+			if token != "" {
+				endpoint = mcp.RequireBearerToken(token, endpoint)
+			} else {
+				endpoint = mcp.RequireLoopback(endpoint)
+			}
+			// Limit before authenticating, so guessing the token is limited too
+			if serveRateLimit > 0 {
+				burst := max(1, int(2*serveRateLimit))
+				endpoint = mcp.RateLimit(serveRateLimit, burst, func() { collector.ObserveRejected("rate_limited") }, endpoint)
+			}
+			mux := monitoringMux(collector)
+			mux.Handle("/mcp", endpoint)
+
+			httpServer := &http.Server{
+				Handler:           mcp.LogRequests(Logger(), mcp.Recover(Logger(), func() { collector.ObserveRejected("panic") }, mux)),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			served := make(chan error, 1)
+			go func() { served <- httpServer.Serve(listener) }()
+			info("Serving MCP on http://%s/mcp, metrics on /metrics, probes on /healthz and /readyz\n", listener.Addr())
+			Logger().Info("server started", "addr", listener.Addr().String())
+
+			select {
+			case err := <-served:
+				return fmt.Errorf("failed to serve: %w", err)
+			case <-ctx.Done():
+			}
+
+			Logger().Info("server stopping")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+			defer cancel()
+			if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("failed to stop server: %w", err)
+			}
+			info("Server stopped\n")
+			return nil
+		},
+	}
+
+	addr := os.Getenv("TOOLS_ADDR")
+	if addr == "" {
+		addr = defaultServeAddr
+	}
+	cmd.Flags().StringVar(&serveAddr, "addr", addr, "Address to listen on, defaults to TOOLS_ADDR or 127.0.0.1:8080")
+	cmd.Flags().Float64Var(&serveRateLimit, "rate-limit", defaultServeRateLimit, "Requests per second each client may send to /mcp, with bursts of twice as many; 0 disables the limit")
+
+	return cmd
+}
+
+// loopback reports whether addr only accepts connections from this host
+func loopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/fgeck/tools/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotRestoreYes bool
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save and restore full copies of your bookmark store",
+		Long: `Snapshots are named full copies of the bookmark store, kept in the snapshot
+directory (~/.config/tools/snapshots by default, 'snapshot_dir' in the config).
+Take one before a bulk change and restore it if the change goes wrong.`,
+	}
+
+	cmd.AddCommand(newSnapshotCreateCmd())
+	cmd.AddCommand(newSnapshotListCmd())
+	cmd.AddCommand(newSnapshotRestoreCmd())
+	cmd.AddCommand(newSnapshotDiffCmd())
+
+	return cmd
+}
+
+func newSnapshotCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create [name]",
+		Short: "Save a copy of the store",
+		Long: `Save a full copy of the bookmark store.
+
+Without a name, the snapshot is named after the current time, e.g. 20261016-153000.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			if err := requireStoreFile(); err != nil {
+				return err
+			}
+			snap, err := snapshot.NewManager(cfg.SnapshotDir).Create(cfg.StorageFilePath, name)
+			if err != nil {
+				return fmt.Errorf("failed to create snapshot: %w", err)
+			}
+
+			info("Successfully created snapshot '%s': %s\n", snap.Name, snap.Path)
+			return nil
+		},
+	}
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List snapshots, oldest first",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := snapshot.NewManager(cfg.SnapshotDir).List()
+			if err != nil {
+				return fmt.Errorf("failed to list snapshots: %w", err)
+			}
+
+			if len(snapshots) == 0 {
+				info("No snapshots found. Use 'tools snapshot create' to take one.\n")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCREATED")
+			for _, snap := range snapshots {
+				fmt.Fprintf(w, "%s\t%s\n", snap.Name, snap.Created.Format("2006-01-02 15:04:05"))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newSnapshotRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Replace the store with a snapshot",
+		Long: `Replace the bookmark store with a snapshot.
+
+Shows how the store would change and asks for confirmation first; pass --yes
+to skip the prompt. Take a snapshot of the current store before restoring if
+you may want to go back.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			manager := snapshot.NewManager(cfg.SnapshotDir)
+			if err := requireStoreFile(); err != nil {
+				return err
+			}
+
+			snap, err := manager.Get(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to restore snapshot: %w", err)
+			}
+
+			if !snapshotRestoreYes {
+				changes, err := diffFiles(ctx, cfg.StorageFilePath, snap.Path)
+				if err != nil {
+					return fmt.Errorf("failed to restore snapshot: %w", err)
+				}
+				printSnapshotChanges(changes)
+				ok, err := confirm(cmd.InOrStdin(), i18n.T("Replace your store with snapshot '%s'?", snap.Name))
+				if err != nil {
+					return fmt.Errorf("failed to restore snapshot: %w", err)
+				}
+				if !ok {
+					return errAborted
+				}
+			}
+
+			if err := manager.Restore(snap.Name, cfg.StorageFilePath); err != nil {
+				return fmt.Errorf("failed to restore snapshot: %w", err)
+			}
+
+			info("Successfully restored snapshot '%s'\n", snap.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&snapshotRestoreYes, "yes", "y", false, "Do not ask for confirmation")
+
+	return cmd
+}
+
+func newSnapshotDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <old> [new]",
+		Short: "Compare two snapshots",
+		Long: `List the bookmarks added, removed and changed between two snapshots.
+
+Without a second snapshot, the first one is compared with the current store.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager := snapshot.NewManager(cfg.SnapshotDir)
+
+			older, err := manager.Get(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to compare snapshots: %w", err)
+			}
+			newerPath := cfg.StorageFilePath
+			if len(args) == 1 {
+				if err := requireStoreFile(); err != nil {
+					return err
+				}
+			}
+			if len(args) == 2 {
+				newer, err := manager.Get(args[1])
+				if err != nil {
+					return fmt.Errorf("failed to compare snapshots: %w", err)
+				}
+				newerPath = newer.Path
+			}
+
+			changes, err := diffFiles(context.Background(), older.Path, newerPath)
+			if err != nil {
+				return fmt.Errorf("failed to compare snapshots: %w", err)
+			}
+			printSnapshotChanges(changes)
+			return nil
+		},
+	}
+}
+
+// requireStoreFile returns a validation error when the store is a directory of YAML files,
+// which snapshots don't cover; version such a directory with git instead
+func requireStoreFile() error {
+	if yaml.IsDirectory(cfg.StorageFilePath) {
+		return fmt.Errorf("%w: %s is a directory store, snapshots only cover a single store file; version the directory with git instead",
+			models.ErrValidation, cfg.StorageFilePath)
+	}
+	return nil
+}
+
+// diffFiles compares the bookmarks of two store files
+func diffFiles(ctx context.Context, olderPath, newerPath string) (snapshot.Changes, error) {
+	older, err := snapshot.Bookmarks(ctx, olderPath)
+	if err != nil {
+		return snapshot.Changes{}, err
+	}
+	newer, err := snapshot.Bookmarks(ctx, newerPath)
+	if err != nil {
+		return snapshot.Changes{}, err
+	}
+	return snapshot.Diff(older, newer), nil
+}
+
+// printSnapshotChanges prints added (+), removed (-) and changed (~) commands
+func printSnapshotChanges(changes snapshot.Changes) {
+	for _, command := range changes.Added {
+		fmt.Println(addedStyle.Render("+ " + summaryLine(command)))
+	}
+	for _, command := range changes.Removed {
+		fmt.Println(removedStyle.Render("- " + summaryLine(command)))
+	}
+	for _, command := range changes.Changed {
+		fmt.Println(changedStyle.Render("~ " + summaryLine(command)))
+	}
+	info("%d added, %d removed, %d changed\n", len(changes.Added), len(changes.Removed), len(changes.Changed))
+}
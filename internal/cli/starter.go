@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fgeck/tools/internal/bundle"
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var starterList bool
+
+func newStarterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "starter [pack...]",
+		Short: "Install curated starter packs of bookmarks",
+		Long: `Install built-in packs of common commands: git, docker, kubectl and networking.
+
+Without arguments, the packs are shown for selection; the first start of the
+TUI with an empty store shows them too. Commands you already bookmarked are
+kept as they are.`,
+		Example: `  tools starter
+  tools starter git kubectl
+  tools starter --list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packs, err := bundle.Starters()
+			if err != nil {
+				return err
+			}
+
+			if starterList {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "NAME\tBOOKMARKS\tDESCRIPTION")
+				for _, b := range packs {
+					_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", b.Metadata.Name, len(b.Bookmarks), b.Metadata.Description)
+				}
+				return w.Flush()
+			}
+
+			selected, err := selectStarterPacks(packs, args)
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				info("No starter packs selected.\n")
+				return nil
+			}
+			return installStarterPacks(context.Background(), selected)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&starterList, "list", "l", false, "List the starter packs")
+
+	return cmd
+}
+
+// selectStarterPacks returns the packs named in args, or those picked in the selection TUI
+func selectStarterPacks(packs []*bundle.Bundle, args []string) ([]*bundle.Bundle, error) {
+	if len(args) == 0 {
+		return tui.SelectStarterPacks(packs)
+	}
+
+	var selected []*bundle.Bundle
+	for _, name := range args {
+		found := false
+		for _, b := range packs {
+			if strings.EqualFold(b.Metadata.Name, name) {
+				selected = append(selected, b)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := make([]string, 0, len(packs))
+			for _, b := range packs {
+				names = append(names, b.Metadata.Name)
+			}
+			return nil, fmt.Errorf("%w: unknown starter pack '%s', must be one of %s", models.ErrValidation, name, strings.Join(names, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// installStarterPacks imports the bookmarks of the packs, keeping existing bookmarks
+func installStarterPacks(ctx context.Context, packs []*bundle.Bundle) error {
+	for _, b := range packs {
+		result, err := svc.ImportBookmarks(ctx, b.Bookmarks, dto.ConflictSkip)
+		if err != nil {
+			return fmt.Errorf("failed to install starter pack '%s': %w", b.Metadata.Name, err)
+		}
+		info("Installed starter pack '%s': %d added, %d skipped\n", b.Metadata.Name, result.Added, result.Skipped)
+	}
+	return nil
+}
+
+// offerStarterPacks shows the starter packs on the first start of the TUI with an empty store.
+// They are offered once; later, 'tools starter' installs them.
+func offerStarterPacks(ctx context.Context) error {
+	marker := filepath.Join(config.GetConfigDir(), "onboarded")
+	if _, err := os.Stat(marker); !errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	resp, err := svc.ListBookmarks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list examples: %w", err)
+	}
+	if len(resp.Examples) == 0 {
+		packs, err := bundle.Starters()
+		if err != nil {
+			return err
+		}
+		selected, err := tui.SelectStarterPacks(packs)
+		if err != nil {
+			return err
+		}
+		if err := installStarterPacks(ctx, selected); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(marker, nil, 0644)
+}
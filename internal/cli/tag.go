@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagTool  string
+	tagQuery string
+)
+
+func newTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Add or remove a tag on many bookmarks at once",
+		Long: `Add a tag to or remove it from all bookmarks of a tool (--tool) or all
+bookmarks matching every term of a search (--query). A summary shows how many
+bookmarks were modified. Bookmarks of read-only layers are skipped.`,
+		Example: `  tools tag add k8s --tool kubectl
+  tools tag remove prod --query "deploy staging"`,
+	}
+
+	cmd.AddCommand(newTagChangeCmd("add", "Add a tag to many bookmarks", true))
+	cmd.AddCommand(newTagChangeCmd("remove", "Remove a tag from many bookmarks", false))
+
+	return cmd
+}
+
+// newTagChangeCmd creates the subcommand adding or, with add false, removing a tag
+func newTagChangeCmd(name, short string, add bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name + " <tag>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			tag := strings.ToLower(strings.TrimSpace(args[0]))
+			if tag == "" {
+				return fmt.Errorf("%w: tag cannot be empty", models.ErrValidation)
+			}
+
+			bookmarks, err := taggedSelection(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to %s tag: %w", name, err)
+			}
+
+			// All bookmarks are retagged or, if one fails, none of them
+			modified, unchanged, skipped := 0, 0, 0
+			err = svc.Transaction(ctx, func(tx service.BookmarkService) error {
+				for _, bookmark := range bookmarks {
+					if bookmark.Origin != "" {
+						skipped++
+						continue
+					}
+					tags := slices.DeleteFunc(slices.Clone(bookmark.Tags), func(t string) bool { return t == tag })
+					if add {
+						tags = append(tags, tag)
+					}
+					if len(tags) == len(bookmark.Tags) {
+						unchanged++
+						continue
+					}
+					if _, err := tx.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{Command: bookmark.Command, NewTags: tags}); err != nil {
+						return err
+					}
+					modified++
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to %s tag: %w", name, err)
+			}
+
+			if add {
+				info("Successfully tagged %d examples with '%s'", modified, tag)
+				if unchanged > 0 {
+					info(", %d already had it", unchanged)
+				}
+			} else {
+				info("Successfully removed '%s' from %d examples", tag, modified)
+				if unchanged > 0 {
+					info(", %d did not have it", unchanged)
+				}
+			}
+			if skipped > 0 {
+				info(", skipped %d read-only examples", skipped)
+			}
+			info("\n")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&tagTool, "tool", "t", "", "Change the bookmarks of this tool")
+	cmd.Flags().StringVar(&tagQuery, "query", "", "Change the bookmarks matching all terms of this search")
+	cmd.MarkFlagsMutuallyExclusive("tool", "query")
+	cmd.MarkFlagsOneRequired("tool", "query")
+
+	return cmd
+}
+
+// taggedSelection returns the bookmarks selected by --tool or --query
+func taggedSelection(ctx context.Context) ([]dto.BookmarkResponse, error) {
+	if tagQuery != "" {
+		resp, err := svc.SearchBookmarks(ctx, tagQuery)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Count == 0 {
+			return nil, fmt.Errorf("no example matches '%s': %w", tagQuery, models.ErrNotFound)
+		}
+		return resp.Examples, nil
+	}
+
+	resp, err := svc.ListBookmarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var bookmarks []dto.BookmarkResponse
+	for _, example := range resp.Examples {
+		if example.ToolName == tagTool {
+			bookmarks = append(bookmarks, example)
+		}
+	}
+	if len(bookmarks) == 0 {
+		return nil, fmt.Errorf("examples for tool '%s' %w", tagTool, models.ErrNotFound)
+	}
+	return bookmarks, nil
+}
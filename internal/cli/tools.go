@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "List tool names with their number of bookmarks",
+		Long: `Print every tool name with the number of bookmarks stored for it,
+most bookmarks first. Same as 'tools list --tools'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listTools()
+		},
+	}
+
+	return cmd
+}
+
+// listTools prints every tool name with its number of examples
+func listTools() error {
+	resp, err := svc.ListTools(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+
+	if resp.Count == 0 {
+		info("No examples found. Use 'tools add' to add your first example.\n")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TOOL\tBOOKMARKS")
+	_, _ = fmt.Fprintln(w, "----\t---------")
+	for _, tool := range resp.Tools {
+		_, _ = fmt.Fprintf(w, "%s\t%d\n", tool.ToolName, tool.Count)
+	}
+	_ = w.Flush()
+
+	info("\nTotal: %d tools\n", resp.Count)
+	return nil
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fgeck/tools/internal/capture"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/parse"
+	"github.com/spf13/cobra"
+)
+
+var triageLimit int
+
+func newTriageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "triage",
+		Short: "Review captured commands and promote them to bookmarks",
+		Long: `Walk through the commands recorded by 'tools capture', most recent first,
+and decide for each one whether to bookmark it.
+
+Answer y to bookmark a command (you are asked for a tool name and description),
+n to dismiss it, or q to stop. Reviewed commands are removed from the capture
+staging area; commands you did not get to stay for the next triage.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			staging := capture.NewStaging(cfg.CaptureFilePath)
+
+			candidates, err := staging.Candidates()
+			if err != nil {
+				return err
+			}
+
+			// Commands that are bookmarked already need no review
+			var reviewed []string
+			var pending []capture.Candidate
+			for _, c := range candidates {
+				if _, err := svc.GetBookmark(ctx, c.Command); err == nil {
+					reviewed = append(reviewed, c.Command)
+					continue
+				}
+				pending = append(pending, c)
+			}
+			if triageLimit > 0 && len(pending) > triageLimit {
+				pending = pending[:triageLimit]
+			}
+
+			if len(pending) == 0 {
+				info("No captured commands to triage.\n")
+				return staging.Remove(reviewed)
+			}
+
+			reader := bufio.NewReader(cmd.InOrStdin())
+			added := 0
+		loop:
+			for i, c := range pending {
+				fmt.Printf("\n[%d/%d] %s\n", i+1, len(pending), c.Command)
+				fmt.Print(i18n.T("      ran %dx, last %s", c.Count, c.LastUsed.Local().Format("2006-01-02 15:04")))
+				if c.Dir != "" {
+					fmt.Print(i18n.T(" in %s", c.Dir))
+				}
+				fmt.Println()
+
+				answer, eof, err := prompt(reader, i18n.T("Bookmark? [y/N/q] "))
+				if err != nil {
+					return err
+				}
+				switch {
+				case isYes(answer):
+					ok, err := promoteCandidate(ctx, reader, c.Command)
+					if err != nil {
+						return err
+					}
+					if ok {
+						added++
+					}
+					reviewed = append(reviewed, c.Command)
+				case isQuit(answer):
+					break loop
+				case answer == "":
+					if eof {
+						break loop
+					}
+					reviewed = append(reviewed, c.Command)
+				default:
+					reviewed = append(reviewed, c.Command)
+				}
+			}
+
+			if err := staging.Remove(reviewed); err != nil {
+				return err
+			}
+
+			info("\nSuccessfully bookmarked %d of %d reviewed commands\n", added, len(reviewed))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&triageLimit, "limit", "l", 20, "Maximum number of commands to review (0 for all)")
+
+	return cmd
+}
+
+// promoteCandidate asks for a tool name and description and bookmarks the command
+// It reports false when the user leaves the description empty or the bookmark cannot be created.
+func promoteCandidate(ctx context.Context, reader *bufio.Reader, command string) (bool, error) {
+	toolName := parse.ToolName(command)
+	if toolName == "" {
+		toolName = strings.Fields(command)[0]
+	}
+
+	name, _, err := prompt(reader, i18n.T("  Tool name [%s]: ", toolName))
+	if err != nil {
+		return false, err
+	}
+	if name != "" {
+		toolName = name
+	}
+
+	// The description template of the tool is the answer to an empty input
+	defaults := cfg.DefaultsFor(toolName)
+	question := i18n.T("  Description: ")
+	template := defaults.RenderDescription(toolName, command)
+	if template != "" {
+		question = i18n.T("  Description [%s]: ", template)
+	}
+	description, _, err := prompt(reader, question)
+	if err != nil {
+		return false, err
+	}
+	if description == "" {
+		description = template
+	}
+	if description == "" {
+		fmt.Println(i18n.T("  Skipped: a description is required"))
+		return false, nil
+	}
+
+	if _, err := svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+		Command:     command,
+		ToolName:    toolName,
+		Description: description,
+		Tags:        defaults.Tags,
+	}); err != nil {
+		fmt.Println(i18n.T("  Skipped: %v", err))
+		return false, nil
+	}
+	return true, nil
+}
+
+// prompt prints a question and reads one trimmed line of input, reporting whether input has ended
+func prompt(reader *bufio.Reader, question string) (string, bool, error) {
+	fmt.Print(question)
+	answer, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	if err == io.EOF {
+		fmt.Println()
+	}
+	return strings.TrimSpace(answer), err == io.EOF, nil
+}
+
+// isQuit reports whether an answer means stop, in English or the selected language
+func isQuit(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "q", "quit", i18n.T("q"), i18n.T("quit"):
+		return true
+	default:
+		return false
+	}
+}
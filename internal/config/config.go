@@ -1,30 +1,437 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMaxRevisions is the number of previous versions kept per bookmark
+const DefaultMaxRevisions = 10
+
+// DefaultAutoExportDelay is how long the auto-export waits for further changes before writing
+const DefaultAutoExportDelay = 2 * time.Second
+
+// DefaultProviderTimeout is how long the provider of a dynamic placeholder may run
+const DefaultProviderTimeout = 10 * time.Second
+
+// DefaultKeptRuns is the number of captured runs kept in the runs directory
+const DefaultKeptRuns = 50
+
+// DefaultCacheTTL is how long the cache decorator serves reads before reloading the store,
+// so changes of other processes show up in a running TUI
+const DefaultCacheTTL = 5 * time.Second
+
+// DefaultStoreTimeout is how long a single store operation may take before it is aborted
+const DefaultStoreTimeout = 30 * time.Second
+
+// Default size of the store beyond which changes warn about it
+const (
+	DefaultMaxEntries = 2000
+	DefaultMaxBytes   = 1 << 20
 )
 
 // Config holds application configuration
 type Config struct {
-	StorageFilePath string
+	StorageFilePath string             `yaml:"storage_file"`
+	HistoryFilePath string             `yaml:"history_file"`
+	MaxRevisions    int                `yaml:"max_revisions"` // 0 disables revision history
+	SystemFilePath  string             `yaml:"system_file"`   // Optional read-only store merged into the user's bookmarks
+	CaptureFilePath string             `yaml:"capture_file"`  // Staging area of the shell capture hook
+	SnapshotDir     string             `yaml:"snapshot_dir"`  // Directory of full store copies made with 'tools snapshot'
+	RunbooksFile    string             `yaml:"runbooks_file"` // Runbooks made with 'tools runbook'
+	Language        string             `yaml:"language"`      // Language of messages, e.g. de; empty uses LANG
+	TUI             TUIConfig          `yaml:"tui"`
+	Hooks           HooksConfig        `yaml:"hooks"`
+	AutoExport      AutoExportConfig   `yaml:"auto_export"`
+	Review          ReviewConfig       `yaml:"review"`
+	Placeholders    PlaceholdersConfig `yaml:"placeholders"`
+	Runs            RunsConfig         `yaml:"runs"`
+	Team            TeamConfig         `yaml:"team"`
+	StoreLimit      StoreLimitConfig   `yaml:"store_limit"`
+	Storage         StorageConfig      `yaml:"storage"`
+
+	// CanonicalCommands offers the canonical form of commands of known tools when adding,
+	// e.g. kubectl get pods --namespace=dev for kubectl get pods -n dev
+	CanonicalCommands bool `yaml:"canonical_commands"`
+
+	// ToolDefaults are applied to new bookmarks of a tool, by tool name
+	ToolDefaults map[string]ToolDefaults `yaml:"tool_defaults"`
+
+	// Redaction hides parts of commands in lists, the TUI and exports
+	Redaction RedactionConfig `yaml:"redaction"`
+}
+
+// RedactionConfig selects the parts of commands shown as **** until they are copied or run
+type RedactionConfig struct {
+	Secrets  bool     `yaml:"secrets"`  // Hide the credentials secret scanning detects
+	Patterns []string `yaml:"patterns"` // Regular expressions of further parts to hide; of a pattern with a group only the group is hidden
+}
+
+// ToolDefaults prefill new bookmarks of one tool, e.g. tag every kubectl bookmark k8s
+type ToolDefaults struct {
+	Tags        []string `yaml:"tags"`        // Added to the tags of a new bookmark
+	Description string   `yaml:"description"` // Template of the description, {tool} and {command} are replaced
+}
+
+// DefaultsFor returns the defaults of a tool; tool names are compared case-insensitively
+func (c *Config) DefaultsFor(tool string) ToolDefaults {
+	if defaults, ok := c.ToolDefaults[tool]; ok {
+		return defaults
+	}
+	for name, defaults := range c.ToolDefaults {
+		if strings.EqualFold(name, tool) {
+			return defaults
+		}
+	}
+	return ToolDefaults{}
+}
+
+// RenderDescription returns the description template filled for a command of the tool
+func (d ToolDefaults) RenderDescription(tool, command string) string {
+	return strings.NewReplacer("{tool}", tool, "{command}", command).Replace(d.Description)
+}
+
+// StoreLimitConfig is the size of the store beyond which changes warn about it, 0 disables a limit
+// The limits are soft: the store keeps working, the warning suggests cleaning it up.
+type StoreLimitConfig struct {
+	MaxEntries int   `yaml:"max_entries"` // Number of bookmarks in the user's store
+	MaxBytes   int64 `yaml:"max_bytes"`   // Size of the store file
+}
+
+// Exceeded reports whether a store of the given size is beyond the limits
+func (l StoreLimitConfig) Exceeded(entries int, bytes int64) bool {
+	return (l.MaxEntries > 0 && entries > l.MaxEntries) || (l.MaxBytes > 0 && bytes > l.MaxBytes)
+}
+
+// TeamConfig is for stores several people write to, e.g. one file on a shared server
+type TeamConfig struct {
+	RecordAuthor bool   `yaml:"record_author"` // Record who added each bookmark
+	Author       string `yaml:"author"`        // Name recorded as the author, empty uses the login name
+}
+
+// AuthorName returns the name recorded on new bookmarks, empty when authors are not recorded
+func (t TeamConfig) AuthorName() string {
+	if !t.RecordAuthor {
+		return ""
+	}
+	if author := strings.TrimSpace(t.Author); author != "" {
+		return author
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// StorageConfig wraps the user's store in decorators adding behavior to all its operations
+type StorageConfig struct {
+	Decorators []string      `yaml:"decorators"` // Applied in order, the first wraps the store: cache, metrics, readonly, audit
+	CacheTTL   time.Duration `yaml:"cache_ttl"`  // How long the cache decorator serves reads before reloading, 0 until the next write
+	AuditFile  string        `yaml:"audit_file"` // Log of every write reaching the store, kept by the audit decorator
+	Timeout    time.Duration `yaml:"timeout"`    // Maximum duration of a single store operation, 0 waits indefinitely
+}
+
+// RunsConfig keeps the output of executed bookmarks for review with 'tools runs'
+type RunsConfig struct {
+	Capture bool   `yaml:"capture"` // Capture the output of every 'tools run', like --capture
+	Dir     string `yaml:"dir"`     // Directory with one subdirectory per captured run
+	Keep    int    `yaml:"keep"`    // Number of captured runs kept, 0 keeps all
+}
+
+// PlaceholdersConfig enables dynamic placeholders like {pod:kubectl get pods -o name}
+// Their provider commands run when a bookmark is selected, after a confirmation unless trusted.
+type PlaceholdersConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"` // Maximum run time of a provider command
+	Trusted []string      `yaml:"trusted"` // Provider commands that run without confirmation
+}
+
+// ReviewConfig holds new bookmarks for approval before they enter the store
+type ReviewConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	PendingFile string `yaml:"pending_file"` // Queue of bookmarks awaiting approval
+}
+
+// AutoExportConfig keeps a rendered copy of the store up to date after every change
+type AutoExportConfig struct {
+	Path   string        `yaml:"path"`   // File to write, empty disables the auto-export
+	Format string        `yaml:"format"` // Export format, e.g. markdown or json
+	Delay  time.Duration `yaml:"delay"`  // Quiet period after a change before the file is written
+}
+
+// Enabled reports whether the auto-export is configured
+func (a AutoExportConfig) Enabled() bool {
+	return a.Path != ""
+}
+
+// HooksConfig holds shell commands run after bookmark operations
+// Each hook receives the bookmark in TOOLS_* environment variables.
+type HooksConfig struct {
+	OnAdd    string `yaml:"on_add"`
+	OnEdit   string `yaml:"on_edit"`
+	OnDelete string `yaml:"on_delete"`
+	OnSelect string `yaml:"on_select"` // Run when a bookmark is selected in the TUI or run with 'tools run'
+}
+
+// Enabled reports whether any hook is configured
+func (h HooksConfig) Enabled() bool {
+	return h.OnAdd != "" || h.OnEdit != "" || h.OnDelete != "" || h.OnSelect != ""
+}
+
+// TUIConfig holds the appearance settings of the interactive UI
+type TUIConfig struct {
+	Icons   bool           `yaml:"icons"`   // Show Nerd Font icons next to tool names (requires a patched font)
+	Plain   bool           `yaml:"plain"`   // Line-oriented accessible mode, like --plain
+	Sidebar bool           `yaml:"sidebar"` // Start with the sidebar of tool names
+	Columns []ColumnConfig `yaml:"columns"` // Visible table columns in display order
+
+	// StateFile keeps the filter, sort and grouping of the table between sessions
+	StateFile string `yaml:"state_file"`
+}
+
+// ColumnConfig is a visible column of the TUI table
+type ColumnConfig struct {
+	Name  string `yaml:"name"`  // tool, description or command
+	Width int    `yaml:"width"` // Share of the terminal width, relative to the other columns
+}
+
+// TUIColumns are the names of the columns the TUI table can show
+var TUIColumns = []string{"tool", "description", "command"}
+
+// DefaultTUIColumns returns the default table layout
+func DefaultTUIColumns() []ColumnConfig {
+	return []ColumnConfig{
+		{Name: "tool", Width: 15},
+		{Name: "description", Width: 40},
+		{Name: "command", Width: 45},
+	}
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		StorageFilePath: GetDefaultStoragePath(),
+		HistoryFilePath: GetDefaultHistoryPath(),
+		MaxRevisions:    DefaultMaxRevisions,
+		CaptureFilePath: GetDefaultCapturePath(),
+		SnapshotDir:     GetDefaultSnapshotDir(),
+		RunbooksFile:    GetDefaultRunbooksPath(),
+		TUI: TUIConfig{
+			Columns:   DefaultTUIColumns(),
+			StateFile: GetDefaultTUIStatePath(),
+		},
+		Review: ReviewConfig{
+			PendingFile: GetDefaultPendingPath(),
+		},
+		AutoExport: AutoExportConfig{
+			Format: "markdown",
+			Delay:  DefaultAutoExportDelay,
+		},
+		Placeholders: PlaceholdersConfig{
+			Timeout: DefaultProviderTimeout,
+		},
+		Runs: RunsConfig{
+			Dir:  GetDefaultRunsDir(),
+			Keep: DefaultKeptRuns,
+		},
+		StoreLimit: StoreLimitConfig{
+			MaxEntries: DefaultMaxEntries,
+			MaxBytes:   DefaultMaxBytes,
+		},
+		Storage: StorageConfig{
+			CacheTTL:  DefaultCacheTTL,
+			AuditFile: GetDefaultStoreAuditPath(),
+			Timeout:   DefaultStoreTimeout,
+		},
 	}
 }
 
-// GetDefaultStoragePath returns the default YAML storage path
-// Following XDG Base Directory specification
-func GetDefaultStoragePath() string {
+// Load returns the default configuration overridden by the values of the config file
+// A missing config file is not an error
+func Load() (*Config, error) {
+	return LoadFile(GetConfigFilePath())
+}
+
+// LoadFile returns the default configuration overridden by the values of the given file
+func LoadFile(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		applyEnv(cfg)
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if cfg.MaxRevisions < 0 {
+		return nil, fmt.Errorf("invalid config file %s: max_revisions cannot be negative", path)
+	}
+	if err := validateColumns(cfg.TUI.Columns); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if cfg.AutoExport.Delay < 0 {
+		return nil, fmt.Errorf("invalid config file %s: auto_export.delay cannot be negative", path)
+	}
+	if cfg.Placeholders.Timeout < 0 {
+		return nil, fmt.Errorf("invalid config file %s: placeholders.timeout cannot be negative", path)
+	}
+	if cfg.Runs.Keep < 0 {
+		return nil, fmt.Errorf("invalid config file %s: runs.keep cannot be negative", path)
+	}
+	if cfg.StoreLimit.MaxEntries < 0 || cfg.StoreLimit.MaxBytes < 0 {
+		return nil, fmt.Errorf("invalid config file %s: store_limit values cannot be negative", path)
+	}
+	if cfg.Storage.CacheTTL < 0 || cfg.Storage.Timeout < 0 {
+		return nil, fmt.Errorf("invalid config file %s: storage durations cannot be negative", path)
+	}
+	for _, pattern := range cfg.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: redaction.patterns: %w", path, err)
+		}
+	}
+	for tool, defaults := range cfg.ToolDefaults {
+		if slices.ContainsFunc(defaults.Tags, func(tag string) bool { return strings.TrimSpace(tag) == "" }) {
+			return nil, fmt.Errorf("invalid config file %s: tool_defaults.%s.tags cannot contain empty tags", path, tool)
+		}
+	}
+
+	applyEnv(cfg)
+	cfg.StorageFilePath = ExpandHome(cfg.StorageFilePath)
+	cfg.HistoryFilePath = ExpandHome(cfg.HistoryFilePath)
+	cfg.SystemFilePath = ExpandHome(cfg.SystemFilePath)
+	cfg.CaptureFilePath = ExpandHome(cfg.CaptureFilePath)
+	cfg.SnapshotDir = ExpandHome(cfg.SnapshotDir)
+	cfg.RunbooksFile = ExpandHome(cfg.RunbooksFile)
+	cfg.AutoExport.Path = ExpandHome(cfg.AutoExport.Path)
+	cfg.Review.PendingFile = ExpandHome(cfg.Review.PendingFile)
+	cfg.Runs.Dir = ExpandHome(cfg.Runs.Dir)
+	cfg.Storage.AuditFile = ExpandHome(cfg.Storage.AuditFile)
+	cfg.TUI.StateFile = ExpandHome(cfg.TUI.StateFile)
+
+	return cfg, nil
+}
+
+// applyEnv overrides the paths of the store and history with TOOLS_STORAGE_FILE and
+// TOOLS_HISTORY_FILE, which take precedence over the config file
+func applyEnv(cfg *Config) {
+	if path := os.Getenv("TOOLS_STORAGE_FILE"); path != "" {
+		cfg.StorageFilePath = path
+	}
+	if path := os.Getenv("TOOLS_HISTORY_FILE"); path != "" {
+		cfg.HistoryFilePath = path
+	}
+}
+
+// validateColumns checks that the TUI columns are known, unique and have a positive width
+func validateColumns(columns []ColumnConfig) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("tui.columns must list at least one column")
+	}
+
+	seen := map[string]bool{}
+	for _, c := range columns {
+		if !slices.Contains(TUIColumns, c.Name) {
+			return fmt.Errorf("unknown tui column '%s' (use %s)", c.Name, strings.Join(TUIColumns, ", "))
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("tui column '%s' is listed twice", c.Name)
+		}
+		if c.Width <= 0 {
+			return fmt.Errorf("tui column '%s' needs a positive width", c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading ~ in a path with the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// GetConfigDir returns the application's configuration directory
+// Following XDG Base Directory specification; TOOLS_CONFIG_DIR replaces the whole
+// directory, e.g. with a volume mounted into a container.
+func GetConfigDir() string {
+	if dir := os.Getenv("TOOLS_CONFIG_DIR"); dir != "" {
+		return dir
+	}
 	configDir := os.Getenv("XDG_CONFIG_HOME")
 	if configDir == "" {
 		home, _ := os.UserHomeDir()
 		configDir = filepath.Join(home, ".config")
 	}
 
-	return filepath.Join(configDir, "tools", "tools.yaml")
+	return filepath.Join(configDir, "tools")
+}
+
+// GetConfigFilePath returns the path of the optional YAML config file
+func GetConfigFilePath() string {
+	return filepath.Join(GetConfigDir(), "config.yaml")
+}
+
+// GetDefaultStoragePath returns the default YAML storage path
+func GetDefaultStoragePath() string {
+	return filepath.Join(GetConfigDir(), "tools.yaml")
+}
+
+// GetDefaultHistoryPath returns the default path of the append-only history log
+func GetDefaultHistoryPath() string {
+	return filepath.Join(GetConfigDir(), "history.jsonl")
+}
+
+// GetDefaultStoreAuditPath returns the default path of the log kept by the audit decorator
+func GetDefaultStoreAuditPath() string {
+	return filepath.Join(GetConfigDir(), "store-audit.jsonl")
+}
+
+// GetDefaultTUIStatePath returns the default path of the view the TUI restores on start
+func GetDefaultTUIStatePath() string {
+	return filepath.Join(GetConfigDir(), "tui-state.yaml")
+}
+
+// GetDefaultCapturePath returns the default path of the captured-commands staging file
+func GetDefaultCapturePath() string {
+	return filepath.Join(GetConfigDir(), "captured.jsonl")
+}
+
+// GetDefaultPendingPath returns the default path of the review queue
+func GetDefaultPendingPath() string {
+	return filepath.Join(GetConfigDir(), "pending.yaml")
+}
+
+// GetDefaultSnapshotDir returns the default directory of store snapshots
+func GetDefaultSnapshotDir() string {
+	return filepath.Join(GetConfigDir(), "snapshots")
+}
+
+// GetDefaultRunbooksPath returns the default path of the runbooks file
+func GetDefaultRunbooksPath() string {
+	return filepath.Join(GetConfigDir(), "runbooks.yaml")
+}
+
+// GetDefaultRunsDir returns the default directory of captured run output
+func GetDefaultRunsDir() string {
+	return filepath.Join(GetConfigDir(), "runs")
 }
@@ -6,7 +6,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -75,3 +77,389 @@ func TestStoragePathStructure(t *testing.T) {
 		t.Errorf("Expected .config or test-config directory, got %s", configDir)
 	}
 }
+
+func TestGetDefaultHistoryPath(t *testing.T) {
+	originalXDG := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", originalXDG)
+
+	testDir := "/tmp/test-config"
+	os.Setenv("XDG_CONFIG_HOME", testDir)
+
+	path := GetDefaultHistoryPath()
+	expected := filepath.Join(testDir, "tools", "history.jsonl")
+
+	if path != expected {
+		t.Errorf("Expected path %s, got %s", expected, path)
+	}
+
+	if filepath.Dir(path) != filepath.Dir(GetDefaultStoragePath()) {
+		t.Error("History log should live next to the storage file")
+	}
+
+	if filepath.Dir(GetDefaultCapturePath()) != filepath.Dir(path) {
+		t.Error("Capture staging file should live next to the history log")
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TOOLS_CONFIG_DIR", dir)
+	t.Setenv("TOOLS_STORAGE_FILE", "")
+	t.Setenv("TOOLS_HISTORY_FILE", "")
+
+	if GetConfigDir() != dir {
+		t.Errorf("Expected TOOLS_CONFIG_DIR %s, got %s", dir, GetConfigDir())
+	}
+	if GetDefaultStoragePath() != filepath.Join(dir, "tools.yaml") {
+		t.Errorf("Expected the store in TOOLS_CONFIG_DIR, got %s", GetDefaultStoragePath())
+	}
+
+	t.Setenv("TOOLS_STORAGE_FILE", "/data/store.yaml")
+	t.Setenv("TOOLS_HISTORY_FILE", "/data/history.jsonl")
+	for _, content := range []string{"", "storage_file: /tmp/custom.yaml\nhistory_file: /tmp/history.jsonl\n"} {
+		path := filepath.Join(dir, "config.yaml")
+		if content != "" {
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.StorageFilePath != "/data/store.yaml" || cfg.HistoryFilePath != "/data/history.jsonl" {
+			t.Errorf("Expected environment paths to win over %q, got %s and %s", content, cfg.StorageFilePath, cfg.HistoryFilePath)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Run("missing file returns defaults", func(t *testing.T) {
+		cfg, err := LoadFile(filepath.Join(t.TempDir(), "config.yaml"))
+		if err != nil {
+			t.Fatalf("Missing config file should not be an error: %v", err)
+		}
+		if cfg.MaxRevisions != DefaultMaxRevisions {
+			t.Errorf("Expected default max revisions %d, got %d", DefaultMaxRevisions, cfg.MaxRevisions)
+		}
+		if cfg.StorageFilePath == "" {
+			t.Error("StorageFilePath should default to the XDG path")
+		}
+	})
+
+	t.Run("values override defaults", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "max_revisions: 3\nstorage_file: /tmp/custom.yaml\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.MaxRevisions != 3 {
+			t.Errorf("Expected max revisions 3, got %d", cfg.MaxRevisions)
+		}
+		if cfg.StorageFilePath != "/tmp/custom.yaml" {
+			t.Errorf("Expected custom storage path, got %s", cfg.StorageFilePath)
+		}
+		if cfg.HistoryFilePath != GetDefaultHistoryPath() {
+			t.Error("Unset values should keep their defaults")
+		}
+	})
+
+	t.Run("tui settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("tui:\n  icons: true\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if !cfg.TUI.Icons {
+			t.Error("Expected icons to be enabled")
+		}
+		if len(cfg.TUI.Columns) != 3 {
+			t.Errorf("Expected default columns, got %+v", cfg.TUI.Columns)
+		}
+	})
+
+	t.Run("tui columns", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "tui:\n  columns:\n    - name: command\n      width: 3\n    - name: tool\n      width: 1\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		expected := []ColumnConfig{{Name: "command", Width: 3}, {Name: "tool", Width: 1}}
+		if !reflect.DeepEqual(cfg.TUI.Columns, expected) {
+			t.Errorf("Expected columns %+v, got %+v", expected, cfg.TUI.Columns)
+		}
+	})
+
+	t.Run("invalid tui columns", func(t *testing.T) {
+		for _, content := range []string{
+			"tui:\n  columns: []\n",
+			"tui:\n  columns:\n    - name: tags\n      width: 1\n",
+			"tui:\n  columns:\n    - name: tool\n      width: 1\n    - name: tool\n      width: 2\n",
+			"tui:\n  columns:\n    - name: tool\n",
+		} {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFile(path); err == nil {
+				t.Errorf("Expected error for %q", content)
+			}
+		}
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("hooks:\n  on_add: echo added\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.Hooks.OnAdd != "echo added" || !cfg.Hooks.Enabled() {
+			t.Errorf("Expected on_add hook, got %+v", cfg.Hooks)
+		}
+		if DefaultConfig().Hooks.Enabled() {
+			t.Error("Expected no hooks by default")
+		}
+	})
+
+	t.Run("auto export", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "auto_export:\n  path: /tmp/cheatsheet.json\n  format: json\n  delay: 500ms\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		expected := AutoExportConfig{Path: "/tmp/cheatsheet.json", Format: "json", Delay: 500 * time.Millisecond}
+		if cfg.AutoExport != expected || !cfg.AutoExport.Enabled() {
+			t.Errorf("Expected auto export %+v, got %+v", expected, cfg.AutoExport)
+		}
+		if DefaultConfig().AutoExport.Enabled() {
+			t.Error("Expected no auto export by default")
+		}
+
+		if err := os.WriteFile(path, []byte("auto_export:\n  delay: -1s\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("Expected error for negative auto_export.delay")
+		}
+	})
+
+	t.Run("review", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("review:\n  enabled: true\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if !cfg.Review.Enabled || cfg.Review.PendingFile != GetDefaultPendingPath() {
+			t.Errorf("Expected review with the default queue, got %+v", cfg.Review)
+		}
+	})
+
+	t.Run("placeholders", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "placeholders:\n  enabled: true\n  trusted:\n    - kubectl get pods -o name\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		p := cfg.Placeholders
+		if !p.Enabled || p.Timeout != DefaultProviderTimeout || len(p.Trusted) != 1 || p.Trusted[0] != "kubectl get pods -o name" {
+			t.Errorf("Unexpected placeholders config: %+v", p)
+		}
+
+		if err := os.WriteFile(path, []byte("placeholders:\n  timeout: -1s\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("Expected error for negative placeholders.timeout")
+		}
+	})
+
+	t.Run("runs", func(t *testing.T) {
+		home, _ := os.UserHomeDir()
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("runs:\n  capture: true\n  dir: ~/runs\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		r := cfg.Runs
+		if !r.Capture || r.Dir != filepath.Join(home, "runs") || r.Keep != DefaultKeptRuns {
+			t.Errorf("Unexpected runs config: %+v", r)
+		}
+
+		if err := os.WriteFile(path, []byte("runs:\n  keep: -1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("Expected error for negative runs.keep")
+		}
+	})
+
+	t.Run("storage", func(t *testing.T) {
+		home, _ := os.UserHomeDir()
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("storage:\n  decorators: [cache, readonly]\n  audit_file: ~/audit.jsonl\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		s := cfg.Storage
+		if len(s.Decorators) != 2 || s.Decorators[0] != "cache" || s.AuditFile != filepath.Join(home, "audit.jsonl") || s.CacheTTL != DefaultCacheTTL || s.Timeout != DefaultStoreTimeout {
+			t.Errorf("Unexpected storage config: %+v", s)
+		}
+
+		for _, invalid := range []string{"cache_ttl: -1s", "timeout: -1s"} {
+			if err := os.WriteFile(path, []byte("storage:\n  "+invalid+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFile(path); err == nil {
+				t.Errorf("Expected error for negative storage %s", invalid)
+			}
+		}
+	})
+
+	t.Run("tool defaults", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "tool_defaults:\n  kubectl:\n    tags: [k8s]\n    description: \"{tool}: {command}\"\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		defaults := cfg.DefaultsFor("Kubectl")
+		if len(defaults.Tags) != 1 || defaults.Tags[0] != "k8s" {
+			t.Errorf("Expected the kubectl defaults regardless of case, got %+v", defaults)
+		}
+		if got := defaults.RenderDescription("kubectl", "kubectl get pods"); got != "kubectl: kubectl get pods" {
+			t.Errorf("Unexpected rendered description %q", got)
+		}
+		if got := cfg.DefaultsFor("helm"); got.Description != "" || got.Tags != nil {
+			t.Errorf("Expected no defaults for helm, got %+v", got)
+		}
+
+		if err := os.WriteFile(path, []byte("tool_defaults:\n  kubectl:\n    tags: [k8s, \"\"]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("Expected error for an empty default tag")
+		}
+	})
+
+	t.Run("redaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("redaction:\n  secrets: true\n  patterns: ['prod-db-\\d+']\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if !cfg.Redaction.Secrets || len(cfg.Redaction.Patterns) != 1 || cfg.Redaction.Patterns[0] != `prod-db-\d+` {
+			t.Errorf("Unexpected redaction config: %+v", cfg.Redaction)
+		}
+
+		if err := os.WriteFile(path, []byte("redaction:\n  patterns: ['(']\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("Expected error for an invalid redaction pattern")
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("max_revisions: -1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadFile(path); err == nil {
+			t.Error("Expected error for negative max_revisions")
+		}
+	})
+}
+
+func TestTeamAuthorName(t *testing.T) {
+	if name := (TeamConfig{Author: "alice"}).AuthorName(); name != "" {
+		t.Errorf("Expected no author unless record_author is set, got %q", name)
+	}
+	if name := (TeamConfig{RecordAuthor: true, Author: " alice "}).AuthorName(); name != "alice" {
+		t.Errorf("Expected the configured author, got %q", name)
+	}
+	if name := (TeamConfig{RecordAuthor: true}).AuthorName(); name == "" {
+		t.Error("Expected the login name when no author is configured")
+	}
+}
+
+func TestStoreLimitExceeded(t *testing.T) {
+	limit := StoreLimitConfig{MaxEntries: 10, MaxBytes: 1000}
+	if limit.Exceeded(10, 1000) {
+		t.Error("A store at the limits should not exceed them")
+	}
+	if !limit.Exceeded(11, 0) || !limit.Exceeded(0, 1001) {
+		t.Error("Expected either limit to be exceeded")
+	}
+	if (StoreLimitConfig{}).Exceeded(1000000, 1<<30) {
+		t.Error("Zero limits should be disabled")
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, _ := os.UserHomeDir()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"~/tools.yaml", filepath.Join(home, "tools.yaml")},
+		{"~", home},
+		{"/abs/tools.yaml", "/abs/tools.yaml"},
+		{"~other/tools.yaml", "~other/tools.yaml"},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandHome(tt.input); got != tt.expected {
+			t.Errorf("ExpandHome(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package cron generates crontab entries that run bookmarks and installs them with crontab(1)
+package cron
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// macros are the nicknames cron accepts instead of the five time fields
+var macros = []string{"@reboot", "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// validField matches one time field: numbers, names, ranges, lists and steps
+var validField = regexp.MustCompile(`^[0-9A-Za-z*,/-]+$`)
+
+// markerPrefix starts the comment line above each entry written by tools
+const markerPrefix = "# tools: "
+
+// Job is a crontab entry running a command on a schedule
+type Job struct {
+	Schedule string // Five time fields, e.g. "0 3 * * *", or a macro like @daily
+	Command  string // Shell command run by cron
+	LogFile  string // File the output is appended to, empty to leave it to cron (usually mailed)
+	Name     string // Identifies the entry in the crontab, installing a job of the same name replaces it
+}
+
+// ValidateSchedule checks that a schedule has the five time fields of crontab(5) or is a macro
+func ValidateSchedule(schedule string) error {
+	fields := strings.Fields(schedule)
+	if len(fields) == 1 && slices.Contains(macros, fields[0]) {
+		return nil
+	}
+	if len(fields) != 5 {
+		return fmt.Errorf("%w: invalid schedule '%s', use five fields like \"0 3 * * *\" or a macro like @daily", models.ErrValidation, schedule)
+	}
+	for _, field := range fields {
+		if !validField.MatchString(field) {
+			return fmt.Errorf("%w: invalid schedule field '%s' in '%s'", models.ErrValidation, field, schedule)
+		}
+	}
+	return nil
+}
+
+// Lines returns the marker comment and the entry of the job as crontab lines
+func (j Job) Lines() string {
+	command := j.Command
+	if j.LogFile != "" {
+		command += " >> " + quote(j.LogFile) + " 2>&1"
+	}
+	// cron turns an unescaped % into a newline
+	command = strings.ReplaceAll(command, "%", `\%`)
+	return fmt.Sprintf("%s%s\n%s %s\n", markerPrefix, j.Name, strings.Join(strings.Fields(j.Schedule), " "), command)
+}
+
+// Merge adds a job to the content of a crontab, replacing an earlier entry of the same name
+func Merge(crontab string, job Job) string {
+	var kept []string
+	lines := strings.Split(strings.TrimRight(crontab, "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		if lines[i] == markerPrefix+job.Name {
+			i++ // Skip the entry below the marker as well
+			continue
+		}
+		kept = append(kept, lines[i])
+	}
+
+	content := strings.Join(kept, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return content + job.Lines()
+}
+
+// Install adds the job to the user's crontab, replacing an earlier entry of the same name
+func Install(job Job) error {
+	current, err := exec.Command("crontab", "-l").Output()
+	if err != nil {
+		// crontab -l fails when the user has no crontab yet, which is fine
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to read crontab: %w", err)
+		}
+		current = nil
+	}
+
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(Merge(string(current), job))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to install crontab: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// quote single-quotes a word for the shell cron runs the entry with
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
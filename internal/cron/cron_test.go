@@ -0,0 +1,67 @@
+//go:build unit
+// +build unit
+
+package cron
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		wantErr  bool
+	}{
+		{"0 3 * * *", false},
+		{"*/15 9-17 * * mon-fri", false},
+		{"@daily", false},
+		{"0 3 * *", true},
+		{"0 3 * * * *", true},
+		{"0 3 * * ;", true},
+		{"@often", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			err := ValidateSchedule(tt.schedule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateSchedule(%q) error = %v, wantErr %v", tt.schedule, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, models.ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	job := Job{
+		Schedule: "0  3 * * *",
+		Command:  "tools run -c 'date +%F'",
+		LogFile:  "/home/me/cron's.log",
+		Name:     "date +%F",
+	}
+
+	want := "# tools: date +%F\n0 3 * * * tools run -c 'date +\\%F' >> '/home/me/cron'\\''s.log' 2>&1\n"
+	if got := job.Lines(); got != want {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	job := Job{Schedule: "@daily", Command: "tools run -c backup", Name: "backup"}
+
+	if got, want := Merge("", job), "# tools: backup\n@daily tools run -c backup\n"; got != want {
+		t.Errorf("Merge() into an empty crontab = %q, want %q", got, want)
+	}
+
+	existing := "MAILTO=me\n# tools: backup\n@hourly tools run -c backup\n0 1 * * * other\n"
+	want := "MAILTO=me\n0 1 * * * other\n# tools: backup\n@daily tools run -c backup\n"
+	if got := Merge(existing, job); got != want {
+		t.Errorf("Merge() = %q, want the earlier entry replaced: %q", got, want)
+	}
+}
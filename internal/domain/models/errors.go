@@ -0,0 +1,16 @@
+package models
+
+import "errors"
+
+// Sentinel errors shared by repositories and services
+// Callers match them with errors.Is, e.g. to choose an exit code or HTTP status.
+var (
+	// ErrNotFound is returned when a bookmark or one of its revisions does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when a command is already bookmarked
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict is returned when a change collides with existing or read-only data
+	ErrConflict = errors.New("conflict")
+	// ErrValidation is returned when input is invalid
+	ErrValidation = errors.New("invalid input")
+)
@@ -1,9 +1,72 @@
 package models
 
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
 // Bookmark represents a single bookmarked command
 // The command string itself is the unique identifier (primary key)
 type Bookmark struct {
-	Command     string // PRIMARY KEY - The actual command to execute (e.g., "lsof -i :54321")
-	ToolName    string // Tool name for grouping (e.g., "lsof")
-	Description string // What this bookmark does
+	Command     string            `json:"command"`                                              // PRIMARY KEY - The actual command to execute (e.g., "lsof -i :54321")
+	ToolName    string            `json:"tool_name"`                                            // Tool name for grouping (e.g., "lsof")
+	Description string            `json:"description"`                                          // What this bookmark does
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`                 // Optional labels, sorted and unique
+	Link        string            `json:"link,omitempty" yaml:"link,omitempty"`                 // Optional URL of documentation, a runbook or a ticket
+	RemindEvery string            `json:"remind_every,omitempty" yaml:"remind_every,omitempty"` // Optional period after which the command is due again, e.g. 30d
+	Host        string            `json:"host,omitempty" yaml:"host,omitempty"`                 // Optional SSH host the command is run on
+	Variants    map[string]string `json:"variants,omitempty" yaml:"variants,omitempty"`         // Optional commands for other operating systems, keyed by GOOS
+	Requires    []string          `json:"requires,omitempty" yaml:"requires,omitempty"`         // Optional environment variables and executables the command needs
+	Private     bool              `json:"private,omitempty" yaml:"private,omitempty"`           // Left out of exports, bundles and the auto-export
+	Author      string            `json:"author,omitempty" yaml:"author,omitempty"`             // Optional user who added the bookmark to a shared store
+	UpdatedAt   time.Time         `json:"updated_at,omitzero" yaml:"updated_at,omitempty"`      // When the bookmark was last created or changed, zero for bookmarks stored before
+	Revisions   []Revision        `json:"revisions,omitempty" yaml:"revisions,omitempty"`       // Previous versions, oldest first
+	Origin      string            `json:"origin,omitempty" yaml:"-"`                            // Read-only layer the bookmark comes from, empty for the user's store
+}
+
+// Revision is a previous version of a bookmark, recorded when it is edited
+type Revision struct {
+	Command     string    `json:"command"`
+	ToolName    string    `json:"tool_name"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Link        string    `json:"link,omitempty" yaml:"link,omitempty"`
+	ChangedAt   time.Time `json:"changed_at" yaml:"changed_at"` // When this version was replaced
+}
+
+// Equal reports whether two bookmarks have the same user-visible fields
+// Revision history, the author and the update time are not compared
+func (b *Bookmark) Equal(other *Bookmark) bool {
+	return b.Command == other.Command &&
+		b.ToolName == other.ToolName &&
+		b.Description == other.Description &&
+		slices.Equal(b.Tags, other.Tags) &&
+		b.Link == other.Link &&
+		b.RemindEvery == other.RemindEvery &&
+		b.Host == other.Host &&
+		maps.Equal(b.Variants, other.Variants) &&
+		slices.Equal(b.Requires, other.Requires) &&
+		b.Private == other.Private
+}
+
+// Clone returns a deep copy of the bookmark, so the copy can be modified independently
+func (b *Bookmark) Clone() *Bookmark {
+	c := *b
+	c.Tags = slices.Clone(b.Tags)
+	c.Variants = maps.Clone(b.Variants)
+	c.Requires = slices.Clone(b.Requires)
+	if b.Revisions != nil {
+		c.Revisions = make([]Revision, len(b.Revisions))
+		for i, rev := range b.Revisions {
+			rev.Tags = slices.Clone(rev.Tags)
+			c.Revisions[i] = rev
+		}
+	}
+	return &c
+}
+
+// HasTag reports whether the bookmark carries the given tag
+func (b *Bookmark) HasTag(tag string) bool {
+	return slices.Contains(b.Tags, tag)
 }
@@ -1,29 +1,261 @@
 package dto
 
+import "time"
+
 // CreateBookmarkRequest - DTO for creating a new example
 type CreateBookmarkRequest struct {
-	Command     string `json:"command" yaml:"command"`         // The actual command (primary key)
-	ToolName    string `json:"tool_name" yaml:"tool_name"`     // Tool name for grouping
-	Description string `json:"description" yaml:"description"` // What this example does
+	Command     string            `json:"command" yaml:"command"`                               // The actual command (primary key)
+	ToolName    string            `json:"tool_name" yaml:"tool_name"`                           // Tool name for grouping
+	Description string            `json:"description" yaml:"description"`                       // What this example does
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`                 // Optional labels (e.g., "k8s")
+	Link        string            `json:"link,omitempty" yaml:"link,omitempty"`                 // Optional URL of documentation, a runbook or a ticket
+	RemindEvery string            `json:"remind_every,omitempty" yaml:"remind_every,omitempty"` // Optional reminder period, e.g. 30d
+	Host        string            `json:"host,omitempty" yaml:"host,omitempty"`                 // Optional SSH host the command is run on
+	Variants    map[string]string `json:"variants,omitempty" yaml:"variants,omitempty"`         // Optional commands for other operating systems, keyed by GOOS (e.g. darwin)
+	Requires    []string          `json:"requires,omitempty" yaml:"requires,omitempty"`         // Optional environment variables (upper-case) and executables the command needs
+	Private     bool              `json:"private,omitempty" yaml:"private,omitempty"`           // Optional, keeps the bookmark out of exports, bundles and the auto-export
+	Author      string            `json:"author,omitempty" yaml:"author,omitempty"`             // Optional, defaults to the author the service records
+
+	AllowSecrets bool `json:"-" yaml:"-"` // Store the command even if it contains likely credentials; never read from files or tool calls
 }
 
 // BookmarkResponse - DTO for returning example data
 type BookmarkResponse struct {
-	Command     string `json:"command" yaml:"command"`
-	ToolName    string `json:"tool_name" yaml:"tool_name"`
-	Description string `json:"description" yaml:"description"`
+	Command     string            `json:"command" yaml:"command"`
+	ToolName    string            `json:"tool_name" yaml:"tool_name"`
+	Description string            `json:"description" yaml:"description"`
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Link        string            `json:"link,omitempty" yaml:"link,omitempty"`
+	RemindEvery string            `json:"remind_every,omitempty" yaml:"remind_every,omitempty"`
+	Host        string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Variants    map[string]string `json:"variants,omitempty" yaml:"variants,omitempty"`
+	Requires    []string          `json:"requires,omitempty" yaml:"requires,omitempty"`
+	Private     bool              `json:"private,omitempty" yaml:"private,omitempty"`
+	Author      string            `json:"author,omitempty" yaml:"author,omitempty"`
+	UpdatedAt   time.Time         `json:"updated_at,omitzero" yaml:"updated_at,omitempty"` // Pass it back to UpdateBookmarkRequest.ExpectedUpdatedAt to detect changes made meanwhile
+	Origin      string            `json:"origin,omitempty" yaml:"origin,omitempty"`        // Read-only layer, empty for the user's store
+	Pending     bool              `json:"pending,omitempty" yaml:"pending,omitempty"`      // Waiting in the review queue, not yet in the store
 }
 
 // UpdateBookmarkRequest - DTO for updating an existing example
 type UpdateBookmarkRequest struct {
-	Command        string `json:"command" yaml:"command"`                 // The command to update (primary key)
-	NewToolName    string `json:"new_tool_name" yaml:"new_tool_name"`     // New tool name (optional)
-	NewDescription string `json:"new_description" yaml:"new_description"` // New description (optional)
-	NewCommand     string `json:"new_command" yaml:"new_command"`         // New command (optional)
+	Command           string            `json:"command" yaml:"command"`                                             // The command to update (primary key)
+	NewToolName       string            `json:"new_tool_name" yaml:"new_tool_name"`                                 // New tool name (optional)
+	NewDescription    string            `json:"new_description" yaml:"new_description"`                             // New description (optional)
+	NewCommand        string            `json:"new_command" yaml:"new_command"`                                     // New command (optional)
+	NewTags           []string          `json:"new_tags" yaml:"new_tags"`                                           // New tags (optional, nil keeps tags, empty clears them)
+	NewLink           *string           `json:"new_link" yaml:"new_link"`                                           // New link (optional, nil keeps the link, empty clears it)
+	NewRemindEvery    *string           `json:"new_remind_every" yaml:"new_remind_every"`                           // New reminder period (optional, nil keeps it, empty clears it)
+	NewHost           *string           `json:"new_host" yaml:"new_host"`                                           // New SSH host (optional, nil keeps it, empty clears it)
+	NewVariants       map[string]string `json:"new_variants" yaml:"new_variants"`                                   // New OS variants (optional, nil keeps them, empty clears them)
+	NewRequires       []string          `json:"new_requires" yaml:"new_requires"`                                   // New requirements (optional, nil keeps them, empty clears them)
+	NewPrivate        *bool             `json:"new_private" yaml:"new_private"`                                     // New private flag (optional, nil keeps it)
+	ExpectedUpdatedAt *time.Time        `json:"expected_updated_at,omitempty" yaml:"expected_updated_at,omitempty"` // Update time of the bookmark when it was read (optional, fails with a conflict if it changed since)
+
+	AllowSecrets bool `json:"-" yaml:"-"` // Store a new command or variant even if it contains likely credentials; never read from files or tool calls
+}
+
+// CommandFor returns the variant of the command for an operating system, given as a GOOS
+// value like "darwin", or the command itself when the bookmark has no variant for it
+func (b BookmarkResponse) CommandFor(goos string) string {
+	if variant, ok := b.Variants[goos]; ok {
+		return variant
+	}
+	return b.Command
+}
+
+// Shared returns the bookmarks that are not private, the ones exports and bundles contain
+func Shared(bookmarks []BookmarkResponse) []BookmarkResponse {
+	shared := make([]BookmarkResponse, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		if !bookmark.Private {
+			shared = append(shared, bookmark)
+		}
+	}
+	return shared
 }
 
 // ListBookmarksResponse - DTO for listing multiple examples
 type ListBookmarksResponse struct {
-	Examples []BookmarkResponse `json:"examples" yaml:"examples"`
-	Count    int                `json:"count" yaml:"count"`
+	Examples   []BookmarkResponse `json:"examples" yaml:"examples"`
+	Count      int                `json:"count" yaml:"count"`
+	NextCursor string             `json:"next_cursor,omitempty" yaml:"next_cursor,omitempty"` // Set when more pages follow
+}
+
+// DueBookmark - DTO for a bookmark whose reminder period has elapsed since it last ran
+type DueBookmark struct {
+	BookmarkResponse `yaml:",inline"`
+	LastRun          *time.Time `json:"last_run,omitempty" yaml:"last_run,omitempty"` // Nil if it never ran
+	DueAt            *time.Time `json:"due_at,omitempty" yaml:"due_at,omitempty"`     // Nil if it never ran
+}
+
+// DueBookmarksResponse - DTO for listing due bookmarks
+type DueBookmarksResponse struct {
+	Bookmarks []DueBookmark `json:"bookmarks" yaml:"bookmarks"`
+	Count     int           `json:"count" yaml:"count"`
+}
+
+// UnusedBookmark - DTO for a bookmark that was not run for a while
+type UnusedBookmark struct {
+	BookmarkResponse `yaml:",inline"`
+	LastUsed         *time.Time `json:"last_used,omitempty" yaml:"last_used,omitempty"` // Last run, or when it was added if it never ran; nil if the history has neither
+}
+
+// UnusedBookmarksResponse - DTO for listing unused bookmarks
+type UnusedBookmarksResponse struct {
+	Bookmarks []UnusedBookmark `json:"bookmarks" yaml:"bookmarks"`
+	Count     int              `json:"count" yaml:"count"`
+}
+
+// HistoryEntry - DTO for a single audit log entry
+type HistoryEntry struct {
+	Time    time.Time         `json:"time" yaml:"time"`
+	Action  string            `json:"action" yaml:"action"`
+	Command string            `json:"command" yaml:"command"`
+	Before  *BookmarkResponse `json:"before,omitempty" yaml:"before,omitempty"` // State before the change (edit, delete)
+	After   *BookmarkResponse `json:"after,omitempty" yaml:"after,omitempty"`   // State after the change (create, edit)
+	Result  *RunResult        `json:"result,omitempty" yaml:"result,omitempty"` // Outcome of an executed command
+}
+
+// RunResult - DTO for the outcome of an executed command
+type RunResult struct {
+	Time     time.Time     `json:"time" yaml:"time"`
+	ExitCode int           `json:"exit_code" yaml:"exit_code"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// HistoryResponse - DTO for listing audit log entries
+type HistoryResponse struct {
+	Entries []HistoryEntry `json:"entries" yaml:"entries"`
+	Count   int            `json:"count" yaml:"count"`
+}
+
+// RevisionResponse - DTO for a previous version of a bookmark
+type RevisionResponse struct {
+	Number      int       `json:"number" yaml:"number"` // 1 is the oldest kept revision
+	Command     string    `json:"command" yaml:"command"`
+	ToolName    string    `json:"tool_name" yaml:"tool_name"`
+	Description string    `json:"description" yaml:"description"`
+	Tags        []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Link        string    `json:"link,omitempty" yaml:"link,omitempty"`
+	ChangedAt   time.Time `json:"changed_at" yaml:"changed_at"` // When this version was replaced
+}
+
+// RevisionsResponse - DTO for listing the revisions of a bookmark
+type RevisionsResponse struct {
+	Current   BookmarkResponse   `json:"current" yaml:"current"`
+	Revisions []RevisionResponse `json:"revisions" yaml:"revisions"`
+	Count     int                `json:"count" yaml:"count"`
+}
+
+// RankedBookmark - DTO for a bookmark with its relevance score
+type RankedBookmark struct {
+	BookmarkResponse `yaml:",inline"`
+	Score            float64 `json:"score" yaml:"score"`
+}
+
+// RankedBookmarksResponse - DTO for bookmarks ordered by relevance
+type RankedBookmarksResponse struct {
+	Results []RankedBookmark `json:"results" yaml:"results"`
+	Count   int              `json:"count" yaml:"count"`
+}
+
+// ConflictStrategy - how imports treat bookmarks whose command already exists with different content
+type ConflictStrategy string
+
+const (
+	ConflictSkip      ConflictStrategy = "skip"      // Keep the existing bookmark
+	ConflictOverwrite ConflictStrategy = "overwrite" // Replace the existing bookmark
+	ConflictFail      ConflictStrategy = "fail"      // Abort without importing anything
+)
+
+// ActivityCount - DTO for how often a command was run or a query searched
+type ActivityCount struct {
+	Value string `json:"value" yaml:"value"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// MetricsResponse - DTO summarizing local activity from the audit log
+type MetricsResponse struct {
+	Since       time.Time       `json:"since" yaml:"since"`
+	Adds        int             `json:"adds" yaml:"adds"`
+	Edits       int             `json:"edits" yaml:"edits"`
+	Deletes     int             `json:"deletes" yaml:"deletes"`
+	Runs        int             `json:"runs" yaml:"runs"`
+	Searches    int             `json:"searches" yaml:"searches"`
+	TopRuns     []ActivityCount `json:"top_runs" yaml:"top_runs"`         // Most run commands
+	TopSearches []ActivityCount `json:"top_searches" yaml:"top_searches"` // Most frequent queries
+	Bookmarks   int             `json:"bookmarks" yaml:"bookmarks"`       // Bookmarks stored now
+	Unused      []string        `json:"unused" yaml:"unused"`             // Stored commands not run in the period
+}
+
+// ToolCountResponse - DTO for the number of examples of a tool
+type ToolCountResponse struct {
+	ToolName string `json:"tool_name" yaml:"tool_name"`
+	Count    int    `json:"count" yaml:"count"`
+}
+
+// ToolsResponse - DTO for the tool names with their example counts
+type ToolsResponse struct {
+	Tools []ToolCountResponse `json:"tools" yaml:"tools"`
+	Count int                 `json:"count" yaml:"count"`
+}
+
+// ImportResult - DTO summarizing an import
+type ImportResult struct {
+	Added     int      `json:"added" yaml:"added"`
+	Updated   int      `json:"updated" yaml:"updated"`
+	Skipped   int      `json:"skipped" yaml:"skipped"`     // Identical or conflicting entries left untouched
+	Conflicts []string `json:"conflicts" yaml:"conflicts"` // Commands that exist with different content
+}
+
+// Statuses of an entry in an import preview
+const (
+	ImportAdded   = "added"   // The command is new
+	ImportChanged = "changed" // The command exists and would be overwritten
+	ImportSkipped = "skipped" // The command exists unchanged or its conflict is skipped
+)
+
+// ImportPreviewEntry - DTO for one entry of an import preview
+type ImportPreviewEntry struct {
+	Status   string                `json:"status" yaml:"status"`
+	Bookmark CreateBookmarkRequest `json:"bookmark" yaml:"bookmark"`
+	Existing *BookmarkResponse     `json:"existing,omitempty" yaml:"existing,omitempty"` // Stored version of a conflicting entry
+}
+
+// ImportPreview - DTO describing what an import would change
+type ImportPreview struct {
+	Entries   []ImportPreviewEntry `json:"entries" yaml:"entries"`
+	Added     int                  `json:"added" yaml:"added"`
+	Changed   int                  `json:"changed" yaml:"changed"`
+	Skipped   int                  `json:"skipped" yaml:"skipped"`
+	Conflicts []string             `json:"conflicts" yaml:"conflicts"` // Commands that exist with different content
+}
+
+// ReplaceRequest - DTO for replacing text in stored commands
+type ReplaceRequest struct {
+	Find     string `json:"find" yaml:"find"`           // Text to find, a regular expression with Regex
+	Replace  string `json:"replace" yaml:"replace"`     // Replacement; with Regex, $1 and ${name} refer to submatches
+	Regex    bool   `json:"regex" yaml:"regex"`         // Find is a regular expression instead of literal text
+	ToolName string `json:"tool_name" yaml:"tool_name"` // Only change commands of this tool (optional)
+	DryRun   bool   `json:"dry_run" yaml:"dry_run"`     // Only report the changes
+}
+
+// CommandChange - DTO for a command replaced by another
+type CommandChange struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// ReplaceResult - DTO summarizing a replacement across commands
+type ReplaceResult struct {
+	Changes []CommandChange `json:"changes" yaml:"changes"`
+	Skipped int             `json:"skipped" yaml:"skipped"` // Matching bookmarks of read-only layers left untouched
+}
+
+// MergeToolResult - DTO summarizing a tool merge
+type MergeToolResult struct {
+	Reassigned int `json:"reassigned" yaml:"reassigned"`
+	Merged     int `json:"merged" yaml:"merged"`   // Duplicates merged after reassigning
+	Skipped    int `json:"skipped" yaml:"skipped"` // Bookmarks of read-only layers left untouched
 }
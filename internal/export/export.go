@@ -0,0 +1,109 @@
+// Package export renders bookmarks as standalone cheat sheets
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+)
+
+// Format is an output format of an export
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	FormatLaTeX    Format = "latex"
+	FormatJSON     Format = "json"
+)
+
+// DefaultTitle is the title of sheets that are not given one
+const DefaultTitle = "Tools cheat sheet"
+
+// Formats lists the supported formats
+func Formats() []Format {
+	return []Format{FormatHTML, FormatMarkdown, FormatLaTeX, FormatJSON}
+}
+
+// Section holds the bookmarks of one tool
+type Section struct {
+	Tool      string                 `json:"tool"`
+	Bookmarks []dto.BookmarkResponse `json:"bookmarks"`
+}
+
+// Sheet is the content of an export
+type Sheet struct {
+	Title    string    `json:"title"`
+	Sections []Section `json:"tools"`
+	Count    int       `json:"count"`
+}
+
+// NewSheet groups bookmarks into per-tool sections, sorted by tool name and description
+func NewSheet(title string, bookmarks []dto.BookmarkResponse) *Sheet {
+	byTool := map[string][]dto.BookmarkResponse{}
+	for _, b := range bookmarks {
+		byTool[b.ToolName] = append(byTool[b.ToolName], b)
+	}
+
+	sheet := &Sheet{Title: title, Sections: make([]Section, 0, len(byTool)), Count: len(bookmarks)}
+	for tool, group := range byTool {
+		slices.SortStableFunc(group, func(a, b dto.BookmarkResponse) int {
+			if c := strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description)); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Command, b.Command)
+		})
+		sheet.Sections = append(sheet.Sections, Section{Tool: tool, Bookmarks: group})
+	}
+	slices.SortFunc(sheet.Sections, func(a, b Section) int {
+		return strings.Compare(strings.ToLower(a.Tool), strings.ToLower(b.Tool))
+	})
+	return sheet
+}
+
+// Write renders a sheet in the given format
+func Write(w io.Writer, format Format, sheet *Sheet) error {
+	switch format {
+	case FormatHTML:
+		return writeHTML(w, sheet)
+	case FormatMarkdown:
+		return writeMarkdown(w, sheet)
+	case FormatLaTeX:
+		return writeLaTeX(w, sheet)
+	case FormatJSON:
+		return writeJSON(w, sheet)
+	default:
+		return fmt.Errorf("%w: unknown export format '%s' (supported: %s)", models.ErrValidation, format, formatList())
+	}
+}
+
+// Supported reports whether format is a known export format
+func Supported(format Format) bool {
+	return slices.Contains(Formats(), format)
+}
+
+// writeJSON renders a sheet as indented JSON, e.g. for other tools to consume
+func writeJSON(w io.Writer, sheet *Sheet) error {
+	data, err := json.MarshalIndent(sheet, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// formatList joins the supported formats for error messages
+func formatList() string {
+	names := make([]string, 0, len(Formats()))
+	for _, f := range Formats() {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
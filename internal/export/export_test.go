@@ -0,0 +1,116 @@
+//go:build unit
+// +build unit
+
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+)
+
+func testBookmarks() []dto.BookmarkResponse {
+	return []dto.BookmarkResponse{
+		{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "list all pods", Tags: []string{"k8s"}},
+		{Command: "docker ps", ToolName: "docker", Description: "list containers"},
+		{Command: "kubectl apply -f <file>", ToolName: "kubectl", Description: "apply a manifest", Link: "https://kubernetes.io/docs/reference/kubectl/"},
+	}
+}
+
+func TestNewSheet(t *testing.T) {
+	sheet := NewSheet("Cheat sheet", testBookmarks())
+
+	if sheet.Count != 3 {
+		t.Errorf("Expected count 3, got %d", sheet.Count)
+	}
+	if len(sheet.Sections) != 2 || sheet.Sections[0].Tool != "docker" || sheet.Sections[1].Tool != "kubectl" {
+		t.Fatalf("Expected docker and kubectl sections, got %+v", sheet.Sections)
+	}
+	kubectl := sheet.Sections[1].Bookmarks
+	if kubectl[0].Description != "apply a manifest" || kubectl[1].Description != "list all pods" {
+		t.Errorf("Expected bookmarks sorted by description, got %+v", kubectl)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatHTML, NewSheet("Team <tools>", testBookmarks())); err != nil {
+		t.Fatalf("Failed to write HTML: %v", err)
+	}
+
+	page := buf.String()
+	for _, want := range []string{
+		"<title>Team &lt;tools&gt;</title>",
+		`<section id="tool-docker">`,
+		`<a href="#tool-kubectl">kubectl</a>`,
+		"<code>kubectl apply -f &lt;file&gt;</code>",
+		`<a href="https://kubernetes.io/docs/reference/kubectl/">docs</a>`,
+		`data-search="kubectl list all pods kubectl get pods -a k8s"`,
+		`id="search"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q in page, got: %s", want, page)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	bookmarks := append(testBookmarks(), dto.BookmarkResponse{Command: "echo ```", ToolName: "echo", Description: "print fences"})
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMarkdown, NewSheet("Cheat sheet", bookmarks)); err != nil {
+		t.Fatalf("Failed to write Markdown: %v", err)
+	}
+
+	doc := buf.String()
+	for _, want := range []string{
+		"# Cheat sheet\n",
+		"\n## docker\n\n**list containers**\n\n```sh\ndocker ps\n```\n",
+		"**apply a manifest** ([docs](https://kubernetes.io/docs/reference/kubectl/))",
+		"_k8s_",
+		"````sh\necho ```\n````",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected %q in document, got: %s", want, doc)
+		}
+	}
+}
+
+func TestWriteLaTeX(t *testing.T) {
+	bookmarks := append(testBookmarks(), dto.BookmarkResponse{Command: "grep -c '^$' file_1 | tee ~/out\nwc -l", ToolName: "grep", Description: "count 100% empty lines"})
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatLaTeX, NewSheet("Cheat sheet", bookmarks)); err != nil {
+		t.Fatalf("Failed to write LaTeX: %v", err)
+	}
+
+	doc := buf.String()
+	for _, want := range []string{
+		`\begin{multicols*}{2}`,
+		`\subsection*{kubectl}`,
+		`\texttt{kubectl apply -f \textless{}file\textgreater{}}`,
+		`\href{https://kubernetes.io/docs/reference/kubectl/}{(docs)}`,
+		`\textbf{count 100\% empty lines}`,
+		`\texttt{grep -c '\textasciicircum{}\$' file\_1 \textbar{} tee \textasciitilde{}/out}\\` + "\n" + `\texttt{wc -l}`,
+		`\end{document}`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected %q in document, got: %s", want, doc)
+		}
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	err := Write(&bytes.Buffer{}, Format("docx"), NewSheet("", nil))
+	if !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	if got := anchor("Git Flow/CLI"); got != "tool-git-flow-cli" {
+		t.Errorf("Expected tool-git-flow-cli, got %s", got)
+	}
+}
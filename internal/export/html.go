@@ -0,0 +1,97 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+var nonAnchor = regexp.MustCompile(`[^a-z0-9]+`)
+
+var htmlTemplate = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"anchor": anchor,
+	"search": searchText,
+	"join":   strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 960px; padding: 1rem 2rem; color: #24292f; }
+header { position: sticky; top: 0; background: #fff; padding: .5rem 0; border-bottom: 1px solid #d0d7de; }
+h1 { margin: .25rem 0 .5rem; font-size: 1.5rem; }
+#search { width: 100%; box-sizing: border-box; padding: .5rem; font-size: 1rem; border: 1px solid #d0d7de; border-radius: 6px; }
+nav { margin: .5rem 0; font-size: .9rem; }
+nav a { margin-right: .75rem; color: #0969da; text-decoration: none; }
+section h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .25rem; font-size: 1.2rem; }
+ul { list-style: none; padding: 0; }
+li { margin: 0 0 .9rem; }
+pre { background: #f6f8fa; padding: .5rem .75rem; border-radius: 6px; overflow-x: auto; margin: .25rem 0; }
+.tags { color: #57606a; font-size: .85rem; }
+.empty { color: #57606a; display: none; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="Search {{.Count}} bookmarks…" autofocus>
+<nav>{{range .Sections}}<a href="#{{anchor .Tool}}">{{.Tool}}</a>{{end}}</nav>
+</header>
+<main>
+{{range .Sections}}<section id="{{anchor .Tool}}">
+<h2>{{.Tool}}</h2>
+<ul>
+{{range .Bookmarks}}<li data-search="{{search .}}">
+<div>{{.Description}}{{if .Link}} (<a href="{{.Link}}">docs</a>){{end}}</div>
+<pre><code>{{.Command}}</code></pre>
+{{if .Tags}}<div class="tags">{{join .Tags ", "}}</div>
+{{end}}</li>
+{{end}}</ul>
+</section>
+{{end}}<p class="empty" id="empty">No bookmarks match your search.</p>
+</main>
+<script>
+const search = document.getElementById("search");
+search.addEventListener("input", () => {
+  const terms = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+  let shown = 0;
+  document.querySelectorAll("section").forEach(section => {
+    let visible = 0;
+    section.querySelectorAll("li").forEach(item => {
+      const match = terms.every(term => item.dataset.search.includes(term));
+      item.style.display = match ? "" : "none";
+      if (match) visible++;
+    });
+    section.style.display = visible ? "" : "none";
+    shown += visible;
+  });
+  document.getElementById("empty").style.display = shown ? "none" : "block";
+});
+</script>
+</body>
+</html>
+`))
+
+// writeHTML renders a sheet as a single HTML page with client-side search
+func writeHTML(w io.Writer, sheet *Sheet) error {
+	if err := htmlTemplate.Execute(w, sheet); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
+
+// anchor turns a tool name into a fragment identifier
+func anchor(tool string) string {
+	return "tool-" + strings.Trim(nonAnchor.ReplaceAllString(strings.ToLower(tool), "-"), "-")
+}
+
+// searchText is the lowercased text the search box matches against
+func searchText(b dto.BookmarkResponse) string {
+	return strings.ToLower(strings.Join([]string{b.ToolName, b.Description, b.Command, strings.Join(b.Tags, " ")}, " "))
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+)
+
+// Launcher is the output format of a system launcher such as Alfred or Raycast
+type Launcher string
+
+const (
+	LauncherAlfred  Launcher = "alfred"
+	LauncherRaycast Launcher = "raycast"
+)
+
+// alfredItem is an item of an Alfred script filter
+// See https://www.alfredapp.com/help/workflows/inputs/script-filter/json/
+type alfredItem struct {
+	UID          string     `json:"uid"`
+	Title        string     `json:"title"`
+	Subtitle     string     `json:"subtitle"`
+	Arg          string     `json:"arg"`
+	Autocomplete string     `json:"autocomplete"`
+	Match        string     `json:"match"`
+	Text         alfredText `json:"text"`
+	QuickLookURL string     `json:"quicklookurl,omitempty"`
+}
+
+// alfredText is the text Alfred copies (⌘C) and shows as large type (⌘L)
+type alfredText struct {
+	Copy      string `json:"copy"`
+	LargeType string `json:"largetype"`
+}
+
+// raycastItem is an item of a Raycast list
+type raycastItem struct {
+	ID          string             `json:"id"`
+	Title       string             `json:"title"`
+	Subtitle    string             `json:"subtitle"`
+	Arg         string             `json:"arg"`
+	Keywords    []string           `json:"keywords"`
+	Accessories []raycastAccessory `json:"accessories"`
+	URL         string             `json:"url,omitempty"`
+}
+
+// raycastAccessory is a text shown on the right of a Raycast list item
+type raycastAccessory struct {
+	Text string `json:"text"`
+}
+
+// WriteLauncher renders bookmarks as the JSON a launcher expects: the description
+// as title, the command as subtitle and the full command as the argument
+func WriteLauncher(w io.Writer, launcher Launcher, bookmarks []dto.BookmarkResponse) error {
+	var items any
+	switch launcher {
+	case LauncherAlfred:
+		alfred := make([]alfredItem, 0, len(bookmarks))
+		for _, b := range bookmarks {
+			alfred = append(alfred, alfredItem{
+				UID:          b.Command,
+				Title:        b.Description,
+				Subtitle:     summaryLine(b.Command),
+				Arg:          b.Command,
+				Autocomplete: b.Description,
+				Match:        strings.Join(append([]string{b.ToolName, b.Description, b.Command}, b.Tags...), " "),
+				Text:         alfredText{Copy: b.Command, LargeType: b.Command},
+				QuickLookURL: b.Link,
+			})
+		}
+		items = alfred
+	case LauncherRaycast:
+		raycast := make([]raycastItem, 0, len(bookmarks))
+		for _, b := range bookmarks {
+			raycast = append(raycast, raycastItem{
+				ID:          b.Command,
+				Title:       b.Description,
+				Subtitle:    summaryLine(b.Command),
+				Arg:         b.Command,
+				Keywords:    append([]string{b.ToolName}, b.Tags...),
+				Accessories: []raycastAccessory{{Text: b.ToolName}},
+				URL:         b.Link,
+			})
+		}
+		items = raycast
+	default:
+		return fmt.Errorf("%w: unknown launcher '%s' (supported: %s, %s)", models.ErrValidation, launcher, LauncherAlfred, LauncherRaycast)
+	}
+
+	data, err := json.MarshalIndent(map[string]any{"items": items}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// summaryLine shortens a multi-line command to its first line
+func summaryLine(command string) string {
+	if first, _, ok := strings.Cut(command, "\n"); ok {
+		return first + " …"
+	}
+	return command
+}
@@ -0,0 +1,84 @@
+//go:build unit
+// +build unit
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+)
+
+func TestWriteLauncherAlfred(t *testing.T) {
+	bookmarks := append(testBookmarks(), dto.BookmarkResponse{Command: "set -e\nmake build", ToolName: "make", Description: "build"})
+	var buf bytes.Buffer
+	if err := WriteLauncher(&buf, LauncherAlfred, bookmarks); err != nil {
+		t.Fatalf("Failed to write Alfred items: %v", err)
+	}
+
+	var out struct {
+		Items []alfredItem `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(out.Items) != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(out.Items))
+	}
+	first := out.Items[0]
+	if first.Title != "list all pods" || first.Subtitle != "kubectl get pods -A" || first.Arg != "kubectl get pods -A" {
+		t.Errorf("Expected description as title and command as subtitle and arg, got %+v", first)
+	}
+	if first.Match != "kubectl list all pods kubectl get pods -A k8s" {
+		t.Errorf("Expected tool, description, command and tags in match, got %q", first.Match)
+	}
+	if out.Items[2].QuickLookURL != "https://kubernetes.io/docs/reference/kubectl/" {
+		t.Errorf("Expected the link as quicklook URL, got %q", out.Items[2].QuickLookURL)
+	}
+	script := out.Items[3]
+	if script.Subtitle != "set -e …" || script.Arg != "set -e\nmake build" {
+		t.Errorf("Expected a one-line subtitle and the full command as arg, got %+v", script)
+	}
+}
+
+func TestWriteLauncherRaycast(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLauncher(&buf, LauncherRaycast, testBookmarks()); err != nil {
+		t.Fatalf("Failed to write Raycast items: %v", err)
+	}
+
+	var out struct {
+		Items []raycastItem `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	first := out.Items[0]
+	if first.Title != "list all pods" || first.Subtitle != "kubectl get pods -A" || first.Arg != "kubectl get pods -A" {
+		t.Errorf("Expected description as title and command as subtitle and arg, got %+v", first)
+	}
+	if len(first.Keywords) != 2 || first.Keywords[0] != "kubectl" || first.Keywords[1] != "k8s" {
+		t.Errorf("Expected tool and tags as keywords, got %v", first.Keywords)
+	}
+}
+
+func TestWriteLauncherEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLauncher(&buf, LauncherAlfred, nil); err != nil {
+		t.Fatalf("Failed to write Alfred items: %v", err)
+	}
+	if buf.String() != "{\n  \"items\": []\n}\n" {
+		t.Errorf("Expected an empty item list, got %q", buf.String())
+	}
+}
+
+func TestWriteLauncherUnknown(t *testing.T) {
+	err := WriteLauncher(&bytes.Buffer{}, Launcher("spotlight"), testBookmarks())
+	if !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
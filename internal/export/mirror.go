@@ -0,0 +1,137 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+// ListFunc returns the bookmarks to export
+type ListFunc func(ctx context.Context) ([]dto.BookmarkResponse, error)
+
+// Mirror keeps an export file in sync with the store
+// Changes are debounced: the file is written once no further change happened
+// for the delay, or when Flush is called.
+type Mirror struct {
+	path   string
+	format Format
+	delay  time.Duration
+	list   ListFunc
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	pending   bool
+	err       error     // Error of the last write done by the timer, reported by Flush
+	lastWrite time.Time // When the file was last written successfully
+	failures  int       // Number of failed writes
+}
+
+// MirrorStatus tells whether the export file is up to date
+type MirrorStatus struct {
+	Pending   bool      // A change was not written yet
+	LastWrite time.Time // Last successful write, zero if none
+	Failures  int       // Failed writes since the mirror was created
+}
+
+// NewMirror creates a mirror writing the bookmarks returned by list to path
+func NewMirror(path string, format Format, delay time.Duration, list ListFunc) *Mirror {
+	return &Mirror{path: path, format: format, delay: delay, list: list}
+}
+
+// Changed schedules a write, postponing one that is already scheduled
+func (m *Mirror) Changed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending = true
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.timer = time.AfterFunc(m.delay, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if err := m.write(); err != nil {
+			m.err = err
+		}
+	})
+}
+
+// Flush writes a scheduled export right away
+// It returns the error of this or an earlier scheduled write.
+func (m *Mirror) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	err := m.write()
+	if err == nil {
+		err, m.err = m.err, nil
+	}
+	return err
+}
+
+// Status reports whether the export file is up to date
+func (m *Mirror) Status() MirrorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return MirrorStatus{Pending: m.pending, LastWrite: m.lastWrite, Failures: m.failures}
+}
+
+// write exports the pending change, counting the outcome; m.mu must be held
+func (m *Mirror) write() error {
+	if !m.pending {
+		return nil
+	}
+	m.pending = false
+
+	if err := m.render(); err != nil {
+		m.failures++
+		return err
+	}
+	m.lastWrite = time.Now()
+	return nil
+}
+
+// render renders the export and replaces the file atomically
+func (m *Mirror) render() error {
+	bookmarks, err := m.list(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, m.format, NewSheet(DefaultTitle, bookmarks)); err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), ".tools-export-*")
+	if err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("failed to auto-export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+//go:build unit
+// +build unit
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+func TestMirrorFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dotfiles", "tools.json")
+	var calls atomic.Int32
+	mirror := NewMirror(path, FormatJSON, time.Hour, func(ctx context.Context) ([]dto.BookmarkResponse, error) {
+		calls.Add(1)
+		return testBookmarks(), nil
+	})
+
+	if err := mirror.Flush(); err != nil {
+		t.Fatalf("Flush without changes failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no export without changes")
+	}
+
+	mirror.Changed()
+	mirror.Changed()
+	if status := mirror.Status(); !status.Pending || !status.LastWrite.IsZero() {
+		t.Errorf("Expected a pending change before the flush, got %+v", status)
+	}
+	if err := mirror.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if status := mirror.Status(); status.Pending || status.LastWrite.IsZero() {
+		t.Errorf("Expected the change written after the flush, got %+v", status)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected one write for debounced changes, got %d", calls.Load())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected export file: %v", err)
+	}
+	var sheet Sheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		t.Fatalf("Expected JSON export: %v", err)
+	}
+	if sheet.Count != 3 || len(sheet.Sections) != 2 || sheet.Sections[1].Tool != "kubectl" {
+		t.Errorf("Unexpected export: %+v", sheet)
+	}
+}
+
+func TestMirrorDebounce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.md")
+	written := make(chan struct{}, 1)
+	mirror := NewMirror(path, FormatMarkdown, 10*time.Millisecond, func(ctx context.Context) ([]dto.BookmarkResponse, error) {
+		written <- struct{}{}
+		return testBookmarks(), nil
+	})
+
+	mirror.Changed()
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the export to be written after the delay")
+	}
+	if err := mirror.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected export file: %v", err)
+	}
+}
+
+func TestMirrorError(t *testing.T) {
+	listErr := errors.New("store unreadable")
+	mirror := NewMirror(filepath.Join(t.TempDir(), "tools.md"), FormatMarkdown, time.Hour, func(ctx context.Context) ([]dto.BookmarkResponse, error) {
+		return nil, listErr
+	})
+
+	mirror.Changed()
+	if err := mirror.Flush(); !errors.Is(err, listErr) {
+		t.Errorf("Expected list error, got %v", err)
+	}
+	if status := mirror.Status(); status.Failures != 1 || !status.LastWrite.IsZero() {
+		t.Errorf("Expected the failed write to be counted, got %+v", status)
+	}
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeMarkdown renders a sheet as Markdown with one heading per tool
+func writeMarkdown(w io.Writer, sheet *Sheet) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", sheet.Title)
+	for _, section := range sheet.Sections {
+		fmt.Fprintf(&b, "\n## %s\n", section.Tool)
+		for _, bookmark := range section.Bookmarks {
+			fmt.Fprintf(&b, "\n**%s**", bookmark.Description)
+			if bookmark.Link != "" {
+				fmt.Fprintf(&b, " ([docs](%s))", bookmark.Link)
+			}
+			b.WriteString("\n\n")
+			fence := codeFence(bookmark.Command)
+			fmt.Fprintf(&b, "%ssh\n%s\n%s\n", fence, bookmark.Command, fence)
+			if len(bookmark.Tags) > 0 {
+				fmt.Fprintf(&b, "\n_%s_\n", strings.Join(bookmark.Tags, ", "))
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write Markdown: %w", err)
+	}
+	return nil
+}
+
+// codeFence returns a fence longer than any backtick run in the command
+func codeFence(command string) string {
+	longest, run := 0, 0
+	for _, r := range command {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// latexEscaper escapes the characters LaTeX treats specially
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\textasciicircum{}`,
+	`~`, `\textasciitilde{}`,
+	`<`, `\textless{}`,
+	`>`, `\textgreater{}`,
+	`|`, `\textbar{}`,
+	`"`, `\textquotedbl{}`,
+)
+
+// writeLaTeX renders a sheet as a compact two-column LaTeX document
+// Compile it with pdflatex to get a printable PDF.
+func writeLaTeX(w io.Writer, sheet *Sheet) error {
+	var b strings.Builder
+	b.WriteString(`\documentclass[9pt]{extarticle}
+\usepackage[T1]{fontenc}
+\usepackage[utf8]{inputenc}
+\usepackage[a4paper,landscape,margin=1cm]{geometry}
+\usepackage{multicol}
+\usepackage{hyperref}
+\setlength{\parindent}{0pt}
+\setlength{\columnseprule}{0.4pt}
+\pagestyle{empty}
+\begin{document}
+`)
+	fmt.Fprintf(&b, "{\\Large\\bfseries %s}\n\\vspace{0.5em}\n\\begin{multicols*}{2}\n", latexEscaper.Replace(sheet.Title))
+	for _, section := range sheet.Sections {
+		fmt.Fprintf(&b, "\n\\subsection*{%s}\n", latexEscaper.Replace(section.Tool))
+		for _, bookmark := range section.Bookmarks {
+			fmt.Fprintf(&b, "\\textbf{%s}", latexEscaper.Replace(bookmark.Description))
+			if bookmark.Link != "" {
+				fmt.Fprintf(&b, " \\href{%s}{(docs)}", latexURL(bookmark.Link))
+			}
+			b.WriteString("\\\\\n")
+			lines := strings.Split(bookmark.Command, "\n")
+			for i, line := range lines {
+				lines[i] = `\texttt{` + latexEscaper.Replace(line) + `}`
+			}
+			b.WriteString(strings.Join(lines, "\\\\\n"))
+			b.WriteString("\n\\par\\smallskip\n")
+		}
+	}
+	b.WriteString("\\end{multicols*}\n\\end{document}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write LaTeX: %w", err)
+	}
+	return nil
+}
+
+// latexURL escapes the characters hyperref does not accept verbatim in a URL
+func latexURL(url string) string {
+	return strings.NewReplacer(`\`, `\\`, `#`, `\#`, `%`, `\%`, `{`, `\{`, `}`, `\}`).Replace(url)
+}
@@ -0,0 +1,209 @@
+// Package highlight renders shell commands with syntax highlighting
+//
+// The tokenizer is deliberately lightweight: it knows words, flags, quoted strings,
+// variables, operators and comments, which is enough to make stored commands easier
+// to read. It never fails; unterminated quotes extend to the end of the input.
+package highlight
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Kind classifies a token
+type Kind int
+
+const (
+	Text     Kind = iota // Arguments and anything else
+	Space                // Whitespace between tokens
+	Program              // First word of a command
+	Flag                 // Word starting with -
+	String               // Single- or double-quoted text, including the quotes
+	Variable             // $NAME, ${NAME}, $1, ...
+	Operator             // Pipes, redirections, command separators and subshell parentheses
+	Comment              // # to the end of the line
+)
+
+// Token is a classified part of a command
+// Concatenating the text of all tokens returns the tokenized command.
+type Token struct {
+	Kind Kind
+	Text string
+}
+
+// styles holds the style of each token kind; kinds without style are rendered as is
+var styles = map[Kind]lipgloss.Style{
+	Program:  lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true),
+	Flag:     lipgloss.NewStyle().Foreground(lipgloss.Color("75")),
+	String:   lipgloss.NewStyle().Foreground(lipgloss.Color("114")),
+	Variable: lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+	Operator: lipgloss.NewStyle().Foreground(lipgloss.Color("204")),
+	Comment:  lipgloss.NewStyle().Foreground(lipgloss.Color("244")),
+}
+
+// operators lists the operators recognized, longest first so ">>" wins over ">"
+var operators = []string{"&&", "||", ">>", "2>", "|", "&", ";", ">", "<", "(", ")"}
+
+// Command renders a command with syntax highlighting
+// Colors are left out when the output does not support them.
+func Command(command string) string {
+	var b strings.Builder
+	for _, token := range Tokenize(command) {
+		if style, ok := styles[token.Kind]; ok {
+			b.WriteString(style.Render(token.Text))
+		} else {
+			b.WriteString(token.Text)
+		}
+	}
+	return b.String()
+}
+
+// Tokenize splits a command into classified tokens
+func Tokenize(command string) []Token {
+	var tokens []Token
+	atCommandStart := true // The next word is a program
+
+	for i := 0; i < len(command); {
+		rest := command[i:]
+		c := command[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			end := i + 1
+			for end < len(command) && strings.IndexByte(" \t\n\r", command[end]) >= 0 {
+				end++
+			}
+			tokens = append(tokens, Token{Space, command[i:end]})
+			if strings.Contains(command[i:end], "\n") {
+				atCommandStart = true
+			}
+			i = end
+
+		case c == '#':
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, Token{Comment, rest[:end]})
+			i += end
+
+		case operatorAt(rest) != "":
+			op := operatorAt(rest)
+			tokens = append(tokens, Token{Operator, op})
+			// Redirections are followed by a file name, not a program
+			atCommandStart = !strings.ContainsAny(op, "<>")
+			i += len(op)
+
+		default:
+			word := wordTokens(command, i, atCommandStart)
+			for _, t := range word {
+				i += len(t.Text)
+			}
+			tokens = append(tokens, word...)
+			atCommandStart = false
+		}
+	}
+	return tokens
+}
+
+// operatorAt returns the operator at the start of s, or ""
+func operatorAt(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// wordTokens splits the word starting at command[start] into plain, quoted and variable parts
+func wordTokens(command string, start int, isProgram bool) []Token {
+	kind := Text
+	switch {
+	case isProgram:
+		kind = Program
+	case command[start] == '-':
+		kind = Flag
+	}
+
+	var tokens []Token
+	plain := start // Start of the pending plain part
+	flush := func(end int) {
+		if end > plain {
+			tokens = append(tokens, Token{kind, command[plain:end]})
+		}
+	}
+
+	i := start
+	for i < len(command) {
+		c := command[i]
+		// A 2 inside a word is no redirection, e.g. in "file2>out"
+		if op := operatorAt(command[i:]); strings.IndexByte(" \t\n\r", c) >= 0 || op != "" && op != "2>" {
+			break
+		}
+
+		switch {
+		case c == '\\' && i+1 < len(command):
+			i += 2
+		case c == '\'' || c == '"':
+			flush(i)
+			end := closingQuote(command, i)
+			tokens = append(tokens, Token{String, command[i:end]})
+			i, plain = end, end
+		case c == '$' && i+1 < len(command):
+			end := variableEnd(command, i)
+			if end == i+1 {
+				i++
+				continue
+			}
+			flush(i)
+			tokens = append(tokens, Token{Variable, command[i:end]})
+			i, plain = end, end
+		default:
+			i++
+		}
+	}
+	flush(i)
+	return tokens
+}
+
+// closingQuote returns the index after the quote closing the one at command[start]
+func closingQuote(command string, start int) int {
+	quote := command[start]
+	for i := start + 1; i < len(command); i++ {
+		switch {
+		case command[i] == '\\' && quote == '"':
+			i++
+		case command[i] == quote:
+			return i + 1
+		}
+	}
+	return len(command)
+}
+
+// variableEnd returns the index after the variable reference at command[start]
+func variableEnd(command string, start int) int {
+	i := start + 1
+	if command[i] == '{' {
+		if end := strings.IndexByte(command[i:], '}'); end >= 0 {
+			return i + end + 1
+		}
+		return len(command)
+	}
+	if isDigit(command[i]) || strings.IndexByte("?#@*!$-", command[i]) >= 0 {
+		return i + 1
+	}
+	for i < len(command) && (isDigit(command[i]) || command[i] == '_' || isLetter(command[i])) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,63 @@
+//go:build unit
+// +build unit
+
+package highlight
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected []Token
+	}{
+		{
+			"kubectl get pods -n dev",
+			[]Token{{Program, "kubectl"}, {Space, " "}, {Text, "get"}, {Space, " "}, {Text, "pods"}, {Space, " "}, {Flag, "-n"}, {Space, " "}, {Text, "dev"}},
+		},
+		{
+			`ps aux | grep "my app"`,
+			[]Token{{Program, "ps"}, {Space, " "}, {Text, "aux"}, {Space, " "}, {Operator, "|"}, {Space, " "}, {Program, "grep"}, {Space, " "}, {String, `"my app"`}},
+		},
+		{
+			"echo ${HOME}/x > out.txt",
+			[]Token{{Program, "echo"}, {Space, " "}, {Variable, "${HOME}"}, {Text, "/x"}, {Space, " "}, {Operator, ">"}, {Space, " "}, {Text, "out.txt"}},
+		},
+		{
+			"docker ps --format='{{.Names}}' && ls # done",
+			[]Token{{Program, "docker"}, {Space, " "}, {Text, "ps"}, {Space, " "}, {Flag, "--format="}, {String, "'{{.Names}}'"}, {Space, " "}, {Operator, "&&"}, {Space, " "}, {Program, "ls"}, {Space, " "}, {Comment, "# done"}},
+		},
+		{
+			"echo 'unterminated",
+			[]Token{{Program, "echo"}, {Space, " "}, {String, "'unterminated"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if tokens := Tokenize(tt.command); !reflect.DeepEqual(tokens, tt.expected) {
+			t.Errorf("Tokenize(%q) =\n%v\nwant\n%v", tt.command, tokens, tt.expected)
+		}
+	}
+}
+
+func TestTokenizeKeepsText(t *testing.T) {
+	commands := []string{
+		"for f in *.log; do\n  gzip \"$f\" 2>&1\ndone",
+		`awk '{print $1}' file2>out | sort -u`,
+		`echo \"a b\" $ $(date)`,
+		"",
+	}
+
+	for _, command := range commands {
+		var b strings.Builder
+		for _, token := range Tokenize(command) {
+			b.WriteString(token.Text)
+		}
+		if b.String() != command {
+			t.Errorf("Tokens of %q joined to %q", command, b.String())
+		}
+	}
+}
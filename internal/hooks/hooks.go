@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/runner"
+)
+
+// Event identifies the operation a hook runs after
+type Event string
+
+const (
+	EventAdd    Event = "add"
+	EventEdit   Event = "edit"
+	EventDelete Event = "delete"
+	EventSelect Event = "select"
+)
+
+// DefaultTimeout bounds how long a hook may run
+const DefaultTimeout = 10 * time.Second
+
+// Runner runs user-defined hooks
+type Runner interface {
+	// Fire runs the hook of the event, if one is configured
+	// previous is the bookmark's command before an edit, empty otherwise
+	Fire(ctx context.Context, event Event, bookmark *models.Bookmark, previous string) error
+}
+
+// ShellRunner runs hooks as shell commands
+// The bookmark is passed in TOOLS_* environment variables; hook output goes to Output.
+type ShellRunner struct {
+	scripts map[Event]string
+	Timeout time.Duration
+	Output  io.Writer
+}
+
+// NewShellRunner creates a runner for the given event scripts; events without a script are ignored
+func NewShellRunner(scripts map[Event]string) *ShellRunner {
+	return &ShellRunner{
+		scripts: scripts,
+		Timeout: DefaultTimeout,
+		Output:  os.Stderr,
+	}
+}
+
+// FromConfig creates a runner for the hooks of the configuration file
+func FromConfig(cfg config.HooksConfig) *ShellRunner {
+	return NewShellRunner(map[Event]string{
+		EventAdd:    cfg.OnAdd,
+		EventEdit:   cfg.OnEdit,
+		EventDelete: cfg.OnDelete,
+		EventSelect: cfg.OnSelect,
+	})
+}
+
+// Fire runs the hook script of the event and waits for it
+func (r *ShellRunner) Fire(ctx context.Context, event Event, bookmark *models.Bookmark, previous string) error {
+	script := r.scripts[event]
+	if script == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, runner.Shell(), "-c", script)
+	cmd.Env = append(os.Environ(), Env(event, bookmark, previous)...)
+	cmd.Stdout = r.Output
+	cmd.Stderr = r.Output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("on_%s hook failed: %w", event, err)
+	}
+	return nil
+}
+
+// Env returns the environment variables describing the event to a hook
+func Env(event Event, bookmark *models.Bookmark, previous string) []string {
+	env := []string{
+		"TOOLS_EVENT=" + string(event),
+		"TOOLS_COMMAND=" + bookmark.Command,
+		"TOOLS_TOOL_NAME=" + bookmark.ToolName,
+		"TOOLS_DESCRIPTION=" + bookmark.Description,
+		"TOOLS_TAGS=" + strings.Join(bookmark.Tags, ","),
+		"TOOLS_LINK=" + bookmark.Link,
+	}
+	if previous != "" {
+		env = append(env, "TOOLS_PREVIOUS_COMMAND="+previous)
+	}
+	return env
+}
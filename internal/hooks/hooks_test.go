@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package hooks
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestShellRunnerPassesBookmark(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "hook.out")
+	r := NewShellRunner(map[Event]string{
+		EventAdd: `printf '%s|%s|%s|%s|%s' "$TOOLS_EVENT" "$TOOLS_COMMAND" "$TOOLS_TOOL_NAME" "$TOOLS_DESCRIPTION" "$TOOLS_TAGS" > ` + out,
+	})
+
+	bookmark := &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods", Tags: []string{"k8s", "prod"}}
+	if err := r.Fire(context.Background(), EventAdd, bookmark, ""); err != nil {
+		t.Fatalf("Hook failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Hook did not run: %v", err)
+	}
+	if expected := "add|kubectl get pods|kubectl|list pods|k8s,prod"; string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, data)
+	}
+}
+
+func TestShellRunnerWithoutScript(t *testing.T) {
+	r := NewShellRunner(map[Event]string{EventAdd: "exit 1"})
+
+	if err := r.Fire(context.Background(), EventDelete, &models.Bookmark{Command: "ls"}, ""); err != nil {
+		t.Errorf("Expected events without a hook to be ignored, got %v", err)
+	}
+}
+
+func TestShellRunnerFailure(t *testing.T) {
+	r := NewShellRunner(map[Event]string{EventSelect: "echo failing; exit 3"})
+	r.Output = io.Discard
+
+	err := r.Fire(context.Background(), EventSelect, &models.Bookmark{Command: "ls"}, "")
+	if err == nil || !strings.Contains(err.Error(), "on_select hook failed") {
+		t.Errorf("Expected hook failure, got %v", err)
+	}
+}
+
+func TestEnvPreviousCommand(t *testing.T) {
+	env := Env(EventEdit, &models.Bookmark{Command: "new"}, "old")
+	if !slices.Contains(env, "TOOLS_PREVIOUS_COMMAND=old") {
+		t.Errorf("Expected previous command in %v", env)
+	}
+
+	env = Env(EventAdd, &models.Bookmark{Command: "new"}, "")
+	if slices.ContainsFunc(env, func(v string) bool { return strings.HasPrefix(v, "TOOLS_PREVIOUS_COMMAND=") }) {
+		t.Errorf("Expected no previous command in %v", env)
+	}
+}
@@ -0,0 +1,157 @@
+package i18n
+
+func init() {
+	register("de", map[string]string{
+		// TUI list
+		"Tools - Command Bookmarks": "Tools - Befehls-Lesezeichen",
+		"↑/↓: navigate • enter: select (copies to clipboard) • x: run • a: add • c: duplicate • e: edit • f2: quick edit • d: delete • v: revisions • i: details • 1/2/3: sort • /: filter • t: group • s: tools • q/esc: quit": "↑/↓: navigieren • enter: auswählen (kopiert in die Zwischenablage) • x: ausführen • a: hinzufügen • c: duplizieren • e: bearbeiten • f2: schnell bearbeiten • d: löschen • v: Versionen • i: Details • 1/2/3: sortieren • /: filtern • t: gruppieren • s: Tools • q/esc: beenden",
+		"Tool":        "Tool",
+		"Description": "Beschreibung",
+		"Command":     "Befehl",
+		"Error: %v":   "Fehler: %v",
+		"Saved script to %s and copied its path to your clipboard": "Skript unter %s gespeichert und den Pfad in die Zwischenablage kopiert",
+		"Copied command '%s' to your clipboard":                    "Befehl '%s' in die Zwischenablage kopiert",
+		"Warning: %v":                                              "Warnung: %v",
+
+		// TUI filter and view
+		"type to filter • enter: keep • esc: cancel": "tippen zum Filtern • enter: übernehmen • esc: abbrechen",
+		"filter: %s (%d of %d)":                      "Filter: %s (%d von %d)",
+		"grouped by tool":                            "nach Tool gruppiert",
+		"ctrl+l: reset view":                         "ctrl+l: Ansicht zurücksetzen",
+		"tool: %s":                                   "Tool: %s",
+		"All":                                        "Alle",
+		"↑/↓: choose tool • enter/tab: to the bookmarks • s: hide tools • q/esc: quit": "↑/↓: Tool wählen • enter/tab: zu den Lesezeichen • s: Tools ausblenden • q/esc: beenden",
+
+		// TUI add and edit forms
+		"Add New Example":   "Neues Beispiel hinzufügen",
+		"Duplicate Example": "Beispiel duplizieren",
+		"Edit Example":      "Beispiel bearbeiten",
+		"Change the command to save a variant of: %s": "Ändere den Befehl, um eine Variante zu speichern von: %s",
+		"Command:":                       "Befehl:",
+		"Tool Name:":                     "Toolname:",
+		"Description:":                   "Beschreibung:",
+		"Tags:":                          "Tags:",
+		"Command (e.g., lsof -i :54321)": "Befehl (z. B. lsof -i :54321)",
+		"Tool name (e.g., lsof)":         "Toolname (z. B. lsof)",
+		"Description (e.g., list all ports at port 54321)":                            "Beschreibung (z. B. alle Prozesse auf Port 54321 anzeigen)",
+		"Tags, comma-separated (e.g., k8s, prod)":                                     "Tags, durch Kommas getrennt (z. B. k8s, prod)",
+		"tab/shift+tab: navigate • enter: submit • esc: cancel":                       "tab/shift+tab: navigieren • enter: speichern • esc: abbrechen",
+		"tab/shift+tab: navigate • enter: submit • alt+enter: new line • esc: cancel": "tab/shift+tab: navigieren • enter: speichern • alt+enter: neue Zeile • esc: abbrechen",
+		"tool name, description, and command are required":                            "Toolname, Beschreibung und Befehl sind erforderlich",
+
+		// TUI form warnings
+		"the command reached the limit of %d characters, longer ones are cut": "der Befehl hat die Grenze von %d Zeichen erreicht, längere werden abgeschnitten",
+		"long command (%d characters), consider a script or a runbook":        "langer Befehl (%d Zeichen), besser als Skript oder Runbook",
+		"already bookmarked for %s: %s":                                       "bereits gespeichert für %s: %s",
+		"no tool found in the command, enter the tool name":                   "kein Tool im Befehl gefunden, gib den Toolnamen ein",
+		"careful, this command %s":                                            "Vorsicht, dieser Befehl %s",
+		"removes everything under /, ~ or the current directory":              "löscht alles unter /, ~ oder im aktuellen Verzeichnis",
+		"formats a file system":                                               "formatiert ein Dateisystem",
+		"writes to a device":                                                  "schreibt auf ein Gerät",
+		"is a fork bomb":                                                      "ist eine Fork-Bombe",
+		"makes every file writable by everyone":                               "macht jede Datei für alle beschreibbar",
+		"runs a script downloaded from the network":                           "führt ein aus dem Netz geladenes Skript aus",
+		"overwrites the remote history":                                       "überschreibt die entfernte Historie",
+		"contains a likely %s, read it from an environment variable instead":  "enthält vermutlich: %s, lies es besser aus einer Umgebungsvariable",
+		"no bookmarks for %s yet, did you mean %s?":                           "noch keine Lesezeichen für %s, meintest du %s?",
+
+		// TUI delete and preview
+		"Confirm Delete":                      "Löschen bestätigen",
+		"Delete example '%s' from tool '%s'?": "Beispiel '%s' von Tool '%s' löschen?",
+		"Command: %s":                         "Befehl: %s",
+		"y: yes • n/esc: no":                  "y: ja • n/esc: nein",
+		"Preview Command":                     "Vorschau des Befehls",
+		"Stored:   %s":                        "Gespeichert: %s",
+		"Expanded: %s":                        "Ersetzt:     %s",
+		"%s cannot be empty":                  "%s darf nicht leer sein",
+		"enter: save • tab: switch tool/description • esc: cancel": "enter: speichern • tab: Tool/Beschreibung wechseln • esc: abbrechen",
+		"Welcome to tools! Start with some bookmarks?":             "Willkommen bei tools! Mit ein paar Lesezeichen starten?",
+		"%d bookmarks": "%d Lesezeichen",
+		"space: select • a: all • enter: install • esc: skip":  "Leertaste: auswählen • a: alle • enter: installieren • esc: überspringen",
+		"Warning: missing requirements: %s":                    "Warnung: fehlende Voraussetzungen: %s",
+		"Warning: unset variables left unexpanded: %s":         "Warnung: nicht gesetzte Variablen wurden nicht ersetzt: %s",
+		"edit the command if needed • enter: copy • esc: back": "Befehl bei Bedarf bearbeiten • enter: kopieren • esc: zurück",
+		"edit the command if needed • enter: run • esc: back":  "Befehl bei Bedarf bearbeiten • enter: ausführen • esc: zurück",
+		"Running '%s'":              "Führe '%s' aus",
+		"exit code %d after %s, %s": "Exit-Code %d nach %s, %s",
+		"every %s":                  "alle %s",
+		"(due)":                     "(fällig)",
+		"Run '%s' on %s via ssh?":   "'%s' per ssh auf %s ausführen?",
+		"'%s' contains redacted values. Reveal them?": "'%s' enthält verborgene Werte. Anzeigen?",
+
+		// TUI details and revisions
+		"Bookmark":              "Lesezeichen",
+		"Related":               "Ähnlich",
+		"No related bookmarks.": "Keine ähnlichen Lesezeichen.",
+		"esc: back":             "esc: zurück",
+		"↑/↓: select related • enter: open • esc: back": "↑/↓: ähnliches auswählen • enter: öffnen • esc: zurück",
+		"o: open link • ":        "o: Link öffnen • ",
+		"v: OS variants • ":      "v: OS-Varianten • ",
+		"OS Variants":            "OS-Varianten",
+		"%s (this system):":      "%s (dieses System):",
+		"same as the command":    "wie der Befehl",
+		"Revisions":              "Versionen",
+		"Current: %s":            "Aktuell: %s",
+		"No previous revisions.": "Keine früheren Versionen.",
+		"Restoring #%d changes:": "Wiederherstellen von #%d ändert:",
+		"↑/↓: select revision • r/enter: restore • esc: back": "↑/↓: Version auswählen • r/enter: wiederherstellen • esc: zurück",
+		"(identical to current)": "(identisch mit der aktuellen Version)",
+
+		// Plain mode
+		"Enter a number to select, text to search, nothing to list all, or q to quit: ": "Nummer zum Auswählen, Text zum Suchen, nichts für alle oder b zum Beenden: ",
+		"There is no bookmark number %d.":                                               "Es gibt kein Lesezeichen Nummer %d.",
+		"No bookmarks match '%s'.":                                                      "Keine Lesezeichen passen zu '%s'.",
+		"No bookmarks yet. Use 'tools add' to add your first one.":                      "Noch keine Lesezeichen. Füge mit 'tools add' das erste hinzu.",
+		"%d bookmarks:":                     "%d Lesezeichen:",
+		"Use the expanded command? [y/N]: ": "Den ersetzten Befehl verwenden? [j/N]: ",
+		"Saved script to %s":                "Skript unter %s gespeichert",
+
+		// Dynamic placeholders
+		"Fill Placeholder {%s}":                             "Platzhalter {%s} füllen",
+		"The values come from running this command:":        "Die Werte liefert dieser Befehl:",
+		"y/enter: run it • n/esc: cancel":                   "y/enter: ausführen • n/esc: abbrechen",
+		"Running %s …":                                      "%s läuft …",
+		"esc: cancel":                                       "esc: abbrechen",
+		"↑/↓: navigate • enter: use value • esc: cancel":    "↑/↓: navigieren • enter: Wert verwenden • esc: abbrechen",
+		"provider of {%s} failed: %v":                       "Befehl für {%s} fehlgeschlagen: %v",
+		"provider of {%s} returned no values":               "Befehl für {%s} lieferte keine Werte",
+		"The values of {%s} come from running: %s":          "Die Werte für {%s} liefert der Befehl: %s",
+		"Run it? [y/N]: ":                                   "Ausführen? [j/N]: ",
+		"Press enter to use it or type a changed command: ": "Enter zum Verwenden oder einen geänderten Befehl eingeben: ",
+
+		// CLI prompts
+		" [y/N]: ":                     " [j/N]: ",
+		"y":                            "j",
+		"yes":                          "ja",
+		"q":                            "b",
+		"quit":                         "beenden",
+		"s":                            "ü",
+		"skip":                         "überspringen",
+		"Run step %d? [y/N, s: skip] ": "Schritt %d ausführen? [j/N, ü: überspringen] ",
+		"This will delete %d example(s) for tool '%s'. Continue?": "Damit werden %d Beispiel(e) von Tool '%s' gelöscht. Fortfahren?",
+
+		// Canonical commands
+		"Canonical form: %s\nStore it instead?": "Kanonische Form: %s\nStattdessen diese speichern?",
+		"canonical: %s (ctrl+n to use it)":      "kanonisch: %s (ctrl+n übernimmt sie)",
+		"changes:":                              "Änderungen:",
+
+		"a":                                   "a",
+		"archive":                             "archivieren",
+		"Remove? [y/N, a: archive, q: quit] ": "Entfernen? [j/N, a: archivieren, b: beenden] ",
+		"Remove these %d bookmarks?":          "Diese %d Lesezeichen entfernen?",
+		"Warning: the store has grown to %d bookmarks (%d KB), beyond 'store_limit' in the config. Remove bookmarks you no longer use with 'tools prune --unused-for 180d'.": "Warnung: Der Speicher ist auf %d Lesezeichen (%d KB) angewachsen, mehr als 'store_limit' in der Konfiguration erlaubt. Entferne nicht mehr genutzte Lesezeichen mit 'tools prune --unused-for 180d'.",
+		"Replace your store with snapshot '%s'?": "Den Speicher durch den Snapshot '%s' ersetzen?",
+		"Tool name":                              "Toolname",
+		"%d examples match '%s':":                "%d Beispiele passen zu '%s':",
+		"Select [1-%d]: ":                        "Auswahl [1-%d]: ",
+		"      ran %dx, last %s":                 "      %dx ausgeführt, zuletzt %s",
+		" in %s":                                 " in %s",
+		"Bookmark? [y/N/q] ":                     "Als Lesezeichen speichern? [j/N/b] ",
+		"  Tool name [%s]: ":                     "  Toolname [%s]: ",
+		"  Description: ":                        "  Beschreibung: ",
+		"  Description [%s]: ":                   "  Beschreibung [%s]: ",
+		"  Skipped: a description is required":   "  Übersprungen: eine Beschreibung ist erforderlich",
+		"  Skipped: %v":                          "  Übersprungen: %v",
+		"Warning: %s was damaged (%v). Restored %d bookmarks from the backup of %s; the damaged file was kept as %s.": "Warnung: %s war beschädigt (%v). %d Lesezeichen wurden aus der Sicherung vom %s wiederhergestellt; die beschädigte Datei wurde als %s behalten.",
+	})
+}
@@ -0,0 +1,120 @@
+// Package i18n translates user-facing messages of the CLI and TUI.
+//
+// Messages are identified by their English text, so code stays readable and
+// English needs no catalog:
+//
+//	fmt.Println(i18n.T("Copied command '%s' to your clipboard", command))
+//
+// To add a locale, create a catalog file like catalog_de.go that registers a
+// map from English messages to their translation. Messages missing from a
+// catalog fall back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when no supported language is configured
+const DefaultLanguage = "en"
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{DefaultLanguage: {}}
+	current  = DefaultLanguage
+)
+
+// register adds the catalog of a language; called from the catalog files
+func register(lang string, catalog map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	catalogs[lang] = catalog
+}
+
+// Languages returns the supported languages
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Detect picks the language from the configured value, falling back to the
+// LC_ALL, LC_MESSAGES and LANG environment variables
+// Locale names like de_DE.UTF-8 are reduced to their language; unsupported
+// languages yield DefaultLanguage.
+func Detect(configured string) string {
+	if lang, ok := supported(configured); ok {
+		return lang
+	}
+	// The first variable that is set decides, like for gettext
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			if lang, ok := supported(value); ok {
+				return lang
+			}
+			break
+		}
+	}
+	return DefaultLanguage
+}
+
+// supported reduces a locale to its language and reports whether it has a catalog
+func supported(locale string) (string, bool) {
+	if locale == "" {
+		return "", false
+	}
+	lang := normalize(locale)
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// SetLanguage selects the language of translated messages
+func SetLanguage(lang string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := catalogs[lang]; ok {
+		current = lang
+	} else {
+		current = DefaultLanguage
+	}
+}
+
+// Language returns the selected language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T translates a message to the selected language and formats it with args
+func T(message string, args ...any) string {
+	mu.RLock()
+	translated, ok := catalogs[current][message]
+	mu.RUnlock()
+	if !ok {
+		translated = message
+	}
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
+
+// normalize reduces a locale name such as de_DE.UTF-8 to its language
+func normalize(locale string) string {
+	lang, _, _ := strings.Cut(locale, ".")
+	lang, _, _ = strings.Cut(lang, "@")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	return strings.ToLower(lang)
+}
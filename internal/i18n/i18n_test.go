@@ -0,0 +1,129 @@
+//go:build unit
+// +build unit
+
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		env        map[string]string
+		expected   string
+	}{
+		{"configured wins", "de", map[string]string{"LANG": "en_US.UTF-8"}, "de"},
+		{"LANG", "", map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{"LC_ALL before LANG", "", map[string]string{"LC_ALL": "de_AT", "LANG": "en_US"}, "de"},
+		{"first set variable decides", "", map[string]string{"LC_ALL": "fr_FR.UTF-8", "LANG": "de_DE"}, "en"},
+		{"unsupported configured falls back to environment", "xx", map[string]string{"LANG": "de"}, "de"},
+		{"C locale", "", map[string]string{"LANG": "C"}, "en"},
+		{"nothing set", "", nil, "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := Detect(tt.configured); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(DefaultLanguage)
+
+	if got := T("Command: %s", "ls"); got != "Command: ls" {
+		t.Errorf("Expected English message, got %q", got)
+	}
+
+	SetLanguage("de")
+	if got := T("Command: %s", "ls"); got != "Befehl: ls" {
+		t.Errorf("Expected German message, got %q", got)
+	}
+	if got := T("Not translated %d", 1); got != "Not translated 1" {
+		t.Errorf("Expected English fallback, got %q", got)
+	}
+
+	SetLanguage("xx")
+	if Language() != DefaultLanguage {
+		t.Errorf("Expected unsupported language to select %s, got %s", DefaultLanguage, Language())
+	}
+}
+
+var verb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsKeepFormatVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for message, translated := range catalog {
+			if !slices.Equal(verb.FindAllString(message, -1), verb.FindAllString(translated, -1)) {
+				t.Errorf("%s: %q changes the format verbs of %q", lang, translated, message)
+			}
+		}
+	}
+}
+
+// TestCatalogsCoverMessages checks that every message passed to T in the CLI and TUI is translated
+func TestCatalogsCoverMessages(t *testing.T) {
+	messages := map[string]bool{}
+	for _, dir := range []string{"../cli", "../tui"} {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "T" {
+					return true
+				}
+				if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+					return true
+				}
+				if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					message, _ := strconv.Unquote(lit.Value)
+					messages[message] = true
+				}
+				return true
+			})
+		}
+	}
+
+	if len(messages) == 0 {
+		t.Fatal("Expected translated messages in the CLI and TUI")
+	}
+	for lang, catalog := range catalogs {
+		if lang == DefaultLanguage {
+			continue
+		}
+		for message := range messages {
+			if _, ok := catalog[message]; !ok {
+				t.Errorf("%s: missing translation of %q", lang, message)
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds how long a tool's help or man page may take to render
+const commandTimeout = 5 * time.Second
+
+// Example is an example invocation discovered in a tool's documentation
+type Example struct {
+	Command     string
+	Description string
+	Source      string // "help" or "man"
+}
+
+// overstrike matches the backspace sequences man uses for bold and underlined text
+var overstrike = regexp.MustCompile(".\x08")
+
+// Discover runs "<tool> --help" and "man <tool>" and returns the example invocations found
+// in their EXAMPLES sections, without duplicates. It only fails when neither source is available.
+func Discover(ctx context.Context, tool string) ([]Example, error) {
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil, fmt.Errorf("tool '%s' not found in PATH", tool)
+	}
+
+	var examples []Example
+	seen := map[string]bool{}
+	add := func(found []Example, source string) {
+		for _, ex := range found {
+			if seen[ex.Command] {
+				continue
+			}
+			seen[ex.Command] = true
+			ex.Source = source
+			examples = append(examples, ex)
+		}
+	}
+
+	helpOut, helpErr := runCommand(ctx, tool, "--help")
+	if helpErr == nil {
+		add(ParseExamples(helpOut, tool), "help")
+	}
+
+	manOut, manErr := runCommand(ctx, "man", tool)
+	if manErr == nil {
+		add(ParseExamples(overstrike.ReplaceAllString(manOut, ""), tool), "man")
+	}
+
+	if helpErr != nil && manErr != nil {
+		return nil, fmt.Errorf("failed to read documentation of '%s': %w", tool, helpErr)
+	}
+
+	return examples, nil
+}
+
+// runCommand returns the combined output of a documentation command
+// Many tools print help to stderr or exit non-zero after printing it, so only
+// an empty output counts as failure.
+func runCommand(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.Env = append(os.Environ(), "MANPAGER=cat", "PAGER=cat", "MANWIDTH=200")
+
+	err := cmd.Run()
+	if out.Len() == 0 {
+		if err == nil {
+			err = fmt.Errorf("%s produced no output", name)
+		}
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// ParseExamples extracts example invocations of tool from the EXAMPLES sections of help text
+// A line is an invocation when it starts with "$ " or with the tool's name. The paragraph of comment
+// or text lines before an invocation describes it; otherwise a more indented line right after it does.
+// Invocations continued with a trailing backslash are joined.
+func ParseExamples(text, tool string) []Example {
+	name := filepath.Base(tool)
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var examples []Example
+	inSection := false
+	sectionIndent := 0
+	pending := ""    // description waiting for its invocation
+	lastIndent := -1 // indentation of the last invocation
+	lastHasDesc := true
+	afterBlank := false
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if isExamplesHeader(trimmed) {
+			inSection = true
+			sectionIndent = indent
+			pending, lastIndent, lastHasDesc = "", -1, true
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if trimmed == "" {
+			afterBlank = true
+			continue
+		}
+		if indent <= sectionIndent {
+			// Next section starts
+			inSection = false
+			continue
+		}
+
+		command, ok := invocation(trimmed, name)
+		if ok {
+			for strings.HasSuffix(command, "\\") && i+1 < len(lines) {
+				i++
+				command = strings.TrimSpace(strings.TrimSuffix(command, "\\")) + " " + strings.TrimSpace(lines[i])
+			}
+			examples = append(examples, Example{Command: command, Description: pending})
+			lastHasDesc = pending != ""
+			lastIndent = indent
+			pending = ""
+			afterBlank = false
+			continue
+		}
+
+		desc := cleanDescription(trimmed)
+		if !lastHasDesc && indent > lastIndent {
+			examples[len(examples)-1].Description = desc
+			lastHasDesc = true
+			continue
+		}
+		// A new paragraph replaces any description that was not used
+		if pending == "" || afterBlank {
+			pending = desc
+		} else {
+			pending += " " + desc
+		}
+		lastHasDesc = true
+		afterBlank = false
+	}
+
+	return examples
+}
+
+// isExamplesHeader reports whether a line opens an EXAMPLES section
+func isExamplesHeader(trimmed string) bool {
+	switch strings.ToLower(strings.TrimSuffix(trimmed, ":")) {
+	case "example", "examples", "example usage", "examples of use":
+		return true
+	}
+	return false
+}
+
+// invocation returns the command of an example line, if it is one
+func invocation(trimmed, name string) (string, bool) {
+	if command, ok := strings.CutPrefix(trimmed, "$ "); ok {
+		return strings.TrimSpace(command), true
+	}
+	fields := strings.Fields(trimmed)
+	if len(fields) > 0 && fields[0] == name {
+		return trimmed, true
+	}
+	return "", false
+}
+
+// cleanDescription strips comment markers and trailing punctuation from a description line
+func cleanDescription(trimmed string) string {
+	text := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+	return strings.TrimRight(text, ":.")
+}
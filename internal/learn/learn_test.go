@@ -0,0 +1,92 @@
+//go:build unit
+// +build unit
+
+package learn
+
+import (
+	"testing"
+)
+
+func TestParseExamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		text     string
+		expected []Example
+	}{
+		{
+			name: "cobra style help",
+			tool: "kubectl",
+			text: `Display one or many resources.
+
+Examples:
+  # List all pods in ps output format
+  kubectl get pods
+
+  # List a single replication controller with specified NAME in ps output format
+  kubectl get replicationcontroller web
+
+Options:
+  -A, --all-namespaces=false: list across all namespaces
+`,
+			expected: []Example{
+				{Command: "kubectl get pods", Description: "List all pods in ps output format"},
+				{Command: "kubectl get replicationcontroller web", Description: "List a single replication controller with specified NAME in ps output format"},
+			},
+		},
+		{
+			name: "man page with description after the command",
+			tool: "/usr/bin/tar",
+			text: `NAME
+       tar - an archiving utility
+
+EXAMPLES
+       tar -cf archive.tar foo bar
+              Create archive.tar from files foo and bar.
+
+       tar -xf archive.tar
+              Extract all files from archive.tar.
+
+SEE ALSO
+       gzip(1)
+`,
+			expected: []Example{
+				{Command: "tar -cf archive.tar foo bar", Description: "Create archive.tar from files foo and bar"},
+				{Command: "tar -xf archive.tar", Description: "Extract all files from archive.tar"},
+			},
+		},
+		{
+			name: "prompt prefix and line continuation",
+			tool: "curl",
+			text: `EXAMPLE
+    To post JSON, use:
+
+        $ curl -X POST \
+            -d '{"a":1}' https://example.com
+`,
+			expected: []Example{
+				{Command: `curl -X POST -d '{"a":1}' https://example.com`, Description: "To post JSON, use"},
+			},
+		},
+		{
+			name:     "no examples section",
+			tool:     "ls",
+			text:     "Usage: ls [OPTION]... [FILE]...\n  ls -la\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseExamples(tt.text, tt.tool)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %d examples, got %d: %+v", len(tt.expected), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Example %d: expected %+v, got %+v", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
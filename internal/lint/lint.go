@@ -0,0 +1,162 @@
+// Package lint checks bookmarks for problems: descriptions that don't say what a command does,
+// hard-coded credentials, paths that only exist on one machine and deprecated flags
+// Rules are pluggable, see Register.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+// Severity ranks findings, from hints to problems that should be fixed before sharing
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// severityNames are the names of the severities, in order
+var severityNames = []string{"info", "warning", "error"}
+
+// String returns the name of the severity, e.g. warning
+func (s Severity) String() string {
+	if s < Info || s > Error {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity with the given name: info, warning or error
+func ParseSeverity(name string) (Severity, error) {
+	for i, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity '%s' (supported: %s)", name, strings.Join(severityNames, ", "))
+}
+
+// Problem is what a rule found in one bookmark
+type Problem struct {
+	Message string
+
+	// Fix is the command with the problem fixed, empty when it cannot be fixed without
+	// possibly changing what the command does
+	Fix string
+}
+
+// Rule checks bookmarks for one kind of problem
+type Rule struct {
+	Name        string // Identifies the rule, e.g. in --rule
+	Description string // What the rule checks
+	Severity    Severity
+	Check       func(bookmark dto.BookmarkResponse) []Problem
+}
+
+// Finding is a problem of a bookmark, with the rule that found it
+type Finding struct {
+	Command  string
+	Rule     string
+	Severity Severity
+	Message  string
+	Fix      string // See Problem
+}
+
+// Fixable reports whether the finding can be fixed safely
+func (f Finding) Fixable() bool {
+	return f.Fix != ""
+}
+
+// rules are the registered rules, in the order findings are reported
+var rules []Rule
+
+func init() {
+	Register(descriptionRule)
+	Register(credentialsRule)
+	Register(pathsRule)
+	Register(deprecatedRule)
+}
+
+// Register adds a rule checked by Run
+// It panics if a rule with the same name is registered already.
+func Register(rule Rule) {
+	if _, ok := Lookup(rule.Name); ok {
+		panic(fmt.Sprintf("lint: rule '%s' registered twice", rule.Name))
+	}
+	rules = append(rules, rule)
+}
+
+// Rules returns the registered rules, in registration order
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+// Lookup returns the registered rule with the given name
+func Lookup(name string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Run checks the bookmarks with the given rules and returns the findings, grouped by bookmark
+// in the order of the bookmarks and, per bookmark, in the order of the rules
+func Run(bookmarks []dto.BookmarkResponse, rules []Rule) []Finding {
+	var findings []Finding
+	for _, bookmark := range bookmarks {
+		findings = append(findings, Check(bookmark, rules)...)
+	}
+	return findings
+}
+
+// Check checks a single bookmark with the given rules
+func Check(bookmark dto.BookmarkResponse, rules []Rule) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		for _, problem := range rule.Check(bookmark) {
+			findings = append(findings, Finding{
+				Command:  bookmark.Command,
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Message:  problem.Message,
+				Fix:      problem.Fix,
+			})
+		}
+	}
+	return findings
+}
+
+// maxFixes bounds the fixes applied to one command, in case rules undo each other's fixes
+const maxFixes = 10
+
+// Fix applies the safe fixes of the given rules to the command of the bookmark, one after the
+// other, and returns the fixed command with the findings that were fixed
+// The command is unchanged when nothing can be fixed.
+func Fix(bookmark dto.BookmarkResponse, rules []Rule) (string, []Finding) {
+	var fixed []Finding
+	for range maxFixes {
+		next, ok := firstFixable(Check(bookmark, rules))
+		if !ok || next.Fix == bookmark.Command {
+			break
+		}
+		fixed = append(fixed, next)
+		bookmark.Command = next.Fix
+	}
+	return bookmark.Command, fixed
+}
+
+// firstFixable returns the first finding that can be fixed safely
+func firstFixable(findings []Finding) (Finding, bool) {
+	for _, finding := range findings {
+		if finding.Fixable() {
+			return finding, true
+		}
+	}
+	return Finding{}, false
+}
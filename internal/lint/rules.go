@@ -0,0 +1,235 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/parse"
+	"github.com/fgeck/tools/internal/secrets"
+)
+
+// minDescriptionWords is the number of words from which a description says what a command does
+const minDescriptionWords = 2
+
+// placeholderDescriptions are descriptions left over from adding a bookmark in a hurry,
+// lower case without trailing punctuation
+var placeholderDescriptions = map[string]bool{
+	"": true, "-": true, "todo": true, "fixme": true, "tbd": true, "xxx": true, "test": true,
+	"n/a": true, "description": true, "no description": true,
+}
+
+var descriptionRule = Rule{
+	Name:        "description",
+	Description: "Descriptions say what the command does and are more than the tool name or the command",
+	Severity:    Warning,
+	Check: func(bookmark dto.BookmarkResponse) []Problem {
+		description := strings.TrimSpace(bookmark.Description)
+		switch {
+		case description == "":
+			return []Problem{{Message: "missing description"}}
+		case placeholderDescriptions[strings.ToLower(strings.TrimRight(description, ".!?"))]:
+			return []Problem{{Message: fmt.Sprintf("description '%s' is a placeholder, say what the command does", description)}}
+		case strings.EqualFold(description, strings.TrimSpace(bookmark.Command)):
+			return []Problem{{Message: "description repeats the command, say what it does"}}
+		case strings.EqualFold(description, bookmark.ToolName):
+			return []Problem{{Message: "description only names the tool, say what the command does"}}
+		case len(strings.Fields(description)) < minDescriptionWords:
+			return []Problem{{Message: fmt.Sprintf("description '%s' is a single word, say what the command does", description)}}
+		}
+		return nil
+	},
+}
+
+var credentialsRule = Rule{
+	Name:        "credentials",
+	Description: "Commands read passwords, tokens and keys from the environment instead of containing them",
+	Severity:    Error,
+	Check: func(bookmark dto.BookmarkResponse) []Problem {
+		problems := credentialProblems(bookmark.Command, "")
+		goos := make([]string, 0, len(bookmark.Variants))
+		for name := range bookmark.Variants {
+			goos = append(goos, name)
+		}
+		sort.Strings(goos)
+		for _, name := range goos {
+			problems = append(problems, credentialProblems(bookmark.Variants[name], fmt.Sprintf(" in the %s variant", name))...)
+		}
+		return problems
+	},
+}
+
+// credentialProblems returns a problem per hard-coded credential in the command; where says
+// which command of the bookmark it is
+func credentialProblems(command, where string) []Problem {
+	var problems []Problem
+	for _, secret := range secrets.Find(command) {
+		problems = append(problems, Problem{
+			Message: fmt.Sprintf("hard-coded %s %s%s, read it from an environment variable or use a placeholder", secret.Kind, secrets.Mask(secret.Value), where),
+		})
+	}
+	return problems
+}
+
+// homePath matches a path into a user's home directory; the second group is the home directory
+var homePath = regexp.MustCompile(`(^|[\s='":])((?:/home|/Users)/[^/\s'"$<{]+)`)
+
+// sharedHomes are directories under /home and /Users that exist on every machine using them
+var sharedHomes = map[string]bool{
+	"/home/linuxbrew": true, "/Users/Shared": true,
+}
+
+// homeDir returns the home directory of the current user; replaced in tests
+var homeDir = os.UserHomeDir
+
+var pathsRule = Rule{
+	Name:        "paths",
+	Description: "Paths into home directories use $HOME, so commands work on other machines and for other users",
+	Severity:    Warning,
+	Check: func(bookmark dto.BookmarkResponse) []Problem {
+		home, _ := homeDir()
+		var problems []Problem
+		for _, match := range homePath.FindAllStringSubmatchIndex(bookmark.Command, -1) {
+			start, end := match[4], match[5]
+			path := bookmark.Command[start:end]
+			if sharedHomes[path] {
+				continue
+			}
+			problem := Problem{Message: fmt.Sprintf("absolute path into %s only works on this machine, use $HOME", path)}
+			// $HOME means the same directory only for the current user, and only outside single quotes
+			if home != "" && path == filepath.Clean(home) && !inSingleQuotes(bookmark.Command, start) {
+				problem.Fix = bookmark.Command[:start] + "$HOME" + bookmark.Command[end:]
+			}
+			problems = append(problems, problem)
+		}
+		return problems
+	},
+}
+
+// inSingleQuotes reports whether the byte at index of the command is inside single quotes
+func inSingleQuotes(command string, index int) bool {
+	var quote rune
+	escaped := false
+	for i, r := range command {
+		if i >= index {
+			break
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		}
+	}
+	return quote == '\''
+}
+
+// deprecation is a tool or a flag of a tool that is deprecated or was removed
+type deprecation struct {
+	tool        string
+	subcommands []string // The flag is only deprecated for these subcommands, empty for all
+	flag        string   // Empty when the tool itself is deprecated
+	message     string
+
+	// fixable is set when replacing the tool by replacement, or removing the flag, keeps
+	// what the command does
+	fixable     bool
+	replacement string
+}
+
+// deprecations are the deprecated tools and flags the deprecated rule knows
+var deprecations = []deprecation{
+	{tool: "egrep", message: "egrep is deprecated, use grep -E", fixable: true, replacement: "grep -E"},
+	{tool: "fgrep", message: "fgrep is deprecated, use grep -F", fixable: true, replacement: "grep -F"},
+	{tool: "docker-compose", message: "docker-compose (Compose V1) is no longer maintained, use docker compose", fixable: true, replacement: "docker compose"},
+	{tool: "apt-key", message: "apt-key is deprecated, keep keys in /etc/apt/keyrings and reference them with signed-by"},
+	{tool: "kubectl", flag: "--show-all", message: "kubectl --show-all was removed, completed pods are always listed", fixable: true},
+	{tool: "kubectl", flag: "--export", message: "kubectl --export was removed in 1.18, strip the status with kubectl neat or yq"},
+	{tool: "kubectl", subcommands: []string{"run"}, flag: "--generator", message: "kubectl run --generator was removed, run only creates pods; use kubectl create for other resources"},
+	{tool: "helm", subcommands: []string{"delete", "uninstall"}, flag: "--purge", message: "helm --purge was removed, helm 3 always purges releases", fixable: true},
+	{tool: "npm", subcommands: []string{"install", "i", "add"}, flag: "--save", message: "npm install --save is the default since npm 5", fixable: true},
+	{tool: "docker", subcommands: []string{"run", "create"}, flag: "--link", message: "docker --link is a legacy feature, connect containers with a user-defined network"},
+}
+
+var deprecatedRule = Rule{
+	Name:        "deprecated",
+	Description: "Commands use no deprecated tools or flags, which newer versions may not support",
+	Severity:    Warning,
+	Check: func(bookmark dto.BookmarkResponse) []Problem {
+		segments := parse.Segments(bookmark.Command)
+		var problems []Problem
+		for _, segment := range segments {
+			executable := parse.Executable(segment)
+			if executable == "" {
+				continue
+			}
+			words := strings.Fields(segment)
+			for _, d := range deprecations {
+				if !d.matches(executable, words) {
+					continue
+				}
+				problem := Problem{Message: d.message}
+				// A fix applied to the whole command could hit another command of a pipeline
+				if d.fixable && len(segments) == 1 {
+					problem.Fix = d.fix(bookmark.Command, executable)
+				}
+				problems = append(problems, problem)
+			}
+		}
+		return problems
+	},
+}
+
+// matches reports whether a simple command, split into words, uses the deprecated tool or flag
+func (d deprecation) matches(executable string, words []string) bool {
+	if filepath.Base(executable) != d.tool {
+		return false
+	}
+	if d.flag == "" {
+		return true
+	}
+
+	operands := words[slices.IndexFunc(words, func(w string) bool { return strings.Trim(w, `"'`) == executable })+1:]
+	if len(d.subcommands) > 0 {
+		i := slices.IndexFunc(operands, func(w string) bool { return !strings.HasPrefix(w, "-") })
+		if i < 0 || !slices.Contains(d.subcommands, operands[i]) {
+			return false
+		}
+	}
+	return slices.ContainsFunc(operands, func(w string) bool {
+		return w == d.flag || strings.HasPrefix(w, d.flag+"=")
+	})
+}
+
+// fix returns the command with the deprecated tool replaced or the flag removed, or "" when
+// the command can't be rewritten safely
+func (d deprecation) fix(command, executable string) string {
+	if d.flag == "" {
+		// Only replace the tool as written, not a path to it
+		if executable != d.tool {
+			return ""
+		}
+		return replaceFirst(command, regexp.MustCompile(`(?:^|\s)(`+regexp.QuoteMeta(d.tool)+`)(?:\s|$)`), d.replacement)
+	}
+	return replaceFirst(command, regexp.MustCompile(`(\s+`+regexp.QuoteMeta(d.flag)+`(?:=\S*)?)(?:\s|$)`), "")
+}
+
+// replaceFirst replaces the first group of the first match of pattern in s
+func replaceFirst(s string, pattern *regexp.Regexp, replacement string) string {
+	match := pattern.FindStringSubmatchIndex(s)
+	if match == nil {
+		return ""
+	}
+	return s[:match[2]] + replacement + s[match[3]:]
+}
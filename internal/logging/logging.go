@@ -0,0 +1,122 @@
+// Package logging provides the structured log of services and repositories
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// Levels lists the accepted log level names, most verbose first
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel returns the slog level of a name of Levels
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level '%s', must be one of %s", name, strings.Join(Levels, ", "))
+	}
+}
+
+// Open returns a text handler writing records of at least level to the file at path, or to
+// stderr when path is empty. An empty level logs from info on; the returned closer closes
+// the file.
+func Open(level, path string) (slog.Handler, io.Closer, error) {
+	minLevel := slog.LevelInfo
+	if level != "" {
+		var err error
+		if minLevel, err = ParseLevel(level); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var out io.WriteCloser = nopCloser{os.Stderr}
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		out = file
+	}
+
+	return slog.NewTextHandler(out, &slog.HandlerOptions{Level: minLevel}), out, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// Switch is a handler forwarding records to a handler that can be replaced later, so
+// loggers handed to services before the command line was parsed follow its log flags.
+// It discards all records until Set is called.
+type Switch struct {
+	target *atomic.Pointer[slog.Handler]
+	derive []func(slog.Handler) slog.Handler // WithAttrs and WithGroup calls, applied to the target
+}
+
+// NewSwitch returns a handler discarding all records until Set is called
+func NewSwitch() *Switch {
+	s := &Switch{target: &atomic.Pointer[slog.Handler]{}}
+	s.Set(slog.DiscardHandler)
+	return s
+}
+
+// Set forwards all records, including those of loggers made before, to h
+func (s *Switch) Set(h slog.Handler) {
+	s.target.Store(&h)
+}
+
+func (s *Switch) current() slog.Handler {
+	h := *s.target.Load()
+	for _, fn := range s.derive {
+		h = fn(h)
+	}
+	return h
+}
+
+// Enabled implements slog.Handler
+func (s *Switch) Enabled(ctx context.Context, level slog.Level) bool {
+	return (*s.target.Load()).Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (s *Switch) Handle(ctx context.Context, r slog.Record) error {
+	return s.current().Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (s *Switch) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return s.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+// WithGroup implements slog.Handler
+func (s *Switch) WithGroup(name string) slog.Handler {
+	return s.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (s *Switch) with(fn func(slog.Handler) slog.Handler) *Switch {
+	derive := make([]func(slog.Handler) slog.Handler, len(s.derive), len(s.derive)+1)
+	copy(derive, s.derive)
+	return &Switch{target: s.target, derive: append(derive, fn)}
+}
+
+// Discard returns a logger dropping all records, the default of services and repositories
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,71 @@
+//go:build unit
+// +build unit
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "warning": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := ParseLevel(name); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	s := NewSwitch()
+	// Loggers made before Set follow it, including their attributes and groups
+	logger := slog.New(s).With("store", "tools.yaml").WithGroup("op")
+
+	logger.Error("dropped")
+
+	var buf bytes.Buffer
+	s.Set(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	logger.Debug("below the level")
+	logger.Error("save failed", "name", "create")
+
+	out := buf.String()
+	if strings.Contains(out, "dropped") || strings.Contains(out, "below the level") {
+		t.Errorf("Expected records before Set and below the level to be dropped, got: %s", out)
+	}
+	if !strings.Contains(out, `msg="save failed" store=tools.yaml op.name=create`) {
+		t.Errorf("Expected the record with its attributes, got: %s", out)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "tools.log")
+	handler, closer, err := Open("", path)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	logger := slog.New(handler)
+	logger.Debug("debug")
+	logger.Info("info")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Failed to close log: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if strings.Contains(string(data), "msg=debug") || !strings.Contains(string(data), "msg=info") {
+		t.Errorf("Expected only info records by default, got: %s", data)
+	}
+
+	if _, _, err := Open("loud", path); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fgeck/tools/internal/secrets"
+	"github.com/fgeck/tools/internal/service"
+)
+
+const (
+	serverName = "tools"
+
+	// latestProtocolVersion is answered when the client requests an unknown version
+	latestProtocolVersion = "2025-06-18"
+
+	// maxMessageSize limits a single JSON-RPC message, over stdio and HTTP alike
+	maxMessageSize = 10 * 1024 * 1024
+)
+
+// supportedProtocolVersions lists the MCP revisions this server can speak
+var supportedProtocolVersions = map[string]bool{
+	"2024-11-05":          true,
+	"2025-03-26":          true,
+	latestProtocolVersion: true,
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Server exposes the bookmark service as MCP tools over a JSON-RPC stream
+type Server struct {
+	service  service.BookmarkService
+	version  string
+	observe  RequestObserver
+	redactor *secrets.Redactor // Hides parts of the commands in results, nil for none
+	mu       sync.Mutex        // Serializes writes to the output stream
+}
+
+// RequestObserver is told about every request the server handled, e.g. to export metrics
+// tool is the tool of a tools/call request, empty for other methods; unknown methods and
+// tools are reported as "unknown". failed is set for protocol and tool errors alike.
+type RequestObserver func(method, tool string, duration time.Duration, failed bool)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewServer creates an MCP server backed by the given service
+func NewServer(svc service.BookmarkService, version string) *Server {
+	return &Server{
+		service: svc,
+		version: version,
+	}
+}
+
+// Observe calls fn after every handled request, including notifications
+func (s *Server) Observe(fn RequestObserver) {
+	s.observe = fn
+}
+
+// Redact hides what r hides in the commands of all results, as the CLI and TUI display them
+func (s *Server) Redact(r *secrets.Redactor) {
+	s.redactor = r
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes responses to w
+// It returns when r is exhausted or the context is cancelled
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if resp := s.handleMessage(ctx, line); resp != nil {
+			if err := s.write(w, resp); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read MCP input: %w", err)
+	}
+	return nil
+}
+
+// handleMessage processes a single message and returns the response, or nil for notifications
+func (s *Server) handleMessage(ctx context.Context, data []byte) *response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(idOrNull(req.ID), codeInvalidRequest, "invalid request")
+	}
+
+	// Notifications carry no id and never get a response
+	isNotification := len(req.ID) == 0
+
+	start := time.Now()
+	result, rpcErr := s.dispatch(ctx, req)
+	if s.observe != nil {
+		s.report(req, time.Since(start), result, rpcErr)
+	}
+	if isNotification {
+		return nil
+	}
+	if rpcErr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// report passes a handled request to the observer, with names limited to known ones
+func (s *Server) report(req request, duration time.Duration, result any, rpcErr *rpcError) {
+	method, tool := req.Method, ""
+	if rpcErr != nil && rpcErr.Code == codeMethodNotFound {
+		method = "unknown"
+	}
+	if method == "tools/call" {
+		var p struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		tool = "unknown"
+		for _, def := range toolDefinitions {
+			if def.Name == p.Name {
+				tool = p.Name
+			}
+		}
+	}
+	toolFailed := false
+	if r, ok := result.(toolResult); ok {
+		toolFailed = r.IsError
+	}
+	s.observe(method, tool, duration, rpcErr != nil || toolFailed)
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": toolDefinitions}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	}
+
+	return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid initialize params"}
+		}
+	}
+
+	version := latestProtocolVersion
+	if supportedProtocolVersions[p.ProtocolVersion] {
+		version = p.ProtocolVersion
+	}
+
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools": map[string]any{},
+		},
+		"serverInfo": map[string]any{
+			"name":    serverName,
+			"version": s.version,
+		},
+	}, nil
+}
+
+func (s *Server) write(w io.Writer, resp *response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal MCP response: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write MCP response: %w", err)
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -0,0 +1,493 @@
+//go:build unit
+// +build unit
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/secrets"
+	"github.com/fgeck/tools/internal/service"
+)
+
+func newTestServer(t *testing.T) (*Server, service.BookmarkService) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository())
+	return NewServer(svc, "test"), svc
+}
+
+// roundTrip sends the given messages and returns the decoded responses
+func roundTrip(t *testing.T, server *Server, messages ...string) []map[string]any {
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(messages, "\n") + "\n")
+	if err := server.Serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	var responses []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]any
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("Invalid JSON response %q: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestInitializeHandshake(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	responses := roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+
+	// The notification must not produce a response
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 response, got %d", len(responses))
+	}
+
+	result := responses[0]["result"].(map[string]any)
+	if result["protocolVersion"] != "2024-11-05" {
+		t.Errorf("Expected negotiated version 2024-11-05, got %v", result["protocolVersion"])
+	}
+	if _, ok := result["capabilities"].(map[string]any)["tools"]; !ok {
+		t.Error("Server should advertise the tools capability")
+	}
+}
+
+func TestInitializeUnknownVersion(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	responses := roundTrip(t, server, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+
+	result := responses[0]["result"].(map[string]any)
+	if result["protocolVersion"] != latestProtocolVersion {
+		t.Errorf("Expected fallback to %s, got %v", latestProtocolVersion, result["protocolVersion"])
+	}
+}
+
+func TestToolsList(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	responses := roundTrip(t, server, `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	names := map[string]bool{}
+	for _, tool := range tools {
+		names[tool.(map[string]any)["name"].(string)] = true
+	}
+	for _, name := range []string{"list_bookmarks", "search_bookmarks", "create_bookmark"} {
+		if !names[name] {
+			t.Errorf("Expected tool %s to be listed", name)
+		}
+	}
+}
+
+func TestToolsCallCreateAndSearch(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	responses := roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"lsof -i :8080","tool_name":"lsof","description":"who uses port 8080"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_bookmarks","arguments":{"query":"port"}}}`,
+	)
+
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(responses))
+	}
+
+	if _, err := svc.GetBookmark(context.Background(), "lsof -i :8080"); err != nil {
+		t.Errorf("Bookmark should have been created: %v", err)
+	}
+
+	result := responses[1]["result"].(map[string]any)
+	if result["isError"] != false {
+		t.Errorf("Search should succeed, got %v", result)
+	}
+	text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+
+	var list dto.ListBookmarksResponse
+	if err := json.Unmarshal([]byte(text), &list); err != nil {
+		t.Fatalf("Search result should be JSON: %v", err)
+	}
+	if list.Count != 1 || list.Examples[0].Command != "lsof -i :8080" {
+		t.Errorf("Unexpected search result: %+v", list)
+	}
+}
+
+func TestToolsCallHidesPrivateAndRedacts(t *testing.T) {
+	server, svc := newTestServer(t)
+	redactor, err := secrets.NewRedactor(false, []string{`--host=(\S+)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Redact(redactor)
+
+	ctx := context.Background()
+	for _, req := range []dto.CreateBookmarkRequest{
+		{Command: "psql --host=db.internal shop", ToolName: "psql", Description: "query shop"},
+		{Command: "psql --host=localhost scratch", ToolName: "psql", Description: "query scratch", Private: true},
+	} {
+		if _, err := svc.CreateBookmark(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	responses := roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_bookmarks","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_bookmarks","arguments":{"query":"psql"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"mysql --host=db.internal","tool_name":"mysql","description":"query"}}}`,
+	)
+	for _, resp := range responses {
+		text := resp["result"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"].(string)
+		if strings.Contains(text, "db.internal") || strings.Contains(text, "scratch") {
+			t.Errorf("Expected private bookmarks left out and hosts redacted, got %s", text)
+		}
+	}
+
+	text := responses[0]["result"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"].(string)
+	var list dto.ListBookmarksResponse
+	if err := json.Unmarshal([]byte(text), &list); err != nil {
+		t.Fatalf("List result should be JSON: %v", err)
+	}
+	if list.Count != 1 || list.Examples[0].Command != "psql --host=**** shop" {
+		t.Errorf("Unexpected list result: %+v", list)
+	}
+}
+
+func TestToolsCallServiceErrorIsToolError(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	responses := roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"","tool_name":"x","description":"y"}}}`,
+	)
+
+	result := responses[0]["result"].(map[string]any)
+	if result["isError"] != true {
+		t.Errorf("Validation failure should be reported as tool error, got %v", result)
+	}
+}
+
+func TestToolsCallCreateRefusesSecrets(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	// allow_secrets is no argument a client can pass
+	responses := roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"psql postgres://admin:s3cret@db/shop","tool_name":"psql","description":"query shop","AllowSecrets":true}}}`,
+	)
+
+	result := responses[0]["result"].(map[string]any)
+	if result["isError"] != true {
+		t.Errorf("A command with a password should be refused, got %v", result)
+	}
+	if list, _ := svc.ListBookmarks(context.Background()); list.Count != 0 {
+		t.Errorf("Expected nothing stored, got %+v", list.Examples)
+	}
+}
+
+func TestToolsCallCreateIgnoresFieldsOutsideSchema(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"uptime","tool_name":"uptime","description":"load","link":"https://example.com/uptime",`+
+			`"author":"someone else","host":"prod-db","variants":{"linux":"rm -rf ~"},"requires":["HOME"],"private":true}}}`,
+	)
+
+	bookmark, err := svc.GetBookmark(context.Background(), "uptime")
+	if err != nil {
+		t.Fatalf("Bookmark should have been created: %v", err)
+	}
+	if bookmark.Link != "https://example.com/uptime" {
+		t.Errorf("Expected the link to be stored, got %q", bookmark.Link)
+	}
+	if bookmark.Author != "" || bookmark.Host != "" || len(bookmark.Variants) != 0 || len(bookmark.Requires) != 0 || bookmark.Private {
+		t.Errorf("Expected only the schema fields to be stored, got %+v", bookmark)
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name    string
+		message string
+		code    float64
+	}{
+		{"parse error", `{not json`, codeParseError},
+		{"invalid request", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, codeInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, codeMethodNotFound},
+		{"unknown tool", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"rm_rf"}}`, codeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := roundTrip(t, server, tt.message)
+			if len(responses) != 1 {
+				t.Fatalf("Expected 1 response, got %d", len(responses))
+			}
+			rpcErr, ok := responses[0]["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected error response, got %v", responses[0])
+			}
+			if rpcErr["code"] != tt.code {
+				t.Errorf("Expected code %v, got %v", tt.code, rpcErr["code"])
+			}
+		})
+	}
+}
+
+func TestObserveRequests(t *testing.T) {
+	server, _ := newTestServer(t)
+	var observed []string
+	server.Observe(func(method, tool string, duration time.Duration, failed bool) {
+		observed = append(observed, fmt.Sprintf("%s %s %v", method, tool, failed))
+	})
+
+	roundTrip(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_bookmarks"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":""}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"rm_rf"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`,
+	)
+
+	expected := []string{
+		"ping  false",
+		"tools/call list_bookmarks false",
+		"tools/call create_bookmark true",
+		"tools/call unknown true",
+		"unknown  true",
+	}
+	if strings.Join(observed, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected observed requests:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(observed, "\n"))
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_bookmark","arguments":{"command":"lsof -i :8080","tool_name":"lsof","description":"check port"}}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+	if resp["id"] != float64(1) || resp["result"] == nil {
+		t.Errorf("Expected a result for id 1, got %v", resp)
+	}
+	if _, err := svc.GetBookmark(context.Background(), "lsof -i :8080"); err != nil {
+		t.Errorf("Expected the bookmark to be created: %v", err)
+	}
+
+	if rec := post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`); rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Errorf("Expected 202 without body for a notification, got %d: %q", rec.Code, rec.Body.String())
+	}
+
+	rec = post(`not json`)
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp["error"] == nil {
+		t.Errorf("Expected a parse error response, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("Expected 405 allowing POST for GET, got %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+
+	// Forms are the POSTs web pages can send to other origins without a preflight
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Expected 415 for Content-Type %q, got %d", contentType, rec.Code)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for JSON with a charset, got %d", rec.Code)
+	}
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	server, _ := newTestServer(t)
+	handler := RequireBearerToken("team-token", server)
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer other", http.StatusUnauthorized},
+		{"other scheme", "Basic team-token", http.StatusUnauthorized},
+		{"token", "Bearer team-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRequireLoopback(t *testing.T) {
+	server, _ := newTestServer(t)
+	handler := RequireLoopback(server)
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		want   int
+	}{
+		{"loopback IP", "127.0.0.1:8080", "", http.StatusOK},
+		{"localhost", "localhost:8080", "", http.StatusOK},
+		{"IPv6 loopback", "[::1]:8080", "", http.StatusOK},
+		{"local origin", "127.0.0.1:8080", "http://localhost:3000", http.StatusOK},
+		{"rebound DNS name", "attacker.example:8080", "", http.StatusForbidden},
+		{"other IP", "192.168.1.10:8080", "", http.StatusForbidden},
+		{"cross-site origin", "127.0.0.1:8080", "https://attacker.example", http.StatusForbidden},
+		{"opaque origin", "127.0.0.1:8080", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+			req.Host = tt.host
+			req.Header.Set("Content-Type", "application/json")
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestLogRequestsAndRecover(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	panics := 0
+	handler := LogRequests(logger, Recover(logger, func() { panics++ }, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusTeapot)
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rec.Code != http.StatusInternalServerError || panics != 1 {
+		t.Errorf("Expected 500 and one counted panic, got %d and %d", rec.Code, panics)
+	}
+	if !strings.Contains(logs.String(), `"msg":"panic serving request"`) || !strings.Contains(logs.String(), `"panic":"boom"`) {
+		t.Errorf("Expected the panic to be logged, got: %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), `"path":"/panic","status":500`) {
+		t.Errorf("Expected the request to be logged with status 500, got: %s", logs.String())
+	}
+
+	logs.Reset()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	if rec.Code != http.StatusTeapot || !strings.Contains(logs.String(), `"method":"POST","path":"/mcp","status":418`) {
+		t.Errorf("Expected the request to be logged with its status, got %d: %s", rec.Code, logs.String())
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limited := 0
+	handler := RateLimit(1, 2, func() { limited++ }, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	request := func(remote string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+			t.Error("Expected Retry-After on 429")
+		}
+		return rec.Code
+	}
+
+	// The burst passes, then the bucket is empty
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := request("192.0.2.1:5000"); got != want {
+			t.Errorf("Request %d: expected %d, got %d", i+1, want, got)
+		}
+	}
+	if limited != 1 {
+		t.Errorf("Expected one limited request to be counted, got %d", limited)
+	}
+	// Other clients have their own limit, other ports of the same one do not
+	if got := request("192.0.2.2:5000"); got != http.StatusOK {
+		t.Errorf("Expected another client to pass, got %d", got)
+	}
+	if got := request("192.0.2.1:6000"); got != http.StatusTooManyRequests {
+		t.Errorf("Expected the same address on another port to be limited, got %d", got)
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	now := time.Now()
+	limiter := &rateLimiter{rate: 2, burst: 1, now: func() time.Time { return now }, clients: map[string]*bucket{}}
+
+	if !limiter.allow("a") || limiter.allow("a") {
+		t.Fatal("Expected one request to pass and the next to be limited")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if !limiter.allow("a") {
+		t.Error("Expected the bucket to be refilled after half a second at 2 per second")
+	}
+
+	now = now.Add(time.Minute)
+	limiter.forgetIdle(now)
+	if len(limiter.clients) != 0 {
+		t.Errorf("Expected idle clients to be forgotten, got %d", len(limiter.clients))
+	}
+}
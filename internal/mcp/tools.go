@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+// toolDefinition describes an MCP tool and the JSON schema of its arguments
+type toolDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+var toolDefinitions = []toolDefinition{
+	{
+		Name:        "list_bookmarks",
+		Description: "List all of the user's bookmarked shell commands with their tool name and description, except private ones.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+	},
+	{
+		Name:        "search_bookmarks",
+		Description: "Search the user's bookmarked shell commands, except private ones. All whitespace-separated terms must match the command, tool name or description (case-insensitive).",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Search terms, e.g. \"kubectl pods\"",
+				},
+			},
+			"required": []string{"query"},
+		},
+	},
+	{
+		Name:        "create_bookmark",
+		Description: "Bookmark a new shell command for the user.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"command": map[string]any{
+					"type":        "string",
+					"description": "The exact command, e.g. \"lsof -i :8080\"",
+				},
+				"tool_name": map[string]any{
+					"type":        "string",
+					"description": "Tool name used for grouping, usually the program name, e.g. \"lsof\"",
+				},
+				"description": map[string]any{
+					"type":        "string",
+					"description": "What the command does",
+				},
+				"link": map[string]any{
+					"type":        "string",
+					"description": "Optional http(s) URL of documentation, a runbook or a ticket",
+				},
+			},
+			"required": []string{"command", "tool_name", "description"},
+		},
+	},
+}
+
+// toolResult is the result of a tools/call request
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.Name == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params"}
+	}
+	if len(p.Arguments) == 0 {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	var (
+		result any
+		err    error
+	)
+	switch p.Name {
+	case "list_bookmarks":
+		var list *dto.ListBookmarksResponse
+		list, err = s.service.ListBookmarks(ctx)
+		result = s.shared(list)
+
+	case "search_bookmarks":
+		var args struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(p.Arguments, &args); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid search_bookmarks arguments"}
+		}
+		var list *dto.ListBookmarksResponse
+		list, err = s.service.SearchBookmarks(ctx, args.Query)
+		result = s.shared(list)
+
+	case "create_bookmark":
+		// Only the fields of the input schema; author, host, variants and the like are not
+		// for clients to set
+		var args struct {
+			Command     string `json:"command"`
+			ToolName    string `json:"tool_name"`
+			Description string `json:"description"`
+			Link        string `json:"link"`
+		}
+		if err := json.Unmarshal(p.Arguments, &args); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid create_bookmark arguments"}
+		}
+		var created *dto.BookmarkResponse
+		if created, err = s.service.CreateBookmark(ctx, dto.CreateBookmarkRequest{
+			Command:     args.Command,
+			ToolName:    args.ToolName,
+			Description: args.Description,
+			Link:        args.Link,
+		}); err == nil {
+			result = s.redactor.Bookmark(*created)
+		}
+
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+	}
+
+	// Service failures are reported as tool errors so the model can see and react to them
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: string(data)}}}, nil
+}
+
+// shared returns the bookmarks of list a client may see: private ones are left out, like in
+// exports and bundles, and the commands are redacted
+func (s *Server) shared(list *dto.ListBookmarksResponse) *dto.ListBookmarksResponse {
+	if list == nil {
+		return nil
+	}
+	examples := s.redactor.Bookmarks(dto.Shared(list.Examples))
+	return &dto.ListBookmarksResponse{Examples: examples, Count: len(examples), NextCursor: list.NextCursor}
+}
@@ -0,0 +1,110 @@
+// Package parse extracts structure from shell command lines
+package parse
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// assignment matches a leading VAR=value environment assignment
+var assignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// wrappers run the command that follows them
+var wrappers = map[string]bool{
+	"sudo": true, "doas": true, "env": true, "nohup": true, "time": true, "exec": true,
+	"command": true, "nice": true, "watch": true,
+}
+
+// wrapperArgFlags are wrapper flags taking a separate value, e.g. sudo -u root or nice -n 10
+var wrapperArgFlags = map[string]bool{
+	"-u": true, "-g": true, "-n": true, "-C": true,
+}
+
+// builtins are shell keywords and builtins that never resolve to a binary
+var builtins = map[string]bool{
+	"cd": true, "echo": true, "export": true, "source": true, ".": true, "alias": true, "unset": true,
+	"set": true, "eval": true, "read": true, "type": true, "ulimit": true, "umask": true, "wait": true,
+	"for": true, "while": true, "until": true, "if": true, "case": true, "function": true, "{": true, "(": true,
+	"printf": true, "test": true, "[": true, "[[": true, "history": true, "jobs": true, "fg": true, "bg": true,
+}
+
+// Segments splits a command line into the simple commands of its pipelines and lists
+// It splits at unquoted |, &, ; and newlines, so && and || separate segments too.
+// Empty segments are dropped.
+func Segments(command string) []string {
+	segments := []string{}
+	var current strings.Builder
+	var quote rune
+	escaped := false
+
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			segments = append(segments, s)
+		}
+		current.Reset()
+	}
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '|' || r == '&' || r == ';' || r == '\n':
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+
+	return segments
+}
+
+// Executable returns the executable a simple command invokes, as written
+// Leading variable assignments and wrappers like sudo are skipped. It returns "" when the
+// command has no resolvable executable, e.g. a shell builtin or a $VARIABLE.
+func Executable(segment string) string {
+	inWrapper := false
+	skipValue := false
+	for _, field := range strings.Fields(segment) {
+		field = strings.Trim(field, `"'`)
+		switch {
+		case field == "":
+			continue
+		case skipValue:
+			skipValue = false
+		case inWrapper && strings.HasPrefix(field, "-"):
+			skipValue = wrapperArgFlags[field]
+		case assignment.MatchString(field):
+			continue
+		case wrappers[field]:
+			inWrapper = true
+		case builtins[field], strings.HasPrefix(field, "$"):
+			return ""
+		default:
+			return field
+		}
+	}
+	return ""
+}
+
+// ToolName infers the tool name of a command line
+// It is the base name of the first executable found in the command's segments, so
+// 'sudo /usr/bin/lsof -i :80' is lsof and 'cd /tmp && make' is make. It returns "" when
+// no segment invokes an executable.
+func ToolName(command string) string {
+	for _, segment := range Segments(command) {
+		if executable := Executable(segment); executable != "" {
+			return filepath.Base(executable)
+		}
+	}
+	return ""
+}
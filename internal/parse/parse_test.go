@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected []string
+	}{
+		{"ls -la", []string{"ls -la"}},
+		{"ps aux | grep node", []string{"ps aux", "grep node"}},
+		{"cd /tmp && make || echo failed", []string{"cd /tmp", "make", "echo failed"}},
+		{"sleep 5 &\nwait", []string{"sleep 5", "wait"}},
+		{`grep "a|b" file; echo 'x && y'`, []string{`grep "a|b" file`, `echo 'x && y'`}},
+		{`echo a\|b`, []string{`echo a\|b`}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := Segments(tt.command); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Segments(%q) = %q, want %q", tt.command, got, tt.expected)
+		}
+	}
+}
+
+func TestExecutable(t *testing.T) {
+	tests := []struct {
+		segment  string
+		expected string
+	}{
+		{"lsof -i :8080", "lsof"},
+		{"sudo -u postgres psql", "psql"},
+		{"env FOO=bar nice -n 10 make build", "make"},
+		{"/usr/local/bin/terraform plan", "/usr/local/bin/terraform"},
+		{"cd /tmp", ""},
+		{"$EDITOR ~/.bashrc", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Executable(tt.segment); got != tt.expected {
+			t.Errorf("Executable(%q) = %q, want %q", tt.segment, got, tt.expected)
+		}
+	}
+}
+
+func TestToolName(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"kubectl get pods -A", "kubectl"},
+		{"sudo lsof -i :8080", "lsof"},
+		{"doas -u www nginx -s reload", "nginx"},
+		{"KUBECONFIG=~/.kube/dev kubectl get pods", "kubectl"},
+		{"AWS_PROFILE=prod aws s3 ls", "aws"},
+		{"/usr/local/bin/terraform plan", "terraform"},
+		{"./scripts/deploy.sh staging", "deploy.sh"},
+		{"cd /tmp && make clean", "make"},
+		{"export FOO=1; docker compose up", "docker"},
+		{"watch -n 5 kubectl get pods", "kubectl"},
+		{"ps aux | grep node", "ps"},
+		{"$EDITOR ~/.bashrc", ""},
+		{"echo done", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ToolName(tt.command); got != tt.expected {
+			t.Errorf("ToolName(%q) = %q, want %q", tt.command, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package placeholder fills dynamic placeholders whose values come from a provider command
+// A dynamic placeholder like {pod:kubectl get pods -o name} is replaced by one line of the
+// output of its provider command, chosen when the bookmark is selected.
+package placeholder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/runner"
+)
+
+// maxValues bounds the number of values offered for a placeholder
+const maxValues = 1000
+
+// pattern matches {name:provider} with the provider right after the colon, so that
+// e.g. jq objects like {name: .x} are left alone; ${VAR:-default} is excluded by Find
+var pattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_-]*):([^\s{}][^{}\n]*)\}`)
+
+// Dynamic is a dynamic placeholder of a command
+type Dynamic struct {
+	Name     string // e.g. "pod"
+	Provider string // Command printing one value per line, e.g. "kubectl get pods -o name"
+	Text     string // The placeholder as written in the command
+}
+
+// Find returns the dynamic placeholders of a command in order of appearance
+// A placeholder written several times is returned once; shell parameter expansions
+// such as ${VAR:-default} are not placeholders.
+func Find(command string) []Dynamic {
+	var found []Dynamic
+	for _, loc := range pattern.FindAllStringSubmatchIndex(command, -1) {
+		if loc[0] > 0 && command[loc[0]-1] == '$' {
+			continue
+		}
+		d := Dynamic{
+			Name:     command[loc[2]:loc[3]],
+			Provider: strings.TrimSpace(command[loc[4]:loc[5]]),
+			Text:     command[loc[0]:loc[1]],
+		}
+		if !slices.ContainsFunc(found, func(f Dynamic) bool { return f.Text == d.Text }) {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// Fill replaces every occurrence of a placeholder with a value
+func Fill(command string, d Dynamic, value string) string {
+	return strings.ReplaceAll(command, d.Text, value)
+}
+
+// Runner runs provider commands
+type Runner struct {
+	Timeout time.Duration // Maximum run time of a provider, 0 for no limit
+	Trusted []string      // Providers that may run without asking the user first
+}
+
+// IsTrusted reports whether a provider may run without confirmation
+func (r Runner) IsTrusted(provider string) bool {
+	return slices.Contains(r.Trusted, strings.TrimSpace(provider))
+}
+
+// Values runs a provider and returns the non-empty lines of its output
+// The provider gets no input and its output is not shown; it is stopped after the timeout.
+func (r Runner) Values(ctx context.Context, provider string) ([]string, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := runner.Run(ctx, provider, nil, &stdout, &stderr); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("'%s' did not finish within %s", provider, r.Timeout)
+		}
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return nil, fmt.Errorf("'%s' failed: %s", provider, msg)
+		}
+		return nil, fmt.Errorf("'%s' failed: %w", provider, err)
+	}
+
+	var values []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+		if len(values) == maxValues {
+			break
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,76 @@
+//go:build unit
+// +build unit
+
+package placeholder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []Dynamic
+	}{
+		{"kubectl logs -f {pod:kubectl get pods -o name}", []Dynamic{{Name: "pod", Provider: "kubectl get pods -o name", Text: "{pod:kubectl get pods -o name}"}}},
+		{"kubectl -n {ns:kubectl get ns -o name} delete {pod:kubectl get pods -o name}", []Dynamic{
+			{Name: "ns", Provider: "kubectl get ns -o name", Text: "{ns:kubectl get ns -o name}"},
+			{Name: "pod", Provider: "kubectl get pods -o name", Text: "{pod:kubectl get pods -o name}"},
+		}},
+		{"echo {b:git branch} && git checkout {b:git branch}", []Dynamic{{Name: "b", Provider: "git branch", Text: "{b:git branch}"}}},
+		{"echo ${NS:-default}", nil},
+		{"jq '{name: .metadata.name}'", nil},
+		{"awk '{print $1}'", nil},
+		{"echo {a,b}", nil},
+	}
+	for _, tt := range tests {
+		if got := Find(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Find(%q) = %+v, want %+v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	command := "git diff {b:git branch}..main && git log {b:git branch}"
+	got := Fill(command, Find(command)[0], "feature")
+	if got != "git diff feature..main && git log feature" {
+		t.Errorf("Expected every occurrence to be filled, got %q", got)
+	}
+}
+
+func TestRunnerValues(t *testing.T) {
+	r := Runner{Timeout: 5 * time.Second}
+
+	values, err := r.Values(context.Background(), "printf 'pod/a\\n\\n  pod/b  \\n'")
+	if err != nil {
+		t.Fatalf("Failed to run provider: %v", err)
+	}
+	if !reflect.DeepEqual(values, []string{"pod/a", "pod/b"}) {
+		t.Errorf("Expected the non-empty lines, got %q", values)
+	}
+
+	_, err = r.Values(context.Background(), "echo 'no cluster' >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "no cluster") {
+		t.Errorf("Expected the provider's error message, got %v", err)
+	}
+
+	r.Timeout = 50 * time.Millisecond
+	_, err = r.Values(context.Background(), "sleep 5")
+	if err == nil || !strings.Contains(err.Error(), "did not finish within 50ms") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+}
+
+func TestRunnerIsTrusted(t *testing.T) {
+	r := Runner{Trusted: []string{"kubectl get pods -o name"}}
+	if !r.IsTrusted(" kubectl get pods -o name ") {
+		t.Error("Expected a listed provider to be trusted")
+	}
+	if r.IsTrusted("kubectl delete pods --all") {
+		t.Error("Expected an unlisted provider not to be trusted")
+	}
+}
@@ -0,0 +1,288 @@
+// Package recipes reads the recipes of task runner files such as Justfiles and Makefiles
+package recipes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/utils"
+)
+
+// Source is a kind of task runner file
+type Source string
+
+const (
+	SourceJustfile Source = "justfile"
+	SourceMakefile Source = "makefile"
+)
+
+// Sources lists the supported task runner files
+func Sources() []Source {
+	return []Source{SourceJustfile, SourceMakefile}
+}
+
+// Recipe is a recipe of a Justfile or a target of a Makefile
+type Recipe struct {
+	Name        string
+	Params      []string // Required parameters, in order
+	Description string   // From the comment above the recipe, empty if there is none
+	Command     string   // Runs the recipe from any directory, set by Read
+}
+
+var (
+	// justRecipe matches a recipe header like "build target='x':" but no ":=" assignment
+	justRecipe = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)((?:\s+[^:]*)?)\s*:($|[^=].*$)`)
+	// justDoc matches a [doc("...")] attribute
+	justDoc = regexp.MustCompile(`doc\(\s*(?:"([^"]*)"|'([^']*)')\s*\)`)
+	// makeTargets matches a rule header like "build test: deps ## description"
+	// but no variable assignment, since target names cannot contain "="
+	makeTargets = regexp.MustCompile(`^([^:#=\t][^:#=]*?)\s*::?($|[^=].*$)`)
+)
+
+// Read parses a task runner file and sets each recipe's Command to an invocation that
+// works from any directory, e.g. "just --justfile /src/app/justfile build"
+func Read(source Source, path string) ([]Recipe, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Recipe
+	var prefix string
+	switch source {
+	case SourceJustfile:
+		found = ParseJustfile(string(data))
+		prefix = "just --justfile " + utils.ShellQuote(abs)
+	case SourceMakefile:
+		found = ParseMakefile(string(data))
+		prefix = "make -C " + utils.ShellQuote(filepath.Dir(abs))
+		if filepath.Base(abs) != "Makefile" {
+			prefix += " -f " + utils.ShellQuote(filepath.Base(abs))
+		}
+	default:
+		return nil, fmt.Errorf("unknown recipe source '%s'", source)
+	}
+
+	for i, r := range found {
+		args := []string{prefix, r.Name}
+		for _, p := range r.Params {
+			args = append(args, "<"+p+">")
+		}
+		found[i].Command = strings.Join(args, " ")
+	}
+	return found, nil
+}
+
+// ParseJustfile returns the public recipes of a Justfile
+// Private recipes (a leading underscore or the [private] attribute) are skipped. The
+// description is taken from a [doc] attribute or the comment line above the recipe.
+// Parameters with a default value and optional variadic ones (*args) are left out.
+func ParseJustfile(text string) []Recipe {
+	var recipes []Recipe
+	var comment, doc string
+	private := false
+	reset := func() { comment, doc, private = "", "", false }
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case line == "":
+			reset()
+		case line[0] == ' ' || line[0] == '\t':
+			// Recipe body
+		case strings.HasPrefix(line, "#"):
+			comment = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		case strings.HasPrefix(line, "["):
+			if m := justDoc.FindStringSubmatch(line); m != nil {
+				doc = m[1] + m[2]
+			}
+			if slices.Contains(attributeNames(line), "private") {
+				private = true
+			}
+		default:
+			m := justRecipe.FindStringSubmatch(line)
+			if m == nil || isJustKeyword(m[1], m[2]) {
+				reset()
+				continue
+			}
+			if !private && !strings.HasPrefix(m[1], "_") {
+				description := doc
+				if description == "" {
+					description = comment
+				}
+				recipes = append(recipes, Recipe{Name: m[1], Params: justParams(m[2]), Description: description})
+			}
+			reset()
+		}
+	}
+	return recipes
+}
+
+// ParseMakefile returns the targets of a Makefile in order of appearance
+// Special targets (.PHONY), pattern rules and file targets are skipped, unless a file
+// target is declared phony. The description is taken from a trailing "## text" or the
+// comment line above the rule.
+func ParseMakefile(text string) []Recipe {
+	var recipes []Recipe
+	phony := map[string]bool{}
+	var comment string
+	inDefine := false
+
+	for _, line := range joinContinuations(text) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inDefine:
+			inDefine = trimmed != "endef"
+			continue
+		case strings.HasPrefix(trimmed, "define ") || trimmed == "define":
+			inDefine = true
+			continue
+		case trimmed == "":
+			comment = ""
+			continue
+		case strings.HasPrefix(line, "\t"):
+			// Recipe body
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+
+		m := makeTargets.FindStringSubmatch(line)
+		if m == nil {
+			comment = ""
+			continue
+		}
+
+		names := strings.Fields(m[1])
+		if slices.Contains(names, ".PHONY") {
+			for _, name := range strings.Fields(m[2]) {
+				phony[name] = true
+			}
+			comment = ""
+			continue
+		}
+
+		description := comment
+		if _, trailing, ok := strings.Cut(m[2], "##"); ok {
+			description = strings.TrimSpace(trailing)
+		}
+		for _, name := range names {
+			recipes = append(recipes, Recipe{Name: name, Description: description})
+		}
+		comment = ""
+	}
+
+	// Keep the first rule of each target, filling its description from later ones
+	var unique []Recipe
+	index := map[string]int{}
+	for _, r := range recipes {
+		if strings.HasPrefix(r.Name, ".") || strings.ContainsAny(r.Name, "%$/") {
+			continue
+		}
+		if strings.Contains(r.Name, ".") && !phony[r.Name] {
+			continue
+		}
+		if i, ok := index[r.Name]; ok {
+			if unique[i].Description == "" {
+				unique[i].Description = r.Description
+			}
+			continue
+		}
+		index[r.Name] = len(unique)
+		unique = append(unique, r)
+	}
+	return unique
+}
+
+// attributeNames returns the names of the attributes in a line like "[private, no-cd]"
+func attributeNames(line string) []string {
+	inner := strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+	var names []string
+	for _, attr := range strings.Split(inner, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(attr), "(")
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// isJustKeyword reports whether a matched header is a setting, alias or module line
+func isJustKeyword(name, rest string) bool {
+	switch name {
+	case "set", "alias", "export", "import", "mod":
+		return strings.TrimSpace(rest) != ""
+	}
+	return false
+}
+
+// justParams returns the required parameters of a recipe's parameter list
+func justParams(list string) []string {
+	var params []string
+	for _, field := range splitParams(list) {
+		field = strings.TrimPrefix(field, "$")
+		if strings.HasPrefix(field, "*") || strings.Contains(field, "=") {
+			continue
+		}
+		params = append(params, strings.TrimPrefix(strings.TrimPrefix(field, "+"), "$"))
+	}
+	return params
+}
+
+// splitParams splits a parameter list at whitespace outside of quotes and parentheses
+func splitParams(list string) []string {
+	var fields []string
+	var current strings.Builder
+	var quote rune
+	depth := 0
+	for _, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case (r == ' ' || r == '\t') && depth == 0:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// joinContinuations splits a Makefile into logical lines, joining lines ending in a backslash
+func joinContinuations(text string) []string {
+	var lines []string
+	var current strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(line)
+		lines = append(lines, current.String())
+		current.Reset()
+	}
+	return lines
+}
@@ -0,0 +1,145 @@
+//go:build unit
+// +build unit
+
+package recipes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const justfile = `set shell := ["bash", "-c"]
+alias b := build
+
+version := "1.0"
+
+# Build the binary
+build target='release':
+    go build ./...
+
+# Deploy to an environment
+deploy env +services:
+    ./deploy.sh {{env}} {{services}}
+
+[private]
+helper:
+    echo hi
+
+_hidden:
+    echo
+
+# ignored, the doc attribute wins
+[doc("Run the tests")]
+[group('ci')]
+test *args: build
+    # not a description
+    go test {{args}}
+
+clean:
+    rm -rf dist
+`
+
+const makefile = `.PHONY: build test lint release.notes
+.DEFAULT_GOAL := help
+VERSION ?= 1.0
+LDFLAGS := -X main.version=$(VERSION) \
+	-s -w
+
+build: ## Build the binary
+	go build ./...
+
+# Run the unit tests
+test: build
+	go test ./...
+
+lint vet:
+	golangci-lint run
+
+release.notes:
+	./notes.sh
+
+bin/app: main.go
+	go build -o $@
+
+%.o: %.c
+	cc -c $<
+
+define HELP
+target: nope
+endef
+
+test: lint ## Test after linting
+`
+
+func TestParseJustfile(t *testing.T) {
+	got := ParseJustfile(justfile)
+	want := []Recipe{
+		{Name: "build", Description: "Build the binary"},
+		{Name: "deploy", Params: []string{"env", "services"}, Description: "Deploy to an environment"},
+		{Name: "test", Description: "Run the tests"},
+		{Name: "clean"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseMakefile(t *testing.T) {
+	got := ParseMakefile(makefile)
+	want := []Recipe{
+		{Name: "build", Description: "Build the binary"},
+		{Name: "test", Description: "Run the unit tests"},
+		{Name: "lint"},
+		{Name: "vet"},
+		{Name: "release.notes"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my app")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"justfile": justfile, "Makefile": makefile, "build.mk": "build:\n\tgo build\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	quoted := func(path string) string { return "'" + path + "'" }
+
+	tests := []struct {
+		source Source
+		file   string
+		want   string
+	}{
+		{SourceJustfile, "justfile", "just --justfile " + quoted(filepath.Join(dir, "justfile")) + " deploy <env> <services>"},
+		{SourceMakefile, "Makefile", "make -C " + quoted(dir) + " build"},
+		{SourceMakefile, "build.mk", "make -C " + quoted(dir) + " -f build.mk build"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			found, err := Read(tt.source, filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", tt.file, err)
+			}
+			for _, r := range found {
+				if r.Command == tt.want {
+					return
+				}
+			}
+			t.Errorf("Expected command %q, got %+v", tt.want, found)
+		})
+	}
+
+	if _, err := Read(SourceMakefile, filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+	if _, err := Read(Source("gradle"), filepath.Join(dir, "Makefile")); err == nil {
+		t.Error("Expected error for an unknown source")
+	}
+}
@@ -0,0 +1,123 @@
+package audited
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// AuditedBookmarkRepository records every successful write of another repository in an
+// audit log, with the bookmark before and after the change
+// Unlike the history the service records, it sees every write reaching the store, e.g. those
+// of imports, migrations and deletes of whole tools, one event per bookmark.
+type AuditedBookmarkRepository struct {
+	repository.BookmarkRepository
+	log audit.Log
+	now func() time.Time
+}
+
+// NewAuditedBookmarkRepository wraps repo, appending its writes to log
+func NewAuditedBookmarkRepository(repo repository.BookmarkRepository, log audit.Log) repository.BookmarkRepository {
+	return &AuditedBookmarkRepository{
+		BookmarkRepository: repo,
+		log:                log,
+		now:                time.Now,
+	}
+}
+
+// Unwrap returns the wrapped repository
+func (r *AuditedBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.BookmarkRepository
+}
+
+// record appends the events of a write that already succeeded
+func (r *AuditedBookmarkRepository) record(events ...audit.Event) error {
+	for _, event := range events {
+		event.Time = r.now()
+		if err := r.log.Append(event); err != nil {
+			return fmt.Errorf("store was changed, but failed to record audit event: %w", err)
+		}
+	}
+	return nil
+}
+
+// Batch runs fn with the staging repository of the wrapped one and records its writes once
+// they all applied
+func (r *AuditedBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	pending := &buffer{}
+	err := repository.Batch(ctx, r.BookmarkRepository, func(tx repository.BookmarkRepository) error {
+		return fn(&AuditedBookmarkRepository{BookmarkRepository: tx, log: pending, now: r.now})
+	})
+	if err != nil {
+		return err
+	}
+	return r.record(pending.events...)
+}
+
+// buffer is an audit log holding the events of a batch until it applied
+type buffer struct {
+	events []audit.Event
+}
+
+// Append adds an event to the buffer
+func (b *buffer) Append(event audit.Event) error {
+	b.events = append(b.events, event)
+	return nil
+}
+
+// Events returns the buffered events
+func (b *buffer) Events() ([]audit.Event, error) {
+	return b.events, nil
+}
+
+// Create adds a new example to storage
+func (r *AuditedBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	if err := r.BookmarkRepository.Create(ctx, example); err != nil {
+		return err
+	}
+	return r.record(audit.Event{Action: audit.ActionCreate, Command: example.Command, After: example.Clone()})
+}
+
+// Update modifies an existing example
+func (r *AuditedBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	before, err := r.BookmarkRepository.GetByCommand(ctx, example.Command)
+	if err != nil {
+		return err
+	}
+	if err := r.BookmarkRepository.Update(ctx, example); err != nil {
+		return err
+	}
+	return r.record(audit.Event{Action: audit.ActionEdit, Command: example.Command, Before: before, After: example.Clone()})
+}
+
+// Delete removes an example by command
+func (r *AuditedBookmarkRepository) Delete(ctx context.Context, command string) error {
+	before, err := r.BookmarkRepository.GetByCommand(ctx, command)
+	if err != nil {
+		return err
+	}
+	if err := r.BookmarkRepository.Delete(ctx, command); err != nil {
+		return err
+	}
+	return r.record(audit.Event{Action: audit.ActionDelete, Command: command, Before: before})
+}
+
+// DeleteByToolName removes all examples for a tool name, recording one event per example
+func (r *AuditedBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	before, err := r.BookmarkRepository.ListByToolName(ctx, toolName)
+	if err != nil {
+		return err
+	}
+	if err := r.BookmarkRepository.DeleteByToolName(ctx, toolName); err != nil {
+		return err
+	}
+	events := make([]audit.Event, len(before))
+	for i, example := range before {
+		events[i] = audit.Event{Action: audit.ActionDelete, Command: example.Command, Before: example}
+	}
+	return r.record(events...)
+}
@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package audited
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+func TestAuditedRepository(t *testing.T) {
+	ctx := context.Background()
+	log := audit.NewFileLog(filepath.Join(t.TempDir(), "store-audit.jsonl"))
+	repo := NewAuditedBookmarkRepository(memory.NewMemoryBookmarkRepository(), log)
+
+	repo.Create(ctx, &models.Bookmark{Command: "git status", ToolName: "git", Description: "status"})
+	repo.Create(ctx, &models.Bookmark{Command: "git log", ToolName: "git", Description: "log"})
+	repo.Update(ctx, &models.Bookmark{Command: "git log", ToolName: "git", Description: "history"})
+	if err := repo.Create(ctx, &models.Bookmark{Command: "git log"}); err == nil {
+		t.Fatal("Expected a duplicate to fail")
+	}
+	if err := repo.DeleteByToolName(ctx, "git"); err != nil {
+		t.Fatalf("Failed to delete tool: %v", err)
+	}
+
+	events, err := log.Events()
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	expected := []audit.Action{audit.ActionCreate, audit.ActionCreate, audit.ActionEdit, audit.ActionDelete, audit.ActionDelete}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events for the successful writes, got %+v", len(expected), events)
+	}
+	for i, action := range expected {
+		if events[i].Action != action || events[i].Time.IsZero() {
+			t.Errorf("Expected %s at %d, got %+v", action, i, events[i])
+		}
+	}
+	if edit := events[2]; edit.Before.Description != "log" || edit.After.Description != "history" {
+		t.Errorf("Expected the edit with both versions, got %+v", edit)
+	}
+	if events[3].Before == nil || events[4].Command != "git log" {
+		t.Errorf("Expected one delete per bookmark of the tool, got %+v", events[3:])
+	}
+}
+
+func TestAuditedBatch(t *testing.T) {
+	ctx := context.Background()
+	log := audit.NewFileLog(filepath.Join(t.TempDir(), "store-audit.jsonl"))
+	repo := NewAuditedBookmarkRepository(memory.NewMemoryBookmarkRepository(), log)
+
+	create := func(fail bool) error {
+		return repository.Batch(ctx, repo, func(tx repository.BookmarkRepository) error {
+			tx.Create(ctx, &models.Bookmark{Command: "git status", ToolName: "git"})
+			tx.Create(ctx, &models.Bookmark{Command: "git log", ToolName: "git"})
+			if fail {
+				return errors.New("canceled")
+			}
+			return nil
+		})
+	}
+
+	if err := create(true); err == nil {
+		t.Fatal("Expected the batch to fail")
+	}
+	if events, _ := log.Events(); len(events) != 0 {
+		t.Errorf("Expected no events for a failed batch, got %+v", events)
+	}
+	if err := create(false); err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if events, _ := log.Events(); len(events) != 2 || events[1].Command != "git log" {
+		t.Errorf("Expected the writes of the batch recorded, got %+v", events)
+	}
+}
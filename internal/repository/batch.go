@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// Batcher is implemented by repositories that apply several writes at once, e.g. a file
+// store that saves the file once for all of them
+type Batcher interface {
+	// Batch runs fn with a repository staging its writes: they are applied together when fn
+	// returns nil, and discarded when it returns an error. Reads of the staging repository
+	// see the staged writes.
+	Batch(ctx context.Context, fn func(tx BookmarkRepository) error) error
+}
+
+// Batch runs fn with a repository whose writes either all apply, when fn returns nil, or
+// leave repo untouched, when fn or applying them fails
+// Decorators implement Batcher by wrapping the staging repository of the one they wrap, so
+// caching, logging and auditing still see the writes. A repository that is no Batcher gets
+// the writes of fn directly, which are undone when it fails; a crash in between leaves them
+// half applied.
+func Batch(ctx context.Context, repo BookmarkRepository, fn func(tx BookmarkRepository) error) error {
+	if batcher, ok := repo.(Batcher); ok {
+		return batcher.Batch(ctx, fn)
+	}
+
+	before, err := repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(repo); err != nil {
+		if undoErr := restore(ctx, repo, before); undoErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to undo the changes: %w", undoErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// restore brings repo back to the bookmarks it listed before
+func restore(ctx context.Context, repo BookmarkRepository, before []*models.Bookmark) error {
+	after, err := repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]*models.Bookmark, len(before))
+	for _, bookmark := range before {
+		previous[bookmark.Command] = bookmark
+	}
+	for _, bookmark := range after {
+		old, ok := previous[bookmark.Command]
+		switch {
+		case !ok:
+			err = repo.Delete(ctx, bookmark.Command)
+		case !reflect.DeepEqual(old, bookmark):
+			err = repo.Update(ctx, old)
+		}
+		if err != nil {
+			return err
+		}
+		delete(previous, bookmark.Command)
+	}
+	for _, bookmark := range before {
+		if _, removed := previous[bookmark.Command]; removed {
+			if err := repo.Create(ctx, bookmark); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
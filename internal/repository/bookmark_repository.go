@@ -19,6 +19,13 @@ type BookmarkRepository interface {
 	// List retrieves all examples
 	List(ctx context.Context) ([]*models.Bookmark, error)
 
+	// ListPage retrieves up to limit examples starting at cursor, in the order of List
+	// An empty cursor starts at the first example; a limit <= 0 returns all remaining ones
+	ListPage(ctx context.Context, cursor string, limit int) (*Page, error)
+
+	// CountByToolName counts the examples of every tool name, most examples first
+	CountByToolName(ctx context.Context) ([]ToolCount, error)
+
 	// ListByToolName retrieves all examples for a specific tool name
 	ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error)
 
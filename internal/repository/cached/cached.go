@@ -0,0 +1,201 @@
+package cached
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// ErrBookmarkNotFound is returned when a bookmark is not in the cached listing
+var ErrBookmarkNotFound = fmt.Errorf("bookmark %w", models.ErrNotFound)
+
+// CachedBookmarkRepository serves reads from a copy of another repository's listing
+// The listing is loaded on the first read and dropped after every write, and after ttl for
+// changes made by other processes. Returned examples are copies, so callers can modify them.
+type CachedBookmarkRepository struct {
+	repo     repository.BookmarkRepository
+	ttl      time.Duration // 0 keeps the listing until the next write
+	now      func() time.Time
+	mu       sync.Mutex
+	examples []*models.Bookmark // nil until loaded
+	loadedAt time.Time
+}
+
+// NewCachedBookmarkRepository wraps repo, reloading its listing at most every ttl
+func NewCachedBookmarkRepository(repo repository.BookmarkRepository, ttl time.Duration) repository.BookmarkRepository {
+	return &CachedBookmarkRepository{
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Unwrap returns the wrapped repository
+func (r *CachedBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.repo
+}
+
+// listing returns the cached examples, loading them if needed
+// The caller must hold the lock and must not modify the result.
+func (r *CachedBookmarkRepository) listing(ctx context.Context) ([]*models.Bookmark, error) {
+	if r.examples != nil && (r.ttl == 0 || r.now().Sub(r.loadedAt) < r.ttl) {
+		return r.examples, nil
+	}
+
+	examples, err := r.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if examples == nil {
+		examples = []*models.Bookmark{}
+	}
+	r.examples = examples
+	r.loadedAt = r.now()
+	return examples, nil
+}
+
+// read runs fn on the cached examples
+func (r *CachedBookmarkRepository) read(ctx context.Context, fn func([]*models.Bookmark)) error {
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	examples, err := r.listing(ctx)
+	if err != nil {
+		return err
+	}
+	fn(examples)
+	return nil
+}
+
+// write runs a write operation and drops the cached listing, whether it failed or not
+func (r *CachedBookmarkRepository) write(fn func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.examples = nil
+	return fn()
+}
+
+// Batch runs fn with the staging repository of the wrapped one, dropping the cached listing
+// Reads of fn are not cached, so they see its writes.
+func (r *CachedBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	return r.write(func() error { return repository.Batch(ctx, r.repo, fn) })
+}
+
+// Create adds a new example to the wrapped repository
+func (r *CachedBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	return r.write(func() error { return r.repo.Create(ctx, example) })
+}
+
+// GetByCommand retrieves an example by its command
+func (r *CachedBookmarkRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	var found *models.Bookmark
+	err := r.read(ctx, func(examples []*models.Bookmark) {
+		if i := indexOf(examples, command); i >= 0 {
+			found = examples[i].Clone()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrBookmarkNotFound
+	}
+	return found, nil
+}
+
+// List retrieves all examples
+func (r *CachedBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	var result []*models.Bookmark
+	err := r.read(ctx, func(examples []*models.Bookmark) {
+		result = cloneAll(examples)
+	})
+	return result, err
+}
+
+// ListPage retrieves up to limit examples starting at cursor
+func (r *CachedBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	var page *repository.Page
+	var pageErr error
+	err := r.read(ctx, func(examples []*models.Bookmark) {
+		if page, pageErr = repository.Paginate(examples, cursor, limit); pageErr == nil {
+			page.Bookmarks = cloneAll(page.Bookmarks)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, pageErr
+}
+
+// CountByToolName counts the examples of every tool name
+func (r *CachedBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	var counts []repository.ToolCount
+	err := r.read(ctx, func(examples []*models.Bookmark) {
+		counts = repository.CountTools(examples)
+	})
+	return counts, err
+}
+
+// ListByToolName retrieves all examples for a specific tool name
+func (r *CachedBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	result := []*models.Bookmark{}
+	err := r.read(ctx, func(examples []*models.Bookmark) {
+		for _, example := range examples {
+			if example.ToolName == toolName {
+				result = append(result, example.Clone())
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Update modifies an existing example in the wrapped repository
+func (r *CachedBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	return r.write(func() error { return r.repo.Update(ctx, example) })
+}
+
+// Delete removes an example by command from the wrapped repository
+func (r *CachedBookmarkRepository) Delete(ctx context.Context, command string) error {
+	return r.write(func() error { return r.repo.Delete(ctx, command) })
+}
+
+// DeleteByToolName removes all examples for a tool name from the wrapped repository
+func (r *CachedBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	return r.write(func() error { return r.repo.DeleteByToolName(ctx, toolName) })
+}
+
+// Exists checks if an example with the given command exists
+func (r *CachedBookmarkRepository) Exists(ctx context.Context, command string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(examples []*models.Bookmark) {
+		exists = indexOf(examples, command) >= 0
+	})
+	return exists, err
+}
+
+func indexOf(examples []*models.Bookmark, command string) int {
+	return slices.IndexFunc(examples, func(example *models.Bookmark) bool {
+		return example.Command == command
+	})
+}
+
+func cloneAll(examples []*models.Bookmark) []*models.Bookmark {
+	result := make([]*models.Bookmark, len(examples))
+	for i, example := range examples {
+		result[i] = example.Clone()
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+//go:build unit
+// +build unit
+
+package cached
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+// countingRepository counts the listings of the wrapped repository
+type countingRepository struct {
+	repository.BookmarkRepository
+	lists int
+}
+
+func (r *countingRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	r.lists++
+	return r.BookmarkRepository.List(ctx)
+}
+
+func TestCachedRepository(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingRepository{BookmarkRepository: memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "ls -la", ToolName: "ls", Description: "list"},
+		&models.Bookmark{Command: "git status", ToolName: "git", Description: "status"},
+	)}
+	repo := NewCachedBookmarkRepository(backend, 0)
+
+	if _, err := repo.List(ctx); err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if exists, _ := repo.Exists(ctx, "git status"); !exists {
+		t.Error("Expected git status to exist")
+	}
+	if counts, _ := repo.CountByToolName(ctx); len(counts) != 2 {
+		t.Errorf("Expected two tools, got %v", counts)
+	}
+	if backend.lists != 1 {
+		t.Errorf("Expected reads to share one listing, got %d", backend.lists)
+	}
+
+	example, err := repo.GetByCommand(ctx, "ls -la")
+	if err != nil {
+		t.Fatalf("Failed to get bookmark: %v", err)
+	}
+	example.Description = "changed"
+	if again, _ := repo.GetByCommand(ctx, "ls -la"); again.Description != "list" {
+		t.Errorf("Expected callers not to change the cache, got %q", again.Description)
+	}
+	if _, err := repo.GetByCommand(ctx, "missing"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := repo.Create(ctx, &models.Bookmark{Command: "pwd", ToolName: "pwd"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	page, err := repo.ListPage(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("Failed to list page: %v", err)
+	}
+	if len(page.Bookmarks) != 2 || page.NextCursor != "2" {
+		t.Errorf("Unexpected first page: %+v", page)
+	}
+	if examples, _ := repo.ListByToolName(ctx, "pwd"); len(examples) != 1 {
+		t.Errorf("Expected the write to drop the cache, got %v", examples)
+	}
+	if backend.lists != 2 {
+		t.Errorf("Expected one reload after the write, got %d listings", backend.lists)
+	}
+}
+
+func TestCachedRepositoryTTL(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingRepository{BookmarkRepository: memory.NewMemoryBookmarkRepository()}
+	repo := NewCachedBookmarkRepository(backend, time.Minute).(*CachedBookmarkRepository)
+	now := time.Now()
+	repo.now = func() time.Time { return now }
+
+	repo.List(ctx)
+	// Changed by another process
+	backend.Create(ctx, &models.Bookmark{Command: "pwd"})
+
+	if examples, _ := repo.List(ctx); len(examples) != 0 {
+		t.Errorf("Expected the cached listing within the ttl, got %d bookmarks", len(examples))
+	}
+	now = now.Add(time.Minute)
+	if examples, _ := repo.List(ctx); len(examples) != 1 {
+		t.Errorf("Expected a reload after the ttl, got %d bookmarks", len(examples))
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// Interrupted returns an error once ctx is canceled or past its deadline, nil before
+// Backends check it before slow steps, e.g. reading or writing a file, so a caller that gave
+// up, like the TUI after its store timeout, is not kept waiting. The error wraps ctx.Err().
+func Interrupted(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("store operation aborted: %w", err)
+	}
+	return nil
+}
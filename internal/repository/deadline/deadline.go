@@ -0,0 +1,125 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// DeadlineBookmarkRepository bounds every operation of another repository by a timeout
+// The wrapped repository has to honor the deadline of its context, like the built-in
+// backends do; callers then get an error instead of waiting for an unresponsive store,
+// e.g. a file on a hung network mount.
+type DeadlineBookmarkRepository struct {
+	repo    repository.BookmarkRepository
+	timeout time.Duration
+}
+
+// NewDeadlineBookmarkRepository wraps repo, giving each operation at most timeout
+func NewDeadlineBookmarkRepository(repo repository.BookmarkRepository, timeout time.Duration) repository.BookmarkRepository {
+	return &DeadlineBookmarkRepository{
+		repo:    repo,
+		timeout: timeout,
+	}
+}
+
+// Unwrap returns the wrapped repository
+func (r *DeadlineBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.repo
+}
+
+// explain tells apart an operation that ran out of time from other failures
+func (r *DeadlineBookmarkRepository) explain(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("store did not respond within %s: %w", r.timeout, err)
+	}
+	return err
+}
+
+// Batch runs fn with the staging repository of the wrapped one
+// The timeout bounds the whole batch, fn included, rather than each of its operations.
+func (r *DeadlineBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.explain(repository.Batch(ctx, r.repo, fn))
+}
+
+// Create adds a new example to storage
+func (r *DeadlineBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.explain(r.repo.Create(ctx, example))
+}
+
+// GetByCommand retrieves an example by its command
+func (r *DeadlineBookmarkRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	example, err := r.repo.GetByCommand(ctx, command)
+	return example, r.explain(err)
+}
+
+// List retrieves all examples
+func (r *DeadlineBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	examples, err := r.repo.List(ctx)
+	return examples, r.explain(err)
+}
+
+// ListPage retrieves up to limit examples starting at cursor
+func (r *DeadlineBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	page, err := r.repo.ListPage(ctx, cursor, limit)
+	return page, r.explain(err)
+}
+
+// CountByToolName counts the examples of every tool name
+func (r *DeadlineBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	counts, err := r.repo.CountByToolName(ctx)
+	return counts, r.explain(err)
+}
+
+// ListByToolName retrieves all examples for a specific tool name
+func (r *DeadlineBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	examples, err := r.repo.ListByToolName(ctx, toolName)
+	return examples, r.explain(err)
+}
+
+// Update modifies an existing example
+func (r *DeadlineBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.explain(r.repo.Update(ctx, example))
+}
+
+// Delete removes an example by command
+func (r *DeadlineBookmarkRepository) Delete(ctx context.Context, command string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.explain(r.repo.Delete(ctx, command))
+}
+
+// DeleteByToolName removes all examples for a tool name
+func (r *DeadlineBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.explain(r.repo.DeleteByToolName(ctx, toolName))
+}
+
+// Exists checks if an example with the given command exists
+func (r *DeadlineBookmarkRepository) Exists(ctx context.Context, command string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	exists, err := r.repo.Exists(ctx, command)
+	return exists, r.explain(err)
+}
@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package deadline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+// hangingRepository blocks its listing until the context is done, like a hung network mount
+type hangingRepository struct {
+	repository.BookmarkRepository
+}
+
+func (r *hangingRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	<-ctx.Done()
+	return nil, repository.Interrupted(ctx)
+}
+
+func TestDeadlineRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDeadlineBookmarkRepository(&hangingRepository{memory.NewMemoryBookmarkRepository()}, 20*time.Millisecond)
+
+	start := time.Now()
+	_, err := repo.List(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "did not respond within 20ms") {
+		t.Errorf("Expected the listing to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the listing to be aborted promptly, took %s", elapsed)
+	}
+
+	if err := repo.Create(ctx, &models.Bookmark{Command: "ls -la"}); err != nil {
+		t.Errorf("Expected operations within the timeout to succeed, got %v", err)
+	}
+	if _, err := repo.GetByCommand(ctx, "missing"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected other errors to pass unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import "context"
+
+// Decorator wraps a repository to add behavior to all of its operations, e.g. caching or
+// metrics, without changing the backend
+type Decorator func(BookmarkRepository) BookmarkRepository
+
+// Decorate wraps repo in the decorators in order: the first one wraps repo itself, the last
+// one receives the calls first
+func Decorate(repo BookmarkRepository, decorators ...Decorator) BookmarkRepository {
+	for _, decorate := range decorators {
+		repo = decorate(repo)
+	}
+	return repo
+}
+
+// Wrapper is implemented by decorators to expose the repository they wrap
+type Wrapper interface {
+	Unwrap() BookmarkRepository
+}
+
+// WriteChecker is implemented by repositories that can tell whether a write would succeed
+// without writing, e.g. for readiness probes
+type WriteChecker interface {
+	CheckWritable(ctx context.Context) error
+}
+
+// CheckWritable reports whether repo accepts writes, asking the outermost WriteChecker of
+// the chain of decorators; repositories that cannot tell are assumed to be writable
+func CheckWritable(ctx context.Context, repo BookmarkRepository) error {
+	for repo != nil {
+		if checker, ok := repo.(WriteChecker); ok {
+			return checker.CheckWritable(ctx)
+		}
+		wrapper, ok := repo.(Wrapper)
+		if !ok {
+			return nil
+		}
+		repo = wrapper.Unwrap()
+	}
+	return nil
+}
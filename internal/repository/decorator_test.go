@@ -0,0 +1,34 @@
+//go:build unit
+// +build unit
+
+package repository
+
+import "testing"
+
+type namedRepository struct {
+	BookmarkRepository
+	names []string
+}
+
+func TestDecorate(t *testing.T) {
+	wrap := func(name string) Decorator {
+		return func(repo BookmarkRepository) BookmarkRepository {
+			var names []string
+			if inner, ok := repo.(*namedRepository); ok {
+				names = inner.names
+			}
+			return &namedRepository{BookmarkRepository: repo, names: append(names, name)}
+		}
+	}
+
+	repo := Decorate(&namedRepository{}, wrap("cache"), wrap("metrics"))
+
+	names := repo.(*namedRepository).names
+	if len(names) != 2 || names[0] != "cache" || names[1] != "metrics" {
+		t.Errorf("Expected the first decorator innermost, got %v", names)
+	}
+	base := &namedRepository{}
+	if Decorate(base) != base {
+		t.Error("Expected no decorators to return the repository itself")
+	}
+}
@@ -0,0 +1,244 @@
+package layered
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// ErrReadOnlyBookmark is returned when deleting a bookmark that only exists in a read-only layer
+var ErrReadOnlyBookmark = fmt.Errorf("%w: bookmark belongs to a read-only layer", models.ErrConflict)
+
+// Layer is a named read-only bookmark store, e.g. a system-wide or team file
+type Layer struct {
+	Name string
+	Repo repository.BookmarkRepository
+}
+
+// LayeredBookmarkRepository merges the user's store with read-only layers
+// Reads see the user's bookmarks first, followed by layer bookmarks whose command is not
+// shadowed by an earlier store. Writes always go to the user's store; editing a layer
+// bookmark copies it into the user's store, which then shadows the original.
+type LayeredBookmarkRepository struct {
+	user   repository.BookmarkRepository
+	layers []Layer
+}
+
+// NewLayeredBookmarkRepository creates a repository writing to user and reading from all layers
+func NewLayeredBookmarkRepository(user repository.BookmarkRepository, layers ...Layer) repository.BookmarkRepository {
+	return &LayeredBookmarkRepository{
+		user:   user,
+		layers: layers,
+	}
+}
+
+// Unwrap returns the user's store, which receives all writes
+func (r *LayeredBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.user
+}
+
+// Batch runs fn with the layers over the staging repository of the user's store
+func (r *LayeredBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	return repository.Batch(ctx, r.user, func(tx repository.BookmarkRepository) error {
+		return fn(&LayeredBookmarkRepository{user: tx, layers: r.layers})
+	})
+}
+
+// Create adds a new example to the user's store
+func (r *LayeredBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	layer, err := r.findInLayers(ctx, example.Command)
+	if err != nil {
+		return err
+	}
+	if layer != nil {
+		return fmt.Errorf("example with this command %w in the %s layer", models.ErrAlreadyExists, layer.Name)
+	}
+
+	return r.user.Create(ctx, userCopy(example))
+}
+
+// GetByCommand retrieves an example by its command, preferring the user's store
+func (r *LayeredBookmarkRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	example, err := r.user.GetByCommand(ctx, command)
+	if err == nil {
+		return example, nil
+	}
+
+	layer, layerErr := r.findInLayers(ctx, command)
+	if layerErr != nil {
+		return nil, layerErr
+	}
+	if layer == nil {
+		return nil, err
+	}
+
+	example, err = layer.Repo.GetByCommand(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	example.Origin = layer.Name
+	return example, nil
+}
+
+// List retrieves all examples of all stores
+func (r *LayeredBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	return r.merge(ctx, func(repo repository.BookmarkRepository) ([]*models.Bookmark, error) {
+		return repo.List(ctx)
+	})
+}
+
+// ListPage retrieves up to limit examples of all stores starting at cursor
+func (r *LayeredBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	examples, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Paginate(examples, cursor, limit)
+}
+
+// CountByToolName counts the examples of every tool name of all stores
+// Shadowed layer bookmarks are only counted once, so the merged listing is counted.
+func (r *LayeredBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	examples, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.CountTools(examples), nil
+}
+
+// ListByToolName retrieves all examples for a specific tool name of all stores
+func (r *LayeredBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	return r.merge(ctx, func(repo repository.BookmarkRepository) ([]*models.Bookmark, error) {
+		return repo.ListByToolName(ctx, toolName)
+	})
+}
+
+// Update modifies an example in the user's store
+// Layer bookmarks are copied into the user's store instead
+func (r *LayeredBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	inUser, err := r.user.Exists(ctx, example.Command)
+	if err != nil {
+		return err
+	}
+	if inUser {
+		return r.user.Update(ctx, userCopy(example))
+	}
+
+	layer, err := r.findInLayers(ctx, example.Command)
+	if err != nil {
+		return err
+	}
+	if layer == nil {
+		return r.user.Update(ctx, userCopy(example))
+	}
+
+	return r.user.Create(ctx, userCopy(example))
+}
+
+// Delete removes an example from the user's store
+// Bookmarks that only exist in a read-only layer cannot be deleted
+func (r *LayeredBookmarkRepository) Delete(ctx context.Context, command string) error {
+	inUser, err := r.user.Exists(ctx, command)
+	if err != nil {
+		return err
+	}
+	if !inUser {
+		layer, err := r.findInLayers(ctx, command)
+		if err != nil {
+			return err
+		}
+		if layer != nil {
+			return fmt.Errorf("%w '%s'", ErrReadOnlyBookmark, layer.Name)
+		}
+	}
+
+	return r.user.Delete(ctx, command)
+}
+
+// DeleteByToolName removes all examples for a tool name from the user's store
+// Layer bookmarks of the tool are kept
+func (r *LayeredBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	userExamples, err := r.user.ListByToolName(ctx, toolName)
+	if err != nil {
+		return err
+	}
+	if len(userExamples) == 0 {
+		for _, layer := range r.layers {
+			examples, err := layer.Repo.ListByToolName(ctx, toolName)
+			if err != nil {
+				return fmt.Errorf("failed to read %s layer: %w", layer.Name, err)
+			}
+			if len(examples) > 0 {
+				return fmt.Errorf("%w '%s'", ErrReadOnlyBookmark, layer.Name)
+			}
+		}
+	}
+
+	return r.user.DeleteByToolName(ctx, toolName)
+}
+
+// Exists checks if an example with the given command exists in any store
+func (r *LayeredBookmarkRepository) Exists(ctx context.Context, command string) (bool, error) {
+	exists, err := r.user.Exists(ctx, command)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	layer, err := r.findInLayers(ctx, command)
+	return layer != nil, err
+}
+
+// findInLayers returns the first layer containing the command, or nil
+func (r *LayeredBookmarkRepository) findInLayers(ctx context.Context, command string) (*Layer, error) {
+	for i := range r.layers {
+		exists, err := r.layers[i].Repo.Exists(ctx, command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s layer: %w", r.layers[i].Name, err)
+		}
+		if exists {
+			return &r.layers[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// merge lists the user's store followed by all layers, skipping shadowed commands
+func (r *LayeredBookmarkRepository) merge(ctx context.Context, list func(repository.BookmarkRepository) ([]*models.Bookmark, error)) ([]*models.Bookmark, error) {
+	examples, err := list(r.user)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(examples))
+	for _, example := range examples {
+		seen[example.Command] = true
+	}
+
+	for _, layer := range r.layers {
+		layerExamples, err := list(layer.Repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s layer: %w", layer.Name, err)
+		}
+		for _, example := range layerExamples {
+			if seen[example.Command] {
+				continue
+			}
+			seen[example.Command] = true
+			example.Origin = layer.Name
+			examples = append(examples, example)
+		}
+	}
+
+	return examples, nil
+}
+
+// userCopy returns the example without its layer origin, ready to be stored by the user
+func userCopy(example *models.Bookmark) *models.Bookmark {
+	c := *example
+	c.Origin = ""
+	return &c
+}
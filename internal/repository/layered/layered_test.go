@@ -0,0 +1,141 @@
+//go:build unit
+// +build unit
+
+package layered
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/yaml"
+)
+
+func setupLayers(t *testing.T) (repository.BookmarkRepository, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	systemPath := filepath.Join(dir, "system.yaml")
+	content := `bookmarks:
+  - command: kubectl get pods -A
+    toolname: kubectl
+    description: list all pods
+  - command: docker ps
+    toolname: docker
+    description: system docker ps
+`
+	if err := os.WriteFile(systemPath, []byte(content), 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	userPath := filepath.Join(dir, "user.yaml")
+	user, err := yaml.NewYAMLBookmarkRepository(userPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := user.Create(context.Background(), &models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "my docker ps"}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewLayeredBookmarkRepository(user, Layer{Name: "system", Repo: yaml.NewReadOnlyYAMLBookmarkRepository(systemPath)})
+	return repo, systemPath
+}
+
+func TestLayeredList(t *testing.T) {
+	repo, _ := setupLayers(t)
+
+	examples, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 merged examples, got %d", len(examples))
+	}
+
+	for _, ex := range examples {
+		switch ex.Command {
+		case "docker ps":
+			if ex.Origin != "" || ex.Description != "my docker ps" {
+				t.Errorf("User bookmark should shadow the system one, got %+v", ex)
+			}
+		case "kubectl get pods -A":
+			if ex.Origin != "system" {
+				t.Errorf("Expected origin system, got %q", ex.Origin)
+			}
+		}
+	}
+}
+
+func TestLayeredCountByToolName(t *testing.T) {
+	repo, _ := setupLayers(t)
+
+	counts, err := repo.CountByToolName(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to count: %v", err)
+	}
+
+	// The shadowed system docker ps is counted once
+	expected := []repository.ToolCount{{ToolName: "docker", Count: 1}, {ToolName: "kubectl", Count: 1}}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+}
+
+func TestLayeredWritesGoToUser(t *testing.T) {
+	repo, systemPath := setupLayers(t)
+	ctx := context.Background()
+	before, _ := os.ReadFile(systemPath)
+
+	ex, err := repo.GetByCommand(ctx, "kubectl get pods -A")
+	if err != nil {
+		t.Fatalf("Failed to get system bookmark: %v", err)
+	}
+	ex.Description = "edited"
+	if err := repo.Update(ctx, ex); err != nil {
+		t.Fatalf("Failed to update system bookmark: %v", err)
+	}
+
+	got, _ := repo.GetByCommand(ctx, "kubectl get pods -A")
+	if got.Description != "edited" || got.Origin != "" {
+		t.Errorf("Edited bookmark should now come from the user store, got %+v", got)
+	}
+
+	// The edited copy can be deleted, revealing the system bookmark again
+	if err := repo.Delete(ctx, "kubectl get pods -A"); err != nil {
+		t.Fatalf("Failed to delete user copy: %v", err)
+	}
+	got, _ = repo.GetByCommand(ctx, "kubectl get pods -A")
+	if got.Origin != "system" {
+		t.Errorf("Expected system bookmark after deleting the user copy, got %+v", got)
+	}
+
+	after, _ := os.ReadFile(systemPath)
+	if string(before) != string(after) {
+		t.Error("System file must never be modified")
+	}
+}
+
+func TestLayeredReadOnlyErrors(t *testing.T) {
+	repo, _ := setupLayers(t)
+	ctx := context.Background()
+
+	if err := repo.Delete(ctx, "kubectl get pods -A"); !errors.Is(err, ErrReadOnlyBookmark) {
+		t.Errorf("Expected ErrReadOnlyBookmark, got %v", err)
+	}
+	if err := repo.DeleteByToolName(ctx, "kubectl"); !errors.Is(err, ErrReadOnlyBookmark) {
+		t.Errorf("Expected ErrReadOnlyBookmark, got %v", err)
+	}
+	if err := repo.Create(ctx, &models.Bookmark{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "dup"}); err == nil {
+		t.Error("Expected error when creating a command of a layer")
+	}
+
+	exists, err := repo.Exists(ctx, "kubectl get pods -A")
+	if err != nil || !exists {
+		t.Errorf("Expected layer bookmark to exist, got %v, %v", exists, err)
+	}
+}
@@ -0,0 +1,134 @@
+package logged
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// LoggedBookmarkRepository logs every operation of another repository
+// Operations are logged at debug level with their duration, failed ones at error level, unless
+// they failed because a bookmark is missing or already exists.
+type LoggedBookmarkRepository struct {
+	repo   repository.BookmarkRepository
+	logger *slog.Logger
+}
+
+// NewLoggedBookmarkRepository wraps repo, logging its operations to logger with the given
+// name, e.g. the path of a store file, to tell several stores apart
+func NewLoggedBookmarkRepository(repo repository.BookmarkRepository, logger *slog.Logger, name string) repository.BookmarkRepository {
+	return &LoggedBookmarkRepository{
+		repo:   repo,
+		logger: logger.With("store", name),
+	}
+}
+
+// Unwrap returns the wrapped repository
+func (r *LoggedBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.repo
+}
+
+// log records an operation that started at start and failed with err, if not nil
+func (r *LoggedBookmarkRepository) log(ctx context.Context, op string, start time.Time, err error, attrs ...any) {
+	attrs = append(attrs, "op", op, "duration", time.Since(start))
+	if err != nil && !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrAlreadyExists) {
+		r.logger.ErrorContext(ctx, "store operation failed", append(attrs, "error", err)...)
+		return
+	}
+	r.logger.DebugContext(ctx, "store operation", attrs...)
+}
+
+// Batch runs fn with the staging repository of the wrapped one, logging its operations as
+// well as the whole batch
+func (r *LoggedBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	start := time.Now()
+	err := repository.Batch(ctx, r.repo, func(tx repository.BookmarkRepository) error {
+		return fn(&LoggedBookmarkRepository{repo: tx, logger: r.logger})
+	})
+	r.log(ctx, "batch", start, err)
+	return err
+}
+
+// Create adds a new example to storage
+func (r *LoggedBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	start := time.Now()
+	err := r.repo.Create(ctx, example)
+	r.log(ctx, "create", start, err, "command", example.Command)
+	return err
+}
+
+// GetByCommand retrieves an example by its command
+func (r *LoggedBookmarkRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	start := time.Now()
+	example, err := r.repo.GetByCommand(ctx, command)
+	r.log(ctx, "get", start, err, "command", command)
+	return example, err
+}
+
+// List retrieves all examples
+func (r *LoggedBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	start := time.Now()
+	examples, err := r.repo.List(ctx)
+	r.log(ctx, "list", start, err, "bookmarks", len(examples))
+	return examples, err
+}
+
+// ListPage retrieves up to limit examples starting at cursor
+func (r *LoggedBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	start := time.Now()
+	page, err := r.repo.ListPage(ctx, cursor, limit)
+	r.log(ctx, "list_page", start, err, "cursor", cursor, "limit", limit)
+	return page, err
+}
+
+// CountByToolName counts the examples of every tool name
+func (r *LoggedBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	start := time.Now()
+	counts, err := r.repo.CountByToolName(ctx)
+	r.log(ctx, "count_by_tool", start, err, "tools", len(counts))
+	return counts, err
+}
+
+// ListByToolName retrieves all examples for a specific tool name
+func (r *LoggedBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	start := time.Now()
+	examples, err := r.repo.ListByToolName(ctx, toolName)
+	r.log(ctx, "list_by_tool", start, err, "tool", toolName, "bookmarks", len(examples))
+	return examples, err
+}
+
+// Update modifies an existing example
+func (r *LoggedBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	start := time.Now()
+	err := r.repo.Update(ctx, example)
+	r.log(ctx, "update", start, err, "command", example.Command)
+	return err
+}
+
+// Delete removes an example by command
+func (r *LoggedBookmarkRepository) Delete(ctx context.Context, command string) error {
+	start := time.Now()
+	err := r.repo.Delete(ctx, command)
+	r.log(ctx, "delete", start, err, "command", command)
+	return err
+}
+
+// DeleteByToolName removes all examples for a tool name
+func (r *LoggedBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	start := time.Now()
+	err := r.repo.DeleteByToolName(ctx, toolName)
+	r.log(ctx, "delete_by_tool", start, err, "tool", toolName)
+	return err
+}
+
+// Exists checks if an example with the given command exists
+func (r *LoggedBookmarkRepository) Exists(ctx context.Context, command string) (bool, error) {
+	start := time.Now()
+	exists, err := r.repo.Exists(ctx, command)
+	r.log(ctx, "exists", start, err, "command", command, "exists", exists)
+	return exists, err
+}
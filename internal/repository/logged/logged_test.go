@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package logged
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/repository/yaml"
+)
+
+func TestLoggedRepository(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	repo := NewLoggedBookmarkRepository(memory.NewMemoryBookmarkRepository(), logger, "memory")
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Bookmark{Command: "ls -la", ToolName: "ls", Description: "list"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	if _, err := repo.GetByCommand(ctx, "missing"); err == nil {
+		t.Fatal("Expected a missing bookmark to be reported")
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `level=DEBUG msg="store operation" store=memory command="ls -la" op=create`) {
+		t.Errorf("Expected the create operation at debug level, got: %s", out)
+	}
+	if strings.Contains(out, "level=ERROR") {
+		t.Errorf("Expected a missing bookmark not to be logged as an error, got: %s", out)
+	}
+
+	buf.Reset()
+	readOnly := NewLoggedBookmarkRepository(yaml.NewReadOnlyYAMLBookmarkRepository(t.TempDir()+"/system.yaml"), logger, "system")
+	if err := readOnly.Create(ctx, &models.Bookmark{Command: "ls -la"}); err == nil {
+		t.Fatal("Expected writing a read-only store to fail")
+	}
+	if !strings.Contains(buf.String(), `level=ERROR msg="store operation failed" store=system command="ls -la" op=create`) {
+		t.Errorf("Expected the failed write at error level, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+var (
+	// ErrBookmarkNotFound is returned when an example is not found
+	ErrBookmarkNotFound = fmt.Errorf("bookmark %w", models.ErrNotFound)
+	// ErrBookmarkAlreadyExists is returned when attempting to create a duplicate example
+	ErrBookmarkAlreadyExists = fmt.Errorf("example with this command %w", models.ErrAlreadyExists)
+)
+
+// MemoryBookmarkRepository implements BookmarkRepository in memory
+// Examples keep their insertion order. Stored and returned examples are copies, so callers
+// can modify them without affecting the repository.
+type MemoryBookmarkRepository struct {
+	examples []*models.Bookmark
+	mu       sync.RWMutex // Thread-safe operations
+}
+
+// NewMemoryBookmarkRepository creates an empty in-memory repository, optionally seeded with examples
+func NewMemoryBookmarkRepository(examples ...*models.Bookmark) repository.BookmarkRepository {
+	r := &MemoryBookmarkRepository{
+		examples: make([]*models.Bookmark, 0, len(examples)),
+	}
+	for _, example := range examples {
+		r.examples = append(r.examples, example.Clone())
+	}
+	return r
+}
+
+// Create adds a new example
+func (r *MemoryBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(example.Command) >= 0 {
+		return ErrBookmarkAlreadyExists
+	}
+
+	r.examples = append(r.examples, example.Clone())
+	return nil
+}
+
+// GetByCommand retrieves an example by its command
+func (r *MemoryBookmarkRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	if err := repository.Interrupted(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(command)
+	if i < 0 {
+		return nil, ErrBookmarkNotFound
+	}
+	return r.examples[i].Clone(), nil
+}
+
+// List retrieves all examples
+func (r *MemoryBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	if err := repository.Interrupted(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	examples := make([]*models.Bookmark, len(r.examples))
+	for i, example := range r.examples {
+		examples[i] = example.Clone()
+	}
+	return examples, nil
+}
+
+// ListPage retrieves up to limit examples starting at cursor
+func (r *MemoryBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	examples, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Paginate(examples, cursor, limit)
+}
+
+// CountByToolName counts the examples of every tool name without copying them
+func (r *MemoryBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	if err := repository.Interrupted(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := map[string]int{}
+	for _, example := range r.examples {
+		counts[example.ToolName]++
+	}
+	return repository.SortToolCounts(counts), nil
+}
+
+// ListByToolName retrieves all examples for a specific tool name
+func (r *MemoryBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	if err := repository.Interrupted(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var examples []*models.Bookmark
+	for _, example := range r.examples {
+		if example.ToolName == toolName {
+			examples = append(examples, example.Clone())
+		}
+	}
+	return examples, nil
+}
+
+// Update modifies an existing example
+func (r *MemoryBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(example.Command)
+	if i < 0 {
+		return ErrBookmarkNotFound
+	}
+
+	r.examples[i] = example.Clone()
+	return nil
+}
+
+// Delete removes an example by command
+func (r *MemoryBookmarkRepository) Delete(ctx context.Context, command string) error {
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(command)
+	if i < 0 {
+		return ErrBookmarkNotFound
+	}
+
+	r.examples = slices.Delete(r.examples, i, i+1)
+	return nil
+}
+
+// DeleteByToolName removes all examples for a tool name
+func (r *MemoryBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := slices.DeleteFunc(slices.Clone(r.examples), func(example *models.Bookmark) bool {
+		return example.ToolName == toolName
+	})
+	if len(remaining) == len(r.examples) {
+		return ErrBookmarkNotFound
+	}
+
+	r.examples = remaining
+	return nil
+}
+
+// Exists checks if an example with the given command exists
+func (r *MemoryBookmarkRepository) Exists(ctx context.Context, command string) (bool, error) {
+	if err := repository.Interrupted(ctx); err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.indexOf(command) >= 0, nil
+}
+
+// indexOf returns the position of the example with the given command, or -1
+func (r *MemoryBookmarkRepository) indexOf(command string) int {
+	return slices.IndexFunc(r.examples, func(example *models.Bookmark) bool {
+		return example.Command == command
+	})
+}
+
+// Batch runs fn with a copy of the examples, which replaces them when fn returns nil
+// Writes of others wait until the batch is done.
+func (r *MemoryBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &MemoryBookmarkRepository{
+		examples: slices.Clone(r.examples), // Stored examples are only replaced, never modified
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.examples = tx.examples
+	return nil
+}
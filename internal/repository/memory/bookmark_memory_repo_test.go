@@ -0,0 +1,172 @@
+//go:build unit
+// +build unit
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestCreateAndGet(t *testing.T) {
+	repo := NewMemoryBookmarkRepository()
+	ctx := context.Background()
+
+	example := &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list all pods"}
+	if err := repo.Create(ctx, example); err != nil {
+		t.Fatalf("Failed to create example: %v", err)
+	}
+
+	retrieved, err := repo.GetByCommand(ctx, example.Command)
+	if err != nil {
+		t.Fatalf("Failed to retrieve example: %v", err)
+	}
+	if !retrieved.Equal(example) {
+		t.Errorf("Expected %+v, got %+v", example, retrieved)
+	}
+
+	if err := repo.Create(ctx, example); !errors.Is(err, models.ErrAlreadyExists) {
+		t.Errorf("Expected already exists error, got %v", err)
+	}
+	if _, err := repo.GetByCommand(ctx, "missing"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestSeededListKeepsOrder(t *testing.T) {
+	repo := NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "b", ToolName: "x"},
+		&models.Bookmark{Command: "a", ToolName: "y"},
+		&models.Bookmark{Command: "c", ToolName: "x"},
+	)
+	ctx := context.Background()
+
+	list, _ := repo.List(ctx)
+	if len(list) != 3 || list[0].Command != "b" || list[1].Command != "a" || list[2].Command != "c" {
+		t.Errorf("Expected insertion order, got %+v", list)
+	}
+
+	byTool, _ := repo.ListByToolName(ctx, "x")
+	if len(byTool) != 2 {
+		t.Errorf("Expected 2 examples for tool x, got %d", len(byTool))
+	}
+
+	page, err := repo.ListPage(ctx, "", 2)
+	if err != nil || len(page.Bookmarks) != 2 || page.NextCursor == "" {
+		t.Errorf("Expected first page of 2, got %+v (%v)", page, err)
+	}
+}
+
+func TestReturnedExamplesAreCopies(t *testing.T) {
+	repo := NewMemoryBookmarkRepository()
+	ctx := context.Background()
+
+	example := &models.Bookmark{Command: "ls", ToolName: "ls", Description: "list", Tags: []string{"fs"}}
+	_ = repo.Create(ctx, example)
+	example.Description = "changed after create"
+	example.Tags[0] = "changed"
+
+	retrieved, _ := repo.GetByCommand(ctx, "ls")
+	retrieved.Description = "changed after get"
+
+	stored, _ := repo.GetByCommand(ctx, "ls")
+	if stored.Description != "list" || stored.Tags[0] != "fs" {
+		t.Errorf("Expected stored example to be unaffected, got %+v", stored)
+	}
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	repo := NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "containers"},
+		&models.Bookmark{Command: "docker images", ToolName: "docker", Description: "images"},
+		&models.Bookmark{Command: "ls", ToolName: "ls", Description: "list"},
+	)
+	ctx := context.Background()
+
+	if err := repo.Update(ctx, &models.Bookmark{Command: "ls", ToolName: "ls", Description: "updated"}); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if updated, _ := repo.GetByCommand(ctx, "ls"); updated.Description != "updated" {
+		t.Errorf("Expected updated description, got %q", updated.Description)
+	}
+	if err := repo.Update(ctx, &models.Bookmark{Command: "missing"}); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found error on update, got %v", err)
+	}
+
+	if err := repo.Delete(ctx, "ls"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if exists, _ := repo.Exists(ctx, "ls"); exists {
+		t.Error("Expected deleted example to be gone")
+	}
+	if err := repo.Delete(ctx, "ls"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found error on delete, got %v", err)
+	}
+
+	if err := repo.DeleteByToolName(ctx, "docker"); err != nil {
+		t.Fatalf("Failed to delete by tool name: %v", err)
+	}
+	if list, _ := repo.List(ctx); len(list) != 0 {
+		t.Errorf("Expected empty repository, got %+v", list)
+	}
+	if err := repo.DeleteByToolName(ctx, "docker"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found error on delete by tool name, got %v", err)
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	repo := NewMemoryBookmarkRepository()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			command := fmt.Sprintf("cmd %d", i)
+			_ = repo.Create(ctx, &models.Bookmark{Command: command, ToolName: "tool"})
+			_, _ = repo.GetByCommand(ctx, command)
+			_, _ = repo.List(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	list, _ := repo.List(ctx)
+	if len(list) != 50 {
+		t.Errorf("Expected 50 examples, got %d", len(list))
+	}
+}
+
+func TestCountByToolName(t *testing.T) {
+	repo := NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "containers"},
+		&models.Bookmark{Command: "git status", ToolName: "git", Description: "status"},
+		&models.Bookmark{Command: "git log", ToolName: "git", Description: "log"},
+	)
+
+	counts, err := repo.CountByToolName(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to count: %v", err)
+	}
+	if len(counts) != 2 || counts[0].ToolName != "git" || counts[0].Count != 2 || counts[1].Count != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	repo := NewMemoryBookmarkRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := repo.Create(ctx, &models.Bookmark{Command: "ls -la"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if exists, _ := repo.Exists(context.Background(), "ls -la"); exists {
+		t.Error("Expected a canceled create not to store the bookmark")
+	}
+}
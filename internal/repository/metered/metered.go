@@ -0,0 +1,198 @@
+package metered
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// DurationBuckets are the upper bounds of the duration histogram of OpStats
+var DurationBuckets = []time.Duration{
+	time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 500 * time.Millisecond, time.Second, 5 * time.Second,
+}
+
+// OpStats are the metrics of one kind of store operation
+type OpStats struct {
+	Op       string
+	Calls    int
+	Errors   int           // Calls that returned an error, including missing bookmarks
+	Duration time.Duration // Total duration of all calls
+	Buckets  []int         // Calls per DurationBuckets bound they did not exceed, cumulative like Prometheus
+}
+
+// Metrics collects the operation metrics of one or more repositories
+// It is safe for concurrent use.
+type Metrics struct {
+	mu  sync.Mutex
+	ops map[string]*OpStats
+}
+
+// NewMetrics returns an empty collector
+func NewMetrics() *Metrics {
+	return &Metrics{ops: map[string]*OpStats{}}
+}
+
+func (m *Metrics) observe(op string, start time.Time, err error) {
+	duration := time.Since(start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.ops[op]
+	if !ok {
+		stats = &OpStats{Op: op, Buckets: make([]int, len(DurationBuckets))}
+		m.ops[op] = stats
+	}
+	stats.Calls++
+	stats.Duration += duration
+	for i, bound := range DurationBuckets {
+		if duration <= bound {
+			stats.Buckets[i]++
+		}
+	}
+	if err != nil {
+		stats.Errors++
+	}
+}
+
+// Snapshot returns the metrics of all operations called so far, sorted by name
+func (m *Metrics) Snapshot() []OpStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]OpStats, 0, len(m.ops))
+	for _, stats := range m.ops {
+		copied := *stats
+		copied.Buckets = slices.Clone(stats.Buckets)
+		result = append(result, copied)
+	}
+	slices.SortFunc(result, func(a, b OpStats) int {
+		return strings.Compare(a.Op, b.Op)
+	})
+	return result
+}
+
+// Log writes one info record per operation to logger
+func (m *Metrics) Log(ctx context.Context, logger *slog.Logger) {
+	for _, stats := range m.Snapshot() {
+		logger.InfoContext(ctx, "store metrics", "op", stats.Op, "calls", stats.Calls, "errors", stats.Errors, "duration", stats.Duration)
+	}
+}
+
+// MeteredBookmarkRepository counts the calls, failures and duration of the operations of
+// another repository
+type MeteredBookmarkRepository struct {
+	repo    repository.BookmarkRepository
+	metrics *Metrics
+}
+
+// NewMeteredBookmarkRepository wraps repo, recording its operations in metrics
+func NewMeteredBookmarkRepository(repo repository.BookmarkRepository, metrics *Metrics) repository.BookmarkRepository {
+	return &MeteredBookmarkRepository{
+		repo:    repo,
+		metrics: metrics,
+	}
+}
+
+// Unwrap returns the wrapped repository
+func (r *MeteredBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.repo
+}
+
+// Batch runs fn with the staging repository of the wrapped one, metering its operations as
+// well as the whole batch
+func (r *MeteredBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	start := time.Now()
+	err := repository.Batch(ctx, r.repo, func(tx repository.BookmarkRepository) error {
+		return fn(&MeteredBookmarkRepository{repo: tx, metrics: r.metrics})
+	})
+	r.metrics.observe("batch", start, err)
+	return err
+}
+
+// Create adds a new example to storage
+func (r *MeteredBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	start := time.Now()
+	err := r.repo.Create(ctx, example)
+	r.metrics.observe("create", start, err)
+	return err
+}
+
+// GetByCommand retrieves an example by its command
+func (r *MeteredBookmarkRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	start := time.Now()
+	example, err := r.repo.GetByCommand(ctx, command)
+	r.metrics.observe("get", start, err)
+	return example, err
+}
+
+// List retrieves all examples
+func (r *MeteredBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	start := time.Now()
+	examples, err := r.repo.List(ctx)
+	r.metrics.observe("list", start, err)
+	return examples, err
+}
+
+// ListPage retrieves up to limit examples starting at cursor
+func (r *MeteredBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	start := time.Now()
+	page, err := r.repo.ListPage(ctx, cursor, limit)
+	r.metrics.observe("list_page", start, err)
+	return page, err
+}
+
+// CountByToolName counts the examples of every tool name
+func (r *MeteredBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	start := time.Now()
+	counts, err := r.repo.CountByToolName(ctx)
+	r.metrics.observe("count_by_tool", start, err)
+	return counts, err
+}
+
+// ListByToolName retrieves all examples for a specific tool name
+func (r *MeteredBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	start := time.Now()
+	examples, err := r.repo.ListByToolName(ctx, toolName)
+	r.metrics.observe("list_by_tool", start, err)
+	return examples, err
+}
+
+// Update modifies an existing example
+func (r *MeteredBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	start := time.Now()
+	err := r.repo.Update(ctx, example)
+	r.metrics.observe("update", start, err)
+	return err
+}
+
+// Delete removes an example by command
+func (r *MeteredBookmarkRepository) Delete(ctx context.Context, command string) error {
+	start := time.Now()
+	err := r.repo.Delete(ctx, command)
+	r.metrics.observe("delete", start, err)
+	return err
+}
+
+// DeleteByToolName removes all examples for a tool name
+func (r *MeteredBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	start := time.Now()
+	err := r.repo.DeleteByToolName(ctx, toolName)
+	r.metrics.observe("delete_by_tool", start, err)
+	return err
+}
+
+// Exists checks if an example with the given command exists
+func (r *MeteredBookmarkRepository) Exists(ctx context.Context, command string) (bool, error) {
+	start := time.Now()
+	exists, err := r.repo.Exists(ctx, command)
+	r.metrics.observe("exists", start, err)
+	return exists, err
+}
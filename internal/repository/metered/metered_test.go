@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package metered
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+func TestMeteredRepository(t *testing.T) {
+	ctx := context.Background()
+	metrics := NewMetrics()
+	repo := NewMeteredBookmarkRepository(memory.NewMemoryBookmarkRepository(), metrics)
+
+	repo.Create(ctx, &models.Bookmark{Command: "ls -la", ToolName: "ls"})
+	repo.Create(ctx, &models.Bookmark{Command: "ls -la", ToolName: "ls"})
+	repo.List(ctx)
+	repo.GetByCommand(ctx, "missing")
+
+	stats := metrics.Snapshot()
+	if len(stats) != 3 {
+		t.Fatalf("Expected three operations, got %+v", stats)
+	}
+	if stats[0].Op != "create" || stats[0].Calls != 2 || stats[0].Errors != 1 {
+		t.Errorf("Unexpected create metrics: %+v", stats[0])
+	}
+	if stats[1].Op != "get" || stats[1].Errors != 1 || stats[2].Op != "list" || stats[2].Calls != 1 {
+		t.Errorf("Unexpected metrics: %+v", stats[1:])
+	}
+
+	if create := stats[0]; create.Buckets[len(create.Buckets)-1] != 2 {
+		t.Errorf("Expected both creates within the largest bucket, got %v", create.Buckets)
+	}
+
+	var buf bytes.Buffer
+	metrics.Log(ctx, slog.New(slog.NewTextHandler(&buf, nil)))
+	if !strings.Contains(buf.String(), `msg="store metrics" op=create calls=2 errors=1`) {
+		t.Errorf("Expected one record per operation, got: %s", buf.String())
+	}
+}
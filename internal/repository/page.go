@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// ErrInvalidCursor is returned when a page cursor was not issued by the repository
+var ErrInvalidCursor = fmt.Errorf("%w: invalid page cursor", models.ErrValidation)
+
+// Page is one slice of a paginated listing
+type Page struct {
+	Bookmarks  []*models.Bookmark
+	NextCursor string // Opaque cursor of the following page, empty on the last page
+}
+
+// Paginate returns the page of bookmarks starting at cursor
+// It serves backends that hold all bookmarks in memory anyway; the cursor is the offset
+// of the page's first bookmark. A limit <= 0 returns all remaining bookmarks.
+func Paginate(bookmarks []*models.Bookmark, cursor string, limit int) (*Page, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		offset, err = strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("%w '%s'", ErrInvalidCursor, cursor)
+		}
+	}
+	if offset > len(bookmarks) {
+		offset = len(bookmarks)
+	}
+
+	end := len(bookmarks)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	page := &Page{Bookmarks: bookmarks[offset:end]}
+	if end < len(bookmarks) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func testBookmarks(n int) []*models.Bookmark {
+	bookmarks := make([]*models.Bookmark, n)
+	for i := range bookmarks {
+		bookmarks[i] = &models.Bookmark{Command: fmt.Sprintf("cmd-%d", i), ToolName: "tool"}
+	}
+	return bookmarks
+}
+
+func TestPaginateWalksAllPages(t *testing.T) {
+	bookmarks := testBookmarks(5)
+
+	var seen []string
+	cursor := ""
+	pages := 0
+	for {
+		page, err := Paginate(bookmarks, cursor, 2)
+		if err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		pages++
+		for _, b := range page.Bookmarks {
+			seen = append(seen, b.Command)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+	if len(seen) != 5 || seen[0] != "cmd-0" || seen[4] != "cmd-4" {
+		t.Errorf("Expected all bookmarks in order, got %v", seen)
+	}
+}
+
+func TestPaginateWithoutLimit(t *testing.T) {
+	page, err := Paginate(testBookmarks(3), "1", 0)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if len(page.Bookmarks) != 2 || page.NextCursor != "" {
+		t.Errorf("Expected remaining 2 bookmarks on the last page, got %d (next %q)", len(page.Bookmarks), page.NextCursor)
+	}
+}
+
+func TestPaginateCursorPastEnd(t *testing.T) {
+	page, err := Paginate(testBookmarks(3), "10", 2)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if len(page.Bookmarks) != 0 || page.NextCursor != "" {
+		t.Errorf("Expected an empty last page, got %+v", page)
+	}
+}
+
+func TestPaginateInvalidCursor(t *testing.T) {
+	for _, cursor := range []string{"abc", "-1"} {
+		_, err := Paginate(testBookmarks(3), cursor, 2)
+		if !errors.Is(err, ErrInvalidCursor) || !errors.Is(err, models.ErrValidation) {
+			t.Errorf("Expected invalid cursor error for %q, got %v", cursor, err)
+		}
+	}
+}
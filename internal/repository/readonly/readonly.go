@@ -0,0 +1,57 @@
+package readonly
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// ErrReadOnly is returned by all write operations of a read-only repository
+var ErrReadOnly = fmt.Errorf("%w: store is read-only", models.ErrConflict)
+
+// ReadOnlyBookmarkRepository passes reads to another repository and rejects all writes
+type ReadOnlyBookmarkRepository struct {
+	repository.BookmarkRepository
+}
+
+// NewReadOnlyBookmarkRepository wraps repo, leaving it unchanged
+func NewReadOnlyBookmarkRepository(repo repository.BookmarkRepository) repository.BookmarkRepository {
+	return &ReadOnlyBookmarkRepository{BookmarkRepository: repo}
+}
+
+// Unwrap returns the wrapped repository
+func (r *ReadOnlyBookmarkRepository) Unwrap() repository.BookmarkRepository {
+	return r.BookmarkRepository
+}
+
+// Create returns ErrReadOnly
+func (r *ReadOnlyBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	return ErrReadOnly
+}
+
+// Update returns ErrReadOnly
+func (r *ReadOnlyBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	return ErrReadOnly
+}
+
+// Delete returns ErrReadOnly
+func (r *ReadOnlyBookmarkRepository) Delete(ctx context.Context, command string) error {
+	return ErrReadOnly
+}
+
+// DeleteByToolName returns ErrReadOnly
+func (r *ReadOnlyBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	return ErrReadOnly
+}
+
+// Batch runs fn with the repository itself, whose writes return ErrReadOnly
+func (r *ReadOnlyBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	return fn(r)
+}
+
+// CheckWritable returns ErrReadOnly
+func (r *ReadOnlyBookmarkRepository) CheckWritable(ctx context.Context) error {
+	return ErrReadOnly
+}
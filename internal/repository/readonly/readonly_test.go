@@ -0,0 +1,55 @@
+//go:build unit
+// +build unit
+
+package readonly
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/cached"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+func TestReadOnlyRepository(t *testing.T) {
+	ctx := context.Background()
+	bookmark := &models.Bookmark{Command: "ls -la", ToolName: "ls", Description: "list"}
+	repo := NewReadOnlyBookmarkRepository(memory.NewMemoryBookmarkRepository(bookmark))
+
+	if _, err := repo.GetByCommand(ctx, "ls -la"); err != nil {
+		t.Errorf("Expected reads to pass through, got %v", err)
+	}
+
+	writes := map[string]error{
+		"create":         repo.Create(ctx, &models.Bookmark{Command: "pwd"}),
+		"update":         repo.Update(ctx, bookmark),
+		"delete":         repo.Delete(ctx, "ls -la"),
+		"delete by tool": repo.DeleteByToolName(ctx, "ls"),
+	}
+	for name, err := range writes {
+		if !errors.Is(err, ErrReadOnly) || !errors.Is(err, models.ErrConflict) {
+			t.Errorf("Expected %s to fail with ErrReadOnly, got %v", name, err)
+		}
+	}
+
+	examples, _ := repo.List(ctx)
+	if len(examples) != 1 {
+		t.Errorf("Expected the store to be unchanged, got %d bookmarks", len(examples))
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryBookmarkRepository()
+
+	if err := repository.CheckWritable(ctx, store); err != nil {
+		t.Errorf("Expected a repository that cannot tell to count as writable, got %v", err)
+	}
+	decorated := cached.NewCachedBookmarkRepository(NewReadOnlyBookmarkRepository(store), 0)
+	if err := repository.CheckWritable(ctx, decorated); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected the read-only decorator to be found through the cache, got %v", err)
+	}
+}
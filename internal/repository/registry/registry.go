@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/audited"
+	"github.com/fgeck/tools/internal/repository/cached"
+	"github.com/fgeck/tools/internal/repository/metered"
+	"github.com/fgeck/tools/internal/repository/readonly"
+	"github.com/fgeck/tools/internal/repository/yaml"
+)
+
+// OpenFunc creates a repository for the given storage location
+type OpenFunc func(path string) (repository.BookmarkRepository, error)
+
+// backends maps backend names to their constructors
+var backends = map[string]OpenFunc{
+	"yaml": yaml.OpenStore,
+}
+
+// Open creates a repository for the named backend at the given location
+func Open(backend, path string) (repository.BookmarkRepository, error) {
+	open, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown storage backend '%s' (available: %v)", models.ErrValidation, backend, Names())
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: storage path for backend '%s' cannot be empty", models.ErrValidation, backend)
+	}
+
+	return open(path)
+}
+
+// Names returns the sorted names of all registered backends
+func Names() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecoratorOptions holds what the decorators need besides the repository they wrap
+type DecoratorOptions struct {
+	CacheTTL time.Duration    // How long the cache serves reads before reloading, 0 until the next write
+	Metrics  *metered.Metrics // Collector of the metrics decorator
+	AuditLog audit.Log        // Log of the audit decorator
+}
+
+// decorators maps decorator names to their constructors
+var decorators = map[string]func(DecoratorOptions) (repository.Decorator, error){
+	"cache": func(opts DecoratorOptions) (repository.Decorator, error) {
+		return func(repo repository.BookmarkRepository) repository.BookmarkRepository {
+			return cached.NewCachedBookmarkRepository(repo, opts.CacheTTL)
+		}, nil
+	},
+	"metrics": func(opts DecoratorOptions) (repository.Decorator, error) {
+		if opts.Metrics == nil {
+			return nil, fmt.Errorf("the metrics decorator needs a collector")
+		}
+		return func(repo repository.BookmarkRepository) repository.BookmarkRepository {
+			return metered.NewMeteredBookmarkRepository(repo, opts.Metrics)
+		}, nil
+	},
+	"readonly": func(opts DecoratorOptions) (repository.Decorator, error) {
+		return readonly.NewReadOnlyBookmarkRepository, nil
+	},
+	"audit": func(opts DecoratorOptions) (repository.Decorator, error) {
+		if opts.AuditLog == nil {
+			return nil, fmt.Errorf("the audit decorator needs an audit log")
+		}
+		return func(repo repository.BookmarkRepository) repository.BookmarkRepository {
+			return audited.NewAuditedBookmarkRepository(repo, opts.AuditLog)
+		}, nil
+	},
+}
+
+// Decorators returns the named decorators in order, for repository.Decorate
+func Decorators(names []string, opts DecoratorOptions) ([]repository.Decorator, error) {
+	result := make([]repository.Decorator, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		build, ok := decorators[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown storage decorator '%s' (available: %v)", models.ErrValidation, name, DecoratorNames())
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: storage decorator '%s' is listed twice", models.ErrValidation, name)
+		}
+		seen[name] = true
+
+		decorator, err := build(opts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, decorator)
+	}
+	return result, nil
+}
+
+// DecoratorNames returns the sorted names of all registered decorators
+func DecoratorNames() []string {
+	names := make([]string, 0, len(decorators))
+	for name := range decorators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// ToolCount is the number of bookmarks stored for a tool name
+type ToolCount struct {
+	ToolName string
+	Count    int
+}
+
+// SortToolCounts turns per-tool counts into a list, most bookmarks first and ties by name
+func SortToolCounts(counts map[string]int) []ToolCount {
+	result := make([]ToolCount, 0, len(counts))
+	for toolName, count := range counts {
+		result = append(result, ToolCount{ToolName: toolName, Count: count})
+	}
+	slices.SortFunc(result, func(a, b ToolCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ToolName, b.ToolName)
+	})
+	return result
+}
+
+// CountTools aggregates bookmarks per tool name
+// It serves backends that hold all bookmarks in memory anyway.
+func CountTools(bookmarks []*models.Bookmark) []ToolCount {
+	counts := map[string]int{}
+	for _, bookmark := range bookmarks {
+		counts[bookmark.ToolName]++
+	}
+	return SortToolCounts(counts)
+}
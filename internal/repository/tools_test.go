@@ -0,0 +1,31 @@
+//go:build unit
+// +build unit
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestCountTools(t *testing.T) {
+	bookmarks := []*models.Bookmark{
+		{Command: "a", ToolName: "git"},
+		{Command: "b", ToolName: "docker"},
+		{Command: "c", ToolName: "git"},
+		{Command: "d", ToolName: "curl"},
+	}
+
+	counts := CountTools(bookmarks)
+
+	expected := []ToolCount{{ToolName: "git", Count: 2}, {ToolName: "curl", Count: 1}, {ToolName: "docker", Count: 1}}
+	if len(counts) != len(expected) {
+		t.Fatalf("Expected %d tools, got %v", len(expected), counts)
+	}
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("Expected %v at %d, got %v", expected[i], i, counts[i])
+		}
+	}
+}
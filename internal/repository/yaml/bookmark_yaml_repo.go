@@ -6,28 +6,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync"
 
 	"github.com/fgeck/tools/internal/domain/models"
 	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/memory"
 	"gopkg.in/yaml.v3"
 )
 
 var (
 	// ErrBookmarkNotFound is returned when an example is not found
-	ErrBookmarkNotFound = errors.New("bookmark not found")
+	ErrBookmarkNotFound = fmt.Errorf("bookmark %w", models.ErrNotFound)
 	// ErrBookmarkAlreadyExists is returned when attempting to create a duplicate example
-	ErrBookmarkAlreadyExists = errors.New("example with this command already exists")
+	ErrBookmarkAlreadyExists = fmt.Errorf("example with this command %w", models.ErrAlreadyExists)
+	// ErrReadOnly is returned when writing to a read-only repository
+	ErrReadOnly = fmt.Errorf("%w: storage file is read-only", models.ErrConflict)
 )
 
 // YAMLBookmarkRepository implements BookmarkRepository using YAML file storage
 type YAMLBookmarkRepository struct {
 	filePath string
+	readOnly bool         // Never create or write the file
 	mu       sync.RWMutex // Thread-safe operations
 }
 
 // yamlStorage represents the file structure
 type yamlStorage struct {
+	Version   int               `yaml:"version"`           // SchemaVersion of the layout, see ParseStore
+	Include   []string          `yaml:"include,omitempty"` // Further stores merged read-only, see Includes
 	Bookmarks []models.Bookmark `yaml:"bookmarks"`
 }
 
@@ -43,51 +50,211 @@ func NewYAMLBookmarkRepository(filePath string) (repository.BookmarkRepository,
 		filePath: filePath,
 	}
 
-	// Initialize file if it doesn't exist
+	// Initialize file if it doesn't exist, checking again under the lock in case another
+	// process creates it meanwhile
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		if err := repo.save(&yamlStorage{Bookmarks: []models.Bookmark{}}); err != nil {
+		ctx := context.Background()
+		unlock, err := repo.lock(ctx)
+		if err != nil {
 			return nil, err
 		}
+		defer unlock()
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			if err := repo.save(ctx, &yamlStorage{Bookmarks: []models.Bookmark{}}); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return repo, nil
 }
 
+// NewReadOnlyYAMLBookmarkRepository opens a YAML file without ever writing to it
+// A missing file is treated as an empty store; all write operations return ErrReadOnly
+func NewReadOnlyYAMLBookmarkRepository(filePath string) repository.BookmarkRepository {
+	return &YAMLBookmarkRepository{
+		filePath: filePath,
+		readOnly: true,
+	}
+}
+
 // load reads the YAML file and returns the storage structure
-func (r *YAMLBookmarkRepository) load() (*yamlStorage, error) {
-	data, err := os.ReadFile(r.filePath)
+func (r *YAMLBookmarkRepository) load(ctx context.Context) (*yamlStorage, error) {
+	data, err := readFile(ctx, r.filePath)
+	if r.readOnly && errors.Is(err, os.ErrNotExist) {
+		return &yamlStorage{Bookmarks: []models.Bookmark{}}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read storage file: %w", err)
+		return nil, err
 	}
 
-	var storage yamlStorage
-	if err := yaml.Unmarshal(data, &storage); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	storage, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	// Parsing a large file takes a while; the caller may have given up meanwhile
+	if err := repository.Interrupted(ctx); err != nil {
+		return nil, err
 	}
+	return storage, nil
+}
 
-	return &storage, nil
+// readFile reads the storage file, returning as soon as ctx is done
+// A read blocked e.g. on an unresponsive network mount goes on in the background and its
+// result is dropped.
+func readFile(ctx context.Context, path string) ([]byte, error) {
+	if err := repository.Interrupted(ctx); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := os.ReadFile(path)
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to read storage file: %w", r.err)
+		}
+		return r.data, nil
+	case <-ctx.Done():
+		return nil, repository.Interrupted(ctx)
+	}
 }
 
 // save writes the storage structure to the YAML file
-func (r *YAMLBookmarkRepository) save(storage *yamlStorage) error {
+// The file is replaced atomically; its previous content is kept as backup first, if valid,
+// for Recover. Nothing is written once ctx is done.
+func (r *YAMLBookmarkRepository) save(ctx context.Context, storage *yamlStorage) error {
+	if r.readOnly {
+		return ErrReadOnly
+	}
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	storage.Version = SchemaVersion
 	data, err := yaml.Marshal(storage)
 	if err != nil {
 		return fmt.Errorf("failed to marshal YAML: %w", err)
 	}
 
-	if err := os.WriteFile(r.filePath, data, 0644); err != nil {
+	if previous, err := os.ReadFile(r.filePath); err == nil && validate(previous) == nil {
+		if err := writeFileAtomic(BackupPath(r.filePath), previous); err != nil {
+			return fmt.Errorf("failed to back up storage file: %w", err)
+		}
+	}
+	if err := writeFileAtomic(r.filePath, data); err != nil {
 		return fmt.Errorf("failed to write storage file: %w", err)
 	}
 
 	return nil
 }
 
+// CheckWritable reports whether the storage file could be saved, without changing it
+// Saving replaces the file through a temporary file next to it, so its directory must be
+// writable.
+func (r *YAMLBookmarkRepository) CheckWritable(ctx context.Context) error {
+	if r.readOnly {
+		return ErrReadOnly
+	}
+	if err := repository.Interrupted(ctx); err != nil {
+		return err
+	}
+
+	path := r.filePath
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tools-check-*")
+	if err != nil {
+		return fmt.Errorf("storage directory is not writable: %w", err)
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// lock guards a change of the file against other changes, by this and by other processes,
+// from loading it until it is saved, and returns the function releasing the lock
+// The lock file next to the store is left alone for read-only repositories, which never save.
+func (r *YAMLBookmarkRepository) lock(ctx context.Context) (func(), error) {
+	r.mu.Lock()
+	if r.readOnly {
+		return r.mu.Unlock, nil
+	}
+	unlock, err := lockFile(ctx, r.filePath+".lock")
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		r.mu.Unlock()
+	}, nil
+}
+
+// Batch runs fn with the loaded examples in memory and saves the file once when fn returns
+// nil, so the file holds either all or none of its writes
+func (r *YAMLBookmarkRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	storage, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	examples, err := stage(ctx, storage.Bookmarks, fn)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(examples, storage.Bookmarks) {
+		return nil
+	}
+
+	storage.Bookmarks = examples
+	return r.save(ctx, storage)
+}
+
+// stage runs fn with an in-memory copy of examples and returns them as fn left them
+func stage(ctx context.Context, examples []models.Bookmark, fn func(tx repository.BookmarkRepository) error) ([]models.Bookmark, error) {
+	seed := make([]*models.Bookmark, len(examples))
+	for i := range examples {
+		seed[i] = &examples[i]
+	}
+	tx := memory.NewMemoryBookmarkRepository(seed...)
+	if err := fn(tx); err != nil {
+		return nil, err
+	}
+
+	staged, err := tx.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.Bookmark, len(staged))
+	for i, example := range staged {
+		result[i] = *example
+	}
+	return result, nil
+}
+
 // Create adds a new example to storage
 func (r *YAMLBookmarkRepository) Create(ctx context.Context, example *models.Bookmark) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
@@ -100,7 +267,7 @@ func (r *YAMLBookmarkRepository) Create(ctx context.Context, example *models.Boo
 	}
 
 	storage.Bookmarks = append(storage.Bookmarks, *example)
-	return r.save(storage)
+	return r.save(ctx, storage)
 }
 
 // GetByCommand retrieves an example by its command
@@ -108,7 +275,7 @@ func (r *YAMLBookmarkRepository) GetByCommand(ctx context.Context, command strin
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +294,7 @@ func (r *YAMLBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark,
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -140,12 +307,39 @@ func (r *YAMLBookmarkRepository) List(ctx context.Context) ([]*models.Bookmark,
 	return examples, nil
 }
 
+// ListPage retrieves up to limit examples starting at cursor
+func (r *YAMLBookmarkRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	examples, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Paginate(examples, cursor, limit)
+}
+
+// CountByToolName counts the examples of every tool name without building the example list
+func (r *YAMLBookmarkRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	storage, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for i := range storage.Bookmarks {
+		counts[storage.Bookmarks[i].ToolName]++
+	}
+	return repository.SortToolCounts(counts), nil
+}
+
 // ListByToolName retrieves all examples for a specific tool name
 func (r *YAMLBookmarkRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -162,10 +356,13 @@ func (r *YAMLBookmarkRepository) ListByToolName(ctx context.Context, toolName st
 
 // Update modifies an existing example
 func (r *YAMLBookmarkRepository) Update(ctx context.Context, example *models.Bookmark) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
@@ -173,7 +370,7 @@ func (r *YAMLBookmarkRepository) Update(ctx context.Context, example *models.Boo
 	for i, ex := range storage.Bookmarks {
 		if ex.Command == example.Command {
 			storage.Bookmarks[i] = *example
-			return r.save(storage)
+			return r.save(ctx, storage)
 		}
 	}
 
@@ -182,10 +379,13 @@ func (r *YAMLBookmarkRepository) Update(ctx context.Context, example *models.Boo
 
 // Delete removes an example by command
 func (r *YAMLBookmarkRepository) Delete(ctx context.Context, command string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
@@ -193,7 +393,7 @@ func (r *YAMLBookmarkRepository) Delete(ctx context.Context, command string) err
 	for i, ex := range storage.Bookmarks {
 		if ex.Command == command {
 			storage.Bookmarks = append(storage.Bookmarks[:i], storage.Bookmarks[i+1:]...)
-			return r.save(storage)
+			return r.save(ctx, storage)
 		}
 	}
 
@@ -202,10 +402,13 @@ func (r *YAMLBookmarkRepository) Delete(ctx context.Context, command string) err
 
 // DeleteByToolName removes all examples for a tool name
 func (r *YAMLBookmarkRepository) DeleteByToolName(ctx context.Context, toolName string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
@@ -226,7 +429,7 @@ func (r *YAMLBookmarkRepository) DeleteByToolName(ctx context.Context, toolName
 	}
 
 	storage.Bookmarks = filtered
-	return r.save(storage)
+	return r.save(ctx, storage)
 }
 
 // Exists checks if an example with the given command exists
@@ -234,7 +437,7 @@ func (r *YAMLBookmarkRepository) Exists(ctx context.Context, command string) (bo
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	storage, err := r.load()
+	storage, err := r.load(ctx)
 	if err != nil {
 		return false, err
 	}
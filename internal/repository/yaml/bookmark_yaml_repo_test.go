@@ -5,12 +5,17 @@ package yaml
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
 )
 
 func TestNewYAMLBookmarkRepository(t *testing.T) {
@@ -219,6 +224,27 @@ func TestListByToolName(t *testing.T) {
 	}
 }
 
+func TestCountByToolName(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	repo, _ := NewYAMLBookmarkRepository(filePath)
+
+	ctx := context.Background()
+	repo.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+	repo.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	repo.Create(ctx, &models.Bookmark{Command: "kubectl get nodes", ToolName: "kubectl", Description: "list nodes"})
+
+	counts, err := repo.CountByToolName(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count by tool name: %v", err)
+	}
+
+	expected := []repository.ToolCount{{ToolName: "kubectl", Count: 2}, {ToolName: "docker", Count: 1}}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	tmpDir := t.TempDir()
 	filePath := filepath.Join(tmpDir, "tools.yaml")
@@ -571,3 +597,548 @@ func TestCreateUpdateDeleteCycle(t *testing.T) {
 		t.Error("Expected error after deletion")
 	}
 }
+
+func TestRevisionsPersisted(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	repo, _ := NewYAMLBookmarkRepository(filePath)
+
+	ctx := context.Background()
+	changedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	example := &models.Bookmark{
+		Command:     "kubectl get pods",
+		ToolName:    "kubectl",
+		Description: "new description",
+		Revisions: []models.Revision{
+			{Command: "kubectl get pods", ToolName: "kubectl", Description: "old description", ChangedAt: changedAt},
+		},
+	}
+	if err := repo.Create(ctx, example); err != nil {
+		t.Fatalf("Failed to create example: %v", err)
+	}
+
+	// Reopen to make sure revisions survive a round-trip through the file
+	reopened, _ := NewYAMLBookmarkRepository(filePath)
+	retrieved, err := reopened.GetByCommand(ctx, example.Command)
+	if err != nil {
+		t.Fatalf("Failed to retrieve example: %v", err)
+	}
+
+	if len(retrieved.Revisions) != 1 {
+		t.Fatalf("Expected 1 revision, got %d", len(retrieved.Revisions))
+	}
+	if retrieved.Revisions[0].Description != "old description" || !retrieved.Revisions[0].ChangedAt.Equal(changedAt) {
+		t.Errorf("Unexpected revision: %+v", retrieved.Revisions[0])
+	}
+}
+
+func TestBookmarkWithoutRevisionsOmitsKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tools.yaml")
+	repo, _ := NewYAMLBookmarkRepository(filePath)
+
+	_ = repo.Create(context.Background(), &models.Bookmark{Command: "ls", ToolName: "ls", Description: "list"})
+
+	data, _ := os.ReadFile(filePath)
+	if strings.Contains(string(data), "revisions") {
+		t.Errorf("Bookmarks without revisions should not write a revisions key:\n%s", data)
+	}
+}
+
+func TestReadOnlyRepository(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+
+	t.Run("missing file is empty and not created", func(t *testing.T) {
+		filePath := filepath.Join(tmpDir, "missing", "tools.yaml")
+		repo := NewReadOnlyYAMLBookmarkRepository(filePath)
+
+		examples, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error for missing file, got %v", err)
+		}
+		if len(examples) != 0 {
+			t.Errorf("Expected empty list, got %d", len(examples))
+		}
+		if _, err := os.Stat(filepath.Dir(filePath)); !os.IsNotExist(err) {
+			t.Error("Read-only repository must not create directories")
+		}
+	})
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		filePath := filepath.Join(tmpDir, "system.yaml")
+		content := "bookmarks:\n  - command: ls -la\n    toolname: ls\n    description: list all\n"
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		repo := NewReadOnlyYAMLBookmarkRepository(filePath)
+
+		if _, err := repo.GetByCommand(ctx, "ls -la"); err != nil {
+			t.Errorf("Failed to read bookmark: %v", err)
+		}
+		if err := repo.Create(ctx, &models.Bookmark{Command: "pwd", ToolName: "pwd", Description: "cwd"}); err != ErrReadOnly {
+			t.Errorf("Expected ErrReadOnly on create, got %v", err)
+		}
+		if err := repo.Delete(ctx, "ls -la"); err != ErrReadOnly {
+			t.Errorf("Expected ErrReadOnly on delete, got %v", err)
+		}
+	})
+}
+
+func TestScriptStoredAsBlockScalar(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tools.yaml")
+	repo, err := NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	script := "cat <<EOF > /tmp/motd\n  welcome\nEOF"
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Bookmark{Command: script, ToolName: "cat", Description: "write motd"}); err != nil {
+		t.Fatalf("Failed to create example: %v", err)
+	}
+
+	data, _ := os.ReadFile(filePath)
+	if !strings.Contains(string(data), "command: |-\n") {
+		t.Errorf("Expected literal block scalar, got:\n%s", data)
+	}
+
+	got, err := repo.GetByCommand(ctx, script)
+	if err != nil || got.Command != script {
+		t.Errorf("Script did not round trip: %v, %+v", err, got)
+	}
+}
+
+func TestListPage(t *testing.T) {
+	repo, _ := NewYAMLBookmarkRepository(filepath.Join(t.TempDir(), "tools.yaml"))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_ = repo.Create(ctx, &models.Bookmark{Command: fmt.Sprintf("cmd %d", i), ToolName: "tool", Description: "desc"})
+	}
+
+	page, err := repo.ListPage(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("Failed to list page: %v", err)
+	}
+	if len(page.Bookmarks) != 2 || page.Bookmarks[0].Command != "cmd 0" || page.NextCursor == "" {
+		t.Fatalf("Unexpected first page: %+v", page)
+	}
+
+	page, err = repo.ListPage(ctx, page.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("Failed to list page: %v", err)
+	}
+	if len(page.Bookmarks) != 1 || page.Bookmarks[0].Command != "cmd 2" || page.NextCursor != "" {
+		t.Errorf("Unexpected last page: %+v", page)
+	}
+}
+
+func TestSaveKeepsBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "tools.yaml")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("bookmarks: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The store is often a symlink into a dotfiles repository
+	filePath := filepath.Join(dir, "tools.yaml")
+	if err := os.Symlink(target, filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	_ = repo.Create(ctx, &models.Bookmark{Command: "ls -la", ToolName: "ls", Description: "list"})
+	_ = repo.Create(ctx, &models.Bookmark{Command: "ls -l", ToolName: "ls", Description: "list long"})
+
+	backup, err := NewReadOnlyYAMLBookmarkRepository(BackupPath(filePath)).List(ctx)
+	if err != nil || len(backup) != 1 || backup[0].Command != "ls -la" {
+		t.Errorf("Expected the backup to hold the store before the last save, got %+v: %v", backup, err)
+	}
+	if stat, err := os.Lstat(filePath); err != nil || stat.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected the store to stay a symlink: %v", err)
+	}
+	if stat, err := os.Stat(target); err != nil || stat.Mode().Perm() != 0600 {
+		t.Errorf("Expected the file mode to be kept, got %v: %v", stat.Mode(), err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	ctx := context.Background()
+	valid := "bookmarks:\n  - command: ls -la\n    toolname: ls\n    description: list\n"
+
+	tests := []struct {
+		name      string
+		store     string
+		backup    string // Empty for no backup
+		recovered bool
+		wantErr   bool
+	}{
+		{"valid store", valid, "", false, false},
+		{"invalid yaml", "bookmarks:\n  - command: ls -la\n    tool", valid, true, false},
+		{"truncated to nothing", "", valid, true, false},
+		{"empty without backup", "", "", false, false},
+		{"invalid without backup", "bookmarks: [", "", false, true},
+		{"invalid backup", "bookmarks: [", "bookmarks: [", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "tools.yaml")
+			_ = os.WriteFile(filePath, []byte(tt.store), 0644)
+			if tt.backup != "" {
+				_ = os.WriteFile(BackupPath(filePath), []byte(tt.backup), 0644)
+			}
+
+			recovery, err := Recover(filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Recover() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if (recovery != nil) != tt.recovered {
+				t.Fatalf("Recover() = %+v, want recovered %t", recovery, tt.recovered)
+			}
+			if recovery == nil {
+				return
+			}
+
+			if recovery.Bookmarks != 1 || recovery.Cause == nil {
+				t.Errorf("Unexpected recovery: %+v", recovery)
+			}
+			if damaged, _ := os.ReadFile(recovery.DamagedPath); string(damaged) != tt.store {
+				t.Errorf("Expected the damaged content to be kept, got %q", damaged)
+			}
+			bookmarks, err := NewReadOnlyYAMLBookmarkRepository(filePath).List(ctx)
+			if err != nil || len(bookmarks) != 1 {
+				t.Errorf("Expected the backup to be restored, got %+v: %v", bookmarks, err)
+			}
+		})
+	}
+
+	if recovery, err := Recover(filepath.Join(t.TempDir(), "missing.yaml")); recovery != nil || err != nil {
+		t.Errorf("Expected nothing to recover for a missing store, got %+v: %v", recovery, err)
+	}
+}
+
+func TestSchemaMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []*models.Bookmark
+	}{
+		{
+			name:    "unversioned store",
+			content: "bookmarks:\n  - command: ls -la\n    toolname: ls\n    description: list\n",
+			want:    []*models.Bookmark{{Command: "ls -la", ToolName: "ls", Description: "list"}},
+		},
+		{
+			name:    "legacy examples list",
+			content: "examples:\n  - command: ls -la\n    tool_name: ls\n    description: list\n    tags: [files]\n",
+			want:    []*models.Bookmark{{Command: "ls -la", ToolName: "ls", Description: "list", Tags: []string{"files"}}},
+		},
+		{
+			name: "legacy tools groups",
+			content: `tools:
+  - name: lsof
+    examples:
+      - command: lsof -i :8080
+        description: check port 8080
+  - name: docker
+    examples:
+      - command: docker ps
+        description: list containers
+`,
+			want: []*models.Bookmark{
+				{Command: "lsof -i :8080", ToolName: "lsof", Description: "check port 8080"},
+				{Command: "docker ps", ToolName: "docker", Description: "list containers"},
+			},
+		},
+		{
+			name:    "empty file",
+			content: "",
+			want:    []*models.Bookmark{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "tools.yaml")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			repo, err := NewYAMLBookmarkRepository(filePath)
+			if err != nil {
+				t.Fatalf("Failed to open repository: %v", err)
+			}
+
+			got, err := repo.List(ctx)
+			if err != nil {
+				t.Fatalf("Failed to list bookmarks: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+
+			// The next save writes the current layout
+			if err := repo.Create(ctx, &models.Bookmark{Command: "pwd", ToolName: "pwd", Description: "where am I"}); err != nil {
+				t.Fatalf("Failed to save: %v", err)
+			}
+			data, _ := os.ReadFile(filePath)
+			if !strings.HasPrefix(string(data), fmt.Sprintf("version: %d\nbookmarks:\n", SchemaVersion)) || strings.Contains(string(data), "examples:") {
+				t.Errorf("Expected the upgraded layout, got:\n%s", data)
+			}
+			if got, _ := repo.List(ctx); len(got) != len(tt.want)+1 {
+				t.Errorf("Expected the migrated bookmarks to be kept, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestSchemaVersionTooNew(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tools.yaml")
+	content := fmt.Sprintf("version: %d\nbookmarks: []\n", SchemaVersion+1)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewReadOnlyYAMLBookmarkRepository(filePath).List(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "please upgrade tools") {
+		t.Errorf("Expected an error asking to upgrade, got %v", err)
+	}
+	// A newer store is not damaged, so its backup must not replace it
+	_ = os.WriteFile(BackupPath(filePath), []byte("bookmarks: []\n"), 0644)
+	if recovery, err := Recover(filePath); recovery != nil || !errors.Is(err, ErrNewerVersion) {
+		t.Errorf("Expected a newer store not to be recovered, got %+v: %v", recovery, err)
+	}
+	if data, _ := os.ReadFile(filePath); string(data) != content {
+		t.Errorf("Expected the newer store to be left alone, got:\n%s", data)
+	}
+
+	if _, err := ParseStore([]byte("examples: {}\n")); err == nil {
+		t.Error("Expected an error for a legacy layout that is not a list")
+	}
+}
+
+func TestDetectLegacy(t *testing.T) {
+	data := []byte(`bookmarks:
+  - command: ls -la
+examples:
+  - command: ls -la
+  - command: df -h
+tools:
+  - name: lsof
+    examples:
+      - command: lsof -i :8080
+      - command: df -h
+`)
+	sections, err := DetectLegacy(data)
+	if err != nil {
+		t.Fatalf("Failed to detect legacy sections: %v", err)
+	}
+	want := LegacySections{Examples: 2, Tools: 1, ToolExamples: 2, Duplicates: 2}
+	if sections != want || !sections.Found() {
+		t.Errorf("Expected %+v, got %+v", want, sections)
+	}
+
+	bookmarks, err := ParseStore(data)
+	if err != nil || len(bookmarks) != 3 {
+		t.Errorf("Expected duplicates to be merged into 3 bookmarks, got %+v: %v", bookmarks, err)
+	}
+
+	if sections, _ := DetectLegacy([]byte("version: 1\nbookmarks: []\n")); sections.Found() {
+		t.Errorf("Expected no legacy sections in the current layout, got %+v", sections)
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tools.yaml")
+	repo, err := NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	before, _ := os.ReadFile(filePath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := repo.Create(ctx, &models.Bookmark{Command: "ls -la", ToolName: "ls"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled write to fail with context.Canceled, got %v", err)
+	}
+	if _, err := repo.List(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled read to fail with context.Canceled, got %v", err)
+	}
+	if after, _ := os.ReadFile(filePath); string(after) != string(before) {
+		t.Errorf("Expected a canceled write to leave the file alone, got:\n%s", after)
+	}
+
+	if _, err := repo.List(context.Background()); err != nil {
+		t.Errorf("Expected later operations to work, got %v", err)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewYAMLBookmarkRepository(filepath.Join(dir, "tools.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	// The store and its lock file
+	before, _ := os.ReadDir(dir)
+	if err := repo.(repository.WriteChecker).CheckWritable(ctx); err != nil {
+		t.Errorf("Expected the store to be writable, got %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != len(before) {
+		t.Errorf("Expected the check to leave no files behind, got %d entries instead of %d", len(entries), len(before))
+	}
+
+	readOnly := NewReadOnlyYAMLBookmarkRepository(filepath.Join(dir, "system.yaml"))
+	if err := repository.CheckWritable(ctx, readOnly); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	repo, err := NewYAMLBookmarkRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	stored, _ := os.ReadFile(path)
+
+	// A failed batch leaves the file as it was, even after writes that succeeded
+	err = repository.Batch(ctx, repo, func(tx repository.BookmarkRepository) error {
+		if err := tx.Create(ctx, &models.Bookmark{Command: "helm list", ToolName: "helm"}); err != nil {
+			return err
+		}
+		return tx.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker"})
+	})
+	if !errors.Is(err, models.ErrAlreadyExists) {
+		t.Fatalf("Expected the duplicate to fail the batch, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(stored) {
+		t.Errorf("Expected the file unchanged, got: %s", data)
+	}
+
+	// The writes of a batch see each other and are saved together
+	err = repository.Batch(ctx, repo, func(tx repository.BookmarkRepository) error {
+		if err := tx.Create(ctx, &models.Bookmark{Command: "helm list", ToolName: "helm"}); err != nil {
+			return err
+		}
+		if exists, _ := tx.Exists(ctx, "helm list"); !exists {
+			t.Error("Expected the batch to see its own writes")
+		}
+		return tx.Delete(ctx, "docker ps")
+	})
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	list, _ := repo.List(ctx)
+	if len(list) != 1 || list[0].Command != "helm list" {
+		t.Errorf("Expected only the created bookmark, got %v", list)
+	}
+	if backup, _ := os.ReadFile(BackupPath(path)); string(backup) != string(stored) {
+		t.Errorf("Expected one save backing up the file before the batch, got: %s", backup)
+	}
+}
+
+func TestBatchLocksAcrossRepositories(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tools.yaml")
+	first, err := NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	// A second repository on the same file stands in for another process
+	second, err := NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+
+	inBatch := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- first.(repository.Batcher).Batch(ctx, func(tx repository.BookmarkRepository) error {
+			close(inBatch)
+			<-release
+			return tx.Create(ctx, &models.Bookmark{Command: "ls", ToolName: "ls"})
+		})
+	}()
+	<-inBatch
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = second.Create(waitCtx, &models.Bookmark{Command: "pwd", ToolName: "pwd"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Create during another batch: got %v, want it to wait for the lock", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if err := second.Create(ctx, &models.Bookmark{Command: "pwd", ToolName: "pwd"}); err != nil {
+		t.Fatalf("Create after the batch failed: %v", err)
+	}
+	examples, err := first.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Errorf("Expected the writes of both repositories, got %d examples", len(examples))
+	}
+}
+
+func TestUpgradeWaitsForLock(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tools.yaml")
+	repo, err := NewYAMLBookmarkRepository(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+
+	inBatch := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- repo.(repository.Batcher).Batch(ctx, func(tx repository.BookmarkRepository) error {
+			close(inBatch)
+			<-release
+			return tx.Create(ctx, &models.Bookmark{Command: "ls", ToolName: "ls"})
+		})
+	}()
+	<-inBatch
+
+	upgraded := make(chan error, 1)
+	go func() { upgraded <- Upgrade(filePath) }()
+	select {
+	case err := <-upgraded:
+		t.Fatalf("Expected the upgrade to wait for the batch, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if err := <-upgraded; err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if examples, _ := repo.List(ctx); len(examples) != 1 {
+		t.Errorf("Expected the upgrade to keep the write of the batch, got %d examples", len(examples))
+	}
+}
@@ -0,0 +1,459 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// YAMLDirectoryRepository implements BookmarkRepository using a directory of YAML files,
+// e.g. one per tool or topic in a dotfiles repository
+// The files are merged when loaded; every bookmark is written back to the file it came from.
+// New bookmarks go to the first file holding bookmarks of the same tool, else to a new file
+// named after the tool.
+type YAMLDirectoryRepository struct {
+	dir string
+	mu  sync.RWMutex // Thread-safe operations
+}
+
+// directoryFile is a loaded file of a directory store
+type directoryFile struct {
+	path    string
+	storage *yamlStorage
+}
+
+// NewYAMLDirectoryRepository creates a repository for the YAML files in dir
+func NewYAMLDirectoryRepository(dir string) (repository.BookmarkRepository, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &YAMLDirectoryRepository{dir: dir}, nil
+}
+
+// OpenStore creates a repository for the store at path: a directory of YAML files if path
+// is a directory, else a single file
+func OpenStore(path string) (repository.BookmarkRepository, error) {
+	if IsDirectory(path) {
+		return NewYAMLDirectoryRepository(path)
+	}
+	return NewYAMLBookmarkRepository(path)
+}
+
+// IsDirectory reports whether the store at path is a directory of YAML files
+func IsDirectory(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && stat.IsDir()
+}
+
+// StoreFiles returns the files of the store at path: the YAML files of a directory, sorted
+// by name, or the path itself. Hidden files and subdirectories of a directory are skipped.
+func StoreFiles(path string) ([]string, error) {
+	if !IsDirectory(path) {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read storage directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// load reads and merges all files of the directory
+// A command stored in two files is reported, since an update could only change one of them.
+func (r *YAMLDirectoryRepository) load(ctx context.Context) ([]*directoryFile, error) {
+	paths, err := StoreFiles(r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*directoryFile, 0, len(paths))
+	seen := map[string]string{}
+	for _, path := range paths {
+		storage, err := (&YAMLBookmarkRepository{filePath: path}).load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		for _, bookmark := range storage.Bookmarks {
+			if other, ok := seen[bookmark.Command]; ok {
+				return nil, fmt.Errorf("%w: '%s' is stored in both %s and %s, remove one of them",
+					models.ErrConflict, bookmark.Command, other, filepath.Base(path))
+			}
+			seen[bookmark.Command] = filepath.Base(path)
+		}
+		files = append(files, &directoryFile{path: path, storage: storage})
+	}
+	return files, nil
+}
+
+// save writes one file of the directory, keeping a backup like a single-file store
+func (r *YAMLDirectoryRepository) save(ctx context.Context, file *directoryFile) error {
+	return (&YAMLBookmarkRepository{filePath: file.path}).save(ctx, file.storage)
+}
+
+// find returns the file holding the command and its index there, nil if none does
+func find(files []*directoryFile, command string) (*directoryFile, int) {
+	for _, file := range files {
+		for i := range file.storage.Bookmarks {
+			if file.storage.Bookmarks[i].Command == command {
+				return file, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// unsafeFileChars are replaced in tool names to get a file name
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fileFor returns the file a new bookmark of the tool goes to, which may not exist yet
+func (r *YAMLDirectoryRepository) fileFor(files []*directoryFile, toolName string) *directoryFile {
+	for _, file := range files {
+		for _, bookmark := range file.storage.Bookmarks {
+			if bookmark.ToolName == toolName {
+				return file
+			}
+		}
+	}
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(toolName, "-"), ".-")
+	if name == "" {
+		name = "bookmarks"
+	}
+	path := filepath.Join(r.dir, name+".yaml")
+	for _, file := range files {
+		if file.path == path || file.path == filepath.Join(r.dir, name+".yml") {
+			return file
+		}
+	}
+	return &directoryFile{path: path, storage: &yamlStorage{Bookmarks: []models.Bookmark{}}}
+}
+
+// all returns the bookmarks of all files, in the order of the files
+func all(files []*directoryFile) []*models.Bookmark {
+	var bookmarks []*models.Bookmark
+	for _, file := range files {
+		for i := range file.storage.Bookmarks {
+			bookmarks = append(bookmarks, &file.storage.Bookmarks[i])
+		}
+	}
+	return bookmarks
+}
+
+// CheckWritable reports whether the files of the directory could be saved, without
+// changing them
+func (r *YAMLDirectoryRepository) CheckWritable(ctx context.Context) error {
+	return (&YAMLBookmarkRepository{filePath: filepath.Join(r.dir, "bookmarks.yaml")}).CheckWritable(ctx)
+}
+
+// lock guards a change of the directory against other changes, by this and by other
+// processes, from loading it until it is saved, and returns the function releasing the lock
+// The lock file is hidden, so it is not read as part of the store.
+func (r *YAMLDirectoryRepository) lock(ctx context.Context) (func(), error) {
+	r.mu.Lock()
+	unlock, err := lockFile(ctx, filepath.Join(r.dir, ".tools.lock"))
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		r.mu.Unlock()
+	}, nil
+}
+
+// Batch runs fn with the loaded examples in memory and saves the files it changed when fn
+// returns nil, each example in the file it came from and new ones in the file of their tool
+// Files saved before one that fails to save are written back, so the directory holds either
+// all or none of the writes of fn, unless writing them back fails as well.
+func (r *YAMLDirectoryRepository) Batch(ctx context.Context, fn func(tx repository.BookmarkRepository) error) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	var current []models.Bookmark
+	for _, file := range files {
+		current = append(current, file.storage.Bookmarks...)
+	}
+	examples, err := stage(ctx, current, fn)
+	if err != nil {
+		return err
+	}
+
+	// Examples stay in their files; new ones go where Create would put them
+	staged := make([]*directoryFile, len(files))
+	origin := map[string]*directoryFile{}
+	for i, file := range files {
+		storage := *file.storage
+		storage.Bookmarks = []models.Bookmark{}
+		staged[i] = &directoryFile{path: file.path, storage: &storage}
+		for _, example := range file.storage.Bookmarks {
+			origin[example.Command] = staged[i]
+		}
+	}
+	var added []models.Bookmark
+	for _, example := range examples {
+		if file, ok := origin[example.Command]; ok {
+			file.storage.Bookmarks = append(file.storage.Bookmarks, example)
+		} else {
+			added = append(added, example)
+		}
+	}
+	for _, example := range added {
+		file := r.fileFor(staged, example.ToolName)
+		if !slices.Contains(staged, file) {
+			staged = append(staged, file)
+		}
+		file.storage.Bookmarks = append(file.storage.Bookmarks, example)
+	}
+
+	var saved []*directoryFile
+	for i, file := range staged {
+		if i < len(files) && reflect.DeepEqual(file.storage.Bookmarks, files[i].storage.Bookmarks) {
+			continue
+		}
+		if err := r.save(ctx, file); err != nil {
+			return errors.Join(err, r.revert(files, saved))
+		}
+		saved = append(saved, file)
+	}
+	return nil
+}
+
+// revert writes back the loaded content of the files saved by a failed Batch, and removes
+// the files it created
+func (r *YAMLDirectoryRepository) revert(files, saved []*directoryFile) error {
+	var errs []error
+	for _, file := range saved {
+		i := slices.IndexFunc(files, func(loaded *directoryFile) bool { return loaded.path == file.path })
+		if i < 0 {
+			errs = append(errs, os.Remove(file.path))
+			continue
+		}
+		// The save did its work, so a done context must not stop the revert
+		errs = append(errs, r.save(context.Background(), files[i]))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to undo the changes: %w", err)
+	}
+	return nil
+}
+
+// Create adds a new example to the file of its tool
+func (r *YAMLDirectoryRepository) Create(ctx context.Context, example *models.Bookmark) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	if file, _ := find(files, example.Command); file != nil {
+		return ErrBookmarkAlreadyExists
+	}
+
+	file := r.fileFor(files, example.ToolName)
+	file.storage.Bookmarks = append(file.storage.Bookmarks, *example)
+	return r.save(ctx, file)
+}
+
+// GetByCommand retrieves an example by its command
+func (r *YAMLDirectoryRepository) GetByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	file, i := find(files, command)
+	if file == nil {
+		return nil, ErrBookmarkNotFound
+	}
+	return &file.storage.Bookmarks[i], nil
+}
+
+// List retrieves all examples
+func (r *YAMLDirectoryRepository) List(ctx context.Context) ([]*models.Bookmark, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	examples := all(files)
+	if examples == nil {
+		examples = []*models.Bookmark{}
+	}
+	return examples, nil
+}
+
+// ListPage retrieves up to limit examples starting at cursor
+func (r *YAMLDirectoryRepository) ListPage(ctx context.Context, cursor string, limit int) (*repository.Page, error) {
+	examples, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Paginate(examples, cursor, limit)
+}
+
+// CountByToolName counts the examples of every tool name without building the example list
+func (r *YAMLDirectoryRepository) CountByToolName(ctx context.Context) ([]repository.ToolCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, file := range files {
+		for i := range file.storage.Bookmarks {
+			counts[file.storage.Bookmarks[i].ToolName]++
+		}
+	}
+	return repository.SortToolCounts(counts), nil
+}
+
+// ListByToolName retrieves all examples for a specific tool name
+func (r *YAMLDirectoryRepository) ListByToolName(ctx context.Context, toolName string) ([]*models.Bookmark, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var examples []*models.Bookmark
+	for _, example := range all(files) {
+		if example.ToolName == toolName {
+			examples = append(examples, example)
+		}
+	}
+	return examples, nil
+}
+
+// Update modifies an existing example in the file it is stored in
+func (r *YAMLDirectoryRepository) Update(ctx context.Context, example *models.Bookmark) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	file, i := find(files, example.Command)
+	if file == nil {
+		return ErrBookmarkNotFound
+	}
+	file.storage.Bookmarks[i] = *example
+	return r.save(ctx, file)
+}
+
+// Delete removes an example by command from the file it is stored in
+// The file is kept when it becomes empty.
+func (r *YAMLDirectoryRepository) Delete(ctx context.Context, command string) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	file, i := find(files, command)
+	if file == nil {
+		return ErrBookmarkNotFound
+	}
+	file.storage.Bookmarks = append(file.storage.Bookmarks[:i], file.storage.Bookmarks[i+1:]...)
+	return r.save(ctx, file)
+}
+
+// DeleteByToolName removes all examples for a tool name from every file
+func (r *YAMLDirectoryRepository) DeleteByToolName(ctx context.Context, toolName string) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, file := range files {
+		filtered := []models.Bookmark{}
+		for _, ex := range file.storage.Bookmarks {
+			if ex.ToolName != toolName {
+				filtered = append(filtered, ex)
+			}
+		}
+		if len(filtered) == len(file.storage.Bookmarks) {
+			continue
+		}
+		found = true
+		file.storage.Bookmarks = filtered
+		if err := r.save(ctx, file); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// Exists checks if an example with the given command exists
+func (r *YAMLDirectoryRepository) Exists(ctx context.Context, command string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files, err := r.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	file, _ := find(files, command)
+	return file != nil, nil
+}
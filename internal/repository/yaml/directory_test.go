@@ -0,0 +1,189 @@
+//go:build unit
+// +build unit
+
+package yaml
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+func TestDirectoryRepository(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "k8s.yaml"), []byte(`bookmarks:
+  - command: kubectl get pods
+    toolname: kubectl
+    description: list pods
+  - command: helm list -A
+    toolname: helm
+    description: list releases
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a store file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open directory store: %v", err)
+	}
+	ctx := context.Background()
+
+	// New bookmarks join the file of their tool, or get a file named after it
+	if err := repo.Create(ctx, &models.Bookmark{Command: "kubectl get svc", ToolName: "kubectl", Description: "list services"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list containers"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker"}); !errors.Is(err, models.ErrAlreadyExists) {
+		t.Errorf("Expected a duplicate to be refused, got %v", err)
+	}
+	assertFileCommands(t, filepath.Join(dir, "k8s.yaml"), "kubectl get pods", "helm list -A", "kubectl get svc")
+	assertFileCommands(t, filepath.Join(dir, "docker.yaml"), "docker ps")
+
+	list, err := repo.List(ctx)
+	if err != nil || len(list) != 4 {
+		t.Fatalf("Expected the bookmarks of both files, got %d: %v", len(list), err)
+	}
+	if list[0].Command != "docker ps" {
+		t.Errorf("Expected the files in name order, got %s first", list[0].Command)
+	}
+
+	// Changes are written back to the file the bookmark came from
+	if err := repo.Update(ctx, &models.Bookmark{Command: "helm list -A", ToolName: "helm", Description: "list all releases"}); err != nil {
+		t.Fatalf("Failed to update bookmark: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "k8s.yaml")); !strings.Contains(string(data), "list all releases") {
+		t.Errorf("Expected the update in k8s.yaml, got: %s", data)
+	}
+	if err := repo.DeleteByToolName(ctx, "kubectl"); err != nil {
+		t.Fatalf("Failed to delete bookmarks: %v", err)
+	}
+	assertFileCommands(t, filepath.Join(dir, "k8s.yaml"), "helm list -A")
+	if err := repo.Delete(ctx, "docker ps"); err != nil {
+		t.Fatalf("Failed to delete bookmark: %v", err)
+	}
+	assertFileCommands(t, filepath.Join(dir, "docker.yaml"))
+
+	files, err := StoreFiles(dir)
+	if err != nil || len(files) != 2 {
+		t.Errorf("Expected the two YAML files, got %v: %v", files, err)
+	}
+}
+
+func TestDirectoryRepositoryDuplicateAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("bookmarks:\n  - command: ls -la\n    toolname: ls\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo, _ := NewYAMLDirectoryRepository(dir)
+	_, err := repo.List(context.Background())
+	if !errors.Is(err, models.ErrConflict) || !strings.Contains(err.Error(), "a.yaml and b.yml") {
+		t.Errorf("Expected a conflict naming both files, got %v", err)
+	}
+}
+
+// assertFileCommands checks the commands stored in a file of a directory store, in order
+func TestDirectoryRepositoryBatch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "k8s.yaml"), []byte(`bookmarks:
+  - command: kubectl get pods
+    toolname: kubectl
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to open directory store: %v", err)
+	}
+	ctx := context.Background()
+
+	err = repository.Batch(ctx, repo, func(tx repository.BookmarkRepository) error {
+		for _, bookmark := range []*models.Bookmark{
+			{Command: "kubectl get svc", ToolName: "kubectl"},
+			{Command: "docker ps", ToolName: "docker"},
+			{Command: "docker images", ToolName: "docker"},
+		} {
+			if err := tx.Create(ctx, bookmark); err != nil {
+				return err
+			}
+		}
+		return errors.New("canceled")
+	})
+	if err == nil {
+		t.Fatal("Expected the batch to fail")
+	}
+	assertFileCommands(t, filepath.Join(dir, "k8s.yaml"), "kubectl get pods")
+	if _, err := os.Stat(filepath.Join(dir, "docker.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no file for the failed batch, got %v", err)
+	}
+
+	// New bookmarks go where Create would put them, one new file per tool
+	err = repository.Batch(ctx, repo, func(tx repository.BookmarkRepository) error {
+		for _, bookmark := range []*models.Bookmark{
+			{Command: "kubectl get svc", ToolName: "kubectl"},
+			{Command: "docker ps", ToolName: "docker"},
+			{Command: "docker images", ToolName: "docker"},
+		} {
+			if err := tx.Create(ctx, bookmark); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	assertFileCommands(t, filepath.Join(dir, "k8s.yaml"), "kubectl get pods", "kubectl get svc")
+	assertFileCommands(t, filepath.Join(dir, "docker.yaml"), "docker ps", "docker images")
+}
+
+func assertFileCommands(t *testing.T, path string, commands ...string) {
+	t.Helper()
+	bookmarks, err := (&YAMLBookmarkRepository{filePath: path}).List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	var got []string
+	for _, bookmark := range bookmarks {
+		got = append(got, bookmark.Command)
+	}
+	if strings.Join(got, "|") != strings.Join(commands, "|") {
+		t.Errorf("Expected %v in %s, got %v", commands, filepath.Base(path), got)
+	}
+}
+
+func TestDirectoryRepositoryLockFile(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewYAMLDirectoryRepository(dir)
+	if err != nil {
+		t.Fatalf("NewYAMLDirectoryRepository failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".tools.lock")); err != nil {
+		t.Errorf("Expected the lock file in the directory: %v", err)
+	}
+	files, err := StoreFiles(dir)
+	if err != nil {
+		t.Fatalf("StoreFiles failed: %v", err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "docker.yaml" {
+		t.Errorf("Expected only docker.yaml as store file, got %v", files)
+	}
+}
@@ -0,0 +1,46 @@
+package yaml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Includes returns the stores a store file includes with its include key, in order:
+//
+//	include: [~/work-tools.yaml, ~/team/shared.yaml]
+//
+// Paths starting with ~ are in the home directory, relative paths next to the store file.
+// The included stores are merged read-only, later ones overriding earlier ones, while the
+// store file itself overrides all of them and receives all writes. Includes of included
+// stores are not followed. A directory store and a missing file include nothing.
+func Includes(filePath string) ([]string, error) {
+	if IsDirectory(filePath) {
+		return nil, nil
+	}
+	storage, err := (&YAMLBookmarkRepository{filePath: filePath, readOnly: true}).load(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(storage.Include))
+	for _, path := range storage.Include {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(filePath), path)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
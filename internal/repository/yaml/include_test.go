@@ -0,0 +1,33 @@
+//go:build unit
+// +build unit
+
+package yaml
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIncludes(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", filepath.Join(dir, "home"))
+	filePath := filepath.Join(dir, "tools.yaml")
+	if err := os.WriteFile(filePath, []byte("include:\n  - ~/work.yaml\n  - team/shared.yaml\n  - /etc/tools/extra.yaml\nbookmarks: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	includes, err := Includes(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read includes: %v", err)
+	}
+	expected := []string{filepath.Join(dir, "home", "work.yaml"), filepath.Join(dir, "team", "shared.yaml"), "/etc/tools/extra.yaml"}
+	if !reflect.DeepEqual(includes, expected) {
+		t.Errorf("Expected %v, got %v", expected, includes)
+	}
+
+	if includes, err := Includes(filepath.Join(dir, "missing.yaml")); err != nil || len(includes) != 0 {
+		t.Errorf("Expected no includes of a missing store, got %v: %v", includes, err)
+	}
+}
@@ -0,0 +1,49 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// lockRetry is how long lockFile waits before trying again to take a lock held elsewhere
+const lockRetry = 10 * time.Millisecond
+
+// errLocked is returned by tryLock while another process holds the lock
+var errLocked = errors.New("lock is held by another process")
+
+// lockFile takes an exclusive advisory lock on the file at path, shared by all processes
+// using the store, and returns the function releasing it
+// The file is created if missing and kept afterwards. Waiting for the lock ends when ctx is
+// done.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	for {
+		err := tryLock(file)
+		if err == nil {
+			return func() {
+				_ = unlock(file)
+				file.Close()
+			}, nil
+		}
+		if !errors.Is(err, errLocked) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+
+		select {
+		case <-time.After(lockRetry):
+		case <-ctx.Done():
+			file.Close()
+			return nil, repository.Interrupted(ctx)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package yaml
+
+import "os"
+
+// tryLock does nothing on platforms without file locks; the store is then only guarded
+// within one process
+func tryLock(*os.File) error {
+	return nil
+}
+
+// unlock does nothing, see tryLock
+func unlock(*os.File) error {
+	return nil
+}
@@ -0,0 +1,158 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// BackupPath returns the path of the backup kept of a store file
+// Every save copies the previous, valid content of the store there first.
+func BackupPath(filePath string) string {
+	return filePath + ".bak"
+}
+
+// Recovery describes a damaged store file that was replaced by its backup
+type Recovery struct {
+	Path        string    // The store file
+	DamagedPath string    // Copy of the damaged content, for manual inspection
+	BackupTime  time.Time // When the restored backup was written
+	Bookmarks   int       // Number of bookmarks restored
+	Cause       error     // Why the store file was considered damaged
+}
+
+// Recover checks that a store file is valid and restores its backup if it is not, e.g. after
+// a crash while it was written. It returns nil when there was nothing to recover, and an error
+// when the file is damaged and has no valid backup either.
+//
+// An empty file counts as damaged when a backup exists, since saving always writes at least
+// the empty list of bookmarks.
+func Recover(filePath string) (*Recovery, error) {
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read storage file: %w", err)
+	}
+
+	cause := validate(data)
+	if cause == nil {
+		return nil, nil
+	}
+	if errors.Is(cause, ErrNewerVersion) {
+		// Written by a newer release, not damaged: restoring the backup would lose its changes
+		return nil, fmt.Errorf("storage file %s: %w", filePath, cause)
+	}
+
+	backupPath := BackupPath(filePath)
+	backup, err := os.ReadFile(backupPath)
+	if err != nil || validate(backup) != nil {
+		if len(bytes.TrimSpace(data)) == 0 {
+			// Without a backup, an empty file is just an empty store
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage file %s is damaged (%v) and has no valid backup in %s; fix or remove it", filePath, cause, backupPath)
+	}
+
+	recovery := &Recovery{Path: filePath, DamagedPath: filePath + ".damaged", Cause: cause}
+	if stat, err := os.Stat(backupPath); err == nil {
+		recovery.BackupTime = stat.ModTime()
+	}
+	if storage, err := parse(backup); err == nil {
+		recovery.Bookmarks = len(storage.Bookmarks)
+	}
+
+	if err := writeFileAtomic(recovery.DamagedPath, data); err != nil {
+		return nil, fmt.Errorf("failed to keep damaged storage file: %w", err)
+	}
+	if err := writeFileAtomic(filePath, backup); err != nil {
+		return nil, fmt.Errorf("failed to restore backup: %w", err)
+	}
+	return recovery, nil
+}
+
+// RecoverStore recovers the store at path like Recover: the file, or every file of a
+// directory store. It returns the recoveries made.
+func RecoverStore(path string) ([]*Recovery, error) {
+	files, err := StoreFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var recoveries []*Recovery
+	for _, file := range files {
+		recovery, err := Recover(file)
+		if err != nil {
+			return recoveries, err
+		}
+		if recovery != nil {
+			recoveries = append(recoveries, recovery)
+		}
+	}
+	return recoveries, nil
+}
+
+// validate reports why the content of a store file cannot be loaded, nil if it can
+func validate(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.New("the file is empty")
+	}
+	_, err := parse(data)
+	return err
+}
+
+// writeFileAtomic replaces a file with data, so it is never left half-written
+// A symlinked file is replaced at its target, keeping the link; the file mode is kept. Data
+// and rename are synced to disk, so a power loss leaves the old or the new content.
+func writeFileAtomic(path string, data []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0644)
+	if stat, err := os.Stat(path); err == nil {
+		mode = stat.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tools-store-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir flushes the entries of a directory to disk, e.g. a file renamed into it
+// Windows cannot open directories as files and needs no such sync.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,241 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the storage file layout written by this release
+// Files without a version header are version 0.
+const SchemaVersion = 1
+
+// ErrNewerVersion is returned for storage files written by a newer release
+var ErrNewerVersion = fmt.Errorf("%w: storage file version is newer than supported", models.ErrConflict)
+
+// migration upgrades a decoded storage file of version n to version n+1
+type migration func(doc map[string]any) error
+
+// migrations lists the upgrades in order; migrations[n] turns version n into n+1
+var migrations = []migration{
+	migrateLegacyLayouts,
+}
+
+// ParseStore decodes the content of a storage file of any supported version, upgrading
+// older layouts to the current one
+func ParseStore(data []byte) ([]*models.Bookmark, error) {
+	storage, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks := make([]*models.Bookmark, len(storage.Bookmarks))
+	for i := range storage.Bookmarks {
+		bookmarks[i] = &storage.Bookmarks[i]
+	}
+	return bookmarks, nil
+}
+
+// parse decodes a storage file, running the migrations from its version on
+func parse(data []byte) (*yamlStorage, error) {
+	var header struct {
+		Version int `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if header.Version > SchemaVersion {
+		return nil, fmt.Errorf("%w (%d > %d), please upgrade tools", ErrNewerVersion, header.Version, SchemaVersion)
+	}
+	if header.Version < 0 {
+		return nil, fmt.Errorf("invalid storage file version %d", header.Version)
+	}
+
+	if header.Version < SchemaVersion {
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+		for version := header.Version; version < SchemaVersion; version++ {
+			if err := migrations[version](doc); err != nil {
+				return nil, fmt.Errorf("failed to upgrade storage file from version %d: %w", version, err)
+			}
+		}
+		doc["version"] = SchemaVersion
+
+		var err error
+		if data, err = yaml.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("failed to upgrade storage file: %w", err)
+		}
+	}
+
+	var storage yamlStorage
+	if err := yaml.Unmarshal(data, &storage); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if storage.Bookmarks == nil {
+		storage.Bookmarks = []models.Bookmark{}
+	}
+	return &storage, nil
+}
+
+// migrateLegacyLayouts upgrades version 0, which also covers the layouts of the tools this
+// one replaced: a flat list under "examples", and examples grouped under "tools":
+//
+//	tools:
+//	  - name: lsof
+//	    examples:
+//	      - command: lsof -i :8080
+//	        description: check port 8080
+//
+// Their entries are appended to "bookmarks", except for commands stored already; a
+// "tool_name" key is read as the tool name.
+func migrateLegacyLayouts(doc map[string]any) error {
+	bookmarks, sections, err := legacyEntries(doc)
+	if err != nil {
+		return err
+	}
+
+	list := make([]any, 0, len(bookmarks))
+	seen := map[any]bool{}
+	for _, bookmark := range bookmarks {
+		if seen[bookmark["command"]] {
+			continue
+		}
+		seen[bookmark["command"]] = true
+
+		if toolName, ok := bookmark["tool_name"]; ok {
+			if _, ok := bookmark["toolname"]; !ok {
+				bookmark["toolname"] = toolName
+			}
+			delete(bookmark, "tool_name")
+		}
+		list = append(list, bookmark)
+	}
+
+	if sections.Found() {
+		delete(doc, "examples")
+		delete(doc, "tools")
+	}
+	doc["bookmarks"] = list
+	return nil
+}
+
+// LegacySections counts the entries found in the legacy layouts of a storage file
+type LegacySections struct {
+	Examples     int // Entries of the "examples" list
+	Tools        int // Tool groups under "tools"
+	ToolExamples int // Entries of all tool groups
+	Duplicates   int // Legacy entries whose command is stored earlier in the file, dropped on upgrade
+}
+
+// Found reports whether the file has any legacy section
+func (l LegacySections) Found() bool {
+	return l.Examples > 0 || l.Tools > 0
+}
+
+// DetectLegacy reports the legacy sections of the content of a storage file
+func DetectLegacy(data []byte) (LegacySections, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return LegacySections{}, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	_, sections, err := legacyEntries(doc)
+	return sections, err
+}
+
+// legacyEntries returns the entries of "bookmarks" followed by those of the legacy
+// sections, in the current layout except for the key of the tool name
+func legacyEntries(doc map[string]any) ([]map[string]any, LegacySections, error) {
+	var sections LegacySections
+	bookmarks, err := entries(doc["bookmarks"], "bookmarks")
+	if err != nil {
+		return nil, sections, err
+	}
+	seen := map[any]bool{}
+	for _, bookmark := range bookmarks {
+		seen[bookmark["command"]] = true
+	}
+	addLegacy := func(legacy []map[string]any) {
+		for _, entry := range legacy {
+			if seen[entry["command"]] {
+				sections.Duplicates++
+			}
+			seen[entry["command"]] = true
+			bookmarks = append(bookmarks, entry)
+		}
+	}
+
+	examples, err := entries(doc["examples"], "examples")
+	if err != nil {
+		return nil, sections, err
+	}
+	sections.Examples = len(examples)
+	addLegacy(examples)
+
+	tools, err := entries(doc["tools"], "tools")
+	if err != nil {
+		return nil, sections, err
+	}
+	sections.Tools = len(tools)
+	for _, tool := range tools {
+		name, _ := tool["name"].(string)
+		examples, err := entries(tool["examples"], "tools."+name+".examples")
+		if err != nil {
+			return nil, sections, err
+		}
+		for _, example := range examples {
+			if _, ok := example["toolname"]; !ok {
+				if _, ok := example["tool_name"]; !ok {
+					example["toolname"] = name
+				}
+			}
+		}
+		sections.ToolExamples += len(examples)
+		addLegacy(examples)
+	}
+
+	return bookmarks, sections, nil
+}
+
+// Upgrade rewrites a storage file in the current layout, keeping its previous content as
+// backup like every save; it holds the lock of the store meanwhile
+func Upgrade(filePath string) error {
+	repo := &YAMLBookmarkRepository{filePath: filePath}
+	ctx := context.Background()
+	unlock, err := repo.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	storage, err := repo.load(ctx)
+	if err != nil {
+		return err
+	}
+	return repo.save(ctx, storage)
+}
+
+// entries returns the mappings of a decoded YAML list, none for a missing key
+func entries(value any, key string) ([]map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a list", key)
+	}
+	result := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("'%s' contains an entry that is not a mapping", key)
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
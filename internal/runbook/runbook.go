@@ -0,0 +1,147 @@
+// Package runbook stores runbooks, ordered sequences of bookmarked commands run step by step
+package runbook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"gopkg.in/yaml.v3"
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Runbook is a named sequence of bookmarks
+type Runbook struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Steps       []string `yaml:"steps"` // Commands of the bookmarks, in order
+}
+
+// file is the layout of the runbooks file
+type file struct {
+	Runbooks []Runbook `yaml:"runbooks"`
+}
+
+// Store keeps runbooks in a YAML file; the file is created on first save
+type Store struct {
+	path string
+}
+
+// NewStore creates a store for the runbooks in the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// List returns all runbooks sorted by name
+func (s *Store) List() ([]Runbook, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(f.Runbooks, func(a, b Runbook) int { return strings.Compare(a.Name, b.Name) })
+	return f.Runbooks, nil
+}
+
+// Get returns the runbook with the given name
+func (s *Store) Get(name string) (*Runbook, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(f.Runbooks, func(r Runbook) bool { return r.Name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: runbook '%s' does not exist", models.ErrNotFound, name)
+	}
+	return &f.Runbooks[i], nil
+}
+
+// Save adds a runbook, or replaces the runbook of the same name if replace is set
+func (s *Store) Save(runbook Runbook, replace bool) error {
+	if !validName.MatchString(runbook.Name) {
+		return fmt.Errorf("%w: invalid runbook name '%s' (use letters, digits, '.', '_' and '-')", models.ErrValidation, runbook.Name)
+	}
+	if len(runbook.Steps) == 0 {
+		return fmt.Errorf("%w: runbook '%s' needs at least one step", models.ErrValidation, runbook.Name)
+	}
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(f.Runbooks, func(r Runbook) bool { return r.Name == runbook.Name })
+	switch {
+	case i < 0:
+		f.Runbooks = append(f.Runbooks, runbook)
+	case replace:
+		f.Runbooks[i] = runbook
+	default:
+		return fmt.Errorf("%w: runbook '%s' already exists", models.ErrAlreadyExists, runbook.Name)
+	}
+	return s.write(f)
+}
+
+// Delete removes the runbook with the given name
+func (s *Store) Delete(name string) error {
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(f.Runbooks, func(r Runbook) bool { return r.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: runbook '%s' does not exist", models.ErrNotFound, name)
+	}
+	f.Runbooks = slices.Delete(f.Runbooks, i, i+1)
+	return s.write(f)
+}
+
+// read loads the runbooks file; a missing file holds no runbooks
+func (s *Store) read() (*file, error) {
+	f := &file{Runbooks: []Runbook{}}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runbooks: %w", err)
+	}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse runbooks file %s: %w", s.path, err)
+	}
+	return f, nil
+}
+
+// write replaces the runbooks file atomically, so it is never left half-written
+func (s *Store) write(f *file) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal runbooks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create runbooks directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".runbooks-*")
+	if err != nil {
+		return fmt.Errorf("failed to write runbooks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write runbooks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write runbooks: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write runbooks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write runbooks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build unit
+// +build unit
+
+package runbook
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestSaveGetAndDelete(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "runbooks.yaml"))
+
+	deploy := Runbook{Name: "deploy", Description: "ship it", Steps: []string{"make build", "make deploy"}}
+	if err := s.Save(deploy, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := s.Save(Runbook{Name: "cleanup", Steps: []string{"docker system prune"}}, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := s.Get("deploy")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, deploy) {
+		t.Errorf("Expected %+v, got %+v", deploy, *got)
+	}
+
+	runbooks, _ := s.List()
+	if len(runbooks) != 2 || runbooks[0].Name != "cleanup" {
+		t.Errorf("Expected runbooks sorted by name, got %+v", runbooks)
+	}
+
+	if err := s.Save(deploy, false); !errors.Is(err, models.ErrAlreadyExists) {
+		t.Errorf("Expected conflict for an existing runbook, got %v", err)
+	}
+	deploy.Steps = []string{"make release"}
+	if err := s.Save(deploy, true); err != nil {
+		t.Fatalf("Save() with replace error = %v", err)
+	}
+	if got, _ := s.Get("deploy"); !reflect.DeepEqual(got.Steps, []string{"make release"}) {
+		t.Errorf("Expected the replaced steps, got %v", got.Steps)
+	}
+
+	if err := s.Delete("deploy"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get("deploy"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found after delete, got %v", err)
+	}
+	if err := s.Delete("deploy"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found for a second delete, got %v", err)
+	}
+}
+
+func TestSaveValidates(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "runbooks.yaml"))
+
+	tests := []Runbook{
+		{Name: "../deploy", Steps: []string{"make"}},
+		{Name: "", Steps: []string{"make"}},
+		{Name: "deploy"},
+	}
+	for _, r := range tests {
+		if err := s.Save(r, false); !errors.Is(err, models.ErrValidation) {
+			t.Errorf("Expected validation error for %+v, got %v", r, err)
+		}
+	}
+}
+
+func TestListWithoutFile(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "missing", "runbooks.yaml"))
+
+	runbooks, err := s.List()
+	if err != nil || len(runbooks) != 0 {
+		t.Errorf("Expected no runbooks, got %v, %v", runbooks, err)
+	}
+}
@@ -0,0 +1,182 @@
+// Package runlog keeps the captured output of executed bookmarks, one directory per run
+package runlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+// idFormat is the layout of run IDs; runs started within the same second get a suffix
+const idFormat = "20060102-150405"
+
+const (
+	metaFile   = "run.json"
+	outputFile = "output.log"
+)
+
+var validID = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}(-[0-9]+)?$`)
+
+// Run is a captured execution of a command
+type Run struct {
+	ID       string        `json:"id"`
+	Command  string        `json:"command"`
+	Started  time.Time     `json:"started"`
+	ExitCode *int          `json:"exit_code,omitempty"` // Nil while running or if the run was not finished
+	Duration time.Duration `json:"duration,omitempty"`
+	Dir      string        `json:"-"`
+}
+
+// OutputPath returns the path of the captured stdout and stderr of the run
+func (r Run) OutputPath() string {
+	return filepath.Join(r.Dir, outputFile)
+}
+
+// Manager captures runs in a directory and lists them
+type Manager struct {
+	dir  string
+	keep int
+	now  func() time.Time
+}
+
+// NewManager creates a manager for the runs in dir that keeps the latest keep runs
+// A keep of 0 keeps all runs.
+func NewManager(dir string, keep int) *Manager {
+	return &Manager{dir: dir, keep: keep, now: time.Now}
+}
+
+// Start creates the directory of a new run and opens its output log for writing
+// The caller closes the file and calls Finish once the command has exited.
+func (m *Manager) Start(command string) (*Run, *os.File, error) {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	started := m.now()
+	base := started.Format(idFormat)
+	run := &Run{ID: base, Command: command, Started: started}
+	for i := 2; ; i++ {
+		run.Dir = filepath.Join(m.dir, run.ID)
+		err := os.Mkdir(run.Dir, 0755)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, nil, fmt.Errorf("failed to create run directory: %w", err)
+		}
+		run.ID = base + "-" + strconv.Itoa(i)
+	}
+
+	if err := writeMeta(run); err != nil {
+		return nil, nil, err
+	}
+	output, err := os.Create(run.OutputPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output log: %w", err)
+	}
+	return run, output, nil
+}
+
+// Finish records the result of a run and removes the oldest runs beyond the limit
+func (m *Manager) Finish(run *Run, exitCode int, duration time.Duration) error {
+	run.ExitCode = &exitCode
+	run.Duration = duration
+	if err := writeMeta(run); err != nil {
+		return err
+	}
+	return m.prune()
+}
+
+// List returns all runs, oldest first
+func (m *Manager) List() ([]Run, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return []Run{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	runs := []Run{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !validID.MatchString(entry.Name()) {
+			continue
+		}
+		run, err := readMeta(filepath.Join(m.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	slices.SortFunc(runs, func(a, b Run) int {
+		if c := a.Started.Compare(b.Started); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return runs, nil
+}
+
+// Get returns the run with the given ID
+func (m *Manager) Get(id string) (*Run, error) {
+	if !validID.MatchString(id) {
+		return nil, fmt.Errorf("%w: invalid run id '%s' (expected e.g. 20261016-153000)", models.ErrValidation, id)
+	}
+	dir := filepath.Join(m.dir, id)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: run '%s' does not exist", models.ErrNotFound, id)
+	}
+	return readMeta(dir)
+}
+
+// prune removes the oldest runs so that at most keep runs are left
+func (m *Manager) prune() error {
+	if m.keep <= 0 {
+		return nil
+	}
+	runs, err := m.List()
+	if err != nil {
+		return err
+	}
+	for len(runs) > m.keep {
+		if err := os.RemoveAll(runs[0].Dir); err != nil {
+			return fmt.Errorf("failed to remove old run: %w", err)
+		}
+		runs = runs[1:]
+	}
+	return nil
+}
+
+func writeMeta(run *Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(run.Dir, metaFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run: %w", err)
+	}
+	return nil
+}
+
+func readMeta(dir string) (*Run, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run: %w", err)
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse run %s: %w", filepath.Base(dir), err)
+	}
+	run.Dir = dir
+	return &run, nil
+}
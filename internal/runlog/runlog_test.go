@@ -0,0 +1,105 @@
+//go:build unit
+// +build unit
+
+package runlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+func TestStartAndFinish(t *testing.T) {
+	m := NewManager(filepath.Join(t.TempDir(), "runs"), 0)
+	m.now = func() time.Time { return time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC) }
+
+	run, output, err := m.Start("make test")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if run.ID != "20261016-153000" {
+		t.Errorf("Expected the run to be named after its start, got %s", run.ID)
+	}
+	_, _ = output.WriteString("ok\n")
+	_ = output.Close()
+
+	// Unfinished runs have no result yet
+	got, err := m.Get(run.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.ExitCode != nil || got.Command != "make test" {
+		t.Errorf("Unexpected unfinished run: %+v", got)
+	}
+
+	if err := m.Finish(run, 2, 1500*time.Millisecond); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	got, _ = m.Get(run.ID)
+	if got.ExitCode == nil || *got.ExitCode != 2 || got.Duration != 1500*time.Millisecond {
+		t.Errorf("Expected the result to be recorded, got %+v", got)
+	}
+	data, _ := os.ReadFile(got.OutputPath())
+	if string(data) != "ok\n" {
+		t.Errorf("Expected the captured output, got %q", data)
+	}
+
+	// A second run in the same second gets a suffix
+	second, output, err := m.Start("make lint")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	_ = output.Close()
+	if second.ID != "20261016-153000-2" {
+		t.Errorf("Expected a suffixed ID, got %s", second.ID)
+	}
+}
+
+func TestFinishKeepsLatestRuns(t *testing.T) {
+	m := NewManager(t.TempDir(), 2)
+	start := time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC)
+
+	for i := range 3 {
+		m.now = func() time.Time { return start.Add(time.Duration(i) * time.Minute) }
+		run, output, err := m.Start("make test")
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		_ = output.Close()
+		if err := m.Finish(run, 0, time.Second); err != nil {
+			t.Fatalf("Finish() error = %v", err)
+		}
+	}
+
+	runs, err := m.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].ID != "20261016-153100" || runs[1].ID != "20261016-153200" {
+		t.Errorf("Expected the two latest runs, got %+v", runs)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	m := NewManager(t.TempDir(), 0)
+
+	if _, err := m.Get("../etc"); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("Expected validation error for an invalid ID, got %v", err)
+	}
+	if _, err := m.Get("20261016-153000"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestListWithoutDirectory(t *testing.T) {
+	m := NewManager(filepath.Join(t.TempDir(), "missing"), 0)
+
+	runs, err := m.List()
+	if err != nil || len(runs) != 0 {
+		t.Errorf("Expected no runs, got %v, %v", runs, err)
+	}
+}
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fgeck/tools/internal/utils"
+)
+
+// posixShells can interpret scripts written for sh
+var posixShells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "ksh": true, "dash": true,
+}
+
+// Shell returns the user's shell if it is POSIX compatible, /bin/sh otherwise
+func Shell() string {
+	shell := os.Getenv("SHELL")
+	if shell == "" || !posixShells[filepath.Base(shell)] {
+		return "/bin/sh"
+	}
+	return shell
+}
+
+// IsScript reports whether a command spans multiple lines
+func IsScript(command string) bool {
+	return strings.Contains(command, "\n")
+}
+
+// SSHCommand wraps a command so that it runs on a remote host with ssh
+// The command is quoted as a single word, so it is interpreted by the remote shell only.
+func SSHCommand(host, command string) string {
+	return "ssh " + utils.ShellQuote(host) + " " + utils.ShellQuote(command)
+}
+
+// WriteScript writes a command to an executable temporary file and returns its path
+// Scripts without a shebang line are run by Shell(). The caller removes the file.
+func WriteScript(command string) (string, error) {
+	f, err := os.CreateTemp("", "tools-*.sh")
+	if err != nil {
+		return "", fmt.Errorf("failed to create script file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	content := command
+	if !strings.HasPrefix(content, "#!") {
+		content = "#!" + Shell() + "\n" + content
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write script file: %w", err)
+	}
+	if err := f.Chmod(0700); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to make script executable: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// Run executes a command with the given standard streams and waits for it
+// Single-line commands run with "Shell() -c"; multi-line commands are written to a
+// temporary script first, which is removed afterwards.
+func Run(ctx context.Context, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var cmd *exec.Cmd
+	if IsScript(command) {
+		path, err := WriteScript(command)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = os.Remove(path) }()
+		cmd = exec.CommandContext(ctx, path)
+	} else {
+		cmd = exec.CommandContext(ctx, Shell(), "-c", command)
+	}
+
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Result is the outcome of a command that ran to completion
+type Result struct {
+	ExitCode int // 128 plus the signal number if the command was killed by a signal
+	Duration time.Duration
+}
+
+// RunTimed runs a command like Run and reports its exit code and duration
+// A non-zero exit code is not an error; the error is only set if the command could not
+// be run. Interrupts (Ctrl+C) reach the command but not the caller, so the caller can
+// still record the result of an interrupted command.
+func RunTimed(ctx context.Context, command string, stdin io.Reader, stdout, stderr io.Writer) (Result, error) {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	start := time.Now()
+	err := Run(ctx, command, stdin, stdout, stderr)
+	result := Result{Duration: time.Since(start)}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			result.ExitCode = 128 + int(status.Signal())
+		}
+		return result, nil
+	}
+	return result, err
+}
+
+// OpenURL opens a URL in the default browser without waiting for it
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", url, err)
+	}
+	// Reap the opener in the background; browsers detach from it
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
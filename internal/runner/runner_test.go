@@ -0,0 +1,120 @@
+//go:build unit
+// +build unit
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWriteScript(t *testing.T) {
+	t.Setenv("SHELL", "/usr/bin/fish")
+
+	path, err := WriteScript("echo one\necho two")
+	if err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	defer func() { _ = os.Remove(path) }()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "#!/bin/sh\necho one\necho two\n" {
+		t.Errorf("Unexpected script content: %q", data)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Script should be executable, got mode %v", info.Mode())
+	}
+}
+
+func TestWriteScriptKeepsShebang(t *testing.T) {
+	path, err := WriteScript("#!/usr/bin/env python3\nprint('hi')\n")
+	if err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	defer func() { _ = os.Remove(path) }()
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "#!/usr/bin/env python3\n") || strings.Count(string(data), "#!") != 1 {
+		t.Errorf("Shebang should be kept as is, got %q", data)
+	}
+}
+
+func TestSSHCommand(t *testing.T) {
+	got := SSHCommand("admin@prod-db", "psql -c 'select 1' && echo $HOSTNAME")
+	want := `ssh admin@prod-db 'psql -c '\''select 1'\'' && echo $HOSTNAME'`
+	if got != want {
+		t.Errorf("SSHCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("SHELL", "/bin/sh")
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		command  string
+		expected string
+		exitCode int
+	}{
+		{"single line", "echo hello | tr a-z A-Z", "HELLO\n", 0},
+		{"script", "for i in 1 2; do\n\techo $i\ndone", "1\n2\n", 0},
+		{"heredoc", "cat <<EOF\nline one\nEOF", "line one\n", 0},
+		{"failing", "echo partial\nexit 3", "partial\n", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := Run(ctx, tt.command, nil, &stdout, &stdout)
+
+			var exitErr *exec.ExitError
+			switch {
+			case tt.exitCode == 0 && err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case tt.exitCode != 0 && (!errors.As(err, &exitErr) || exitErr.ExitCode() != tt.exitCode):
+				t.Fatalf("Expected exit code %d, got %v", tt.exitCode, err)
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunTimed(t *testing.T) {
+	t.Setenv("SHELL", "/bin/sh")
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		command  string
+		exitCode int
+	}{
+		{"success", "true", 0},
+		{"failing", "exit 3", 3},
+		{"killed", "kill -TERM $$", 128 + 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RunTimed(ctx, tt.command, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.ExitCode != tt.exitCode {
+				t.Errorf("Expected exit code %d, got %d", tt.exitCode, result.ExitCode)
+			}
+			if result.Duration <= 0 {
+				t.Errorf("Expected a duration, got %v", result.Duration)
+			}
+		})
+	}
+}
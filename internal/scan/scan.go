@@ -0,0 +1,189 @@
+// Package scan finds common commands of a project in its compose files, Makefiles and Justfiles
+package scan
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fgeck/tools/internal/recipes"
+	"github.com/fgeck/tools/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// maxDepth limits how many directories below the project root are scanned
+const maxDepth = 3
+
+// Proposal is a command suggested by a project file
+type Proposal struct {
+	Command     string
+	ToolName    string
+	Description string
+	Source      string // Path of the file relative to the scanned directory
+}
+
+var (
+	composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+	makefiles    = []string{"GNUmakefile", "makefile", "Makefile"}
+	justfiles    = []string{"justfile", "Justfile", ".justfile"}
+	skippedDirs  = []string{"node_modules", "vendor", "testdata"}
+)
+
+// Root returns the root of the repository containing dir, or dir itself outside of repositories
+func Root(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+	for current := abs; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return abs, nil
+		}
+		current = parent
+	}
+}
+
+// Scan walks dir and the directories below it and returns the proposed commands of
+// each compose file, Makefile and Justfile, those of files closer to dir first.
+// Hidden directories and dependency folders such as node_modules are skipped.
+func Scan(dir string) ([]Proposal, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	var proposals []Proposal
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if d.IsDir() {
+			if rel != "." && (strings.HasPrefix(d.Name(), ".") || slices.Contains(skippedDirs, d.Name()) ||
+				strings.Count(rel, string(filepath.Separator)) >= maxDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var found []Proposal
+		switch name := d.Name(); {
+		case slices.Contains(composeFiles, name):
+			found, err = composeProposals(path)
+		case slices.Contains(makefiles, name):
+			found, err = recipeProposals(recipes.SourceMakefile, path)
+		case slices.Contains(justfiles, name):
+			found, err = recipeProposals(recipes.SourceJustfile, path)
+		default:
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		for i := range found {
+			found[i].Source = rel
+		}
+		proposals = append(proposals, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(proposals, func(a, b Proposal) int {
+		return strings.Count(a.Source, string(filepath.Separator)) - strings.Count(b.Source, string(filepath.Separator))
+	})
+	return proposals, nil
+}
+
+// recipeProposals proposes each recipe of a Makefile or Justfile
+func recipeProposals(source recipes.Source, path string) ([]Proposal, error) {
+	found, err := recipes.Read(source, path)
+	if err != nil {
+		return nil, err
+	}
+	tool, kind := "just", "recipe"
+	if source == recipes.SourceMakefile {
+		tool, kind = "make", "target"
+	}
+	project := filepath.Base(filepath.Dir(path))
+	proposals := make([]Proposal, 0, len(found))
+	for _, r := range found {
+		description := r.Description
+		if description == "" {
+			description = fmt.Sprintf("Run the %s %s of %s", r.Name, kind, project)
+		}
+		proposals = append(proposals, Proposal{Command: r.Command, ToolName: tool, Description: description})
+	}
+	return proposals, nil
+}
+
+// composeFile holds the parts of a compose file the proposals are based on
+type composeFile struct {
+	Services map[string]struct {
+		Image string `yaml:"image"`
+		Build any    `yaml:"build"`
+	} `yaml:"services"`
+}
+
+// composeProposals proposes the usual docker compose invocations for a compose file:
+// starting, stopping and inspecting the whole project, and a shell, the logs and a
+// restart per service
+func composeProposals(path string) ([]Proposal, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	var file composeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	base := "docker compose -f " + utils.ShellQuote(abs)
+	project := filepath.Base(filepath.Dir(abs))
+	proposals := []Proposal{
+		{Command: base + " up -d", Description: fmt.Sprintf("Start the %s services in the background", project)},
+		{Command: base + " down", Description: fmt.Sprintf("Stop and remove the %s services", project)},
+		{Command: base + " ps", Description: fmt.Sprintf("List the %s containers", project)},
+		{Command: base + " logs -f", Description: fmt.Sprintf("Follow the logs of all %s services", project)},
+	}
+
+	services := make([]string, 0, len(file.Services))
+	builds, images := false, false
+	for name, service := range file.Services {
+		services = append(services, name)
+		builds = builds || service.Build != nil
+		images = images || service.Image != ""
+	}
+	slices.Sort(services)
+
+	if builds {
+		proposals = append(proposals, Proposal{Command: base + " build", Description: fmt.Sprintf("Build the %s images", project)})
+	}
+	if images {
+		proposals = append(proposals, Proposal{Command: base + " pull", Description: fmt.Sprintf("Pull the %s images", project)})
+	}
+	for _, name := range services {
+		proposals = append(proposals,
+			Proposal{Command: base + " exec " + name + " sh", Description: fmt.Sprintf("Open a shell in the %s service", name)},
+			Proposal{Command: base + " logs -f " + name, Description: fmt.Sprintf("Follow the logs of the %s service", name)},
+			Proposal{Command: base + " restart " + name, Description: fmt.Sprintf("Restart the %s service", name)},
+		)
+	}
+
+	for i := range proposals {
+		proposals[i].ToolName = "docker"
+	}
+	return proposals, nil
+}
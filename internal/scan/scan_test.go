@@ -0,0 +1,93 @@
+//go:build unit
+// +build unit
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "cmd", "api")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := Root(sub)
+	if err != nil {
+		t.Fatalf("Failed to find root: %v", err)
+	}
+	if root != repo {
+		t.Errorf("Expected repository root %s, got %s", repo, root)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "services", "Makefile"), "test: ## Run the tests\n\tgo test ./...\n")
+	writeFile(t, filepath.Join(dir, "docker-compose.yml"), "services:\n  web:\n    build: .\n  db:\n    image: postgres\n")
+	writeFile(t, filepath.Join(dir, "node_modules", "pkg", "Makefile"), "all:\n\techo\n")
+	writeFile(t, filepath.Join(dir, ".github", "justfile"), "ci:\n    echo\n")
+	writeFile(t, filepath.Join(dir, "a", "b", "c", "d", "Makefile"), "deep:\n\techo\n")
+
+	proposals, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+
+	compose := "docker compose -f " + filepath.Join(dir, "docker-compose.yml")
+	want := []Proposal{
+		{Command: compose + " up -d", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " down", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " ps", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " logs -f", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " build", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " pull", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " exec db sh", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " logs -f db", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " restart db", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " exec web sh", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " logs -f web", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: compose + " restart web", ToolName: "docker", Source: "docker-compose.yml"},
+		{Command: "make -C " + filepath.Join(dir, "services") + " test", ToolName: "make", Source: filepath.Join("services", "Makefile")},
+	}
+	if len(proposals) != len(want) {
+		t.Fatalf("Expected %d proposals, got %d: %+v", len(want), len(proposals), proposals)
+	}
+	for i, p := range proposals {
+		if p.Command != want[i].Command || p.ToolName != want[i].ToolName || p.Source != want[i].Source {
+			t.Errorf("Proposal %d: expected %+v, got %+v", i, want[i], p)
+		}
+		if p.Description == "" {
+			t.Errorf("Proposal %d has no description", i)
+		}
+	}
+	if proposals[12].Description != "Run the tests" {
+		t.Errorf("Expected the Makefile comment as description, got %q", proposals[12].Description)
+	}
+}
+
+func TestScanInvalidCompose(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "compose.yaml"), "services: [")
+
+	if _, err := Scan(dir); err == nil {
+		t.Error("Expected error for an invalid compose file")
+	}
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Distance returns the Levenshtein edit distance between two strings
+// It counts the single-rune insertions, deletions and substitutions turning a into b.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Closest returns up to limit candidates that are likely misspellings of the query
+// Candidates are compared case-insensitively and kept when their edit distance is at
+// most a third of the query length (at least 1); results are sorted by distance
+// (ties keep candidate order).
+func Closest(query string, candidates []string, limit int) []string {
+	query = strings.ToLower(query)
+	maxDistance := max(len([]rune(query))/3, 1)
+
+	type match struct {
+		candidate string
+		distance  int
+	}
+	matches := []match{}
+	for _, candidate := range candidates {
+		if d := Distance(query, strings.ToLower(candidate)); d <= maxDistance {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].distance < matches[b].distance
+	})
+
+	results := []string{}
+	for _, m := range matches {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, m.candidate)
+	}
+	return results
+}
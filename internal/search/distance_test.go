@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kubectl", "kubectl", 0},
+		{"kubectl", "kubetcl", 2},
+		{"kubectl get pod", "kubectl get pods", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"größe", "grösse", 2},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"kubectl get pods -A", "kubectl get nodes", "docker ps -a", "kubectl get pod -A"}
+
+	got := Closest("kubectl get pods -a", candidates, 2)
+	want := []string{"kubectl get pods -A", "kubectl get pod -A"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Closest() = %v, want %v", got, want)
+	}
+
+	if got := Closest("helm", candidates, 3); len(got) != 0 {
+		t.Errorf("Expected no suggestions for an unrelated query, got %v", got)
+	}
+	if got := Closest("dokcer ps -a", candidates, 3); !reflect.DeepEqual(got, []string{"docker ps -a"}) {
+		t.Errorf("Expected the transposed command, got %v", got)
+	}
+}
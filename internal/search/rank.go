@@ -0,0 +1,200 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is a piece of text that can be ranked against a query
+type Document struct {
+	ID     string            // Identifier returned with the result (e.g. the command)
+	Fields map[string]string // Field name -> text
+}
+
+// Result is a ranked document
+type Result struct {
+	ID    string
+	Score float64
+}
+
+// FieldWeights boosts matches in specific fields; fields not listed have weight 1
+type FieldWeights map[string]float64
+
+// BM25 tuning parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// synonymWeight scales the contribution of terms added by synonym expansion
+	synonymWeight = 0.5
+)
+
+// Rank scores documents against a natural-language query using BM25 keyword scoring
+// with stop-word removal, light stemming and synonym expansion.
+// Documents that share no term with the query are omitted; results are sorted by
+// descending score (ties keep document order).
+func Rank(query string, docs []Document, weights FieldWeights) []Result {
+	queryTerms := expandQuery(Tokenize(query))
+	if len(queryTerms) == 0 || len(docs) == 0 {
+		return []Result{}
+	}
+
+	// Build weighted term frequencies per document
+	termFreqs := make([]map[string]float64, len(docs))
+	lengths := make([]float64, len(docs))
+	docFreq := map[string]int{}
+	totalLength := 0.0
+
+	for i, doc := range docs {
+		tf := map[string]float64{}
+		for field, text := range doc.Fields {
+			weight := 1.0
+			if w, ok := weights[field]; ok {
+				weight = w
+			}
+			for _, term := range Tokenize(text) {
+				tf[term] += weight
+				lengths[i] += weight
+			}
+		}
+		for term := range tf {
+			docFreq[term]++
+		}
+		termFreqs[i] = tf
+		totalLength += lengths[i]
+	}
+	avgLength := totalLength / float64(len(docs))
+	if avgLength == 0 {
+		avgLength = 1
+	}
+
+	n := float64(len(docs))
+	results := []Result{}
+	for i, doc := range docs {
+		score := 0.0
+		for term, queryWeight := range queryTerms {
+			tf := termFreqs[i][term]
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*lengths[i]/avgLength))
+			score += queryWeight * idf * norm
+		}
+		if score > 0 {
+			results = append(results, Result{ID: doc.ID, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].Score > results[b].Score
+	})
+
+	return results
+}
+
+// Tokenize splits text into lowercase, stemmed terms without stop words
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// expandQuery returns the query terms with their weights, adding synonyms at reduced weight
+func expandQuery(terms []string) map[string]float64 {
+	weighted := map[string]float64{}
+	for _, term := range terms {
+		weighted[term] = 1
+	}
+	for _, term := range terms {
+		for _, synonym := range synonyms[term] {
+			if _, ok := weighted[synonym]; !ok {
+				weighted[synonym] = synonymWeight
+			}
+		}
+	}
+	return weighted
+}
+
+// stem strips common English suffixes so "listing", "lists" and "listed" match "list"
+func stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			word = strings.TrimSuffix(word, suffix)
+			// running -> runn -> run
+			if n := len(word); suffix == "ing" && n >= 2 && word[n-1] == word[n-2] {
+				word = word[:n-1]
+			}
+			return word
+		}
+	}
+	return word
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "at": true, "for": true, "with": true, "by": true, "from": true,
+	"is": true, "are": true, "be": true, "it": true, "this": true, "that": true, "my": true,
+	"i": true, "me": true, "do": true, "does": true, "how": true, "what": true, "which": true,
+	"can": true, "could": true, "should": true, "would": true, "want": true, "need": true,
+	"all": true, "some": true, "any": true, "into": true, "out": true, "up": true, "there": true,
+	"you": true, "your": true, "we": true, "our": true, "when": true, "where": true, "who": true,
+}
+
+// synonymGroups lists terms that are treated as related when ranking
+var synonymGroups = [][]string{
+	{"kill", "stop", "terminate", "end", "abort"},
+	{"list", "show", "display", "ls", "get", "print", "view"},
+	{"delete", "remove", "rm", "del", "clean", "purge"},
+	{"process", "pid", "proc", "ps"},
+	{"port", "socket", "listen"},
+	{"find", "search", "locate", "grep", "lookup"},
+	{"directory", "dir", "folder"},
+	{"disk", "space", "size", "du", "df", "storage"},
+	{"log", "tail", "journal"},
+	{"start", "run", "launch"},
+	{"network", "net", "connection"},
+	{"container", "docker"},
+	{"pod", "kubectl", "kubernetes", "k8s"},
+	{"copy", "cp", "sync", "rsync"},
+	{"move", "mv", "rename"},
+	{"permission", "chmod", "chown"},
+	{"memory", "mem", "ram"},
+}
+
+// synonyms maps each stemmed term to the other stemmed terms of its group
+var synonyms = buildSynonyms(synonymGroups)
+
+func buildSynonyms(groups [][]string) map[string][]string {
+	result := map[string][]string{}
+	for _, group := range groups {
+		stemmed := make([]string, len(group))
+		for i, term := range group {
+			stemmed[i] = stem(term)
+		}
+		for _, term := range stemmed {
+			for _, other := range stemmed {
+				if other != term {
+					result[term] = append(result[term], other)
+				}
+			}
+		}
+	}
+	return result
+}
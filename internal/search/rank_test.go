@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testDocs() []Document {
+	return []Document{
+		{ID: "lsof -ti :8080 | xargs kill -9", Fields: map[string]string{"tool": "lsof", "description": "kill the process listening on port 8080"}},
+		{ID: "kubectl get pods -A", Fields: map[string]string{"tool": "kubectl", "description": "list pods in all namespaces"}},
+		{ID: "docker ps -a", Fields: map[string]string{"tool": "docker", "description": "show all containers"}},
+		{ID: "du -sh *", Fields: map[string]string{"tool": "du", "description": "size of each entry in the current directory"}},
+	}
+}
+
+func TestRankBestMatch(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"how do I kill the process on port 8080", "lsof -ti :8080 | xargs kill -9"},
+		{"which pods are running", "kubectl get pods -A"},
+		{"list my containers", "docker ps -a"},
+		{"how big is this folder", "du -sh *"},
+		{"stop whatever listens on 8080", "lsof -ti :8080 | xargs kill -9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := Rank(tt.query, testDocs(), FieldWeights{"tool": 1.5})
+			if len(results) == 0 {
+				t.Fatal("Expected at least one result")
+			}
+			if results[0].ID != tt.expected {
+				t.Errorf("Best match = %q, want %q (results: %+v)", results[0].ID, tt.expected, results)
+			}
+		})
+	}
+}
+
+func TestRankNoMatch(t *testing.T) {
+	if results := Rank("compile rust crate", testDocs(), nil); len(results) != 0 {
+		t.Errorf("Expected no results, got %+v", results)
+	}
+	if results := Rank("how do I", testDocs(), nil); len(results) != 0 {
+		t.Errorf("Stop-word-only query should not match, got %+v", results)
+	}
+	if results := Rank("pods", nil, nil); len(results) != 0 {
+		t.Errorf("Expected no results without documents, got %+v", results)
+	}
+}
+
+func TestRankSortedByScore(t *testing.T) {
+	results := Rank("list pods containers", testDocs(), nil)
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("Results not sorted by score: %+v", results)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"How do I list the pods?", []string{"list", "pod"}},
+		{"lsof -i :8080", []string{"lsof", "8080"}},
+		{"Running containers", []string{"run", "container"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := Tokenize(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Tokenize(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
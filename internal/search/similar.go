@@ -0,0 +1,63 @@
+package search
+
+import "sort"
+
+// Related scores documents by their similarity to a target document
+// Each field contributes the Jaccard overlap of its terms with the same field of the
+// target, scaled by the field weight. The target itself and documents without any
+// overlap are omitted; results are sorted by descending score (ties keep document order).
+func Related(target Document, docs []Document, weights FieldWeights) []Result {
+	targetTerms := make(map[string]map[string]bool, len(target.Fields))
+	for field, text := range target.Fields {
+		targetTerms[field] = termSet(text)
+	}
+
+	results := []Result{}
+	for _, doc := range docs {
+		if doc.ID == target.ID {
+			continue
+		}
+
+		score := 0.0
+		for field, text := range doc.Fields {
+			weight := 1.0
+			if w, ok := weights[field]; ok {
+				weight = w
+			}
+			score += weight * jaccard(targetTerms[field], termSet(text))
+		}
+		if score > 0 {
+			results = append(results, Result{ID: doc.ID, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].Score > results[b].Score
+	})
+
+	return results
+}
+
+// termSet returns the distinct terms of a text
+func termSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, term := range Tokenize(text) {
+		set[term] = true
+	}
+	return set
+}
+
+// jaccard returns the size of the intersection of two sets divided by the size of their union
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,58 @@
+//go:build unit
+// +build unit
+
+package search
+
+import (
+	"testing"
+)
+
+func relatedDocs() []Document {
+	return []Document{
+		{ID: "kubectl get pods -A", Fields: map[string]string{"tool": "kubectl", "description": "list pods in all namespaces", "tags": "k8s"}},
+		{ID: "kubectl logs -f deploy/api", Fields: map[string]string{"tool": "kubectl", "description": "follow the api logs", "tags": "k8s debug"}},
+		{ID: "docker logs -f api", Fields: map[string]string{"tool": "docker", "description": "follow the api container logs", "tags": "debug"}},
+		{ID: "du -sh *", Fields: map[string]string{"tool": "du", "description": "size of each entry in the current directory"}},
+	}
+}
+
+func TestRelated(t *testing.T) {
+	docs := relatedDocs()
+
+	results := Related(docs[1], docs, FieldWeights{"tool": 2})
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 related documents, got %+v", results)
+	}
+	for _, r := range results {
+		if r.ID == docs[1].ID {
+			t.Error("Expected the target to be excluded")
+		}
+		if r.ID == "du -sh *" {
+			t.Error("Expected unrelated document to be omitted")
+		}
+	}
+	if results[0].ID != "kubectl get pods -A" {
+		t.Errorf("Expected the same tool to rank first with a tool boost, got %+v", results)
+	}
+}
+
+func TestRelatedByContent(t *testing.T) {
+	docs := relatedDocs()
+
+	results := Related(docs[1], docs, FieldWeights{"tool": 0.5})
+
+	if len(results) == 0 || results[0].ID != "docker logs -f api" {
+		t.Errorf("Expected the document sharing description and tags to rank first, got %+v", results)
+	}
+}
+
+func TestRelatedNoOverlap(t *testing.T) {
+	target := Document{ID: "x", Fields: map[string]string{"tool": "jq"}}
+
+	results := Related(target, relatedDocs(), nil)
+
+	if len(results) != 0 {
+		t.Errorf("Expected no related documents, got %+v", results)
+	}
+}
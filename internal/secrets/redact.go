@@ -0,0 +1,129 @@
+package secrets
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+)
+
+// Redacted replaces the hidden parts of a command
+const Redacted = "****"
+
+// Redactor hides parts of commands where they are displayed or exported, while the stored
+// command keeps the real values
+// A nil Redactor hides nothing.
+type Redactor struct {
+	secrets  bool
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns a redactor hiding the credentials Find detects if secrets is set, and
+// what the regular expressions match: of a pattern with a group only the group, else the
+// whole match. It returns nil when there is nothing to hide.
+func NewRedactor(secrets bool, patterns []string) (*Redactor, error) {
+	if !secrets && len(patterns) == 0 {
+		return nil, nil
+	}
+	r := &Redactor{secrets: secrets}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern '%s': %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// span is a part of a command to hide, as byte offsets
+type span struct {
+	start, end int
+}
+
+// Redact returns the command with its hidden parts replaced by ****
+func (r *Redactor) Redact(command string) string {
+	if r == nil {
+		return command
+	}
+
+	var spans []span
+	if r.secrets {
+		for _, secret := range Find(command) {
+			spans = append(spans, span{secret.Start, secret.End})
+		}
+	}
+	for _, re := range r.patterns {
+		for _, match := range re.FindAllStringSubmatchIndex(command, -1) {
+			start, end := match[0], match[1]
+			if len(match) > 2 && match[2] >= 0 {
+				start, end = match[2], match[3]
+			}
+			if start < end {
+				spans = append(spans, span{start, end})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return command
+	}
+
+	// Overlapping parts are hidden as one
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			last.end = max(last.end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	var b strings.Builder
+	offset := 0
+	for _, s := range merged {
+		b.WriteString(command[offset:s.start])
+		b.WriteString(Redacted)
+		offset = s.end
+	}
+	b.WriteString(command[offset:])
+	return b.String()
+}
+
+// Hides reports whether the command has parts the redactor hides
+func (r *Redactor) Hides(command string) bool {
+	return r.Redact(command) != command
+}
+
+// Bookmark returns a copy of the bookmark with its command and variants redacted
+// The stored command stays the key of the bookmark for operations, so only display the copy.
+func (r *Redactor) Bookmark(bookmark dto.BookmarkResponse) dto.BookmarkResponse {
+	if r == nil {
+		return bookmark
+	}
+	bookmark.Command = r.Redact(bookmark.Command)
+	if len(bookmark.Variants) > 0 {
+		variants := maps.Clone(bookmark.Variants)
+		for goos, command := range variants {
+			variants[goos] = r.Redact(command)
+		}
+		bookmark.Variants = variants
+	}
+	return bookmark
+}
+
+// Bookmarks returns copies of the bookmarks with their commands and variants redacted
+func (r *Redactor) Bookmarks(bookmarks []dto.BookmarkResponse) []dto.BookmarkResponse {
+	if r == nil {
+		return bookmarks
+	}
+	redacted := make([]dto.BookmarkResponse, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		redacted[i] = r.Bookmark(bookmark)
+	}
+	return redacted
+}
@@ -0,0 +1,88 @@
+// Package secrets detects credentials written into commands, such as passwords, tokens and
+// access keys, so they are not stored or shared by accident
+package secrets
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Secret is a credential found in a command
+type Secret struct {
+	Kind  string // What was found, e.g. AWS access key
+	Value string // The credential as written, without quotes
+	Start int    // Byte offset of the value in the command
+	End   int
+}
+
+// patterns match credentials written into commands; the first group is the value
+var patterns = []struct {
+	pattern *regexp.Regexp
+	kind    string
+}{
+	{regexp.MustCompile(`(?i)(?:^|\s)--?(?:password|passwd|pass|token|api[-_]?key|secret|client[-_]secret|access[-_]key)(?:=|\s+)(\S+)`), "password or token"},
+	{regexp.MustCompile(`\b[A-Z0-9_]*(?:PASSWORD|PASSWD|TOKEN|SECRET|API_KEY|APIKEY)[A-Z0-9_]*=(\S+)`), "secret in an environment variable"},
+	{regexp.MustCompile(`\bmysql(?:dump|admin)?\s(?:.*\s)?-p(\S+)`), "MySQL password"},
+	{regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`), "password in a URL"},
+	{regexp.MustCompile(`(?i)authorization:\s*(?:bearer|basic|token)\s+([^\s'"]+)`), "Authorization header"},
+	{regexp.MustCompile(`\b(AKIA[0-9A-Z]{16})\b`), "AWS access key"},
+	{regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,})\b`), "GitHub token"},
+	{regexp.MustCompile(`\b(xox[baprs]-[A-Za-z0-9-]{10,})\b`), "Slack token"},
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----\s*([A-Za-z0-9+/=]{16,})`), "private key"},
+}
+
+// Find returns the credentials written into the command, in the order of their kinds and
+// without duplicates
+// References like $TOKEN, <password> or {token} are not credentials.
+func Find(command string) []Secret {
+	var found []Secret
+	for _, p := range patterns {
+		for _, match := range p.pattern.FindAllStringSubmatchIndex(command, -1) {
+			start, end := match[2], match[3]
+			// Quotes around the value are part of the shell syntax, not of the credential
+			raw := command[start:end]
+			trimmed := strings.TrimLeft(raw, `"'`)
+			start += len(raw) - len(trimmed)
+			end -= len(trimmed) - len(strings.TrimRight(trimmed, `"'`))
+			value := command[start:end]
+
+			if !literal(value) || slices.ContainsFunc(found, func(s Secret) bool { return s.Value == value }) {
+				continue
+			}
+			found = append(found, Secret{Kind: p.kind, Value: value, Start: start, End: end})
+		}
+	}
+	return found
+}
+
+// Contains reports whether any credential is written into the command
+func Contains(command string) bool {
+	return len(Find(command)) > 0
+}
+
+// Mask hides all but the first characters of a credential
+func Mask(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", min(len(runes)-2, 6))
+}
+
+// MaskAll returns the command with every credential found in it masked
+func MaskAll(command string) string {
+	found := Find(command)
+	sort.Slice(found, func(i, j int) bool { return found[i].Start > found[j].Start })
+	for _, secret := range found {
+		command = command[:secret.Start] + Mask(secret.Value) + command[secret.End:]
+	}
+	return command
+}
+
+// literal reports whether a credential is written out rather than referenced, like $TOKEN,
+// <password>, {token} or a flag following a flag without value
+func literal(value string) bool {
+	return value != "" && !strings.ContainsAny(value[:1], "$<{*-")
+}
@@ -0,0 +1,315 @@
+// Package selfupdate replaces the running binary with the latest GitHub release
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReleaseURL is the GitHub API endpoint of the latest release
+const DefaultReleaseURL = "https://api.github.com/repos/fgeck/tools/releases/latest"
+
+// checksumsAsset is the name of the release asset listing SHA-256 checksums of all archives
+const checksumsAsset = "checksums.txt"
+
+// maxDownloadSize caps the size of downloaded release assets
+const maxDownloadSize = 100 << 20
+
+// ErrChecksumMismatch is returned when a downloaded archive does not match its published checksum
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Release is a published version of tools
+type Release struct {
+	Version string            // Without the leading v, e.g. 1.4.0
+	Assets  map[string]string // Download URLs by asset name
+}
+
+// Updater finds and installs releases
+type Updater struct {
+	ReleaseURL string
+	Client     *http.Client
+	GOOS       string
+	GOARCH     string
+}
+
+// New creates an updater for the latest release of the current platform
+func New() *Updater {
+	return &Updater{
+		ReleaseURL: DefaultReleaseURL,
+		Client:     &http.Client{Timeout: 60 * time.Second},
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+	}
+}
+
+// Latest fetches the latest release
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	data, err := u.get(ctx, u.ReleaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse latest release: %w", err)
+	}
+	if payload.TagName == "" {
+		return nil, fmt.Errorf("failed to parse latest release: missing tag name")
+	}
+
+	release := &Release{Version: strings.TrimPrefix(payload.TagName, "v"), Assets: map[string]string{}}
+	for _, asset := range payload.Assets {
+		release.Assets[asset.Name] = asset.URL
+	}
+	return release, nil
+}
+
+// AssetName returns the name of the release archive for the updater's platform
+func (u *Updater) AssetName(version string) string {
+	ext := "tar.gz"
+	if u.GOOS == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("tools_%s_%s_%s.%s", version, u.GOOS, u.GOARCH, ext)
+}
+
+// Download fetches the release archive of the updater's platform, verifies its checksum
+// and returns the binary it contains
+func (u *Updater) Download(ctx context.Context, release *Release) ([]byte, error) {
+	name := u.AssetName(release.Version)
+	archiveURL, ok := release.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no build for %s/%s", release.Version, u.GOOS, u.GOARCH)
+	}
+	checksumsURL, ok := release.Assets[checksumsAsset]
+	if !ok {
+		return nil, fmt.Errorf("release %s publishes no %s", release.Version, checksumsAsset)
+	}
+
+	checksums, err := u.get(ctx, checksumsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksums: %w", err)
+	}
+	expected, err := findChecksum(checksums, name)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := u.get(ctx, archiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, name, expected, actual)
+	}
+
+	binary := "tools"
+	if u.GOOS == "windows" {
+		binary = "tools.exe"
+		return extractZip(archive, binary)
+	}
+	return extractTarGz(archive, binary)
+}
+
+// get downloads a URL
+func (u *Updater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("download exceeds maximum size of %d bytes", maxDownloadSize)
+	}
+	return data, nil
+}
+
+// findChecksum looks up the checksum of an asset in a "<sha256>  <name>" checksums file
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum published for %s", name)
+}
+
+// extractTarGz returns the named file of a gzipped tar archive
+func extractTarGz(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == name {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		}
+	}
+	return nil, fmt.Errorf("archive does not contain %s", name)
+}
+
+// extractZip returns the named file of a zip archive
+func extractZip(archive []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if filepath.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+	}
+	return nil, fmt.Errorf("archive does not contain %s", name)
+}
+
+// Replace swaps the executable at path for binary
+// The new binary is written next to the old one and renamed over it, so an interrupted
+// update never leaves a partial executable behind. Windows cannot overwrite a running
+// executable, so the old one is moved aside first.
+func Replace(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tools-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to replace executable: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace executable: %w", err)
+	}
+	return nil
+}
+
+// PackageManager returns the package manager that installed the executable at path, or ""
+// Such installations should be updated with the package manager instead.
+func PackageManager(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	switch {
+	case strings.Contains(path, "/Cellar/") || strings.Contains(path, "/Caskroom/") || strings.Contains(path, "/homebrew/"):
+		return "brew"
+	case strings.Contains(strings.ToLower(path), "/scoop/"):
+		return "scoop"
+	}
+	return ""
+}
+
+// IsNewer reports whether version latest is newer than current
+// Versions are compared numerically by their dot-separated parts; pre-release suffixes
+// are ignored. A development build is older than every release.
+func IsNewer(latest, current string) bool {
+	if current == "" || current == "dev" {
+		return true
+	}
+
+	l, c := versionParts(latest), versionParts(current)
+	for i := 0; i < max(len(l), len(c)); i++ {
+		var lp, cp int
+		if i < len(l) {
+			lp = l[i]
+		}
+		if i < len(c) {
+			cp = c[i]
+		}
+		if lp != cp {
+			return lp > cp
+		}
+	}
+	return false
+}
+
+// versionParts parses "v1.2.3-rc1" into [1 2 3]
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts []int
+	for _, p := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
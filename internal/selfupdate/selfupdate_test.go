@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tw.Write(content)
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+// releaseServer serves a fake latest release of version 1.2.0 for linux/amd64
+func releaseServer(t *testing.T, archive []byte, checksum string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v1.2.0", "assets": [
+			{"name": "tools_1.2.0_linux_amd64.tar.gz", "browser_download_url": "%[1]s/archive"},
+			{"name": "checksums.txt", "browser_download_url": "%[1]s/checksums"}
+		]}`, server.URL)
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	})
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  tools_1.2.0_linux_amd64.tar.gz\nabc  tools_1.2.0_darwin_arm64.tar.gz\n", checksum)
+	})
+	return server
+}
+
+func testUpdater(server *httptest.Server) *Updater {
+	return &Updater{ReleaseURL: server.URL + "/latest", Client: server.Client(), GOOS: "linux", GOARCH: "amd64"}
+}
+
+func TestDownloadVerifiesAndExtracts(t *testing.T) {
+	archive := tarGz(t, "tools", []byte("new binary"))
+	sum := sha256.Sum256(archive)
+	server := releaseServer(t, archive, hex.EncodeToString(sum[:]))
+	updater := testUpdater(server)
+	ctx := context.Background()
+
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Failed to fetch release: %v", err)
+	}
+	if release.Version != "1.2.0" {
+		t.Errorf("Expected version 1.2.0, got %s", release.Version)
+	}
+
+	binary, err := updater.Download(ctx, release)
+	if err != nil {
+		t.Fatalf("Failed to download: %v", err)
+	}
+	if string(binary) != "new binary" {
+		t.Errorf("Expected extracted binary, got %q", binary)
+	}
+}
+
+func TestDownloadRejectsChecksumMismatch(t *testing.T) {
+	server := releaseServer(t, tarGz(t, "tools", []byte("tampered")), "0000")
+	updater := testUpdater(server)
+	ctx := context.Background()
+
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Failed to fetch release: %v", err)
+	}
+	if _, err := updater.Download(ctx, release); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+
+	updater.GOOS = "freebsd"
+	if _, err := updater.Download(ctx, release); err == nil {
+		t.Error("Expected error for a platform without build")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatalf("Failed to replace: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("Expected new binary, got %q", data)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected executable permissions, got %v", info.Mode())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected no leftover files, got %d entries", len(entries))
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		expected        bool
+	}{
+		{"1.2.0", "1.1.9", true},
+		{"1.10.0", "1.9.0", true},
+		{"1.2.0", "v1.2.0", false},
+		{"1.2.0", "1.3.0", false},
+		{"1.2.1", "1.2", true},
+		{"1.2.0", "1.2.0-rc1", false},
+		{"0.1.0", "dev", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.expected {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.expected)
+		}
+	}
+}
+
+func TestPackageManager(t *testing.T) {
+	tests := map[string]string{
+		"/opt/homebrew/Caskroom/tools/1.2.0/tools":       "brew",
+		"/usr/local/Cellar/tools/1.2.0/bin/tools":        "brew",
+		`C:\Users\me\scoop\apps\tools\current\tools.exe`: "scoop",
+		"/usr/local/bin/tools":                           "",
+	}
+
+	for path, expected := range tests {
+		if got := PackageManager(path); got != expected {
+			t.Errorf("PackageManager(%q) = %q, want %q", path, got, expected)
+		}
+	}
+}
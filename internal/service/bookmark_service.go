@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/fgeck/tools/internal/dto"
 )
@@ -17,12 +18,97 @@ type BookmarkService interface {
 	// ListBookmarks retrieves all examples
 	ListBookmarks(ctx context.Context) (*dto.ListBookmarksResponse, error)
 
+	// ListBookmarksPage retrieves up to limit examples starting at cursor
+	ListBookmarksPage(ctx context.Context, cursor string, limit int) (*dto.ListBookmarksResponse, error)
+
+	// ListTools retrieves every tool name with its number of examples
+	ListTools(ctx context.Context) (*dto.ToolsResponse, error)
+
+	// SearchBookmarks retrieves examples matching all terms of a query
+	SearchBookmarks(ctx context.Context, query string) (*dto.ListBookmarksResponse, error)
+
+	// MatchCommand retrieves the examples whose command matches a full or partial command
+	MatchCommand(ctx context.Context, command string) (*dto.ListBookmarksResponse, error)
+
+	// SuggestCommands retrieves stored commands that are likely misspelled as the given one
+	SuggestCommands(ctx context.Context, command string, limit int) ([]string, error)
+
+	// SuggestToolNames retrieves stored tool names that are likely misspelled as the given one
+	SuggestToolNames(ctx context.Context, toolName string, limit int) ([]string, error)
+
+	// RankBookmarks retrieves the examples most relevant to a natural-language query
+	RankBookmarks(ctx context.Context, query string, limit int) (*dto.RankedBookmarksResponse, error)
+
+	// RelatedBookmarks retrieves the examples most similar to the bookmark with the given command
+	RelatedBookmarks(ctx context.Context, command string, limit int) (*dto.RankedBookmarksResponse, error)
+
 	// UpdateBookmark modifies an existing example
+	// With ExpectedUpdatedAt, an example changed since it was read fails with a conflict.
 	UpdateBookmark(ctx context.Context, req dto.UpdateBookmarkRequest) (*dto.BookmarkResponse, error)
 
 	// DeleteBookmark removes an example by command
 	DeleteBookmark(ctx context.Context, command string) error
 
+	// DeleteBookmarkIfUnchanged removes an example unless its update time differs from the
+	// given one, which the caller read it with; a changed example fails with a conflict
+	DeleteBookmarkIfUnchanged(ctx context.Context, command string, updatedAt time.Time) error
+
 	// DeleteToolBookmarks removes all examples for a tool name
 	DeleteToolBookmarks(ctx context.Context, toolName string) error
+
+	// MergeTool reassigns all examples of the source tool to the target tool
+	MergeTool(ctx context.Context, source, target string, dedupe bool) (*dto.MergeToolResult, error)
+
+	// ReplaceInCommands replaces text in the stored commands, optionally only of one tool
+	ReplaceInCommands(ctx context.Context, req dto.ReplaceRequest) (*dto.ReplaceResult, error)
+
+	// ListRevisions retrieves the previous revisions of a bookmark
+	ListRevisions(ctx context.Context, command string) (*dto.RevisionsResponse, error)
+
+	// RestoreRevision replaces a bookmark with one of its previous revisions
+	RestoreRevision(ctx context.Context, command string, number int) (*dto.BookmarkResponse, error)
+
+	// ImportBookmarks creates many examples at once, resolving conflicts with the given strategy
+	ImportBookmarks(ctx context.Context, reqs []dto.CreateBookmarkRequest, strategy dto.ConflictStrategy) (*dto.ImportResult, error)
+
+	// PreviewImport reports what ImportBookmarks would change, without writing anything
+	PreviewImport(ctx context.Context, reqs []dto.CreateBookmarkRequest, strategy dto.ConflictStrategy) (*dto.ImportPreview, error)
+
+	// Transaction runs fn with a service whose changes to the store all apply when fn returns
+	// nil, or none of them when it or applying them fails; they are recorded once applied
+	Transaction(ctx context.Context, fn func(tx BookmarkService) error) error
+
+	// ListPending retrieves the bookmarks waiting for review
+	ListPending(ctx context.Context) (*dto.ListBookmarksResponse, error)
+
+	// ApproveBookmark moves a bookmark from the review queue into the store
+	ApproveBookmark(ctx context.Context, command string) (*dto.BookmarkResponse, error)
+
+	// RejectBookmark removes a bookmark from the review queue
+	RejectBookmark(ctx context.Context, command string) error
+
+	// RecordRun records that a bookmarked command was selected for execution
+	RecordRun(ctx context.Context, command string) error
+
+	// RecordRunResult records the exit code and duration of an executed bookmark
+	RecordRunResult(ctx context.Context, command string, exitCode int, duration time.Duration) error
+
+	// LastRun retrieves the result of the latest execution of a command, nil if it never ran
+	LastRun(ctx context.Context, command string) (*dto.RunResult, error)
+
+	// ListDue retrieves the bookmarks with a reminder period that has elapsed since they last ran
+	ListDue(ctx context.Context, now time.Time) (*dto.DueBookmarksResponse, error)
+
+	// ListUnused retrieves the bookmarks that were neither run nor added since the given time
+	ListUnused(ctx context.Context, since time.Time) (*dto.UnusedBookmarksResponse, error)
+
+	// GetHistory retrieves the audit log, optionally filtered by command
+	GetHistory(ctx context.Context, command string) (*dto.HistoryResponse, error)
+
+	// GetMetrics summarizes the activity recorded in the audit log since the given time
+	GetMetrics(ctx context.Context, since time.Time, limit int) (*dto.MetricsResponse, error)
+
+	// CheckStore verifies that the store can be read and, with writable, written, without
+	// changing it; it backs health and readiness probes
+	CheckStore(ctx context.Context, writable bool) error
 }
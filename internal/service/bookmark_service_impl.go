@@ -2,31 +2,120 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/fgeck/tools/internal/audit"
 	"github.com/fgeck/tools/internal/domain/models"
 	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/hooks"
+	"github.com/fgeck/tools/internal/logging"
 	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/search"
+	"github.com/fgeck/tools/internal/secrets"
+	"github.com/fgeck/tools/internal/utils"
 )
 
 type bookmarkServiceImpl struct {
-	repo repository.BookmarkRepository
+	repo         repository.BookmarkRepository
+	auditLog     audit.Log                     // Optional, nil disables history recording
+	hooks        hooks.Runner                  // Optional, nil disables hooks
+	maxRevisions int                           // Previous versions kept per bookmark, 0 disables revisions
+	onChange     []func()                      // Optional, called after every change to the store
+	review       repository.BookmarkRepository // Optional queue of bookmarks awaiting approval, nil adds directly
+	author       string                        // Recorded on new bookmarks, empty records none
+	logger       *slog.Logger                  // Changes and failures of history and hooks
+	pending      *[]change                     // Set within a transaction, which records its changes once applied
+}
+
+// Option configures optional service dependencies
+type Option func(*bookmarkServiceImpl)
+
+// WithAuditLog records every change and run to the given audit log
+func WithAuditLog(log audit.Log) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.auditLog = log
+	}
+}
+
+// WithHooks runs user-defined hooks after every change and run
+func WithHooks(runner hooks.Runner) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.hooks = runner
+	}
+}
+
+// WithOnChange calls fn after every change to the store, e.g. to refresh an export
+// Several listeners are called in the order they were given.
+func WithOnChange(fn func()) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.onChange = append(s.onChange, fn)
+	}
+}
+
+// WithMaxRevisions keeps up to n previous versions of each bookmark on edit
+func WithMaxRevisions(n int) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.maxRevisions = n
+	}
+}
+
+// WithAuthor records the given user as the author of new bookmarks, for stores shared by a team
+func WithAuthor(name string) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.author = name
+	}
+}
+
+// WithLogger logs changes to the store and failures recording them, which are otherwise only
+// visible as an error of the single operation, or not at all for best-effort recording
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.logger = logger
+	}
 }
 
 // NewBookmarkService creates a new example service instance
-func NewBookmarkService(repo repository.BookmarkRepository) BookmarkService {
-	return &bookmarkServiceImpl{
-		repo: repo,
+func NewBookmarkService(repo repository.BookmarkRepository, opts ...Option) BookmarkService {
+	s := &bookmarkServiceImpl{
+		repo:   repo,
+		logger: logging.Discard(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // CreateBookmark implements business logic for creating an example
 func (s *bookmarkServiceImpl) CreateBookmark(ctx context.Context, req dto.CreateBookmarkRequest) (*dto.BookmarkResponse, error) {
+	req.Command = normalizeCommand(req.Command)
+
 	// Validation
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
 	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := normalizeVariants(req.Variants)
+	if err != nil {
+		return nil, err
+	}
+	requires, err := normalizeRequires(req.Requires)
+	if err != nil {
+		return nil, err
+	}
+	if err := refuseSecrets(req.Command, variants, nil, req.AllowSecrets); err != nil {
+		return nil, err
+	}
 
 	// Check if command already exists
 	exists, err := s.repo.Exists(ctx, req.Command)
@@ -34,7 +123,7 @@ func (s *bookmarkServiceImpl) CreateBookmark(ctx context.Context, req dto.Create
 		return nil, fmt.Errorf("failed to check example existence: %w", err)
 	}
 	if exists {
-		return nil, fmt.Errorf("example with command '%s' already exists", req.Command)
+		return nil, fmt.Errorf("example with command '%s' %w", req.Command, models.ErrAlreadyExists)
 	}
 
 	// Create domain model
@@ -42,6 +131,23 @@ func (s *bookmarkServiceImpl) CreateBookmark(ctx context.Context, req dto.Create
 		Command:     req.Command,
 		ToolName:    req.ToolName,
 		Description: req.Description,
+		Tags:        tags,
+		Link:        strings.TrimSpace(req.Link),
+		RemindEvery: strings.TrimSpace(req.RemindEvery),
+		Host:        strings.TrimSpace(req.Host),
+		Variants:    variants,
+		Requires:    requires,
+		Private:     req.Private,
+		Author:      strings.TrimSpace(req.Author),
+		UpdatedAt:   time.Now(),
+	}
+	if example.Author == "" {
+		example.Author = s.author
+	}
+
+	// With review enabled, new bookmarks wait in the queue until they are approved
+	if s.review != nil {
+		return s.submit(ctx, example)
 	}
 
 	// Persist
@@ -49,13 +155,17 @@ func (s *bookmarkServiceImpl) CreateBookmark(ctx context.Context, req dto.Create
 		return nil, fmt.Errorf("failed to create example: %w", err)
 	}
 
+	if err := s.record(ctx, audit.ActionCreate, example.Command, nil, example); err != nil {
+		return nil, err
+	}
+
 	// Convert to DTO
 	return s.modelToDTO(example), nil
 }
 
 // GetBookmark retrieves an example by command
 func (s *bookmarkServiceImpl) GetBookmark(ctx context.Context, command string) (*dto.BookmarkResponse, error) {
-	example, err := s.repo.GetByCommand(ctx, command)
+	example, err := s.getByCommand(ctx, command)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get example: %w", err)
 	}
@@ -81,13 +191,243 @@ func (s *bookmarkServiceImpl) ListBookmarks(ctx context.Context) (*dto.ListBookm
 	}, nil
 }
 
+// ListBookmarksPage retrieves up to limit examples starting at cursor
+// The response's NextCursor continues the listing; it is empty on the last page
+func (s *bookmarkServiceImpl) ListBookmarksPage(ctx context.Context, cursor string, limit int) (*dto.ListBookmarksResponse, error) {
+	page, err := s.repo.ListPage(ctx, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	responses := make([]dto.BookmarkResponse, len(page.Bookmarks))
+	for i, example := range page.Bookmarks {
+		responses[i] = *s.modelToDTO(example)
+	}
+
+	return &dto.ListBookmarksResponse{
+		Examples:   responses,
+		Count:      len(responses),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// ListTools retrieves every tool name with its number of examples, most examples first
+func (s *bookmarkServiceImpl) ListTools(ctx context.Context) (*dto.ToolsResponse, error) {
+	counts, err := s.repo.CountByToolName(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tool examples: %w", err)
+	}
+
+	tools := make([]dto.ToolCountResponse, len(counts))
+	for i, c := range counts {
+		tools[i] = dto.ToolCountResponse{ToolName: c.ToolName, Count: c.Count}
+	}
+
+	return &dto.ToolsResponse{
+		Tools: tools,
+		Count: len(tools),
+	}, nil
+}
+
+// SearchBookmarks retrieves examples matching all whitespace-separated terms of a query
+// Terms are matched case-insensitively against command, tool name and description
+func (s *bookmarkServiceImpl) SearchBookmarks(ctx context.Context, query string) (*dto.ListBookmarksResponse, error) {
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	responses := []dto.BookmarkResponse{}
+	for _, example := range examples {
+		if matchesAllTerms(example, terms) {
+			responses = append(responses, *s.modelToDTO(example))
+		}
+	}
+	s.recordSearch(query)
+
+	return &dto.ListBookmarksResponse{
+		Examples: responses,
+		Count:    len(responses),
+	}, nil
+}
+
+// MatchCommand retrieves the examples whose command matches a full or partial command
+// An exact match wins; otherwise commands are compared case-insensitively, preferring
+// equal commands over prefixes and prefixes over substrings. Only the best kind of
+// match is returned, so a unique prefix resolves to a single example.
+func (s *bookmarkServiceImpl) MatchCommand(ctx context.Context, command string) (*dto.ListBookmarksResponse, error) {
+	responses := []dto.BookmarkResponse{}
+	if example, err := s.getByCommand(ctx, command); err == nil {
+		responses = append(responses, *s.modelToDTO(example))
+		return &dto.ListBookmarksResponse{Examples: responses, Count: 1}, nil
+	} else if !errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get example: %w", err)
+	}
+
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	query := strings.ToLower(normalizeCommand(command))
+	if query == "" {
+		return &dto.ListBookmarksResponse{Examples: responses}, nil
+	}
+	for _, match := range []func(string) bool{
+		func(c string) bool { return c == query },
+		func(c string) bool { return strings.HasPrefix(c, query) },
+		func(c string) bool { return strings.Contains(c, query) },
+	} {
+		for _, example := range examples {
+			if match(strings.ToLower(example.Command)) {
+				responses = append(responses, *s.modelToDTO(example))
+			}
+		}
+		if len(responses) > 0 {
+			break
+		}
+	}
+
+	return &dto.ListBookmarksResponse{
+		Examples: responses,
+		Count:    len(responses),
+	}, nil
+}
+
+// SuggestCommands retrieves up to limit stored commands within a small edit distance of command
+// It backs "did you mean" hints when a command is not found.
+func (s *bookmarkServiceImpl) SuggestCommands(ctx context.Context, command string, limit int) ([]string, error) {
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	commands := make([]string, len(examples))
+	for i, example := range examples {
+		commands[i] = example.Command
+	}
+	return search.Closest(normalizeCommand(command), commands, limit), nil
+}
+
+// SuggestToolNames retrieves up to limit stored tool names within a small edit distance of toolName
+func (s *bookmarkServiceImpl) SuggestToolNames(ctx context.Context, toolName string, limit int) ([]string, error) {
+	counts, err := s.repo.CountByToolName(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tool examples: %w", err)
+	}
+
+	names := make([]string, len(counts))
+	for i, c := range counts {
+		names[i] = c.ToolName
+	}
+	return search.Closest(strings.TrimSpace(toolName), names, limit), nil
+}
+
+// RankBookmarks retrieves the examples most relevant to a natural-language query
+// Tool names and tags weigh more than descriptions and commands; a limit <= 0 returns all matches
+func (s *bookmarkServiceImpl) RankBookmarks(ctx context.Context, query string, limit int) (*dto.RankedBookmarksResponse, error) {
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	docs := make([]search.Document, len(examples))
+	for i, example := range examples {
+		docs[i] = bookmarkDocument(example)
+	}
+
+	ranked := search.Rank(query, docs, search.FieldWeights{"tool": 1.5, "tags": 1.5})
+	s.recordSearch(query)
+	return s.rankedResponse(examples, ranked, limit), nil
+}
+
+// RelatedBookmarks retrieves the examples most similar to the bookmark with the given command
+// Bookmarks of the same tool weigh most, followed by shared tags; a limit <= 0 returns all matches
+func (s *bookmarkServiceImpl) RelatedBookmarks(ctx context.Context, command string, limit int) (*dto.RankedBookmarksResponse, error) {
+	target, err := s.getByCommand(ctx, command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get example: %w", err)
+	}
+
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	docs := make([]search.Document, len(examples))
+	for i, example := range examples {
+		docs[i] = bookmarkDocument(example)
+	}
+
+	related := search.Related(bookmarkDocument(target), docs, search.FieldWeights{"tool": 2, "tags": 1.5})
+	return s.rankedResponse(examples, related, limit), nil
+}
+
+// bookmarkDocument returns the searchable fields of a bookmark
+func bookmarkDocument(example *models.Bookmark) search.Document {
+	return search.Document{
+		ID: example.Command,
+		Fields: map[string]string{
+			"command":     example.Command,
+			"tool":        example.ToolName,
+			"description": example.Description,
+			"tags":        strings.Join(example.Tags, " "),
+		},
+	}
+}
+
+// rankedResponse converts search results over examples to a response, keeping at most limit results
+func (s *bookmarkServiceImpl) rankedResponse(examples []*models.Bookmark, ranked []search.Result, limit int) *dto.RankedBookmarksResponse {
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	byCommand := make(map[string]*models.Bookmark, len(examples))
+	for _, example := range examples {
+		byCommand[example.Command] = example
+	}
+
+	results := make([]dto.RankedBookmark, len(ranked))
+	for i, r := range ranked {
+		results[i] = dto.RankedBookmark{
+			BookmarkResponse: *s.modelToDTO(byCommand[r.ID]),
+			Score:            r.Score,
+		}
+	}
+
+	return &dto.RankedBookmarksResponse{
+		Results: results,
+		Count:   len(results),
+	}
+}
+
 // UpdateBookmark modifies an existing example
 func (s *bookmarkServiceImpl) UpdateBookmark(ctx context.Context, req dto.UpdateBookmarkRequest) (*dto.BookmarkResponse, error) {
+	// No other write may come between checking the expected version and writing the new one
+	if req.ExpectedUpdatedAt != nil && s.pending == nil {
+		var resp *dto.BookmarkResponse
+		err := s.inTransaction(ctx, func(tx *bookmarkServiceImpl) error {
+			var err error
+			resp, err = tx.UpdateBookmark(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+
 	// Get existing example
-	existing, err := s.repo.GetByCommand(ctx, req.Command)
+	existing, err := s.getByCommand(ctx, req.Command)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get example: %w", err)
 	}
+	if req.ExpectedUpdatedAt != nil {
+		if err := checkUnchanged(existing, *req.ExpectedUpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+	req.Command = existing.Command
+	before := *existing
+	req.NewCommand = normalizeCommand(req.NewCommand)
 
 	// Update fields if provided
 	if req.NewToolName != "" {
@@ -96,6 +436,60 @@ func (s *bookmarkServiceImpl) UpdateBookmark(ctx context.Context, req dto.Update
 	if req.NewDescription != "" {
 		existing.Description = req.NewDescription
 	}
+	if req.NewTags != nil {
+		tags, err := normalizeTags(req.NewTags)
+		if err != nil {
+			return nil, err
+		}
+		existing.Tags = tags
+	}
+	if req.NewLink != nil {
+		link := strings.TrimSpace(*req.NewLink)
+		if err := validateLink(link); err != nil {
+			return nil, err
+		}
+		existing.Link = link
+	}
+	if req.NewRemindEvery != nil {
+		period := strings.TrimSpace(*req.NewRemindEvery)
+		if err := validateRemindEvery(period); err != nil {
+			return nil, err
+		}
+		existing.RemindEvery = period
+	}
+	if req.NewHost != nil {
+		host := strings.TrimSpace(*req.NewHost)
+		if err := validateHost(host); err != nil {
+			return nil, err
+		}
+		existing.Host = host
+	}
+	if req.NewVariants != nil {
+		variants, err := normalizeVariants(req.NewVariants)
+		if err != nil {
+			return nil, err
+		}
+		existing.Variants = variants
+	}
+	if req.NewRequires != nil {
+		requires, err := normalizeRequires(req.NewRequires)
+		if err != nil {
+			return nil, err
+		}
+		existing.Requires = requires
+	}
+	if req.NewPrivate != nil {
+		existing.Private = *req.NewPrivate
+	}
+	command := existing.Command
+	if req.NewCommand != "" {
+		command = req.NewCommand
+	}
+	if err := refuseSecrets(command, existing.Variants, &before, req.AllowSecrets); err != nil {
+		return nil, err
+	}
+	s.addRevision(existing, &before)
+
 	if req.NewCommand != "" {
 		// If changing the command (primary key), check for conflicts
 		if req.NewCommand != req.Command {
@@ -104,65 +498,1142 @@ func (s *bookmarkServiceImpl) UpdateBookmark(ctx context.Context, req dto.Update
 				return nil, fmt.Errorf("failed to check new command existence: %w", err)
 			}
 			if exists {
-				return nil, fmt.Errorf("example with command '%s' already exists", req.NewCommand)
+				return nil, fmt.Errorf("example with command '%s' %w", req.NewCommand, models.ErrAlreadyExists)
 			}
 			// Delete old entry and create new one with new command
 			if err := s.repo.Delete(ctx, req.Command); err != nil {
 				return nil, fmt.Errorf("failed to delete old example: %w", err)
 			}
 			existing.Command = req.NewCommand
+			existing.UpdatedAt = time.Now()
 			if err := s.repo.Create(ctx, existing); err != nil {
 				return nil, fmt.Errorf("failed to create updated example: %w", err)
 			}
+			if err := s.record(ctx, audit.ActionEdit, before.Command, &before, existing); err != nil {
+				return nil, err
+			}
 			return s.modelToDTO(existing), nil
 		}
 	}
 
 	// Persist changes
+	if !existing.Equal(&before) {
+		existing.UpdatedAt = time.Now()
+	}
 	if err := s.repo.Update(ctx, existing); err != nil {
 		return nil, fmt.Errorf("failed to update example: %w", err)
 	}
 
+	if err := s.record(ctx, audit.ActionEdit, before.Command, &before, existing); err != nil {
+		return nil, err
+	}
+
 	return s.modelToDTO(existing), nil
 }
 
 // DeleteBookmark removes an example by command
 func (s *bookmarkServiceImpl) DeleteBookmark(ctx context.Context, command string) error {
+	// Resolve the stored command; the lookup also captures the bookmark so the history
+	// and hooks can show what was deleted
+	var before *models.Bookmark
+	if existing, err := s.getByCommand(ctx, command); err == nil {
+		command = existing.Command
+		before = existing
+	} else {
+		command = normalizeCommand(command)
+	}
+
 	if err := s.repo.Delete(ctx, command); err != nil {
 		return fmt.Errorf("failed to delete example: %w", err)
 	}
 
+	return s.record(ctx, audit.ActionDelete, command, before, nil)
+}
+
+// DeleteBookmarkIfUnchanged removes an example unless it was changed since it was read
+func (s *bookmarkServiceImpl) DeleteBookmarkIfUnchanged(ctx context.Context, command string, updatedAt time.Time) error {
+	return s.inTransaction(ctx, func(tx *bookmarkServiceImpl) error {
+		existing, err := tx.getByCommand(ctx, command)
+		if err != nil {
+			return fmt.Errorf("failed to get example: %w", err)
+		}
+		if err := checkUnchanged(existing, updatedAt); err != nil {
+			return err
+		}
+		return tx.DeleteBookmark(ctx, existing.Command)
+	})
+}
+
+// checkUnchanged fails with a conflict if a bookmark was changed since it was read with the
+// given update time, e.g. by another process sharing the store
+func checkUnchanged(bookmark *models.Bookmark, updatedAt time.Time) error {
+	if !bookmark.UpdatedAt.Equal(updatedAt) {
+		return fmt.Errorf("%w: '%s' was changed since it was read, reload it and try again", models.ErrConflict, bookmark.Command)
+	}
 	return nil
 }
 
 // DeleteToolBookmarks removes all examples for a tool name
 func (s *bookmarkServiceImpl) DeleteToolBookmarks(ctx context.Context, toolName string) error {
+	var deleted []*models.Bookmark
+	if s.auditLog != nil || s.hooks != nil || len(s.onChange) > 0 {
+		existing, err := s.repo.ListByToolName(ctx, toolName)
+		if err != nil {
+			return fmt.Errorf("failed to list tool examples: %w", err)
+		}
+		// Bookmarks of read-only layers are kept by the repository
+		for _, bookmark := range existing {
+			if bookmark.Origin == "" {
+				deleted = append(deleted, bookmark)
+			}
+		}
+	}
+
 	if err := s.repo.DeleteByToolName(ctx, toolName); err != nil {
 		return fmt.Errorf("failed to delete tool examples: %w", err)
 	}
 
+	for _, bookmark := range deleted {
+		if err := s.record(ctx, audit.ActionDelete, bookmark.Command, bookmark, nil); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// validateCreateRequest validates the create example request
-func (s *bookmarkServiceImpl) validateCreateRequest(req dto.CreateBookmarkRequest) error {
-	if strings.TrimSpace(req.Command) == "" {
-		return fmt.Errorf("command cannot be empty")
+// MergeTool reassigns all examples of the source tool to the target tool
+// With dedupe, examples of the target tool whose commands only differ by whitespace
+// are merged afterwards. Examples of read-only layers cannot be changed and are skipped.
+// The store is changed in a transaction, so a failure leaves it unchanged.
+func (s *bookmarkServiceImpl) MergeTool(ctx context.Context, source, target string, dedupe bool) (*dto.MergeToolResult, error) {
+	source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+	if source == "" || target == "" {
+		return nil, fmt.Errorf("%w: source and target tool names are required", models.ErrValidation)
 	}
-	if strings.TrimSpace(req.ToolName) == "" {
-		return fmt.Errorf("tool name cannot be empty")
+	if source == target {
+		return nil, fmt.Errorf("%w: source and target tool are the same", models.ErrValidation)
 	}
-	if strings.TrimSpace(req.Description) == "" {
-		return fmt.Errorf("description cannot be empty")
+
+	examples, err := s.repo.ListByToolName(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool examples: %w", err)
 	}
-	return nil
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("examples for tool '%s' %w", source, models.ErrNotFound)
+	}
+
+	result := &dto.MergeToolResult{}
+	err = s.inTransaction(ctx, func(tx *bookmarkServiceImpl) error {
+		for _, example := range examples {
+			if example.Origin != "" {
+				result.Skipped++
+				continue
+			}
+
+			before := *example
+			example.ToolName = target
+			example.UpdatedAt = time.Now()
+			tx.addRevision(example, &before)
+			if err := tx.repo.Update(ctx, example); err != nil {
+				return fmt.Errorf("failed to update example '%s': %w", example.Command, err)
+			}
+			if err := tx.record(ctx, audit.ActionEdit, example.Command, &before, example); err != nil {
+				return err
+			}
+			result.Reassigned++
+		}
+
+		if !dedupe {
+			return nil
+		}
+		merged, err := tx.repo.ListByToolName(ctx, target)
+		if err != nil {
+			return fmt.Errorf("failed to list tool examples: %w", err)
+		}
+		var writable []*models.Bookmark
+		for _, example := range merged {
+			if example.Origin == "" {
+				writable = append(writable, example)
+			}
+		}
+		normalized, err := normalizeBookmarks(ctx, tx.repo, writable, normalizeCommand, false)
+		if err != nil {
+			return err
+		}
+		result.Merged = normalized.Merged
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
-// modelToDTO converts a domain model to a DTO
-func (s *bookmarkServiceImpl) modelToDTO(example *models.Bookmark) *dto.BookmarkResponse {
-	return &dto.BookmarkResponse{
-		Command:     example.Command,
-		ToolName:    example.ToolName,
-		Description: example.Description,
+// ReplaceInCommands replaces literal text or regular expression matches in stored commands
+// All changes are checked before any is written: a command may not become empty or collide
+// with another bookmark. The changes are written in a transaction and recorded in the history
+// like edits.
+func (s *bookmarkServiceImpl) ReplaceInCommands(ctx context.Context, req dto.ReplaceRequest) (*dto.ReplaceResult, error) {
+	if req.Find == "" {
+		return nil, fmt.Errorf("%w: the text to find cannot be empty", models.ErrValidation)
+	}
+	pattern := regexp.QuoteMeta(req.Find)
+	replacement := strings.ReplaceAll(req.Replace, "$", "$$")
+	if req.Regex {
+		pattern, replacement = req.Find, req.Replace
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid regular expression '%s': %v", models.ErrValidation, req.Find, err)
+	}
+
+	var examples []*models.Bookmark
+	if toolName := strings.TrimSpace(req.ToolName); toolName != "" {
+		examples, err = s.repo.ListByToolName(ctx, toolName)
+	} else {
+		examples, err = s.repo.List(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	result := &dto.ReplaceResult{Changes: []dto.CommandChange{}}
+	targets := map[string]string{}
+	for _, example := range examples {
+		if !re.MatchString(example.Command) {
+			continue
+		}
+		command := normalizeCommand(re.ReplaceAllString(example.Command, replacement))
+		if command == example.Command {
+			continue
+		}
+		if example.Origin != "" {
+			result.Skipped++
+			continue
+		}
+
+		if command == "" {
+			return nil, fmt.Errorf("%w: replacing would leave the command '%s' empty", models.ErrValidation, example.Command)
+		}
+		if other, ok := targets[command]; ok {
+			return nil, fmt.Errorf("%w: '%s' and '%s' would both become '%s'", models.ErrConflict, other, example.Command, command)
+		}
+		exists, err := s.repo.Exists(ctx, command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check example existence: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: '%s' would become '%s', which is already bookmarked", models.ErrConflict, example.Command, command)
+		}
+		targets[command] = example.Command
+		result.Changes = append(result.Changes, dto.CommandChange{From: example.Command, To: command})
+	}
+
+	if req.DryRun {
+		return result, nil
+	}
+	err = s.inTransaction(ctx, func(tx *bookmarkServiceImpl) error {
+		for _, change := range result.Changes {
+			if _, err := tx.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{Command: change.From, NewCommand: change.To}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importEntry is one validated entry of an import and the bookmark it would replace
+type importEntry struct {
+	req      dto.CreateBookmarkRequest
+	existing *models.Bookmark // Nil for new commands
+	same     bool             // The existing bookmark has identical content
+}
+
+// planImport validates import entries and looks up the bookmarks they collide with
+func (s *bookmarkServiceImpl) planImport(ctx context.Context, reqs []dto.CreateBookmarkRequest, strategy dto.ConflictStrategy) ([]importEntry, error) {
+	switch strategy {
+	case dto.ConflictSkip, dto.ConflictOverwrite, dto.ConflictFail:
+	default:
+		return nil, fmt.Errorf("%w: unknown conflict strategy '%s' (use skip, overwrite or fail)", models.ErrValidation, strategy)
 	}
+
+	entries := make([]importEntry, 0, len(reqs))
+	seen := map[string]bool{}
+	var refused []string // Entries with likely credentials, with what was found
+
+	for i, req := range reqs {
+		req.Command = normalizeCommand(req.Command)
+		if err := s.validateCreateRequest(req); err != nil {
+			return nil, fmt.Errorf("invalid entry %d: %w", i+1, err)
+		}
+		tags, err := normalizeTags(req.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry %d: %w", i+1, err)
+		}
+		req.Tags = tags
+		req.Link = strings.TrimSpace(req.Link)
+		req.RemindEvery = strings.TrimSpace(req.RemindEvery)
+		req.Host = strings.TrimSpace(req.Host)
+		if req.Variants, err = normalizeVariants(req.Variants); err != nil {
+			return nil, fmt.Errorf("invalid entry %d: %w", i+1, err)
+		}
+		if req.Requires, err = normalizeRequires(req.Requires); err != nil {
+			return nil, fmt.Errorf("invalid entry %d: %w", i+1, err)
+		}
+
+		if seen[req.Command] {
+			return nil, fmt.Errorf("%w: entry %d duplicates command '%s'", models.ErrValidation, i+1, req.Command)
+		}
+		seen[req.Command] = true
+
+		exists, err := s.repo.Exists(ctx, req.Command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check example existence: %w", err)
+		}
+		var existing *models.Bookmark
+		if exists {
+			if existing, err = s.repo.GetByCommand(ctx, req.Command); err != nil {
+				return nil, fmt.Errorf("failed to get example: %w", err)
+			}
+		}
+		if found := newSecrets(req.Command, req.Variants, existing); len(found) > 0 && !req.AllowSecrets {
+			refused = append(refused, secrets.MaskAll(req.Command)+" ("+strings.Join(found, ", ")+")")
+		}
+		if !exists {
+			entries = append(entries, importEntry{req: req})
+			continue
+		}
+
+		incoming := &models.Bookmark{Command: req.Command, ToolName: req.ToolName, Description: req.Description, Tags: tags, Link: req.Link, RemindEvery: req.RemindEvery, Host: req.Host, Variants: req.Variants, Requires: req.Requires, Private: req.Private}
+		entries = append(entries, importEntry{req: req, existing: existing, same: existing.Equal(incoming)})
+	}
+
+	if len(refused) > 0 {
+		return nil, fmt.Errorf("%w: %d bookmarks contain likely secrets, nothing was imported; read them from environment variables or placeholders instead, or allow secrets to import them anyway: %s",
+			models.ErrValidation, len(refused), strings.Join(refused, "; "))
+	}
+	return entries, nil
+}
+
+// ImportBookmarks creates many examples at once
+// Entries identical to an existing bookmark are skipped. Entries whose command exists with
+// different content are conflicts, handled according to strategy. All entries are validated
+// and conflicts are detected before anything is written, and the entries are written in a
+// transaction, so a failure leaves the store unchanged.
+func (s *bookmarkServiceImpl) ImportBookmarks(ctx context.Context, reqs []dto.CreateBookmarkRequest, strategy dto.ConflictStrategy) (*dto.ImportResult, error) {
+	entries, err := s.planImport(ctx, reqs, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ImportResult{Conflicts: []string{}}
+	var toCreate, toOverwrite []dto.CreateBookmarkRequest
+
+	for _, entry := range entries {
+		switch {
+		case entry.existing == nil:
+			toCreate = append(toCreate, entry.req)
+		case entry.same:
+			result.Skipped++
+		default:
+			result.Conflicts = append(result.Conflicts, entry.req.Command)
+			if strategy == dto.ConflictOverwrite {
+				toOverwrite = append(toOverwrite, entry.req)
+			} else {
+				result.Skipped++
+			}
+		}
+	}
+
+	if strategy == dto.ConflictFail && len(result.Conflicts) > 0 {
+		return result, fmt.Errorf("%w: import aborted, %d bookmarks differ from existing entries", models.ErrConflict, len(result.Conflicts))
+	}
+
+	err = s.inTransaction(ctx, func(tx *bookmarkServiceImpl) error {
+		for _, req := range toCreate {
+			if _, err := tx.CreateBookmark(ctx, req); err != nil {
+				return err
+			}
+			result.Added++
+		}
+		for _, req := range toOverwrite {
+			if _, err := tx.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{
+				Command:        req.Command,
+				NewToolName:    req.ToolName,
+				NewDescription: req.Description,
+				NewTags:        append([]string{}, req.Tags...),
+				NewLink:        &req.Link,
+				NewRemindEvery: &req.RemindEvery,
+				NewHost:        &req.Host,
+				NewVariants:    nonNilVariants(req.Variants),
+				NewRequires:    append([]string{}, req.Requires...),
+				NewPrivate:     &req.Private,
+				AllowSecrets:   req.AllowSecrets,
+			}); err != nil {
+				return err
+			}
+			result.Updated++
+		}
+		return nil
+	})
+	if err != nil {
+		result.Added, result.Updated = 0, 0 // Nothing was written
+		return result, err
+	}
+
+	s.logger.InfoContext(ctx, "imported bookmarks", "added", result.Added, "updated", result.Updated, "skipped", result.Skipped, "strategy", strategy)
+	return result, nil
+}
+
+// PreviewImport reports what ImportBookmarks would do with the same arguments, without writing
+// Conflicting entries are changed with the overwrite strategy and skipped otherwise.
+func (s *bookmarkServiceImpl) PreviewImport(ctx context.Context, reqs []dto.CreateBookmarkRequest, strategy dto.ConflictStrategy) (*dto.ImportPreview, error) {
+	entries, err := s.planImport(ctx, reqs, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &dto.ImportPreview{Entries: []dto.ImportPreviewEntry{}, Conflicts: []string{}}
+	for _, entry := range entries {
+		item := dto.ImportPreviewEntry{Bookmark: entry.req}
+		switch {
+		case entry.existing == nil:
+			item.Status = dto.ImportAdded
+			preview.Added++
+		case entry.same:
+			item.Status = dto.ImportSkipped
+			preview.Skipped++
+		default:
+			item.Existing = s.modelToDTO(entry.existing)
+			preview.Conflicts = append(preview.Conflicts, entry.req.Command)
+			if strategy == dto.ConflictOverwrite {
+				item.Status = dto.ImportChanged
+				preview.Changed++
+			} else {
+				item.Status = dto.ImportSkipped
+				preview.Skipped++
+			}
+		}
+		preview.Entries = append(preview.Entries, item)
+	}
+
+	return preview, nil
+}
+
+// RecordRun records that a bookmarked command was selected for execution
+func (s *bookmarkServiceImpl) RecordRun(ctx context.Context, command string) error {
+	return s.record(ctx, audit.ActionRun, normalizeCommand(command), nil, nil)
+}
+
+// RecordRunResult records the exit code and duration of an executed bookmark
+func (s *bookmarkServiceImpl) RecordRunResult(ctx context.Context, command string, exitCode int, duration time.Duration) error {
+	if s.auditLog == nil {
+		return nil
+	}
+	event := audit.Event{
+		Time:    time.Now(),
+		Action:  audit.ActionResult,
+		Command: normalizeCommand(command),
+		Result:  &audit.Result{ExitCode: exitCode, Duration: duration},
+	}
+	if err := s.auditLog.Append(event); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record run result", "command", event.Command, "error", err)
+		return fmt.Errorf("failed to record run result: %w", err)
+	}
+	return nil
+}
+
+// LastRun retrieves the result of the latest execution of a command, nil if it never ran
+// Results recorded before the command was edited count as runs of the edited command.
+func (s *bookmarkServiceImpl) LastRun(ctx context.Context, command string) (*dto.RunResult, error) {
+	if s.auditLog == nil {
+		return nil, nil
+	}
+	events, err := s.auditLog.Events()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	command = normalizeCommand(command)
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		switch {
+		case event.Action == audit.ActionResult && event.Command == command && event.Result != nil:
+			return &dto.RunResult{Time: event.Time, ExitCode: event.Result.ExitCode, Duration: event.Result.Duration}, nil
+		case event.Action == audit.ActionEdit && event.Before != nil && event.After != nil && event.After.Command == command:
+			command = event.Before.Command
+		case event.Action == audit.ActionCreate && event.Command == command:
+			// Runs before the bookmark was created belong to an earlier, deleted bookmark
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+// ListDue retrieves the bookmarks with a reminder period that has elapsed since they last ran
+// Bookmarks that never ran are due right away. Selecting a bookmark in the TUI counts as a
+// run. Bookmarks that never ran come first, then the longest overdue ones.
+func (s *bookmarkServiceImpl) ListDue(ctx context.Context, now time.Time) (*dto.DueBookmarksResponse, error) {
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	var events []audit.Event
+	if s.auditLog != nil {
+		if events, err = s.auditLog.Events(); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+	lastRuns := lastEventTimes(events, audit.ActionRun, audit.ActionResult)
+
+	due := []dto.DueBookmark{}
+	for _, example := range examples {
+		if example.RemindEvery == "" {
+			continue
+		}
+		period, err := utils.ParsePeriod(example.RemindEvery)
+		if err != nil || period == 0 {
+			// Only hand-edited stores can hold an invalid period; don't fail the listing for it
+			continue
+		}
+
+		entry := dto.DueBookmark{BookmarkResponse: *s.modelToDTO(example)}
+		if lastRun, ok := lastRuns[example.Command]; ok {
+			dueAt := lastRun.Add(period)
+			if now.Before(dueAt) {
+				continue
+			}
+			entry.LastRun, entry.DueAt = &lastRun, &dueAt
+		}
+		due = append(due, entry)
+	}
+
+	slices.SortStableFunc(due, func(a, b dto.DueBookmark) int {
+		switch {
+		case a.DueAt == nil && b.DueAt == nil:
+			return 0
+		case a.DueAt == nil:
+			return -1
+		case b.DueAt == nil:
+			return 1
+		}
+		return a.DueAt.Compare(*b.DueAt)
+	})
+
+	return &dto.DueBookmarksResponse{Bookmarks: due, Count: len(due)}, nil
+}
+
+// ListUnused retrieves the bookmarks of the user's store that were neither run nor added since
+// the given time, least recently used first. Bookmarks without a run or creation in the
+// history, e.g. stored before it was started, count as unused unless they were changed since
+// the given time. Bookmarks with a reminder are meant to run rarely and are left out.
+func (s *bookmarkServiceImpl) ListUnused(ctx context.Context, since time.Time) (*dto.UnusedBookmarksResponse, error) {
+	if s.auditLog == nil {
+		return nil, fmt.Errorf("%w: unused bookmarks are found in the history, which is not recorded", models.ErrValidation)
+	}
+	events, err := s.auditLog.Events()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	lastUses := lastEventTimes(events, audit.ActionRun, audit.ActionResult, audit.ActionCreate)
+
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	unused := []dto.UnusedBookmark{}
+	for _, example := range examples {
+		if example.Origin != "" || example.RemindEvery != "" {
+			continue
+		}
+		entry := dto.UnusedBookmark{BookmarkResponse: *s.modelToDTO(example)}
+		if lastUse, ok := lastUses[example.Command]; ok {
+			if !lastUse.Before(since) {
+				continue
+			}
+			entry.LastUsed = &lastUse
+		} else if !example.UpdatedAt.IsZero() && !example.UpdatedAt.Before(since) {
+			continue
+		}
+		unused = append(unused, entry)
+	}
+
+	slices.SortStableFunc(unused, func(a, b dto.UnusedBookmark) int {
+		switch {
+		case a.LastUsed == nil && b.LastUsed == nil:
+			return 0
+		case a.LastUsed == nil:
+			return -1
+		case b.LastUsed == nil:
+			return 1
+		}
+		return a.LastUsed.Compare(*b.LastUsed)
+	})
+
+	return &dto.UnusedBookmarksResponse{Bookmarks: unused, Count: len(unused)}, nil
+}
+
+// lastEventTimes returns when each command last had one of the given actions, following renames
+func lastEventTimes(events []audit.Event, actions ...audit.Action) map[string]time.Time {
+	last := map[string]time.Time{}
+	for _, event := range events {
+		switch {
+		case slices.Contains(actions, event.Action):
+			if event.Time.After(last[event.Command]) {
+				last[event.Command] = event.Time
+			}
+		case event.Action == audit.ActionEdit:
+			if event.Before == nil || event.After == nil || event.Before.Command == event.After.Command {
+				continue
+			}
+			if t, ok := last[event.Before.Command]; ok {
+				last[event.After.Command] = t
+				delete(last, event.Before.Command)
+			}
+		case event.Action == audit.ActionDelete:
+			delete(last, event.Command)
+		}
+	}
+	return last
+}
+
+// GetHistory retrieves the audit log, optionally filtered by command
+// A change of the command itself matches both the old and the new command
+func (s *bookmarkServiceImpl) GetHistory(ctx context.Context, command string) (*dto.HistoryResponse, error) {
+	entries := []dto.HistoryEntry{}
+	if s.auditLog == nil {
+		return &dto.HistoryResponse{Entries: entries}, nil
+	}
+	command = normalizeCommand(command)
+
+	events, err := s.auditLog.Events()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	for _, event := range events {
+		// Searches only count towards metrics
+		if event.Action == audit.ActionSearch {
+			continue
+		}
+		if command != "" && !eventMatchesCommand(event, command) {
+			continue
+		}
+
+		entry := dto.HistoryEntry{
+			Time:    event.Time,
+			Action:  string(event.Action),
+			Command: event.Command,
+		}
+		if event.Before != nil {
+			entry.Before = s.modelToDTO(event.Before)
+		}
+		if event.After != nil {
+			entry.After = s.modelToDTO(event.After)
+		}
+		if event.Result != nil {
+			entry.Result = &dto.RunResult{Time: event.Time, ExitCode: event.Result.ExitCode, Duration: event.Result.Duration}
+		}
+		entries = append(entries, entry)
+	}
+
+	return &dto.HistoryResponse{
+		Entries: entries,
+		Count:   len(entries),
+	}, nil
+}
+
+// CheckStore verifies that the store can be read and, with writable, written
+func (s *bookmarkServiceImpl) CheckStore(ctx context.Context, writable bool) error {
+	if _, err := s.repo.CountByToolName(ctx); err != nil {
+		return fmt.Errorf("store is not readable: %w", err)
+	}
+	if writable {
+		if err := repository.CheckWritable(ctx, s.repo); err != nil {
+			return fmt.Errorf("store is not writable: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetMetrics summarizes the activity recorded in the audit log since the given time
+// Top lists hold at most limit entries; a limit <= 0 keeps all of them.
+func (s *bookmarkServiceImpl) GetMetrics(ctx context.Context, since time.Time, limit int) (*dto.MetricsResponse, error) {
+	metrics := &dto.MetricsResponse{Since: since, TopRuns: []dto.ActivityCount{}, TopSearches: []dto.ActivityCount{}, Unused: []string{}}
+
+	var events []audit.Event
+	if s.auditLog != nil {
+		var err error
+		events, err = s.auditLog.Events()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+
+	runs, searches := map[string]int{}, map[string]int{}
+	for _, event := range events {
+		if event.Time.Before(since) {
+			continue
+		}
+		switch event.Action {
+		case audit.ActionCreate:
+			metrics.Adds++
+		case audit.ActionEdit:
+			metrics.Edits++
+		case audit.ActionDelete:
+			metrics.Deletes++
+		case audit.ActionRun:
+			metrics.Runs++
+			runs[event.Command]++
+		case audit.ActionSearch:
+			metrics.Searches++
+			searches[strings.ToLower(strings.TrimSpace(event.Query))]++
+		}
+	}
+	metrics.TopRuns = topActivity(runs, limit)
+	metrics.TopSearches = topActivity(searches, limit)
+
+	examples, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+	metrics.Bookmarks = len(examples)
+	for _, example := range examples {
+		if runs[example.Command] == 0 {
+			metrics.Unused = append(metrics.Unused, example.Command)
+		}
+	}
+
+	return metrics, nil
+}
+
+// topActivity sorts counted values by count, then alphabetically, keeping at most limit
+func topActivity(counts map[string]int, limit int) []dto.ActivityCount {
+	result := []dto.ActivityCount{}
+	for value, count := range counts {
+		result = append(result, dto.ActivityCount{Value: value, Count: count})
+	}
+	slices.SortFunc(result, func(a, b dto.ActivityCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// ListRevisions retrieves the current version and previous revisions of a bookmark
+func (s *bookmarkServiceImpl) ListRevisions(ctx context.Context, command string) (*dto.RevisionsResponse, error) {
+	bookmark, err := s.getByCommand(ctx, command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get example: %w", err)
+	}
+
+	revisions := make([]dto.RevisionResponse, len(bookmark.Revisions))
+	for i, rev := range bookmark.Revisions {
+		revisions[i] = dto.RevisionResponse{
+			Number:      i + 1,
+			Command:     rev.Command,
+			ToolName:    rev.ToolName,
+			Description: rev.Description,
+			Tags:        rev.Tags,
+			Link:        rev.Link,
+			ChangedAt:   rev.ChangedAt,
+		}
+	}
+
+	return &dto.RevisionsResponse{
+		Current:   *s.modelToDTO(bookmark),
+		Revisions: revisions,
+		Count:     len(revisions),
+	}, nil
+}
+
+// RestoreRevision replaces a bookmark with one of its previous revisions
+// The replaced version is kept as a new revision, so a restore can be undone
+func (s *bookmarkServiceImpl) RestoreRevision(ctx context.Context, command string, number int) (*dto.BookmarkResponse, error) {
+	bookmark, err := s.getByCommand(ctx, command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get example: %w", err)
+	}
+
+	if number < 1 || number > len(bookmark.Revisions) {
+		return nil, fmt.Errorf("revision %d %w (bookmark has %d revisions)", number, models.ErrNotFound, len(bookmark.Revisions))
+	}
+	rev := bookmark.Revisions[number-1]
+
+	return s.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{
+		Command:        bookmark.Command,
+		NewCommand:     rev.Command,
+		NewToolName:    rev.ToolName,
+		NewDescription: rev.Description,
+		NewTags:        append([]string{}, rev.Tags...), // Non-nil so missing tags are restored as well
+		NewLink:        &rev.Link,
+	})
+}
+
+// addRevision stores the previous version of an edited bookmark, keeping at most maxRevisions
+func (s *bookmarkServiceImpl) addRevision(bookmark, previous *models.Bookmark) {
+	// Revisions don't keep the reminder, host, variants, requirements and private flag, so changing only those records none
+	unchanged := *previous
+	unchanged.RemindEvery, unchanged.Host, unchanged.Variants = bookmark.RemindEvery, bookmark.Host, bookmark.Variants
+	unchanged.Requires, unchanged.Private = bookmark.Requires, bookmark.Private
+	if s.maxRevisions <= 0 || bookmark.Equal(&unchanged) {
+		return
+	}
+
+	bookmark.Revisions = append(bookmark.Revisions, models.Revision{
+		Command:     previous.Command,
+		ToolName:    previous.ToolName,
+		Description: previous.Description,
+		Tags:        previous.Tags,
+		Link:        previous.Link,
+		ChangedAt:   time.Now(),
+	})
+	if excess := len(bookmark.Revisions) - s.maxRevisions; excess > 0 {
+		bookmark.Revisions = bookmark.Revisions[excess:]
+	}
+}
+
+// hookEvents maps recorded actions to the hooks run after them
+var hookEvents = map[audit.Action]hooks.Event{
+	audit.ActionCreate: hooks.EventAdd,
+	audit.ActionEdit:   hooks.EventEdit,
+	audit.ActionDelete: hooks.EventDelete,
+	audit.ActionRun:    hooks.EventSelect,
+}
+
+// record appends an event to the audit log and runs the action's hook, if configured
+// Changes, unlike runs, are also reported to the change listener.
+func (s *bookmarkServiceImpl) record(ctx context.Context, action audit.Action, command string, before, after *models.Bookmark) error {
+	if s.pending != nil && action != audit.ActionRun {
+		*s.pending = append(*s.pending, change{action, command, before, after})
+		return nil
+	}
+	s.logger.DebugContext(ctx, "bookmark "+string(action), "command", command)
+	if action != audit.ActionRun {
+		for _, fn := range s.onChange {
+			fn()
+		}
+	}
+	if err := s.appendAudit(action, command, before, after); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record history", "action", action, "command", command, "error", err)
+		return err
+	}
+	if err := s.runHook(ctx, action, command, before, after); err != nil {
+		s.logger.ErrorContext(ctx, "hook failed", "action", action, "command", command, "error", err)
+		return err
+	}
+	return nil
+}
+
+// appendAudit appends an event to the audit log if one is configured
+func (s *bookmarkServiceImpl) appendAudit(action audit.Action, command string, before, after *models.Bookmark) error {
+	if s.auditLog == nil {
+		return nil
+	}
+
+	// Store copies so later changes to the models don't alter recorded history
+	event := audit.Event{
+		Time:    time.Now(),
+		Action:  action,
+		Command: command,
+	}
+	if before != nil {
+		snapshot := *before
+		snapshot.Revisions = nil
+		event.Before = &snapshot
+	}
+	if after != nil {
+		snapshot := *after
+		snapshot.Revisions = nil
+		event.After = &snapshot
+	}
+	if err := s.auditLog.Append(event); err != nil {
+		return fmt.Errorf("change applied but failed to record history: %w", err)
+	}
+
+	return nil
+}
+
+// recordSearch appends a search to the audit log for the activity metrics
+// Recording is best effort: a failing history must not hide search results.
+func (s *bookmarkServiceImpl) recordSearch(query string) {
+	if s.auditLog == nil || strings.TrimSpace(query) == "" {
+		return
+	}
+	if err := s.auditLog.Append(audit.Event{Time: time.Now(), Action: audit.ActionSearch, Query: query}); err != nil {
+		s.logger.Warn("failed to record search", "query", query, "error", err)
+	}
+}
+
+// runHook runs the hook of an action with the affected bookmark
+func (s *bookmarkServiceImpl) runHook(ctx context.Context, action audit.Action, command string, before, after *models.Bookmark) error {
+	if s.hooks == nil {
+		return nil
+	}
+
+	bookmark, previous := after, ""
+	switch {
+	case after == nil && before != nil:
+		bookmark = before
+	case after == nil:
+		// Runs only carry the command; hooks get the bookmark's fields if it is known
+		bookmark = &models.Bookmark{Command: command}
+		if existing, err := s.repo.GetByCommand(ctx, command); err == nil {
+			bookmark = existing
+		}
+	case before != nil && before.Command != after.Command:
+		previous = before.Command
+	}
+
+	if err := s.hooks.Fire(ctx, hookEvents[action], bookmark, previous); err != nil {
+		return fmt.Errorf("change applied but %w", err)
+	}
+	return nil
+}
+
+// matchesAllTerms reports whether every (lowercase) term occurs in one of the example's fields
+func matchesAllTerms(example *models.Bookmark, terms []string) bool {
+	haystack := strings.ToLower(strings.Join([]string{example.Command, example.ToolName, example.Description, strings.Join(example.Tags, " ")}, "\n"))
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// eventMatchesCommand reports whether an event concerns the given command
+func eventMatchesCommand(event audit.Event, command string) bool {
+	if event.Command == command {
+		return true
+	}
+	if event.After != nil && event.After.Command == command {
+		return true
+	}
+	return event.Before != nil && event.Before.Command == command
+}
+
+// validateCreateRequest validates the create example request
+func (s *bookmarkServiceImpl) validateCreateRequest(req dto.CreateBookmarkRequest) error {
+	if strings.TrimSpace(req.Command) == "" {
+		return fmt.Errorf("%w: command cannot be empty", models.ErrValidation)
+	}
+	if strings.TrimSpace(req.ToolName) == "" {
+		return fmt.Errorf("%w: tool name cannot be empty", models.ErrValidation)
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		return fmt.Errorf("%w: description cannot be empty", models.ErrValidation)
+	}
+	if err := validateLink(strings.TrimSpace(req.Link)); err != nil {
+		return err
+	}
+	if err := validateRemindEvery(strings.TrimSpace(req.RemindEvery)); err != nil {
+		return err
+	}
+	return validateHost(strings.TrimSpace(req.Host))
+}
+
+// validateLink checks that a bookmark link is empty or an absolute http(s) URL
+func validateLink(link string) error {
+	if link == "" {
+		return nil
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: link '%s' must be an http or https URL", models.ErrValidation, link)
+	}
+	return nil
+}
+
+// validateRemindEvery checks that a reminder period is empty or a positive period like 30d
+func validateRemindEvery(period string) error {
+	if period == "" {
+		return nil
+	}
+	d, err := utils.ParsePeriod(period)
+	if err != nil {
+		return fmt.Errorf("%w: remind_every: %v", models.ErrValidation, err)
+	}
+	if d == 0 {
+		return fmt.Errorf("%w: remind_every '%s' must be longer than zero", models.ErrValidation, period)
+	}
+	return nil
+}
+
+// validHost matches SSH destinations such as prod-db, user@10.0.0.5 or [::1]
+var validHost = regexp.MustCompile(`^[A-Za-z0-9_\[][A-Za-z0-9_.@:%\[\]-]*$`)
+
+// validateHost checks that an SSH host is empty or a single destination like user@prod-db
+// A leading '-' would be taken as an ssh option.
+func validateHost(host string) error {
+	if host == "" {
+		return nil
+	}
+	if !validHost.MatchString(host) {
+		return fmt.Errorf("%w: host '%s' must be an SSH destination like prod-db or user@prod-db", models.ErrValidation, host)
+	}
+	return nil
+}
+
+// variantOS are the operating systems commands can have variants for, as GOOS values
+var variantOS = []string{"darwin", "linux", "windows", "freebsd", "openbsd", "netbsd"}
+
+// normalizeVariants trims OS variants, drops empty commands and checks the operating systems
+// It returns nil when no variant is left.
+func normalizeVariants(variants map[string]string) (map[string]string, error) {
+	normalized := map[string]string{}
+	for goos, command := range variants {
+		goos = strings.ToLower(strings.TrimSpace(goos))
+		command = strings.TrimSpace(command)
+		if command == "" {
+			continue
+		}
+		if !slices.Contains(variantOS, goos) {
+			return nil, fmt.Errorf("%w: unknown operating system '%s' for a variant (supported: %s)", models.ErrValidation, goos, strings.Join(variantOS, ", "))
+		}
+		normalized[goos] = command
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// nonNilVariants returns variants, or an empty map for nil so that an update clears them
+func nonNilVariants(variants map[string]string) map[string]string {
+	if variants == nil {
+		return map[string]string{}
+	}
+	return variants
+}
+
+// normalizeRequires trims, sorts and de-duplicates requirements
+// A requirement is an environment variable like AWS_PROFILE or an executable like aws.
+func normalizeRequires(requires []string) ([]string, error) {
+	var normalized []string
+	for _, requirement := range requires {
+		requirement = strings.TrimSpace(requirement)
+		if requirement == "" {
+			continue
+		}
+		if strings.ContainsAny(requirement, " \t\n,$") {
+			return nil, fmt.Errorf("%w: requirement '%s' must be an environment variable like AWS_PROFILE or an executable like aws", models.ErrValidation, requirement)
+		}
+		normalized = append(normalized, requirement)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+// modelToDTO converts a domain model to a DTO
+func (s *bookmarkServiceImpl) modelToDTO(example *models.Bookmark) *dto.BookmarkResponse {
+	return &dto.BookmarkResponse{
+		Command:     example.Command,
+		ToolName:    example.ToolName,
+		Description: example.Description,
+		Tags:        example.Tags,
+		Link:        example.Link,
+		RemindEvery: example.RemindEvery,
+		Host:        example.Host,
+		Variants:    example.Variants,
+		Requires:    example.Requires,
+		Private:     example.Private,
+		Author:      example.Author,
+		UpdatedAt:   example.UpdatedAt,
+		Origin:      example.Origin,
+	}
+}
+
+// getByCommand looks up a bookmark by its normalized command
+// Bookmarks stored before commands were normalized are still found by their exact command.
+func (s *bookmarkServiceImpl) getByCommand(ctx context.Context, command string) (*models.Bookmark, error) {
+	normalized := normalizeCommand(command)
+	example, err := s.repo.GetByCommand(ctx, normalized)
+	if err != nil && normalized != command && errors.Is(err, models.ErrNotFound) {
+		return s.repo.GetByCommand(ctx, command)
+	}
+	return example, err
+}
+
+// normalizeCommand brings a command into the form it is stored and looked up in
+// Single-line commands are trimmed and runs of spaces and tabs collapse to one space,
+// except inside quotes, so "kubectl  get pods" and "kubectl get pods" are the same bookmark.
+// Multi-line commands (scripts) keep their indentation so they are stored as YAML block
+// scalars: line endings become \n and trailing whitespace of each line and the script is removed.
+func normalizeCommand(command string) string {
+	if !strings.ContainsAny(command, "\r\n") {
+		return collapseWhitespace(strings.TrimSpace(command))
+	}
+
+	lines := strings.Split(strings.ReplaceAll(command, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// collapseWhitespace replaces runs of unquoted spaces and tabs with a single space
+// Whitespace within single or double quotes and escaped characters are kept as they are.
+func collapseWhitespace(command string) string {
+	var b strings.Builder
+	b.Grow(len(command))
+
+	var quote rune
+	escaped, space := false, false
+	for _, r := range command {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ' ' || r == '\t':
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalizeTags lowercases, trims, sorts and de-duplicates tags
+// Tags may not contain whitespace or commas
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if strings.ContainsAny(tag, " \t\n,") {
+			return nil, fmt.Errorf("%w: tag '%s' cannot contain whitespace or commas", models.ErrValidation, tag)
+		}
+		normalized = append(normalized, tag)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
 }
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// MigrationProgress is called after each bookmark has been processed
+type MigrationProgress func(done, total int, bookmark *models.Bookmark)
+
+// MigrationResult summarizes a migration between two repositories
+type MigrationResult struct {
+	Total    int // Bookmarks found in the source
+	Migrated int // Bookmarks written to the destination
+	Skipped  int // Identical bookmarks already present in the destination
+}
+
+// MigrateBookmarks copies every bookmark from src into dst and verifies the result.
+// Bookmarks already present in dst with identical fields are skipped; a bookmark
+// with the same command but different fields is reported as a conflict.
+func MigrateBookmarks(ctx context.Context, src, dst repository.BookmarkRepository, progress MigrationProgress) (*MigrationResult, error) {
+	bookmarks, err := src.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source bookmarks: %w", err)
+	}
+
+	result := &MigrationResult{Total: len(bookmarks)}
+	for i, bookmark := range bookmarks {
+		exists, err := dst.Exists(ctx, bookmark.Command)
+		if err != nil {
+			return result, fmt.Errorf("failed to check destination for '%s': %w", bookmark.Command, err)
+		}
+
+		if exists {
+			existing, err := dst.GetByCommand(ctx, bookmark.Command)
+			if err != nil {
+				return result, fmt.Errorf("failed to read destination bookmark '%s': %w", bookmark.Command, err)
+			}
+			if !existing.Equal(bookmark) {
+				return result, fmt.Errorf("%w: destination already contains a different bookmark for '%s'", models.ErrConflict, bookmark.Command)
+			}
+			result.Skipped++
+		} else {
+			if err := dst.Create(ctx, bookmark); err != nil {
+				return result, fmt.Errorf("failed to write bookmark '%s': %w", bookmark.Command, err)
+			}
+			result.Migrated++
+		}
+
+		if progress != nil {
+			progress(i+1, len(bookmarks), bookmark)
+		}
+	}
+
+	if err := verifyMigration(ctx, bookmarks, dst); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// verifyMigration checks that every source bookmark is present and unchanged in dst
+func verifyMigration(ctx context.Context, bookmarks []*models.Bookmark, dst repository.BookmarkRepository) error {
+	for _, bookmark := range bookmarks {
+		migrated, err := dst.GetByCommand(ctx, bookmark.Command)
+		if err != nil {
+			return fmt.Errorf("verification failed for '%s': %w", bookmark.Command, err)
+		}
+		if !migrated.Equal(bookmark) {
+			return fmt.Errorf("verification failed for '%s': destination differs from source", bookmark.Command)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+//go:build unit
+// +build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+func TestMigrateBookmarks(t *testing.T) {
+	src := memory.NewMemoryBookmarkRepository()
+	dst := memory.NewMemoryBookmarkRepository()
+	ctx := context.Background()
+
+	_ = src.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	_ = src.Create(ctx, &models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+
+	calls := 0
+	result, err := MigrateBookmarks(ctx, src, dst, func(done, total int, bookmark *models.Bookmark) {
+		calls++
+		if total != 2 {
+			t.Errorf("Expected total 2, got %d", total)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	if result.Migrated != 2 || result.Skipped != 0 || result.Total != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 progress callbacks, got %d", calls)
+	}
+
+	for _, cmd := range []string{"kubectl get pods", "docker ps"} {
+		if exists, _ := dst.Exists(ctx, cmd); !exists {
+			t.Errorf("Expected '%s' in destination", cmd)
+		}
+	}
+}
+
+func TestMigrateBookmarksSkipsIdentical(t *testing.T) {
+	src := memory.NewMemoryBookmarkRepository()
+	dst := memory.NewMemoryBookmarkRepository()
+	ctx := context.Background()
+
+	_ = src.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	_ = dst.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+
+	result, err := MigrateBookmarks(ctx, src, dst, nil)
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	if result.Migrated != 0 || result.Skipped != 1 {
+		t.Errorf("Expected 1 skipped bookmark, got %+v", result)
+	}
+}
+
+func TestMigrateBookmarksConflict(t *testing.T) {
+	src := memory.NewMemoryBookmarkRepository()
+	dst := memory.NewMemoryBookmarkRepository()
+	ctx := context.Background()
+
+	_ = src.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"})
+	_ = dst.Create(ctx, &models.Bookmark{Command: "kubectl get pods", ToolName: "k8s", Description: "other"})
+
+	if _, err := MigrateBookmarks(ctx, src, dst, nil); err == nil {
+		t.Error("Expected conflict error")
+	}
+}
+
+func TestMigrateBookmarksSourceError(t *testing.T) {
+	src := &errorMockRepository{shouldErrorOnList: true}
+	dst := memory.NewMemoryBookmarkRepository()
+
+	if _, err := MigrateBookmarks(context.Background(), src, dst, nil); err == nil {
+		t.Error("Expected error from source repository")
+	}
+}
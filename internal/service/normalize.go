@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fgeck/tools/internal/canonical"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// NormalizationChange describes stored commands that are replaced by their normalized form
+type NormalizationChange struct {
+	From []string // Stored commands, more than one if they were duplicates
+	To   string   // Normalized command that replaces them
+}
+
+// NormalizationResult summarizes the reconciliation of stored commands
+type NormalizationResult struct {
+	Renamed int // Bookmarks whose command was normalized
+	Merged  int // Duplicate bookmarks merged into another one
+	Changes []NormalizationChange
+}
+
+// NormalizeStoredCommands rewrites stored commands to their normalized form.
+// Bookmarks that only differ by whitespace are merged: the bookmark already stored in
+// normalized form (or else the first one) is kept and receives the tags of the others.
+// With dryRun the changes are only reported.
+func NormalizeStoredCommands(ctx context.Context, repo repository.BookmarkRepository, dryRun bool) (*NormalizationResult, error) {
+	return normalizeStore(ctx, repo, normalizeCommand, dryRun)
+}
+
+// CanonicalizeStoredCommands is NormalizeStoredCommands that also rewrites commands of known
+// tools into their canonical form, see canonical.Command. Bookmarks spelling the same command
+// differently, like -n dev and --namespace dev, are merged.
+func CanonicalizeStoredCommands(ctx context.Context, repo repository.BookmarkRepository, dryRun bool) (*NormalizationResult, error) {
+	return normalizeStore(ctx, repo, func(command string) string {
+		return canonical.Command(normalizeCommand(command))
+	}, dryRun)
+}
+
+// normalizeStore rewrites all stored commands with normalize in one batch, so a failure
+// leaves the store unchanged
+func normalizeStore(ctx context.Context, repo repository.BookmarkRepository, normalize func(string) string, dryRun bool) (*NormalizationResult, error) {
+	var result *NormalizationResult
+	err := repository.Batch(ctx, repo, func(tx repository.BookmarkRepository) error {
+		bookmarks, err := tx.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list bookmarks: %w", err)
+		}
+		result, err = normalizeBookmarks(ctx, tx, bookmarks, normalize, dryRun)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// normalizeBookmarks rewrites the commands of the given stored bookmarks with normalize,
+// merging duplicates
+func normalizeBookmarks(ctx context.Context, repo repository.BookmarkRepository, bookmarks []*models.Bookmark, normalize func(string) string, dryRun bool) (*NormalizationResult, error) {
+	// Group bookmarks by normalized command, keeping the store order
+	var order []string
+	groups := map[string][]*models.Bookmark{}
+	for _, bookmark := range bookmarks {
+		normalized := normalize(bookmark.Command)
+		if _, ok := groups[normalized]; !ok {
+			order = append(order, normalized)
+		}
+		groups[normalized] = append(groups[normalized], bookmark)
+	}
+
+	result := &NormalizationResult{}
+	for _, normalized := range order {
+		group := groups[normalized]
+		if len(group) == 1 && group[0].Command == normalized {
+			continue
+		}
+
+		change := NormalizationChange{To: normalized}
+		for _, bookmark := range group {
+			change.From = append(change.From, bookmark.Command)
+		}
+		result.Changes = append(result.Changes, change)
+		result.Merged += len(group) - 1
+
+		keeper := group[0]
+		for _, bookmark := range group {
+			if bookmark.Command == normalized {
+				keeper = bookmark
+				break
+			}
+		}
+		if keeper.Command != normalized {
+			result.Renamed++
+		}
+
+		if dryRun {
+			continue
+		}
+		if err := reconcileGroup(ctx, repo, normalized, keeper, group); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileGroup replaces a group of bookmarks with the same normalized command by the merged keeper
+// The keeper gets the tags of all bookmarks and, if it has none, the first link, reminder
+// period, host, OS variants and requirements of the group. It stays private if any of them is.
+func reconcileGroup(ctx context.Context, repo repository.BookmarkRepository, normalized string, keeper *models.Bookmark, group []*models.Bookmark) error {
+	merged := *keeper
+	merged.Tags = nil
+	for _, bookmark := range group {
+		merged.Tags = append(merged.Tags, bookmark.Tags...)
+		if merged.Link == "" {
+			merged.Link = bookmark.Link
+		}
+		if merged.RemindEvery == "" {
+			merged.RemindEvery = bookmark.RemindEvery
+		}
+		if merged.Host == "" {
+			merged.Host = bookmark.Host
+		}
+		if len(merged.Variants) == 0 {
+			merged.Variants = bookmark.Variants
+		}
+		if len(merged.Requires) == 0 {
+			merged.Requires = bookmark.Requires
+		}
+		merged.Private = merged.Private || bookmark.Private
+		if bookmark.Command == keeper.Command {
+			continue
+		}
+		if err := repo.Delete(ctx, bookmark.Command); err != nil {
+			return fmt.Errorf("failed to delete duplicate '%s': %w", bookmark.Command, err)
+		}
+	}
+	slices.Sort(merged.Tags)
+	merged.Tags = slices.Compact(merged.Tags)
+	merged.UpdatedAt = time.Now()
+
+	if keeper.Command == normalized {
+		if err := repo.Update(ctx, &merged); err != nil {
+			return fmt.Errorf("failed to update '%s': %w", normalized, err)
+		}
+		return nil
+	}
+
+	if err := repo.Delete(ctx, keeper.Command); err != nil {
+		return fmt.Errorf("failed to delete '%s': %w", keeper.Command, err)
+	}
+	merged.Command = normalized
+	if err := repo.Create(ctx, &merged); err != nil {
+		return fmt.Errorf("failed to create '%s': %w", normalized, err)
+	}
+	return nil
+}
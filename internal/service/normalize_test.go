@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/memory"
+)
+
+func TestNormalizeStoredCommandsMergesDuplicates(t *testing.T) {
+	repo := memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl  get pods", ToolName: "kubectl", Description: "pods", Tags: []string{"k8s"}},
+		&models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods", Tags: []string{"pods"}},
+		&models.Bookmark{Command: "docker   ps ", ToolName: "docker", Description: "containers"},
+		&models.Bookmark{Command: "ls -la", ToolName: "ls", Description: "list"},
+	)
+	ctx := context.Background()
+
+	result, err := NormalizeStoredCommands(ctx, repo, false)
+	if err != nil {
+		t.Fatalf("Normalization failed: %v", err)
+	}
+	if result.Merged != 1 || result.Renamed != 1 || len(result.Changes) != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	bookmarks, _ := repo.List(ctx)
+	if len(bookmarks) != 3 {
+		t.Fatalf("Expected 3 bookmarks, got %d", len(bookmarks))
+	}
+
+	pods, err := repo.GetByCommand(ctx, "kubectl get pods")
+	if err != nil {
+		t.Fatalf("Expected merged bookmark: %v", err)
+	}
+	if pods.Description != "list pods" {
+		t.Errorf("Expected the normalized bookmark to be kept, got %q", pods.Description)
+	}
+	if !slices.Equal(pods.Tags, []string{"k8s", "pods"}) {
+		t.Errorf("Expected merged tags, got %v", pods.Tags)
+	}
+
+	if _, err := repo.GetByCommand(ctx, "docker ps"); err != nil {
+		t.Errorf("Expected renamed bookmark: %v", err)
+	}
+}
+
+func TestNormalizeStoredCommandsDryRun(t *testing.T) {
+	repo := memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "git  status", ToolName: "git", Description: "status"},
+		&models.Bookmark{Command: "git status", ToolName: "git", Description: "status"},
+	)
+	ctx := context.Background()
+
+	result, err := NormalizeStoredCommands(ctx, repo, true)
+	if err != nil {
+		t.Fatalf("Normalization failed: %v", err)
+	}
+	if result.Merged != 1 || len(result.Changes) != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if !slices.Equal(result.Changes[0].From, []string{"git  status", "git status"}) || result.Changes[0].To != "git status" {
+		t.Errorf("Unexpected change: %+v", result.Changes[0])
+	}
+
+	if bookmarks, _ := repo.List(ctx); len(bookmarks) != 2 {
+		t.Errorf("Dry run must not change the store, got %d bookmarks", len(bookmarks))
+	}
+}
+
+func TestCanonicalizeStoredCommands(t *testing.T) {
+	repo := memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods -n dev", ToolName: "kubectl", Description: "dev pods", Tags: []string{"dev"}},
+		&models.Bookmark{Command: "kubectl get pods --namespace dev", ToolName: "kubectl", Description: "pods of dev", Tags: []string{"k8s"}},
+		&models.Bookmark{Command: "git  status", ToolName: "git", Description: "status"},
+	)
+	ctx := context.Background()
+
+	result, err := CanonicalizeStoredCommands(ctx, repo, false)
+	if err != nil {
+		t.Fatalf("Canonicalization failed: %v", err)
+	}
+	if result.Merged != 1 || result.Renamed != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	pods, err := repo.GetByCommand(ctx, "kubectl get pods --namespace=dev")
+	if err != nil {
+		t.Fatalf("Expected the canonical bookmark: %v", err)
+	}
+	if pods.Description != "dev pods" || !slices.Equal(pods.Tags, []string{"dev", "k8s"}) {
+		t.Errorf("Expected the first bookmark with the tags of both, got %+v", pods)
+	}
+	if _, err := repo.GetByCommand(ctx, "git status"); err != nil {
+		t.Errorf("Expected unknown tools to be normalized only: %v", err)
+	}
+}
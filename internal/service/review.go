@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// Bookmarks created while review is enabled move through these states:
+//
+//	submitted -> pending (in the review queue) -> approved (moved to the store)
+//	                                           -> rejected (removed from the queue)
+//
+// Pending bookmarks are not part of the store, so they don't show up in listings,
+// searches or the TUI until they are approved.
+
+// WithReviewQueue holds new bookmarks in queue until they are approved
+func WithReviewQueue(queue repository.BookmarkRepository) Option {
+	return func(s *bookmarkServiceImpl) {
+		s.review = queue
+	}
+}
+
+// submit adds a new bookmark to the review queue
+func (s *bookmarkServiceImpl) submit(ctx context.Context, bookmark *models.Bookmark) (*dto.BookmarkResponse, error) {
+	pending, err := s.review.Exists(ctx, bookmark.Command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check review queue: %w", err)
+	}
+	if pending {
+		return nil, fmt.Errorf("example with command '%s' is pending review and %w", bookmark.Command, models.ErrAlreadyExists)
+	}
+
+	if err := s.review.Create(ctx, bookmark); err != nil {
+		return nil, fmt.Errorf("failed to submit example for review: %w", err)
+	}
+
+	resp := s.modelToDTO(bookmark)
+	resp.Pending = true
+	return resp, nil
+}
+
+// ListPending retrieves the bookmarks waiting for review
+func (s *bookmarkServiceImpl) ListPending(ctx context.Context) (*dto.ListBookmarksResponse, error) {
+	responses := []dto.BookmarkResponse{}
+	if s.review == nil {
+		return &dto.ListBookmarksResponse{Examples: responses}, nil
+	}
+
+	pending, err := s.review.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review queue: %w", err)
+	}
+	for _, bookmark := range pending {
+		resp := s.modelToDTO(bookmark)
+		resp.Pending = true
+		responses = append(responses, *resp)
+	}
+
+	return &dto.ListBookmarksResponse{
+		Examples: responses,
+		Count:    len(responses),
+	}, nil
+}
+
+// ApproveBookmark moves a pending bookmark into the store
+// It counts as the bookmark's creation: history, hooks and change listeners see it now.
+func (s *bookmarkServiceImpl) ApproveBookmark(ctx context.Context, command string) (*dto.BookmarkResponse, error) {
+	bookmark, err := s.getPending(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.Exists(ctx, bookmark.Command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check example existence: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: example with command '%s' was added to the store since it was submitted, reject it instead", models.ErrConflict, bookmark.Command)
+	}
+
+	bookmark.UpdatedAt = time.Now()
+	if err := s.repo.Create(ctx, bookmark); err != nil {
+		return nil, fmt.Errorf("failed to create example: %w", err)
+	}
+	if err := s.review.Delete(ctx, bookmark.Command); err != nil {
+		return nil, fmt.Errorf("example approved but failed to remove it from the review queue: %w", err)
+	}
+
+	if err := s.record(ctx, audit.ActionCreate, bookmark.Command, nil, bookmark); err != nil {
+		return nil, err
+	}
+	return s.modelToDTO(bookmark), nil
+}
+
+// RejectBookmark removes a pending bookmark from the review queue
+func (s *bookmarkServiceImpl) RejectBookmark(ctx context.Context, command string) error {
+	bookmark, err := s.getPending(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	if err := s.review.Delete(ctx, bookmark.Command); err != nil {
+		return fmt.Errorf("failed to reject example: %w", err)
+	}
+	return nil
+}
+
+// getPending looks up a bookmark in the review queue by its normalized command
+func (s *bookmarkServiceImpl) getPending(ctx context.Context, command string) (*models.Bookmark, error) {
+	if s.review == nil {
+		return nil, fmt.Errorf("%w: review is not enabled", models.ErrValidation)
+	}
+
+	bookmark, err := s.review.GetByCommand(ctx, normalizeCommand(command))
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("no example with command '%s' is pending review: %w", command, models.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review queue: %w", err)
+	}
+	return bookmark, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/secrets"
+)
+
+// bookmarkSecrets returns the credentials written into a command and its OS variants,
+// described with their masked value, e.g. "AWS access key AK******"
+func bookmarkSecrets(command string, variants map[string]string) []string {
+	commands := []string{command}
+	goos := make([]string, 0, len(variants))
+	for name := range variants {
+		goos = append(goos, name)
+	}
+	sort.Strings(goos)
+	for _, name := range goos {
+		commands = append(commands, variants[name])
+	}
+
+	var found []string
+	for _, command := range commands {
+		for _, secret := range secrets.Find(command) {
+			found = append(found, secret.Kind+" "+secrets.Mask(secret.Value))
+		}
+	}
+	return found
+}
+
+// newSecrets returns the credentials in command and variants that previous, the bookmark
+// they replace, did not hold already; all of them when previous is nil
+// A bookmark stored with its credential on purpose can thus still be edited.
+func newSecrets(command string, variants map[string]string, previous *models.Bookmark) []string {
+	found := bookmarkSecrets(command, variants)
+	if previous == nil || len(found) == 0 {
+		return found
+	}
+	held := map[string]bool{}
+	for _, secret := range bookmarkSecrets(previous.Command, previous.Variants) {
+		held[secret] = true
+	}
+	var added []string
+	for _, secret := range found {
+		if !held[secret] {
+			added = append(added, secret)
+		}
+	}
+	return added
+}
+
+// refuseSecrets returns a validation error when command or variants contain a likely
+// credential, unless allow is set, so none ends up in a store that is synced or shared
+func refuseSecrets(command string, variants map[string]string, previous *models.Bookmark, allow bool) error {
+	found := newSecrets(command, variants, previous)
+	if len(found) == 0 || allow {
+		return nil
+	}
+	return fmt.Errorf("%w: the command contains a likely %s, read it from an environment variable or a placeholder instead, or allow secrets to store it anyway",
+		models.ErrValidation, strings.Join(found, " and a likely "))
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository"
+)
+
+// change is a change to the store made within a transaction, recorded once it applied
+type change struct {
+	action        audit.Action
+	command       string
+	before, after *models.Bookmark
+}
+
+// Transaction runs fn with a service writing to a staging repository, see repository.Batch
+// The changes are recorded in the history, reported to the change listeners and passed to
+// the hooks once all of them applied; runs are recorded right away. Reviews still go to the
+// review queue directly. A transaction within a transaction joins the outer one.
+func (s *bookmarkServiceImpl) Transaction(ctx context.Context, fn func(tx BookmarkService) error) error {
+	return s.inTransaction(ctx, func(tx *bookmarkServiceImpl) error { return fn(tx) })
+}
+
+// inTransaction is Transaction for the operations of the service itself
+func (s *bookmarkServiceImpl) inTransaction(ctx context.Context, fn func(tx *bookmarkServiceImpl) error) error {
+	if s.pending != nil {
+		return fn(s)
+	}
+
+	var changes []change
+	err := repository.Batch(ctx, s.repo, func(repo repository.BookmarkRepository) error {
+		tx := *s
+		tx.repo = repo
+		tx.pending = &changes
+		return fn(&tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if err := s.record(ctx, c.action, c.command, c.before, c.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
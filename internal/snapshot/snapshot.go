@@ -0,0 +1,218 @@
+// Package snapshot keeps named full copies of the YAML bookmark store
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/yaml"
+)
+
+// fileExt is the extension of snapshot files
+const fileExt = ".yaml"
+
+// nameFormat is the layout of names given to unnamed snapshots
+const nameFormat = "20060102-150405"
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Snapshot is a full copy of the bookmark store
+type Snapshot struct {
+	Name    string
+	Path    string
+	Created time.Time
+}
+
+// Manager creates, lists and restores snapshots in a directory
+type Manager struct {
+	dir string
+	now func() time.Time
+}
+
+// NewManager creates a manager for the snapshots in dir
+func NewManager(dir string) *Manager {
+	return &Manager{dir: dir, now: time.Now}
+}
+
+// Create copies the store file into a new snapshot
+// Without a name, the snapshot is named after the current time.
+func (m *Manager) Create(storePath, name string) (*Snapshot, error) {
+	created := m.now()
+	if name == "" {
+		name = created.Format(nameFormat)
+	}
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	path := m.path(name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%w: snapshot '%s' already exists", models.ErrAlreadyExists, name)
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Chtimes(path, created, created); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return &Snapshot{Name: name, Path: path, Created: created}, nil
+}
+
+// List returns all snapshots, oldest first
+func (m *Manager) List() ([]Snapshot, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	snapshots := []Snapshot{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		snapshots = append(snapshots, Snapshot{
+			Name:    strings.TrimSuffix(entry.Name(), fileExt),
+			Path:    filepath.Join(m.dir, entry.Name()),
+			Created: fileInfo.ModTime(),
+		})
+	}
+
+	slices.SortFunc(snapshots, func(a, b Snapshot) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return snapshots, nil
+}
+
+// Get returns the snapshot with the given name
+func (m *Manager) Get(name string) (*Snapshot, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	path := m.path(name)
+	fileInfo, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: snapshot '%s' does not exist", models.ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return &Snapshot{Name: name, Path: path, Created: fileInfo.ModTime()}, nil
+}
+
+// Restore replaces the store file with a snapshot
+// The store is replaced atomically, so it is never left half-written.
+func (m *Manager) Restore(name, storePath string) error {
+	snap, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(snap.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(storePath), ".tools-restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), storePath); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	return nil
+}
+
+// Bookmarks reads the bookmarks of a snapshot or store file
+func Bookmarks(ctx context.Context, path string) ([]*models.Bookmark, error) {
+	return yaml.NewReadOnlyYAMLBookmarkRepository(path).List(ctx)
+}
+
+// Changes lists the commands that differ between two sets of bookmarks
+type Changes struct {
+	Added   []string // Only in the newer set
+	Removed []string // Only in the older set
+	Changed []string // In both sets with different content
+}
+
+// Diff compares an older and a newer set of bookmarks by command
+func Diff(older, newer []*models.Bookmark) Changes {
+	previous := make(map[string]*models.Bookmark, len(older))
+	for _, b := range older {
+		previous[b.Command] = b
+	}
+
+	changes := Changes{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	current := make(map[string]bool, len(newer))
+	for _, b := range newer {
+		current[b.Command] = true
+		old, ok := previous[b.Command]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, b.Command)
+		case !old.Equal(b):
+			changes.Changed = append(changes.Changed, b.Command)
+		}
+	}
+	for _, b := range older {
+		if !current[b.Command] {
+			changes.Removed = append(changes.Removed, b.Command)
+		}
+	}
+
+	slices.Sort(changes.Added)
+	slices.Sort(changes.Removed)
+	slices.Sort(changes.Changed)
+	return changes
+}
+
+// path returns the file of a snapshot
+func (m *Manager) path(name string) string {
+	return filepath.Join(m.dir, name+fileExt)
+}
+
+// validateName checks that a snapshot name is usable as a file name
+func validateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%w: invalid snapshot name '%s' (use letters, digits, '.', '_' and '-')", models.ErrValidation, name)
+	}
+	return nil
+}
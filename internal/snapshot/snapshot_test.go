@@ -0,0 +1,135 @@
+//go:build unit
+// +build unit
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+)
+
+const storeContent = "bookmarks:\n  - command: kubectl get pods\n    toolname: kubectl\n    description: list pods\n"
+
+func newTestManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "tools.yaml")
+	if err := os.WriteFile(storePath, []byte(storeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(filepath.Join(dir, "snapshots")), storePath
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	m, storePath := newTestManager(t)
+
+	snap, err := m.Create(storePath, "before-cleanup")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if snap.Name != "before-cleanup" {
+		t.Errorf("Expected name before-cleanup, got %s", snap.Name)
+	}
+
+	if err := os.WriteFile(storePath, []byte("bookmarks: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Restore("before-cleanup", storePath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(storePath)
+	if string(data) != storeContent {
+		t.Errorf("Expected restored store, got %q", data)
+	}
+}
+
+func TestCreateNamesSnapshotsByTime(t *testing.T) {
+	m, storePath := newTestManager(t)
+	m.now = func() time.Time { return time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC) }
+
+	snap, err := m.Create(storePath, "")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if snap.Name != "20261016-153000" {
+		t.Errorf("Expected timestamp name, got %s", snap.Name)
+	}
+
+	if _, err := m.Create(storePath, ""); !errors.Is(err, models.ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists for a taken name, got %v", err)
+	}
+}
+
+func TestListSortsByCreation(t *testing.T) {
+	m, storePath := newTestManager(t)
+	base := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	for i, name := range []string{"b", "a", "c"} {
+		m.now = func() time.Time { return base.Add(time.Duration(i) * time.Hour) }
+		if _, err := m.Create(storePath, name); err != nil {
+			t.Fatalf("Create(%s) error = %v", name, err)
+		}
+	}
+
+	snapshots, err := m.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	names := []string{}
+	for _, snap := range snapshots {
+		names = append(names, snap.Name)
+	}
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
+
+func TestInvalidAndMissingNames(t *testing.T) {
+	m, storePath := newTestManager(t)
+
+	for _, name := range []string{"../escape", "with space", ".hidden"} {
+		if _, err := m.Create(storePath, name); !errors.Is(err, models.ErrValidation) {
+			t.Errorf("Create(%q): expected ErrValidation, got %v", name, err)
+		}
+	}
+	if err := m.Restore("missing", storePath); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	older := []*models.Bookmark{
+		{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"},
+		{Command: "docker ps", ToolName: "docker", Description: "list containers"},
+	}
+	newer := []*models.Bookmark{
+		{Command: "kubectl get pods", ToolName: "kubectl", Description: "list all pods"},
+		{Command: "helm list", ToolName: "helm", Description: "list releases"},
+	}
+
+	got := Diff(older, newer)
+	want := Changes{Added: []string{"helm list"}, Removed: []string{"docker ps"}, Changed: []string{"kubectl get pods"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	_, storePath := newTestManager(t)
+
+	bookmarks, err := Bookmarks(context.Background(), storePath)
+	if err != nil {
+		t.Fatalf("Bookmarks() error = %v", err)
+	}
+	if len(bookmarks) != 1 || bookmarks[0].Command != "kubectl get pods" {
+		t.Errorf("Unexpected bookmarks: %+v", bookmarks)
+	}
+}
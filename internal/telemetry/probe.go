@@ -0,0 +1,20 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Probe answers health checks of orchestrators like Kubernetes: 200 while check passes,
+// 503 with the error otherwise
+func Probe(check func(ctx context.Context) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := check(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+}
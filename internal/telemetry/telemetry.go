@@ -0,0 +1,239 @@
+// Package telemetry exports the metrics of long-running tools processes, like the MCP
+// server, in the Prometheus text format
+package telemetry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fgeck/tools/internal/export"
+	"github.com/fgeck/tools/internal/repository/metered"
+)
+
+// ContentType is the media type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Sources are the parts of the process whose state is exported on every scrape
+type Sources struct {
+	Version    string
+	Bookmarks  func(ctx context.Context) (int, error) // Number of bookmarks in the store
+	Store      *metered.Metrics                       // Store operations, recorded by the metrics decorator; nil if not collected
+	AutoExport func() export.MirrorStatus             // Status of the auto-export, nil if none is configured
+}
+
+// Collector counts the requests of a server and renders them with the state of its Sources
+type Collector struct {
+	sources Sources
+
+	mu       sync.Mutex
+	requests map[requestKey]*requestStats
+	rejected map[string]int // HTTP requests answered without reaching a handler, by reason
+}
+
+type requestKey struct {
+	method string
+	tool   string
+}
+
+type requestStats struct {
+	ok       int
+	failed   int
+	duration time.Duration
+	buckets  []int // Cumulative, per metered.DurationBuckets
+}
+
+// NewCollector returns a collector without any requests
+func NewCollector(sources Sources) *Collector {
+	return &Collector{
+		sources:  sources,
+		requests: map[requestKey]*requestStats{},
+		rejected: map[string]int{},
+	}
+}
+
+// ObserveRequest counts a handled request; it matches mcp.RequestObserver
+func (c *Collector) ObserveRequest(method, tool string, duration time.Duration, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := requestKey{method: method, tool: tool}
+	stats, ok := c.requests[key]
+	if !ok {
+		stats = &requestStats{buckets: make([]int, len(metered.DurationBuckets))}
+		c.requests[key] = stats
+	}
+	if failed {
+		stats.failed++
+	} else {
+		stats.ok++
+	}
+	stats.duration += duration
+	for i, bound := range metered.DurationBuckets {
+		if duration <= bound {
+			stats.buckets[i]++
+		}
+	}
+}
+
+// ObserveRejected counts an HTTP request that was answered with an error instead of being
+// handled, e.g. "rate_limited" or "panic"
+func (c *Collector) ObserveRejected(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejected[reason]++
+}
+
+// ServeHTTP answers a scrape
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	c.Write(r.Context(), w)
+}
+
+// Write renders all metrics in the Prometheus text format
+func (c *Collector) Write(ctx context.Context, w io.Writer) error {
+	out := &exposition{w: w}
+
+	out.header("tools_build_info", "gauge", "Version of the running tools binary")
+	out.sample("tools_build_info", 1, "version", c.sources.Version)
+
+	c.writeRequests(out)
+	c.writeRejected(out)
+
+	if c.sources.Bookmarks != nil {
+		count, err := c.sources.Bookmarks(ctx)
+		out.header("tools_store_up", "gauge", "Whether the store could be read on this scrape")
+		out.sample("tools_store_up", boolValue(err == nil))
+		if err == nil {
+			out.header("tools_store_bookmarks", "gauge", "Bookmarks in the store, including read-only layers")
+			out.sample("tools_store_bookmarks", float64(count))
+		}
+	}
+
+	if c.sources.Store != nil {
+		if ops := c.sources.Store.Snapshot(); len(ops) > 0 {
+			out.header("tools_store_operation_duration_seconds", "histogram", "Duration of store operations")
+			for _, op := range ops {
+				out.histogram("tools_store_operation_duration_seconds", op.Buckets, op.Calls, op.Duration, "op", op.Op)
+			}
+			out.header("tools_store_operation_errors_total", "counter", "Store operations that failed, including lookups of missing bookmarks")
+			for _, op := range ops {
+				out.sample("tools_store_operation_errors_total", float64(op.Errors), "op", op.Op)
+			}
+		}
+	}
+
+	if c.sources.AutoExport != nil {
+		status := c.sources.AutoExport()
+		out.header("tools_auto_export_pending", "gauge", "Whether a change waits to be written to the auto-export file")
+		out.sample("tools_auto_export_pending", boolValue(status.Pending))
+		out.header("tools_auto_export_failures_total", "counter", "Failed writes of the auto-export file")
+		out.sample("tools_auto_export_failures_total", float64(status.Failures))
+		if !status.LastWrite.IsZero() {
+			out.header("tools_auto_export_last_success_timestamp_seconds", "gauge", "Unix time of the last successful write of the auto-export file")
+			out.sample("tools_auto_export_last_success_timestamp_seconds", float64(status.LastWrite.UnixMilli())/1000)
+		}
+	}
+
+	return out.err
+}
+
+func (c *Collector) writeRequests(out *exposition) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.requests) == 0 {
+		return
+	}
+	keys := make([]requestKey, 0, len(c.requests))
+	for key := range c.requests {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b requestKey) int {
+		return cmp.Or(cmp.Compare(a.method, b.method), cmp.Compare(a.tool, b.tool))
+	})
+
+	out.header("tools_requests_total", "counter", "Handled server requests by method, tool and status")
+	for _, key := range keys {
+		stats := c.requests[key]
+		out.sample("tools_requests_total", float64(stats.ok), "method", key.method, "tool", key.tool, "status", "ok")
+		out.sample("tools_requests_total", float64(stats.failed), "method", key.method, "tool", key.tool, "status", "error")
+	}
+	out.header("tools_request_duration_seconds", "histogram", "Duration of handled server requests")
+	for _, key := range keys {
+		stats := c.requests[key]
+		out.histogram("tools_request_duration_seconds", stats.buckets, stats.ok+stats.failed, stats.duration, "method", key.method, "tool", key.tool)
+	}
+}
+
+func (c *Collector) writeRejected(out *exposition) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.rejected) == 0 {
+		return
+	}
+	out.header("tools_http_rejected_total", "counter", "HTTP requests answered with an error instead of being handled, by reason")
+	for _, reason := range slices.Sorted(maps.Keys(c.rejected)) {
+		out.sample("tools_http_rejected_total", float64(c.rejected[reason]), "reason", reason)
+	}
+}
+
+// exposition writes metrics in the text format, keeping the first write error
+type exposition struct {
+	w   io.Writer
+	err error
+}
+
+func (e *exposition) printf(format string, args ...any) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
+
+func (e *exposition) header(name, kind, help string) {
+	e.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one value; labels are name and value pairs
+func (e *exposition) sample(name string, value float64, labels ...string) {
+	e.printf("%s%s %s\n", name, formatLabels(labels), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// histogram writes the series of one histogram from cumulative bucket counts
+func (e *exposition) histogram(name string, buckets []int, count int, sum time.Duration, labels ...string) {
+	for i, bound := range metered.DurationBuckets {
+		e.sample(name+"_bucket", float64(buckets[i]), append(slices.Clone(labels), "le", strconv.FormatFloat(bound.Seconds(), 'g', -1, 64))...)
+	}
+	e.sample(name+"_bucket", float64(count), append(slices.Clone(labels), "le", "+Inf")...)
+	e.sample(name+"_sum", sum.Seconds(), labels...)
+	e.sample(name+"_count", float64(count), labels...)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+//go:build unit
+// +build unit
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/export"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/repository/metered"
+)
+
+func TestCollector(t *testing.T) {
+	store := metered.NewMetrics()
+	repo := metered.NewMeteredBookmarkRepository(memory.NewMemoryBookmarkRepository(), store)
+	repo.Create(context.Background(), &models.Bookmark{Command: "ls -la"})
+
+	collector := NewCollector(Sources{
+		Version:    "1.2.3",
+		Bookmarks:  func(ctx context.Context) (int, error) { return 42, nil },
+		Store:      store,
+		AutoExport: func() export.MirrorStatus { return export.MirrorStatus{Pending: true, Failures: 2} },
+	})
+	collector.ObserveRequest("tools/call", "search_bookmarks", 3*time.Millisecond, false)
+	collector.ObserveRequest("tools/call", "search_bookmarks", 2*time.Second, true)
+	collector.ObserveRequest("ping", "", time.Millisecond, false)
+	collector.ObserveRejected("rate_limited")
+	collector.ObserveRejected("rate_limited")
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+
+	out := rec.Body.String()
+	for _, expected := range []string{
+		`tools_build_info{version="1.2.3"} 1`,
+		"# TYPE tools_requests_total counter",
+		`tools_requests_total{method="ping",tool="",status="ok"} 1`,
+		`tools_requests_total{method="tools/call",tool="search_bookmarks",status="error"} 1`,
+		`tools_request_duration_seconds_bucket{method="tools/call",tool="search_bookmarks",le="0.005"} 1`,
+		`tools_request_duration_seconds_bucket{method="tools/call",tool="search_bookmarks",le="+Inf"} 2`,
+		`tools_request_duration_seconds_sum{method="tools/call",tool="search_bookmarks"} 2.003`,
+		`tools_http_rejected_total{reason="rate_limited"} 2`,
+		"tools_store_up 1",
+		"tools_store_bookmarks 42",
+		`tools_store_operation_duration_seconds_count{op="create"} 1`,
+		`tools_store_operation_errors_total{op="create"} 0`,
+		"tools_auto_export_pending 1",
+		"tools_auto_export_failures_total 2",
+	} {
+		if !strings.Contains(out, expected+"\n") {
+			t.Errorf("Expected %q in:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "tools_auto_export_last_success_timestamp_seconds") {
+		t.Error("Expected no last success before the first write")
+	}
+}
+
+func TestCollectorStoreDown(t *testing.T) {
+	collector := NewCollector(Sources{
+		Version:   "dev",
+		Bookmarks: func(ctx context.Context) (int, error) { return 0, errors.New("unreadable") },
+	})
+
+	var buf bytes.Buffer
+	if err := collector.Write(context.Background(), &buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "tools_store_up 0\n") || strings.Contains(out, "tools_store_bookmarks") {
+		t.Errorf("Expected an unreadable store to be reported as down, got:\n%s", out)
+	}
+	if strings.Contains(out, "tools_requests_total") || strings.Contains(out, "tools_http_rejected_total") || strings.Contains(out, "tools_auto_export") {
+		t.Errorf("Expected no series without requests or auto-export, got:\n%s", out)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	var checkErr error
+	probe := Probe(func(ctx context.Context) error { return checkErr })
+
+	rec := httptest.NewRecorder()
+	probe.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != 200 || rec.Body.String() != "ok\n" {
+		t.Errorf("Expected 200 ok, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	checkErr = errors.New("store is not writable")
+	rec = httptest.NewRecorder()
+	probe.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != 503 || !strings.Contains(rec.Body.String(), "store is not writable") {
+		t.Errorf("Expected 503 with the error, got %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,191 @@
+package toolcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fgeck/tools/internal/parse"
+)
+
+// LookPathFunc resolves an executable name to its path, like exec.LookPath
+type LookPathFunc func(file string) (string, error)
+
+// Status is the installation state of one binary referenced by bookmarks
+type Status struct {
+	Binary   string
+	Path     string   // Resolved path, empty when missing
+	Commands []string // Bookmarked commands invoking the binary
+}
+
+// Missing reports whether the binary could not be found
+func (s Status) Missing() bool {
+	return s.Path == ""
+}
+
+// Hint is an install command for one package manager
+type Hint struct {
+	Manager string
+	Command string
+}
+
+// packageManagers are the managers hints are shown for, in display order
+var packageManagers = []struct {
+	name    string
+	install string
+}{
+	{"brew", "brew install"},
+	{"apt", "sudo apt install"},
+	{"dnf", "sudo dnf install"},
+	{"pacman", "sudo pacman -S"},
+}
+
+// packageNames maps binaries to package names where they differ from the binary name
+// Managers without an entry use the default key "", then the binary name itself
+var packageNames = map[string]map[string]string{
+	"rg":     {"": "ripgrep"},
+	"fd":     {"": "fd", "apt": "fd-find"},
+	"http":   {"": "httpie"},
+	"dig":    {"": "bind", "apt": "dnsutils", "dnf": "bind-utils"},
+	"nc":     {"": "netcat", "apt": "netcat-openbsd", "dnf": "nmap-ncat", "pacman": "openbsd-netcat"},
+	"psql":   {"": "postgresql", "brew": "libpq", "apt": "postgresql-client"},
+	"aws":    {"": "awscli", "apt": "awscli", "pacman": "aws-cli"},
+	"docker": {"": "docker", "apt": "docker.io"},
+	"python": {"": "python3", "pacman": "python"},
+	"pip":    {"": "python3-pip", "brew": "python", "pacman": "python-pip"},
+	"gh":     {"": "gh", "pacman": "github-cli"},
+}
+
+// Binary returns the executable a command invokes, skipping leading variable
+// assignments and wrappers like sudo. It returns "" when the command has no
+// resolvable executable, e.g. a shell builtin or a $VARIABLE.
+func Binary(command string) string {
+	return parse.Executable(command)
+}
+
+// Check resolves the binary of every command and groups the commands by binary
+// Results are sorted by binary name; commands without a resolvable binary are ignored.
+func Check(commands []string, lookPath LookPathFunc) []Status {
+	byBinary := map[string]*Status{}
+	for _, command := range commands {
+		binary := Binary(command)
+		if binary == "" {
+			continue
+		}
+		status, ok := byBinary[binary]
+		if !ok {
+			status = &Status{Binary: binary}
+			if path, err := lookPath(binary); err == nil {
+				status.Path = path
+			}
+			byBinary[binary] = status
+		}
+		status.Commands = append(status.Commands, command)
+	}
+
+	statuses := make([]Status, 0, len(byBinary))
+	for _, status := range byBinary {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Binary < statuses[j].Binary
+	})
+	return statuses
+}
+
+// envName matches the requirements naming environment variables, like AWS_PROFILE
+var envName = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// IsEnvRequirement reports whether a bookmark requirement is an environment variable
+// Upper-case names like AWS_PROFILE are environment variables, anything else is an executable.
+func IsEnvRequirement(requirement string) bool {
+	return envName.MatchString(requirement)
+}
+
+// Unmet returns the requirements that are not available, in order: environment variables
+// that are unset or empty and executables lookPath cannot find
+func Unmet(requires []string, lookPath LookPathFunc, getenv func(string) string) []string {
+	var unmet []string
+	for _, requirement := range requires {
+		if IsEnvRequirement(requirement) {
+			if getenv(requirement) == "" {
+				unmet = append(unmet, requirement)
+			}
+			continue
+		}
+		if _, err := lookPath(requirement); err != nil {
+			unmet = append(unmet, requirement)
+		}
+	}
+	return unmet
+}
+
+// InstallHints returns the install command of the binary for each supported package manager
+func InstallHints(binary string) []Hint {
+	names := packageNames[binary]
+	hints := make([]Hint, len(packageManagers))
+	for i, pm := range packageManagers {
+		pkg := binary
+		if name, ok := names[""]; ok {
+			pkg = name
+		}
+		if name, ok := names[pm.name]; ok {
+			pkg = name
+		}
+		hints[i] = Hint{Manager: pm.name, Command: fmt.Sprintf("%s %s", pm.install, pkg)}
+	}
+	return hints
+}
+
+// executableExts are the extensions of executables on Windows, left out of their names
+var executableExts = []string{".exe", ".com", ".bat", ".cmd", ".ps1"}
+
+// Executables lists the names of the executables in the directories of a PATH value, sorted
+// and without duplicates. Directories that cannot be read are skipped.
+func Executables(path string) []string {
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if name, ok := executableName(dir, entry); ok {
+				seen[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// executableName returns the name an entry of dir is run by, ok is false for entries that are
+// no executable files. Links are followed, package managers like brew link their binaries.
+func executableName(dir string, entry os.DirEntry) (name string, ok bool) {
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	name = entry.Name()
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !slices.Contains(executableExts, strings.ToLower(ext)) {
+			return "", false
+		}
+		return strings.TrimSuffix(name, ext), true
+	}
+	return name, info.Mode()&0o111 != 0
+}
@@ -0,0 +1,140 @@
+//go:build unit
+// +build unit
+
+package toolcheck
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"lsof -i :8080", "lsof"},
+		{"sudo lsof -i :8080", "lsof"},
+		{"sudo -u postgres psql", "psql"},
+		{"KUBECONFIG=~/.kube/dev kubectl get pods", "kubectl"},
+		{"env FOO=bar nice -n 10 make build", "make"},
+		{"/usr/local/bin/terraform plan", "/usr/local/bin/terraform"},
+		{"'rg' TODO", "rg"},
+		{"cd /tmp && ls", ""},
+		{"$EDITOR ~/.bashrc", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Binary(tt.command); got != tt.expected {
+			t.Errorf("Binary(%q) = %q, want %q", tt.command, got, tt.expected)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	installed := map[string]string{"lsof": "/usr/bin/lsof"}
+	lookups := 0
+	lookPath := func(file string) (string, error) {
+		lookups++
+		if path, ok := installed[file]; ok {
+			return path, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	statuses := Check([]string{"lsof -i :8080", "kubectl get pods", "sudo lsof -t -i :80", "kubectl logs x", "cd /tmp"}, lookPath)
+
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 binaries, got %d: %+v", len(statuses), statuses)
+	}
+	if lookups != 2 {
+		t.Errorf("Expected each binary to be resolved once, got %d lookups", lookups)
+	}
+
+	kubectl, lsof := statuses[0], statuses[1]
+	if kubectl.Binary != "kubectl" || !kubectl.Missing() || len(kubectl.Commands) != 2 {
+		t.Errorf("Unexpected kubectl status: %+v", kubectl)
+	}
+	if lsof.Binary != "lsof" || lsof.Missing() || lsof.Path != "/usr/bin/lsof" || len(lsof.Commands) != 2 {
+		t.Errorf("Unexpected lsof status: %+v", lsof)
+	}
+}
+
+func TestUnmet(t *testing.T) {
+	lookPath := func(file string) (string, error) {
+		if file == "aws" {
+			return "/usr/bin/aws", nil
+		}
+		return "", errors.New("not found")
+	}
+	env := map[string]string{"AWS_PROFILE": "dev", "EMPTY": ""}
+	getenv := func(key string) string { return env[key] }
+
+	unmet := Unmet([]string{"AWS_PROFILE", "aws", "EMPTY", "KUBECONFIG", "kubectl"}, lookPath, getenv)
+	if want := []string{"EMPTY", "KUBECONFIG", "kubectl"}; !slices.Equal(unmet, want) {
+		t.Errorf("Unmet() = %v, want %v", unmet, want)
+	}
+	if unmet := Unmet(nil, lookPath, getenv); unmet != nil {
+		t.Errorf("Unmet(nil) = %v, want nil", unmet)
+	}
+}
+
+func TestInstallHints(t *testing.T) {
+	hints := InstallHints("fd")
+	expected := map[string]string{
+		"brew":   "brew install fd",
+		"apt":    "sudo apt install fd-find",
+		"dnf":    "sudo dnf install fd",
+		"pacman": "sudo pacman -S fd",
+	}
+
+	if len(hints) != len(expected) {
+		t.Fatalf("Expected %d hints, got %d", len(expected), len(hints))
+	}
+	for _, hint := range hints {
+		if expected[hint.Manager] != hint.Command {
+			t.Errorf("%s: expected %q, got %q", hint.Manager, expected[hint.Manager], hint.Command)
+		}
+	}
+
+	if got := InstallHints("jq")[0].Command; got != "brew install jq" {
+		t.Errorf("Expected binary name as default package, got %q", got)
+	}
+}
+
+func TestExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executables are recognized by their extension on Windows")
+	}
+	bin, local := t.TempDir(), t.TempDir()
+	write := func(path string, mode os.FileMode) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(bin, "kubectl"), 0o755)
+	write(filepath.Join(bin, "README"), 0o644)
+	write(filepath.Join(local, "kubectl"), 0o755)
+	write(filepath.Join(local, "helm"), 0o755)
+	if err := os.Mkdir(filepath.Join(local, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(bin, "kubectl"), filepath.Join(local, "k")); err != nil {
+		t.Fatal(err)
+	}
+
+	path := strings.Join([]string{bin, "", filepath.Join(bin, "missing"), local}, string(os.PathListSeparator))
+	if got, want := Executables(path), []string{"helm", "k", "kubectl"}; !slices.Equal(got, want) {
+		t.Errorf("Executables() = %v, want %v", got, want)
+	}
+	if got := Executables(""); len(got) != 0 {
+		t.Errorf("Executables(\"\") = %v, want none", got)
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/fgeck/tools/internal/canonical"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// canonicalOffer returns the canonical form of the command in the form, "" if it has none
+// apart from whitespace or canonical commands are not enabled
+func (m model) canonicalOffer() string {
+	if !m.options.Canonical {
+		return ""
+	}
+	command := strings.TrimSpace(m.cmdInput.Value())
+	offer := canonical.Command(command)
+	if strings.Join(strings.Fields(offer), " ") == strings.Join(strings.Fields(command), " ") {
+		return ""
+	}
+	return offer
+}
+
+// useCanonical replaces the command of the form with its canonical form
+func (m *model) useCanonical() {
+	offer := m.canonicalOffer()
+	if offer == "" {
+		return
+	}
+	m.cmdInput.SetValue(offer)
+	m.fitCommand()
+}
+
+// canonicalView renders the canonical form offered below the command
+func (m model) canonicalView() string {
+	offer := m.canonicalOffer()
+	if offer == "" {
+		return ""
+	}
+	return itemStyle.Render(helpStyle.UnsetPadding().Render(i18n.T("canonical: %s (ctrl+n to use it)", offer)))
+}
@@ -0,0 +1,34 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCanonicalOffer(t *testing.T) {
+	m := NewModel(nil, Options{Canonical: true})
+	m = typeKeys(t, m, runes("a"), runes("kubectl get pods -n dev"))
+
+	if view := m.View(); !strings.Contains(view, "canonical: kubectl get pods --namespace=dev (ctrl+n to use it)") {
+		t.Errorf("Expected the canonical form below the command, got:\n%s", view)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlN})
+	if got := m.cmdInput.Value(); got != "kubectl get pods --namespace=dev" {
+		t.Errorf("Expected ctrl+n to take the canonical form, got %q", got)
+	}
+	if offer := m.canonicalOffer(); offer != "" {
+		t.Errorf("Expected no offer for a canonical command, got %q", offer)
+	}
+
+	// Only offered when enabled
+	m = NewModel(nil, Options{})
+	m = typeKeys(t, m, runes("a"), runes("kubectl get pods -n dev"))
+	if strings.Contains(m.View(), "canonical:") {
+		t.Error("Expected no canonical form unless enabled")
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/utils"
+)
+
+// Column names of the bookmark table
+const (
+	ColumnTool        = "tool"
+	ColumnDescription = "description"
+	ColumnCommand     = "command"
+)
+
+// Column is a visible table column and its share of the terminal width
+type Column struct {
+	Name   string
+	Weight int // Relative width; columns split the terminal width in proportion to their weights
+}
+
+// DefaultColumns is the table layout used when none is configured
+var DefaultColumns = []Column{
+	{Name: ColumnTool, Weight: 15},
+	{Name: ColumnDescription, Weight: 40},
+	{Name: ColumnCommand, Weight: 45},
+}
+
+var columnTitles = map[string]string{
+	ColumnTool:        "Tool",
+	ColumnDescription: "Description",
+	ColumnCommand:     "Command",
+}
+
+const (
+	defaultTableWidth = 115 // Terminal width assumed until the first resize
+	minColumnWidth    = 8
+	borderWidth       = 2 // Left and right table border
+	cellPadding       = 2 // Padding around every cell
+)
+
+// layoutColumns splits the terminal width between the columns in proportion to their weights
+func layoutColumns(columns []Column, termWidth int) []table.Column {
+	available := termWidth - borderWidth - cellPadding*len(columns)
+
+	total := 0
+	for _, c := range columns {
+		total += c.Weight
+	}
+
+	result := make([]table.Column, len(columns))
+	for i, c := range columns {
+		width := minColumnWidth
+		if total > 0 {
+			width = max(available*c.Weight/total, minColumnWidth)
+		}
+		result[i] = table.Column{Title: i18n.T(columnTitles[c.Name]), Width: width}
+	}
+	return result
+}
+
+// columnWidth returns the width of the named column, or 0 if it is hidden
+func (m model) columnWidth(name string) int {
+	cols := m.table.Columns()
+	for i, c := range m.columns {
+		if c.Name == name && i < len(cols) {
+			return cols[i].Width
+		}
+	}
+	return 0
+}
+
+// columnOffset returns the display offset of the named column's content within a table line
+func (m model) columnOffset(name string) int {
+	cols := m.table.Columns()
+	offset := cellPadding / 2
+	for i, c := range m.columns {
+		if c.Name == name || i >= len(cols) {
+			break
+		}
+		offset += cols[i].Width + cellPadding
+	}
+	return offset
+}
+
+// setRows fills the table with the bookmarks, wrapping long values to the column widths
+// Bookmarks are shown in the order of the sorted column, if any.
+func (m *model) setRows(examples []dto.BookmarkResponse) {
+	m.bookmarks = examples
+	examples = m.arrange(examples)
+	m.examples = examples
+
+	rows := []table.Row{}
+	m.tableRows = []tableRow{}
+	m.rowToBookmarkMap = []int{}
+	m.isFirstRow = []bool{}
+
+	descWidth := m.columnWidth(ColumnDescription)
+	cmdWidth := m.columnWidth(ColumnCommand)
+
+	for bookmarkIndex, example := range examples {
+		toolLabel := m.toolLabel(example)
+
+		// Store the original bookmark
+		m.tableRows = append(m.tableRows, tableRow{
+			toolName:    example.ToolName,
+			toolLabel:   toolLabel,
+			description: example.Description,
+			command:     example.Command,
+			tags:        example.Tags,
+			updatedAt:   example.UpdatedAt,
+		})
+
+		// Grouped bookmarks show their tool once, on the first of the group
+		shownLabel := toolLabel
+		if m.grouped && bookmarkIndex > 0 && strings.EqualFold(examples[bookmarkIndex-1].ToolName, example.ToolName) {
+			shownLabel = ""
+		}
+
+		// Hidden columns must not add wrapped rows
+		description, command := example.Description, m.options.Redactor.Redact(example.Command)
+		if descWidth == 0 {
+			description = ""
+		}
+		if cmdWidth == 0 {
+			command = ""
+		}
+
+		// Wrap and split into multiple rows if needed
+		wrappedRows := utils.SplitWrappedRows(shownLabel, description, command, descWidth, cmdWidth)
+
+		for rowIdx, wrapped := range wrappedRows {
+			values := map[string]string{
+				ColumnTool:        wrapped[0],
+				ColumnDescription: wrapped[1],
+				ColumnCommand:     wrapped[2],
+			}
+			row := make(table.Row, len(m.columns))
+			for i, c := range m.columns {
+				row[i] = values[c.Name]
+			}
+			rows = append(rows, row)
+			m.rowToBookmarkMap = append(m.rowToBookmarkMap, bookmarkIndex)
+			m.isFirstRow = append(m.isFirstRow, rowIdx == 0) // Only first row is true
+		}
+	}
+	m.table.SetRows(rows)
+
+	// Ensure cursor starts on a first row
+	if len(m.isFirstRow) > 0 {
+		cursor := m.table.Cursor()
+		if cursor >= 0 && cursor < len(m.isFirstRow) && !m.isFirstRow[cursor] {
+			// If current cursor is not on a first row, find the nearest first row
+			m.table.SetCursor(m.findNextFirstRow(-1)) // Start from -1 to get first available
+		}
+	}
+}
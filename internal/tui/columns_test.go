@@ -0,0 +1,95 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+)
+
+func TestLayoutColumnsIsProportional(t *testing.T) {
+	cols := layoutColumns([]Column{{Name: ColumnTool, Weight: 1}, {Name: ColumnCommand, Weight: 3}}, 108)
+
+	// 108 - border 2 - padding 2*2 = 102 columns to share
+	if len(cols) != 2 || cols[0].Width != 25 || cols[1].Width != 76 {
+		t.Errorf("Expected widths 25 and 76, got %+v", cols)
+	}
+	if cols[0].Title != "Tool" || cols[1].Title != "Command" {
+		t.Errorf("Expected titles in configured order, got %+v", cols)
+	}
+}
+
+func TestLayoutColumnsMinimumWidth(t *testing.T) {
+	cols := layoutColumns(DefaultColumns, 20)
+
+	for _, c := range cols {
+		if c.Width < minColumnWidth {
+			t.Errorf("Expected at least %d columns for %s, got %d", minColumnWidth, c.Title, c.Width)
+		}
+	}
+}
+
+func TestSetRowsWithHiddenColumns(t *testing.T) {
+	m := NewModel(nil, Options{Columns: []Column{{Name: ColumnCommand, Weight: 1}, {Name: ColumnTool, Weight: 1}}})
+
+	m.setRows([]dto.BookmarkResponse{
+		{Command: "kubectl get pods", ToolName: "kubectl", Description: "a description long enough to wrap onto several lines of the table"},
+	})
+
+	rows := m.table.Rows()
+	if len(rows) != 1 {
+		t.Fatalf("Expected hidden description not to add wrapped rows, got %d rows", len(rows))
+	}
+	if rows[0][0] != "kubectl get pods" || rows[0][1] != "kubectl" {
+		t.Errorf("Expected values in configured column order, got %v", rows[0])
+	}
+}
+
+func TestSortByColumnHotkeys(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows([]dto.BookmarkResponse{
+		{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"},
+		{Command: "docker ps", ToolName: "docker", Description: "Show containers"},
+		{Command: "aws s3 ls", ToolName: "aws", Description: "list buckets"},
+	})
+	m.table.SetCursor(0)
+
+	order := func() []string {
+		var commands []string
+		for _, row := range m.tableRows {
+			commands = append(commands, row.command)
+		}
+		return commands
+	}
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(model)
+	if got, want := order(), []string{"aws s3 ls", "kubectl get pods", "docker ps"}; !slices.Equal(got, want) {
+		t.Errorf("Expected sorting by description, got %v", got)
+	}
+	if title := m.table.Columns()[1].Title; title != "Description ▲" {
+		t.Errorf("Expected the sorted column to be marked, got %q", title)
+	}
+	if m.tableRows[m.rowToBookmarkMap[m.table.Cursor()]].command != "kubectl get pods" {
+		t.Errorf("Expected the selected bookmark to stay selected")
+	}
+
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(model)
+	if got, want := order(), []string{"docker ps", "kubectl get pods", "aws s3 ls"}; !slices.Equal(got, want) {
+		t.Errorf("Expected the direction to toggle, got %v", got)
+	}
+
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	m = updated.(model)
+	if got, want := order(), []string{"aws s3 ls", "docker ps", "kubectl get pods"}; !slices.Equal(got, want) {
+		t.Errorf("Expected sorting by tool, got %v", got)
+	}
+	if cols := m.table.Columns(); cols[0].Title != "Tool ▲" || cols[1].Title != "Description" {
+		t.Errorf("Expected only the tool column to be marked, got %q and %q", cols[0].Title, cols[1].Title)
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// word is a word of a command with the whitespace before it
+type word struct {
+	space string
+	text  string
+}
+
+// wordPattern matches a word with the whitespace before it
+var wordPattern = regexp.MustCompile(`(\s*)(\S+)`)
+
+// splitWords splits a command into its words, keeping the whitespace between them
+func splitWords(command string) []word {
+	var words []word
+	for _, match := range wordPattern.FindAllStringSubmatch(command, -1) {
+		words = append(words, word{space: match[1], text: match[2]})
+	}
+	return words
+}
+
+// diffWords renders the changes from one command to another word by word, like
+// git diff --word-diff: removed words as [-word-] and added ones as {+word+}. The whitespace
+// and line breaks of the new command are kept.
+func diffWords(from, to string) string {
+	old, updated := splitWords(from), splitWords(to)
+
+	// common[i][j] is the length of the longest common subsequence of old[i:] and updated[j:]
+	common := make([][]int, len(old)+1)
+	for i := range common {
+		common[i] = make([]int, len(updated)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(updated) - 1; j >= 0; j-- {
+			if old[i].text == updated[j].text {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	// write adds a word after the whitespace before it, at least a space between two words
+	write := func(space, text string) {
+		if space == "" && b.Len() > 0 {
+			space = " "
+		}
+		b.WriteString(space + text)
+	}
+	i, j := 0, 0
+	for i < len(old) || j < len(updated) {
+		switch {
+		case i < len(old) && j < len(updated) && old[i].text == updated[j].text:
+			write(updated[j].space, updated[j].text)
+			i++
+			j++
+		case j == len(updated) || (i < len(old) && common[i+1][j] >= common[i][j+1]):
+			// Removed words are set apart by a space, the line breaks are those of the new command
+			write("", removedStyle.Render("[-"+old[i].text+"-]"))
+			i++
+		default:
+			write(updated[j].space, addedStyle.Render("{+"+updated[j].text+"+}"))
+			j++
+		}
+	}
+	return b.String()
+}
+
+// commandDiffView renders the changes to the command of the edit form, "" while it is unchanged
+func (m model) commandDiffView() string {
+	command := strings.TrimSpace(m.cmdInput.Value())
+	if m.mode != modeEdit || command == "" || command == m.originalCmd {
+		return ""
+	}
+	diff := diffWords(m.originalCmd, command)
+	return itemStyle.Render(helpStyle.UnsetPadding().Render(i18n.T("changes:")) + "\n" + diff)
+}
@@ -0,0 +1,52 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		diff string
+	}{
+		{"unchanged", "kubectl get pods -A", "kubectl get pods -A", "kubectl get pods -A"},
+		{"removed flag", "kubectl get pods -A -o wide", "kubectl get pods -o wide", "kubectl get pods [--A-] -o wide"},
+		{"added flag", "docker ps", "docker ps -a", "docker ps {+-a+}"},
+		{"changed value", "lsof -i :8080", "lsof -i :9090", "lsof -i [-:8080-] {+:9090+}"},
+		{"removed first word", "sudo lsof -i :80", "lsof -i :80", "[-sudo-] lsof -i :80"},
+		{"whitespace only", "ls  -la", "ls -la", "ls -la"},
+		{"line breaks of the new command", "for f in *; do gzip $f\ndone", "for f in *; do\n  gzip -9 $f\ndone", "for f in *; do\n  gzip {+-9+} $f\ndone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffWords(tt.from, tt.to); got != tt.diff {
+				t.Errorf("Expected %q, got %q", tt.diff, got)
+			}
+		})
+	}
+}
+
+func TestEditShowsCommandDiff(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(model)
+	if m.mode != modeEdit || strings.Contains(m.View(), "changes:") {
+		t.Fatalf("Expected the edit form without changes, got mode %v:\n%s", m.mode, m.View())
+	}
+
+	m.cmdInput.SetValue(strings.Replace(m.originalCmd, " ", "  --verbose ", 1))
+	view := m.View()
+	if !strings.Contains(view, "changes:") || !strings.Contains(view, "{+--verbose+}") {
+		t.Errorf("Expected the added flag in the diff, got:\n%s", view)
+	}
+}
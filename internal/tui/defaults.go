@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+)
+
+// applyToolDefaults prefills the add form with the description template and tags configured
+// for the entered tool. It fills an empty description and adds missing tags once per tool name,
+// so a prefilled value the user removed stays removed.
+func (m *model) applyToolDefaults() {
+	tool := strings.TrimSpace(m.field(toolField).Value())
+	if m.options.ToolDefaults == nil || m.mode != modeAdd || tool == "" || tool == m.defaultsTool {
+		return
+	}
+	m.defaultsTool = tool
+
+	description, tags := m.options.ToolDefaults(tool, strings.TrimSpace(m.cmdInput.Value()))
+	if description != "" && strings.TrimSpace(m.field(descField).Value()) == "" {
+		m.field(descField).SetValue(description)
+	}
+
+	entered := parseTags(m.field(tagsField).Value())
+	value := strings.TrimRight(m.field(tagsField).Value(), ", \t")
+	for _, tag := range tags {
+		if slices.ContainsFunc(entered, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+		if value != "" {
+			value += ", "
+		}
+		value += tag
+		entered = append(entered, tag)
+	}
+	if value != strings.TrimRight(m.field(tagsField).Value(), ", \t") {
+		m.field(tagsField).SetValue(value)
+	}
+}
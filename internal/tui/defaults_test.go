@@ -0,0 +1,65 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/service"
+)
+
+func kubectlDefaults(tool, command string) (string, []string) {
+	if tool != "kubectl" {
+		return "", nil
+	}
+	return "k8s: " + command, []string{"k8s", "kubernetes"}
+}
+
+func TestToolDefaultsPrefillForm(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository())
+	m := NewModel(svc, Options{ToolDefaults: kubectlDefaults})
+	m = typeKeys(t, m, runes("a"), runes("kubectl get pods"), tea.KeyMsg{Type: tea.KeyTab})
+
+	if m.field(descField).Value() != "k8s: kubectl get pods" || m.field(tagsField).Value() != "k8s, kubernetes" {
+		t.Fatalf("Expected the defaults of kubectl in the form, got %q and %q", m.field(descField).Value(), m.field(tagsField).Value())
+	}
+
+	// Prefilled values are only a start: a removed tag is not added back
+	m.field(tagsField).SetValue("k8s")
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil || m.mode != modeList {
+		t.Fatalf("Failed to save: %v", m.err)
+	}
+	saved, err := svc.GetBookmark(context.Background(), "kubectl get pods")
+	if err != nil || saved.Description != "k8s: kubectl get pods" || !slices.Equal(saved.Tags, []string{"k8s"}) {
+		t.Errorf("Expected the edited defaults to be saved, got %+v, %v", saved, err)
+	}
+}
+
+func TestToolDefaultsKeepEnteredValues(t *testing.T) {
+	m := NewModel(nil, Options{ToolDefaults: kubectlDefaults})
+	m = typeKeys(t, m, runes("a"))
+	m.cmdInput.SetValue("kubectl get nodes")
+	m.field(descField).SetValue("list nodes")
+	m.field(tagsField).SetValue("prod, K8s")
+	m.field(toolField).SetValue("kubectl")
+
+	m.applyToolDefaults()
+	if m.field(descField).Value() != "list nodes" || m.field(tagsField).Value() != "prod, K8s, kubernetes" {
+		t.Errorf("Expected entered values kept and missing tags added, got %q and %q", m.field(descField).Value(), m.field(tagsField).Value())
+	}
+
+	// Duplicates keep what the copied bookmark has
+	m.resetInputs()
+	m.mode, m.defaultsTool = modeAdd, "kubectl"
+	m.field(toolField).SetValue("kubectl")
+	m.applyToolDefaults()
+	if m.field(tagsField).Value() != "" {
+		t.Errorf("Expected no defaults for the tool of a duplicate, got %q", m.field(tagsField).Value())
+	}
+}
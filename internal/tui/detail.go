@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/highlight"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/service"
+)
+
+// relatedLimit is the number of related bookmarks shown in the detail view
+const relatedLimit = 5
+
+// openURL opens a bookmark link; replaced in tests
+var openURL = runner.OpenURL
+
+type detailLoadedMsg struct {
+	bookmark *dto.BookmarkResponse
+	lastRun  *dto.RunResult
+	related  *dto.RankedBookmarksResponse
+}
+
+func loadDetail(svc service.BookmarkService, command string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		bookmark, err := svc.GetBookmark(ctx, command)
+		if err != nil {
+			return errorMsg{err}
+		}
+		lastRun, err := svc.LastRun(ctx, command)
+		if err != nil {
+			return errorMsg{err}
+		}
+		related, err := svc.RelatedBookmarks(ctx, command, relatedLimit)
+		if err != nil {
+			return errorMsg{err}
+		}
+		return detailLoadedMsg{bookmark: bookmark, lastRun: lastRun, related: related}
+	}
+}
+
+func (m model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc", "q":
+		m.mode = modeList
+		m.detail = nil
+		m.detailRun = nil
+		m.related = nil
+		return m, nil
+
+	case "up", "k":
+		if m.relatedCursor > 0 {
+			m.relatedCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.related != nil && m.relatedCursor < m.related.Count-1 {
+			m.relatedCursor++
+		}
+		return m, nil
+
+	case "v":
+		return m.startVariants()
+
+	case "o":
+		// Open the bookmark's link in the browser
+		if m.detail == nil || m.detail.Link == "" {
+			return m, nil
+		}
+		if err := openURL(m.detail.Link); err != nil {
+			m.err = err
+		}
+		return m, nil
+
+	case "enter":
+		// Follow the selected related bookmark
+		if m.related == nil || m.related.Count == 0 {
+			return m, nil
+		}
+		return m, loadDetail(m.service, m.related.Results[m.relatedCursor].Command)
+	}
+
+	return m, nil
+}
+
+func (m model) detailView() string {
+	if m.detail == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(i18n.T("Bookmark")))
+	b.WriteString("\n\n")
+	for _, line := range bookmarkDetailLines(m.options.Redactor.Bookmark(*m.detail)) {
+		b.WriteString(itemStyle.Render(line))
+		b.WriteString("\n")
+	}
+	if m.detailRun != nil {
+		b.WriteString(itemStyle.Render(fmt.Sprintf("%-13s%s", "last run:", lastRunText(*m.detailRun))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(titleStyle.Render(i18n.T("Related")))
+	b.WriteString("\n\n")
+
+	if m.related == nil || m.related.Count == 0 {
+		b.WriteString(itemStyle.Render(i18n.T("No related bookmarks.")))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(m.detailHelp(i18n.T("esc: back"))))
+	} else {
+		for i, r := range m.related.Results {
+			cursor := "  "
+			if i == m.relatedCursor {
+				cursor = "> "
+			}
+			line := fmt.Sprintf("%s%-15s %s", cursor, r.ToolName, r.Description)
+			b.WriteString(itemStyle.Render(line))
+			b.WriteString("\n")
+			b.WriteString(itemStyle.Render("    " + summaryLine(m.options.Redactor.Redact(r.Command))))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render(m.detailHelp(i18n.T("↑/↓: select related • enter: open • esc: back"))))
+	}
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
+	}
+
+	return b.String()
+}
+
+// bookmarkDetailLines renders all fields of a bookmark, one per line, highlighting the command
+func bookmarkDetailLines(bookmark dto.BookmarkResponse) []string {
+	lines := []string{}
+	for i, line := range strings.Split(bookmark.Command, "\n") {
+		label := ""
+		if i == 0 {
+			label = "command:"
+		}
+		lines = append(lines, fmt.Sprintf("%-13s%s", label, highlight.Command(line)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("%-13s%s", "tool:", bookmark.ToolName),
+		fmt.Sprintf("%-13s%s", "description:", bookmark.Description),
+	)
+	if len(bookmark.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "tags:", strings.Join(bookmark.Tags, ", ")))
+	}
+	if bookmark.Link != "" {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "link:", bookmark.Link))
+	}
+	for _, system := range slices.Sorted(maps.Keys(bookmark.Variants)) {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "on "+system+":", highlight.Command(summaryLine(bookmark.Variants[system]))))
+	}
+	if len(bookmark.Requires) > 0 {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "requires:", strings.Join(bookmark.Requires, ", ")))
+	}
+	if bookmark.Host != "" {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "host:", bookmark.Host))
+	}
+	if bookmark.Private {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "private:", "yes"))
+	}
+	if bookmark.Author != "" {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "author:", bookmark.Author))
+	}
+	if bookmark.RemindEvery != "" {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "reminder:", i18n.T("every %s", bookmark.RemindEvery)))
+	}
+	if bookmark.Origin != "" {
+		lines = append(lines, fmt.Sprintf("%-13s%s", "origin:", bookmark.Origin))
+	}
+	return lines
+}
+
+// lastRunText describes the latest execution of a bookmark, failures highlighted
+func lastRunText(run dto.RunResult) string {
+	text := i18n.T("exit code %d after %s, %s", run.ExitCode, run.Duration.Round(time.Millisecond), run.Time.Local().Format("2006-01-02 15:04"))
+	if run.ExitCode != 0 {
+		return errorStyle.Render(text)
+	}
+	return addedStyle.Render(text)
+}
+
+// summaryLine returns the first line of a command, marking scripts that continue
+func summaryLine(command string) string {
+	if first, _, ok := strings.Cut(command, "\n"); ok {
+		return first + " …"
+	}
+	return command
+}
+
+// detailHelp adds the variants key and, when the bookmark has a link, the link key to the help line
+func (m model) detailHelp(help string) string {
+	if m.detail != nil && m.detail.Origin == "" {
+		help = i18n.T("v: OS variants • ") + help
+	}
+	if m.detail != nil && m.detail.Link != "" {
+		return i18n.T("o: open link • ") + help
+	}
+	return help
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/placeholder"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/toolcheck"
+	"github.com/fgeck/tools/internal/utils"
+)
+
+// fillWindow is the number of provider values shown at once
+const fillWindow = 10
+
+type valuesLoadedMsg struct {
+	placeholder placeholder.Dynamic
+	values      []string
+	err         error
+}
+
+func loadValues(r placeholder.Runner, d placeholder.Dynamic) tea.Cmd {
+	return func() tea.Msg {
+		values, err := r.Values(context.Background(), d.Provider)
+		return valuesLoadedMsg{placeholder: d, values: values, err: err}
+	}
+}
+
+// selectCommand outputs the command of the selected bookmark with key as stored command
+// Dynamic placeholders are filled first, then environment references are expanded.
+func (m model) selectCommand(key, command string) (tea.Model, tea.Cmd) {
+	if m.options.Placeholders != nil {
+		if pending := placeholder.Find(command); len(pending) > 0 {
+			m.mode = modeFill
+			m.fillKey = key
+			m.fillCmd = command
+			m.fillPending = pending
+			return m.nextPlaceholder()
+		}
+	}
+	return m.finishSelection(key, command, false)
+}
+
+// finishSelection expands environment references if enabled and selects the command
+// When placeholders were filled, variables expanded or requirements of the bookmark are
+// missing, the rendered command is previewed first and can be edited a last time.
+func (m model) finishSelection(key, command string, filled bool) (tea.Model, tea.Cmd) {
+	rendered, missing := command, []string(nil)
+	if m.options.ExpandEnv {
+		rendered, missing = utils.ExpandEnvFromOS(command)
+	}
+	unmet := m.unmetRequirements(key)
+
+	if filled || rendered != command || len(missing) > 0 || len(unmet) > 0 {
+		m.mode = modePreview
+		m.previewKey = key
+		m.previewCmd = rendered
+		m.previewMissing = missing
+		m.previewUnmet = unmet
+		m.previewInput = textinput.New()
+		m.previewInput.Prompt = "> "
+		m.previewInput.SetValue(rendered)
+		m.previewInput.Width = 80
+		return m, m.previewInput.Focus()
+	}
+
+	m.selectedCmd = command
+	m.selectedKey = key
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// previewReadOnly reports whether the previewed command is shown as it is instead of in the
+// input: scripts, which the single-line input would flatten, and commands with redacted values
+func (m model) previewReadOnly() bool {
+	return runner.IsScript(m.previewCmd) || m.options.Redactor.Hides(m.previewCmd)
+}
+
+// unmetRequirements returns the missing environment variables and executables required by
+// the bookmark with the given stored command. Requirements of bookmarks with a host concern
+// that host and are not checked.
+func (m model) unmetRequirements(key string) []string {
+	for _, example := range m.examples {
+		if example.Command == key && example.Host == "" {
+			return toolcheck.Unmet(example.Requires, exec.LookPath, os.Getenv)
+		}
+	}
+	return nil
+}
+
+// nextPlaceholder asks to run the provider of the next unfilled placeholder
+// Trusted providers run right away; when all placeholders are filled, the command is selected.
+func (m model) nextPlaceholder() (tea.Model, tea.Cmd) {
+	if len(m.fillPending) == 0 {
+		key, command := m.fillKey, m.fillCmd
+		m.resetFill()
+		return m.finishSelection(key, command, true)
+	}
+
+	m.fillValues = nil
+	m.fillCursor = 0
+	m.fillLoading = m.options.Placeholders.IsTrusted(m.fillPending[0].Provider)
+	if m.fillLoading {
+		return m, loadValues(*m.options.Placeholders, m.fillPending[0])
+	}
+	return m, nil
+}
+
+// resetFill returns to the list without selecting anything
+func (m *model) resetFill() {
+	m.mode = modeList
+	m.fillKey = ""
+	m.fillCmd = ""
+	m.fillPending = nil
+	m.fillValues = nil
+	m.fillLoading = false
+}
+
+func (m model) handleValuesLoaded(msg valuesLoadedMsg) (tea.Model, tea.Cmd) {
+	// Ignore results of a fill that was cancelled meanwhile
+	if m.mode != modeFill || !m.fillLoading || len(m.fillPending) == 0 || m.fillPending[0] != msg.placeholder {
+		return m, nil
+	}
+
+	m.fillLoading = false
+	switch {
+	case msg.err != nil:
+		m.resetFill()
+		m.err = errors.New(i18n.T("provider of {%s} failed: %v", msg.placeholder.Name, msg.err))
+	case len(msg.values) == 0:
+		m.resetFill()
+		m.err = errors.New(i18n.T("provider of {%s} returned no values", msg.placeholder.Name))
+	default:
+		m.fillValues = msg.values
+	}
+	return m, nil
+}
+
+func (m model) handleFillKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.resetFill()
+		return m, nil
+	}
+
+	switch {
+	case m.fillLoading:
+		return m, nil
+
+	case m.fillValues == nil:
+		// Confirm running the provider
+		switch msg.String() {
+		case "y", "enter":
+			m.fillLoading = true
+			return m, loadValues(*m.options.Placeholders, m.fillPending[0])
+		case "n":
+			m.resetFill()
+		}
+
+	default:
+		switch msg.String() {
+		case "up", "k":
+			if m.fillCursor > 0 {
+				m.fillCursor--
+			}
+		case "down", "j":
+			if m.fillCursor < len(m.fillValues)-1 {
+				m.fillCursor++
+			}
+		case "enter":
+			m.fillCmd = placeholder.Fill(m.fillCmd, m.fillPending[0], m.fillValues[m.fillCursor])
+			m.fillPending = m.fillPending[1:]
+			return m.nextPlaceholder()
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) fillView() string {
+	if len(m.fillPending) == 0 {
+		return ""
+	}
+	d := m.fillPending[0]
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(i18n.T("Fill Placeholder {%s}", d.Name)))
+	b.WriteString("\n\n")
+	b.WriteString(itemStyle.Render(i18n.T("Command: %s", summaryLine(m.options.Redactor.Redact(m.fillCmd)))))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.fillLoading:
+		b.WriteString(itemStyle.Render(i18n.T("Running %s …", d.Provider)))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(i18n.T("esc: cancel")))
+
+	case m.fillValues == nil:
+		b.WriteString(itemStyle.Render(i18n.T("The values come from running this command:")))
+		b.WriteString("\n")
+		b.WriteString(itemStyle.Render(d.Provider))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(i18n.T("y/enter: run it • n/esc: cancel")))
+
+	default:
+		// Show a window of values around the cursor
+		start := max(0, min(m.fillCursor-fillWindow/2, len(m.fillValues)-fillWindow))
+		end := min(len(m.fillValues), start+fillWindow)
+		for i := start; i < end; i++ {
+			line := "  " + m.fillValues[i]
+			if i == m.fillCursor {
+				line = addedStyle.Render("> " + m.fillValues[i])
+			}
+			b.WriteString(itemStyle.Render(line))
+			b.WriteString("\n")
+		}
+		if len(m.fillValues) > fillWindow {
+			b.WriteString(itemStyle.Render(fmt.Sprintf("%d/%d", m.fillCursor+1, len(m.fillValues))))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render(i18n.T("↑/↓: navigate • enter: use value • esc: cancel")))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// Fields of the add and edit forms in focus order, see also toolField and tagsField
+// The command is a multi-line textarea, the other fields are the inputs.
+const (
+	commandField = 0
+	descField    = 2
+	fieldCount   = 4
+)
+
+const (
+	commandWidth     = 50
+	commandHeight    = 8    // Lines the command input grows to before it scrolls
+	commandCharLimit = 4000 // Room for scripts and heredocs
+)
+
+// newCommandInput creates the command input of the add and edit forms
+// Enter submits the form, so a line break is entered with alt+enter or ctrl+j; pasted text
+// keeps its line breaks.
+func newCommandInput() textarea.Model {
+	input := textarea.New()
+	input.Placeholder = i18n.T("Command (e.g., lsof -i :54321)")
+	input.CharLimit = commandCharLimit
+	input.MaxHeight = 0 // The line count is limited by the char limit
+	input.ShowLineNumbers = false
+	input.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	input.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("alt+enter", "ctrl+j"))
+	input.SetWidth(commandWidth + lipgloss.Width(input.Prompt))
+	input.SetHeight(1)
+	input.Focus()
+	return input
+}
+
+// field returns the input of a field of the form other than the command
+func (m *model) field(i int) *textinput.Model {
+	return &m.inputs[i-1] // The command comes first
+}
+
+// focusField focuses a field of the form and blurs the others
+func (m *model) focusField(i int) tea.Cmd {
+	m.focusIndex = i
+	m.cmdInput.Blur()
+	for j := range m.inputs {
+		m.inputs[j].Blur()
+	}
+	if i == commandField {
+		return m.cmdInput.Focus()
+	}
+	return m.field(i).Focus()
+}
+
+// moveFocus moves the focus of the form by delta fields, wrapping around at either end
+func (m *model) moveFocus(delta int) tea.Cmd {
+	m.suggestionSelected = 0
+	return m.focusField(((m.focusIndex+delta)%fieldCount + fieldCount) % fieldCount)
+}
+
+// commandLineKey reports whether up or down moves between the lines of the command rather
+// than to another field
+func (m model) commandLineKey(key string) bool {
+	if m.focusIndex != commandField {
+		return false
+	}
+	line := m.cmdInput.LineInfo()
+	switch key {
+	case "up":
+		return m.cmdInput.Line() > 0 || line.RowOffset > 0
+	case "down":
+		return m.cmdInput.Line() < m.cmdInput.LineCount()-1 || line.RowOffset < line.Height-1
+	}
+	return false
+}
+
+// fitCommand grows the command input with its lines, soft-wrapped ones included, up to
+// commandHeight
+func (m *model) fitCommand() {
+	width := max(m.cmdInput.Width(), 1)
+	rows := 0
+	for _, line := range strings.Split(m.cmdInput.Value(), "\n") {
+		rows += max(1, (lipgloss.Width(line)+width)/width) // The cursor takes a column at the end
+	}
+	m.cmdInput.SetHeight(min(rows, commandHeight))
+}
+
+// updateCommand passes a key to the command input and fits its height to the result
+func (m *model) updateCommand(msg tea.KeyMsg) tea.Cmd {
+	// At full height, the input doesn't scroll lines away that fit once it has grown
+	m.cmdInput.SetHeight(commandHeight)
+	var cmd tea.Cmd
+	m.cmdInput, cmd = m.cmdInput.Update(msg)
+	m.fitCommand()
+	return cmd
+}
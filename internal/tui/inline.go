@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// startInline edits a cell of the selected bookmark below the table, without the edit form
+// field is ColumnDescription or ColumnTool.
+func (m model) startInline(field string) (tea.Model, tea.Cmd) {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.rowToBookmarkMap) {
+		return m, nil
+	}
+	bookmarkIndex := m.rowToBookmarkMap[cursor]
+	if bookmarkIndex < 0 || bookmarkIndex >= len(m.tableRows) {
+		return m, nil
+	}
+	row := m.tableRows[bookmarkIndex]
+
+	m.inlineKey = row.command
+	m.inlineUpdatedAt = row.updatedAt
+	m.inlineField = field
+	m.inlineInput = textinput.New()
+	m.inlineInput.Prompt = "> "
+	m.inlineInput.Width = 80
+	m.inlineValue = row.description
+	if field == ColumnTool {
+		m.inlineValue = row.toolName
+	}
+	m.inlineInput.SetValue(m.inlineValue)
+	m.mode = modeInline
+	m.err = nil
+	return m, m.inlineInput.Focus()
+}
+
+func (m model) handleInlineKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.mode = modeList
+		m.inlineKey = ""
+		m.err = nil
+		return m, nil
+
+	case "enter":
+		return m.submitInline()
+
+	case "tab", "shift+tab":
+		// Switch between the description and tool name of the same bookmark
+		if m.inlineField == ColumnTool {
+			return m.startInline(ColumnDescription)
+		}
+		return m.startInline(ColumnTool)
+	}
+
+	var cmd tea.Cmd
+	m.inlineInput, cmd = m.inlineInput.Update(msg)
+	return m, cmd
+}
+
+// submitInline saves the edited cell; an unchanged value just closes the editor
+func (m model) submitInline() (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(m.inlineInput.Value())
+	if value == "" {
+		m.err = errors.New(i18n.T("%s cannot be empty", i18n.T(columnTitles[m.inlineField])))
+		return m, nil
+	}
+	if value == m.inlineValue {
+		m.mode = modeList
+		m.inlineKey = ""
+		m.err = nil
+		return m, nil
+	}
+
+	req := dto.UpdateBookmarkRequest{Command: m.inlineKey, ExpectedUpdatedAt: &m.inlineUpdatedAt}
+	if m.inlineField == ColumnTool {
+		req.NewToolName = value
+	} else {
+		req.NewDescription = value
+	}
+
+	if _, err := m.service.UpdateBookmark(context.Background(), req); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.mode = modeList
+	m.inlineKey = ""
+	m.err = nil
+	return m, loadBookmarks(m.service)
+}
+
+// inlineView renders the cell being edited below the table
+func (m model) inlineView() string {
+	var b strings.Builder
+
+	b.WriteString(itemStyle.Render(i18n.T(columnTitles[m.inlineField]) + ": " + m.inlineInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(i18n.T("enter: save • tab: switch tool/description • esc: cancel")))
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
+	}
+
+	return b.String()
+}
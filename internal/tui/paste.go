@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/parse"
+)
+
+// promptPrefix matches the shell prompt documentation puts in front of commands
+var promptPrefix = regexp.MustCompile(`^\s*\$\s+`)
+
+// shellSyntax matches flags, pipes, redirections, variables and paths, which prose rarely has
+var shellSyntax = regexp.MustCompile(`(^|\s)--?[A-Za-z]|[|<>;&$]|(^|\s)[.~]?/`)
+
+// pastedCommand returns the command in pasted text with the prompts of copied documentation
+// removed, and whether it looks like a command rather than prose: its tool is stored or on the
+// PATH, or it holds shell syntax like flags and pipes
+func (m model) pastedCommand(text string) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	prompted := true
+	for _, line := range lines {
+		prompted = prompted && (strings.TrimSpace(line) == "" || promptPrefix.MatchString(line))
+	}
+	if prompted {
+		for i, line := range lines {
+			lines[i] = promptPrefix.ReplaceAllString(line, "")
+		}
+	}
+	command := strings.TrimSpace(strings.Join(lines, "\n"))
+
+	tool := parse.ToolName(command)
+	if tool == "" {
+		return "", false
+	}
+	if _, stored := m.toolCounts()[tool]; stored || slices.Contains(m.executables, tool) {
+		return command, true
+	}
+	return command, shellSyntax.MatchString(command)
+}
+
+// handlePaste fills the empty command of the add form with a pasted command, infers its tool
+// and moves on to the description; ok is false for pastes left to the focused input
+func (m model) handlePaste(msg tea.KeyMsg) (_ tea.Model, _ tea.Cmd, ok bool) {
+	if !msg.Paste || m.focusIndex != commandField || strings.TrimSpace(m.cmdInput.Value()) != "" {
+		return m, nil, false
+	}
+	command, ok := m.pastedCommand(string(msg.Runes))
+	if !ok {
+		return m, nil, false
+	}
+
+	m.cmdInput.SetValue(command)
+	m.fitCommand()
+	m.prefillToolName()
+	m.applyToolDefaults()
+	return m, m.focusField(descField), true
+}
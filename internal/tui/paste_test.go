@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/service"
+)
+
+func TestPastedCommand(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+	m.executables = []string{"lsof", "rg"}
+
+	tests := []struct {
+		text    string
+		command string
+		ok      bool
+	}{
+		{"kubectl get pods\n", "kubectl get pods", true},
+		{"  lsof -i :8080  ", "lsof -i :8080", true},
+		{"rg TODO", "rg TODO", true},
+		{"$ terraform plan -out plan.tfplan", "terraform plan -out plan.tfplan", true},
+		{"$ cat <<EOF\n$ hello\n$ EOF", "cat <<EOF\nhello\nEOF", true},
+		{"brew list | grep go", "brew list | grep go", true},
+		{"$ echo $HOME", "echo $HOME", false},
+		{"list all pods", "list all pods", false},
+		{"Check the logs of the failing job", "Check the logs of the failing job", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			command, ok := m.pastedCommand(tt.text)
+			if ok != tt.ok || (ok && command != tt.command) {
+				t.Errorf("Expected %q, %v, got %q, %v", tt.command, tt.ok, command, ok)
+			}
+		})
+	}
+}
+
+func TestPasteFillsAddForm(t *testing.T) {
+	m := NewModel(service.NewBookmarkService(memory.NewMemoryBookmarkRepository()), Options{})
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(model)
+
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("$ sudo lsof -i :8080\n"), Paste: true})
+	m = updated.(model)
+	if m.cmdInput.Value() != "sudo lsof -i :8080" {
+		t.Fatalf("Expected the pasted command, got %q", m.cmdInput.Value())
+	}
+	if m.field(toolField).Value() != "lsof" || m.focusIndex != descField {
+		t.Errorf("Expected the tool inferred and the description focused, got %q in field %d", m.field(toolField).Value(), m.focusIndex)
+	}
+
+	// Prose pasted into the command, or pasted into another field, is typed as it is
+	m.resetInputs()
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("list open ports"), Paste: true})
+	m = updated.(model)
+	if m.cmdInput.Value() != "list open ports" || m.focusIndex != commandField {
+		t.Errorf("Expected prose to stay in the command, got %q in field %d", m.cmdInput.Value(), m.focusIndex)
+	}
+	m.focusField(descField)
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("lsof -i :80"), Paste: true})
+	m = updated.(model)
+	if m.field(descField).Value() != "lsof -i :80" || m.cmdInput.Value() != "list open ports" {
+		t.Errorf("Expected the paste in the description, got %q", m.field(descField).Value())
+	}
+}
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/placeholder"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/service"
+	"github.com/fgeck/tools/internal/toolcheck"
+	"github.com/fgeck/tools/internal/utils"
+)
+
+// runPlain is the line-oriented variant of the TUI for screen readers and dumb terminals
+// It prints numbered bookmarks without colors, borders or cursor movement and reads one
+// answer per line: a number selects a bookmark, other text searches, q quits.
+func runPlain(svc service.BookmarkService, opts Options, in io.Reader, out io.Writer) error {
+	ctx := context.Background()
+	reader := bufio.NewReader(in)
+
+	all, err := svc.ListBookmarks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list examples: %w", err)
+	}
+	shown := all.Examples
+	printPlainList(out, opts.Redactor.Bookmarks(shown))
+
+	for {
+		fmt.Fprint(out, i18n.T("Enter a number to select, text to search, nothing to list all, or q to quit: "))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			// End of input quits like q
+			fmt.Fprintln(out)
+			return nil
+		}
+
+		switch n, convErr := strconv.Atoi(answer); {
+		case strings.EqualFold(answer, "q") || strings.EqualFold(answer, i18n.T("q")):
+			return nil
+
+		case answer == "":
+			shown = all.Examples
+			printPlainList(out, opts.Redactor.Bookmarks(shown))
+
+		case convErr == nil:
+			if n < 1 || n > len(shown) {
+				fmt.Fprintln(out, i18n.T("There is no bookmark number %d.", n))
+				continue
+			}
+			if shown[n-1].Host == "" {
+				if unmet := toolcheck.Unmet(shown[n-1].Requires, exec.LookPath, os.Getenv); len(unmet) > 0 {
+					fmt.Fprintln(out, i18n.T("Warning: missing requirements: %s", strings.Join(unmet, ", ")))
+				}
+			}
+			selected, ok, err := plainSelect(reader, out, shown[n-1].CommandFor(goos), opts)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if host := shown[n-1].Host; host != "" {
+				selected = runner.SSHCommand(host, selected)
+			}
+			if redacted := opts.Redactor.Redact(selected); redacted != selected && !plainConfirmReveal(reader, out, redacted) {
+				continue
+			}
+			if err := printPlainSelection(out, selected); err != nil {
+				return err
+			}
+			return svc.RecordRun(ctx, shown[n-1].Command)
+
+		default:
+			resp, err := svc.SearchBookmarks(ctx, answer)
+			if err != nil {
+				return fmt.Errorf("failed to search examples: %w", err)
+			}
+			if resp.Count == 0 {
+				fmt.Fprintln(out, i18n.T("No bookmarks match '%s'.", answer))
+				continue
+			}
+			shown = resp.Examples
+			printPlainList(out, opts.Redactor.Bookmarks(shown))
+		}
+	}
+}
+
+// printPlainList prints bookmarks as a numbered list, one field per line
+func printPlainList(out io.Writer, bookmarks []dto.BookmarkResponse) {
+	if len(bookmarks) == 0 {
+		fmt.Fprintln(out, i18n.T("No bookmarks yet. Use 'tools add' to add your first one."))
+		return
+	}
+
+	fmt.Fprintln(out, i18n.T("%d bookmarks:", len(bookmarks)))
+	for i, b := range bookmarks {
+		fmt.Fprintf(out, "%d. %s: %s\n", i+1, b.ToolName, b.Description)
+		fmt.Fprintf(out, "   %s\n", summaryLine(b.Command))
+	}
+}
+
+// plainSelect returns the command to output for a selected bookmark
+// Dynamic placeholders are filled first and the result can be edited a last time. With
+// ExpandEnv, environment references are expanded after the user confirms the result;
+// ok is false when the user declines.
+func plainSelect(reader *bufio.Reader, out io.Writer, command string, opts Options) (string, bool, error) {
+	filled := false
+	if opts.Placeholders != nil && len(placeholder.Find(command)) > 0 {
+		rendered, ok, err := plainFill(reader, out, command, *opts.Placeholders)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		command, filled = rendered, true
+	}
+
+	expanded, missing := command, []string(nil)
+	if opts.ExpandEnv {
+		expanded, missing = utils.ExpandEnvFromOS(command)
+	}
+	if filled {
+		if len(missing) > 0 {
+			fmt.Fprintln(out, i18n.T("Warning: unset variables left unexpanded: %s", strings.Join(missing, ", ")))
+		}
+		return plainEdit(reader, out, expanded)
+	}
+	if expanded == command && len(missing) == 0 {
+		return command, true, nil
+	}
+
+	fmt.Fprintln(out, i18n.T("Expanded: %s", expanded))
+	if len(missing) > 0 {
+		fmt.Fprintln(out, i18n.T("Warning: unset variables left unexpanded: %s", strings.Join(missing, ", ")))
+	}
+	fmt.Fprint(out, i18n.T("Use the expanded command? [y/N]: "))
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes", i18n.T("y"), i18n.T("yes"):
+		return expanded, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// plainEdit shows the rendered command and lets the user replace it by typing a changed one
+// Scripts can't be typed on one line and are used as they are.
+func plainEdit(reader *bufio.Reader, out io.Writer, command string) (string, bool, error) {
+	if runner.IsScript(command) {
+		return command, true, nil
+	}
+
+	fmt.Fprintln(out, i18n.T("Command: %s", command))
+	fmt.Fprint(out, i18n.T("Press enter to use it or type a changed command: "))
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", false, nil
+	}
+	if edited := strings.TrimSpace(line); edited != "" {
+		return edited, true, nil
+	}
+	return command, true, nil
+}
+
+// plainFill asks for a value of each dynamic placeholder among its provider's output
+// Providers that are not trusted only run after the user agrees; ok is false when the
+// user declines, a provider fails or the answer is not a listed value.
+func plainFill(reader *bufio.Reader, out io.Writer, command string, r placeholder.Runner) (string, bool, error) {
+	for _, d := range placeholder.Find(command) {
+		if !r.IsTrusted(d.Provider) {
+			fmt.Fprintln(out, i18n.T("The values of {%s} come from running: %s", d.Name, d.Provider))
+			fmt.Fprint(out, i18n.T("Run it? [y/N]: "))
+			line, err := reader.ReadString('\n')
+			if err != nil && line == "" {
+				return "", false, nil
+			}
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "y", "yes", i18n.T("y"), i18n.T("yes"):
+			default:
+				return "", false, nil
+			}
+		}
+
+		values, err := r.Values(context.Background(), d.Provider)
+		if err != nil {
+			fmt.Fprintln(out, i18n.T("provider of {%s} failed: %v", d.Name, err))
+			return "", false, nil
+		}
+		if len(values) == 0 {
+			fmt.Fprintln(out, i18n.T("provider of {%s} returned no values", d.Name))
+			return "", false, nil
+		}
+
+		for i, value := range values {
+			fmt.Fprintf(out, "%d. %s\n", i+1, value)
+		}
+		fmt.Fprint(out, i18n.T("Select [1-%d]: ", len(values)))
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", false, nil
+		}
+		n, convErr := strconv.Atoi(strings.TrimSpace(line))
+		if convErr != nil || n < 1 || n > len(values) {
+			return "", false, nil
+		}
+		command = placeholder.Fill(command, d, values[n-1])
+	}
+	return command, true, nil
+}
+
+// plainConfirmReveal asks whether to reveal the redacted values of a command, shown redacted
+func plainConfirmReveal(reader *bufio.Reader, out io.Writer, shown string) bool {
+	fmt.Fprint(out, i18n.T("'%s' contains redacted values. Reveal them?", summaryLine(shown))+i18n.T(" [y/N]: "))
+	line, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes", i18n.T("y"), i18n.T("yes"):
+		return true
+	default:
+		return false
+	}
+}
+
+// printPlainSelection outputs the selected command; scripts are saved to a file first
+// Unlike the TUI, nothing is copied to the clipboard: the escape sequence would garble
+// terminals that don't support it.
+func printPlainSelection(out io.Writer, command string) error {
+	if runner.IsScript(command) {
+		path, err := runner.WriteScript(command)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, i18n.T("Saved script to %s", path))
+		fmt.Fprintln(out, path)
+		return nil
+	}
+	fmt.Fprintln(out, command)
+	return nil
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/service"
+)
+
+var (
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // Red
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("46"))  // Bright green
+)
+
+type revisionsLoadedMsg struct {
+	revisions *dto.RevisionsResponse
+}
+
+func loadRevisions(svc service.BookmarkService, command string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := svc.ListRevisions(context.Background(), command)
+		if err != nil {
+			return errorMsg{err}
+		}
+		return revisionsLoadedMsg{revisions: resp}
+	}
+}
+
+func (m model) handleRevisionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc", "q":
+		m.mode = modeList
+		m.revisions = nil
+		return m, nil
+
+	case "up", "k":
+		if m.revisionCursor > 0 {
+			m.revisionCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.revisions != nil && m.revisionCursor < m.revisions.Count-1 {
+			m.revisionCursor++
+		}
+		return m, nil
+
+	case "r", "enter":
+		return m.submitRestore()
+	}
+
+	return m, nil
+}
+
+func (m model) submitRestore() (tea.Model, tea.Cmd) {
+	if m.revisions == nil || m.revisions.Count == 0 {
+		return m, nil
+	}
+
+	rev := m.revisions.Revisions[m.revisionCursor]
+	_, err := m.service.RestoreRevision(context.Background(), m.revisions.Current.Command, rev.Number)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.mode = modeList
+	m.revisions = nil
+	m.err = nil
+	return m, loadBookmarks(m.service)
+}
+
+func (m model) revisionsView() string {
+	if m.revisions == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(i18n.T("Revisions")))
+	b.WriteString("\n\n")
+	b.WriteString(itemStyle.Render(i18n.T("Current: %s", m.revisions.Current.Command)))
+	b.WriteString("\n\n")
+
+	if m.revisions.Count == 0 {
+		b.WriteString(itemStyle.Render(i18n.T("No previous revisions.")))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(i18n.T("esc: back")))
+		return b.String()
+	}
+
+	for i, rev := range m.revisions.Revisions {
+		cursor := "  "
+		if i == m.revisionCursor {
+			cursor = "> "
+		}
+		line := fmt.Sprintf("%s#%d  %s  %s", cursor, rev.Number, rev.ChangedAt.Local().Format("2006-01-02 15:04"), rev.Description)
+		b.WriteString(itemStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	// Show what restoring the selected revision would change
+	selected := m.revisions.Revisions[m.revisionCursor]
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(i18n.T("Restoring #%d changes:", selected.Number)))
+	b.WriteString("\n")
+	for _, line := range revisionDiffLines(m.revisions.Current, selected) {
+		b.WriteString(itemStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render(i18n.T("↑/↓: select revision • r/enter: restore • esc: back")))
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
+	}
+
+	return b.String()
+}
+
+// revisionDiffLines renders the fields that differ between the current version and a revision
+func revisionDiffLines(current dto.BookmarkResponse, rev dto.RevisionResponse) []string {
+	fields := []struct {
+		name     string
+		current  string
+		revision string
+	}{
+		{"command", current.Command, rev.Command},
+		{"tool", current.ToolName, rev.ToolName},
+		{"description", current.Description, rev.Description},
+		{"tags", strings.Join(current.Tags, ", "), strings.Join(rev.Tags, ", ")},
+	}
+
+	var lines []string
+	for _, f := range fields {
+		if f.current == f.revision {
+			continue
+		}
+		lines = append(lines, removedStyle.Render(fmt.Sprintf("- %s: %s", f.name, f.current)))
+		lines = append(lines, addedStyle.Render(fmt.Sprintf("+ %s: %s", f.name, f.revision)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, i18n.T("(identical to current)"))
+	}
+
+	return lines
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/mattn/go-runewidth"
+)
+
+// Bounds of the sidebar width, including its border and padding
+const (
+	minSidebarWidth = 16
+	maxSidebarWidth = 30
+	sidebarChrome   = 4 // Border and padding around the tool names
+)
+
+var (
+	sidebarStyle         = baseStyle.Padding(0, 1)
+	sidebarSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Bold(true)
+	sidebarFocusedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("46"))
+)
+
+// toolEntry is a line of the sidebar: a tool and the number of its bookmarks
+// The first entry, with an empty name, stands for all bookmarks.
+type toolEntry struct {
+	name  string
+	count int
+}
+
+// sidebarTools returns the entry of all bookmarks followed by the tools of the loaded
+// bookmarks, by name. A selected tool without bookmarks is kept, so it can be left.
+func (m model) sidebarTools() []toolEntry {
+	counts := map[string]int{}
+	for _, example := range m.bookmarks {
+		counts[example.ToolName]++
+	}
+	if m.tool != "" {
+		counts[m.tool] += 0
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	entries := []toolEntry{{count: len(m.bookmarks)}}
+	for _, name := range names {
+		entries = append(entries, toolEntry{name: name, count: counts[name]})
+	}
+	return entries
+}
+
+// label returns the text of an entry in the sidebar
+func (e toolEntry) label() string {
+	name := e.name
+	if name == "" {
+		name = i18n.T("All")
+	}
+	return fmt.Sprintf("%s (%d)", name, e.count)
+}
+
+// sidebarWidth returns the width of the sidebar, 0 when it is hidden
+func (m model) sidebarWidth() int {
+	if !m.sidebar {
+		return 0
+	}
+	width := 0
+	for _, entry := range m.sidebarTools() {
+		width = max(width, runewidth.StringWidth(entry.label()))
+	}
+	return min(max(width+sidebarChrome, minSidebarWidth), maxSidebarWidth)
+}
+
+// relayout fits the table into the terminal width left next to the sidebar
+func (m *model) relayout() {
+	width := m.width
+	if width == 0 {
+		width = defaultTableWidth
+	}
+	m.table.SetColumns(layoutColumns(m.columns, width-m.sidebarWidth()))
+	m.markSortColumn()
+	m.setRows(m.bookmarks)
+}
+
+// toggleSidebar shows or hides the sidebar; a shown sidebar gets the focus
+func (m model) toggleSidebar() (tea.Model, tea.Cmd) {
+	m.sidebar = !m.sidebar
+	m.sidebarFocus = m.sidebar
+	m.relayout()
+	return m, nil
+}
+
+// selectTool shows the bookmarks of the tool of the sidebar entry at index
+func (m model) selectTool(index int) (tea.Model, tea.Cmd) {
+	entries := m.sidebarTools()
+	if index < 0 || index >= len(entries) {
+		return m, nil
+	}
+	m.tool = entries[index].name
+	m.setRows(m.bookmarks)
+	m.table.SetCursor(0)
+	return m, nil
+}
+
+// toolIndex returns the index of the selected tool among the sidebar entries
+func (m model) toolIndex(entries []toolEntry) int {
+	for i, entry := range entries {
+		if entry.name == m.tool {
+			return i
+		}
+	}
+	return 0
+}
+
+// handleSidebarKeys handles the keys of the focused sidebar; ok is false for keys left to
+// the table
+func (m model) handleSidebarKeys(msg tea.KeyMsg) (_ tea.Model, _ tea.Cmd, ok bool) {
+	index := m.toolIndex(m.sidebarTools())
+	switch msg.String() {
+	case "up", "k":
+		updated, cmd := m.selectTool(index - 1)
+		return updated, cmd, true
+	case "down", "j":
+		updated, cmd := m.selectTool(index + 1)
+		return updated, cmd, true
+	case "enter", "tab", "right", "l":
+		m.sidebarFocus = false
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// sidebarView renders the tools next to the table, as high as the table
+func (m model) sidebarView(height int) string {
+	entries := m.sidebarTools()
+	selected := m.toolIndex(entries)
+	inner := m.sidebarWidth() - sidebarChrome
+
+	// Scroll the selected tool into view
+	start := 0
+	if height > 0 && selected >= height {
+		start = selected - height + 1
+	}
+
+	var lines []string
+	for i := start; i < len(entries) && (height <= 0 || len(lines) < height); i++ {
+		label := runewidth.FillRight(runewidth.Truncate(entries[i].label(), inner, "…"), inner)
+		switch {
+		case i == selected && m.sidebarFocus:
+			label = sidebarFocusedStyle.Render(label)
+		case i == selected:
+			label = sidebarSelectedStyle.Render(label)
+		}
+		lines = append(lines, label)
+	}
+	return sidebarStyle.Height(height).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,89 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSidebarTools(t *testing.T) {
+	m := NewModel(nil, Options{Sidebar: true})
+	m.setRows(viewBookmarks)
+
+	var labels []string
+	for _, entry := range m.sidebarTools() {
+		labels = append(labels, entry.label())
+	}
+	if want := []string{"All (3)", "docker (1)", "kubectl (2)"}; !slices.Equal(labels, want) {
+		t.Errorf("Expected sidebar %v, got %v", want, labels)
+	}
+
+	view := m.View()
+	for _, label := range labels {
+		if !strings.Contains(view, label) {
+			t.Errorf("Expected %q in the sidebar, got:\n%s", label, view)
+		}
+	}
+}
+
+func TestSidebarSelectsTool(t *testing.T) {
+	m := NewModel(nil, Options{Sidebar: true})
+	m.setRows(viewBookmarks)
+	if m.sidebarFocus {
+		t.Fatal("Expected the table to have the focus on start")
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if m.tool != "kubectl" {
+		t.Fatalf("Expected kubectl to be selected, got %q", m.tool)
+	}
+	if got := shownCommands(m); !slices.Equal(got, []string{"kubectl get pods", "kubectl logs -f"}) {
+		t.Errorf("Expected the bookmarks of kubectl, got %v", got)
+	}
+
+	// Past the last tool the selection stays, enter hands the keys back to the table
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.tool != "kubectl" || m.sidebarFocus {
+		t.Errorf("Expected kubectl with the focus on the table, got %q, focus %v", m.tool, m.sidebarFocus)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.table.Cursor() != 1 || m.tool != "kubectl" {
+		t.Errorf("Expected down to move through the table, got cursor %d and tool %q", m.table.Cursor(), m.tool)
+	}
+
+	// The tool is part of the view restored on the next start, and reset with ctrl+l
+	if m.viewState().Tool != "kubectl" {
+		t.Errorf("Expected the tool in the view state, got %+v", m.viewState())
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	if m.tool != "" || len(m.examples) != 3 {
+		t.Errorf("Expected ctrl+l to show all bookmarks, got %q with %v", m.tool, shownCommands(m))
+	}
+}
+
+func TestToggleSidebar(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+	full := m.columnWidth(ColumnCommand)
+
+	m = typeKeys(t, m, runes("s"))
+	if !m.sidebar || !m.sidebarFocus {
+		t.Fatalf("Expected s to show and focus the sidebar, got %v, %v", m.sidebar, m.sidebarFocus)
+	}
+	if m.columnWidth(ColumnCommand) >= full {
+		t.Errorf("Expected the table to narrow next to the sidebar, got %d of %d", m.columnWidth(ColumnCommand), full)
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyDown}, runes("s"))
+	if m.sidebar || m.columnWidth(ColumnCommand) != full {
+		t.Errorf("Expected s to hide the sidebar and widen the table, got %v with %d", m.sidebar, m.columnWidth(ColumnCommand))
+	}
+	if !strings.Contains(m.View(), "tool: docker") {
+		t.Errorf("Expected the hidden tool selection in the status, got:\n%s", m.View())
+	}
+}
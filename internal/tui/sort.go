@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// sortIndicators mark the title of the column the table is sorted by
+const (
+	sortAscending  = " ▲"
+	sortDescending = " ▼"
+)
+
+// sortBy sorts the table by the visible column at index, toggling the direction when it is
+// already sorted by that column. The selected bookmark stays selected.
+func (m model) sortBy(index int) (tea.Model, tea.Cmd) {
+	if index < 0 || index >= len(m.columns) {
+		return m, nil
+	}
+
+	selected := ""
+	if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(m.rowToBookmarkMap) {
+		selected = m.tableRows[m.rowToBookmarkMap[cursor]].command
+	}
+
+	name := m.columns[index].Name
+	if m.sortColumn == name {
+		m.sortDesc = !m.sortDesc
+	} else {
+		m.sortColumn, m.sortDesc = name, false
+	}
+	m.markSortColumn()
+	m.setRows(m.bookmarks)
+
+	for row, bookmarkIndex := range m.rowToBookmarkMap {
+		if m.isFirstRow[row] && m.tableRows[bookmarkIndex].command == selected {
+			m.table.SetCursor(row)
+			break
+		}
+	}
+	return m, nil
+}
+
+// sortExamples returns the bookmarks in the order of the sorted column, or as given when the
+// table is not sorted
+func (m model) sortExamples(examples []dto.BookmarkResponse) []dto.BookmarkResponse {
+	if m.sortColumn == "" {
+		return examples
+	}
+
+	sorted := slices.Clone(examples)
+	slices.SortStableFunc(sorted, func(a, b dto.BookmarkResponse) int {
+		c := strings.Compare(strings.ToLower(sortValue(a, m.sortColumn)), strings.ToLower(sortValue(b, m.sortColumn)))
+		if m.sortDesc {
+			return -c
+		}
+		return c
+	})
+	return sorted
+}
+
+// sortValue returns the value of a bookmark in the named column
+func sortValue(example dto.BookmarkResponse, column string) string {
+	switch column {
+	case ColumnTool:
+		return example.ToolName
+	case ColumnDescription:
+		return example.Description
+	default:
+		return example.Command
+	}
+}
+
+// markSortColumn shows the sort direction next to the title of the sorted column
+func (m *model) markSortColumn() {
+	cols := m.table.Columns()
+	for i, c := range m.columns {
+		if i >= len(cols) {
+			break
+		}
+		cols[i].Title = i18n.T(columnTitles[c.Name])
+		if c.Name == m.sortColumn {
+			if m.sortDesc {
+				cols[i].Title += sortDescending
+			} else {
+				cols[i].Title += sortAscending
+			}
+		}
+	}
+	m.table.SetColumns(cols)
+}
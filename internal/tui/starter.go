@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/bundle"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// starterModel lets a new user pick the starter packs to install
+type starterModel struct {
+	packs    []*bundle.Bundle
+	selected []bool
+	cursor   int
+	done     bool // Confirmed with enter; false when skipped
+}
+
+func newStarterModel(packs []*bundle.Bundle) starterModel {
+	return starterModel{packs: packs, selected: make([]bool, len(packs))}
+}
+
+func (m starterModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m starterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "esc", "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.packs)-1 {
+			m.cursor++
+		}
+	case " ", "x":
+		if len(m.packs) > 0 {
+			m.selected[m.cursor] = !m.selected[m.cursor]
+		}
+	case "a":
+		// Select all, or none when all are selected already
+		all := !m.allSelected()
+		for i := range m.selected {
+			m.selected[i] = all
+		}
+	case "enter":
+		m.done = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m starterModel) allSelected() bool {
+	for _, s := range m.selected {
+		if !s {
+			return false
+		}
+	}
+	return true
+}
+
+// chosen returns the selected packs, none when the selection was skipped
+func (m starterModel) chosen() []*bundle.Bundle {
+	if !m.done {
+		return nil
+	}
+	var packs []*bundle.Bundle
+	for i, b := range m.packs {
+		if m.selected[i] {
+			packs = append(packs, b)
+		}
+	}
+	return packs
+}
+
+func (m starterModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(i18n.T("Welcome to tools! Start with some bookmarks?")))
+	b.WriteString("\n\n")
+	for i, pack := range m.packs {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if m.selected[i] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s%s %-12s %s", cursor, check, pack.Metadata.Name, i18n.T("%d bookmarks", len(pack.Bookmarks)))
+		if pack.Metadata.Description != "" {
+			line += " - " + pack.Metadata.Description
+		}
+		b.WriteString(itemStyle.Render(line))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render(i18n.T("space: select • a: all • enter: install • esc: skip")))
+
+	return b.String()
+}
+
+// SelectStarterPacks shows the starter packs and returns those the user selected,
+// none when the selection was skipped
+func SelectStarterPacks(packs []*bundle.Bundle) ([]*bundle.Bundle, error) {
+	finalModel, err := tea.NewProgram(newStarterModel(packs)).Run()
+	if err != nil {
+		return nil, err
+	}
+	if fm, ok := finalModel.(starterModel); ok {
+		return fm.chosen(), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/search"
+)
+
+// maxSuggestions limits the suggestions shown below an input
+const maxSuggestions = 5
+
+// suggestion is a value offered below the focused input while typing, with the number of
+// bookmarks using it; 0 for values not from the store, like executables on the PATH
+type suggestion struct {
+	value string
+	count int
+}
+
+// rankSuggestions returns the values matching token, best first: those starting with it, then
+// those containing it or its letters in order, then likely misspellings. Ties go to the more
+// used value; a value typed out already is not suggested.
+func rankSuggestions(token string, counts map[string]int) []suggestion {
+	token = strings.ToLower(token)
+	if token == "" {
+		return nil
+	}
+
+	rank := func(value string) int {
+		value = strings.ToLower(value)
+		switch {
+		case value == token:
+			return -1 // Typed out already, nothing to suggest
+		case strings.HasPrefix(value, token):
+			return 0
+		case strings.Contains(value, token):
+			return 1
+		case isSubsequence(token, value):
+			return 2
+		case search.Distance(token, value) <= max(len([]rune(token))/3, 1):
+			return 3
+		default:
+			return -1
+		}
+	}
+
+	type ranked struct {
+		suggestion
+		rank int
+	}
+	var matches []ranked
+	for value, count := range counts {
+		if r := rank(value); r >= 0 {
+			matches = append(matches, ranked{suggestion{value, count}, r})
+		}
+	}
+	slices.SortFunc(matches, func(a, b ranked) int {
+		if a.rank != b.rank {
+			return a.rank - b.rank
+		}
+		if a.count != b.count {
+			return b.count - a.count
+		}
+		return strings.Compare(a.value, b.value)
+	})
+
+	suggestions := make([]suggestion, 0, min(len(matches), maxSuggestions))
+	for _, match := range matches[:min(len(matches), maxSuggestions)] {
+		suggestions = append(suggestions, match.suggestion)
+	}
+	return suggestions
+}
+
+// isSubsequence reports whether the runes of sub appear in s in order, e.g. "kbs" in "kubernetes"
+func isSubsequence(sub, s string) bool {
+	rs := []rune(s)
+	i := 0
+	for _, r := range sub {
+		for i < len(rs) && rs[i] != r {
+			i++
+		}
+		if i == len(rs) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// suggestions returns the suggestions for the focused input of the add or edit form
+func (m model) suggestions() []suggestion {
+	switch m.focusIndex {
+	case toolField:
+		return m.toolSuggestions()
+	case tagsField:
+		return m.tagSuggestions()
+	}
+	return nil
+}
+
+// acceptSuggestion puts the value of a suggestion into the focused input
+func (m *model) acceptSuggestion(s suggestion) {
+	switch m.focusIndex {
+	case toolField:
+		m.field(toolField).SetValue(s.value)
+		m.applyToolDefaults()
+	case tagsField:
+		head, _ := cutTagToken(m.field(tagsField).Value())
+		m.field(tagsField).SetValue(head + s.value + ", ")
+	}
+	m.field(m.focusIndex).CursorEnd()
+	m.suggestionSelected = 0
+}
+
+// handleSuggestionKeys moves through the suggestions of the focused input and accepts the
+// highlighted one; ok is false for keys left to the form
+func (m model) handleSuggestionKeys(msg tea.KeyMsg) (_ tea.Model, ok bool) {
+	suggestions := m.suggestions()
+	if len(suggestions) == 0 {
+		return m, false
+	}
+
+	switch msg.String() {
+	case "down":
+		m.suggestionSelected = min(m.suggestionSelected+1, len(suggestions))
+		return m, true
+	case "up":
+		if m.suggestionSelected == 0 {
+			return m, false
+		}
+		m.suggestionSelected--
+		return m, true
+	case "tab", "enter":
+		if m.suggestionSelected == 0 {
+			return m, false
+		}
+		m.acceptSuggestion(suggestions[m.suggestionSelected-1])
+		return m, true
+	}
+	return m, false
+}
+
+// suggestionsView renders the suggestions below an input while it has the focus, the
+// highlighted one marked
+func (m model) suggestionsView(field int) string {
+	suggestions := m.suggestions()
+	if field != m.focusIndex || len(suggestions) == 0 {
+		return ""
+	}
+	labels := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		label := s.value
+		if s.count > 0 {
+			label = fmt.Sprintf("%s (%d)", s.value, s.count)
+		}
+		if i == m.suggestionSelected-1 {
+			label = sidebarFocusedStyle.Render(label)
+		}
+		labels[i] = label
+	}
+	return itemStyle.Render(helpStyle.UnsetPadding().Render("↓ ") + strings.Join(labels, "  "))
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"strings"
+)
+
+// tagsField is the index of the tags among the inputs of the add and edit forms
+const tagsField = 3
+
+// parseTags splits the value of the tags input at commas and whitespace
+func parseTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// cutTagToken splits the value of the tags input before the tag being typed
+func cutTagToken(value string) (head, token string) {
+	i := strings.LastIndexAny(value, ", \t")
+	return value[:i+1], value[i+1:]
+}
+
+// tagSuggestions returns the tags of the store matching the tag being typed; tags already
+// entered are left out
+func (m model) tagSuggestions() []suggestion {
+	if m.focusIndex != tagsField {
+		return nil
+	}
+	head, token := cutTagToken(m.field(tagsField).Value())
+
+	counts := map[string]int{}
+	for _, example := range m.bookmarks {
+		for _, tag := range example.Tags {
+			counts[tag]++
+		}
+	}
+	for _, tag := range parseTags(head) {
+		delete(counts, strings.ToLower(tag))
+	}
+	return rankSuggestions(token, counts)
+}
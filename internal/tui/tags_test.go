@@ -0,0 +1,138 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/service"
+)
+
+var taggedBookmarks = []dto.BookmarkResponse{
+	{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods", Tags: []string{"k8s", "kubernetes"}},
+	{Command: "kubectl get nodes", ToolName: "kubectl", Description: "list nodes", Tags: []string{"k8s"}},
+	{Command: "helm list", ToolName: "helm", Description: "list releases", Tags: []string{"k8s", "helm"}},
+	{Command: "docker ps", ToolName: "docker", Description: "list containers", Tags: []string{"docker", "local"}},
+}
+
+func suggestedTags(m model) []string {
+	var tags []string
+	for _, suggestion := range m.tagSuggestions() {
+		tags = append(tags, suggestion.value)
+	}
+	return tags
+}
+
+func TestTagSuggestions(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(taggedBookmarks)
+	m.mode, m.focusIndex = modeAdd, tagsField
+
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{"k", []string{"k8s", "kubernetes", "docker"}}, // Prefix first, the more used first
+		{"ube", []string{"kubernetes"}},                // Contained
+		{"kbs", []string{"kubernetes", "k8s"}},         // Letters in order, then misspelled
+		{"kuberntes", []string{"kubernetes"}},
+		{"dokcer", []string{"docker"}},
+		{"k8s", nil}, // Typed out
+		{"k8s, ", nil},
+		{"k8s, k", []string{"kubernetes", "docker"}}, // Entered tags are not offered again
+		{"prod", nil},
+	}
+	for _, tt := range tests {
+		m.field(tagsField).SetValue(tt.value)
+		if got := suggestedTags(m); !slices.Equal(got, tt.expected) {
+			t.Errorf("%q: expected suggestions %v, got %v", tt.value, tt.expected, got)
+		}
+	}
+
+	m.focusIndex = 0
+	m.field(tagsField).SetValue("k")
+	if got := suggestedTags(m); got != nil {
+		t.Errorf("Expected no suggestions without focus on the tags, got %v", got)
+	}
+}
+
+func TestAcceptTagSuggestion(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods", Tags: []string{"k8s", "kubernetes"}},
+		&models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list containers", Tags: []string{"docker"}},
+	))
+	ctx := context.Background()
+	resp, _ := svc.ListBookmarks(ctx)
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+	m = typeKeys(t, m, runes("a"))
+	m.cmdInput.SetValue("kubectl get svc")
+	m.field(descField).SetValue("list services")
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	if m.focusIndex != tagsField {
+		t.Fatalf("Expected the tags to have the focus, got field %d", m.focusIndex)
+	}
+
+	// Down highlights the second suggestion, enter takes it instead of submitting
+	m = typeKeys(t, m, runes("k"), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if view := m.View(); !strings.Contains(view, "kubernetes (1)") {
+		t.Errorf("Expected the suggestions below the input, got:\n%s", view)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeAdd || m.field(tagsField).Value() != "kubernetes, " {
+		t.Fatalf("Expected the suggestion to be taken, got mode %v with %q", m.mode, m.field(tagsField).Value())
+	}
+
+	// Up past the first suggestion leaves the input, like without suggestions
+	m = typeKeys(t, m, runes("d"), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyUp})
+	if m.suggestionSelected != 0 || m.focusIndex != tagsField {
+		t.Fatalf("Expected no highlighted suggestion, got %d in field %d", m.suggestionSelected, m.focusIndex)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.field(tagsField).Value() != "kubernetes, d" || m.focusIndex != 0 {
+		t.Errorf("Expected tab without highlight to move on, got %q in field %d", m.field(tagsField).Value(), m.focusIndex)
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil || m.mode != modeList {
+		t.Fatalf("Failed to save: %v", m.err)
+	}
+	saved, err := svc.GetBookmark(ctx, "kubectl get svc")
+	if err != nil || !slices.Equal(saved.Tags, []string{"d", "kubernetes"}) {
+		t.Errorf("Expected the entered tags to be saved, got %+v, %v", saved, err)
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods", Tags: []string{"k8s", "prod"}},
+	))
+	ctx := context.Background()
+	resp, _ := svc.ListBookmarks(ctx)
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+	m = typeKeys(t, m, runes("e"))
+	if m.field(tagsField).Value() != "k8s, prod" {
+		t.Fatalf("Expected the tags in the edit form, got %q", m.field(tagsField).Value())
+	}
+
+	m.field(tagsField).SetValue("")
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil {
+		t.Fatalf("Failed to save: %v", m.err)
+	}
+	if saved, _ := svc.GetBookmark(ctx, "kubectl get pods"); len(saved.Tags) != 0 {
+		t.Errorf("Expected an empty input to clear the tags, got %v", saved.Tags)
+	}
+}
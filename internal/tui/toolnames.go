@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/search"
+	"github.com/fgeck/tools/internal/toolcheck"
+)
+
+// toolField is the index of the tool name among the inputs of the add and edit forms
+const toolField = 1
+
+// executablesLoadedMsg carries the names of the executables on the PATH
+type executablesLoadedMsg struct {
+	names []string
+}
+
+// loadExecutables lists the executables on the PATH in the background, reading every
+// directory of a long PATH would delay the start
+func loadExecutables() tea.Msg {
+	return executablesLoadedMsg{names: toolcheck.Executables(os.Getenv("PATH"))}
+}
+
+// toolCounts returns the tool names of the loaded bookmarks with the number of their bookmarks
+func (m model) toolCounts() map[string]int {
+	counts := map[string]int{}
+	for _, example := range m.bookmarks {
+		counts[example.ToolName]++
+	}
+	return counts
+}
+
+// toolSuggestions returns the tool names of the store matching the typed name, followed by
+// executables on the PATH starting with it. The PATH holds thousands of names, so only its
+// prefixes are offered, while a misspelled tool of the store is still found.
+func (m model) toolSuggestions() []suggestion {
+	if m.focusIndex != toolField {
+		return nil
+	}
+	token := strings.TrimSpace(m.field(toolField).Value())
+	counts := m.toolCounts()
+	suggestions := rankSuggestions(token, counts)
+	if token == "" {
+		return suggestions
+	}
+
+	prefix := strings.ToLower(token)
+	for _, name := range m.executables {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		if _, stored := counts[name]; stored || strings.EqualFold(name, token) || !strings.HasPrefix(strings.ToLower(name), prefix) {
+			continue
+		}
+		suggestions = append(suggestions, suggestion{value: name})
+	}
+	return suggestions
+}
+
+// similarTool returns the tool of the store a new tool name likely misspells, "" when the name
+// is in use, is an executable on the PATH or is like none of the stored tools
+func (m model) similarTool(name string) string {
+	counts := m.toolCounts()
+	if name == "" || counts[name] > 0 || slices.Contains(m.executables, name) {
+		return ""
+	}
+
+	// The most used tool wins among equally close ones
+	names := make([]string, 0, len(counts))
+	for tool := range counts {
+		names = append(names, tool)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	if closest := search.Closest(name, names, 1); len(closest) > 0 {
+		return closest[0]
+	}
+	return ""
+}
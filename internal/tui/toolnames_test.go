@@ -0,0 +1,111 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/service"
+)
+
+func suggestedTools(m model) []string {
+	var tools []string
+	for _, suggestion := range m.toolSuggestions() {
+		tools = append(tools, suggestion.value)
+	}
+	return tools
+}
+
+func TestToolSuggestions(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(taggedBookmarks)
+	m.executables = []string{"dockerd", "helm", "kubeadm", "kubectl", "kubectx", "top"}
+	m.mode, m.focusIndex = modeAdd, toolField
+
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{"kube", []string{"kubectl", "kubeadm", "kubectx"}}, // Stored tools first, then executables
+		{"kubctl", []string{"kubectl"}},                     // Misspellings only among the stored tools
+		{"Dock", []string{"docker", "dockerd"}},
+		{"hlm", []string{"helm"}},
+		{"kubectl", nil},
+		{"to", []string{"top"}},
+	}
+	for _, tt := range tests {
+		m.field(toolField).SetValue(tt.value)
+		if got := suggestedTools(m); !slices.Equal(got, tt.expected) {
+			t.Errorf("%q: expected suggestions %v, got %v", tt.value, tt.expected, got)
+		}
+	}
+
+	m.focusIndex = tagsField
+	m.field(toolField).SetValue("kube")
+	if got := suggestedTools(m); got != nil {
+		t.Errorf("Expected no suggestions without focus on the tool name, got %v", got)
+	}
+}
+
+func TestAcceptToolSuggestion(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"},
+	))
+	ctx := context.Background()
+	resp, _ := svc.ListBookmarks(ctx)
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+	m = typeKeys(t, m, runes("a"))
+	m = typeKeys(t, m, runes("k get svc"))
+	m.field(descField).SetValue("list services")
+
+	// Leaving the command prefills "k", which is a prefix of the stored kubectl
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	if view := m.View(); !strings.Contains(view, "kubectl (1)") {
+		t.Errorf("Expected the stored tool below the input, got:\n%s", view)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeAdd || m.field(toolField).Value() != "kubectl" {
+		t.Fatalf("Expected the suggestion to be taken, got mode %v with %q", m.mode, m.field(toolField).Value())
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil || m.mode != modeList {
+		t.Fatalf("Failed to save: %v", m.err)
+	}
+	if saved, err := svc.GetBookmark(ctx, "k get svc"); err != nil || saved.ToolName != "kubectl" {
+		t.Errorf("Expected the bookmark under kubectl, got %+v, %v", saved, err)
+	}
+}
+
+func TestSimilarToolWarning(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+	m.mode = modeAdd
+	m.cmdInput.SetValue("kubctl get pods -A")
+
+	m.field(toolField).SetValue("kubctl")
+	if warnings := m.formWarnings(); len(warnings) != 1 || warnings[0] != "no bookmarks for kubctl yet, did you mean kubectl?" {
+		t.Errorf("Expected a warning about the misspelled tool, got %v", warnings)
+	}
+
+	// An installed executable is a tool of its own
+	m.executables = []string{"kubctl"}
+	if warnings := m.formWarnings(); warnings != nil {
+		t.Errorf("Expected no warning for an executable on the PATH, got %v", warnings)
+	}
+
+	m.field(toolField).SetValue("kubectl")
+	if warnings := m.formWarnings(); warnings != nil {
+		t.Errorf("Expected no warning for a stored tool, got %v", warnings)
+	}
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// toolPalette holds the colors tool names are drawn in; all are readable on dark and light backgrounds
+var toolPalette = []lipgloss.Color{
+	"39",  // Deep sky blue
+	"208", // Dark orange
+	"170", // Orchid
+	"42",  // Spring green
+	"214", // Orange
+	"75",  // Steel blue
+	"204", // Hot pink
+	"149", // Dark olive green
+	"141", // Medium purple
+	"37",  // Teal
+	"178", // Gold
+	"167", // Indian red
+}
+
+// toolIcons maps tool names to Nerd Font glyphs
+var toolIcons = map[string]string{
+	"git":       "\ue702",
+	"gh":        "\uf09b",
+	"docker":    "\ue7b0",
+	"kubectl":   "\U000f10fe",
+	"helm":      "\U000f10fe",
+	"k9s":       "\U000f10fe",
+	"go":        "\ue627",
+	"python":    "\ue73c",
+	"python3":   "\ue73c",
+	"pip":       "\ue73c",
+	"node":      "\ue718",
+	"npm":       "\ue71e",
+	"cargo":     "\ue7a8",
+	"rustc":     "\ue7a8",
+	"aws":       "\ue7ad",
+	"terraform": "\U000f1062",
+	"vim":       "\ue62b",
+	"nvim":      "\ue62b",
+	"psql":      "\ue76e",
+	"mysql":     "\ue704",
+}
+
+// defaultToolIcon is shown for tools without a specific icon
+const defaultToolIcon = "\uf120"
+
+// toolStyle returns the style of a tool name; the same name always gets the same color
+func toolStyle(toolName string) lipgloss.Style {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(toolName)))
+	return lipgloss.NewStyle().Foreground(toolPalette[h.Sum32()%uint32(len(toolPalette))])
+}
+
+// toolIcon returns the Nerd Font icon of a tool
+func toolIcon(toolName string) string {
+	if icon, ok := toolIcons[strings.ToLower(toolName)]; ok {
+		return icon
+	}
+	return defaultToolIcon
+}
+
+// colorToolColumn colors the tool column of a rendered table
+// The table measures cell values including escape sequences, so colored values would be
+// truncated; instead the plain rendered rows are colored afterwards.
+// Rows that are styled already (header, selected row) keep their style.
+func colorToolColumn(view string, rows []tableRow, offset, width int) string {
+	styles := make(map[string]lipgloss.Style, len(rows))
+	for _, row := range rows {
+		styles[strings.TrimSpace(runewidth.Truncate(row.toolLabel, width, "…"))] = toolStyle(row.toolName)
+	}
+
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "\x1b") {
+			continue
+		}
+		head, rest := cutWidth(line, offset)
+		cell, tail := cutWidth(rest, width)
+		style, ok := styles[strings.TrimSpace(cell)]
+		if !ok || strings.TrimSpace(cell) == "" {
+			continue
+		}
+		lines[i] = head + style.Render(cell) + tail
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cutWidth splits s after the given display width
+func cutWidth(s string, width int) (string, string) {
+	w := 0
+	for i, r := range s {
+		rw := runewidth.RuneWidth(r)
+		if w+rw > width {
+			return s[:i], s[i:]
+		}
+		w += rw
+	}
+	return s, ""
+}
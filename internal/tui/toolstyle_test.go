@@ -0,0 +1,54 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"testing"
+)
+
+func TestToolStyleIsConsistent(t *testing.T) {
+	first := toolStyle("kubectl").GetForeground()
+	if second := toolStyle("KUBECTL").GetForeground(); first != second {
+		t.Errorf("Expected the same color for the same tool, got %v and %v", first, second)
+	}
+}
+
+func TestToolIcon(t *testing.T) {
+	if icon := toolIcon("Docker"); icon != toolIcons["docker"] {
+		t.Errorf("Expected docker icon, got %q", icon)
+	}
+	if icon := toolIcon("some-unknown-tool"); icon != defaultToolIcon {
+		t.Errorf("Expected default icon, got %q", icon)
+	}
+}
+
+func TestCutWidth(t *testing.T) {
+	tests := []struct {
+		s, head, tail string
+		width         int
+	}{
+		{" kubectl   list", " ", "kubectl   list", 1},
+		{"kubectl   list", "kubectl   ", "list", 10},
+		{"short", "short", "", 10},
+		{"日本語ab", "日本", "語ab", 5},
+	}
+
+	for _, tt := range tests {
+		head, tail := cutWidth(tt.s, tt.width)
+		if head != tt.head || tail != tt.tail {
+			t.Errorf("cutWidth(%q, %d) = %q, %q; want %q, %q", tt.s, tt.width, head, tail, tt.head, tt.tail)
+		}
+	}
+}
+
+func TestColorToolColumnKeepsStyledLines(t *testing.T) {
+	view := "\x1b[1m Tool      \x1b[0m\n other     text"
+	rows := []tableRow{{toolName: "other", toolLabel: "other"}}
+
+	result := colorToolColumn(view, rows, 1, 10)
+
+	if result[:len("\x1b[1m Tool      \x1b[0m\n")] != "\x1b[1m Tool      \x1b[0m\n" {
+		t.Errorf("Expected styled line to be kept, got %q", result)
+	}
+}
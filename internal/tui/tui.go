@@ -1,18 +1,29 @@
 package tui
 
 import (
+	"bufio"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fgeck/tools/internal/domain/models"
 	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/parse"
+	"github.com/fgeck/tools/internal/placeholder"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/secrets"
 	"github.com/fgeck/tools/internal/service"
-	"github.com/fgeck/tools/internal/utils"
 )
 
 var (
@@ -25,8 +36,11 @@ var (
 
 type tableRow struct {
 	toolName    string
+	toolLabel   string // Tool name as displayed, with icon, origin and due badge
 	description string // Example description
 	command     string // The actual command to execute
+	tags        []string
+	updatedAt   time.Time // When the bookmark was last changed, to detect changes made elsewhere
 }
 
 type mode int
@@ -36,32 +50,127 @@ const (
 	modeAdd
 	modeEdit
 	modeDelete
+	modePreview
+	modeRevisions
+	modeDetail
+	modeFill
+	modeVariants
+	modeInline
+	modeFilter
 )
 
+// Options configures optional TUI behavior
+type Options struct {
+	ExpandEnv bool     // Expand $VAR/${VAR} references from the environment when selecting
+	Icons     bool     // Show Nerd Font icons next to tool names
+	Columns   []Column // Visible table columns in display order; empty uses DefaultColumns
+	Plain     bool     // Line-oriented mode without colors, borders and alternate screen
+	Sidebar   bool     // Start with the sidebar of tool names, to browse the bookmarks by tool
+	Canonical bool     // Offer the canonical form of commands of known tools in the forms
+
+	// Redactor hides parts of commands in the table, the details and the preview; they are
+	// revealed on copy or run after confirmation. nil hides nothing
+	Redactor *secrets.Redactor
+
+	// StateFile keeps the filter, sort and grouping of the table between sessions; empty
+	// keeps them for the session only
+	StateFile string
+
+	// ToolDefaults returns the description and tags new bookmarks of a tool start with; nil
+	// prefills nothing
+	ToolDefaults func(tool, command string) (description string, tags []string)
+
+	// Placeholders runs the providers of dynamic placeholders like {pod:kubectl get pods -o name}
+	// when selecting, to pick their values; nil leaves such placeholders as written
+	Placeholders *placeholder.Runner
+}
+
 type model struct {
 	table            table.Model
+	columns          []Column
+	bookmarks        []dto.BookmarkResponse // All loaded bookmarks, before the filter
+	examples         []dto.BookmarkResponse // Bookmarks shown in the table
 	tableRows        []tableRow
 	rowToBookmarkMap []int  // Maps table row index to bookmark index in tableRows
 	isFirstRow       []bool // Tracks if a display row is the first row of its bookmark
 	service          service.BookmarkService
+	options          Options
 	mode             mode
 	err              error
 	quitting         bool
-	selectedCmd      string // Command to output when exiting
+	selectedCmd      string          // Command to output when exiting
+	selectedKey      string          // Stored command (primary key) of the selected bookmark
+	execute          bool            // Run the selected command instead of copying it
+	due              map[string]bool // Commands whose reminder is due, badged in the tool column
+	sortColumn       string          // Column the table is sorted by, empty for the order of the store
+	sortDesc         bool            // Sort descending instead of ascending
+	filter           string          // Words every shown bookmark contains, empty for all
+	grouped          bool            // Bookmarks are grouped by tool
+	tool             string          // Tool selected in the sidebar, empty for all
+	sidebar          bool            // The sidebar of tool names is shown
+	sidebarFocus     bool            // Keys move through the sidebar instead of the table
+	width            int             // Terminal width, 0 until the first resize
 
 	// Add/Edit mode fields
-	toolNameInput textinput.Model
-	descInput     textinput.Model
-	cmdInput      textinput.Model
-	focusIndex    int
-	inputs        []textinput.Model
+	cmdInput           textarea.Model    // Command, which may span several lines
+	focusIndex         int               // Focused field, see commandField
+	inputs             []textinput.Model // Tool name, description and tags, see field
+	suggestionSelected int               // Suggestion highlighted with the arrow keys, from 1; 0 for none
+	executables        []string          // Names of the executables on the PATH, suggested as tool names
+
+	// Add mode specific
+	duplicateOf  string // Command of the bookmark being duplicated, empty for a new bookmark
+	defaultsTool string // Tool whose defaults were applied to the form
 
 	// Edit mode specific
-	originalCmd string // Original command being edited
+	originalCmd       string    // Original command being edited
+	originalUpdatedAt time.Time // Update time of the bookmark when the form was opened
+
+	// Preview mode specific
+	previewKey     string          // Stored command of the previewed bookmark
+	previewCmd     string          // Command after filling placeholders and expanding the environment
+	previewInput   textinput.Model // Last edit of a single-line previewed command
+	previewMissing []string        // Referenced variables that are not set
+	previewUnmet   []string        // Required environment variables and executables that are missing
+
+	// Revisions mode specific
+	revisions      *dto.RevisionsResponse
+	revisionCursor int
+
+	// Detail mode specific
+	detail        *dto.BookmarkResponse
+	detailRun     *dto.RunResult // Result of the latest execution, nil if it never ran
+	related       *dto.RankedBookmarksResponse
+	relatedCursor int
+
+	// Fill mode specific
+	fillKey     string                // Stored command of the bookmark being filled
+	fillCmd     string                // Command with the placeholders filled so far
+	fillPending []placeholder.Dynamic // Placeholders still to fill, the first is shown
+	fillValues  []string              // Values of the first pending placeholder, nil until its provider ran
+	fillCursor  int
+	fillLoading bool // The provider of the first pending placeholder is running
+
+	// Variants mode specific
+	variantOS     []string          // Operating systems in the form, in display order
+	variantInputs []textinput.Model // Variant of each operating system, empty for none
+	variantFocus  int
+
+	// Inline mode specific
+	inlineKey       string    // Stored command of the bookmark whose cell is edited
+	inlineUpdatedAt time.Time // Update time of the bookmark when editing started
+	inlineField     string    // Column being edited, ColumnDescription or ColumnTool
+	inlineValue     string    // Value of the cell before editing
+	inlineInput     textinput.Model
+
+	// Filter mode specific
+	filterInput  textinput.Model
+	filterBefore string // Filter when editing started, restored on esc
 }
 
 type bookmarksLoadedMsg struct {
 	examples []dto.BookmarkResponse
+	due      map[string]bool
 }
 
 type errorMsg struct {
@@ -75,19 +184,26 @@ func loadBookmarks(svc service.BookmarkService) tea.Cmd {
 		if err != nil {
 			return errorMsg{err}
 		}
-		return bookmarksLoadedMsg{examples: resp.Examples}
+		dueResp, err := svc.ListDue(ctx, time.Now())
+		if err != nil {
+			return errorMsg{err}
+		}
+		due := make(map[string]bool, dueResp.Count)
+		for _, b := range dueResp.Bookmarks {
+			due[b.Command] = true
+		}
+		return bookmarksLoadedMsg{examples: resp.Examples, due: due}
 	}
 }
 
-func NewModel(svc service.BookmarkService) model {
-	columns := []table.Column{
-		{Title: "Tool", Width: 15},
-		{Title: "Description", Width: 40},
-		{Title: "Command", Width: 50},
+func NewModel(svc service.BookmarkService, opts Options) model {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
 	}
 
 	t := table.New(
-		table.WithColumns(columns),
+		table.WithColumns(layoutColumns(columns, defaultTableWidth)),
 		table.WithFocused(true),
 		table.WithHeight(20),
 	)
@@ -105,60 +221,64 @@ func NewModel(svc service.BookmarkService) model {
 		Bold(false)
 	t.SetStyles(s)
 
-	// Initialize text inputs for add mode - order: Command, Tool Name, Description
-	cmdInput := textinput.New()
-	cmdInput.Placeholder = "Command (e.g., lsof -i :54321)"
-	cmdInput.Focus()
-	cmdInput.CharLimit = 200
-	cmdInput.Width = 50
-
+	// Initialize the inputs for add mode - order: Command, Tool Name, Description, Tags
 	toolNameInput := textinput.New()
-	toolNameInput.Placeholder = "Tool name (e.g., lsof)"
+	toolNameInput.Placeholder = i18n.T("Tool name (e.g., lsof)")
 	toolNameInput.CharLimit = 50
 	toolNameInput.Width = 50
 
 	descInput := textinput.New()
-	descInput.Placeholder = "Description (e.g., list all ports at port 54321)"
+	descInput.Placeholder = i18n.T("Description (e.g., list all ports at port 54321)")
 	descInput.CharLimit = 200
 	descInput.Width = 50
 
+	tagsInput := textinput.New()
+	tagsInput.Placeholder = i18n.T("Tags, comma-separated (e.g., k8s, prod)")
+	tagsInput.CharLimit = 200
+	tagsInput.Width = 50
+
 	m := model{
-		table:         t,
-		service:       svc,
-		mode:          modeList,
-		toolNameInput: toolNameInput,
-		descInput:     descInput,
-		cmdInput:      cmdInput,
-		inputs:        []textinput.Model{cmdInput, toolNameInput, descInput},
+		table:    t,
+		columns:  columns,
+		service:  svc,
+		options:  opts,
+		mode:     modeList,
+		cmdInput: newCommandInput(),
+		inputs:   []textinput.Model{toolNameInput, descInput, tagsInput},
+	}
+
+	if opts.StateFile != "" {
+		state, err := LoadViewState(opts.StateFile)
+		m.err = err
+		m.restoreView(state)
+	}
+	if opts.Sidebar {
+		m.sidebar = true
+		m.relayout()
 	}
 
 	return m
 }
 
-// updateColumnWidths dynamically adjusts table column widths based on terminal size
-func (m *model) updateColumnWidths(termWidth int) {
-	const (
-		toolWidth = 15
-		padding   = 10 // Account for borders, spacing
-	)
-
-	availableWidth := termWidth - toolWidth - padding
-	if availableWidth < 50 {
-		availableWidth = 50 // Minimum width
+// toolLabel returns the tool column text of a bookmark
+// Bookmarks of read-only layers are marked with their origin next to the tool name,
+// bookmarks whose reminder is due with a badge.
+func (m model) toolLabel(example dto.BookmarkResponse) string {
+	label := example.ToolName
+	if m.options.Icons {
+		label = toolIcon(example.ToolName) + " " + label
 	}
-
-	descWidth := int(float64(availableWidth) * 0.4)
-	cmdWidth := int(float64(availableWidth) * 0.5)
-
-	m.table.SetColumns([]table.Column{
-		{Title: "Tool", Width: toolWidth},
-		{Title: "Description", Width: descWidth},
-		{Title: "Command", Width: cmdWidth},
-	})
+	if example.Origin != "" {
+		label = fmt.Sprintf("%s [%s]", label, example.Origin)
+	}
+	if m.due[example.Command] {
+		label += " " + i18n.T("(due)")
+	}
+	return label
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(loadBookmarks(m.service), textinput.Blink)
+	return tea.Batch(loadBookmarks(m.service), loadExecutables, textinput.Blink)
 }
 
 // findNextFirstRow finds the next row index that is a first row, starting from current+1
@@ -187,59 +307,38 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.table.SetHeight(msg.Height - 10)
-		m.updateColumnWidths(msg.Width)
+		m.width = msg.Width
+		m.relayout()
 		return m, nil
 
 	case bookmarksLoadedMsg:
-		rows := []table.Row{}
-		m.tableRows = []tableRow{}
-		m.rowToBookmarkMap = []int{}
-		m.isFirstRow = []bool{}
-
-		// Get current column widths
-		cols := m.table.Columns()
-		descWidth := 40 // Default
-		cmdWidth := 50  // Default
-		if len(cols) >= 3 {
-			descWidth = cols[1].Width
-			cmdWidth = cols[2].Width
+		m.due = msg.due
+		m.setRows(msg.examples)
+		return m, nil
+
+	case revisionsLoadedMsg:
+		m.mode = modeRevisions
+		m.revisions = msg.revisions
+		m.revisionCursor = msg.revisions.Count - 1 // Start at the most recent revision
+		if m.revisionCursor < 0 {
+			m.revisionCursor = 0
 		}
+		return m, nil
 
-		bookmarkIndex := 0
-		for _, example := range msg.examples {
-			// Store the original bookmark
-			m.tableRows = append(m.tableRows, tableRow{
-				toolName:    example.ToolName,
-				description: example.Description,
-				command:     example.Command,
-			})
-
-			// Wrap and split into multiple rows if needed
-			wrappedRows := utils.SplitWrappedRows(
-				example.ToolName,
-				example.Description,
-				example.Command,
-				descWidth,
-				cmdWidth,
-			)
-
-			for rowIdx, row := range wrappedRows {
-				rows = append(rows, table.Row{row[0], row[1], row[2]})
-				m.rowToBookmarkMap = append(m.rowToBookmarkMap, bookmarkIndex)
-				m.isFirstRow = append(m.isFirstRow, rowIdx == 0) // Only first row is true
-			}
+	case detailLoadedMsg:
+		m.mode = modeDetail
+		m.detail = msg.bookmark
+		m.detailRun = msg.lastRun
+		m.related = msg.related
+		m.relatedCursor = 0
+		m.err = nil
+		return m, nil
 
-			bookmarkIndex++
-		}
-		m.table.SetRows(rows)
-		// Ensure cursor starts on a first row
-		if len(m.isFirstRow) > 0 {
-			cursor := m.table.Cursor()
-			if cursor >= 0 && cursor < len(m.isFirstRow) && !m.isFirstRow[cursor] {
-				// If current cursor is not on a first row, find the nearest first row
-				m.table.SetCursor(m.findNextFirstRow(-1)) // Start from -1 to get first available
-			}
-		}
+	case valuesLoadedMsg:
+		return m.handleValuesLoaded(msg)
+
+	case executablesLoadedMsg:
+		m.executables = msg.names
 		return m, nil
 
 	case errorMsg:
@@ -256,6 +355,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleEditKeys(msg)
 		case modeDelete:
 			return m.handleDeleteKeys(msg)
+		case modePreview:
+			return m.handlePreviewKeys(msg)
+		case modeRevisions:
+			return m.handleRevisionsKeys(msg)
+		case modeDetail:
+			return m.handleDetailKeys(msg)
+		case modeFill:
+			return m.handleFillKeys(msg)
+		case modeVariants:
+			return m.handleVariantsKeys(msg)
+		case modeInline:
+			return m.handleInlineKeys(msg)
+		case modeFilter:
+			return m.handleFilterKeys(msg)
 		}
 	}
 
@@ -274,6 +387,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.sidebar && m.sidebarFocus {
+		if updated, cmd, ok := m.handleSidebarKeys(msg); ok {
+			return updated, cmd
+		}
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "esc", "q":
 		m.quitting = true
@@ -281,9 +400,7 @@ func (m model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "a":
 		m.mode = modeAdd
-		m.focusIndex = 0
-		m.inputs[0].Focus()
-		return m, textinput.Blink
+		return m, m.focusField(commandField)
 
 	case "up", "k", "pgup":
 		// Navigate to previous first row
@@ -308,32 +425,87 @@ func (m model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					row := m.tableRows[bookmarkIndex]
 					m.mode = modeEdit
 					m.originalCmd = row.command
-					// Pre-fill inputs with current values - order: Command, Tool Name, Description
-					m.inputs[0].SetValue(row.command)
-					m.inputs[1].SetValue(row.toolName)
-					m.inputs[2].SetValue(row.description)
-					m.focusIndex = 0
-					m.inputs[0].Focus()
+					m.originalUpdatedAt = row.updatedAt
+					m.fillInputs(row)
 					return m, textinput.Blink
 				}
 			}
 		}
 
+	case "1", "2", "3":
+		// Sort by the column at this position, again to toggle the direction
+		return m.sortBy(int(msg.String()[0] - '1'))
+
+	case "/":
+		return m.startFilter()
+
+	case "t":
+		return m.toggleGroup()
+
+	case "s":
+		return m.toggleSidebar()
+
+	case "tab":
+		if m.sidebar {
+			m.sidebarFocus = true
+			return m, nil
+		}
+
+	case "ctrl+l":
+		return m.resetView()
+
+	case "f2":
+		// Quick fix of the description, tab switches to the tool name
+		return m.startInline(ColumnDescription)
+
+	case "c":
+		// Duplicate: open the add form pre-filled with the selected bookmark
+		cursor := m.table.Cursor()
+		if cursor >= 0 && cursor < len(m.rowToBookmarkMap) {
+			bookmarkIndex := m.rowToBookmarkMap[cursor]
+			if bookmarkIndex >= 0 && bookmarkIndex < len(m.tableRows) {
+				row := m.tableRows[bookmarkIndex]
+				m.mode = modeAdd
+				m.duplicateOf = row.command
+				m.defaultsTool = row.toolName // The copy keeps the tags and description it has
+				m.fillInputs(row)
+				return m, textinput.Blink
+			}
+		}
+
 	case "d", "delete":
 		if len(m.tableRows) > 0 {
 			m.mode = modeDelete
 			return m, nil
 		}
 
-	case "enter":
-		// Select the command and exit
+	case "v":
 		cursor := m.table.Cursor()
 		if cursor >= 0 && cursor < len(m.rowToBookmarkMap) {
 			bookmarkIndex := m.rowToBookmarkMap[cursor]
 			if bookmarkIndex >= 0 && bookmarkIndex < len(m.tableRows) {
-				m.selectedCmd = m.tableRows[bookmarkIndex].command
-				m.quitting = true
-				return m, tea.Quit
+				return m, loadRevisions(m.service, m.tableRows[bookmarkIndex].command)
+			}
+		}
+
+	case "i":
+		cursor := m.table.Cursor()
+		if cursor >= 0 && cursor < len(m.rowToBookmarkMap) {
+			bookmarkIndex := m.rowToBookmarkMap[cursor]
+			if bookmarkIndex >= 0 && bookmarkIndex < len(m.tableRows) {
+				return m, loadDetail(m.service, m.tableRows[bookmarkIndex].command)
+			}
+		}
+
+	case "enter", "x":
+		// Select the command and exit, running it right away for x
+		cursor := m.table.Cursor()
+		if cursor >= 0 && cursor < len(m.rowToBookmarkMap) {
+			bookmarkIndex := m.rowToBookmarkMap[cursor]
+			if bookmarkIndex >= 0 && bookmarkIndex < len(m.tableRows) {
+				key := m.tableRows[bookmarkIndex].command
+				m.execute = msg.String() == "x"
+				return m.selectCommand(key, m.osCommand(key))
 			}
 		}
 	}
@@ -350,6 +522,13 @@ func (m model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleAddKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if updated, cmd, ok := m.handlePaste(msg); ok {
+		return updated, cmd
+	}
+	if updated, ok := m.handleSuggestionKeys(msg); ok {
+		return updated, nil
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "esc":
 		m.mode = modeList
@@ -360,33 +539,28 @@ func (m model) handleAddKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Submit on enter from any field
 		return m.submitAdd()
 
+	case "ctrl+n":
+		m.useCanonical()
+		return m, nil
+
 	case "tab", "shift+tab", "up", "down":
 		s := msg.String()
-
-		// Navigation
-		switch s {
-		case "up", "shift+tab":
-			m.focusIndex--
-		case "down", "tab":
-			m.focusIndex++
+		if m.commandLineKey(s) {
+			break
 		}
 
-		if m.focusIndex > len(m.inputs)-1 {
-			m.focusIndex = 0
-		} else if m.focusIndex < 0 {
-			m.focusIndex = len(m.inputs) - 1
+		// Leaving the command, suggest the tool name it invokes; with a tool name, prefill
+		// what is configured for it
+		if m.focusIndex == commandField {
+			m.prefillToolName()
 		}
+		m.applyToolDefaults()
 
-		cmds := make([]tea.Cmd, len(m.inputs))
-		for i := 0; i < len(m.inputs); i++ {
-			if i == m.focusIndex {
-				cmds[i] = m.inputs[i].Focus()
-			} else {
-				m.inputs[i].Blur()
-			}
+		// Navigation
+		if s == "up" || s == "shift+tab" {
+			return m, m.moveFocus(-1)
 		}
-
-		return m, tea.Batch(cmds...)
+		return m, m.moveFocus(1)
 	}
 
 	// Update current input
@@ -395,6 +569,10 @@ func (m model) handleAddKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if updated, ok := m.handleSuggestionKeys(msg); ok {
+		return updated, nil
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "esc":
 		m.mode = modeList
@@ -405,33 +583,21 @@ func (m model) handleEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Submit on enter from any field
 		return m.submitEdit()
 
+	case "ctrl+n":
+		m.useCanonical()
+		return m, nil
+
 	case "tab", "shift+tab", "up", "down":
 		s := msg.String()
-
-		// Navigation
-		switch s {
-		case "up", "shift+tab":
-			m.focusIndex--
-		case "down", "tab":
-			m.focusIndex++
-		}
-
-		if m.focusIndex > len(m.inputs)-1 {
-			m.focusIndex = 0
-		} else if m.focusIndex < 0 {
-			m.focusIndex = len(m.inputs) - 1
+		if m.commandLineKey(s) {
+			break
 		}
 
-		cmds := make([]tea.Cmd, len(m.inputs))
-		for i := 0; i < len(m.inputs); i++ {
-			if i == m.focusIndex {
-				cmds[i] = m.inputs[i].Focus()
-			} else {
-				m.inputs[i].Blur()
-			}
+		// Navigation
+		if s == "up" || s == "shift+tab" {
+			return m, m.moveFocus(-1)
 		}
-
-		return m, tea.Batch(cmds...)
+		return m, m.moveFocus(1)
 	}
 
 	// Update current input
@@ -452,38 +618,92 @@ func (m model) handleDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m model) handlePreviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.mode = modeList
+		m.previewKey = ""
+		m.previewCmd = ""
+		m.previewMissing = nil
+		m.previewUnmet = nil
+		return m, nil
+
+	case "enter":
+		command := m.previewCmd
+		if !m.previewReadOnly() {
+			command = strings.TrimSpace(m.previewInput.Value())
+		}
+		if command == "" {
+			return m, nil
+		}
+		m.selectedCmd = command
+		m.selectedKey = m.previewKey
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	// Scripts are shown as they are, the single-line input would flatten them, and redacted
+	// commands can't be edited without revealing them
+	if m.previewReadOnly() {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.previewInput, cmd = m.previewInput.Update(msg)
+	return m, cmd
+}
+
 func (m *model) updateInputs(msg tea.KeyMsg) tea.Cmd {
-	cmds := make([]tea.Cmd, len(m.inputs))
+	cmds := make([]tea.Cmd, len(m.inputs)+1)
+	m.suggestionSelected = 0 // The suggestions follow the typed value
 
+	cmds[commandField] = m.updateCommand(msg)
 	for i := range m.inputs {
-		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+		m.inputs[i], cmds[i+1] = m.inputs[i].Update(msg)
 	}
 
-	// Sync back to individual fields - order: Command, Tool Name, Description
-	m.cmdInput = m.inputs[0]
-	m.toolNameInput = m.inputs[1]
-	m.descInput = m.inputs[2]
-
 	return tea.Batch(cmds...)
 }
 
+// fillInputs pre-fills the form with a bookmark and focuses the command
+func (m *model) fillInputs(row tableRow) {
+	// Order: Command, Tool Name, Description, Tags
+	m.cmdInput.SetValue(row.command)
+	m.fitCommand()
+	m.field(toolField).SetValue(row.toolName)
+	m.field(descField).SetValue(row.description)
+	m.field(tagsField).SetValue(strings.Join(row.tags, ", "))
+	m.focusField(commandField)
+}
+
+// prefillToolName fills an empty tool name with the tool the command invokes
+func (m *model) prefillToolName() {
+	if strings.TrimSpace(m.field(toolField).Value()) != "" {
+		return
+	}
+	m.field(toolField).SetValue(parse.ToolName(m.cmdInput.Value()))
+}
+
 func (m *model) resetInputs() {
 	for i := range m.inputs {
 		m.inputs[i].SetValue("")
 	}
-	m.toolNameInput.SetValue("")
-	m.descInput.SetValue("")
 	m.cmdInput.SetValue("")
-	m.focusIndex = 0
+	m.fitCommand()
+	m.focusField(commandField)
+	m.duplicateOf = ""
+	m.defaultsTool = ""
+	m.suggestionSelected = 0
 }
 
 func (m model) submitAdd() (tea.Model, tea.Cmd) {
-	toolName := strings.TrimSpace(m.toolNameInput.Value())
-	desc := strings.TrimSpace(m.descInput.Value())
+	m.prefillToolName()
+	m.applyToolDefaults()
+	toolName := strings.TrimSpace(m.field(toolField).Value())
+	desc := strings.TrimSpace(m.field(descField).Value())
 	cmd := strings.TrimSpace(m.cmdInput.Value())
 
 	if toolName == "" || desc == "" || cmd == "" {
-		m.err = fmt.Errorf("tool name, description, and command are required")
+		m.err = errors.New(i18n.T("tool name, description, and command are required"))
 		return m, nil
 	}
 
@@ -491,6 +711,7 @@ func (m model) submitAdd() (tea.Model, tea.Cmd) {
 		Command:     cmd,
 		ToolName:    toolName,
 		Description: desc,
+		Tags:        parseTags(m.field(tagsField).Value()),
 	}
 
 	ctx := context.Background()
@@ -507,20 +728,29 @@ func (m model) submitAdd() (tea.Model, tea.Cmd) {
 }
 
 func (m model) submitEdit() (tea.Model, tea.Cmd) {
-	toolName := strings.TrimSpace(m.toolNameInput.Value())
-	desc := strings.TrimSpace(m.descInput.Value())
+	toolName := strings.TrimSpace(m.field(toolField).Value())
+	desc := strings.TrimSpace(m.field(descField).Value())
 	cmd := strings.TrimSpace(m.cmdInput.Value())
 
 	if toolName == "" || desc == "" || cmd == "" {
-		m.err = fmt.Errorf("tool name, description, and command are required")
+		m.err = errors.New(i18n.T("tool name, description, and command are required"))
 		return m, nil
 	}
 
+	// An empty tags input clears the tags, nil would keep them
+	tags := parseTags(m.field(tagsField).Value())
+	if tags == nil {
+		tags = []string{}
+	}
+
+	// Saving fails if the bookmark was changed elsewhere, e.g. by the CLI, while the form was open
 	req := dto.UpdateBookmarkRequest{
-		Command:        m.originalCmd,
-		NewToolName:    toolName,
-		NewDescription: desc,
-		NewCommand:     cmd,
+		Command:           m.originalCmd,
+		NewToolName:       toolName,
+		NewDescription:    desc,
+		NewCommand:        cmd,
+		NewTags:           tags,
+		ExpectedUpdatedAt: &m.originalUpdatedAt,
 	}
 
 	ctx := context.Background()
@@ -549,8 +779,14 @@ func (m model) submitDelete() (tea.Model, tea.Cmd) {
 
 	row := m.tableRows[bookmarkIndex]
 	ctx := context.Background()
-	// Delete the specific example by its command (primary key)
-	err := m.service.DeleteBookmark(ctx, row.command)
+	// Delete the specific example by its command (primary key), unless it changed since it was
+	// listed; the reloaded list then shows the change
+	err := m.service.DeleteBookmarkIfUnchanged(ctx, row.command, row.updatedAt)
+	if errors.Is(err, models.ErrConflict) {
+		m.err = err
+		m.mode = modeList
+		return m, loadBookmarks(m.service)
+	}
 	if err != nil {
 		m.err = err
 		m.mode = modeList
@@ -574,6 +810,16 @@ func (m model) View() string {
 		return m.editView()
 	case modeDelete:
 		return m.deleteView()
+	case modePreview:
+		return m.previewView()
+	case modeRevisions:
+		return m.revisionsView()
+	case modeDetail:
+		return m.detailView()
+	case modeFill:
+		return m.fillView()
+	case modeVariants:
+		return m.variantsView()
 	default:
 		return m.listView()
 	}
@@ -582,51 +828,107 @@ func (m model) View() string {
 func (m model) listView() string {
 	var b strings.Builder
 
-	b.WriteString(titleStyle.Render("Tools - Command Bookmarks"))
+	b.WriteString(titleStyle.Render(i18n.T("Tools - Command Bookmarks")))
 	b.WriteString("\n\n")
-	b.WriteString(baseStyle.Render(m.table.View()))
+	table := baseStyle.Render(m.tableView())
+	if m.sidebar {
+		table = lipgloss.JoinHorizontal(lipgloss.Top, m.sidebarView(lipgloss.Height(table)-borderWidth), table)
+	}
+	b.WriteString(table)
 	b.WriteString("\n")
 
+	if m.mode == modeInline {
+		b.WriteString(m.inlineView())
+		return b.String()
+	}
+	if m.mode == modeFilter {
+		b.WriteString(m.filterView())
+		return b.String()
+	}
+	if status := m.viewStatus(); status != "" {
+		b.WriteString(itemStyle.Render(status))
+		b.WriteString("\n")
+	}
+
 	// Help
-	help := helpStyle.Render("↑/↓: navigate • enter: select (copies to clipboard) • a: add • e: edit • d: delete • q/esc: quit")
+	help := helpStyle.Render(i18n.T("↑/↓: navigate • enter: select (copies to clipboard) • x: run • a: add • c: duplicate • e: edit • f2: quick edit • d: delete • v: revisions • i: details • 1/2/3: sort • /: filter • t: group • s: tools • q/esc: quit"))
+	if m.sidebar && m.sidebarFocus {
+		help = helpStyle.Render(i18n.T("↑/↓: choose tool • enter/tab: to the bookmarks • s: hide tools • q/esc: quit"))
+	}
 	b.WriteString(help)
 
 	if m.err != nil {
 		b.WriteString("\n")
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
 	}
 
 	return b.String()
 }
 
+// tableView renders the table with colored tool names
+func (m model) tableView() string {
+	width := m.columnWidth(ColumnTool)
+	if width == 0 {
+		return m.table.View()
+	}
+	return colorToolColumn(m.table.View(), m.tableRows, m.columnOffset(ColumnTool), width)
+}
+
 func (m model) addView() string {
 	var b strings.Builder
 
-	b.WriteString(titleStyle.Render("Add New Example"))
+	if m.duplicateOf != "" {
+		b.WriteString(titleStyle.Render(i18n.T("Duplicate Example")))
+		b.WriteString("\n")
+		b.WriteString(itemStyle.Render(i18n.T("Change the command to save a variant of: %s", summaryLine(m.duplicateOf))))
+	} else {
+		b.WriteString(titleStyle.Render(i18n.T("Add New Example")))
+	}
 	b.WriteString("\n\n")
 
 	// Order: Command, Tool Name, Description
-	b.WriteString(itemStyle.Render("Command:"))
+	b.WriteString(itemStyle.Render(i18n.T("Command:")))
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(m.cmdInput.View()))
+	b.WriteString("\n")
+	if offer := m.canonicalView(); offer != "" {
+		b.WriteString(offer)
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(m.inputs[0].View()))
-	b.WriteString("\n\n")
 
-	b.WriteString(itemStyle.Render("Tool Name:"))
+	b.WriteString(itemStyle.Render(i18n.T("Tool Name:")))
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(m.field(toolField).View()))
+	b.WriteString("\n")
+	if suggestions := m.suggestionsView(toolField); suggestions != "" {
+		b.WriteString(suggestions)
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(m.inputs[1].View()))
-	b.WriteString("\n\n")
 
-	b.WriteString(itemStyle.Render("Description:"))
+	b.WriteString(itemStyle.Render(i18n.T("Description:")))
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(m.inputs[2].View()))
+	b.WriteString(itemStyle.Render(m.field(descField).View()))
 	b.WriteString("\n\n")
 
-	help := helpStyle.Render("tab/shift+tab: navigate • enter: submit • esc: cancel")
+	b.WriteString(itemStyle.Render(i18n.T("Tags:")))
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(m.field(tagsField).View()))
+	b.WriteString("\n")
+	if suggestions := m.suggestionsView(tagsField); suggestions != "" {
+		b.WriteString(suggestions)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.warningsView())
+
+	help := helpStyle.Render(i18n.T("tab/shift+tab: navigate • enter: submit • alt+enter: new line • esc: cancel"))
 	b.WriteString(help)
 
 	if m.err != nil {
 		b.WriteString("\n")
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
 	}
 
 	return b.String()
@@ -635,31 +937,56 @@ func (m model) addView() string {
 func (m model) editView() string {
 	var b strings.Builder
 
-	b.WriteString(titleStyle.Render("Edit Example"))
+	b.WriteString(titleStyle.Render(i18n.T("Edit Example")))
 	b.WriteString("\n\n")
 
 	// Order: Command, Tool Name, Description
-	b.WriteString(itemStyle.Render("Command:"))
+	b.WriteString(itemStyle.Render(i18n.T("Command:")))
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(m.cmdInput.View()))
+	b.WriteString("\n")
+	if offer := m.canonicalView(); offer != "" {
+		b.WriteString(offer)
+		b.WriteString("\n")
+	}
+	if diff := m.commandDiffView(); diff != "" {
+		b.WriteString(diff)
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(m.inputs[0].View()))
-	b.WriteString("\n\n")
 
-	b.WriteString(itemStyle.Render("Tool Name:"))
+	b.WriteString(itemStyle.Render(i18n.T("Tool Name:")))
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(m.field(toolField).View()))
+	b.WriteString("\n")
+	if suggestions := m.suggestionsView(toolField); suggestions != "" {
+		b.WriteString(suggestions)
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(m.inputs[1].View()))
-	b.WriteString("\n\n")
 
-	b.WriteString(itemStyle.Render("Description:"))
+	b.WriteString(itemStyle.Render(i18n.T("Description:")))
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(m.inputs[2].View()))
+	b.WriteString(itemStyle.Render(m.field(descField).View()))
 	b.WriteString("\n\n")
 
-	help := helpStyle.Render("tab/shift+tab: navigate • enter: submit • esc: cancel")
+	b.WriteString(itemStyle.Render(i18n.T("Tags:")))
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(m.field(tagsField).View()))
+	b.WriteString("\n")
+	if suggestions := m.suggestionsView(tagsField); suggestions != "" {
+		b.WriteString(suggestions)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.warningsView())
+
+	help := helpStyle.Render(i18n.T("tab/shift+tab: navigate • enter: submit • alt+enter: new line • esc: cancel"))
 	b.WriteString(help)
 
 	if m.err != nil {
 		b.WriteString("\n")
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
 	}
 
 	return b.String()
@@ -678,38 +1005,162 @@ func (m model) deleteView() string {
 
 	row := m.tableRows[bookmarkIndex]
 	var b strings.Builder
-	b.WriteString(titleStyle.Render("Confirm Delete"))
+	b.WriteString(titleStyle.Render(i18n.T("Confirm Delete")))
 	b.WriteString("\n\n")
-	b.WriteString(itemStyle.Render(fmt.Sprintf("Delete example '%s' from tool '%s'?", row.description, row.toolName)))
+	b.WriteString(itemStyle.Render(i18n.T("Delete example '%s' from tool '%s'?", row.description, row.toolName)))
 	b.WriteString("\n")
-	b.WriteString(itemStyle.Render(fmt.Sprintf("Command: %s", row.command)))
+	b.WriteString(itemStyle.Render(i18n.T("Command: %s", row.command)))
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("y: yes • n/esc: no"))
+	b.WriteString(helpStyle.Render(i18n.T("y: yes • n/esc: no")))
 
 	return b.String()
 }
 
-func Run(svc service.BookmarkService) error {
-	m := NewModel(svc)
+func (m model) previewView() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(i18n.T("Preview Command")))
+	b.WriteString("\n\n")
+	b.WriteString(itemStyle.Render(i18n.T("Stored:   %s", summaryLine(m.options.Redactor.Redact(m.previewKey)))))
+	b.WriteString("\n\n")
+
+	if m.previewReadOnly() {
+		b.WriteString(itemStyle.Render(m.options.Redactor.Redact(m.previewCmd)))
+	} else {
+		b.WriteString(itemStyle.Render(m.previewInput.View()))
+	}
+	b.WriteString("\n")
+
+	if len(m.previewMissing) > 0 {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(i18n.T("Warning: unset variables left unexpanded: %s", strings.Join(m.previewMissing, ", "))))
+		b.WriteString("\n")
+	}
+	if len(m.previewUnmet) > 0 {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(i18n.T("Warning: missing requirements: %s", strings.Join(m.previewUnmet, ", "))))
+		b.WriteString("\n")
+	}
+
+	if m.execute {
+		b.WriteString(helpStyle.Render(i18n.T("edit the command if needed • enter: run • esc: back")))
+	} else {
+		b.WriteString(helpStyle.Render(i18n.T("edit the command if needed • enter: copy • esc: back")))
+	}
+
+	return b.String()
+}
+
+func Run(svc service.BookmarkService, opts Options) error {
+	if opts.Plain {
+		return runPlain(svc, opts, os.Stdin, os.Stdout)
+	}
+
+	m := NewModel(svc, opts)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
 		return err
 	}
+	if fm, ok := finalModel.(model); ok && opts.StateFile != "" {
+		if err := SaveViewState(opts.StateFile, fm.viewState()); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Warning: %v", err))
+		}
+	}
 
 	// Output the selected command if one was chosen
 	if fm, ok := finalModel.(model); ok && fm.selectedCmd != "" {
-		// Copy to clipboard using OSC 52 escape sequence
-		copyToClipboard(fm.selectedCmd)
-
-		// Print success message in green
 		greenStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("35")).Bold(true)
-		fmt.Println(greenStyle.Render(fmt.Sprintf("Copied command '%s' to your clipboard", fm.selectedCmd)))
+
+		// Bookmarks with a host run there, so hand out the ssh command
+		host, remote := "", ""
+		if bookmark, err := svc.GetBookmark(context.Background(), fm.selectedKey); err == nil && bookmark.Host != "" {
+			host, remote = bookmark.Host, fm.selectedCmd
+			fm.selectedCmd = runner.SSHCommand(host, remote)
+		}
+
+		// Redacted values are only revealed once the user agrees
+		shown := opts.Redactor.Redact(fm.selectedCmd)
+		if shown != fm.selectedCmd && !confirmReveal(os.Stdin, shown) {
+			return nil
+		}
+
+		if fm.execute {
+			if host != "" && !confirmRemote(os.Stdin, opts.Redactor.Redact(remote), host) {
+				return nil
+			}
+			return execute(svc, fm.selectedKey, fm.selectedCmd, shown, greenStyle)
+		}
+
+		if runner.IsScript(fm.selectedCmd) {
+			// Pasting a multi-line script would run it line by line, so hand out an executable file instead
+			path, err := runner.WriteScript(fm.selectedCmd)
+			if err != nil {
+				return err
+			}
+			copyToClipboard(path)
+			fmt.Println(shown)
+			fmt.Println(greenStyle.Render(i18n.T("Saved script to %s and copied its path to your clipboard", path)))
+		} else {
+			// Copy to clipboard using OSC 52 escape sequence
+			copyToClipboard(fm.selectedCmd)
+			fmt.Println(greenStyle.Render(i18n.T("Copied command '%s' to your clipboard", shown)))
+		}
+
+		if err := svc.RecordRun(context.Background(), fm.selectedKey); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// confirmRemote asks whether to run a bookmark on its host, reading the answer from in
+func confirmRemote(in io.Reader, command, host string) bool {
+	fmt.Print(i18n.T("Run '%s' on %s via ssh?", summaryLine(command), host) + i18n.T(" [y/N]: "))
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes", i18n.T("y"), i18n.T("yes"):
+		return true
+	default:
+		return false
+	}
+}
+
+// confirmReveal asks whether to reveal the redacted values of a command, shown redacted,
+// reading the answer from in
+func confirmReveal(in io.Reader, shown string) bool {
+	fmt.Print(i18n.T("'%s' contains redacted values. Reveal them?", summaryLine(shown)) + i18n.T(" [y/N]: "))
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes", i18n.T("y"), i18n.T("yes"):
+		return true
+	default:
+		return false
+	}
+}
+
+// execute runs a command selected with x and records its result for the bookmark key; shown
+// is the command as displayed, with redacted values hidden
+func execute(svc service.BookmarkService, key, command, shown string, style lipgloss.Style) error {
+	ctx := context.Background()
+	fmt.Println(style.Render(i18n.T("Running '%s'", summaryLine(shown))))
+	if err := svc.RecordRun(ctx, key); err != nil {
+		return err
+	}
+
+	result, err := runner.RunTimed(ctx, command, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
+	if err := svc.RecordRunResult(ctx, key, result.ExitCode, result.Duration); err != nil {
+		return err
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("command failed with exit code %d", result.ExitCode)
+	}
+	return nil
+}
+
 // copyToClipboard uses OSC 52 escape sequence to copy to clipboard
 func copyToClipboard(text string) {
 	// Base64 encode the text
@@ -0,0 +1,699 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/bundle"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/placeholder"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/runner"
+	"github.com/fgeck/tools/internal/secrets"
+	"github.com/fgeck/tools/internal/service"
+)
+
+func TestDuplicateBookmark(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods -n dev", ToolName: "kubectl", Description: "list pods", Tags: []string{"k8s"}},
+	))
+	ctx := context.Background()
+	resp, _ := svc.ListBookmarks(ctx)
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(model)
+	if m.mode != modeAdd || m.duplicateOf != "kubectl get pods -n dev" {
+		t.Fatalf("Expected add form for the duplicate, got mode %v of %q", m.mode, m.duplicateOf)
+	}
+	if m.field(toolField).Value() != "kubectl" || m.field(descField).Value() != "list pods" {
+		t.Errorf("Expected pre-filled fields, got %q and %q", m.field(toolField).Value(), m.field(descField).Value())
+	}
+
+	m.cmdInput.SetValue("kubectl get pods -n prod")
+	updated, _ = m.submitAdd()
+	m = updated.(model)
+	if m.err != nil {
+		t.Fatalf("Failed to save duplicate: %v", m.err)
+	}
+	if m.mode != modeList || m.duplicateOf != "" {
+		t.Errorf("Expected the form to be reset, got mode %v of %q", m.mode, m.duplicateOf)
+	}
+
+	variant, err := svc.GetBookmark(ctx, "kubectl get pods -n prod")
+	if err != nil {
+		t.Fatalf("Expected the variant to be saved: %v", err)
+	}
+	if variant.Description != "list pods" || !reflect.DeepEqual(variant.Tags, []string{"k8s"}) {
+		t.Errorf("Expected description and tags to be copied, got %+v", variant)
+	}
+}
+
+func TestAddInfersToolName(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository())
+	m := NewModel(svc, Options{})
+	m.mode = modeAdd
+
+	m.cmdInput.SetValue("sudo lsof -i :8080")
+	updated, _ := m.handleAddKeys(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(model)
+	if m.field(toolField).Value() != "lsof" {
+		t.Fatalf("Expected tool name to be pre-filled, got %q", m.field(toolField).Value())
+	}
+
+	// A tool name typed by the user is kept
+	m.field(toolField).SetValue("ports")
+	m.focusIndex = 0
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(model)
+	if m.field(toolField).Value() != "ports" {
+		t.Errorf("Expected the typed tool name to be kept, got %q", m.field(toolField).Value())
+	}
+}
+
+func TestMultiLineCommand(t *testing.T) {
+	script := "cat <<EOF > notes.txt\nhello\nEOF"
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: script, ToolName: "cat", Description: "write notes"},
+	))
+	ctx := context.Background()
+	resp, _ := svc.ListBookmarks(ctx)
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(model)
+
+	// Pasted lines are kept, their line breaks don't submit the form
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("for f in *.log; do")})
+	m = updated.(model)
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	m = updated.(model)
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("  gzip \"$f\"\ndone"), Paste: true})
+	m = updated.(model)
+	if m.mode != modeAdd || m.cmdInput.Value() != "for f in *.log; do\n  gzip \"$f\"\ndone" {
+		t.Fatalf("Expected the script in the form, got mode %v with %q", m.mode, m.cmdInput.Value())
+	}
+	if m.cmdInput.Height() != 3 {
+		t.Errorf("Expected the input to grow to the 3 lines, got %d", m.cmdInput.Height())
+	}
+
+	// Up moves between the lines of the command, then to the previous field
+	for range 2 {
+		updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyUp})
+		m = updated.(model)
+	}
+	if m.focusIndex != commandField || m.cmdInput.Line() != 0 {
+		t.Fatalf("Expected the first line of the command, got field %d line %d", m.focusIndex, m.cmdInput.Line())
+	}
+	updated, _ = m.handleAddKeys(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(model)
+	if m.focusIndex != tagsField {
+		t.Errorf("Expected up on the first line to leave the command, got field %d", m.focusIndex)
+	}
+	m.resetInputs()
+
+	// Editing a script keeps its lines
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(model)
+	m.field(descField).SetValue("write a note")
+	updated, _ = m.submitEdit()
+	m = updated.(model)
+	if m.err != nil {
+		t.Fatalf("Failed to save the edit: %v", m.err)
+	}
+	if _, err := svc.GetBookmark(ctx, script); err != nil {
+		t.Errorf("Expected the script to be kept as it was: %v", err)
+	}
+}
+
+func TestDetailOpensLink(t *testing.T) {
+	var opened string
+	openURL = func(url string) error {
+		opened = url
+		return nil
+	}
+	defer func() { openURL = runner.OpenURL }()
+
+	m := NewModel(service.NewBookmarkService(memory.NewMemoryBookmarkRepository()), Options{})
+	m.mode = modeDetail
+	m.detail = &dto.BookmarkResponse{Command: "helm list", ToolName: "helm", Description: "list releases", Link: "https://helm.sh/docs/"}
+
+	if !strings.Contains(m.detailView(), "link:") {
+		t.Error("Expected the link in the detail view")
+	}
+
+	m.handleDetailKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if opened != "https://helm.sh/docs/" {
+		t.Errorf("Expected the link to be opened, got %q", opened)
+	}
+}
+
+func TestViewsUseSelectedLanguage(t *testing.T) {
+	i18n.SetLanguage("de")
+	defer i18n.SetLanguage(i18n.DefaultLanguage)
+
+	m := NewModel(service.NewBookmarkService(memory.NewMemoryBookmarkRepository()), Options{})
+	view := m.listView()
+	for _, want := range []string{"Befehls-Lesezeichen", "Beschreibung", "q/esc: beenden"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in German list view, got: %s", want, view)
+		}
+	}
+}
+
+func TestRunPlain(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods -A", ToolName: "kubectl", Description: "list all pods"},
+		&models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list containers"},
+	))
+
+	var out strings.Builder
+	in := strings.NewReader("7\nnothing-matches\ncontainers\n1\n")
+	if err := runPlain(svc, Options{}, in, &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"2 bookmarks:\n",
+		"1. kubectl: list all pods\n   kubectl get pods -A\n",
+		"There is no bookmark number 7.",
+		"No bookmarks match 'nothing-matches'.",
+		"1 bookmarks:\n1. docker: list containers\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if !strings.HasSuffix(output, "quit: docker ps\n") {
+		t.Errorf("Expected the selected command last, got: %s", output)
+	}
+	if strings.Contains(output, "\x1b") {
+		t.Errorf("Expected no escape sequences, got: %q", output)
+	}
+
+	out.Reset()
+	if err := runPlain(svc, Options{}, strings.NewReader("q\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "quit: ") {
+		t.Errorf("Expected no selection after quitting, got: %s", out.String())
+	}
+}
+
+func TestRunPlainExpandEnv(t *testing.T) {
+	t.Setenv("TOOLS_TEST_NS", "dev")
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods -n $TOOLS_TEST_NS", ToolName: "kubectl", Description: "list pods"},
+	))
+
+	var out strings.Builder
+	if err := runPlain(svc, Options{ExpandEnv: true}, strings.NewReader("1\ny\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "[y/N]: kubectl get pods -n dev\n") {
+		t.Errorf("Expected the expanded command, got: %s", out.String())
+	}
+}
+
+func TestFillPlaceholders(t *testing.T) {
+	template := "kubectl logs -f {pod:printf 'pod/api\\npod/web\\n'}"
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: template, ToolName: "kubectl", Description: "follow logs"},
+	))
+	resp, _ := svc.ListBookmarks(context.Background())
+
+	m := NewModel(svc, Options{Placeholders: &placeholder.Runner{}})
+	m.setRows(resp.Examples)
+
+	// The provider only runs after confirmation
+	updated, cmd := m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modeFill || cmd != nil {
+		t.Fatalf("Expected confirmation of the provider, got mode %v", m.mode)
+	}
+	if !strings.Contains(m.View(), "printf 'pod/api\\npod/web\\n'") {
+		t.Errorf("Expected the provider command to be shown, got: %s", m.View())
+	}
+
+	updated, cmd = m.handleFillKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(model)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	if !reflect.DeepEqual(m.fillValues, []string{"pod/api", "pod/web"}) {
+		t.Fatalf("Expected the provider's values, got %q (error: %v)", m.fillValues, m.err)
+	}
+
+	updated, _ = m.handleFillKeys(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(model)
+	updated, _ = m.handleFillKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modePreview || m.previewInput.Value() != "kubectl logs -f pod/web" {
+		t.Fatalf("Expected a preview of the filled command, got mode %v with %q", m.mode, m.previewInput.Value())
+	}
+
+	// A last edit before copying
+	updated, _ = m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" --tail 50")})
+	m = updated.(model)
+	updated, _ = m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.selectedCmd != "kubectl logs -f pod/web --tail 50" || m.selectedKey != template {
+		t.Errorf("Expected the edited command to be selected, got %q for %q", m.selectedCmd, m.selectedKey)
+	}
+}
+
+func TestPreviewExpandedCommand(t *testing.T) {
+	t.Setenv("TOOLS_TEST_NS", "dev")
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "kubectl get pods -n $TOOLS_TEST_NS", ToolName: "kubectl", Description: "list pods"},
+		&models.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list containers"},
+	))
+	resp, _ := svc.ListBookmarks(context.Background())
+
+	m := NewModel(svc, Options{ExpandEnv: true})
+	m.setRows(resp.Examples)
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modePreview || m.previewInput.Value() != "kubectl get pods -n dev" {
+		t.Fatalf("Expected a preview of the expanded command, got mode %v with %q", m.mode, m.previewInput.Value())
+	}
+	if !strings.Contains(m.View(), "kubectl get pods -n $TOOLS_TEST_NS") {
+		t.Errorf("Expected the stored command in the preview, got: %s", m.View())
+	}
+
+	// Going back keeps the list, an unchanged command is selected without preview
+	updated, _ = m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(model)
+	if m.mode != modeList || m.selectedCmd != "" {
+		t.Fatalf("Expected to be back in the list, got mode %v", m.mode)
+	}
+	m.table.SetCursor(m.findNextFirstRow(m.table.Cursor()))
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.selectedCmd != "docker ps" {
+		t.Errorf("Expected the command to be selected right away, got mode %v and %q", m.mode, m.selectedCmd)
+	}
+}
+
+func TestPreviewWarnsAboutMissingRequirements(t *testing.T) {
+	t.Setenv("TOOLS_TEST_PROFILE", "")
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "aws s3 ls", ToolName: "aws", Description: "list buckets",
+			Requires: []string{"TOOLS_TEST_PROFILE", "tools-test-missing-binary"}},
+	))
+	resp, _ := svc.ListBookmarks(context.Background())
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modePreview {
+		t.Fatalf("Expected a preview warning about the requirements, got mode %v", m.mode)
+	}
+	if !strings.Contains(m.View(), "missing requirements: TOOLS_TEST_PROFILE, tools-test-missing-binary") {
+		t.Errorf("Expected the missing requirements in the preview, got: %s", m.View())
+	}
+
+	// The command can still be copied
+	updated, _ = m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.selectedCmd != "aws s3 ls" {
+		t.Errorf("Expected the command to be selected, got %q", m.selectedCmd)
+	}
+}
+
+func TestInlineEditDescriptionAndToolName(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "docker ps", ToolName: "dokcer", Description: "list contianers"},
+	))
+	resp, _ := svc.ListBookmarks(context.Background())
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyF2})
+	m = updated.(model)
+	if m.mode != modeInline || m.inlineInput.Value() != "list contianers" {
+		t.Fatalf("Expected to edit the description inline, got mode %v with %q", m.mode, m.inlineInput.Value())
+	}
+	if !strings.Contains(m.View(), "Tools - Command Bookmarks") {
+		t.Errorf("Expected the table to stay visible while editing, got: %s", m.View())
+	}
+
+	m.inlineInput.SetValue("list containers")
+	updated, cmd := m.handleInlineKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modeList || cmd == nil {
+		t.Fatalf("Expected to save and reload, got mode %v (error: %v)", m.mode, m.err)
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+
+	// Tab switches to the tool name, an empty value is rejected
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyF2})
+	m = updated.(model)
+	updated, _ = m.handleInlineKeys(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(model)
+	if m.inlineField != ColumnTool || m.inlineInput.Value() != "dokcer" {
+		t.Fatalf("Expected to edit the tool name, got %s with %q", m.inlineField, m.inlineInput.Value())
+	}
+	m.inlineInput.SetValue(" ")
+	updated, _ = m.handleInlineKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modeInline || m.err == nil {
+		t.Fatalf("Expected an error for an empty tool name, got mode %v", m.mode)
+	}
+	m.inlineInput.SetValue("docker")
+	updated, _ = m.handleInlineKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	bookmark, _ := svc.GetBookmark(context.Background(), "docker ps")
+	if bookmark.ToolName != "docker" || bookmark.Description != "list containers" {
+		t.Errorf("Expected both fixes to be saved, got %q: %q", bookmark.ToolName, bookmark.Description)
+	}
+}
+
+func TestExecuteAndShowLastRun(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "make test", ToolName: "make", Description: "run the tests"},
+	), service.WithAuditLog(audit.NewFileLog(filepath.Join(t.TempDir(), "history.jsonl"))))
+	resp, _ := svc.ListBookmarks(context.Background())
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(model)
+	if !m.execute || m.selectedCmd != "make test" {
+		t.Fatalf("Expected the command to be selected for execution, got %q (execute %v)", m.selectedCmd, m.execute)
+	}
+
+	_ = svc.RecordRunResult(context.Background(), "make test", 2, 1500*time.Millisecond)
+	msg := loadDetail(svc, "make test")()
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	if view := m.detailView(); !strings.Contains(view, "last run:") || !strings.Contains(view, "exit code 2 after 1.5s") {
+		t.Errorf("Expected the last run in the detail view, got: %s", view)
+	}
+}
+
+func TestDueBookmarksAreBadged(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "certbot renew", ToolName: "certbot", Description: "renew certificates", RemindEvery: "30d"},
+		&models.Bookmark{Command: "make test", ToolName: "make", Description: "run the tests"},
+	))
+
+	m := NewModel(svc, Options{})
+	updated, _ := m.Update(loadBookmarks(svc)())
+	m = updated.(model)
+
+	if len(m.tableRows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(m.tableRows))
+	}
+	for _, row := range m.tableRows {
+		due := strings.HasSuffix(row.toolLabel, " (due)")
+		if due != (row.command == "certbot renew") {
+			t.Errorf("Unexpected badge for %s: %q", row.command, row.toolLabel)
+		}
+	}
+}
+
+func TestSelectAndEditOSVariants(t *testing.T) {
+	goos = "darwin"
+	defer func() { goos = runtime.GOOS }()
+
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "xclip -selection clipboard", ToolName: "clipboard", Description: "copy to the clipboard",
+			Variants: map[string]string{"darwin": "pbcopy"}},
+	))
+	m := NewModel(svc, Options{})
+	updated, _ := m.Update(loadBookmarks(svc)())
+	m = updated.(model)
+
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := updated.(model); got.selectedCmd != "pbcopy" || got.selectedKey != "xclip -selection clipboard" {
+		t.Fatalf("Expected the darwin variant to be selected, got %q for %q", got.selectedCmd, got.selectedKey)
+	}
+
+	updated, _ = m.Update(loadDetail(svc, "xclip -selection clipboard")())
+	m = updated.(model)
+	if view := m.detailView(); !strings.Contains(view, "on darwin:") || !strings.Contains(view, "v: OS variants") {
+		t.Errorf("Expected the variants in the detail view, got: %s", view)
+	}
+
+	updated, _ = m.handleDetailKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(model)
+	if m.mode != modeVariants || m.variantInputs[0].Value() != "pbcopy" {
+		t.Fatalf("Expected the variants form with the darwin variant, got mode %v", m.mode)
+	}
+
+	// Clear darwin, set windows
+	m.variantInputs[0].SetValue("")
+	m.variantInputs[2].SetValue("clip")
+	updated, _ = m.handleVariantsKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if err := updated.(model).err; err != nil {
+		t.Fatalf("Failed to save variants: %v", err)
+	}
+
+	bookmark, _ := svc.GetBookmark(context.Background(), "xclip -selection clipboard")
+	if len(bookmark.Variants) != 1 || bookmark.Variants["windows"] != "clip" {
+		t.Errorf("Expected only the windows variant, got %v", bookmark.Variants)
+	}
+}
+
+func TestSelectSkipsOSVariantsOnHost(t *testing.T) {
+	goos = "darwin"
+	defer func() { goos = runtime.GOOS }()
+
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "systemctl restart postgresql", ToolName: "systemctl", Description: "restart the database",
+			Host: "prod-db", Variants: map[string]string{"darwin": "brew services restart postgresql"}},
+	))
+	m := NewModel(svc, Options{})
+	updated, _ := m.Update(loadBookmarks(svc)())
+	m = updated.(model)
+
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := updated.(model); got.selectedCmd != "systemctl restart postgresql" {
+		t.Errorf("Expected the stored command for the host, got %q", got.selectedCmd)
+	}
+}
+
+func TestFillPlaceholdersTrustedAndFailing(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "ssh {host:exit 3}", ToolName: "ssh", Description: "connect"},
+	))
+	resp, _ := svc.ListBookmarks(context.Background())
+
+	m := NewModel(svc, Options{Placeholders: &placeholder.Runner{Trusted: []string{"exit 3"}}})
+	m.setRows(resp.Examples)
+
+	// A trusted provider runs without asking
+	updated, cmd := m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if !m.fillLoading || cmd == nil {
+		t.Fatalf("Expected the trusted provider to run right away")
+	}
+
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	if m.mode != modeList || m.err == nil || !strings.Contains(m.err.Error(), "provider of {host} failed") {
+		t.Errorf("Expected the provider's failure in the list, got mode %v and error %v", m.mode, m.err)
+	}
+	if m.selectedCmd != "" {
+		t.Errorf("Expected nothing to be selected, got %q", m.selectedCmd)
+	}
+}
+
+func TestRunPlainFillPlaceholders(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "git checkout {branch:printf 'main\\ndev\\n'}", ToolName: "git", Description: "switch branch"},
+	))
+	opts := Options{Placeholders: &placeholder.Runner{}}
+
+	var out strings.Builder
+	if err := runPlain(svc, opts, strings.NewReader("1\ny\n2\n\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	output := out.String()
+	if !strings.Contains(output, "1. main\n2. dev\n") || !strings.Contains(output, "Command: git checkout dev\n") {
+		t.Errorf("Expected the provider's values and the filled command, got: %s", output)
+	}
+	if !strings.HasSuffix(output, "type a changed command: git checkout dev\n") {
+		t.Errorf("Expected the filled command, got: %s", output)
+	}
+
+	// A last edit replaces the filled command
+	out.Reset()
+	if err := runPlain(svc, opts, strings.NewReader("1\ny\n2\ngit checkout -b dev-fix\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "type a changed command: git checkout -b dev-fix\n") {
+		t.Errorf("Expected the edited command, got: %s", out.String())
+	}
+
+	// Declining to run the provider selects nothing
+	out.Reset()
+	if err := runPlain(svc, opts, strings.NewReader("1\nn\nq\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	if strings.Contains(out.String(), "git checkout main") {
+		t.Errorf("Expected no selection, got: %s", out.String())
+	}
+}
+
+func TestSelectStarterPacks(t *testing.T) {
+	packs := []*bundle.Bundle{
+		{Metadata: bundle.Metadata{Name: "docker"}},
+		{Metadata: bundle.Metadata{Name: "git"}},
+		{Metadata: bundle.Metadata{Name: "kubectl"}},
+	}
+	press := func(m starterModel, key string) starterModel {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		updated, _ := m.Update(msg)
+		return updated.(starterModel)
+	}
+
+	m := newStarterModel(packs)
+	for _, key := range []string{"down", " ", "down", " ", "enter"} {
+		m = press(m, key)
+	}
+	var names []string
+	for _, b := range m.chosen() {
+		names = append(names, b.Metadata.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"git", "kubectl"}) {
+		t.Errorf("Expected git and kubectl, got %v", names)
+	}
+
+	m = press(press(newStarterModel(packs), "a"), "esc")
+	if chosen := m.chosen(); len(chosen) != 0 {
+		t.Errorf("Expected no packs when skipped, got %d", len(chosen))
+	}
+
+	m = press(press(press(newStarterModel(packs), "a"), "a"), "enter")
+	if chosen := m.chosen(); len(chosen) != 0 {
+		t.Errorf("Expected a second 'a' to deselect all, got %d", len(chosen))
+	}
+	if !strings.Contains(m.View(), "[ ] git") {
+		t.Errorf("Expected unselected packs in the view, got:\n%s", m.View())
+	}
+}
+
+func TestRedactedCommands(t *testing.T) {
+	t.Setenv("TOOLS_TEST_DB", "shop")
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "psql --host=prod-db-1 $TOOLS_TEST_DB", ToolName: "psql", Description: "query shop"},
+	))
+	resp, _ := svc.ListBookmarks(context.Background())
+	redactor, err := secrets.NewRedactor(false, []string{`--host=(\S+)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(svc, Options{Redactor: redactor, ExpandEnv: true})
+	m.setRows(resp.Examples)
+	if view := m.listView(); strings.Contains(view, "prod-db-1") || !strings.Contains(view, "--host=****") {
+		t.Errorf("Expected the host to be redacted in the table, got: %s", view)
+	}
+
+	// The preview can't be edited without revealing the host, enter still selects the real command
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modePreview || !m.previewReadOnly() {
+		t.Fatalf("Expected a read-only preview, got mode %v", m.mode)
+	}
+	if strings.Contains(m.View(), "prod-db-1") {
+		t.Errorf("Expected the host to be redacted in the preview, got: %s", m.View())
+	}
+	updated, _ = m.handlePreviewKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.selectedCmd != "psql --host=prod-db-1 shop" {
+		t.Errorf("Expected the stored host in the selected command, got %q", m.selectedCmd)
+	}
+}
+
+func TestRunPlainConfirmsReveal(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository(
+		&models.Bookmark{Command: "psql --host=prod-db-1 shop", ToolName: "psql", Description: "query shop"},
+	))
+	redactor, _ := secrets.NewRedactor(false, []string{`prod-db-\d+`})
+
+	var out strings.Builder
+	if err := runPlain(svc, Options{Redactor: redactor}, strings.NewReader("1\nn\nq\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	if strings.Contains(out.String(), "prod-db-1") || !strings.Contains(out.String(), "psql --host=**** shop") {
+		t.Errorf("Expected the host to stay redacted, got: %s", out.String())
+	}
+
+	out.Reset()
+	if err := runPlain(svc, Options{Redactor: redactor}, strings.NewReader("1\ny\n"), &out); err != nil {
+		t.Fatalf("Plain mode failed: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "psql --host=prod-db-1 shop\n") {
+		t.Errorf("Expected the revealed command, got: %s", out.String())
+	}
+}
+
+func TestEditChangedElsewhere(t *testing.T) {
+	svc := service.NewBookmarkService(memory.NewMemoryBookmarkRepository())
+	ctx := context.Background()
+	_, _ = svc.CreateBookmark(ctx, dto.CreateBookmarkRequest{Command: "docker ps", ToolName: "docker", Description: "list containers"})
+	resp, _ := svc.ListBookmarks(ctx)
+
+	m := NewModel(svc, Options{})
+	m.setRows(resp.Examples)
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(model)
+
+	// The CLI changes the bookmark while the form is open
+	_, _ = svc.UpdateBookmark(ctx, dto.UpdateBookmarkRequest{Command: "docker ps", NewDescription: "list running containers"})
+
+	m.field(descField).SetValue("containers")
+	updated, _ = m.submitEdit()
+	m = updated.(model)
+	if !errors.Is(m.err, models.ErrConflict) || m.mode != modeEdit {
+		t.Fatalf("Expected the form kept open with a conflict, got %v", m.err)
+	}
+	if current, _ := svc.GetBookmark(ctx, "docker ps"); current.Description != "list running containers" {
+		t.Errorf("Expected the other change kept, got %q", current.Description)
+	}
+
+	// Deleting the row listed before the change fails the same way
+	m.mode = modeList
+	updated, _ = m.submitDelete()
+	m = updated.(model)
+	if !errors.Is(m.err, models.ErrConflict) {
+		t.Errorf("Expected a conflict for the stale row, got %v", m.err)
+	}
+	if _, err := svc.GetBookmark(ctx, "docker ps"); err != nil {
+		t.Errorf("Expected the bookmark kept: %v", err)
+	}
+}
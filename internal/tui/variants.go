@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+)
+
+// goos is the operating system whose variant of a command is selected; replaced in tests
+var goos = runtime.GOOS
+
+// variantFormOS are the operating systems the variants form always offers, in order
+var variantFormOS = []string{"darwin", "linux", "windows"}
+
+// osCommand returns the command to select for the bookmark with the given stored command,
+// its variant for this operating system if it has one and runs here rather than on a host
+func (m model) osCommand(key string) string {
+	for _, example := range m.examples {
+		if example.Command == key {
+			if example.Host != "" {
+				return key
+			}
+			return example.CommandFor(goos)
+		}
+	}
+	return key
+}
+
+// startVariants opens the form editing the OS variants of the bookmark in the detail view
+func (m model) startVariants() (tea.Model, tea.Cmd) {
+	if m.detail == nil || m.detail.Origin != "" {
+		return m, nil
+	}
+
+	m.variantOS = append([]string{}, variantFormOS...)
+	for _, system := range slices.Sorted(maps.Keys(m.detail.Variants)) {
+		if !slices.Contains(m.variantOS, system) {
+			m.variantOS = append(m.variantOS, system)
+		}
+	}
+
+	m.variantInputs = make([]textinput.Model, len(m.variantOS))
+	for i, system := range m.variantOS {
+		input := textinput.New()
+		input.Prompt = "> "
+		input.Placeholder = i18n.T("same as the command")
+		input.Width = 80
+		input.SetValue(m.detail.Variants[system])
+		m.variantInputs[i] = input
+	}
+	m.variantFocus = 0
+	m.mode = modeVariants
+	m.err = nil
+	return m, m.variantInputs[0].Focus()
+}
+
+func (m model) handleVariantsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.mode = modeDetail
+		m.variantInputs = nil
+		m.err = nil
+		return m, nil
+
+	case "enter":
+		return m.submitVariants()
+
+	case "tab", "shift+tab", "up", "down":
+		switch msg.String() {
+		case "up", "shift+tab":
+			m.variantFocus--
+		case "down", "tab":
+			m.variantFocus++
+		}
+		m.variantFocus = (m.variantFocus + len(m.variantInputs)) % len(m.variantInputs)
+
+		cmds := make([]tea.Cmd, len(m.variantInputs))
+		for i := range m.variantInputs {
+			if i == m.variantFocus {
+				cmds[i] = m.variantInputs[i].Focus()
+			} else {
+				m.variantInputs[i].Blur()
+			}
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	var cmd tea.Cmd
+	m.variantInputs[m.variantFocus], cmd = m.variantInputs[m.variantFocus].Update(msg)
+	return m, cmd
+}
+
+// submitVariants saves the variants of the form, empty fields remove a variant
+func (m model) submitVariants() (tea.Model, tea.Cmd) {
+	variants := map[string]string{}
+	for i, system := range m.variantOS {
+		if value := strings.TrimSpace(m.variantInputs[i].Value()); value != "" {
+			variants[system] = value
+		}
+	}
+
+	_, err := m.service.UpdateBookmark(context.Background(), dto.UpdateBookmarkRequest{
+		Command:     m.detail.Command,
+		NewVariants: variants,
+	})
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.variantInputs = nil
+	m.err = nil
+	return m, tea.Batch(loadDetail(m.service, m.detail.Command), loadBookmarks(m.service))
+}
+
+func (m model) variantsView() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(i18n.T("OS Variants")))
+	b.WriteString("\n\n")
+	b.WriteString(itemStyle.Render(fmt.Sprintf("%-13s%s", "command:", summaryLine(m.detail.Command))))
+	b.WriteString("\n\n")
+
+	for i, system := range m.variantOS {
+		label := system + ":"
+		if system == goos {
+			label = i18n.T("%s (this system):", system)
+		}
+		b.WriteString(itemStyle.Render(label))
+		b.WriteString("\n")
+		b.WriteString(itemStyle.Render(m.variantInputs[i].View()))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render(i18n.T("tab/shift+tab: navigate • enter: submit • esc: cancel")))
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(i18n.T("Error: %v", m.err)))
+	}
+
+	return b.String()
+}
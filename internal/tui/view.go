@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// ViewState is the view of the table kept between sessions: its filter, sort and grouping
+// The zero value is the default view.
+type ViewState struct {
+	Filter   string `yaml:"filter,omitempty"`    // Words every shown bookmark contains
+	Sort     string `yaml:"sort,omitempty"`      // Column the table is sorted by, empty for the order of the store
+	SortDesc bool   `yaml:"sort_desc,omitempty"` // Sort descending instead of ascending
+	Group    bool   `yaml:"group,omitempty"`     // Group the bookmarks by tool
+	Tool     string `yaml:"tool,omitempty"`      // Tool selected in the sidebar, empty for all
+}
+
+// LoadViewState reads the view saved at path; a missing file is the default view
+func LoadViewState(path string) (ViewState, error) {
+	var state ViewState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read TUI state: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return ViewState{}, fmt.Errorf("failed to parse TUI state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// SaveViewState writes the view to path, removing the file for the default view
+func SaveViewState(path string, state ViewState) error {
+	if state == (ViewState{}) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to reset TUI state: %w", err)
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode TUI state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create TUI state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write TUI state: %w", err)
+	}
+	return nil
+}
+
+// viewState returns the current view of the table
+func (m model) viewState() ViewState {
+	return ViewState{Filter: m.filter, Sort: m.sortColumn, SortDesc: m.sortDesc, Group: m.grouped, Tool: m.tool}
+}
+
+// restoreView applies a saved view; a sort by a column that is no longer shown is dropped
+func (m *model) restoreView(state ViewState) {
+	m.filter, m.grouped, m.tool = state.Filter, state.Group, state.Tool
+	m.sortColumn, m.sortDesc = "", false
+	for _, c := range m.columns {
+		if c.Name == state.Sort {
+			m.sortColumn, m.sortDesc = state.Sort, state.SortDesc
+		}
+	}
+	m.markSortColumn()
+}
+
+// resetView goes back to the default view: all bookmarks, in the order of the store
+func (m model) resetView() (tea.Model, tea.Cmd) {
+	m.restoreView(ViewState{})
+	m.setRows(m.bookmarks)
+	return m, nil
+}
+
+// toggleGroup groups the bookmarks by tool, or stops grouping them
+func (m model) toggleGroup() (tea.Model, tea.Cmd) {
+	m.grouped = !m.grouped
+	m.setRows(m.bookmarks)
+	return m, nil
+}
+
+// arrange returns the bookmarks the table shows: those of the selected tool matching the
+// filter, sorted, and by tool first when grouped
+func (m model) arrange(examples []dto.BookmarkResponse) []dto.BookmarkResponse {
+	if m.tool != "" {
+		var ofTool []dto.BookmarkResponse
+		for _, example := range examples {
+			if example.ToolName == m.tool {
+				ofTool = append(ofTool, example)
+			}
+		}
+		examples = ofTool
+	}
+	if words := strings.Fields(strings.ToLower(m.filter)); len(words) > 0 {
+		var matching []dto.BookmarkResponse
+		for _, example := range examples {
+			if matchesFilter(example, words) {
+				matching = append(matching, example)
+			}
+		}
+		examples = matching
+	}
+
+	examples = m.sortExamples(examples)
+	if m.grouped {
+		examples = slices.Clone(examples)
+		slices.SortStableFunc(examples, func(a, b dto.BookmarkResponse) int {
+			return strings.Compare(strings.ToLower(a.ToolName), strings.ToLower(b.ToolName))
+		})
+	}
+	return examples
+}
+
+// matchesFilter reports whether a bookmark contains all lowercase words in its tool name,
+// description, command or tags
+func matchesFilter(example dto.BookmarkResponse, words []string) bool {
+	text := strings.ToLower(strings.Join(append([]string{example.ToolName, example.Description, example.Command}, example.Tags...), "\n"))
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// startFilter edits the filter below the table, narrowing the table while typing
+func (m model) startFilter() (tea.Model, tea.Cmd) {
+	m.filterBefore = m.filter
+	m.filterInput = textinput.New()
+	m.filterInput.Prompt = "/ "
+	m.filterInput.Width = 80
+	m.filterInput.SetValue(m.filter)
+	m.mode = modeFilter
+	m.err = nil
+	return m, m.filterInput.Focus()
+}
+
+func (m model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		// Back to the filter before editing
+		m.mode = modeList
+		m.filter = m.filterBefore
+		m.setRows(m.bookmarks)
+		return m, nil
+
+	case "enter":
+		m.mode = modeList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if value := strings.TrimSpace(m.filterInput.Value()); value != m.filter {
+		m.filter = value
+		m.setRows(m.bookmarks)
+	}
+	return m, cmd
+}
+
+// filterView shows the filter being edited below the table
+func (m model) filterView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.filterInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(i18n.T("type to filter • enter: keep • esc: cancel")))
+	return b.String()
+}
+
+// viewStatus describes a view other than the default one, empty for the default
+func (m model) viewStatus() string {
+	var parts []string
+	if m.tool != "" && !m.sidebar {
+		parts = append(parts, i18n.T("tool: %s", m.tool))
+	}
+	if m.filter != "" {
+		parts = append(parts, i18n.T("filter: %s (%d of %d)", m.filter, len(m.examples), len(m.bookmarks)))
+	}
+	if m.grouped {
+		parts = append(parts, i18n.T("grouped by tool"))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, " • ") + " • " + i18n.T("ctrl+l: reset view")
+}
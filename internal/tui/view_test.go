@@ -0,0 +1,165 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fgeck/tools/internal/dto"
+)
+
+var viewBookmarks = []dto.BookmarkResponse{
+	{Command: "kubectl get pods", ToolName: "kubectl", Description: "list pods"},
+	{Command: "docker ps", ToolName: "docker", Description: "list containers", Tags: []string{"local"}},
+	{Command: "kubectl logs -f", ToolName: "kubectl", Description: "follow logs"},
+}
+
+func shownCommands(m model) []string {
+	var commands []string
+	for _, example := range m.examples {
+		commands = append(commands, example.Command)
+	}
+	return commands
+}
+
+func typeKeys(t *testing.T, m model, keys ...tea.KeyMsg) model {
+	t.Helper()
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFilter(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+
+	m = typeKeys(t, m, runes("/"), runes("list"), runes(" "), runes("POD"))
+	if m.mode != modeFilter {
+		t.Fatalf("Expected filter mode, got %v", m.mode)
+	}
+	if got := shownCommands(m); len(got) != 1 || got[0] != "kubectl get pods" {
+		t.Errorf("Expected the table to narrow while typing, got %v", got)
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeList || m.filter != "list POD" {
+		t.Fatalf("Expected enter to keep the filter, got mode %v and %q", m.mode, m.filter)
+	}
+
+	// Tags match too; esc restores the filter before editing
+	m = typeKeys(t, m, runes("/"), tea.KeyMsg{Type: tea.KeyCtrlU}, runes("local"))
+	if got := shownCommands(m); len(got) != 1 || got[0] != "docker ps" {
+		t.Errorf("Expected tags to match, got %v", got)
+	}
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filter != "list POD" || len(m.examples) != 1 || len(m.bookmarks) != 3 {
+		t.Errorf("Expected esc to restore the previous filter, got %q showing %v", m.filter, shownCommands(m))
+	}
+}
+
+func TestGroupByTool(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+
+	m = typeKeys(t, m, runes("t"))
+	if !m.grouped {
+		t.Fatal("Expected t to group the bookmarks")
+	}
+	want := []string{"docker ps", "kubectl get pods", "kubectl logs -f"}
+	if got := shownCommands(m); !slices.Equal(got, want) {
+		t.Errorf("Expected bookmarks grouped by tool %v, got %v", want, got)
+	}
+
+	rows := m.table.Rows()
+	if rows[1][0] != "kubectl" || rows[2][0] != "" {
+		t.Errorf("Expected the tool shown once per group, got %q and %q", rows[1][0], rows[2][0])
+	}
+
+	// The sort applies within the groups
+	m = typeKeys(t, m, runes("3"), runes("3"))
+	want = []string{"docker ps", "kubectl logs -f", "kubectl get pods"}
+	if got := shownCommands(m); !slices.Equal(got, want) {
+		t.Errorf("Expected descending commands within groups %v, got %v", want, got)
+	}
+}
+
+func TestResetView(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+	m = typeKeys(t, m, runes("/"), runes("kubectl"), tea.KeyMsg{Type: tea.KeyEnter}, runes("t"), runes("3"))
+	if m.viewState() == (ViewState{}) {
+		t.Fatal("Expected a changed view")
+	}
+
+	m = typeKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	if m.viewState() != (ViewState{}) {
+		t.Errorf("Expected ctrl+l to reset the view, got %+v", m.viewState())
+	}
+	if got := shownCommands(m); !slices.Equal(got, []string{"kubectl get pods", "docker ps", "kubectl logs -f"}) {
+		t.Errorf("Expected all bookmarks in the order of the store, got %v", got)
+	}
+	if m.table.Columns()[2].Title != "Command" {
+		t.Errorf("Expected the sort indicator to be removed, got %q", m.table.Columns()[2].Title)
+	}
+}
+
+func TestViewStatePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools", "tui-state.yaml")
+
+	state, err := LoadViewState(path)
+	if err != nil || state != (ViewState{}) {
+		t.Fatalf("Expected the default view without a file, got %+v, %v", state, err)
+	}
+
+	saved := ViewState{Filter: "kubectl", Sort: ColumnDescription, SortDesc: true, Group: true}
+	if err := SaveViewState(path, saved); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+	if state, err := LoadViewState(path); err != nil || state != saved {
+		t.Errorf("Expected %+v, got %+v, %v", saved, state, err)
+	}
+
+	m := NewModel(nil, Options{StateFile: path})
+	m.setRows(viewBookmarks)
+	if m.err != nil || m.viewState() != saved {
+		t.Fatalf("Expected the saved view on start, got %+v, %v", m.viewState(), m.err)
+	}
+	if got := shownCommands(m); !slices.Equal(got, []string{"kubectl get pods", "kubectl logs -f"}) {
+		t.Errorf("Expected the restored filter to apply, got %v", got)
+	}
+	if m.table.Columns()[1].Title != "Description"+sortDescending {
+		t.Errorf("Expected the restored sort to be marked, got %q", m.table.Columns()[1].Title)
+	}
+
+	// A sort by a column that is no longer shown is dropped
+	m = NewModel(nil, Options{StateFile: path, Columns: []Column{{Name: ColumnCommand, Weight: 1}}})
+	if m.sortColumn != "" || m.filter != "kubectl" {
+		t.Errorf("Expected only the sort to be dropped, got %+v", m.viewState())
+	}
+
+	// The default view leaves no file behind
+	if err := SaveViewState(path, ViewState{}); err != nil {
+		t.Fatalf("Failed to reset view: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the state file to be removed, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("filter: [unclosed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if m := NewModel(nil, Options{StateFile: path}); m.err == nil || m.viewState() != (ViewState{}) {
+		t.Errorf("Expected a damaged state file to be reported and ignored, got %+v, %v", m.viewState(), m.err)
+	}
+}
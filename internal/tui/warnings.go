@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fgeck/tools/internal/i18n"
+	"github.com/fgeck/tools/internal/parse"
+	"github.com/fgeck/tools/internal/secrets"
+)
+
+// longCommand is the length from which a command is better kept as a script or runbook
+const longCommand = 120
+
+var warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214")) // Orange
+
+// dangerousPatterns are commands that destroy data or run code from the network, with the
+// reason shown when a command matches
+var dangerousPatterns = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`\brm\s+(-\S+\s+)*(-[a-zA-Z]*[rR][a-zA-Z]*|--recursive)\s+(-\S+\s+)*(/|/\*|\*|~|~/|~/\*|\$HOME|\$HOME/|\$HOME/\*)(\s|$|[;&|])`), "removes everything under /, ~ or the current directory"},
+	{regexp.MustCompile(`\bmkfs(\.\w+)?\b`), "formats a file system"},
+	{regexp.MustCompile(`\bdd\b.*\bof=/dev/`), "writes to a device"},
+	{regexp.MustCompile(`>\s*/dev/(sd|nvme|hd|disk)`), "writes to a device"},
+	{regexp.MustCompile(`:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:`), "is a fork bomb"},
+	{regexp.MustCompile(`\bchmod\s+(-\S+\s+)*-R\s+(-\S+\s+)*0?777\s+/(\s|$)`), "makes every file writable by everyone"},
+	{regexp.MustCompile(`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z|da)?sh\b`), "runs a script downloaded from the network"},
+	{regexp.MustCompile(`\bgit\s+push\b.*\s(--force|-f)(\s|$)`), "overwrites the remote history"},
+}
+
+// formWarnings returns what is questionable about the command and tool name of the add or
+// edit form, while typing: nothing keeps the form from being submitted
+func (m model) formWarnings() []string {
+	command := strings.TrimSpace(m.cmdInput.Value())
+	if command == "" {
+		return nil
+	}
+
+	var warnings []string
+	if length := m.cmdInput.Length(); length >= m.cmdInput.CharLimit && m.cmdInput.CharLimit > 0 {
+		warnings = append(warnings, i18n.T("the command reached the limit of %d characters, longer ones are cut", m.cmdInput.CharLimit))
+	} else if length > longCommand {
+		warnings = append(warnings, i18n.T("long command (%d characters), consider a script or a runbook", length))
+	}
+
+	own := ""
+	if m.mode == modeEdit {
+		own = m.originalCmd
+	}
+	if command != own {
+		for _, example := range m.bookmarks {
+			if example.Command == command {
+				warnings = append(warnings, i18n.T("already bookmarked for %s: %s", example.ToolName, summaryLine(example.Description)))
+				break
+			}
+		}
+	}
+
+	tool := strings.TrimSpace(m.field(toolField).Value())
+	if tool == "" && parse.ToolName(command) == "" {
+		warnings = append(warnings, i18n.T("no tool found in the command, enter the tool name"))
+	}
+	if similar := m.similarTool(tool); similar != "" {
+		warnings = append(warnings, i18n.T("no bookmarks for %s yet, did you mean %s?", tool, similar))
+	}
+
+	for _, secret := range secrets.Find(command) {
+		warnings = append(warnings, i18n.T("contains a likely %s, read it from an environment variable instead", secret.Kind))
+	}
+
+	for _, dangerous := range dangerousPatterns {
+		warning := i18n.T("careful, this command %s", i18n.T(dangerous.reason))
+		if dangerous.pattern.MatchString(command) && !slices.Contains(warnings, warning) {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// warningsView renders the warnings of the form, one per line
+func (m model) warningsView() string {
+	var b strings.Builder
+	for _, warning := range m.formWarnings() {
+		b.WriteString(itemStyle.Render(warningStyle.Render("⚠ " + warning)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
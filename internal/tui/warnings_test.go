@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDangerousPatterns(t *testing.T) {
+	tests := []struct {
+		command   string
+		dangerous bool
+	}{
+		{"rm -rf /", true},
+		{"sudo rm -rf / --no-preserve-root", true},
+		{"rm -r -f ~/", true},
+		{"rm -rf *", true},
+		{"rm --recursive $HOME/*", true},
+		{"rm -rf /tmp/build", false},
+		{"rm -rf ./node_modules", false},
+		{"rm ~/notes.txt", false},
+		{"mkfs.ext4 /dev/sdb1", true},
+		{"dd if=ubuntu.iso of=/dev/disk4 bs=4m", true},
+		{"dd if=/dev/zero of=zeros.img bs=1M count=10", false},
+		{":(){ :|:& };:", true},
+		{"chmod -R 777 /", true},
+		{"chmod -R 777 /var/www/uploads", false},
+		{"curl -fsSL https://get.docker.com | sh", true},
+		{"wget -qO- https://example.com/install.sh | sudo bash", true},
+		{"curl -s https://api.github.com | jq .", false},
+		{"git push --force origin main", true},
+		{"git push -f", true},
+		{"git push --force-with-lease", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			m := NewModel(nil, Options{})
+			m.mode = modeAdd
+			m.cmdInput.SetValue(tt.command)
+
+			dangerous := false
+			for _, warning := range m.formWarnings() {
+				dangerous = dangerous || strings.HasPrefix(warning, "careful")
+			}
+			if dangerous != tt.dangerous {
+				t.Errorf("Expected dangerous %v, got warnings %v", tt.dangerous, m.formWarnings())
+			}
+		})
+	}
+}
+
+func TestFormWarnings(t *testing.T) {
+	m := NewModel(nil, Options{})
+	m.setRows(viewBookmarks)
+	m.mode = modeAdd
+
+	if warnings := m.formWarnings(); warnings != nil {
+		t.Errorf("Expected no warnings for an empty form, got %v", warnings)
+	}
+
+	m.cmdInput.SetValue("kubectl get pods")
+	if warnings := m.formWarnings(); len(warnings) != 1 || warnings[0] != "already bookmarked for kubectl: list pods" {
+		t.Errorf("Expected a duplicate warning, got %v", warnings)
+	}
+
+	// Editing a bookmark without changing its command is no duplicate
+	m.mode, m.originalCmd = modeEdit, "kubectl get pods"
+	if warnings := m.formWarnings(); warnings != nil {
+		t.Errorf("Expected no warnings for the edited bookmark itself, got %v", warnings)
+	}
+	m.cmdInput.SetValue("docker ps")
+	if warnings := m.formWarnings(); len(warnings) != 1 || !strings.HasPrefix(warnings[0], "already bookmarked for docker") {
+		t.Errorf("Expected a duplicate warning when editing into another command, got %v", warnings)
+	}
+
+	m.mode = modeAdd
+	m.cmdInput.SetValue("$EDITOR ~/.zshrc")
+	if warnings := m.formWarnings(); len(warnings) != 1 || !strings.HasPrefix(warnings[0], "no tool found") {
+		t.Errorf("Expected a warning about the missing tool, got %v", warnings)
+	}
+	m.field(toolField).SetValue("zsh")
+	if warnings := m.formWarnings(); warnings != nil {
+		t.Errorf("Expected an entered tool name to settle it, got %v", warnings)
+	}
+
+	m.cmdInput.SetValue("echo " + strings.Repeat("x", longCommand))
+	if warnings := m.formWarnings(); len(warnings) != 1 || !strings.HasPrefix(warnings[0], "long command") {
+		t.Errorf("Expected a long command warning, got %v", warnings)
+	}
+	m.cmdInput.SetValue(strings.Repeat("x", commandCharLimit+100))
+	if warnings := m.formWarnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "limit of 4000 characters") {
+		t.Errorf("Expected a warning at the limit of the input, got %v", warnings)
+	}
+
+	m.cmdInput.SetValue("psql postgres://admin:s3cret@db/shop")
+	if warnings := m.formWarnings(); len(warnings) != 1 || warnings[0] != "contains a likely password in a URL, read it from an environment variable instead" {
+		t.Errorf("Expected a secret warning, got %v", warnings)
+	}
+
+	m.cmdInput.SetValue("curl -fsSL https://get.docker.com | sh")
+	if view := m.View(); !strings.Contains(view, "careful, this command runs a script downloaded from the network") {
+		t.Errorf("Expected the warning below the form, got:\n%s", view)
+	}
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// ExpandEnv replaces $VAR and ${VAR} references in a shell command using the given lookup function.
+// Like the shell, nothing inside single quotes is expanded, so snippets such as awk '{print $1}'
+// stay intact; within double quotes a ' is an apostrophe. Only valid variable names are
+// considered; special parameters ($1, $@, $$) are kept as-is. References to undefined
+// variables are left untouched and their names are returned (sorted, without duplicates) so
+// callers can warn about them.
+func ExpandEnv(command string, lookup func(string) (string, bool)) (string, []string) {
+	var b strings.Builder
+	missingSet := map[string]bool{}
+	inSingleQuote, inDoubleQuote := false, false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+
+		switch {
+		case c == '\'' && !inDoubleQuote:
+			inSingleQuote = !inSingleQuote
+			b.WriteByte(c)
+			continue
+		case c == '"' && !inSingleQuote:
+			inDoubleQuote = !inDoubleQuote
+			b.WriteByte(c)
+			continue
+		case c == '\\' && !inSingleQuote && i+1 < len(command):
+			// Escaped characters (e.g. \$HOME) are never expanded
+			b.WriteByte(c)
+			b.WriteByte(command[i+1])
+			i++
+			continue
+		case c != '$' || inSingleQuote:
+			b.WriteByte(c)
+			continue
+		}
+
+		name, end := parseVarRef(command, i)
+		if name == "" {
+			b.WriteByte(c)
+			continue
+		}
+
+		if value, ok := lookup(name); ok {
+			b.WriteString(value)
+		} else {
+			missingSet[name] = true
+			b.WriteString(command[i:end])
+		}
+		i = end - 1
+	}
+
+	missing := make([]string, 0, len(missingSet))
+	for name := range missingSet {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+
+	return b.String(), missing
+}
+
+// ExpandEnvFromOS expands $VAR and ${VAR} references from the current process environment
+func ExpandEnvFromOS(command string) (string, []string) {
+	return ExpandEnv(command, os.LookupEnv)
+}
+
+// parseVarRef parses a variable reference starting at the '$' at index start.
+// Returns the variable name and the index just past the reference, or an empty
+// name if the text at start is not a valid $VAR or ${VAR} reference.
+func parseVarRef(s string, start int) (string, int) {
+	i := start + 1
+	braced := i < len(s) && s[i] == '{'
+	if braced {
+		i++
+	}
+
+	nameStart := i
+	for i < len(s) && isVarNameChar(s[i], i == nameStart) {
+		i++
+	}
+	if i == nameStart {
+		return "", start
+	}
+	name := s[nameStart:i]
+
+	if braced {
+		if i >= len(s) || s[i] != '}' {
+			return "", start
+		}
+		i++
+	}
+
+	return name, i
+}
+
+// isVarNameChar reports whether c may appear in a shell variable name
+func isVarNameChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
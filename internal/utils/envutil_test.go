@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{
+		"HOME":     "/home/user",
+		"NS":       "production",
+		"PORT_NUM": "8080",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		wantMissing []string
+	}{
+		{
+			name:     "no variables",
+			input:    "kubectl get pods",
+			expected: "kubectl get pods",
+		},
+		{
+			name:     "plain variable",
+			input:    "ls $HOME",
+			expected: "ls /home/user",
+		},
+		{
+			name:     "braced variable",
+			input:    "kubectl get pods -n ${NS}",
+			expected: "kubectl get pods -n production",
+		},
+		{
+			name:     "variable followed by text",
+			input:    "lsof -i :${PORT_NUM}/tcp",
+			expected: "lsof -i :8080/tcp",
+		},
+		{
+			name:     "double quotes are expanded",
+			input:    `echo "$HOME"`,
+			expected: `echo "/home/user"`,
+		},
+		{
+			name:     "single quotes are not expanded",
+			input:    "awk '{print $1, $HOME}'",
+			expected: "awk '{print $1, $HOME}'",
+		},
+		{
+			name:     "apostrophe inside double quotes",
+			input:    `echo "don't $HOME" '$HOME'`,
+			expected: `echo "don't /home/user" '$HOME'`,
+		},
+		{
+			name:     "escaped dollar is not expanded",
+			input:    `echo \$HOME`,
+			expected: `echo \$HOME`,
+		},
+		{
+			name:     "special parameters untouched",
+			input:    "echo $1 $@ $$ $?",
+			expected: "echo $1 $@ $$ $?",
+		},
+		{
+			name:        "missing variables are kept and reported",
+			input:       "aws s3 ls --profile $AWS_PROFILE ${AWS_PROFILE} $REGION",
+			expected:    "aws s3 ls --profile $AWS_PROFILE ${AWS_PROFILE} $REGION",
+			wantMissing: []string{"AWS_PROFILE", "REGION"},
+		},
+		{
+			name:     "unterminated brace",
+			input:    "echo ${HOME",
+			expected: "echo ${HOME",
+		},
+		{
+			name:     "trailing dollar",
+			input:    "echo $",
+			expected: "echo $",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, missing := ExpandEnv(tt.input, lookup)
+			if result != tt.expected {
+				t.Errorf("ExpandEnv() = %q, want %q", result, tt.expected)
+			}
+			if len(missing) == 0 && len(tt.wantMissing) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("ExpandEnv() missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
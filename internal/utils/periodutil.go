@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePeriod parses a period given as days (30d), weeks (4w) or a Go duration such as 12h
+func ParsePeriod(period string) (time.Duration, error) {
+	period = strings.TrimSpace(period)
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(period, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				break
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid period '%s', use e.g. 30d, 4w or 12h", period)
+	}
+	return d, nil
+}
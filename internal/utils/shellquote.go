@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Shell is a shell whose quoting rules QuoteFor follows
+type Shell string
+
+const (
+	ShellPOSIX Shell = "sh"   // POSIX sh and its relatives, e.g. dash
+	ShellBash  Shell = "bash" // bash, with ANSI-C quoting
+	ShellZsh   Shell = "zsh"  // zsh, with ANSI-C quoting
+	ShellFish  Shell = "fish" // fish, with backslash escapes inside single quotes
+)
+
+// shellNames maps the executable names of shells to their quoting rules
+var shellNames = map[string]Shell{
+	"sh": ShellPOSIX, "dash": ShellPOSIX, "ash": ShellPOSIX, "ksh": ShellPOSIX,
+	"bash": ShellBash, "zsh": ShellZsh, "fish": ShellFish,
+}
+
+// ParseShell returns the shell of a name or path, e.g. "zsh" or "/usr/bin/fish"
+func ParseShell(name string) (Shell, error) {
+	if shell, ok := shellNames[filepath.Base(name)]; ok {
+		return shell, nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s' (use sh, bash, zsh or fish)", name)
+}
+
+// safeWord matches words that need no quoting in any supported shell
+var safeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// isSafe reports whether s needs no quoting; zsh expands a leading = to the path of a
+// command and fish a leading % to a process ID
+func isSafe(s string) bool {
+	return safeWord.MatchString(s) && s[0] != '=' && s[0] != '%'
+}
+
+// ShellQuote returns s as a single POSIX shell word, single-quoted unless it only contains
+// safe characters. Line breaks are kept inside the quotes, so the result may span several
+// lines; use QuoteFor where it must fit on one.
+func ShellQuote(s string) string {
+	if isSafe(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ShellJoin quotes every argument with ShellQuote and joins them into a command line
+func ShellJoin(args []string) string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		words[i] = ShellQuote(arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// QuoteFor returns s as a single word for the shell that always fits on one line, for
+// crontab entries, alias definitions or a line inserted into the shell's buffer:
+//   - sh single-quotes, and builds strings with control characters like line breaks with
+//     printf, since POSIX shells lack an escape for them inside quotes; trailing line
+//     breaks are dropped by the command substitution
+//   - bash and zsh use ANSI-C quoting ($'...') for strings with control characters
+//   - fish single-quotes, escaping \ and ', and writes control characters as escapes
+//     between quoted parts
+func QuoteFor(shell Shell, s string) string {
+	if isSafe(s) {
+		return s
+	}
+	if !strings.ContainsFunc(s, isControl) {
+		if shell == ShellFish {
+			return fishQuote(s)
+		}
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+
+	switch shell {
+	case ShellBash, ShellZsh:
+		return ansiCQuote(s)
+	case ShellFish:
+		var b strings.Builder
+		start := 0
+		for i, r := range s {
+			if !isControl(r) {
+				continue
+			}
+			if start < i {
+				b.WriteString(fishQuote(s[start:i]))
+			}
+			b.WriteString(controlEscape(r))
+			start = i + 1
+		}
+		if start < len(s) {
+			b.WriteString(fishQuote(s[start:]))
+		}
+		return b.String()
+	default:
+		// printf interprets the escapes of its format, which holds s with only % and \ escaped
+		format := strings.NewReplacer(`\`, `\\`, "%", "%%").Replace(s)
+		var b strings.Builder
+		for _, r := range format {
+			if isControl(r) {
+				b.WriteString(controlEscape(r))
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		format = b.String()
+		// A leading - would be taken for an option
+		if strings.HasPrefix(format, "-") {
+			format = `\055` + format[1:]
+		}
+		return `"$(printf '` + strings.ReplaceAll(format, "'", `'\''`) + `')"`
+	}
+}
+
+// JoinFor quotes every argument with QuoteFor and joins them into a single command line
+func JoinFor(shell Shell, args []string) string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		words[i] = QuoteFor(shell, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// validAliasName matches names every supported shell accepts for an alias
+var validAliasName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// Alias returns the definition of an alias running command, on one line, for the shell
+func Alias(shell Shell, name, command string) (string, error) {
+	if !validAliasName.MatchString(name) {
+		return "", fmt.Errorf("invalid alias name '%s', use letters, digits, _, . and -", name)
+	}
+	if shell == ShellFish {
+		return "alias " + name + " " + QuoteFor(shell, command), nil
+	}
+	return "alias " + name + "=" + QuoteFor(shell, command), nil
+}
+
+// isControl reports whether r is a control character, which breaks a line or a terminal
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// controlEscape returns the backslash escape of a control character that printf, ANSI-C
+// quoting and fish understand
+func controlEscape(r rune) string {
+	switch r {
+	case '\n':
+		return `\n`
+	case '\t':
+		return `\t`
+	case '\r':
+		return `\r`
+	}
+	return fmt.Sprintf(`\%03o`, r)
+}
+
+// ansiCQuote quotes s as $'...' for bash and zsh
+func ansiCQuote(s string) string {
+	var b strings.Builder
+	b.WriteString("$'")
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case isControl(r):
+			b.WriteString(controlEscape(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// fishQuote single-quotes s for fish, where \ and ' are escaped inside single quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"kubectl":           "kubectl",
+		"--namespace=dev":   "--namespace=dev",
+		"":                  "''",
+		"get pods":          "'get pods'",
+		"it's":              `'it'\''s'`,
+		"=ls":               "'=ls'",
+		"$HOME | grep x":    "'$HOME | grep x'",
+		"echo a\necho b":    "'echo a\necho b'",
+		`C:\Program Files`:  `'C:\Program Files'`,
+		"select * from t;":  "'select * from t;'",
+		"%self":             "'%self'",
+		"user@host:/path/x": "user@host:/path/x",
+		"/src/my app":       "'/src/my app'",
+	}
+	for s, expected := range tests {
+		if got := ShellQuote(s); got != expected {
+			t.Errorf("ShellQuote(%q) = %s, want %s", s, got, expected)
+		}
+	}
+
+	if got := ShellJoin([]string{"kubectl", "logs", "-l", "app=web api"}); got != "kubectl logs -l 'app=web api'" {
+		t.Errorf("ShellJoin() = %s", got)
+	}
+}
+
+func TestQuoteFor(t *testing.T) {
+	tests := []struct {
+		shell    Shell
+		s        string
+		expected string
+	}{
+		{ShellPOSIX, "get pods", "'get pods'"},
+		{ShellPOSIX, "echo 'a'\necho 50%", `"$(printf 'echo '\''a'\''\necho 50%%')"`},
+		{ShellPOSIX, "-n\tx", `"$(printf '\055n\tx')"`},
+		{ShellBash, "get pods", "'get pods'"},
+		{ShellBash, "echo 'a'\necho \\n", `$'echo \'a\'\necho \\n'`},
+		{ShellZsh, "printf '\x1b[1m'", `$'printf \'\033[1m\''`},
+		{ShellFish, `it's C:\tmp`, `'it\'s C:\\tmp'`},
+		{ShellFish, "echo a\necho b\n", `'echo a'\n'echo b'\n`},
+		{ShellFish, "kubectl", "kubectl"},
+	}
+	for _, tt := range tests {
+		if got := QuoteFor(tt.shell, tt.s); got != tt.expected {
+			t.Errorf("QuoteFor(%s, %q) = %s, want %s", tt.shell, tt.s, got, tt.expected)
+		}
+	}
+}
+
+// TestQuoteForRoundTrip checks with the installed shells that the quoted words evaluate to
+// the original strings
+func TestQuoteForRoundTrip(t *testing.T) {
+	inputs := []string{
+		"kubectl get pods -A | grep -v Running",
+		"it's \"quoted\" $HOME `date` \\n \\",
+		"for f in *.log; do\n\tgzip \"$f\"\ndone",
+		"-e 'x'\r\n50% done\x1b[0m",
+		"=ls %self ~ * ? [a] {a,b} !! #",
+	}
+	for _, shell := range []Shell{ShellPOSIX, ShellBash, ShellZsh, ShellFish} {
+		path, err := exec.LookPath(string(shell))
+		if err != nil {
+			continue
+		}
+		for _, s := range inputs {
+			out, err := exec.Command(path, "-c", "printf '%s' "+QuoteFor(shell, s)).Output()
+			if err != nil {
+				t.Errorf("%s failed to evaluate the quoted %q: %v", shell, s, err)
+				continue
+			}
+			if string(out) != s {
+				t.Errorf("%s evaluated the quoted %q to %q", shell, s, out)
+			}
+		}
+	}
+}
+
+func TestAlias(t *testing.T) {
+	tests := []struct {
+		shell    Shell
+		expected string
+	}{
+		{ShellPOSIX, `alias ll="$(printf 'ls -la\nls -la '\''x'\''')"`},
+		{ShellZsh, `alias ll=$'ls -la\nls -la \'x\''`},
+		{ShellFish, `alias ll 'ls -la'\n'ls -la \'x\''`},
+	}
+	for _, tt := range tests {
+		got, err := Alias(tt.shell, "ll", "ls -la\nls -la 'x'")
+		if err != nil || got != tt.expected {
+			t.Errorf("Alias(%s) = %s, %v, want %s", tt.shell, got, err, tt.expected)
+		}
+	}
+	if _, err := Alias(ShellBash, "rm -rf", "ls"); err == nil {
+		t.Error("Expected an error for an invalid alias name")
+	}
+}
+
+func TestParseShell(t *testing.T) {
+	for name, expected := range map[string]Shell{"/bin/bash": ShellBash, "zsh": ShellZsh, "/usr/bin/dash": ShellPOSIX, "fish": ShellFish} {
+		if got, err := ParseShell(name); err != nil || got != expected {
+			t.Errorf("ParseShell(%s) = %s, %v, want %s", name, got, err, expected)
+		}
+	}
+	if _, err := ParseShell("tcsh"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
@@ -16,10 +16,12 @@ func WrapText(text string, width int) string {
 }
 
 // WrapToLines wraps text and returns it as a slice of lines.
-// If width is <= 0, returns the original text as a single-element slice.
+// Existing line breaks are kept and tabs are expanded to four spaces, so multi-line
+// scripts stay aligned in tables. If width is <= 0, lines are not wrapped.
 func WrapToLines(text string, width int) []string {
+	text = strings.ReplaceAll(text, "\t", "    ")
 	if width <= 0 {
-		return []string{text}
+		return strings.Split(text, "\n")
 	}
 	wrapped := wordwrap.String(text, width)
 	return strings.Split(wrapped, "\n")
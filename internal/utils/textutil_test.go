@@ -80,6 +80,13 @@ func TestWrapToLines(t *testing.T) {
 			wantMinLines:  1,
 			wantMaxLinLen: 4,
 		},
+		{
+			name:          "multi-line script keeps line breaks",
+			input:         "for f in *.log; do\n\tgzip $f\ndone",
+			width:         0,
+			wantMinLines:  3,
+			wantMaxLinLen: 18,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,204 @@
+// Package bookmarks embeds the tools bookmark store in other Go programs.
+//
+// It exposes the service used by the tools CLI, the repository interface and its
+// implementations, so launchers and plugins can read and modify bookmarks without
+// shelling out to the CLI:
+//
+//	svc, err := bookmarks.OpenDefault()
+//	if err != nil {
+//		return err
+//	}
+//	resp, err := svc.RankBookmarks(ctx, "kill process on port 8080", 5)
+//
+// OpenDefault uses the same configuration file, store and history as the CLI.
+// Use New with any Repository for custom setups, e.g. NewMemoryRepository in tests.
+package bookmarks
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/fgeck/tools/internal/app"
+	"github.com/fgeck/tools/internal/audit"
+	"github.com/fgeck/tools/internal/config"
+	"github.com/fgeck/tools/internal/domain/models"
+	"github.com/fgeck/tools/internal/dto"
+	"github.com/fgeck/tools/internal/repository"
+	"github.com/fgeck/tools/internal/repository/cached"
+	"github.com/fgeck/tools/internal/repository/deadline"
+	"github.com/fgeck/tools/internal/repository/layered"
+	"github.com/fgeck/tools/internal/repository/logged"
+	"github.com/fgeck/tools/internal/repository/memory"
+	"github.com/fgeck/tools/internal/repository/readonly"
+	"github.com/fgeck/tools/internal/repository/yaml"
+	"github.com/fgeck/tools/internal/service"
+)
+
+// Domain types
+type (
+	// Bookmark is a stored command; the command string is its unique key
+	Bookmark = models.Bookmark
+	// Revision is a previous version of a bookmark
+	Revision = models.Revision
+)
+
+// Service and repository types
+type (
+	// Service is the business logic shared by the CLI, TUI and MCP server
+	Service = service.BookmarkService
+	// Option configures optional service behavior
+	Option = service.Option
+	// Repository persists bookmarks
+	Repository = repository.BookmarkRepository
+	// Page is one slice of a paginated listing
+	Page = repository.Page
+	// ToolCount is the number of bookmarks of a tool name
+	ToolCount = repository.ToolCount
+	// Layer is a named read-only repository merged by NewLayeredRepository
+	Layer = layered.Layer
+	// Decorator wraps a repository to add behavior to all its operations
+	Decorator = repository.Decorator
+)
+
+// Request and response types of the Service
+type (
+	CreateRequest           = dto.CreateBookmarkRequest
+	UpdateRequest           = dto.UpdateBookmarkRequest
+	BookmarkResponse        = dto.BookmarkResponse
+	ListResponse            = dto.ListBookmarksResponse
+	RankedBookmark          = dto.RankedBookmark
+	RankedBookmarksResponse = dto.RankedBookmarksResponse
+	RevisionResponse        = dto.RevisionResponse
+	RevisionsResponse       = dto.RevisionsResponse
+	HistoryEntry            = dto.HistoryEntry
+	HistoryResponse         = dto.HistoryResponse
+	RunResult               = dto.RunResult
+	DueBookmark             = dto.DueBookmark
+	DueBookmarksResponse    = dto.DueBookmarksResponse
+	UnusedBookmark          = dto.UnusedBookmark
+	UnusedBookmarksResponse = dto.UnusedBookmarksResponse
+	ActivityCount           = dto.ActivityCount
+	MetricsResponse         = dto.MetricsResponse
+	ImportResult            = dto.ImportResult
+	ImportPreview           = dto.ImportPreview
+	ImportPreviewEntry      = dto.ImportPreviewEntry
+	MergeToolResult         = dto.MergeToolResult
+	ReplaceRequest          = dto.ReplaceRequest
+	CommandChange           = dto.CommandChange
+	ReplaceResult           = dto.ReplaceResult
+	ToolCountResponse       = dto.ToolCountResponse
+	ToolsResponse           = dto.ToolsResponse
+	ConflictStrategy        = dto.ConflictStrategy
+)
+
+// Conflict strategies of Service.ImportBookmarks
+const (
+	ConflictSkip      = dto.ConflictSkip
+	ConflictOverwrite = dto.ConflictOverwrite
+	ConflictFail      = dto.ConflictFail
+)
+
+// Error categories; match them with errors.Is
+var (
+	ErrNotFound      = models.ErrNotFound
+	ErrAlreadyExists = models.ErrAlreadyExists
+	ErrConflict      = models.ErrConflict
+	ErrValidation    = models.ErrValidation
+)
+
+// New creates a service on top of a repository
+func New(repo Repository, opts ...Option) Service {
+	return service.NewBookmarkService(repo, opts...)
+}
+
+// WithHistory records every change and run to a JSON-lines history file
+func WithHistory(filePath string) Option {
+	return service.WithAuditLog(audit.NewFileLog(filePath))
+}
+
+// WithMaxRevisions keeps up to n previous versions of each bookmark on edit
+func WithMaxRevisions(n int) Option {
+	return service.WithMaxRevisions(n)
+}
+
+// WithReviewQueue holds new bookmarks in queue until they are approved
+func WithReviewQueue(queue Repository) Option {
+	return service.WithReviewQueue(queue)
+}
+
+// WithAuthor records name as the author of new bookmarks
+func WithAuthor(name string) Option {
+	return service.WithAuthor(name)
+}
+
+// WithLogger logs changes and failures to record them to logger
+func WithLogger(logger *slog.Logger) Option {
+	return service.WithLogger(logger)
+}
+
+// NewLoggedRepository logs the operations of repo to logger, under the given store name
+func NewLoggedRepository(repo Repository, logger *slog.Logger, name string) Repository {
+	return logged.NewLoggedBookmarkRepository(repo, logger, name)
+}
+
+// Decorate wraps repo in the decorators in order, the first one wrapping repo itself
+func Decorate(repo Repository, decorators ...Decorator) Repository {
+	return repository.Decorate(repo, decorators...)
+}
+
+// NewCachedRepository serves the reads of repo from memory until a write or until ttl
+// passed; a ttl of 0 keeps the cache until the next write
+func NewCachedRepository(repo Repository, ttl time.Duration) Repository {
+	return cached.NewCachedBookmarkRepository(repo, ttl)
+}
+
+// NewDeadlineRepository aborts every operation of repo that takes longer than timeout
+func NewDeadlineRepository(repo Repository, timeout time.Duration) Repository {
+	return deadline.NewDeadlineBookmarkRepository(repo, timeout)
+}
+
+// NewReadOnlyRepository rejects all writes to repo with ErrConflict
+func NewReadOnlyRepository(repo Repository) Repository {
+	return readonly.NewReadOnlyBookmarkRepository(repo)
+}
+
+// NewYAMLRepository opens the YAML store at filePath, creating it if needed
+// A directory is opened as a store of all its YAML files, see the README.
+func NewYAMLRepository(filePath string) (Repository, error) {
+	return yaml.OpenStore(filePath)
+}
+
+// NewReadOnlyYAMLRepository opens a YAML store that is never written
+func NewReadOnlyYAMLRepository(filePath string) Repository {
+	return yaml.NewReadOnlyYAMLBookmarkRepository(filePath)
+}
+
+// NewMemoryRepository creates an in-memory store, optionally seeded with bookmarks
+func NewMemoryRepository(seed ...*Bookmark) Repository {
+	return memory.NewMemoryBookmarkRepository(seed...)
+}
+
+// NewLayeredRepository merges read-only layers into a writable store
+func NewLayeredRepository(user Repository, layers ...Layer) Repository {
+	return layered.NewLayeredBookmarkRepository(user, layers...)
+}
+
+// OpenDefault opens the user's bookmark store the same way the tools CLI does
+// It reads the CLI configuration file and honors all its settings: storage, storage
+// decorators, includes and system layer, history, revisions, hooks, review, team and the
+// auto-export, written right after every change and with the configured redaction. Like the
+// CLI, it restores the backup of a store file left damaged by a crash. Store operations and
+// changes are logged to slog.Default().
+func OpenDefault() (Service, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	a, err := app.Open(cfg, app.Options{Logger: slog.Default(), SyncExport: true})
+	if err != nil {
+		return nil, err
+	}
+	return a.Service, nil
+}
@@ -0,0 +1,226 @@
+//go:build unit
+// +build unit
+
+package bookmarks_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/tools/pkg/bookmarks"
+)
+
+func TestEmbeddedService(t *testing.T) {
+	ctx := context.Background()
+	svc := bookmarks.New(bookmarks.NewMemoryRepository(
+		&bookmarks.Bookmark{Command: "docker ps", ToolName: "docker", Description: "list running containers"},
+	))
+
+	if _, err := svc.CreateBookmark(ctx, bookmarks.CreateRequest{
+		Command:     "lsof -ti :8080 | xargs kill",
+		ToolName:    "lsof",
+		Description: "kill the process listening on port 8080",
+	}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+
+	ranked, err := svc.RankBookmarks(ctx, "kill whatever listens on 8080", 1)
+	if err != nil {
+		t.Fatalf("Failed to rank bookmarks: %v", err)
+	}
+	if ranked.Count != 1 || ranked.Results[0].ToolName != "lsof" {
+		t.Errorf("Expected lsof bookmark, got %+v", ranked.Results)
+	}
+
+	if _, err := svc.GetBookmark(ctx, "missing"); !errors.Is(err, bookmarks.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestYAMLRepositoryWithHistory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := bookmarks.NewYAMLRepository(filepath.Join(dir, "tools.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	svc := bookmarks.New(repo, bookmarks.WithHistory(filepath.Join(dir, "history.jsonl")), bookmarks.WithMaxRevisions(5))
+
+	if _, err := svc.CreateBookmark(ctx, bookmarks.CreateRequest{Command: "ls -la", ToolName: "ls", Description: "list files"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+
+	history, err := svc.GetHistory(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if len(history.Entries) != 1 {
+		t.Errorf("Expected one history entry, got %+v", history.Entries)
+	}
+}
+
+// TestServiceTypesExported calls every Service method with arguments and results typed by
+// this package, so a method using a type without an alias here fails to compile; what the
+// calls return is covered by the tests of the service
+func TestServiceTypesExported(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := bookmarks.NewYAMLRepository(filepath.Join(dir, "tools.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	svc := bookmarks.New(repo, bookmarks.WithHistory(filepath.Join(dir, "history.jsonl")), bookmarks.WithMaxRevisions(5),
+		bookmarks.WithReviewQueue(bookmarks.NewMemoryRepository()))
+	now := time.Now()
+
+	var (
+		created  *bookmarks.BookmarkResponse
+		list     *bookmarks.ListResponse
+		tools    *bookmarks.ToolsResponse
+		ranked   *bookmarks.RankedBookmarksResponse
+		merged   *bookmarks.MergeToolResult
+		replaced *bookmarks.ReplaceResult
+		revs     *bookmarks.RevisionsResponse
+		imported *bookmarks.ImportResult
+		preview  *bookmarks.ImportPreview
+		run      *bookmarks.RunResult
+		due      *bookmarks.DueBookmarksResponse
+		unused   *bookmarks.UnusedBookmarksResponse
+		history  *bookmarks.HistoryResponse
+		metrics  *bookmarks.MetricsResponse
+		names    []string
+	)
+	created, err = svc.CreateBookmark(ctx, bookmarks.CreateRequest{Command: "ls -la", ToolName: "ls", Description: "list files"})
+	if err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	created, _ = svc.GetBookmark(ctx, "ls -la")
+	list, _ = svc.ListBookmarks(ctx)
+	list, _ = svc.ListBookmarksPage(ctx, "", 10)
+	tools, _ = svc.ListTools(ctx)
+	list, _ = svc.SearchBookmarks(ctx, "files")
+	list, _ = svc.MatchCommand(ctx, "ls -la")
+	names, _ = svc.SuggestCommands(ctx, "ls", 5)
+	names, _ = svc.SuggestToolNames(ctx, "l", 5)
+	ranked, _ = svc.RankBookmarks(ctx, "list files", 5)
+	ranked, _ = svc.RelatedBookmarks(ctx, "ls -la", 5)
+	created, _ = svc.UpdateBookmark(ctx, bookmarks.UpdateRequest{Command: "ls -la", NewDescription: "list all files"})
+	merged, _ = svc.MergeTool(ctx, "ls", "coreutils", false)
+	replaced, _ = svc.ReplaceInCommands(ctx, bookmarks.ReplaceRequest{Find: "-la", Replace: "-lah", DryRun: true})
+	revs, _ = svc.ListRevisions(ctx, "ls -la")
+	created, _ = svc.RestoreRevision(ctx, "ls -la", 1)
+	imported, _ = svc.ImportBookmarks(ctx, []bookmarks.CreateRequest{{Command: "pwd", ToolName: "pwd", Description: "directory"}}, bookmarks.ConflictSkip)
+	preview, _ = svc.PreviewImport(ctx, []bookmarks.CreateRequest{{Command: "pwd", ToolName: "pwd", Description: "directory"}}, bookmarks.ConflictOverwrite)
+	_ = svc.Transaction(ctx, func(tx bookmarks.Service) error { return nil })
+	list, _ = svc.ListPending(ctx)
+	created, _ = svc.ApproveBookmark(ctx, "pwd")
+	_ = svc.RejectBookmark(ctx, "pwd")
+	_ = svc.RecordRun(ctx, "ls -la")
+	_ = svc.RecordRunResult(ctx, "ls -la", 0, time.Second)
+	run, _ = svc.LastRun(ctx, "ls -la")
+	due, _ = svc.ListDue(ctx, now)
+	unused, _ = svc.ListUnused(ctx, now)
+	history, _ = svc.GetHistory(ctx, "ls -la")
+	metrics, _ = svc.GetMetrics(ctx, now.Add(-time.Hour), 5)
+	_ = svc.CheckStore(ctx, true)
+	_ = svc.DeleteBookmarkIfUnchanged(ctx, "pwd", now)
+	_ = svc.DeleteToolBookmarks(ctx, "pwd")
+	_ = svc.DeleteBookmark(ctx, "ls -la")
+
+	// The element types of the results
+	_ = bookmarks.RankedBookmarksResponse{Results: []bookmarks.RankedBookmark{}}
+	_ = bookmarks.ToolsResponse{Tools: []bookmarks.ToolCountResponse{}}
+	_ = bookmarks.ReplaceResult{Changes: []bookmarks.CommandChange{}}
+	_ = bookmarks.RevisionsResponse{Revisions: []bookmarks.RevisionResponse{}}
+	_ = bookmarks.ImportPreview{Entries: []bookmarks.ImportPreviewEntry{}}
+	_ = bookmarks.DueBookmarksResponse{Bookmarks: []bookmarks.DueBookmark{}}
+	_ = bookmarks.UnusedBookmarksResponse{Bookmarks: []bookmarks.UnusedBookmark{}}
+	_ = bookmarks.HistoryResponse{Entries: []bookmarks.HistoryEntry{}}
+	_ = bookmarks.MetricsResponse{TopRuns: []bookmarks.ActivityCount{}}
+	_ = bookmarks.ListResponse{Examples: []bookmarks.BookmarkResponse{}}
+	_, _, _, _, _, _, _ = created, list, tools, ranked, merged, replaced, revs
+	_, _, _, _, _, _, _, _ = imported, preview, run, due, unused, history, metrics, names
+}
+
+func TestOpenDefaultIncludes(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TOOLS_CONFIG_DIR", dir)
+	t.Setenv("TOOLS_STORAGE_FILE", "")
+	files := map[string]string{
+		"tools.yaml": "include: [work.yaml, team.yaml]\nbookmarks:\n  - command: git status\n    toolname: git\n    description: mine\n",
+		"work.yaml":  "bookmarks:\n  - command: git status\n    toolname: git\n    description: work\n  - command: make test\n    toolname: make\n    description: work\n",
+		"team.yaml":  "bookmarks:\n  - command: make test\n    toolname: make\n    description: team\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc, err := bookmarks.OpenDefault()
+	if err != nil {
+		t.Fatalf("Failed to open the store: %v", err)
+	}
+	ctx := context.Background()
+
+	// The store overrides its includes, later includes override earlier ones
+	for command, expected := range map[string]string{"git status": "mine", "make test": "team"} {
+		b, err := svc.GetBookmark(ctx, command)
+		if err != nil || b.Description != expected {
+			t.Errorf("Expected %q from %s, got %+v: %v", command, expected, b, err)
+		}
+	}
+
+	if _, err := svc.CreateBookmark(ctx, bookmarks.CreateRequest{Command: "ls -la", ToolName: "ls", Description: "list files"}); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "tools.yaml"))
+	if !strings.Contains(string(data), "ls -la") || !strings.Contains(string(data), "include:") {
+		t.Errorf("Expected the write in the store, keeping its includes, got: %s", data)
+	}
+	for _, name := range []string{"work.yaml", "team.yaml"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != files[name] {
+			t.Errorf("Expected %s to be unchanged, got: %s", name, data)
+		}
+	}
+}
+
+func TestOpenDefaultAutoExport(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TOOLS_CONFIG_DIR", dir)
+	t.Setenv("TOOLS_STORAGE_FILE", "")
+	exportPath := filepath.Join(dir, "cheatsheet.json")
+	config := "auto_export:\n  path: " + exportPath + "\n  format: json\n  delay: 1h\nredaction:\n  patterns: ['--host=(\\S+)']\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err := bookmarks.OpenDefault()
+	if err != nil {
+		t.Fatalf("Failed to open the store: %v", err)
+	}
+	ctx := context.Background()
+	for _, req := range []bookmarks.CreateRequest{
+		{Command: "psql --host=db.internal shop", ToolName: "psql", Description: "query shop"},
+		{Command: "psql scratch", ToolName: "psql", Description: "query scratch", Private: true},
+	} {
+		if _, err := svc.CreateBookmark(ctx, req); err != nil {
+			t.Fatalf("Failed to create bookmark: %v", err)
+		}
+	}
+
+	// The export is written right away, an embedding program has no chance to flush it
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("Expected the auto-export to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "psql --host=**** shop") || strings.Contains(string(data), "scratch") {
+		t.Errorf("Expected the export redacted and without private bookmarks, got: %s", data)
+	}
+}